@@ -0,0 +1,98 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	app *application.Avalanche
+
+	passphraseFile string
+)
+
+// avalanche backup
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up and restore the subnets, keys and snapshots",
+		Long: `The backup command suite archives everything the CLI keeps in its
+directory into a single file, to move it to another machine or to recover it.
+
+A backup holds the genesis and configuration of the subnets, the keys, the
+custom snapshots of the local network and the config file. The private keys
+are encrypted with a passphrase.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// backup create
+	cmd.AddCommand(newCreateCmd())
+	// backup restore
+	cmd.AddCommand(newRestoreCmd())
+	return cmd
+}
+
+func addPassphraseFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "file holding the passphrase of the private keys, instead of prompting for it")
+}
+
+// configFile returns the path of the config file in use, or of the default
+// one
+func configFile() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, constants.DefaultConfigFileName+"."+constants.DefaultConfigFileType), nil
+}
+
+// readPassphrase returns the passphrase from --passphrase-file, or prompts for
+// it, twice if confirm is set
+func readPassphrase(confirm bool) (string, error) {
+	if passphraseFile != "" {
+		content, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return "", exitcodes.UserInput(fmt.Errorf("failed reading the passphrase: %w", err))
+		}
+		passphrase := strings.TrimRight(string(content), "\r\n")
+		if passphrase == "" {
+			return "", exitcodes.UserInput(fmt.Errorf("passphrase file %s is empty", passphraseFile))
+		}
+		return passphrase, nil
+	}
+	passphrase, err := app.Prompt.CapturePassword("Passphrase of the private keys")
+	if err != nil {
+		return "", err
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+	again, err := app.Prompt.CapturePassword("Confirm the passphrase")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", exitcodes.UserInput(errors.New("the passphrases don't match"))
+	}
+	return passphrase, nil
+}
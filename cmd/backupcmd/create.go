@@ -0,0 +1,45 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche backup create
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [backupFile]",
+		Short: "Archive the subnets, keys and snapshots into a file",
+		Long: `The backup create command writes the subnets, keys, custom snapshots and
+config file to the given file.
+
+The private keys are encrypted with a passphrase, prompted for unless given
+with --passphrase-file, which is needed to restore them.`,
+		RunE:         createBackup,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	addPassphraseFlag(cmd)
+	return cmd
+}
+
+func createBackup(cmd *cobra.Command, args []string) error {
+	config, err := configFile()
+	if err != nil {
+		return err
+	}
+	manifest, err := backup.Create(app.GetBaseDir(), config, args[0], func() (string, error) {
+		return readPassphrase(true)
+	})
+	if err != nil {
+		return fmt.Errorf("failed creating backup: %w", err)
+	}
+	ux.Logger.PrintToUser("Backup written to %s, with %d subnet files, %d keys and %d snapshots",
+		args[0], len(manifest.Subnets), len(manifest.Keys), len(manifest.Snapshots))
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var force bool
+
+// avalanche backup restore
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [backupFile]",
+		Short: "Restore the subnets, keys and snapshots of a backup",
+		Long: `The backup restore command puts back the subnets, keys, custom snapshots and
+config file of a backup made with backup create.
+
+Nothing is restored if any of the files already exists, unless --force is
+given to overwrite them, or if the passphrase of the private keys is wrong.`,
+		RunE:         restoreBackup,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	addPassphraseFlag(cmd)
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite existing files")
+	return cmd
+}
+
+func restoreBackup(cmd *cobra.Command, args []string) error {
+	config, err := configFile()
+	if err != nil {
+		return err
+	}
+	manifest, err := backup.Restore(app.GetBaseDir(), config, args[0], func() (string, error) {
+		return readPassphrase(false)
+	}, force)
+	switch {
+	case errors.Is(err, backup.ErrConflict):
+		return exitcodes.UserInput(fmt.Errorf("%w. Use --force to overwrite them", err))
+	case errors.Is(err, backup.ErrWrongPassphrase):
+		return exitcodes.UserInput(err)
+	case err != nil:
+		return fmt.Errorf("failed restoring backup: %w", err)
+	}
+	ux.Logger.PrintToUser("Restored %d subnet files, %d keys and %d snapshots from the backup of %s",
+		len(manifest.Subnets), len(manifest.Keys), len(manifest.Snapshots), manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
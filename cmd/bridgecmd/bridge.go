@@ -0,0 +1,155 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package bridgecmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/bridge"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+const cChainName = "C-Chain"
+
+var app *application.Avalanche
+
+// avalanche bridge
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "bridge",
+		Short: "Bridge the native tokens of a local subnet and the C-Chain",
+		Long: `The bridge command suite prototypes cross-chain dapps on the local network.
+It deploys a bridge contract on the chain of a subnet-evm subnet and on the
+C-Chain, and relays the deposits made in one of them to the other one.
+
+The bridge is meant for local development only: the ewoq key deploys the
+contracts and relays the deposits, and the tokens released on a chain come
+from the liquidity the bridge was funded with on that chain.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// bridge deploy
+	cmd.AddCommand(newDeployCmd())
+	// bridge relay
+	cmd.AddCommand(newRelayCmd())
+	return cmd
+}
+
+// localChains are the chains of a bridge on the local network
+type localChains struct {
+	blockchainID ids.ID
+	subnet       bridge.Chain
+	cChain       bridge.Chain
+	clients      []ethclient.Client
+}
+
+func (c *localChains) Close() {
+	for _, client := range c.clients {
+		client.Close()
+	}
+}
+
+// connectLocalChains connects to the chain of subnetName and to the C-Chain
+// on the local network
+func connectLocalChains(subnetName string) (*localChains, error) {
+	if !app.GenesisExists(subnetName) {
+		return nil, exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	if sc.VM != models.SubnetEvm {
+		return nil, exitcodes.UserInput(fmt.Errorf("subnet %s is a %s subnet, only %s subnets can be bridged", subnetName, sc.VM, models.SubnetEvm))
+	}
+	blockchainID := sc.Networks[app.GetLocalNetworkKey()].BlockchainID
+	if blockchainID == ids.Empty {
+		return nil, exitcodes.UserInput(fmt.Errorf("subnet %s has not been deployed to the local network", subnetName))
+	}
+
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the local network status: %w", err)
+	}
+	clusterInfo := status.GetClusterInfo()
+	if clusterInfo == nil || len(clusterInfo.NodeInfos) == 0 {
+		return nil, exitcodes.UserInput(errors.New("no local network running"))
+	}
+	if _, ok := clusterInfo.CustomVms[blockchainID.String()]; !ok {
+		return nil, exitcodes.UserInput(fmt.Errorf("blockchain %s of subnet %s is not running on the local network", blockchainID, subnetName))
+	}
+	nodeNames := []string{}
+	for name := range clusterInfo.NodeInfos {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	uri := clusterInfo.NodeInfos[nodeNames[0]].GetUri()
+
+	chains := &localChains{blockchainID: blockchainID}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	chains.subnet, err = chains.connect(ctx, subnetName, ux.RPCEndpoint(uri, blockchainID.String()))
+	if err != nil {
+		chains.Close()
+		return nil, err
+	}
+	chains.cChain, err = chains.connect(ctx, cChainName, ux.RPCEndpoint(uri, "C"))
+	if err != nil {
+		chains.Close()
+		return nil, err
+	}
+	return chains, nil
+}
+
+func (c *localChains) connect(ctx context.Context, name, rpcURL string) (bridge.Chain, error) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return bridge.Chain{}, fmt.Errorf("failed connecting to %s at %s: %w", name, rpcURL, err)
+	}
+	c.clients = append(c.clients, client)
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return bridge.Chain{}, fmt.Errorf("failed getting the chain ID of %s at %s: %w", name, rpcURL, err)
+	}
+	return bridge.Chain{Name: name, Backend: client, ChainID: chainID}, nil
+}
+
+// ewoqKey returns the key deploying the bridges and relaying the deposits,
+// funded on the C-Chain of the local network, and which the genesis of
+// subnetName must fund
+func ewoqKey(subnetName string) (*ecdsa.PrivateKey, error) {
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	if account, ok := genesis.Alloc[vm.PrefundedEwoqAddress]; !ok || account.Balance == nil || account.Balance.Sign() == 0 {
+		return nil, exitcodes.UserInput(fmt.Errorf("the genesis of subnet %s does not fund the ewoq address %s, which deploys the bridge and relays the deposits",
+			subnetName, vm.PrefundedEwoqAddress))
+	}
+	return crypto.HexToECDSA(vm.PrefundedEwoqPrivate)
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package bridgecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/bridge"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var liquidity string
+
+// avalanche bridge deploy
+func newDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [subnetName]",
+		Short: "Deploy a bridge between a local subnet and the C-Chain",
+		Long: `The bridge deploy command deploys a bridge contract on the chain of a
+subnet-evm subnet deployed to the local network, and another one on the
+C-Chain, with the ewoq key, which the genesis of the subnet must fund.
+
+Each bridge is funded with --liquidity tokens of its chain, out of which the
+deposits made on the other chain are released. Run bridge relay to relay
+the deposits.
+
+Deploying again replaces the bridge of the subnet.`,
+		RunE:         deployBridge,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&liquidity, "liquidity", "1000", "amount of tokens funding each bridge, released to the recipients of the deposits")
+	return cmd
+}
+
+func deployBridge(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	amount, err := subnet.ParseAVAX(liquidity)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("invalid --liquidity: %w", err))
+	}
+	if amount == 0 {
+		return exitcodes.UserInput(errors.New("the liquidity of the bridges must be positive"))
+	}
+	// the tokens of both chains have 18 decimals, and ParseAVAX returns 9
+	liquidityWei := new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(params.GWei))
+	key, err := ewoqKey(subnetName)
+	if err != nil {
+		return err
+	}
+	chains, err := connectLocalChains(subnetName)
+	if err != nil {
+		return err
+	}
+	defer chains.Close()
+
+	relayer := crypto.PubkeyToAddress(key.PublicKey)
+	deployment := bridge.Deployment{
+		Subnet:       subnetName,
+		BlockchainID: chains.blockchainID.String(),
+		Relayer:      relayer,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	ux.Logger.PrintToUser("Deploying the bridge on %s...", subnetName)
+	deployment.SubnetBridge, err = bridge.Deploy(ctx, chains.subnet, key, relayer, liquidityWei)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Deploying the bridge on the %s...", cChainName)
+	deployment.CChainBridge, err = bridge.Deploy(ctx, chains.cChain, key, relayer, liquidityWei)
+	if err != nil {
+		return err
+	}
+	if err := bridge.WriteDeployment(app.GetBridgePath(subnetName), deployment); err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Bridge deployed, each side funded with %s tokens", liquidity)
+	ux.Logger.PrintToUser("%s bridge: %s", subnetName, deployment.SubnetBridge.Address)
+	ux.Logger.PrintToUser("%s bridge: %s", cChainName, deployment.CChainBridge.Address)
+	ux.Logger.PrintToUser("Call deposit(address recipient) on a bridge with the amount as value, and run")
+	ux.Logger.PrintToUser("  avalanche bridge relay %s", subnetName)
+	ux.Logger.PrintToUser("to release it to the recipient on the other chain")
+	return nil
+}
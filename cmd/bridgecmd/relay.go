@@ -0,0 +1,93 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package bridgecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/bridge"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var relayInterval time.Duration
+
+// avalanche bridge relay
+func newRelayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relay [subnetName]",
+		Short: "Relay the deposits of the bridge of a local subnet",
+		Long: `The bridge relay command watches the bridges deployed with bridge deploy,
+and releases every deposit made on one of them to its recipient on the other
+chain, until interrupted.
+
+The relayer records the blocks it went through, so a stopped relayer picks
+up the deposits made in the meantime when started again. A deposit is never
+released twice.`,
+		RunE:         relay,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().DurationVar(&relayInterval, "interval", 2*time.Second, "how often to look for new deposits")
+	return cmd
+}
+
+func relay(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if relayInterval <= 0 {
+		return exitcodes.UserInput(errors.New("the --interval must be positive"))
+	}
+	deploymentPath := app.GetBridgePath(subnetName)
+	deployment, err := bridge.LoadDeployment(deploymentPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return exitcodes.UserInput(fmt.Errorf("no bridge deployed for subnet %s, deploy one with bridge deploy", subnetName))
+	}
+	if err != nil {
+		return err
+	}
+	key, err := ewoqKey(subnetName)
+	if err != nil {
+		return err
+	}
+	chains, err := connectLocalChains(subnetName)
+	if err != nil {
+		return err
+	}
+	defer chains.Close()
+	if deployment.BlockchainID != chains.blockchainID.String() {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s was deployed again since its bridge was deployed, deploy the bridge again with bridge deploy", subnetName))
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ux.Logger.PrintToUser("Relaying the deposits between %s and the %s, press Ctrl+C to stop...", subnetName, cChainName)
+	relayer := bridge.NewRelayer(chains.subnet, chains.cChain, &deployment, key)
+	var saveErr error
+	relayer.Run(ctx, relayInterval, func(transfers []bridge.Transfer, err error) {
+		for _, t := range transfers {
+			if t.Released {
+				ux.Logger.PrintToUser("Released deposit %s of %s from %s to %s on %s",
+					t.Nonce, ux.FormatAmount(t.Amount, 18), t.Sender, t.Recipient, t.To)
+			} else {
+				ux.Logger.PrintToUser("Deposit %s of %s had already been released on %s", t.Nonce, t.From, t.To)
+			}
+		}
+		if err != nil {
+			ux.Logger.PrintToUser("Failed relaying: %s, retrying", err)
+		}
+		if saveErr = bridge.WriteDeployment(deploymentPath, deployment); saveErr != nil {
+			cancel()
+		}
+	})
+	if saveErr != nil {
+		return saveErr
+	}
+	return bridge.WriteDeployment(deploymentPath, deployment)
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cachecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	avalancheGoVersion string
+	subnetEVMVersion   string
+	manifestPath       string
+)
+
+// avalanche cache warm
+func newWarmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Download the releases local networks need ahead of time",
+		Long: `The cache warm command downloads the releases of avalanchego and subnet-evm
+local networks need into the local cache, for deploys not to download them,
+e.g. to prepare a machine for offline use or to seed the cache of a CI job.
+
+The versions default to the ones pinned by the project, or the ones of this
+tool. Releases already cached are verified, and downloaded again if they are
+not valid. The command prints the manifest of the cached files, with their
+SHA-256, which --manifest also writes as JSON.`,
+		RunE:         warmCache,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&avalancheGoVersion, "avalanchego", "", "avalanchego release version to cache (default the project or CLI version)")
+	cmd.Flags().StringVar(&subnetEVMVersion, "subnet-evm", "", "subnet-evm release version to cache (default the project or CLI version)")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "file to write the manifest of the cached files to, as JSON")
+	return cmd
+}
+
+func warmCache(cmd *cobra.Command, args []string) error {
+	if avalancheGoVersion == "" {
+		avalancheGoVersion, _ = app.Conf.AvalancheGoVersion()
+	}
+	if subnetEVMVersion == "" {
+		subnetEVMVersion, _ = app.Conf.SubnetEVMVersion()
+	}
+	if err := binutils.CheckReleaseVersion(avalancheGoVersion); err != nil {
+		return exitcodes.UserInput(fmt.Errorf("--avalanchego: %w", err))
+	}
+	if err := binutils.CheckReleaseVersion(subnetEVMVersion); err != nil {
+		return exitcodes.UserInput(fmt.Errorf("--subnet-evm: %w", err))
+	}
+	if manifestPath != "" {
+		if err := app.CheckWritable("write " + manifestPath); err != nil {
+			return err
+		}
+	}
+
+	binDir := filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)
+	manifest, err := binutils.WarmCache(app.Log, binDir, avalancheGoVersion, subnetEVMVersion)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Release", "Version", "Path", "Size", "SHA-256"})
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	table.SetRowLine(true)
+	for _, f := range manifest {
+		table.Append([]string{f.Release, f.Version, f.Path, strconv.FormatInt(f.Size, 10), f.SHA256})
+	}
+	table.Render()
+
+	if manifestPath != "" {
+		manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(manifestPath, append(manifestBytes, '\n'), application.WriteReadReadPerms); err != nil {
+			return fmt.Errorf("failed writing the manifest: %w", err)
+		}
+	}
+	ux.Logger.PrintToUser("avalanchego %s and subnet-evm %s are cached in %s", avalancheGoVersion, subnetEVMVersion, binDir)
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package diskcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche disk
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "disk",
+		Short: "Inspect the disk space used by the CLI",
+		Long: `The disk command suite accounts for the space the CLI uses for releases,
+snapshots, local networks and subnets, and for what can be reclaimed.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// disk usage
+	cmd.AddCommand(newUsageCmd())
+	return cmd
+}
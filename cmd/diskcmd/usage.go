@@ -0,0 +1,120 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package diskcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/support"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/shirou/gopsutil/process"
+	"github.com/spf13/cobra"
+)
+
+var usageJSON bool
+
+// avalanche disk usage
+func newUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Break down the disk space used by the CLI",
+		Long: `The disk usage command breaks down the space used by the CLI:
+
+  binaries   the avalanchego and subnet-evm releases installed
+  snapshots  the snapshots of the local network
+  runs       the data and logs of every local network started
+  subnets    the files of every subnet, and the logs of their local chain
+  logs       the logs of the CLI
+
+and prints the commands removing what is no longer in use: the releases of
+other versions than the ones in use, the snapshots saved by network stop,
+and the data of the local networks other than the running one. Nothing is
+removed by the command itself.
+
+The state of the chains is kept in the database of each node, shared by
+all the chains, so the subnets are only accounted for their own files.`,
+		RunE:         printDiskUsage,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&usageJSON, "json", false, "print the disk usage as JSON")
+	return cmd
+}
+
+func printDiskUsage(cmd *cobra.Command, args []string) error {
+	entries, err := support.GetDiskUsage(app, activeRootDataDir())
+	if err != nil {
+		return fmt.Errorf("failed accounting for the disk usage: %w", err)
+	}
+	if usageJSON {
+		entriesBytes, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(entriesBytes))
+		return nil
+	}
+
+	var total, prunable int64
+	categoryTotals := map[string]int64{}
+	categories := []string{}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Category", "Name", "Size", "Path"})
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetRowLine(true)
+	for _, entry := range entries {
+		if _, ok := categoryTotals[entry.Category]; !ok {
+			categories = append(categories, entry.Category)
+		}
+		categoryTotals[entry.Category] += entry.Size
+		total += entry.Size
+		name := entry.Name
+		if entry.Prune != "" {
+			prunable += entry.Size
+			name += " (unused)"
+		}
+		table.Append([]string{entry.Category, name, ux.FormatSize(entry.Size), entry.Path})
+	}
+	table.Render()
+
+	for _, category := range categories {
+		ux.Logger.PrintToUser("%-10s %s", category, ux.FormatSize(categoryTotals[category]))
+	}
+	ux.Logger.PrintToUser("%-10s %s", "total", ux.FormatSize(total))
+	if prunable == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("\n%s can be reclaimed by running:", ux.FormatSize(prunable))
+	for _, entry := range entries {
+		if entry.Prune != "" {
+			ux.Logger.PrintToUser("  %s", entry.Prune)
+		}
+	}
+	return nil
+}
+
+// activeRootDataDir returns the data directory of the running local
+// network, if the backend runs one
+func activeRootDataDir() string {
+	pid, err := binutils.GetServerPID(app)
+	if err != nil {
+		return ""
+	}
+	if running, err := process.PidExists(int32(pid)); err != nil || !running {
+		return ""
+	}
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return ""
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		return ""
+	}
+	return status.GetClusterInfo().GetRootDataDir()
+}
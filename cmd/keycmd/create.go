@@ -3,8 +3,13 @@
 package keycmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"os"
 
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/spf13/cobra"
@@ -17,13 +22,39 @@ const (
 var (
 	forceCreate bool
 	filename    string
+	awsKMSKey   string
+	gcpKMSKey   string
+	encryptKey  bool
 )
 
 func createKey(cmd *cobra.Command, args []string) error {
 	keyName := args[0]
 
 	if app.KeyExists(keyName) && !forceCreate {
-		return errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite")
+		return exitcodes.UserInput(errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite"))
+	}
+	sources := 0
+	for _, source := range []string{filename, awsKMSKey, gcpKMSKey} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return exitcodes.UserInput(errors.New("only one of --file, --aws-kms-key and --gcp-kms-key can be given"))
+	}
+	if encryptKey && (awsKMSKey != "" || gcpKMSKey != "") {
+		return exitcodes.UserInput(errors.New("--encrypt only applies to local keys"))
+	}
+
+	if awsKMSKey != "" || gcpKMSKey != "" {
+		if err := createRemoteKey(keyName); err != nil {
+			return err
+		}
+		return removeKeyFile(app.GetKeyPath(keyName))
+	}
+	// a key is either local or remote
+	if err := removeKeyFile(app.GetRemoteKeyPath(keyName)); err != nil {
+		return err
 	}
 
 	if filename == "" {
@@ -34,7 +65,7 @@ func createKey(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		keyPath := app.GetKeyPath(keyName)
-		if err := k.Save(keyPath); err != nil {
+		if err := saveSoftKey(k, keyName); err != nil {
 			return err
 		}
 		ux.Logger.PrintToUser("Key created")
@@ -42,18 +73,85 @@ func createKey(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		// Load key from file
-		// TODO add validation that key is legal
+		// Load key from file, in any of the encodings key import supports
 		ux.Logger.PrintToUser("Loading user key...")
-		if err := app.CopyKeyFile(filename, keyName); err != nil {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return exitcodes.UserInput(fmt.Errorf("failed reading the key: %w", err))
+		}
+		if err := saveImportedKey(keyName, string(content), ""); err != nil {
 			return err
 		}
-		ux.Logger.PrintToUser("Key loaded")
 	}
 
 	return nil
 }
 
+// saveSoftKey saves k as the local key keyName, encrypted with a passphrase
+// if --encrypt is given
+func saveSoftKey(k *key.SoftKey, keyName string) error {
+	keyPath := app.GetKeyPath(keyName)
+	if !encryptKey {
+		return k.Save(keyPath)
+	}
+	passphrase, err := newPassphrase(keyName)
+	if err != nil {
+		return err
+	}
+	return k.SaveEncrypted(keyPath, passphrase)
+}
+
+// newPassphrase prompts twice for the passphrase to encrypt keyName with
+func newPassphrase(keyName string) (string, error) {
+	passphrase, err := app.Prompt.CapturePassword(fmt.Sprintf("Passphrase to encrypt key %s with", keyName))
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", exitcodes.UserInput(errors.New("the passphrase can't be empty"))
+	}
+	again, err := app.Prompt.CapturePassword("Confirm the passphrase")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", exitcodes.UserInput(errors.New("the passphrases don't match"))
+	}
+	return passphrase, nil
+}
+
+func removeKeyFile(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// createRemoteKey configures keyName to be signed with by a cloud KMS
+func createRemoteKey(keyName string) error {
+	provider, keyID := key.AWSKMSProvider, awsKMSKey
+	if gcpKMSKey != "" {
+		provider, keyID = key.GCPKMSProvider, gcpKMSKey
+	}
+	signer, err := key.NewRemoteSigner(provider, keyID)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Fetching the public key of %s...", keyID)
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	k, err := key.NewRemoteKey(ctx, 0, signer)
+	if err != nil {
+		return err
+	}
+	keyPath := app.GetRemoteKeyPath(keyName)
+	if err := k.Save(keyPath, provider, keyID); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Remote key created")
+	return printAddresses([]string{keyPath})
+}
+
 func newCreateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [keyName]",
@@ -65,7 +163,18 @@ use in production environments. DO NOT use these keys on mainnet.
 The command works by generating a secp256 key and storing it with the provided keyName. You can use this key
 in other commands by providing this keyName.
 
-If you'd like to import and existing key instead of generating one from scatch, provide the --file flag.`,
+If you'd like to import and existing key instead of generating one from scatch, provide the --file flag,
+or use the key import command.
+
+To keep the private key off this machine, the key can instead be held by a cloud KMS, which
+then signs the P-Chain transactions of deployments and validator additions. Provide either
+--aws-kms-key with the ID or ARN of an AWS KMS key of spec ECC_SECG_P256K1, used through the
+aws CLI, or --gcp-kms-key with the resource name of a GCP KMS key version of algorithm
+EC_SIGN_SECP256K1_SHA256, used with the credentials of the gcloud CLI.
+
+With --encrypt, the private key is encrypted with a passphrase. Commands
+signing with the key prompt for it, and the unlocked key is then held in
+memory by a key agent for the following commands, see key unlock.`,
 		Args:         cobra.ExactArgs(1),
 		RunE:         createKey,
 		SilenceUsage: true,
@@ -77,6 +186,24 @@ If you'd like to import and existing key instead of generating one from scatch,
 		"",
 		"import the key from an existing key file",
 	)
+	cmd.Flags().StringVar(
+		&awsKMSKey,
+		"aws-kms-key",
+		"",
+		"use the AWS KMS key with this ID or ARN as a remote signer",
+	)
+	cmd.Flags().StringVar(
+		&gcpKMSKey,
+		"gcp-kms-key",
+		"",
+		"use the GCP KMS key version with this resource name as a remote signer",
+	)
+	cmd.Flags().BoolVar(
+		&encryptKey,
+		"encrypt",
+		false,
+		"encrypt the private key with a passphrase",
+	)
 	cmd.Flags().BoolVarP(
 		&forceCreate,
 		forceFlag,
@@ -19,8 +19,9 @@ func newDeleteCmd() *cobra.Command {
 		Short: "Delete a signing key",
 		Long: `The key delete command deletes an existing signing key.
 
-To delete a key, provide the keyName. The command will prompt for confirmation
-before deleting the key. To skip the confirmation, provide the --force flag.`,
+To delete a key, provide the keyName. The command asks to type the name of
+the key to confirm before deleting it. To skip the confirmation, provide the
+--force or --yes flag.`,
 		RunE:         deleteKey,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
@@ -37,7 +38,7 @@ before deleting the key. To skip the confirmation, provide the --force flag.`,
 
 func deleteKey(cmd *cobra.Command, args []string) error {
 	keyName := args[0]
-	keyPath := app.GetKeyPath(keyName)
+	keyPath := app.GetSigningKeyPath(keyName)
 
 	// Check file exists
 	_, err := os.Stat(keyPath)
@@ -46,16 +47,9 @@ func deleteKey(cmd *cobra.Command, args []string) error {
 	}
 
 	if !forceDelete {
-		confStr := "Are you sure you want to delete " + keyName + "?"
-		conf, err := app.Prompt.CaptureNoYes(confStr)
-		if err != nil {
+		if err := app.Prompt.ConfirmDestructive("This deletes the key "+keyName+".", keyName); err != nil {
 			return err
 		}
-
-		if !conf {
-			ux.Logger.PrintToUser("Delete cancelled")
-			return nil
-		}
 	}
 
 	// exists
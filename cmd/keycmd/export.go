@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +39,9 @@ flag is provided, the key will be written to a file of your choosing.`,
 func exportKey(cmd *cobra.Command, args []string) error {
 	keyName := args[0]
 
+	if app.IsRemoteKey(keyName) {
+		return exitcodes.UserInput(fmt.Errorf("key %s is held by a remote signer, its private key can't be exported", keyName))
+	}
 	keyPath := app.GetKeyPath(keyName)
 	keyBytes, err := os.ReadFile(keyPath)
 	if err != nil {
@@ -49,5 +53,8 @@ func exportKey(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if err := app.CheckWritable("write " + filename); err != nil {
+		return err
+	}
 	return os.WriteFile(filename, keyBytes, application.WriteReadReadPerms)
 }
@@ -0,0 +1,106 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importHex  string
+	importCB58 string
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [keyName]",
+		Short: "Import a private key",
+		Long: `The key import command stores an existing private key under keyName, to use
+it in other commands.
+
+The key can be given in any of the common encodings, which are detected: the
+hex of the key exported by MetaMask, with or without 0x, CB58, or CB58 with
+the PrivateKey- prefix of avalanchego, as in the ewoq key. Provide it with
+--hex or --cb58 to skip the detection, read it from a file with --file, or
+enter it at the prompt, which keeps it out of your shell history. With
+--encrypt, the key is stored encrypted with a passphrase.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         importKey,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&importHex, "hex", "", "private key in hex, with or without 0x")
+	cmd.Flags().StringVar(&importCB58, "cb58", "", "private key in CB58, with or without the PrivateKey- prefix")
+	cmd.Flags().StringVar(&filename, "file", "", "import the key from this file, in any supported encoding")
+	cmd.Flags().BoolVar(&encryptKey, "encrypt", false, "encrypt the private key with a passphrase")
+	cmd.Flags().BoolVarP(&forceCreate, forceFlag, "f", false, "overwrite an existing key with the same name")
+	return cmd
+}
+
+func importKey(cmd *cobra.Command, args []string) error {
+	keyName := args[0]
+
+	if app.KeyExists(keyName) && !forceCreate {
+		return exitcodes.UserInput(errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite"))
+	}
+	sources := 0
+	for _, source := range []string{importHex, importCB58, filename} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return exitcodes.UserInput(errors.New("only one of --hex, --cb58 and --file can be given"))
+	}
+
+	encoded, format := importHex, key.FormatHex
+	switch {
+	case importCB58 != "":
+		encoded, format = importCB58, key.FormatCB58
+	case filename != "":
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return exitcodes.UserInput(fmt.Errorf("failed reading the key: %w", err))
+		}
+		encoded, format = string(content), ""
+	case importHex == "":
+		var err error
+		encoded, err = app.Prompt.CapturePassword("Private key")
+		if err != nil {
+			return err
+		}
+		format = ""
+	}
+	return saveImportedKey(keyName, encoded, format)
+}
+
+// saveImportedKey stores the private key encoded in format, or in any
+// supported format if empty, as the local key keyName
+func saveImportedKey(keyName string, encoded string, format key.Format) error {
+	if err := app.CheckWritable("write key " + keyName); err != nil {
+		return err
+	}
+	privKey, format, err := key.ParsePrivateKey(encoded, format)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	k, err := key.NewSoft(0, key.WithPrivateKey(privKey))
+	if err != nil {
+		return err
+	}
+	// a key is either local or remote
+	if err := removeKeyFile(app.GetRemoteKeyPath(keyName)); err != nil {
+		return err
+	}
+	if err := saveSoftKey(k, keyName); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Key imported from %s", format)
+	return printAddresses([]string{app.GetKeyPath(keyName)})
+}
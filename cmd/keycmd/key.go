@@ -34,6 +34,9 @@ To get started, use the key create command.`,
 	// avalanche key create
 	cmd.AddCommand(newCreateCmd())
 
+	// avalanche key import
+	cmd.AddCommand(newImportCmd())
+
 	// avalanche key list
 	cmd.AddCommand(newListCmd())
 
@@ -43,5 +46,18 @@ To get started, use the key create command.`,
 	// avalanche key export
 	cmd.AddCommand(newExportCmd())
 
+	// avalanche key transfer
+	cmd.AddCommand(newTransferCmd())
+
+	// avalanche key unlock
+	cmd.AddCommand(newUnlockCmd())
+
+	// avalanche key lock
+	cmd.AddCommand(newLockCmd())
+
+	// avalanche key agent, run by the commands unlocking keys as a background
+	// process
+	cmd.AddCommand(newAgentCmd())
+
 	return cmd
 }
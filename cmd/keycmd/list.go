@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -36,7 +37,7 @@ func listKeys(cmd *cobra.Command, args []string) error {
 	keyPaths := make([]string, len(files))
 
 	for i, f := range files {
-		if strings.HasSuffix(f.Name(), constants.KeySuffix) {
+		if strings.HasSuffix(f.Name(), constants.KeySuffix) || strings.HasSuffix(f.Name(), constants.RemoteKeySuffix) {
 			keyPaths[i] = filepath.Join(app.GetKeyDir(), f.Name())
 		}
 	}
@@ -50,8 +51,12 @@ func printAddresses(keyPaths []string) error {
 	table.SetRowLine(true)
 	table.SetAutoMergeCells(true)
 
+	localNetworkID, err := subnet.LocalNetworkID(app)
+	if err != nil {
+		return err
+	}
 	supportedNetworks := map[string]uint32{
-		models.Local.String(): 0,
+		models.Local.String(): localNetworkID,
 		models.Fuji.String():  avago_constants.FujiID,
 		/*
 			Not enabled yet
@@ -61,8 +66,9 @@ func printAddresses(keyPaths []string) error {
 	for _, keyPath := range keyPaths {
 		cAdded := false
 		keyName := strings.TrimSuffix(filepath.Base(keyPath), constants.KeySuffix)
+		keyName = strings.TrimSuffix(keyName, constants.RemoteKeySuffix)
 		for net, id := range supportedNetworks {
-			sk, err := key.LoadSoft(id, keyPath)
+			sk, err := loadKeyAddresses(id, keyPath)
 			if err != nil {
 				return err
 			}
@@ -82,3 +88,12 @@ func printAddresses(keyPaths []string) error {
 	table.Render()
 	return nil
 }
+
+// loadKeyAddresses loads the addresses of a local or remote key, without
+// unlocking it
+func loadKeyAddresses(networkID uint32, keyPath string) (key.Addresser, error) {
+	if strings.HasSuffix(keyPath, constants.RemoteKeySuffix) {
+		return key.LoadRemote(networkID, keyPath)
+	}
+	return key.LoadSoftAddresses(networkID, keyPath)
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	transferFrom    string
+	transferTo      string
+	transferAmount  string
+	transferKey     string
+	transferNetwork string
+)
+
+// avalanche key transfer
+func newTransferCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "Transfer AVAX between chains with a signing key",
+		Long: `The key transfer command moves AVAX owned by a signing key from the C-Chain
+to the P-Chain of the Fuji testnet or of mainnet. Deploying a subnet requires AVAX
+on the P-Chain, while faucets and exchanges usually fund the C-Chain.
+
+The transfer is an atomic swap made of two transactions: an export from the
+C-Chain, whose fee is paid on top of the amount, and an import on the P-Chain,
+whose fee is deducted from the amount.`,
+		Args:         cobra.NoArgs,
+		RunE:         transfer,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&transferFrom, "from", "c", "chain to transfer the funds from [c]")
+	cmd.Flags().StringVar(&transferTo, "to", "p", "chain to transfer the funds to [p]")
+	cmd.Flags().StringVar(&transferAmount, "amount", "", "amount of AVAX to transfer")
+	cmd.Flags().StringVarP(&transferKey, "key", "k", "", "key owning the funds")
+	cmd.Flags().StringVar(&transferNetwork, "network", "fuji", "network to transfer the funds on [fuji, mainnet]")
+	return cmd
+}
+
+func transfer(cmd *cobra.Command, args []string) error {
+	if !strings.EqualFold(transferFrom, "c") || !strings.EqualFold(transferTo, "p") {
+		return exitcodes.UserInput(fmt.Errorf("unsupported transfer from %q to %q, only from c to p is supported", transferFrom, transferTo))
+	}
+	var network models.Network
+	switch strings.ToLower(transferNetwork) {
+	case "fuji":
+		network = models.Fuji
+	case "mainnet":
+		network = models.Mainnet
+	default:
+		return exitcodes.UserInput(fmt.Errorf("invalid --network %q, must be one of fuji, mainnet", transferNetwork))
+	}
+	if transferAmount == "" {
+		return exitcodes.UserInput(errors.New("the amount to transfer must be set with --amount"))
+	}
+	amount, err := subnet.ParseAVAX(transferAmount)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if amount == 0 {
+		return exitcodes.UserInput(errors.New("the amount to transfer must be positive"))
+	}
+	if transferKey == "" {
+		transferKey = app.Conf.BoundKey()
+	}
+	if transferKey == "" {
+		return exitcodes.UserInput(errors.New("the key owning the funds must be set with --key"))
+	}
+	if err := app.CheckProfileBinding(network, transferKey); err != nil {
+		return err
+	}
+	keyName := app.Conf.ResolveKeyAlias(transferKey)
+	if !app.KeyExists(keyName) {
+		return exitcodes.UserInput(fmt.Errorf("key %s does not exist", keyName))
+	}
+
+	ux.Logger.PrintToUser("Transferring %s AVAX from the C-Chain to the P-Chain on %s...", transferAmount, network)
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network)
+	exportTxID, importTxID, err := deployer.TransferCToP(amount)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Export transaction ID: %s", exportTxID)
+	ux.Logger.PrintToUser("Import transaction ID: %s", importTxID)
+	return nil
+}
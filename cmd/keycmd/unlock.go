@@ -0,0 +1,126 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unlockTTL   time.Duration
+	agentSocket string
+)
+
+func newUnlockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock [keyName]",
+		Short: "Unlock an encrypted key for the following commands",
+		Long: `The key unlock command prompts for the passphrase of an encrypted key, and
+hands the unlocked key to the key agent, a background process holding it in
+memory for the following commands, which then don't prompt for it.
+
+The agent forgets the key once the --ttl is over, 15 minutes by default, or
+as set by key-unlock-ttl in the config file. Commands signing with a locked
+key unlock it the same way, set key-unlock-ttl to 0 to prompt for the
+passphrase on every command instead. Use key lock to forget it earlier.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         unlockKey,
+		SilenceUsage: true,
+	}
+	cmd.Flags().DurationVar(&unlockTTL, "ttl", 0, "how long the key stays unlocked (default key-unlock-ttl of the config file, or 15m)")
+	return cmd
+}
+
+func newLockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock [keyName]",
+		Short: "Lock unlocked keys",
+		Long: `The key lock command has the key agent forget the unlocked key keyName,
+or all of them if no key is given, so that the following commands prompt for
+their passphrase again.`,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         lockKey,
+		SilenceUsage: true,
+	}
+}
+
+func newAgentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "agent",
+		Short:  "Run the key agent in the foreground",
+		Args:   cobra.ExactArgs(0),
+		RunE:   runAgent,
+		Hidden: true,
+	}
+	cmd.Flags().StringVar(&agentSocket, "socket", "", "socket to listen on")
+	return cmd
+}
+
+func unlockKey(cmd *cobra.Command, args []string) error {
+	keyName := args[0]
+	if !app.KeyExists(keyName) {
+		return exitcodes.UserInput(fmt.Errorf("key %s does not exist", keyName))
+	}
+	if app.IsRemoteKey(keyName) {
+		return exitcodes.UserInput(fmt.Errorf("key %s is held by a remote signer, it has no passphrase", keyName))
+	}
+	keyPath := app.GetKeyPath(keyName)
+	encrypted, err := key.IsEncrypted(keyPath)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return exitcodes.UserInput(fmt.Errorf("key %s is not encrypted", keyName))
+	}
+	ttl := unlockTTL
+	if !cmd.Flags().Changed("ttl") {
+		ttl = app.Conf.KeyUnlockTTL()
+	}
+	if ttl <= 0 {
+		return exitcodes.UserInput(errors.New("--ttl must be positive"))
+	}
+	if err := key.Unlock(keyPath, ttl); err != nil {
+		if errors.Is(err, key.ErrWrongPassphrase) {
+			return exitcodes.UserInput(err)
+		}
+		return err
+	}
+	ux.Logger.PrintToUser("Key %s unlocked until %s", keyName, time.Now().Add(ttl).Format(time.Kitchen))
+	return nil
+}
+
+func lockKey(cmd *cobra.Command, args []string) error {
+	keyPath := ""
+	if len(args) > 0 {
+		keyPath = app.GetKeyPath(args[0])
+	}
+	if err := key.Lock(keyPath); err != nil && !errors.Is(err, key.ErrAgentNotRunning) {
+		return err
+	}
+	if len(args) > 0 {
+		ux.Logger.PrintToUser("Key %s locked", args[0])
+	} else {
+		ux.Logger.PrintToUser("All keys locked")
+	}
+	return nil
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	if agentSocket == "" {
+		agentSocket = app.GetKeyAgentSocket()
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return key.ServeAgent(ctx, agentSocket)
+}
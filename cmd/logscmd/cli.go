@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package logscmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listLogs    bool
+	printPath   bool
+	invocations int
+
+	errNoPreviousLogs = errors.New("no log file of a previous invocation found")
+)
+
+// avalanche logs cli
+func newCliCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cli",
+		Short: "Print the log of the last invocation",
+		Long: `The logs cli command prints the log file of the last invocation of the
+CLI (not counting this one). Use the --invocation flag to go further back in
+history, or --list to see all the log files still available.`,
+		RunE:         showCliLog,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&listLogs, "list", false, "list the available log files, most recent first")
+	cmd.Flags().BoolVar(&printPath, "path", false, "only print the path of the log file")
+	cmd.Flags().IntVarP(&invocations, "invocation", "n", 1, "how many invocations to go back in history")
+	return cmd
+}
+
+func showCliLog(cmd *cobra.Command, args []string) error {
+	logFiles, err := previousLogFiles()
+	if err != nil {
+		return err
+	}
+
+	if listLogs {
+		for i, f := range logFiles {
+			ux.Logger.PrintToUser("%d. %s", i+1, f)
+		}
+		return nil
+	}
+
+	if invocations < 1 {
+		return fmt.Errorf("invalid invocation %d: must be a positive number", invocations)
+	}
+	if len(logFiles) < invocations {
+		return errNoPreviousLogs
+	}
+	logFile := logFiles[invocations-1]
+	if printPath {
+		fmt.Println(logFile)
+		return nil
+	}
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		return fmt.Errorf("failed reading log file %s: %w", logFile, err)
+	}
+	fmt.Print(string(content))
+	return nil
+}
+
+// previousLogFiles returns all invocation log files except the one of
+// the currently running invocation
+func previousLogFiles() ([]string, error) {
+	logFiles, err := app.GetCliLogFiles()
+	if err != nil {
+		return nil, err
+	}
+	current := filepath.Clean(app.GetLogFile())
+	previous := make([]string, 0, len(logFiles))
+	for _, f := range logFiles {
+		if filepath.Clean(f) != current {
+			previous = append(previous, f)
+		}
+	}
+	return previous, nil
+}
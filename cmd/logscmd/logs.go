@@ -0,0 +1,36 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package logscmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche logs
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the logs of the tool",
+		Long: `The logs command suite provides access to the log files written by the
+tool. Every invocation of the CLI writes its own rotating log file into the
+logs directory of the app dir.
+
+When filing a bug report, please attach the output of the logs cli command.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// logs cli
+	cmd.AddCommand(newCliCmd())
+	return cmd
+}
@@ -3,6 +3,8 @@
 package networkcmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
@@ -16,22 +18,36 @@ func newCleanCmd() *cobra.Command {
 		Short: "Stop the running local network and delete state",
 		Long: `The network clean command shuts down your local, multi-node network. All
 the deployed subnets will shutdown and delete their state. The network
-may be started again by deploying a new subnet configuration.`,
-		Run:  clean,
-		Args: cobra.ExactArgs(0),
+may be started again by deploying a new subnet configuration.
+
+It asks to type the name of the profile of the local network to confirm,
+unless --yes is given.`,
+		RunE:         clean,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
 	}
 }
 
-func clean(cmd *cobra.Command, args []string) {
+func clean(cmd *cobra.Command, args []string) error {
+	if err := app.Prompt.ConfirmDestructive(
+		fmt.Sprintf("This stops the local network of profile %s and deletes the state of all its deployed subnets.", app.GetProfile()),
+		app.GetProfile(),
+	); err != nil {
+		return err
+	}
 	app.Log.Info("killing gRPC server process...")
 
 	if err := subnet.SetDefaultSnapshot(app.GetSnapshotsDir(), true); err != nil {
 		app.Log.Warn("failed resetting default snapshot: %s\n", err)
 	}
+	if err := subnet.ClearUndeployed(app); err != nil {
+		app.Log.Warn("failed forgetting the undeployed blockchains: %s\n", err)
+	}
 
 	if err := binutils.KillgRPCServerProcess(app); err != nil {
 		app.Log.Warn("failed killing server process: %s\n", err)
 	} else {
 		ux.Logger.PrintToUser("Process terminated.")
 	}
+	return nil
 }
@@ -0,0 +1,94 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphFormat string
+	graphOutput string
+)
+
+// avalanche network graph
+func newGraphCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Draw the topology of the local network",
+		Long: `The network graph command draws the nodes of the local network, the subnets
+each of them tracks, and the blockchains of these subnets, with the versions
+of avalanchego and of the VMs the nodes run, to see at a glance how several
+subnets deployed locally are laid out.
+
+The graph is printed as an ASCII tree by default. --format dot exports it in
+the DOT language of Graphviz, and --format mermaid as a mermaid flowchart,
+e.g. to render it in a markdown document:
+
+  avalanche network graph --format dot -o network.dot && dot -Tsvg network.dot > network.svg`,
+		RunE:         networkGraph,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&graphFormat, "format", subnet.GraphFormatText, fmt.Sprintf("format of the graph (%s)", strings.Join(subnet.GraphFormats, ", ")))
+	cmd.Flags().StringVarP(&graphOutput, "output", "o", "", "file to write the graph to (default stdout)")
+	return cmd
+}
+
+func networkGraph(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(graphFormat)
+	known := false
+	for _, f := range subnet.GraphFormats {
+		known = known || f == format
+	}
+	if !known {
+		return exitcodes.UserInput(fmt.Errorf("unknown --format %q, must be one of %s", graphFormat, strings.Join(subnet.GraphFormats, ", ")))
+	}
+
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return err
+	}
+	ctx := binutils.GetAsyncContext()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "not bootstrapped") {
+			return exitcodes.UserInput(fmt.Errorf("no local network running, start it with network start"))
+		}
+		return err
+	}
+	// the blockchains undeployed from the network are still reported by it
+	clusterInfo, err := subnet.FilterUndeployed(app, status.ClusterInfo)
+	if err != nil {
+		return err
+	}
+	if clusterInfo == nil {
+		return exitcodes.UserInput(fmt.Errorf("no local network running, start it with network start"))
+	}
+	graph := subnet.BuildGraph(ctx, clusterInfo, subnet.GetNodeVersion)
+
+	if graphOutput == "" {
+		return graph.Render(os.Stdout, format)
+	}
+	f, err := os.Create(graphOutput)
+	if err != nil {
+		return err
+	}
+	if err := graph.Render(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Graph of the local network written to %s", graphOutput)
+	return nil
+}
@@ -42,5 +42,17 @@ subnets.`,
 	cmd.AddCommand(newCleanCmd())
 	// network status
 	cmd.AddCommand(newStatusCmd())
+	// network wait
+	cmd.AddCommand(newWaitCmd())
+	// network graph
+	cmd.AddCommand(newGraphCmd())
+	// network observability
+	cmd.AddCommand(newObservabilityCmd())
+	// network proxy
+	cmd.AddCommand(newProxyCmd())
+	// network snapshot
+	cmd.AddCommand(newSnapshotCmd())
+	// network profile
+	cmd.AddCommand(newProfileCmd())
 	return cmd
 }
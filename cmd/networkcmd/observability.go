@@ -0,0 +1,120 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/observability"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+var (
+	prometheusPort uint16
+	grafanaPort    uint16
+)
+
+func newObservabilityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "observability",
+		Short: "Manage a Prometheus and Grafana stack for the local network",
+		Long: `The network observability command suite runs Prometheus and Grafana in
+docker, preconfigured to scrape the nodes of the local network, with a
+dashboard of the key metrics of every chain. Docker is required.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network observability start
+	cmd.AddCommand(newObservabilityStartCmd())
+	// network observability stop
+	cmd.AddCommand(newObservabilityStopCmd())
+	return cmd
+}
+
+func newObservabilityStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start Prometheus and Grafana for the local network",
+		Long: `The network observability start command starts Prometheus and Grafana
+containers scraping the nodes of the running local network. If the stack
+is already running, it is restarted with the current nodes, so run it
+again after restarting the network.`,
+
+		RunE:         startObservability,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().Uint16Var(&prometheusPort, "prometheus-port", constants.DefaultPrometheusPort, "port of the Prometheus server")
+	cmd.Flags().Uint16Var(&grafanaPort, "grafana-port", constants.DefaultGrafanaPort, "port of the Grafana server")
+	return cmd
+}
+
+func newObservabilityStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop Prometheus and Grafana for the local network",
+		Long: `The network observability stop command removes the Prometheus and
+Grafana containers of the local network, along with their data.`,
+
+		RunE:         stopObservability,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func newStack() *observability.Stack {
+	return observability.NewStack(app.GetObservabilityDir(), app.GetProfile(), prometheusPort, grafanaPort)
+}
+
+func startObservability(cmd *cobra.Command, args []string) error {
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		// TODO: use error type not string comparison
+		if strings.Contains(err.Error(), "not bootstrapped") {
+			return exitcodes.UserInput(errors.New("no local network running, start it first"))
+		}
+		return err
+	}
+	nodeURIs := map[string]string{}
+	for name, nodeInfo := range status.GetClusterInfo().GetNodeInfos() {
+		nodeURIs[name] = nodeInfo.GetUri()
+	}
+	if len(nodeURIs) == 0 {
+		return exitcodes.UserInput(errors.New("no local network running, start it first"))
+	}
+
+	ux.Logger.PrintToUser("Starting Prometheus and Grafana...")
+	stack := newStack()
+	if err := stack.Start(nodeURIs); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Prometheus: %s", stack.PrometheusURL())
+	ux.Logger.PrintToUser("Grafana:    %s", stack.GrafanaURL())
+	return nil
+}
+
+func stopObservability(cmd *cobra.Command, args []string) error {
+	if err := newStack().Stop(); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Prometheus and Grafana stopped")
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+)
+
+var (
+	cpuProfileDuration time.Duration
+	traceBlocks        uint64
+)
+
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Collect debugging data from the local network",
+		Long: `The network profile command suite collects what is needed to debug the
+performance of the local network, as when it is under load.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network profile collect
+	cmd.AddCommand(newProfileCollectCmd())
+	return cmd
+}
+
+func newProfileCollectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect",
+		Short: "Collect node profiles and traces of failed transactions",
+		Long: `The network profile collect command collects, into a new directory of
+the run directory of the local network:
+
+  nodes/<node>/     the CPU profile of every node over --cpu-duration, and
+                    its memory and lock profiles, to read with go tool pprof
+  chains/<subnet>/  the call trace of every transaction which failed in the
+                    last --blocks blocks of every Subnet-EVM chain
+
+Tracing needs the debug-tracer API enabled in the eth-apis of the chain
+config of the subnet. What can't be collected from a node or a chain is
+reported without stopping the collection of the others.`,
+
+		RunE:         collectProfiles,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().DurationVar(&cpuProfileDuration, "cpu-duration", 10*time.Second, "how long to profile the CPU of the nodes")
+	cmd.Flags().Uint64Var(&traceBlocks, "blocks", 100, "number of last blocks of every chain to trace the failed transactions of")
+	return cmd
+}
+
+func collectProfiles(cmd *cobra.Command, args []string) error {
+	if cpuProfileDuration <= 0 {
+		return exitcodes.UserInput(errors.New("--cpu-duration must be positive"))
+	}
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		// TODO: use error type not string comparison
+		if strings.Contains(err.Error(), "not bootstrapped") {
+			return exitcodes.UserInput(errors.New("no local network running, start it first"))
+		}
+		return err
+	}
+	clusterInfo := status.GetClusterInfo()
+	nodeURIs := map[string]string{}
+	for name, nodeInfo := range clusterInfo.GetNodeInfos() {
+		nodeURIs[name] = nodeInfo.GetUri()
+	}
+	if len(nodeURIs) == 0 {
+		return exitcodes.UserInput(errors.New("no local network running, start it first"))
+	}
+
+	outDir := filepath.Join(app.GetRunDir(), "profiles_"+time.Now().Format("20060102_150405"))
+	ctx := context.Background()
+
+	ux.Logger.PrintToUser("Profiling the nodes for %s...", cpuProfileDuration)
+	failed := false
+	for _, node := range subnet.CollectNodeProfiles(ctx, nodeURIs, filepath.Join(outDir, "nodes"), cpuProfileDuration) {
+		if node.Err != nil {
+			failed = true
+			ux.Logger.PrintToUser("  %s: %s", node.Node, node.Err)
+			continue
+		}
+		ux.Logger.PrintToUser("  %s: %s", node.Node, strings.Join(node.Files, ", "))
+	}
+
+	// any node serves the chains
+	var nodeURI string
+	for _, uri := range nodeURIs {
+		nodeURI = uri
+		break
+	}
+	for _, chain := range evmChains(clusterInfo) {
+		ux.Logger.PrintToUser("Tracing the failed transactions of %s...", chain.name)
+		ctx, cancel := context.WithTimeout(ctx, constants.RequestTimeout)
+		traces, err := subnet.TraceFailedTxs(ctx, ux.RPCEndpoint(nodeURI, chain.blockchainID), traceBlocks, filepath.Join(outDir, "chains", chain.name))
+		cancel()
+		if err != nil {
+			failed = true
+			ux.Logger.PrintToUser("  %s", err)
+			continue
+		}
+		if len(traces) == 0 {
+			ux.Logger.PrintToUser("  no failed transaction in the last %d blocks", traceBlocks)
+		}
+		for _, trace := range traces {
+			if trace.Err != nil {
+				failed = true
+				ux.Logger.PrintToUser("  %s: %s", trace.Hash, trace.Err)
+				continue
+			}
+			ux.Logger.PrintToUser("  %s", trace.Hash)
+		}
+	}
+
+	ux.Logger.PrintToUser("Collected into %s", outDir)
+	if failed {
+		return errors.New("some of the profiles or traces couldn't be collected")
+	}
+	return nil
+}
+
+type evmChain struct {
+	name         string
+	blockchainID string
+}
+
+// evmChains returns the Subnet-EVM chains running on the local network, by
+// subnet name
+func evmChains(clusterInfo *rpcpb.ClusterInfo) []evmChain {
+	chains := []evmChain{}
+	for blockchainID, vmInfo := range clusterInfo.GetCustomVms() {
+		name := subnet.ChainAlias(vmInfo)
+		sc, err := app.LoadSidecar(name)
+		if err != nil || sc.VM != models.SubnetEvm {
+			continue
+		}
+		chains = append(chains, evmChain{name: name, blockchainID: blockchainID})
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].name < chains[j].name
+	})
+	return chains
+}
@@ -0,0 +1,349 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/devenv"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/proxy"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+var (
+	proxyPort   uint16
+	hostsFile   string
+	writeHosts  bool
+	removeHosts bool
+)
+
+func newProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Expose stable RPC URLs for the local network",
+		Long: `The network proxy command suite runs a lightweight reverse proxy in the
+background, exposing every blockchain deployed on the local network under a
+stable URL, which survives network restarts:
+
+  http://localhost:<port>/<subnetName>/rpc
+  ws://localhost:<port>/<subnetName>/ws
+
+Requests are routed to a healthy node, and the routes follow the local
+network as it is restarted or new subnets are deployed.
+
+Once registered in the hosts file with "network proxy hosts", every
+blockchain is also reachable by name:
+
+  http://<subnetName>.avax.local:<port>`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network proxy start
+	cmd.AddCommand(newProxyStartCmd())
+	// network proxy stop
+	cmd.AddCommand(newProxyStopCmd())
+	// network proxy hosts
+	cmd.AddCommand(newProxyHostsCmd())
+	// network proxy serve, run by start as a background process
+	cmd.AddCommand(newProxyServeCmd())
+	return cmd
+}
+
+func newProxyStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the RPC proxy of the local network",
+		Long: `The network proxy start command starts the RPC proxy in the background.
+The proxy keeps running, and following the local network, until stopped
+with "network proxy stop".`,
+
+		RunE:         startProxy,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().Uint16Var(&proxyPort, "port", constants.DefaultProxyPort, "port of the proxy")
+	return cmd
+}
+
+func newProxyStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the RPC proxy of the local network",
+		Long:  `The network proxy stop command stops the RPC proxy.`,
+
+		RunE:         stopProxy,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func newProxyHostsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hosts",
+		Short: "Register the subnet host names of the RPC proxy",
+		Long: `The network proxy hosts command prints the hosts file entries resolving
+<subnetName>` + proxy.DomainSuffix + ` to this machine, for every subnet, so that the
+RPC proxy serves its blockchain at http://<subnetName>` + proxy.DomainSuffix + `:<port>.
+
+With --write, the entries are added to the hosts file, which usually needs
+administrator rights. The entries are kept in a block of their own, updated
+on every run and removed with --remove.`,
+
+		RunE:         proxyHosts,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&writeHosts, "write", false, "add the entries to the hosts file")
+	cmd.Flags().BoolVar(&removeHosts, "remove", false, "remove the entries from the hosts file")
+	cmd.Flags().StringVar(&hostsFile, "hosts-file", constants.HostsFile, "path of the hosts file")
+	return cmd
+}
+
+func newProxyServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "serve",
+		Short:  "Run the RPC proxy of the local network in the foreground",
+		RunE:   serveProxy,
+		Args:   cobra.ExactArgs(0),
+		Hidden: true,
+	}
+	cmd.Flags().Uint16Var(&proxyPort, "port", constants.DefaultProxyPort, "port of the proxy")
+	return cmd
+}
+
+func startProxy(cmd *cobra.Command, args []string) error {
+	ri, running, err := proxy.GetRunInfo(app)
+	if err != nil {
+		return err
+	}
+	if running {
+		return exitcodes.UserInput(fmt.Errorf("the RPC proxy is already running at %s, stop it first", ri.URL()))
+	}
+	// fail early, the proxy process output is not shown
+	ln, err := net.Listen("tcp", proxyListenAddr())
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("port %d is not available: %w", proxyPort, err))
+	}
+	_ = ln.Close()
+	ri, err = proxy.StartProcess(app, proxyPort)
+	if err != nil {
+		return fmt.Errorf("failed starting the RPC proxy: %w", err)
+	}
+	ux.Logger.PrintToUser("RPC proxy started at %s, pid: %d, output at: %s", ux.HostURI(ri.URL()), ri.Pid, ri.OutputFile)
+
+	subnetNames, err := localSubnetNames()
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, name := range subnetNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		ux.Logger.PrintToUser("Stable URLs of the subnets, once deployed locally:")
+	}
+	for _, name := range names {
+		ux.Logger.PrintToUser("%s: %s/%s/rpc (WebSocket: %s/%s/ws)", name, ux.HostURI(ri.URL()), name, ux.HostURI(fmt.Sprintf("ws://localhost:%d", ri.Port)), name)
+	}
+	if len(names) > 0 {
+		ux.Logger.PrintToUser("Run \"network proxy hosts\" to also reach them at http://<subnetName>%s:%d", proxy.DomainSuffix, ri.Port)
+	}
+	return nil
+}
+
+func proxyHosts(cmd *cobra.Command, args []string) error {
+	if writeHosts && removeHosts {
+		return exitcodes.UserInput(errors.New("--write and --remove are mutually exclusive"))
+	}
+	if removeHosts {
+		if err := proxy.UpdateHostsFile(hostsFile, nil); err != nil {
+			return hostsFileError(err)
+		}
+		ux.Logger.PrintToUser("Removed the subnet host names from %s", hostsFile)
+		return nil
+	}
+
+	subnetNames, err := localSubnetNames()
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, name := range subnetNames {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		ux.Logger.PrintToUser("No subnets to register")
+		return nil
+	}
+	if writeHosts && devenv.Detect().InContainer() && !cmd.Flags().Changed("hosts-file") {
+		// the hosts file of the container doesn't apply to the browser
+		// of the host
+		ux.Logger.PrintToUser("Running in a container, whose hosts file does not apply to the host. Add these entries to the hosts file of the host instead:")
+		for _, entry := range proxy.HostsEntries(names) {
+			ux.Logger.PrintToUser(entry)
+		}
+		return nil
+	}
+	if !writeHosts {
+		ux.Logger.PrintToUser("Add these entries to %s, or run this command with --write:", hostsFile)
+		for _, entry := range proxy.HostsEntries(names) {
+			ux.Logger.PrintToUser(entry)
+		}
+		return nil
+	}
+	if err := proxy.UpdateHostsFile(hostsFile, names); err != nil {
+		return hostsFileError(err)
+	}
+	port := constants.DefaultProxyPort
+	if ri, running, err := proxy.GetRunInfo(app); err == nil && running {
+		port = int(ri.Port)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ux.Logger.PrintToUser("%s: http://%s:%d", name, proxy.Hostname(name), port)
+	}
+	return nil
+}
+
+// hostsFileError hints at the permissions usually needed to change the hosts
+// file
+func hostsFileError(err error) error {
+	if errors.Is(err, os.ErrPermission) {
+		return exitcodes.UserInput(fmt.Errorf("%w: run with administrator rights, or edit %s manually", err, hostsFile))
+	}
+	return err
+}
+
+// proxyListenAddr returns the address the proxy listens on, on all the
+// interfaces in a container for its port to be published to the host
+func proxyListenAddr() string {
+	host := "localhost"
+	if devenv.Detect().InContainer() {
+		host = "0.0.0.0"
+	}
+	return fmt.Sprintf("%s:%d", host, proxyPort)
+}
+
+func stopProxy(cmd *cobra.Command, args []string) error {
+	if err := proxy.StopProcess(app); err != nil {
+		if errors.Is(err, proxy.ErrNotRunning) {
+			ux.Logger.PrintToUser("RPC proxy already stopped")
+			return nil
+		}
+		return err
+	}
+	ux.Logger.PrintToUser("RPC proxy stopped")
+	return nil
+}
+
+// localSubnetNames returns the names of the local subnets, by VM ID
+func localSubnetNames() (map[string]string, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	subnetNames := map[string]string{}
+	for _, name := range names {
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			app.Log.Warn("failed loading sidecar of subnet %s: %s", name, err)
+			continue
+		}
+		vmID, err := sc.GetVMID()
+		if err != nil {
+			app.Log.Warn("failed getting VM ID of subnet %s: %s", name, err)
+			continue
+		}
+		subnetNames[vmID.String()] = name
+	}
+	return subnetNames, nil
+}
+
+// refreshProxyTargets routes the proxy to the blockchains of the local
+// network as currently running
+func refreshProxyTargets(ctx context.Context, p *proxy.Proxy) error {
+	subnetNames, err := localSubnetNames()
+	if err != nil {
+		return err
+	}
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		p.SetTargets(map[string]proxy.Target{})
+		return err
+	}
+	defer cli.Close()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		// the network is not running
+		p.SetTargets(map[string]proxy.Target{})
+		return err
+	}
+	clusterInfo, err := subnet.FilterUndeployed(app, status.GetClusterInfo())
+	if err != nil {
+		return err
+	}
+	targets := proxy.BuildTargets(ctx, clusterInfo, subnetNames)
+	if p.SetTargets(targets) {
+		for name, t := range targets {
+			fmt.Printf("routing %s to blockchain %s at %v\n", name, t.BlockchainID, t.NodeURIs)
+		}
+	}
+	return nil
+}
+
+func serveProxy(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	p := proxy.New(app.Log)
+	server := &http.Server{
+		Addr:              proxyListenAddr(),
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- server.ListenAndServe()
+	}()
+	fmt.Printf("proxy listening on %s\n", server.Addr)
+
+	ticker := time.NewTicker(constants.ProxyRefreshInterval)
+	defer ticker.Stop()
+	for {
+		refreshCtx, refreshCancel := context.WithTimeout(ctx, constants.ProxyRefreshInterval)
+		if err := refreshProxyTargets(refreshCtx, p); err != nil {
+			app.Log.Debug("failed refreshing proxy routes: %s", err)
+		}
+		refreshCancel()
+		select {
+		case <-ctx.Done():
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			return server.Shutdown(shutdownCtx)
+		case err := <-errc:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
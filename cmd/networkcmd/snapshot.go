@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/spf13/cobra"
+)
+
+var (
+	numSnapshotSubnets uint32
+	snapshotNetworkID  uint32
+)
+
+// avalanche network snapshot
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage the snapshots of the local network",
+		Long: `The network snapshot command suite manages the snapshots the local network
+is started from.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network snapshot rebuild-default
+	cmd.AddCommand(newRebuildDefaultCmd())
+	return cmd
+}
+
+// avalanche network snapshot rebuild-default
+func newRebuildDefaultCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild-default",
+		Short: "Regenerate the bootstrap snapshot with the local avalanchego",
+		Long: fmt.Sprintf(`The network snapshot rebuild-default command regenerates the bootstrap
+snapshot the local network starts from, instead of the one downloaded on
+first use, which may be stale compared to the avalanchego version in use.
+
+It starts a clean network with the avalanchego version of the project,
+creates the validated subnets deploys use, and saves it as the bootstrap
+snapshot. The default snapshot is reset to it, losing the state of the
+deployed subnets, as with network clean. The local network must be stopped.
+It asks to type the name of the profile of the local network to confirm,
+unless --yes is given.
+
+With --network-id, the network is a standalone one with this network ID
+instead of the default one (%d), e.g. to simulate your own Avalanche-like
+environment. The network ID applies to the local network from then on,
+including the P-Chain addresses of the keys, until the snapshot is rebuilt
+without it. The HRP of the addresses is the one avalanchego derives from
+the network ID, %q for the network IDs it doesn't know.`, constants.LocalNetworkID, avago_constants.FallbackHRP),
+		RunE:         rebuildDefaultSnapshot,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().Uint32Var(&numSnapshotSubnets, "subnets", constants.BootstrapSnapshotSubnets, "number of validated subnets to create for the deploys to use")
+	cmd.Flags().Uint32Var(&snapshotNetworkID, "network-id", 0, "network ID of a standalone local network (default the one of the network runner)")
+	return cmd
+}
+
+func rebuildDefaultSnapshot(cmd *cobra.Command, args []string) error {
+	if numSnapshotSubnets == 0 {
+		return exitcodes.UserInput(errors.New("--subnets must be at least 1"))
+	}
+	if cmd.Flags().Changed("network-id") {
+		if err := subnet.CheckLocalNetworkID(snapshotNetworkID); err != nil {
+			return exitcodes.UserInput(fmt.Errorf("invalid --network-id: %w", err))
+		}
+	}
+	if err := app.Prompt.ConfirmDestructive(
+		fmt.Sprintf("This overwrites the default snapshot of profile %s, losing the state of its deployed subnets.", app.GetProfile()),
+		app.GetProfile(),
+	); err != nil {
+		return err
+	}
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	return deployer.RebuildDefaultSnapshot(numSnapshotSubnets, snapshotNetworkID)
+}
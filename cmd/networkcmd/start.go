@@ -3,21 +3,35 @@
 package networkcmd
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
 	"github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// nodeProfile overrides the node profile of the config file
+	nodeProfile string
+	// startFollowLogs streams the logs of the nodes while they start
+	startFollowLogs bool
+	// startNoConfirm downloads the bootstrap snapshot without asking on a
+	// metered connection
+	startNoConfirm bool
+)
+
 func newStartCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "start [snapshotName]",
 		Short: "Starts a local network",
 		Long: `The network start command starts a local, multi-node Avalanche network
@@ -26,16 +40,42 @@ on your machine.
 By default, the command loads the default snapshot. If "snapshotName"
 is provided, that snapshot will be used for starting the network if
 it can be found. The command may fail if the local network is already
-running.`,
+running.
+
+With --node-profile fast-dev, the nodes run with consensus and networking
+settings tuned for a local network, so that it is healthy in seconds. The
+node-profile key of the config file sets the profile by default.
+
+With --follow-logs, the log lines of the nodes are printed as they are
+written, prefixed with the name of their node, until the network is healthy.
+
+The size of the bootstrap snapshot and how long it takes to download are
+printed before it is downloaded, and how long the network should take to
+bootstrap before it starts. On a metered connection, set with the metered
+key of the config file or AVALANCHE_METERED, the download is confirmed
+first, unless --no-confirm is given.`,
 
 		RunE:         startNetwork,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 	}
+	cmd.Flags().BoolVar(&startFollowLogs, "follow-logs", false, "print the log lines of the nodes until the network is healthy")
+	cmd.Flags().BoolVar(&startNoConfirm, "no-confirm", false, "download the bootstrap snapshot without asking on a metered connection")
+	cmd.Flags().StringVar(&nodeProfile, "node-profile", "", "node profile of the local network, one of "+strings.Join(config.NodeProfileNames(), ", "))
+	return cmd
 }
 
 func startNetwork(cmd *cobra.Command, args []string) error {
+	if nodeProfile != "" {
+		app.Conf.SetNodeProfile(nodeProfile)
+	}
+	if err := app.Conf.CheckNodeProfile(); err != nil {
+		return exitcodes.UserInput(err)
+	}
+
 	sd := subnet.NewLocalSubnetDeployer(app)
+	sd.SetFollowLogs(startFollowLogs)
+	sd.SetNoConfirm(startNoConfirm)
 
 	if err := sd.StartServer(); err != nil {
 		return err
@@ -45,8 +85,11 @@ func startNetwork(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := subnet.CheckPluginsProtocol(app.Log, avalancheGoBinPath, pluginDir); err != nil {
+		return err
+	}
 
-	cli, err := binutils.NewGRPCClient()
+	cli, err := binutils.NewGRPCClient(app)
 	if err != nil {
 		return err
 	}
@@ -62,7 +105,23 @@ func startNetwork(cmd *cobra.Command, args []string) error {
 
 	ctx := binutils.GetAsyncContext()
 
+	// the nodes would crash loop on a snapshot they can't load
+	_, err = cli.Status(ctx)
+	// TODO: use error type not string comparison
+	if err != nil && strings.Contains(err.Error(), "not bootstrapped") {
+		if snapshotName == constants.DefaultSnapshotName {
+			if err := sd.RepairDefaultSnapshot(); err != nil {
+				return err
+			}
+		} else if err := subnet.CheckSnapshotCompatibility(app, snapshotName); errors.Is(err, subnet.ErrIncompatibleSnapshot) {
+			return exitcodes.UserInput(err)
+		} else if err != nil {
+			return err
+		}
+	}
+
 	ux.Logger.PrintToUser(startMsg)
+	sd.PrintExpectedBootstrap()
 
 	outputDirPrefix := path.Join(app.GetRunDir(), "restart")
 	outputDir, err := utils.MkDirWithTimestamp(outputDirPrefix)
@@ -76,36 +135,59 @@ func startNetwork(cmd *cobra.Command, args []string) error {
 		client.WithRootDataDir(outputDir),
 	}
 
-	// load global node configs if they exist
-	configStr, err := app.Conf.LoadNodeConfig()
+	// load the node configs if they exist
+	nodeConfigOpts, err := subnet.LocalNodeConfigOptions(app, snapshotName)
 	if err != nil {
 		return err
 	}
-	if configStr != "" {
-		loadSnapshotOpts = append(loadSnapshotOpts, client.WithGlobalNodeConfig(configStr))
+	loadSnapshotOpts = append(loadSnapshotOpts, nodeConfigOpts...)
+	chainConfigs, err := subnet.LocalChainConfigs(app)
+	if err != nil {
+		return err
+	}
+	if len(chainConfigs) > 0 {
+		loadSnapshotOpts = append(loadSnapshotOpts, client.WithChainConfigs(chainConfigs))
 	}
 
-	_, err = cli.LoadSnapshot(
-		ctx,
-		snapshotName,
-		loadSnapshotOpts...,
-	)
+	var clusterInfo *rpcpb.ClusterInfo
+	err = sd.StreamNodeLogs(outputDir, func() error {
+		_, err := cli.LoadSnapshot(
+			ctx,
+			snapshotName,
+			loadSnapshotOpts...,
+		)
+
+		if err != nil {
+			// TODO: use error type not string comparison
+			if !strings.Contains(err.Error(), "already bootstrapped") {
+				return fmt.Errorf("failed to start network with the persisted snapshot: %s", err)
+			}
+			ux.Logger.PrintToUser("Network has already been booted. Wait until healthy...")
+		} else {
+			ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
+		}
 
-	if err != nil {
-		// TODO: use error type not string comparison
-		if !strings.Contains(err.Error(), "already bootstrapped") {
-			return fmt.Errorf("failed to start network with the persisted snapshot: %s", err)
+		// TODO: this should probably be extracted from the deployer and
+		// used as an independent helper
+		clusterInfo, err = sd.WaitForHealthy(ctx, cli, constants.HealthCheckInterval)
+		if err != nil {
+			return fmt.Errorf("failed waiting for network to become healthy: %w", err)
 		}
-		ux.Logger.PrintToUser("Network has already been booted. Wait until healthy...")
-	} else {
-		ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if clusterInfo, err = sd.ApplyNodeVersions(ctx, cli, clusterInfo, avalancheGoBinPath, pluginDir); err != nil {
+		return err
+	}
+	if clusterInfo, err = subnet.FilterUndeployed(app, clusterInfo); err != nil {
+		return err
 	}
 
-	// TODO: this should probably be extracted from the deployer and
-	// used as an independent helper
-	clusterInfo, err := sd.WaitForHealthy(ctx, cli, constants.HealthCheckInterval)
-	if err != nil {
-		return fmt.Errorf("failed waiting for network to become healthy: %s", err)
+	// the aliases of the blockchains are lost when the nodes restart
+	if err := subnet.AliasChains(ctx, clusterInfo); err != nil {
+		ux.Logger.PrintToUser("Warning: the endpoints are only available by blockchain ID: %s", err)
 	}
 
 	endpoints := subnet.GetEndpoints(clusterInfo)
@@ -3,35 +3,66 @@
 package networkcmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/info"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
 )
 
+var statusWatch time.Duration
+
 func newStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Prints the status of the local network",
 		Long: `The network status command prints whether or not a local Avalanche
-network is running and some basic stats about the network.`,
+network is running and some basic stats about the network.
+
+With --watch, the status is refreshed in place until interrupted.`,
 
 		RunE:         networkStatus,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
 	}
+	ux.AddWatchFlag(cmd.Flags(), &statusWatch)
+	return cmd
 }
 
 func networkStatus(cmd *cobra.Command, args []string) error {
 	ux.Logger.PrintToUser("Requesting network status...")
 
-	cli, err := binutils.NewGRPCClient()
+	cli, err := binutils.NewGRPCClient(app)
 	if err != nil {
 		return err
 	}
 
+	if statusWatch <= 0 {
+		return printNetworkStatus(cli)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return ux.Watch(ctx, statusWatch, func() error {
+		return printNetworkStatus(cli)
+	})
+}
+
+// printNetworkStatus prints the status of the local network
+func printNetworkStatus(cli client.Client) error {
 	ctx := binutils.GetAsyncContext()
 	status, err := cli.Status(ctx)
 	if err != nil {
@@ -42,25 +73,44 @@ func networkStatus(cmd *cobra.Command, args []string) error {
 		}
 		return err
 	}
+	// the blockchains undeployed from the network are still reported by it
+	if status.ClusterInfo, err = subnet.FilterUndeployed(app, status.ClusterInfo); err != nil {
+		return err
+	}
 
 	// TODO: This layout may break some screens, is there a "failsafe" way?
 	if status != nil && status.ClusterInfo != nil {
 		ux.Logger.PrintToUser("Network is Up. Network information:")
 		ux.Logger.PrintToUser("==================================================================================================")
-		ux.Logger.PrintToUser("Healthy: %t", status.ClusterInfo.Healthy)
-		ux.Logger.PrintToUser("Custom VMs healthy: %t", status.ClusterInfo.CustomVmsHealthy)
+		ux.Logger.PrintToUser("Profile: %s", app.GetProfile())
+		printNetworkID(status.ClusterInfo)
+		health := subnet.CheckClusterHealth(ctx, status.ClusterInfo, subnet.NewNodeHealthClient)
+		ux.Logger.PrintToUser("Health: %s", describeLiveness(health.Liveness, health.Reason))
 		ux.Logger.PrintToUser("Number of nodes: %d", len(status.ClusterInfo.NodeNames))
 		ux.Logger.PrintToUser("Number of custom VMs: %d", len(status.ClusterInfo.CustomVms))
 		ux.Logger.PrintToUser("======================================== Node information ========================================")
-		for n, nodeInfo := range status.ClusterInfo.NodeInfos {
-			ux.Logger.PrintToUser("%s has ID %s and endpoint %s: ", n, nodeInfo.Id, nodeInfo.Uri)
+		// the nodes are sorted, for the refreshes of --watch to keep the order
+		for _, node := range health.Nodes {
+			nodeInfo := status.ClusterInfo.NodeInfos[node.Name]
+			ux.Logger.PrintToUser("%s has ID %s and endpoint %s: %s", node.Name, nodeInfo.Id, node.URI, describeLiveness(node.Liveness, node.Reason))
 		}
 		ux.Logger.PrintToUser("==================================== Custom VM information =======================================")
+		for _, vm := range health.VMs {
+			ux.Logger.PrintToUser("Blockchain %q of VM %s: %s", vm.BlockchainID, vm.VMName, describeLiveness(vm.Liveness, vm.Reason))
+		}
 		for _, nodeInfo := range status.ClusterInfo.NodeInfos {
-			for blockchainID := range status.ClusterInfo.CustomVms {
-				ux.Logger.PrintToUser("Endpoint at %s for blockchain %q: %s/ext/bc/%s/rpc", nodeInfo.Name, blockchainID, nodeInfo.GetUri(), blockchainID)
+			for blockchainID, vmInfo := range status.ClusterInfo.CustomVms {
+				ux.Logger.PrintToUser("Endpoint at %s for blockchain %q: %s (WebSocket: %s), by name: %s",
+					nodeInfo.Name,
+					blockchainID,
+					ux.RPCEndpoint(ux.HostURI(nodeInfo.GetUri()), blockchainID),
+					ux.WSEndpoint(ux.HostURI(nodeInfo.GetUri()), blockchainID),
+					ux.RPCEndpoint(ux.HostURI(nodeInfo.GetUri()), subnet.ChainAlias(vmInfo)),
+				)
 			}
 		}
+		ux.PrintPortsHint(status.ClusterInfo)
+		printUpgrades(status.ClusterInfo.CustomVms)
 	} else {
 		ux.Logger.PrintToUser("No local network running")
 	}
@@ -70,3 +120,62 @@ func networkStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// describeLiveness returns the liveness category, with the reason why it is
+// not live
+func describeLiveness(liveness subnet.Liveness, reason string) string {
+	if reason == "" {
+		return string(liveness)
+	}
+	return fmt.Sprintf("%s (%s)", liveness, reason)
+}
+
+// printNetworkID prints the network ID the nodes of the local network run
+// with, and the HRP of its addresses
+func printNetworkID(clusterInfo *rpcpb.ClusterInfo) {
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+		networkID, err := info.NewClient(ux.HostURI(nodeInfo.GetUri())).GetNetworkID(ctx)
+		cancel()
+		if err != nil {
+			app.Log.Warn("failed getting the network ID from %s: %s", nodeInfo.Name, err)
+			return
+		}
+		ux.Logger.PrintToUser("Network ID: %d (addresses P-%s1...)", networkID, avago_constants.GetHRP(networkID))
+		return
+	}
+}
+
+// printUpgrades prints the precompile upgrades scheduled for the blockchains
+// running on the local network, warning if its subnet-evm can't honor them
+func printUpgrades(runningChains map[string]*rpcpb.CustomVmInfo) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		app.Log.Warn("failed listing subnets: %s", err)
+		return
+	}
+	now := time.Now()
+	pending := false
+	for _, name := range names {
+		sc, err := app.LoadSidecar(name)
+		if err != nil || len(sc.Upgrades) == 0 {
+			continue
+		}
+		for _, data := range sc.Networks {
+			if _, ok := runningChains[data.BlockchainID.String()]; !ok {
+				continue
+			}
+			ux.Logger.PrintToUser("==================================== Upgrades of %s =======================================", name)
+			vm.PrintUpgrades(os.Stdout, sc, now)
+			pending = pending || len(vm.PendingUpgrades(sc, now)) > 0
+			break
+		}
+	}
+	if !pending {
+		return
+	}
+	version, _ := app.Conf.SubnetEVMVersion()
+	if err := vm.SupportsUpgrades(version); err != nil {
+		ux.Logger.PrintToUser("Warning: %s. Upgrade the nodes before the activation time", err)
+	}
+}
@@ -10,7 +10,10 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/webhooks"
 )
 
 func newStopCmd() *cobra.Command {
@@ -34,7 +37,7 @@ snapshot can then be restarted without parameter
 }
 
 func stopNetwork(cmd *cobra.Command, args []string) error {
-	cli, err := binutils.NewGRPCClient()
+	cli, err := binutils.NewGRPCClient(app)
 	if err != nil {
 		return err
 	}
@@ -68,6 +71,15 @@ func stopNetwork(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to stop network with a snapshot: %s", err)
 	}
+	// the snapshot can't be loaded by older avalanchego releases
+	if err := subnet.WriteSnapshotRecord(app, snapshotName); err != nil {
+		app.Log.Warn("failed recording the versions of snapshot %s: %s", snapshotName, err)
+	}
 	ux.Logger.PrintToUser("Network stopped successfully.")
+	webhooks.Notify(app, webhooks.Event{
+		Type:    webhooks.NetworkStopped,
+		Network: models.Local.String(),
+		Details: map[string]string{"snapshot": snapshotName},
+	})
 	return nil
 }
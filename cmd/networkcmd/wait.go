@@ -0,0 +1,57 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+var (
+	waitTimeout time.Duration
+	waitSubnet  string
+)
+
+// avalanche network wait
+func newWaitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Wait until the local network is healthy",
+		Long: `The network wait command blocks until the local network is healthy and,
+with --subnet, until the blockchain of the subnet is deployed on it and its
+VM is healthy, so that scripts can sequence their steps after starting the
+network or deploying in the background.
+
+It fails with exit code 3 if the network is not ready within --timeout,
+and with exit code 5 if the local network is not running at all.`,
+		RunE:         waitForNetwork,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().DurationVar(&waitTimeout, "timeout", constants.DefaultNetworkWaitTimeout, "how long to wait for the network to be ready")
+	cmd.Flags().StringVar(&waitSubnet, "subnet", "", "also wait until the blockchain of this subnet is deployed and healthy")
+	return cmd
+}
+
+func waitForNetwork(cmd *cobra.Command, args []string) error {
+	if waitTimeout <= 0 {
+		return exitcodes.UserInput(errors.New("--timeout must be positive"))
+	}
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	if _, err := deployer.WaitForSubnet(waitSubnet, waitTimeout); err != nil {
+		return err
+	}
+	if waitSubnet != "" {
+		ux.Logger.PrintToUser("Local network is healthy and %s is deployed", waitSubnet)
+		return nil
+	}
+	ux.Logger.PrintToUser("Local network is healthy")
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/staking"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeDir  string
+	certFile string
+)
+
+// avalanche node id
+func newIDCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "id",
+		Short: "Print the NodeID of a node",
+		Long: `The node id command prints the NodeID derived from the staking certificate
+of a node. Point --node-dir to the data directory of the node (by default
+~/.avalanchego), or to a directory directly holding its staker.crt. Use --cert
+instead to read a certificate file at any other location.
+
+The printed NodeID is the one to use with subnet addValidator.`,
+		RunE:         printNodeID,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&nodeDir, "node-dir", "", "data directory of the node")
+	cmd.Flags().StringVar(&certFile, "cert", "", "path of the staking certificate")
+	return cmd
+}
+
+func printNodeID(cmd *cobra.Command, args []string) error {
+	certPath := certFile
+	switch {
+	case certFile != "" && nodeDir != "":
+		return exitcodes.UserInput(errors.New("only one of --node-dir and --cert can be set"))
+	case certFile == "" && nodeDir == "":
+		return exitcodes.UserInput(errors.New("either --node-dir or --cert must be set"))
+	case nodeDir != "":
+		var err error
+		certPath, err = staking.FindCert(nodeDir)
+		if err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+	nodeID, err := staking.NodeIDFromCertFile(certPath)
+	if err != nil {
+		return err
+	}
+	app.Log.Info("read NodeID %s from %s", nodeID, certPath)
+	ux.Logger.PrintToUser(nodeID.String())
+	return nil
+}
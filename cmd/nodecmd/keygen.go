@@ -0,0 +1,74 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/staking"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keygenCount     int
+	keygenOutputDir string
+	keygenWeight    uint64
+)
+
+// avalanche node keygen
+func newKeygenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Pre-generate the identities of a planned validator fleet",
+		Long: `The node keygen command generates the staking key pairs of --count nodes
+ahead of provisioning their machines, so that the subnet validators can be
+planned, and added, before the nodes exist. Each pair is written to its own
+directory of --output-dir, node-01, node-02..., and the NodeIDs are listed,
+in the same order, in validators.csv, ready for subnet addValidator --file:
+
+  avalanche node keygen --count 10 -o ./nodes/
+  avalanche subnet addValidator mySubnet --file ./nodes/validators.csv
+
+Edit the weight, start and duration columns of validators.csv as needed. When
+provisioning the machine of a node, inject its key pair with node
+staking-keys import:
+
+  avalanche node staking-keys import --input-dir ./nodes/node-01 --node-dir ~/.avalanchego
+
+Existing files are never overwritten. Keep the generated staker.key files
+secret: they are the identities of the validators.`,
+		RunE:         generateFleet,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&keygenCount, "count", 1, "number of nodes to generate the key pairs of")
+	cmd.Flags().StringVarP(&keygenOutputDir, "output-dir", "o", "", "directory to write the key pairs and the manifest to")
+	cmd.Flags().Uint64Var(&keygenWeight, "weight", 20, "weight of the nodes in the manifest")
+	return cmd
+}
+
+func generateFleet(cmd *cobra.Command, args []string) error {
+	switch {
+	case keygenOutputDir == "":
+		return exitcodes.UserInput(errors.New("--output-dir must be set"))
+	case keygenCount < 1:
+		return exitcodes.UserInput(errors.New("--count must be at least 1"))
+	case keygenWeight == 0:
+		return exitcodes.UserInput(errors.New("--weight must be a positive integer"))
+	}
+	nodes, err := staking.GenerateFleet(keygenOutputDir, keygenCount, keygenWeight)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		ux.Logger.PrintToUser("%s: %s", node.NodeID, node.Dir)
+	}
+	manifestPath := filepath.Join(keygenOutputDir, constants.FleetManifestFile)
+	ux.Logger.PrintToUser("Generated the key pairs of %d nodes, add them as validators with:", len(nodes))
+	ux.Logger.PrintToUser("  avalanche subnet addValidator <subnetName> --file %s", manifestPath)
+	return nil
+}
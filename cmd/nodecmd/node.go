@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche node
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Inspect and manage the identity of avalanchego nodes",
+		Long: `The node command suite provides a collection of tools for the staking
+identity of avalanchego nodes. A node's NodeID is derived from its staking
+certificate, staker.crt, and is needed to add the node as a subnet validator.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// node id
+	cmd.AddCommand(newIDCmd())
+	// node staking-keys
+	cmd.AddCommand(newStakingKeysCmd())
+	// node keygen
+	cmd.AddCommand(newKeygenCmd())
+	return cmd
+}
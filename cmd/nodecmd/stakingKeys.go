@@ -0,0 +1,144 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/staking"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var keysDir string
+
+// avalanche node staking-keys
+func newStakingKeysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "staking-keys",
+		Short: "Export, import or generate staking key pairs",
+		Long: `The node staking-keys command suite moves the staking certificate and key
+of a node, staker.crt and staker.key, in and out of its data directory, or
+generates a new pair. Moving the pair moves the identity of the node, e.g. to
+migrate a validator to a new machine without changing its NodeID.
+
+Existing files are never overwritten.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(newStakingKeysExportCmd())
+	cmd.AddCommand(newStakingKeysImportCmd())
+	cmd.AddCommand(newStakingKeysGenerateCmd())
+	return cmd
+}
+
+func newStakingKeysExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the staking key pair of a node",
+		Long: `The node staking-keys export command copies staker.crt and staker.key
+from the data directory of a node to the --output-dir directory.`,
+		RunE:         exportStakingKeys,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&nodeDir, "node-dir", "", "data directory of the node")
+	cmd.Flags().StringVar(&keysDir, "output-dir", "", "directory to export the key pair to")
+	return cmd
+}
+
+func newStakingKeysImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import a staking key pair into a node",
+		Long: `The node staking-keys import command copies staker.crt and staker.key from
+the --input-dir directory into the data directory of a node, after checking
+they form a valid pair. The node must be restarted to use the imported pair.`,
+		RunE:         importStakingKeys,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&nodeDir, "node-dir", "", "data directory of the node")
+	cmd.Flags().StringVar(&keysDir, "input-dir", "", "directory holding the key pair to import")
+	return cmd
+}
+
+func newStakingKeysGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a new staking key pair",
+		Long: `The node staking-keys generate command creates a new staking certificate
+and key, and prints the NodeID they correspond to. With --node-dir, the pair
+is written to where the node reads it from, otherwise to --output-dir.`,
+		RunE:         generateStakingKeys,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&nodeDir, "node-dir", "", "data directory of the node")
+	cmd.Flags().StringVar(&keysDir, "output-dir", "", "directory to write the key pair to")
+	return cmd
+}
+
+// flatKeyPairPaths returns the paths of a key pair stored directly in dir
+func flatKeyPairPaths(dir string) (string, string) {
+	return filepath.Join(dir, constants.StakerCertFile), filepath.Join(dir, constants.StakerKeyFile)
+}
+
+func exportStakingKeys(cmd *cobra.Command, args []string) error {
+	if nodeDir == "" || keysDir == "" {
+		return exitcodes.UserInput(errors.New("both --node-dir and --output-dir must be set"))
+	}
+	srcCert, srcKey := staking.KeyPairPaths(nodeDir)
+	dstCert, dstKey := flatKeyPairPaths(keysDir)
+	nodeID, err := staking.CopyKeyPair(srcCert, srcKey, dstCert, dstKey)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Exported the staking key pair of %s to %s", nodeID, keysDir)
+	return nil
+}
+
+func importStakingKeys(cmd *cobra.Command, args []string) error {
+	if nodeDir == "" || keysDir == "" {
+		return exitcodes.UserInput(errors.New("both --node-dir and --input-dir must be set"))
+	}
+	srcCert, srcKey := flatKeyPairPaths(keysDir)
+	dstCert, dstKey := staking.KeyPairPaths(nodeDir)
+	nodeID, err := staking.CopyKeyPair(srcCert, srcKey, dstCert, dstKey)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Imported the staking key pair of %s into %s", nodeID, nodeDir)
+	return nil
+}
+
+func generateStakingKeys(cmd *cobra.Command, args []string) error {
+	var certPath, keyPath string
+	switch {
+	case nodeDir != "" && keysDir != "":
+		return exitcodes.UserInput(errors.New("only one of --node-dir and --output-dir can be set"))
+	case nodeDir != "":
+		certPath, keyPath = staking.KeyPairPaths(nodeDir)
+	case keysDir != "":
+		certPath, keyPath = flatKeyPairPaths(keysDir)
+	default:
+		return exitcodes.UserInput(errors.New("either --node-dir or --output-dir must be set"))
+	}
+	nodeID, err := staking.GenerateKeyPair(certPath, keyPath)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Generated a new staking key pair for %s:", nodeID)
+	ux.Logger.PrintToUser("  %s", certPath)
+	ux.Logger.PrintToUser("  %s", keyPath)
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package profilecmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createNetwork   string
+	createKey       string
+	createEndpoints []string
+	forceCreate     bool
+)
+
+// avalanche profile create
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [profileName]",
+		Short: "Bind a profile to a network, key and endpoints",
+		Long: `The profile create command binds a profile to a network, and optionally to
+the key signing its transactions and to the API endpoints of the network:
+
+  avalanche profile create prod --network mainnet --key ops-ledger --endpoint https://...
+
+The commands run with --profile prod then deploy to mainnet without asking,
+sign with ops-ledger and reach the network through the endpoints given, and
+refuse to deploy to another network, to sign with another key or to use
+another --endpoint.
+
+Use --force to bind a profile which is bound already again.`,
+		RunE:         createProfile,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&createNetwork, "network", "", "network the profile is bound to [local, fuji, mainnet]")
+	cmd.Flags().StringVar(&createKey, "key", "", "key signing the transactions of the profile")
+	cmd.Flags().StringSliceVar(&createEndpoints, "endpoint", nil, "API endpoint of the network, can be repeated (default the ones of the config file, or the public one)")
+	cmd.Flags().BoolVarP(&forceCreate, "force", "f", false, "overwrite the binding of the profile")
+	return cmd
+}
+
+func createProfile(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := config.CheckProfileName(name); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if name == constants.DefaultProfile {
+		return exitcodes.UserInput(errors.New("the default profile can't be bound, create a profile of another name"))
+	}
+	if createNetwork == "" {
+		return exitcodes.UserInput(errors.New("the network of the profile must be given with --network"))
+	}
+	path := app.GetProfileBindingPath(name)
+	if exists, _ := storage.FileExists(path); exists && !forceCreate {
+		return exitcodes.UserInput(fmt.Errorf("profile %s is bound already, use --force to bind it again", name))
+	}
+	binding := &config.ProfileBinding{
+		Name:      name,
+		Network:   strings.ToLower(createNetwork),
+		Key:       createKey,
+		Endpoints: createEndpoints,
+	}
+	if err := binding.Validate(); err != nil {
+		return exitcodes.UserInput(fmt.Errorf("invalid profile %s: %w", name, err))
+	}
+	if createKey != "" && !app.KeyExists(app.Conf.ResolveKeyAlias(createKey)) {
+		return exitcodes.UserInput(fmt.Errorf("key %s does not exist", createKey))
+	}
+	if err := config.SaveProfileBinding(path, binding); err != nil {
+		return fmt.Errorf("failed saving profile %s: %w", name, err)
+	}
+	ux.Logger.PrintToUser("Profile %s is bound to %s, use it with --profile %s", name, describeBinding(binding), name)
+	return nil
+}
+
+// describeBinding returns what a profile is bound to, in a few words
+func describeBinding(binding *config.ProfileBinding) string {
+	description := binding.Network
+	if binding.Key != "" {
+		description += ", key " + binding.Key
+	}
+	if len(binding.Endpoints) > 0 {
+		description += ", endpoints " + strings.Join(binding.Endpoints, ", ")
+	}
+	return description
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package profilecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche profile delete
+func newDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [profileName]",
+		Short: "Unbind a profile",
+		Long: `The profile delete command removes the binding of a profile to its network,
+key and endpoints. The local network of the profile is left alone, use
+network clean --profile to delete it.`,
+		RunE:         deleteProfile,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func deleteProfile(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	err := os.Remove(app.GetProfileBindingPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return exitcodes.UserInput(fmt.Errorf("profile %s is not bound", name))
+	}
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Profile %s is not bound anymore", name)
+	return nil
+}
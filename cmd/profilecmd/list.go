@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package profilecmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche profile list
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles and what they are bound to",
+		Long: `The profile list command prints the profiles, with the network, key and
+endpoints of the bound ones. The others only run their own local network.`,
+		RunE:         listProfiles,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func listProfiles(cmd *cobra.Command, args []string) error {
+	entries, err := os.ReadDir(app.GetProfilesDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Profile", "Network", "Key", "Endpoints"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	rows := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		binding, err := config.LoadProfileBinding(name, app.GetProfileBindingPath(name))
+		if err != nil {
+			return err
+		}
+		if binding == nil {
+			table.Append([]string{name, "-", "-", "-"})
+		} else {
+			table.Append([]string{name, binding.Network, binding.Key, strings.Join(binding.Endpoints, "\n")})
+		}
+		rows++
+	}
+	if rows == 0 {
+		ux.Logger.PrintToUser("No profiles, create one with profile create")
+		return nil
+	}
+	table.Render()
+	return nil
+}
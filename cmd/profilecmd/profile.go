@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package profilecmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche profile
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Bind profiles to a network, key and endpoints",
+		Long: `The profile command suite binds the profiles selected with --profile to the
+network, the signing key and the API endpoints to use with them, so that the
+commands run with a profile can't use another network, key or endpoint by
+mistake, such as signing a mainnet transaction with a test key.
+
+Each profile also runs its own isolated local network, bound or not.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// profile create
+	cmd.AddCommand(newCreateCmd())
+	// profile list
+	cmd.AddCommand(newListCmd())
+	// profile delete
+	cmd.AddCommand(newDeleteCmd())
+	return cmd
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registrycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/registry"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportNetwork string
+	exportOutput  string
+)
+
+// avalanche registry export
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the chain parameters of your subnets as a chain list",
+		Long: `The registry export command prints the subnet-evm chains deployed to a
+network as a JSON chain list, in the format of
+https://github.com/ethereum-lists/chains used by wallets and frontends, to
+share them with the teams integrating your subnets.
+
+A chain list can't hold a chain ID twice, so the chains of one network are
+exported at a time, the local network by default.`,
+		RunE:         exportRegistry,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&exportNetwork, "network", "local", "network of the exported chains [local, fuji, mainnet]")
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file to write the chain list to, instead of printing it")
+	return cmd
+}
+
+func exportRegistry(cmd *cobra.Command, args []string) error {
+	network, err := networkFromFlag("network", exportNetwork)
+	if err != nil {
+		return err
+	}
+	chains, err := buildRegistry()
+	if err != nil {
+		return err
+	}
+	entries := registry.ChainList(chains, network)
+	chainList, err := json.MarshalIndent(entries, "", "    ")
+	if err != nil {
+		return err
+	}
+	if exportOutput == "" {
+		fmt.Println(string(chainList))
+		return nil
+	}
+	if err := app.CheckWritable("write " + exportOutput); err != nil {
+		return err
+	}
+	if err := os.WriteFile(exportOutput, append(chainList, '\n'), application.WriteReadReadPerms); err != nil {
+		return fmt.Errorf("failed writing the chain list: %w", err)
+	}
+	ux.Logger.PrintToUser("Chain list of the %d chains on %s written to %s", len(entries), network, exportOutput)
+	return nil
+}
+
+func networkFromFlag(flagName, value string) (models.Network, error) {
+	switch strings.ToLower(value) {
+	case "local":
+		return models.Local, nil
+	case "fuji":
+		return models.Fuji, nil
+	case "mainnet":
+		return models.Mainnet, nil
+	}
+	return models.Undefined, exitcodes.UserInput(fmt.Errorf("invalid --%s %q, must be one of local, fuji, mainnet", flagName, value))
+}
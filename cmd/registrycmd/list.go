@@ -0,0 +1,45 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registrycmd
+
+import (
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche registry list
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the chain parameters of your subnets",
+		Long: `The registry list command prints the chain ID, token symbol, blockchain ID
+and RPC URL of the subnet-evm chain of every subnet, once for each network it
+is deployed to. Subnets which aren't deployed are listed without network.`,
+		RunE:         listRegistry,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func listRegistry(cmd *cobra.Command, args []string) error {
+	chains, err := buildRegistry()
+	if err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Subnet", "Chain ID", "Token", "Network", "Blockchain ID", "RPC URL"})
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1, 2})
+	table.SetRowLine(true)
+	for _, c := range chains {
+		network, blockchainID := "-", "-"
+		if c.Deployed() {
+			network = c.Network.String()
+			blockchainID = c.BlockchainID.String()
+		}
+		table.Append([]string{c.Subnet, c.ChainID.String(), c.TokenSymbol, network, blockchainID, c.RPCURL})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registrycmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche registry
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "List the chain IDs, tokens and RPC URLs of your subnets",
+		Long: `The registry command suite gathers the parameters wallets and frontends
+need to connect to the subnet-evm chains of your subnets: their chain ID,
+token symbol and RPC URL on each network they are deployed to.
+
+The registry is built from the subnet configurations and their deployments,
+so it always reflects the current state of the CLI.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// registry list
+	cmd.AddCommand(newListCmd())
+	// registry export
+	cmd.AddCommand(newExportCmd())
+	return cmd
+}
+
+// buildRegistry returns the chains of the registry, with the RPC URLs of the
+// endpoints in use
+func buildRegistry() ([]registry.Chain, error) {
+	endpoints := map[models.Network]string{
+		models.Local: binutils.LocalEndpoint(app),
+	}
+	for _, network := range []models.Network{models.Fuji, models.Mainnet} {
+		endpoint, err := app.GetAPIEndpoint(network)
+		if err != nil {
+			return nil, err
+		}
+		endpoints[network] = endpoint
+	}
+	return registry.Build(app, endpoints)
+}
@@ -3,32 +3,160 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/cmd/backendcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/backupcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/bridgecmd"
+	"github.com/ava-labs/avalanche-cli/cmd/cachecmd"
+	"github.com/ava-labs/avalanche-cli/cmd/diskcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/keycmd"
+	"github.com/ava-labs/avalanche-cli/cmd/logscmd"
 	"github.com/ava-labs/avalanche-cli/cmd/networkcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/nodecmd"
+	"github.com/ava-labs/avalanche-cli/cmd/profilecmd"
+	"github.com/ava-labs/avalanche-cli/cmd/registrycmd"
+	"github.com/ava-labs/avalanche-cli/cmd/runcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/schemacmd"
+	"github.com/ava-labs/avalanche-cli/cmd/servecmd"
+	"github.com/ava-labs/avalanche-cli/cmd/servicescmd"
+	"github.com/ava-labs/avalanche-cli/cmd/statecmd"
 	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/supportcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/transactioncmd"
+	"github.com/ava-labs/avalanche-cli/cmd/tutorialcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/upcmd"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/devenv"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/lock"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/publicapi"
+	"github.com/ava-labs/avalanche-cli/pkg/support"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
+	"github.com/ava-labs/avalanche-cli/pkg/tlsconfig"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 )
 
 var (
 	app *application.Avalanche
 
-	logLevel string
-	Version  = ""
-	cfgFile  string
+	logLevel  string
+	logFormat string
+	language  string
+	useASCII  bool
+	profile   string
+	endpoint  string
+	readOnly  bool
+	headless  bool
+	unlock    bool
+	// correlationID identifies the invocation in the logs of the CLI and of
+	// the backend
+	correlationID string
+	// lockWait is how long to wait for the state lock held by another
+	// command, e.g. by the deploy starting a deploy in the background
+	lockWait time.Duration
+	// printTimings prints the breakdown of the time the command took
+	printTimings bool
+	// traceGRPC logs the requests to the backend and their responses
+	traceGRPC bool
+	// assumeYes confirms destructive operations without typing the name
+	// of what they destroy
+	assumeYes bool
+	// allowEwoq lets the operations on Fuji and Mainnet involve the ewoq key
+	allowEwoq bool
+	// avalancheGoPath is an avalanchego binary for the local networks to run
+	avalancheGoPath string
+	// promptTimeout answers the prompts with their default once elapsed
+	promptTimeout time.Duration
+	Version       = ""
+	cfgFile       string
+
+	// stateLock is held by the commands mutating the app state
+	stateLock *lock.Lock
+
+	// readOnlyCommands are the commands which don't mutate any state, the
+	// only ones allowed in read-only mode
+	readOnlyCommands = map[string]bool{
+		"avalanche disk usage":               true,
+		"avalanche help":                     true,
+		"avalanche key agent":                true,
+		"avalanche key list":                 true,
+		"avalanche key lock":                 true,
+		"avalanche key export":               true,
+		"avalanche key unlock":               true,
+		"avalanche logs cli":                 true,
+		"avalanche network graph":            true,
+		"avalanche network status":           true,
+		"avalanche network wait":             true,
+		"avalanche node id":                  true,
+		"avalanche profile list":             true,
+		"avalanche registry export":          true,
+		"avalanche registry list":            true,
+		"avalanche schema export":            true,
+		"avalanche services list":            true,
+		"avalanche state show":               true,
+		"avalanche subnet accounts":          true,
+		"avalanche subnet conformance":       true,
+		"avalanche subnet cost":              true,
+		"avalanche subnet deploy-status":     true,
+		"avalanche subnet describe":          true,
+		"avalanche subnet docs":              true,
+		"avalanche subnet governance":        true,
+		"avalanche subnet lint":              true,
+		"avalanche subnet list":              true,
+		"avalanche subnet metrics":           true,
+		"avalanche subnet plan":              true,
+		"avalanche subnet render":            true,
+		"avalanche subnet stats":             true,
+		"avalanche subnet validators health": true,
+		"avalanche subnet verify":            true,
+		"avalanche subnet verify-binary":     true,
+		"avalanche support bundle":           true,
+		"avalanche up diff":                  true,
+		"avalanche up status":                true,
+	}
+
+	// unlockedCommands are the commands running until interrupted which only
+	// write their own files, or take the state lock for each of their writes,
+	// and so don't hold it, letting other commands run meanwhile. run doesn't
+	// hold it either, its steps being commands taking it themselves.
+	unlockedCommands = map[string]bool{
+		"avalanche bridge relay": true,
+		"avalanche run":          true,
+		"avalanche serve":        true,
+	}
+
+	// sharedLockCommands are the commands mutating the state which can run
+	// concurrently with each other, coordinating on their own, such as the
+	// deploys of different subnets. They only exclude the other commands.
+	sharedLockCommands = map[string]bool{
+		"avalanche subnet deploy": true,
+	}
+
+	// runnableSuites are the commands with subcommands which do more than
+	// printing their help
+	runnableSuites = map[string]bool{
+		"avalanche up": true,
+	}
 )
 
 func NewRootCmd() *cobra.Command {
@@ -49,34 +177,294 @@ in with avalanche subnet create myNewSubnet.`,
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.avalanche-cli.json)")
-	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", constants.DefaultLogLevel, "log level for the application")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", constants.DefaultLogFormat, "log format for the application (plain, colors, json)")
+	rootCmd.PersistentFlags().StringVar(&language, "lang", "", "language of the user facing messages (default derived from the locale)")
+	rootCmd.PersistentFlags().BoolVar(&useASCII, "ascii", false, "only print ASCII characters and disable animations, for accessibility")
+	rootCmd.PersistentFlags().BoolVar(&useASCII, "no-emoji", false, "alias for --ascii")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", constants.DefaultProfile, "profile, each profile runs its own isolated local network, and is bound to the network, key and endpoints given to profile create, if any")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "refuse any operation mutating state, such as deploys, transactions and file writes")
+	rootCmd.PersistentFlags().BoolVar(&headless, "headless", false, "never prompt, taking all inputs from flags, environment variables and config files, and print plain line-based output, e.g. for CI")
+	rootCmd.PersistentFlags().DurationVar(&promptTimeout, "prompt-timeout", 0, "answer the prompts with their default answer after this long, or fail the ones without, e.g. for semi-automated runs (default waits forever)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "confirm destructive operations, such as network clean or subnet delete, without typing the name of what they destroy")
+	rootCmd.PersistentFlags().BoolVar(&allowEwoq, constants.AllowEwoqFlag, false, "allow the operations on Fuji and Mainnet signed by, controlled by or funding the publicly known ewoq key")
+	rootCmd.PersistentFlags().BoolVar(&unlock, "force-unlock", false, "take over the state lock of another avalanche command, if it is stuck")
+	rootCmd.PersistentFlags().StringVar(&correlationID, "correlation-id", "", "ID of this invocation in the logs of the CLI and of the backend, for support (default a random one)")
+	rootCmd.PersistentFlags().DurationVar(&lockWait, constants.LockWaitFlag, 0, "wait this long for the state lock held by another avalanche command instead of failing")
+	_ = rootCmd.PersistentFlags().MarkHidden(constants.LockWaitFlag)
+	rootCmd.PersistentFlags().StringVar(&endpoint, "endpoint", "", "API endpoint to use for Fuji and Mainnet instead of the public one, e.g. your own node or an RPC provider")
+	rootCmd.PersistentFlags().BoolVar(&printTimings, "timings", false, "print to stderr, as a line of JSON, the wall-clock time the command spent downloading, in gRPC calls, waiting for health and in file IO")
+	rootCmd.PersistentFlags().BoolVar(&traceGRPC, "trace-grpc", false, "log every request to the backend running the local network and its response, truncating the large ones, to the log file of the invocation, for debugging")
+	rootCmd.PersistentFlags().StringVar(&avalancheGoPath, "avalanchego-path", "", "avalanchego binary for the local network to run instead of the managed release, e.g. a build of a branch")
 
 	// add sub commands
 	rootCmd.AddCommand(subnetcmd.NewCmd(app))
 	rootCmd.AddCommand(networkcmd.NewCmd(app))
 	rootCmd.AddCommand(keycmd.NewCmd(app))
+	rootCmd.AddCommand(logscmd.NewCmd(app))
+	rootCmd.AddCommand(nodecmd.NewCmd(app))
+	rootCmd.AddCommand(transactioncmd.NewCmd(app))
+	rootCmd.AddCommand(backupcmd.NewCmd(app))
+	rootCmd.AddCommand(upcmd.NewCmd(app))
+	rootCmd.AddCommand(registrycmd.NewCmd(app))
+	rootCmd.AddCommand(supportcmd.NewCmd(app))
+	rootCmd.AddCommand(cachecmd.NewCmd(app))
+	rootCmd.AddCommand(bridgecmd.NewCmd(app))
+	rootCmd.AddCommand(servecmd.NewCmd(app))
+	rootCmd.AddCommand(statecmd.NewCmd(app))
+	rootCmd.AddCommand(diskcmd.NewCmd(app))
+	rootCmd.AddCommand(profilecmd.NewCmd(app))
+	rootCmd.AddCommand(runcmd.NewCmd(app))
+	rootCmd.AddCommand(tutorialcmd.NewCmd(app))
+	rootCmd.AddCommand(servicescmd.NewCmd(app))
+	rootCmd.AddCommand(schemacmd.NewCmd(app))
 
 	// add hidden backend command
 	rootCmd.AddCommand(backendcmd.NewCmd(app))
 
+	// errors in flags and arguments are user input errors
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return exitcodes.UserInput(err)
+	})
+	markArgErrorsAsUserInput(rootCmd)
+
 	return rootCmd
 }
 
+// markArgErrorsAsUserInput wraps the argument validators of cmd and
+// all its subcommands so that their errors result in a user input exit code
+func markArgErrorsAsUserInput(cmd *cobra.Command) {
+	if cmd.Args != nil {
+		validateArgs := cmd.Args
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			return exitcodes.UserInput(validateArgs(c, args))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markArgErrorsAsUserInput(sub)
+	}
+}
+
 func createApp(cmd *cobra.Command, args []string) error {
+	if err := applyEnvFlags(cmd); err != nil {
+		return err
+	}
+	// colors and animations only make sense on a terminal
+	plainOutput := headless || !term.IsTerminal(int(os.Stdout.Fd()))
+	if plainOutput && !cmd.Flags().Changed("log-format") {
+		logFormat = "plain"
+	}
 	baseDir, err := setupEnv()
 	if err != nil {
 		return err
 	}
-	log, err := setupLogging(baseDir)
+	log, logFile, err := setupLogging(baseDir)
 	if err != nil {
 		return err
 	}
 	cf := config.New()
-	app.Setup(baseDir, log, cf, prompts.NewPrompter())
-	cobra.OnInitialize(initConfig)
+	cf.SetEndpoint(endpoint)
+	cf.SetAvalancheGoPath(avalancheGoPath)
+	cf.SetReadOnly(readOnly)
+	cf.SetAllowEwoq(allowEwoq)
+	cf.SetInContainer(devenv.Detect().InContainer())
+	if promptTimeout < 0 {
+		return exitcodes.UserInput(errors.New("--prompt-timeout can't be negative"))
+	}
+	prompter := prompts.NewPrompter(prompts.WithTimeout(promptTimeout), prompts.WithAssumeYes(assumeYes))
+	if headless {
+		prompter = prompts.NewHeadlessPrompter(prompts.WithAssumeYes(assumeYes))
+	}
+	app.Setup(baseDir, log, cf, prompter)
+	app.SetLogFile(logFile)
+	app.SetVersion(Version)
+	if err := setupCorrelationID(cmd); err != nil {
+		return err
+	}
+	if traceGRPC {
+		binutils.SetGRPCTrace(app.Log)
+	}
+	if err := setupProject(cmd); err != nil {
+		return err
+	}
+	if err := setupProfile(); err != nil {
+		return err
+	}
+	if err := setupProfileBinding(); err != nil {
+		return err
+	}
+	setupOutput(plainOutput)
+	// cobra has already run its initializers at this point
+	initConfig()
+	// the transports of the public API wrap the one with the TLS settings
+	if err := tlsconfig.Install(app); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if err := publicapi.Install(app); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	setupKeyUnlocker()
+	if err := checkReadOnly(cmd); err != nil {
+		return err
+	}
+	if err := lockState(cmd); err != nil {
+		return err
+	}
+	// the invocations changing the state are recorded along with the
+	// artifacts of the run dir they may produce
+	if stateLock != nil {
+		if err := app.RecordInvocation(cmd.CommandPath()); err != nil {
+			app.Log.Warn("failed recording the invocation: %s", err)
+		}
+		warnIncompatibleState()
+	}
+	return nil
+}
+
+// warnIncompatibleState warns about the state recorded by other versions of
+// the CLI or of the releases it runs, with how to fix it, before a command
+// mutating the state fails on it
+func warnIncompatibleState() {
+	incompatibilities, err := support.CheckCompatibility(app)
+	if err != nil {
+		app.Log.Warn("failed checking the compatibility of the state: %s", err)
+		return
+	}
+	for _, incompatibility := range incompatibilities {
+		app.Log.Warn("%s", incompatibility.Problem)
+		fmt.Fprintf(os.Stderr, "Warning: %s. To fix it: %s\n", incompatibility.Problem, incompatibility.Fix)
+	}
+}
+
+// printLikelyCauses walks through the state of the backend and the logs of
+// the local network after it failed to become healthy, and prints the likely
+// causes found, with how to fix them
+func printLikelyCauses() {
+	fmt.Fprintln(os.Stderr, "Troubleshooting the local network: checking the backend, the exit codes of the nodes and their logs...")
+	causes, err := support.TriageUnhealthy(app)
+	if err != nil {
+		app.Log.Warn("failed troubleshooting the local network: %s", err)
+		return
+	}
+	if len(causes) == 0 {
+		fmt.Fprintln(os.Stderr, "No known cause found. Attach the archive of avalanche support bundle to a bug report.")
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Likely causes, the most likely first:")
+	for i, cause := range causes {
+		app.Log.Warn("likely cause: %s (%s)", cause.Problem, cause.Evidence)
+		hits := ""
+		if cause.Hits > 1 {
+			hits = fmt.Sprintf(" (%d log lines)", cause.Hits)
+		}
+		fmt.Fprintf(os.Stderr, "%d. %s%s\n   To fix it: %s\n   Found in %s\n", i+1, cause.Problem, hits, cause.Fix, cause.Evidence)
+	}
+}
+
+// printTimingsReport prints the breakdown of the time cmd took, with the
+// error it returned, as a line of JSON for the numbers to be parsed by scripts
+// and attached to performance issues
+func printTimingsReport(cmd *cobra.Command, err error, total time.Duration) {
+	command := "avalanche"
+	if cmd != nil {
+		command = cmd.CommandPath()
+	}
+	report := timings.NewReport(command, int(exitcodes.FromError(err)), total)
+	reportBytes, err := json.Marshal(report)
+	if err != nil {
+		app.Log.Warn("failed marshaling the timings: %s", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(reportBytes))
+}
+
+// setupCorrelationID sets the correlation ID of the invocation, given with
+// --correlation-id or else random, and logs it
+func setupCorrelationID(cmd *cobra.Command) error {
+	if correlationID == "" {
+		var err error
+		if correlationID, err = application.NewCorrelationID(); err != nil {
+			return err
+		}
+	} else if err := application.CheckCorrelationID(correlationID); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	app.SetCorrelationID(correlationID)
+	binutils.SetCorrelationID(correlationID)
+	app.Log.Info("correlation ID %s: %s", correlationID, cmd.CommandPath())
 	return nil
 }
 
+// applyEnvFlags sets the flags of cmd not given on the command line from their
+// environment variable, if set: AVALANCHE_ followed by the flag name in upper
+// case with dashes replaced by underscores, e.g. AVALANCHE_LOG_LEVEL
+func applyEnvFlags(cmd *cobra.Command) error {
+	fromEnv := map[string]string{}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if value, ok := os.LookupEnv(flagEnvVar(f.Name)); ok && !f.Changed {
+			fromEnv[f.Name] = value
+		}
+	})
+	for name, value := range fromEnv {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return exitcodes.UserInput(fmt.Errorf("invalid value %q of %s: %w", value, flagEnvVar(name), err))
+		}
+	}
+	return nil
+}
+
+func flagEnvVar(flagName string) string {
+	return constants.EnvVarPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// checkReadOnly refuses to run cmd in read-only mode, unless it doesn't
+// mutate any state. Commands with subcommands only print their help.
+func checkReadOnly(cmd *cobra.Command) error {
+	if printsHelpOnly(cmd) || readOnlyCommands[cmd.CommandPath()] {
+		return nil
+	}
+	err := app.CheckWritable("run " + cmd.CommandPath())
+	if err != nil {
+		// the command is used right, it is just not allowed
+		cmd.SilenceUsage = true
+	}
+	return err
+}
+
+// printsHelpOnly returns true if cmd is a command suite, which only prints
+// the help of its subcommands
+func printsHelpOnly(cmd *cobra.Command) bool {
+	return cmd.HasSubCommands() && !runnableSuites[cmd.CommandPath()]
+}
+
+// lockState takes the state lock for the duration of cmd, unless it doesn't
+// mutate any state, shared with the commands which can run concurrently.
+// Hidden commands are the long running processes spawned
+// by other commands, and must not hold it.
+func lockState(cmd *cobra.Command) error {
+	if printsHelpOnly(cmd) || cmd.Hidden || readOnlyCommands[cmd.CommandPath()] || unlockedCommands[cmd.CommandPath()] {
+		return nil
+	}
+	lockPath := filepath.Join(app.GetBaseDir(), constants.LockFile)
+	if unlock {
+		if err := lock.ForceUnlock(lockPath); err != nil {
+			return fmt.Errorf("failed removing the state lock: %w", err)
+		}
+		app.Log.Warn("state lock forcefully removed")
+	}
+	var err error
+	if sharedLockCommands[cmd.CommandPath()] {
+		stateLock, err = lock.AcquireSharedWait(lockPath, cmd.CommandPath(), lockWait)
+	} else {
+		stateLock, err = lock.AcquireWait(lockPath, cmd.CommandPath(), lockWait)
+	}
+	if errors.Is(err, lock.ErrLocked) {
+		cmd.SilenceUsage = true
+		return exitcodes.Locked(fmt.Errorf("%w. Wait for it to complete, or use --force-unlock if it is stuck", err))
+	}
+	return err
+}
+
 func setupEnv() (string, error) {
 	// Set base dir
 	usr, err := user.Current()
@@ -112,35 +500,164 @@ func setupEnv() (string, error) {
 	return baseDir, nil
 }
 
-func setupLogging(baseDir string) (logging.Logger, error) {
+// setupLogging creates a logger writing to a dedicated, rotating log file
+// for this invocation. The file level follows the display level if the latter
+// is more verbose than the default file level.
+func setupLogging(baseDir string) (logging.Logger, string, error) {
 	var err error
 
 	config := logging.Config{}
 	config.LogLevel = logging.Info
 	config.DisplayLevel, err = logging.ToLevel(logLevel)
 	if err != nil {
-		return nil, fmt.Errorf("invalid log level configured: %s", logLevel)
+		return nil, "", exitcodes.UserInput(fmt.Errorf("invalid log level configured: %s", logLevel))
+	}
+	if config.DisplayLevel < config.LogLevel {
+		config.LogLevel = config.DisplayLevel
+	}
+	config.LogFormat, err = logging.ToFormat(logFormat, os.Stdout.Fd())
+	if err != nil {
+		return nil, "", exitcodes.UserInput(fmt.Errorf("invalid log format configured: %s", logFormat))
 	}
-	config.Directory = filepath.Join(baseDir, "logs")
+	config.Directory = filepath.Join(baseDir, constants.LogDir)
 	if err := os.MkdirAll(config.Directory, perms.ReadWriteExecute); err != nil {
-		return nil, fmt.Errorf("failed creating log directory: %w", err)
+		return nil, "", fmt.Errorf("failed creating log directory: %w", err)
 	}
 
 	// some logging config params
-	config.LogFormat = logging.Colors
 	config.MaxSize = constants.MaxLogFileSize
 	config.MaxFiles = constants.MaxNumOfLogFiles
 	config.MaxAge = constants.RetainOldFiles
 
+	// each invocation gets its own log file, so that the logs of the last
+	// run can be easily retrieved (e.g. for bug reports)
+	logName := fmt.Sprintf("%s%s-%d", constants.CliLogPrefix, time.Now().Format("20060102-150405"), os.Getpid())
+	pruneInvocationLogs(config.Directory)
+
 	factory := logging.NewFactory(config)
-	log, err := factory.Make("avalanche")
+	log, err := factory.Make(logName)
 	if err != nil {
 		factory.Close()
-		return nil, fmt.Errorf("failed setting up logging, exiting: %s", err)
+		return nil, "", fmt.Errorf("failed setting up logging, exiting: %s", err)
 	}
 	// create the user facing logger as a global var
 	ux.NewUserLog(log, os.Stdout)
-	return log, nil
+	return log, filepath.Join(config.Directory, logName+constants.LogSuffix), nil
+}
+
+// pruneInvocationLogs removes the oldest invocation log files so that
+// at most constants.MaxNumOfInvocationLogs are kept, including the new one
+func pruneInvocationLogs(logDir string) {
+	matches, err := filepath.Glob(filepath.Join(logDir, constants.CliLogPrefix+"*"+constants.LogSuffix))
+	if err != nil {
+		return
+	}
+	// file names start with the timestamp, so lexical order is chronological
+	sort.Strings(matches)
+	for len(matches) >= constants.MaxNumOfInvocationLogs {
+		_ = os.Remove(matches[0])
+		matches = matches[1:]
+	}
+}
+
+// setupProject loads the project config found in the working directory
+// or its parents, if any. Settings of the project only apply where the
+// corresponding flag has not been given.
+func setupProject(cmd *cobra.Command) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	projectFile, err := config.FindProjectConfig(wd)
+	if err != nil || projectFile == "" {
+		return err
+	}
+	project, err := config.LoadProjectConfig(projectFile)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	app.Log.Info("Using project config: %s", projectFile)
+	app.Conf.SetProject(project)
+	if project.Profile != "" && !cmd.Flags().Changed("profile") {
+		profile = project.Profile
+	}
+	return nil
+}
+
+// setupProfile selects the local network profile and creates its directories
+func setupProfile() error {
+	if err := config.CheckProfileName(profile); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	app.SetProfile(profile)
+	for _, dir := range []string{app.GetRunDir(), app.GetSnapshotsDir()} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed creating the profile dir %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// setupProfileBinding applies the network, key and endpoints the profile in
+// use is bound to, if any. An --endpoint other than the ones of the binding
+// is refused, as it would reach another node than the profile expects.
+func setupProfileBinding() error {
+	binding, err := config.LoadProfileBinding(app.GetProfile(), app.GetProfileBindingPath(app.GetProfile()))
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if binding == nil {
+		return nil
+	}
+	if endpoint != "" && len(binding.Endpoints) > 0 {
+		bound := false
+		for _, e := range binding.Endpoints {
+			bound = bound || strings.TrimSuffix(e, "/") == strings.TrimSuffix(endpoint, "/")
+		}
+		if !bound {
+			return exitcodes.UserInput(fmt.Errorf("profile %s is bound to the endpoints %s, not to %s",
+				binding.Name, strings.Join(binding.Endpoints, ", "), endpoint))
+		}
+	}
+	app.Conf.SetProfileBinding(binding)
+	return nil
+}
+
+// setupOutput configures localization and accessibility of the user facing
+// output. Plain output reports progress with lines instead of animations.
+// setupKeyUnlocker has the encrypted keys unlocked by prompting for their
+// passphrase, and held by the key agent for the following commands
+func setupKeyUnlocker() {
+	key.SetUnlocker(&key.Unlocker{
+		Passphrase: func(keyPath string) (string, error) {
+			keyName := strings.TrimSuffix(filepath.Base(keyPath), constants.KeySuffix)
+			return app.Prompt.CapturePassword(fmt.Sprintf("Passphrase of key %s", keyName))
+		},
+		AgentSocket: app.GetKeyAgentSocket(),
+		TTL:         app.Conf.KeyUnlockTTL(),
+		Log:         app.Log.Warn,
+	})
+}
+
+func setupOutput(plainOutput bool) {
+	ux.SetLineMode(plainOutput)
+	if useASCII {
+		ux.SetASCIIMode(true)
+		prompts.UseASCIIIcons()
+	}
+	lang := language
+	if lang == "" {
+		lang = ux.LanguageFromEnv()
+	}
+	ux.SetNumberLocale(lang)
+	if err := ux.LoadCatalog(app.GetLocalesDir(), lang); err != nil {
+		// an explicitly requested language must exist,
+		// a language derived from the environment may not
+		if language != "" {
+			ux.Logger.PrintToUser("Falling back to english messages: %s", err)
+		}
+		app.Log.Debug("failed loading message catalog: %s", err)
+	}
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -163,17 +680,35 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		app.Log.Info("Using config file: %s", viper.ConfigFileUsed())
 	} else {
-		app.Log.Info("No log file found")
+		app.Log.Info("No config file found")
 	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The exit code of the process reflects the class of failure, see pkg/exitcodes.
 func Execute() {
+	start := time.Now()
 	app = application.New()
 	rootCmd := NewRootCmd()
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err := binutils.CloseGRPCClients(); err != nil {
+		app.Log.Warn("failed closing the backend connections: %s", err)
+	}
+	if err := stateLock.Release(); err != nil {
+		app.Log.Warn("failed releasing the state lock: %s", err)
+	}
+	if printTimings {
+		printTimingsReport(cmd, err, time.Since(start))
+	}
 	if err != nil {
-		os.Exit(1)
+		if exitcodes.FromError(err) == exitcodes.NetworkUnhealthy {
+			printLikelyCauses()
+		}
+		// for support to find the logs of the failed invocation
+		if id := app.GetCorrelationID(); id != "" {
+			fmt.Fprintf(os.Stderr, "Correlation ID: %s, logs at %s\n", id, app.GetLogFile())
+		}
+		os.Exit(int(exitcodes.FromError(err)))
 	}
 }
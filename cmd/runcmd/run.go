@@ -0,0 +1,126 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package runcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/pipeline"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	app *application.Avalanche
+
+	pipelineVars map[string]string
+	dryRun       bool
+)
+
+// avalanche run
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "run [pipelineFile]",
+		Short: "Run the steps of a YAML pipeline",
+		Long: `The run command runs the steps of a pipeline file one after the other, to
+script the lifecycle of subnets without a shell script per command:
+
+  vars:
+    subnet: tokens
+  steps:
+    - name: create
+      avalanche: subnet create ${subnet} --evm --file ./genesis.json --force
+    - name: deploy
+      avalanche: subnet deploy ${subnet} --local
+    - name: chain-id
+      run: jq -r .config.chainId ./genesis.json
+      output: chainID
+    - name: tests
+      run: go test ./e2e/... -chain-id ${chainID}
+      continue-on-error: true
+    - name: snapshot
+      avalanche: network stop ${subnet}-tested
+      if: ${steps.tests.outcome} == success
+    - name: cleanup
+      avalanche: network clean --yes
+      if: always
+
+A step either runs an avalanche command, with the global flags the pipeline
+is run with, or a shell command with run, in the directory of the pipeline
+file. ${name} is replaced by the variable name, set in vars, with --var, or
+by the output of a previous step, ${env.NAME} by an environment variable and
+${steps.<step>.outcome} by the outcome of a previous step: success, failure
+or skipped.
+
+A failing step fails the pipeline, unless it has continue-on-error, and the
+steps after it are skipped, except the ones with if: always or if: failure.
+Otherwise, a step with an if runs when its condition holds: a comparison,
+a == b or a != b, or a single value, true unless empty, false, no or 0.
+
+Use --dry-run to print the steps with their variables replaced, without
+running them.`,
+		RunE:         runPipeline,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringToStringVar(&pipelineVars, "var", nil, "value of a variable of the pipeline, as name=value")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the steps instead of running them")
+	return cmd
+}
+
+func runPipeline(cmd *cobra.Command, args []string) error {
+	p, err := pipeline.Load(args[0])
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	vars, err := pipeline.NewVars(p, pipelineVars)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	runner := &pipeline.Runner{
+		Self:       reexec.Self(),
+		GlobalArgs: globalArgs(cmd),
+		Shell:      "sh",
+		Exec:       execStep,
+		Out:        os.Stdout,
+		DryRun:     dryRun,
+	}
+	results, err := runner.Run(p, vars)
+	summary := map[pipeline.StepOutcome]int{}
+	for _, result := range results {
+		summary[result.Outcome]++
+		if result.Outcome == pipeline.OutcomeFailure {
+			app.Log.Error("pipeline %s: step %s failed: %s", p.Path, result.Step.Name, result.Err)
+		}
+	}
+	ux.Logger.PrintToUser("Pipeline %s: %d succeeded, %d failed, %d skipped", p.Path,
+		summary[pipeline.OutcomeSuccess], summary[pipeline.OutcomeFailure], summary[pipeline.OutcomeSkipped])
+	return err
+}
+
+// globalArgs returns the global flags given to this command, for the
+// avalanche steps to run with the same profile, project, output settings...
+func globalArgs(cmd *cobra.Command) []string {
+	args := []string{}
+	cmd.InheritedFlags().Visit(func(f *pflag.Flag) {
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return args
+}
+
+func execStep(dir string, name string, args []string, stdout io.Writer) error {
+	c := exec.Command(name, args...)
+	c.Dir = dir
+	c.Stdin = os.Stdin
+	c.Stdout = stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schemacmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/schema"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var exportOutputDir string
+
+// avalanche schema export
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [format]",
+		Short: "Export the JSON schema of a file format of the CLI",
+		Long: `The schema export command prints the JSON schema of a file format of the CLI,
+or writes it to --output-dir as <format>.schema.json. Without a format, the
+schemas of all of them are written to --output-dir.
+
+The formats are:
+  sidecar        the sidecar of a subnet, <subnetName>_sidecar.json
+  subnet-spec    a subnet of a topology file
+  topology       a topology file, deployed by up
+  answers        an answers file of subnet create --record and --replay
+  deploy-report  the report of subnet deploy --report
+
+The schemas follow the JSON Schema 2020-12 dialect, and match the version of
+the CLI which exports them.`,
+		RunE:         exportSchemas,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&exportOutputDir, "output-dir", "", "directory to write the schemas to, instead of printing them")
+	return cmd
+}
+
+func exportSchemas(cmd *cobra.Command, args []string) error {
+	formats := schema.Formats
+	if len(args) == 1 {
+		format, err := schema.GetFormat(args[0])
+		if err != nil {
+			return exitcodes.UserInput(err)
+		}
+		formats = []schema.Format{format}
+	} else if exportOutputDir == "" {
+		return exitcodes.UserInput(fmt.Errorf("give the format to print, one of %s, or --output-dir to write all of them", strings.Join(schema.FormatNames(), ", ")))
+	}
+
+	if exportOutputDir == "" {
+		schemaBytes, err := formats[0].JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(schemaBytes))
+		return nil
+	}
+	if err := app.CheckWritable("write " + exportOutputDir); err != nil {
+		return err
+	}
+	if info, err := os.Stat(exportOutputDir); err == nil && !info.IsDir() {
+		return exitcodes.UserInput(errors.New("--output-dir is not a directory"))
+	}
+	if err := os.MkdirAll(exportOutputDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	for _, format := range formats {
+		schemaBytes, err := format.JSON()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(exportOutputDir, format.Name+constants.SchemaSuffix)
+		if err := os.WriteFile(path, append(schemaBytes, '\n'), application.WriteReadReadPerms); err != nil {
+			return fmt.Errorf("failed writing the schema of %s: %w", format.Name, err)
+		}
+		ux.Logger.PrintToUser("Schema of %s written to %s", format.Name, path)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schemacmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche schema
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Export the JSON schemas of the files of the CLI",
+		Long: `The schema command suite exports JSON schemas of the files the CLI reads and
+writes, for editors and external tools to validate and autocomplete them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// schema export
+	cmd.AddCommand(newExportCmd())
+	return cmd
+}
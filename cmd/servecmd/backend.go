@@ -0,0 +1,219 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/lock"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/server"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/topology"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// backend implements the API of the server with the app. The requests
+// mutating the state are serialized, and take the state lock.
+type backend struct {
+	lock sync.Mutex
+}
+
+// withStateLock runs f holding the state lock, or fails if another avalanche
+// command holds it
+func (b *backend) withStateLock(command string, f func() error) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err := app.CheckWritable(command); err != nil {
+		return err
+	}
+	stateLock, err := lock.Acquire(filepath.Join(app.GetBaseDir(), constants.LockFile), "avalanche serve: "+command)
+	if errors.Is(err, lock.ErrLocked) {
+		return exitcodes.Locked(err)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stateLock.Release(); err != nil {
+			app.Log.Warn("failed releasing the state lock: %s", err)
+		}
+	}()
+	return f()
+}
+
+func (b *backend) ListSubnets() ([]server.SubnetStatus, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	clusterInfo, err := localClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	statuses := []server.SubnetStatus{}
+	for _, name := range names {
+		status, err := subnetStatus(name, clusterInfo)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (b *backend) Status(name string) (server.SubnetStatus, error) {
+	if !app.GenesisExists(name) {
+		return server.SubnetStatus{}, fmt.Errorf("subnet %s %w", name, server.ErrNotFound)
+	}
+	clusterInfo, err := localClusterInfo()
+	if err != nil {
+		return server.SubnetStatus{}, err
+	}
+	return subnetStatus(name, clusterInfo)
+}
+
+func (b *backend) CreateSubnet(req server.CreateRequest) (server.SubnetStatus, error) {
+	spec := topology.SubnetSpec{Name: req.Name, VM: req.VM}
+	if err := (&topology.Topology{Subnets: []topology.SubnetSpec{spec}}).Validate(); err != nil {
+		return server.SubnetStatus{}, exitcodes.UserInput(err)
+	}
+	genesis := bytes.TrimSpace(req.Genesis)
+	if len(genesis) == 0 || bytes.Equal(genesis, []byte("null")) {
+		return server.SubnetStatus{}, exitcodes.UserInput(fmt.Errorf("subnet %s has no genesis", req.Name))
+	}
+	err := b.withStateLock("create subnet "+req.Name, func() error {
+		if app.GenesisExists(req.Name) {
+			return exitcodes.UserInput(fmt.Errorf("subnet %s already exists", req.Name))
+		}
+		if err := app.WriteGenesisFile(req.Name, genesis); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Created subnet %s", req.Name)
+		return app.CreateSidecar(&models.Sidecar{
+			Name:   req.Name,
+			VM:     spec.VMType(),
+			Subnet: req.Name,
+		})
+	})
+	if err != nil {
+		return server.SubnetStatus{}, err
+	}
+	return b.Status(req.Name)
+}
+
+func (b *backend) DeployLocal(name string) (server.SubnetStatus, error) {
+	if !app.GenesisExists(name) {
+		return server.SubnetStatus{}, fmt.Errorf("subnet %s %w", name, server.ErrNotFound)
+	}
+	err := b.withStateLock("deploy subnet "+name, func() error {
+		ux.Logger.PrintToUser("Deploying %s to the local network", name)
+		return deployLocal(name)
+	})
+	if err != nil {
+		return server.SubnetStatus{}, err
+	}
+	return b.Status(name)
+}
+
+// deployLocal deploys the subnet to the local network and records the
+// deployment in its sidecar
+func deployLocal(name string) error {
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		return fmt.Errorf("failed loading subnet %s: %w", name, err)
+	}
+	genesisPath, cleanup, err := app.ResolveGenesisFile(name, models.Local, nil)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	subnetID, blockchainID, err := deployer.DeployToLocalNetwork(sc, genesisPath)
+	if err != nil {
+		if deployer.BackendStartedHere() {
+			if innerErr := binutils.KillgRPCServerProcess(app); innerErr != nil {
+				app.Log.Warn("tried to kill the gRPC server process but it failed: %s", innerErr)
+			}
+		}
+		return err
+	}
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	sc.Networks[app.GetLocalNetworkKey()] = models.NetworkData{
+		SubnetID:     subnetID,
+		BlockchainID: blockchainID,
+		VMBinaryHash: deployer.VMBinaryHash(),
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return fmt.Errorf("creation of chains and subnet was successful, but failed to update sidecar: %w", err)
+	}
+	return nil
+}
+
+// localClusterInfo returns the state of the local network, without the
+// undeployed blockchains, or nil if it is not running
+func localClusterInfo() (*rpcpb.ClusterInfo, error) {
+	isRunning, err := binutils.NewProcessChecker().IsServerProcessRunning(app)
+	if err != nil {
+		return nil, exitcodes.Backend(fmt.Errorf("failed querying if server process is running: %w", err))
+	}
+	if !isRunning {
+		return nil, nil
+	}
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		// TODO: use error type not string comparison
+		if strings.Contains(err.Error(), "not bootstrapped") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return subnet.FilterUndeployed(app, status.GetClusterInfo())
+}
+
+// subnetStatus returns the status of the subnet on the local network in
+// clusterInfo, which is nil if the network is not running
+func subnetStatus(name string, clusterInfo *rpcpb.ClusterInfo) (server.SubnetStatus, error) {
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		return server.SubnetStatus{}, fmt.Errorf("failed loading subnet %s: %w", name, err)
+	}
+	status := server.SubnetStatus{Name: name, VM: string(sc.VM)}
+	deployment, ok := sc.Networks[app.GetLocalNetworkKey()]
+	if !ok || deployment.BlockchainID == ids.Empty {
+		return status, nil
+	}
+	status.SubnetID = deployment.SubnetID.String()
+	status.BlockchainID = deployment.BlockchainID.String()
+	if _, ok := clusterInfo.GetCustomVms()[status.BlockchainID]; !ok {
+		return status, nil
+	}
+	status.Running = true
+	nodeNames := []string{}
+	for nodeName := range clusterInfo.GetNodeInfos() {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		status.Endpoints = append(status.Endpoints, ux.RPCEndpoint(clusterInfo.GetNodeInfos()[nodeName].GetUri(), status.BlockchainID))
+	}
+	return status, nil
+}
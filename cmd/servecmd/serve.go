@@ -0,0 +1,115 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/server"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Avalanche
+
+	servePort  int
+	serveToken string
+)
+
+// avalanche serve
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the subnet lifecycle over a local REST API",
+		Long: `The serve command exposes the lifecycle of the subnets on the local network
+over a REST API on localhost, for IDE extensions and internal portals to
+drive the CLI programmatically, until interrupted:
+
+  GET  /v1/subnets                   list the subnets
+  POST /v1/subnets                   create a subnet from a spec
+  GET  /v1/subnets/<name>            status of a subnet
+  GET  /v1/subnets/<name>/endpoints  RPC endpoints of a subnet
+  POST /v1/subnets/<name>/deploy     deploy a subnet to the local network
+
+A spec is a JSON object with the name of the subnet, its vm (subnet-evm or
+custom, subnet-evm by default) and its genesis.
+
+Every request must carry the header "Authorization: Bearer <token>". The
+token is given with --token, or the AVALANCHE_TOKEN environment variable to
+keep it out of the process list, or generated and written to a file readable by
+the user only, whose path is printed at startup.
+
+The server doesn't hold the state lock while idle: every request creating or
+deploying a subnet takes it, and fails with 409 Conflict if another avalanche
+command holds it.`,
+		RunE:         serve,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&servePort, "port", constants.DefaultServePort, "localhost port to serve the API on")
+	cmd.Flags().StringVar(&serveToken, "token", "", "bearer token authenticating the requests (default generated)")
+	return cmd
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	if servePort <= 0 || servePort > 65535 {
+		return exitcodes.UserInput(fmt.Errorf("invalid --port %d", servePort))
+	}
+	token := serveToken
+	if token == "" {
+		var err error
+		if token, err = server.NewToken(); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		tokenPath := app.GetServeTokenPath()
+		if err := os.WriteFile(tokenPath, []byte(token), perms.ReadWrite); err != nil {
+			return fmt.Errorf("failed writing the token to %s: %w", tokenPath, err)
+		}
+		defer os.Remove(tokenPath)
+		ux.Logger.PrintToUser("Token written to %s", tokenPath)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	httpServer := &http.Server{
+		Addr:              net.JoinHostPort("127.0.0.1", strconv.Itoa(servePort)),
+		Handler:           server.New(&backend{}, token, app.Log),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- httpServer.ListenAndServe()
+	}()
+	ux.Logger.PrintToUser("Serving the API on http://%s%s, press Ctrl+C to stop...", httpServer.Addr, server.APIPrefix)
+
+	select {
+	case <-ctx.Done():
+		// a deploy in progress is let to finish
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+		defer shutdownCancel()
+		return httpServer.Shutdown(shutdownCtx)
+	case err := <-errc:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("failed serving on %s: %w", httpServer.Addr, err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servicescmd
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/supervisor"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche services list
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the background processes of the profile",
+		Long: `The services list command prints the background processes of the profile,
+with their status, pid, restarts, uptime and the file their output goes to.
+
+A service is restarting when its process exited and its supervisor is about
+to restart it, and exited once it won't be anymore.`,
+		RunE:         listServices,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func listServices(cmd *cobra.Command, args []string) error {
+	services, err := supervisor.List(app)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		ux.Logger.PrintToUser("No services running in profile %s", app.GetProfile())
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Service", "Status", "Pid", "Restarts", "Uptime", "Output"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, svc := range services {
+		status := svc.Status()
+		uptime := "-"
+		if status == supervisor.StatusRunning && !svc.StartedAt.IsZero() {
+			uptime = svc.Uptime().String()
+		}
+		if status == supervisor.StatusExited && svc.LastExit != "" {
+			status += supervisor.Status(" (" + svc.LastExit + ")")
+		}
+		table.Append([]string{
+			svc.Name,
+			string(status),
+			strconv.Itoa(svc.Pid),
+			strconv.Itoa(svc.Restarts),
+			uptime,
+			svc.OutputFile,
+		})
+	}
+	table.Render()
+	return nil
+}
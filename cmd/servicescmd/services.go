@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servicescmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche services
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "services",
+		Short: "Manage the background processes of the CLI",
+		Long: `The services command suite manages the background processes the CLI starts
+for a profile, such as the backend controller running the local network and
+the RPC proxy.
+
+They are recorded in the run dir of the profile, and run under a supervisor
+process restarting them if they fail, a few times, with a growing delay.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// services list
+	cmd.AddCommand(newListCmd())
+	// services stop
+	cmd.AddCommand(newStopCmd())
+	// services supervise
+	cmd.AddCommand(newSuperviseCmd())
+	return cmd
+}
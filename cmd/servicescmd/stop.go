@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servicescmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/supervisor"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var stopAll bool
+
+// avalanche services stop
+func newStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop [serviceName]",
+		Short: "Stop background processes of the profile",
+		Long: `The services stop command stops a background process of the profile, or all
+of them with --all, along with its supervisor so that it isn't restarted, and
+removes its record. Exited services are only removed.
+
+The backend is stopped without saving the local network it runs, use network
+stop to save it.`,
+		RunE:         stopServices,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&stopAll, "all", false, "stop all the services of the profile")
+	return cmd
+}
+
+func stopServices(cmd *cobra.Command, args []string) error {
+	if stopAll == (len(args) == 1) {
+		return exitcodes.UserInput(errors.New("give either the service to stop or --all"))
+	}
+	names := args
+	if stopAll {
+		services, err := supervisor.List(app)
+		if err != nil {
+			return err
+		}
+		names = nil
+		for _, svc := range services {
+			names = append(names, svc.Name)
+		}
+		if len(names) == 0 {
+			ux.Logger.PrintToUser("No services running in profile %s", app.GetProfile())
+			return nil
+		}
+	}
+	for _, name := range names {
+		err := supervisor.Stop(app, name)
+		switch {
+		case errors.Is(err, supervisor.ErrNotRunning):
+			if stopAll {
+				ux.Logger.PrintToUser("Service %s was not running", name)
+				continue
+			}
+			return exitcodes.UserInput(fmt.Errorf("service %s is not running in profile %s", name, app.GetProfile()))
+		case err != nil:
+			return fmt.Errorf("failed stopping service %s: %w", name, err)
+		default:
+			ux.Logger.PrintToUser("Service %s stopped", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servicescmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/supervisor"
+	"github.com/spf13/cobra"
+)
+
+// avalanche services supervise, the process restarting a service, spawned
+// when starting it
+func newSuperviseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "supervise [serviceName]",
+		Short:  "Run a background process, restarting it per its policy",
+		RunE:   supervise,
+		Args:   cobra.ExactArgs(1),
+		Hidden: true,
+	}
+}
+
+func supervise(cmd *cobra.Command, args []string) error {
+	return supervisor.Supervise(app, args[0])
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statecmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/statesync"
+	"github.com/spf13/cobra"
+)
+
+// avalanche state pull
+func newPullCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Take the subnets shared by the team",
+		Long: `The state pull command downloads the genesis, the sidecar and the chain
+config of the subnets changed on the remote since the last push or pull, see
+state push for the remotes. The deployments of the sidecars to the local
+networks are kept.
+
+A subnet changed both locally and on the remote since the last sync is a
+conflict, failing the pull. Pass --force to overwrite the local changes with
+the ones of the remote.`,
+		RunE:         pullState,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	addSyncFlags(cmd)
+	return cmd
+}
+
+func pullState(cmd *cobra.Command, args []string) error {
+	remote, err := getRemote()
+	if err != nil {
+		return err
+	}
+	changes, err := statesync.Pull(app.GetBaseDir(), remote, statesync.Options{
+		Force:  syncForce,
+		DryRun: syncDryRun,
+	})
+	return printChanges(changes, err, remote, "pulled from", "back up the local changes first")
+}
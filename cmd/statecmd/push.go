@@ -0,0 +1,110 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statecmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/statesync"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncRemote  string
+	syncForce   bool
+	syncDryRun  bool
+	pushMessage string
+
+	errNoRemote = errors.New("no remote to sync the state with, pass --remote or set state-remote in the project or the config file")
+)
+
+// avalanche state push
+func newPushCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "Share the subnets with the team",
+		Long: `The state push command uploads the genesis, the sidecar and the chain config
+of the subnets changed since the last push or pull to the remote the team
+shares them in:
+
+  - a git repository, as git+<url>[#branch] or any URL ending in .git,
+    where each push is a commit
+  - an S3 bucket, as s3://bucket/prefix, with the aws CLI
+  - a GCS bucket, as gs://bucket/prefix, with the gsutil CLI
+  - a directory, e.g. on a shared drive, as a path or a file:// URL
+
+The remote is --remote, or else state-remote in the project or the config
+file. Keys, snapshots and the deployments to the local networks are never
+pushed.
+
+A subnet changed both locally and on the remote since the last sync is a
+conflict, failing the push. Pull first to take the changes of the team, or
+pass --force to overwrite them.`,
+		RunE:         pushState,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	addSyncFlags(cmd)
+	cmd.Flags().StringVarP(&pushMessage, "message", "m", "", "describe the changes, on the remotes keeping a history")
+	return cmd
+}
+
+func addSyncFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&syncRemote, "remote", "", "remote to sync the state with")
+	cmd.Flags().BoolVar(&syncForce, "force", false, "overwrite the subnets changed on both sides")
+	cmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "print the changes without making them")
+}
+
+func pushState(cmd *cobra.Command, args []string) error {
+	remote, err := getRemote()
+	if err != nil {
+		return err
+	}
+	message := pushMessage
+	if message == "" {
+		message = "Update the subnets"
+	}
+	changes, err := statesync.Push(app.GetBaseDir(), remote, statesync.Options{
+		Force:   syncForce,
+		DryRun:  syncDryRun,
+		Message: message,
+	})
+	return printChanges(changes, err, remote, "pushed to", "pull first to take the changes of the remote")
+}
+
+func getRemote() (statesync.Remote, error) {
+	url := syncRemote
+	if url == "" {
+		url = app.Conf.StateRemote()
+	}
+	if url == "" {
+		return nil, exitcodes.UserInput(errNoRemote)
+	}
+	return statesync.ParseRemote(url)
+}
+
+func printChanges(changes []statesync.Change, err error, remote statesync.Remote, done string, hint string) error {
+	var conflictErr *statesync.ConflictError
+	if errors.As(err, &conflictErr) {
+		return exitcodes.UserInput(fmt.Errorf("%w: %s, or pass --force to overwrite them", err, hint))
+	}
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		ux.Logger.PrintToUser("Already in sync with %s", remote)
+		return nil
+	}
+	for _, change := range changes {
+		ux.Logger.PrintToUser("  %-6s %s", change.Action, change.File)
+	}
+	if syncDryRun {
+		ux.Logger.PrintToUser("Dry run, %d files would be %s %s", len(changes), done, remote)
+		return nil
+	}
+	ux.Logger.PrintToUser("%d files %s %s", len(changes), done, remote)
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/support"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var showJSON bool
+
+// avalanche state show
+func newShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print everything the CLI keeps track of",
+		Long: `The state show command prints the model the CLI has of what exists, in one
+view:
+
+  - the subnets, whether their sidecar and genesis can be read, and the
+    networks they are deployed to
+  - the avalanchego and subnet-evm releases installed
+  - the snapshots of the local network
+  - the local and remote keys
+  - the backend and the proxy of the profile, and whether they are running
+
+Nothing is checked against the networks, so that the output tells what the
+CLI bases its decisions on. Pass --json for a machine readable output, e.g.
+to attach to a bug report.`,
+		RunE:         showState,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&showJSON, "json", false, "print the state as JSON")
+	return cmd
+}
+
+func showState(cmd *cobra.Command, args []string) error {
+	state, err := support.GetState(app)
+	if err != nil {
+		return fmt.Errorf("failed reading the state: %w", err)
+	}
+	if showJSON {
+		stateBytes, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(stateBytes))
+		return nil
+	}
+
+	ux.Logger.PrintToUser("Profile: %s", state.Profile)
+	ux.Logger.PrintToUser("Base directory: %s", state.BaseDir)
+
+	ux.Logger.PrintToUser("\nSubnets:")
+	if len(state.Subnets) == 0 {
+		ux.Logger.PrintToUser("  none")
+	}
+	for _, subnet := range state.Subnets {
+		if subnet.Error != "" {
+			ux.Logger.PrintToUser("  %s: invalid sidecar: %s", subnet.Name, subnet.Error)
+			continue
+		}
+		genesis := ""
+		if !subnet.Genesis {
+			genesis = ", genesis missing"
+		}
+		ux.Logger.PrintToUser("  %s (%s, chain ID %s%s)", subnet.Name, subnet.VM, subnet.ChainID, genesis)
+		if len(subnet.Deployments) == 0 {
+			ux.Logger.PrintToUser("    not deployed")
+		}
+		for _, d := range subnet.Deployments {
+			ux.Logger.PrintToUser("    %s: subnet %s, blockchain %s", d.Network, d.SubnetID, d.BlockchainID)
+		}
+	}
+
+	ux.Logger.PrintToUser("\nBinaries:")
+	if len(state.Binaries) == 0 {
+		ux.Logger.PrintToUser("  none")
+	}
+	for _, b := range state.Binaries {
+		ux.Logger.PrintToUser("  %s %s at %s", b.Name, b.Version, b.Path)
+	}
+
+	ux.Logger.PrintToUser("\nSnapshots: %s", listOrNone(state.Snapshots))
+	ux.Logger.PrintToUser("Keys: %s", listOrNone(state.Keys))
+
+	ux.Logger.PrintToUser("\nBackend: %s", describeProcess(state.Backend))
+	ux.Logger.PrintToUser("Proxy: %s", describeProcess(state.Proxy))
+	return nil
+}
+
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// describeProcess tells whether p runs, and if it doesn't, whether it left a
+// stale run file behind
+func describeProcess(p support.Process) string {
+	switch {
+	case p.RunFile == "":
+		return "not started"
+	case p.Pid == 0:
+		return fmt.Sprintf("invalid run file %s", p.RunFile)
+	case !p.Running:
+		return fmt.Sprintf("not running, stale run file %s of pid %d", p.RunFile, p.Pid)
+	case p.Port != 0:
+		return fmt.Sprintf("running, pid %d, port %d", p.Pid, p.Port)
+	}
+	return fmt.Sprintf("running, pid %d", p.Pid)
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statecmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche state
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect and share the state of the CLI",
+		Long: `The state command suite shows what the CLI thinks exists, as read from
+the files it keeps in its base directory, and shares the subnets defined
+there with a team through a remote.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// state show
+	cmd.AddCommand(newShowCmd())
+	// state push
+	cmd.AddCommand(newPushCmd())
+	// state pull
+	cmd.AddCommand(newPullCmd())
+	return cmd
+}
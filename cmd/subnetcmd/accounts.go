@@ -0,0 +1,143 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche subnet accounts
+func newAccountsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "accounts [subnetName]",
+		Short: "List the addresses funded by the genesis of a subnet",
+		Long: `The subnet accounts command lists the addresses funded by the genesis of
+a subnet-evm subnet, with the name of the stored key of each address, as
+recorded by subnet create, and its airdropped amount.
+
+When the subnet is running on the local network, the current balance of
+every address is queried from the RPC of its chain.`,
+		RunE:         listAccounts,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func listAccounts(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s is not a subnet-evm subnet", subnetName))
+	}
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return err
+	}
+
+	addresses := make([]common.Address, 0, len(genesis.Alloc))
+	for address := range genesis.Alloc {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Hex() < addresses[j].Hex() })
+
+	balances, err := localBalances(sc, addresses)
+	if err != nil {
+		ux.Logger.PrintToUser("Balances not available: %s", err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{
+		"Address",
+		"Key",
+		fmt.Sprintf("Airdrop Amount (%s)", sc.TokenName),
+		fmt.Sprintf("Balance (%s)", sc.TokenName),
+	})
+	table.SetRowLine(true)
+	for i, address := range addresses {
+		keyName := sc.FundedKeys[address.Hex()]
+		if keyName == "" && address == vm.PrefundedEwoqAddress {
+			keyName = "ewoq"
+		}
+		balance := "-"
+		if balances != nil {
+			balance = ux.FormatAmount(balances[i], sc.GetTokenDecimals())
+		}
+		table.Append([]string{
+			address.Hex(),
+			keyName,
+			ux.FormatAmount(genesis.Alloc[address].Balance, sc.GetTokenDecimals()),
+			balance,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// localBalances returns the balances of addresses on the chain of sc running
+// on the local network
+func localBalances(sc models.Sidecar, addresses []common.Address) ([]*big.Int, error) {
+	rpcURL, err := localRPCURL(sc)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	return subnet.GetBalances(ctx, rpcURL, addresses)
+}
+
+// localRPCURL returns the RPC URL of the chain of sc on the first node of the
+// local network, if it is running there
+func localRPCURL(sc models.Sidecar) (string, error) {
+	blockchainID := sc.Networks[localNetworkKey()].BlockchainID
+	if blockchainID == ids.Empty {
+		return "", fmt.Errorf("subnet %s has not been deployed to the local network", sc.Name)
+	}
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		return "", fmt.Errorf("failed to query the local network status: %w", err)
+	}
+	clusterInfo := status.GetClusterInfo()
+	if clusterInfo == nil {
+		return "", errors.New("no local network running")
+	}
+	if _, ok := clusterInfo.CustomVms[blockchainID.String()]; !ok {
+		return "", fmt.Errorf("blockchain %s is not running on the local network", blockchainID)
+	}
+	nodeNames := make([]string, 0, len(clusterInfo.NodeInfos))
+	for name := range clusterInfo.NodeInfos {
+		nodeNames = append(nodeNames, name)
+	}
+	if len(nodeNames) == 0 {
+		return "", errors.New("no local node running")
+	}
+	sort.Strings(nodeNames)
+	return ux.RPCEndpoint(clusterInfo.NodeInfos[nodeNames[0]].Uri, blockchainID.String()), nil
+}
@@ -3,18 +3,24 @@
 package subnetcmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/webhooks"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -24,8 +30,13 @@ var (
 	startTimeStr string
 	duration     time.Duration
 
-	errNoSubnetID    = errors.New("failed to find the subnet ID for this subnet, has it been deployed/created on this network?")
-	startTimeDefault = time.Now().Add(constants.StakingStartLeadTime)
+	validatorsFile        string
+	waitValidator         bool
+	validatorsParallelism int
+
+	errNoSubnetID = errors.New("failed to find the subnet ID for this subnet, has it been deployed/created on this network?")
+	// startTimeDefault starts the validation as soon as the P-Chain allows
+	startTimeDefault = "in " + constants.StakingStartLeadTime.String()
 )
 
 // avalanche subnet deploy
@@ -39,18 +50,43 @@ validate the provided deployed subnet.
 To add the validator to the subnet's allow list, you first need to provide
 the subnetName and the validator's unique NodeID. The command then prompts
 for the validation start time, duration and stake weight. These values can
-all be collected with flags instead of prompts.
+all be collected with flags instead of prompts. Run node id on the validator
+machine to print its NodeID.
+
+To add many validators at once, list them in a CSV file given with --file,
+one validator per row with the columns nodeID, weight, start and duration,
+e.g. "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg,20,2022-08-01 12:00:00,720h".
+An empty start defaults to shortly from now, an empty duration to the maximum
+staking period. The transactions are signed up front, each spending what the
+previous ones left, and --parallel of them are issued at once, a transaction
+spending the change of another one once that one is committed.
+
+Start times are entered relative to now, e.g. "in 10 minutes", in RFC3339
+format, e.g. 2022-08-01T12:00:00Z, or as 'YYYY-MM-DD HH:MM:SS' in the local
+timezone. Times are printed in the local timezone, which they show. All rows are validated before any transaction is issued, and the
+outcome of each row is reported.
+
+With --wait, the command then watches the P-Chain until the added validators
+move from pending to current, reporting every change of their status.
+
+With --unsigned, the transaction is only built, to be signed by the control
+keys of the subnet with transaction sign and broadcast with transaction
+broadcast.
 
 This command currently only works on subnets deployed to the Fuji testnet.`,
 		SilenceUsage: true,
 		RunE:         addValidator,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.RangeArgs(0, 1),
 	}
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use")
 	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to add")
 	cmd.Flags().StringVar(&weightStr, "weight", "", "set the staking weight of the validator to add")
-	cmd.Flags().StringVar(&startTimeStr, "start-time", startTimeDefault.Format(constants.TimeParseLayout), "start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
+	cmd.Flags().StringVar(&startTimeStr, "start-time", startTimeDefault, "start time when this validator starts validating, relative to now as in 'in 10 minutes', in RFC3339 format, or in 'YYYY-MM-DD HH:MM:SS' format in the local timezone")
 	cmd.Flags().DurationVar(&duration, "staking-period", constants.MaxStakeDuration, "how long this validator will be staking")
+	cmd.Flags().StringVar(&validatorsFile, "file", "", "add all the validators listed in a CSV file")
+	cmd.Flags().IntVar(&validatorsParallelism, "parallel", constants.DefaultValidatorTxParallelism, "how many transactions adding the validators of --file are issued at once")
+	cmd.Flags().BoolVar(&waitValidator, "wait", false, "wait until the added validators start validating")
+	addUnsignedFlags(cmd)
 	return cmd
 }
 
@@ -62,12 +98,32 @@ func addValidator(cmd *cobra.Command, args []string) error {
 		err    error
 	)
 
-	if keyName == "" {
+	if validatorsFile != "" {
+		for _, flag := range []string{"nodeID", "weight", "start-time", "staking-period"} {
+			if cmd.Flags().Changed(flag) {
+				return exitcodes.UserInput(fmt.Errorf("--%s can't be used together with --file", flag))
+			}
+		}
+		if validatorsParallelism < 1 {
+			return exitcodes.UserInput(fmt.Errorf("--parallel must be at least 1, not %d", validatorsParallelism))
+		}
+	} else if cmd.Flags().Changed("parallel") {
+		return exitcodes.UserInput(errors.New("--parallel only applies to the validators of --file"))
+	}
+
+	if buildUnsigned {
+		for _, flag := range []string{"file", "wait"} {
+			if cmd.Flags().Changed(flag) {
+				return exitcodes.UserInput(fmt.Errorf("--%s can't be used together with --unsigned", flag))
+			}
+		}
+	} else if keyName == "" {
 		keyName, err = captureKeyName()
 		if err != nil {
 			return err
 		}
 	}
+	keyName = app.Conf.ResolveKeyAlias(keyName)
 
 	var network models.Network
 	networkStr, err := app.Prompt.CaptureList(
@@ -78,6 +134,9 @@ func addValidator(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	network = models.NetworkFromString(networkStr)
+	if err := app.CheckProfileBinding(network, keyName); err != nil {
+		return err
+	}
 
 	chains, err := validateSubnetNameAndGetChains(args)
 	if err != nil {
@@ -91,7 +150,14 @@ func addValidator(cmd *cobra.Command, args []string) error {
 
 	subnetID := sc.Networks[network.String()].SubnetID
 	if subnetID == ids.Empty {
-		return errNoSubnetID
+		return exitcodes.UserInput(errNoSubnetID)
+	}
+
+	// the start times are checked by the network against its own time
+	clock := networkClock(network)
+
+	if validatorsFile != "" {
+		return addValidatorsFromFile(subnetName, network, subnetID, clock)
 	}
 
 	if nodeIDStr == "" {
@@ -102,7 +168,7 @@ func addValidator(cmd *cobra.Command, args []string) error {
 	} else {
 		nodeID, err = ids.NodeIDFromString(nodeIDStr)
 		if err != nil {
-			return err
+			return exitcodes.UserInput(err)
 		}
 	}
 
@@ -114,22 +180,23 @@ func addValidator(cmd *cobra.Command, args []string) error {
 	} else {
 		weight, err = strconv.ParseUint(weightStr, 10, 64)
 		if err != nil {
-			return err
+			return exitcodes.UserInput(err)
 		}
 	}
 
 	if startTimeStr == "" {
-		start, err = promptStart()
+		start, err = promptStart(clock)
 		if err != nil {
 			return err
 		}
 	} else {
-		start, err = time.Parse(constants.TimeParseLayout, startTimeStr)
+		now := clock.Now()
+		start, err = ux.ParseTime(startTimeStr, now)
 		if err != nil {
-			return err
+			return exitcodes.UserInput(err)
 		}
-		if start.Before(time.Now().Add(constants.StakingStartLeadTime)) {
-			return fmt.Errorf("time should be at least %s in the future ", constants.StakingStartLeadTime)
+		if start.Before(now.Add(constants.StakingStartLeadTime)) {
+			return exitcodes.UserInput(fmt.Errorf("time should be at least %s in the future ", constants.StakingStartLeadTime))
 		}
 	}
 
@@ -141,9 +208,116 @@ func addValidator(cmd *cobra.Command, args []string) error {
 	}
 	// TODO validate this duration?
 
+	// time passes while prompting
+	if !start.After(clock.Now()) {
+		return exitcodes.UserInput(fmt.Errorf("the start time %s has passed on %s, give a later one", ux.FormatTime(start, clock.Now()), network))
+	}
+
+	if buildUnsigned {
+		return addValidatorUnsigned(network, subnetName, subnetID, nodeID, weight, start, duration)
+	}
+
 	ux.Logger.PrintToUser("Inputs complete, issuing transaction to add the provided validator information...")
-	deployer := subnet.NewPublicDeployer(app, app.GetKeyPath(keyName), network)
-	return deployer.AddValidator(subnetID, nodeID, weight, start, duration)
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network)
+	if err := deployer.AddValidator(subnetID, nodeID, weight, start, duration); err != nil {
+		return err
+	}
+	notifyValidatorAdded(subnetName, network, nodeID, weight, start, duration)
+	if waitValidator {
+		return waitForValidator(deployer, subnetID, nodeID, start)
+	}
+	return nil
+}
+
+func waitForValidator(deployer *subnet.PublicDeployer, subnetID ids.ID, nodeID ids.NodeID, start time.Time) error {
+	ux.Logger.PrintToUser("Waiting for validator %s to start validating at %s...", nodeID, ux.FormatTime(start, time.Now()))
+	return deployer.WaitForValidator(subnetID, nodeID, start, func(status subnet.ValidatorStatus) {
+		ux.Logger.PrintToUser("Validator %s is now %s", nodeID, status)
+	})
+}
+
+func addValidatorsFromFile(subnetName string, network models.Network, subnetID ids.ID, clock subnet.NetworkClock) error {
+	now := clock.Now()
+	validators, err := subnet.LoadValidatorsFile(validatorsFile, now)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+
+	ux.Logger.PrintToUser("Issuing transactions to add %d validators...", len(validators))
+	header := []string{"Line", "NodeID", "Weight", "Start", "End", "Result"}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network)
+	added := []subnet.ValidatorEntry{}
+	rows := [][]string{}
+	failed, err := deployer.AddValidators(subnetID, validators, validatorsParallelism, func(u subnet.ValidatorTxUpdate) {
+		v := u.Validator
+		if !u.Final() {
+			ux.Logger.PrintToUser("line %d, %s: %s tx %s", v.Line, v.NodeID, u.Status, u.TxID)
+			return
+		}
+		result := "tx " + u.TxID.String()
+		if u.Status == subnet.ValidatorTxFailed {
+			result = "FAILED: " + u.Err.Error()
+			ux.Logger.PrintToUser("line %d, %s: failed: %s", v.Line, v.NodeID, u.Err)
+		} else {
+			added = append(added, v)
+			ux.Logger.PrintToUser("line %d, %s: added", v.Line, v.NodeID)
+			notifyValidatorAdded(subnetName, network, v.NodeID, v.Weight, v.Start, v.Duration)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(v.Line),
+			v.NodeID.String(),
+			strconv.FormatUint(v.Weight, 10),
+			ux.FormatTime(v.Start, now),
+			ux.FormatTime(v.Start.Add(v.Duration), now),
+			result,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	// the transactions complete in any order, the table follows the file
+	sort.SliceStable(rows, func(i, j int) bool {
+		li, _ := strconv.Atoi(rows[i][0])
+		lj, _ := strconv.Atoi(rows[j][0])
+		return li < lj
+	})
+	table.AppendBulk(rows)
+	table.Render()
+	if waitValidator {
+		// validators starting first are waited for first
+		sort.SliceStable(added, func(i, j int) bool {
+			return added[i].Start.Before(added[j].Start)
+		})
+		for _, v := range added {
+			if err := waitForValidator(deployer, subnetID, v.NodeID, v.Start); err != nil {
+				return err
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d validators failed to be added", failed, len(validators))
+	}
+	ux.Logger.PrintToUser("All %d validators added", len(validators))
+	return nil
+}
+
+// notifyValidatorAdded posts the validator added to the webhooks
+func notifyValidatorAdded(subnetName string, network models.Network, nodeID ids.NodeID, weight uint64, start time.Time, duration time.Duration) {
+	webhooks.Notify(app, webhooks.Event{
+		Type:    webhooks.ValidatorAdded,
+		Subnet:  subnetName,
+		Network: network.String(),
+		Details: map[string]string{
+			"nodeID": nodeID.String(),
+			"weight": strconv.FormatUint(weight, 10),
+			"start":  start.UTC().Format(time.RFC3339),
+			"end":    start.Add(duration).UTC().Format(time.RFC3339),
+		},
+	})
 }
 
 func promptDuration(start time.Time) (time.Duration, error) {
@@ -154,7 +328,7 @@ func promptDuration(start time.Time) (time.Duration, error) {
 			return 0, err
 		}
 		end := start.Add(d)
-		confirm := fmt.Sprintf("Your validator will finish staking by %s", end.Format(constants.TimeParseLayout))
+		confirm := fmt.Sprintf("Your validator will finish staking by %s", ux.FormatTime(end, time.Now()))
 		yes, err := app.Prompt.CaptureYesNo(confirm)
 		if err != nil {
 			return 0, err
@@ -165,9 +339,37 @@ func promptDuration(start time.Time) (time.Duration, error) {
 	}
 }
 
-func promptStart() (time.Time, error) {
-	txt := "When should the validator start validating? Enter a date in 'YYYY-MM-DD HH:MM:SS' format"
-	return app.Prompt.CaptureDate(txt)
+func promptStart(clock subnet.NetworkClock) (time.Time, error) {
+	txt := fmt.Sprintf(
+		"When should the validator start validating? Enter a time relative to now, e.g. 'in 10 minutes', or a date in 'YYYY-MM-DD HH:MM:SS' format in your timezone (%s)",
+		time.Now().Format("MST"),
+	)
+	return app.Prompt.CaptureDate(txt, prompts.WithClock(clock.Now))
+}
+
+// networkClock returns the clock of network, warning if the local clock is
+// off from it: the network rejects the validators starting before its own
+// time, so start times near now computed from a skewed clock fail. The local
+// clock is used if it is close enough, or if the network can't tell its time.
+func networkClock(network models.Network) subnet.NetworkClock {
+	api, err := app.GetAPIEndpoint(network)
+	if err != nil {
+		app.Log.Warn("not checking the local clock: %s", err)
+		return subnet.NetworkClock{}
+	}
+	skew, err := subnet.MeasureClockSkew(context.Background(), api)
+	if err != nil {
+		app.Log.Warn("not checking the local clock: %s", err)
+		return subnet.NetworkClock{}
+	}
+	clock := subnet.NetworkClock{Skew: skew}
+	if !clock.Skewed() {
+		app.Log.Debug("the local clock is %s %s", clock.Describe(), network)
+		return subnet.NetworkClock{}
+	}
+	ux.Logger.PrintToUser("Warning: your clock is %s %s, the start times are computed from the time of %s. Sync your clock with NTP to fix it.",
+		clock.Describe(), network, network)
+	return clock
 }
 
 func promptNodeID() (ids.NodeID, error) {
@@ -181,6 +383,10 @@ func promptWeight() (uint64, error) {
 }
 
 func captureKeyName() (string, error) {
+	// the profile in use signs with its key
+	if boundKey := app.Conf.BoundKey(); boundKey != "" {
+		return boundKey, nil
+	}
 	files, err := os.ReadDir(app.GetKeyDir())
 	if err != nil {
 		return "", err
@@ -191,6 +397,8 @@ func captureKeyName() (string, error) {
 	for i, f := range files {
 		if strings.HasSuffix(f.Name(), constants.KeySuffix) {
 			keys[i] = strings.TrimSuffix(f.Name(), constants.KeySuffix)
+		} else if strings.HasSuffix(f.Name(), constants.RemoteKeySuffix) {
+			keys[i] = strings.TrimSuffix(f.Name(), constants.RemoteKeySuffix)
 		}
 	}
 
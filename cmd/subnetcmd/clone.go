@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cloneBlockchainID string
+	cloneNetwork      string
+	cloneSkipDeploy   bool
+)
+
+// avalanche subnet clone
+func newCloneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone [subnetName]",
+		Short: "Copy a live blockchain to the local network",
+		Long: `The subnet clone command fetches the genesis of a blockchain deployed
+on Fuji or Mainnet from the P-Chain, stores it as a new subnet configuration,
+and deploys an identical chain to the local network. Use it to debug
+production issues against the real chain parameters.
+
+The subnet name defaults to the name the blockchain was created with. The
+original deployment is recorded in the new configuration, so subnet verify
+can compare the copy against it.
+
+Local networks derive the VM ID from the VM name, so a blockchain whose VM
+ID was not derived from its name is cloned with a local VM ID.`,
+		RunE:         cloneSubnet,
+		Args:         cobra.RangeArgs(0, 1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&cloneBlockchainID, "blockchain-id", "", "ID of the blockchain to clone")
+	cmd.Flags().StringVar(&cloneNetwork, "network", "fuji", "network the blockchain is deployed to [fuji, mainnet]")
+	cmd.Flags().BoolVar(&useSubnetEvm, "evm", false, "the blockchain uses the SubnetEVM")
+	cmd.Flags().BoolVar(&useCustom, "custom", false, "the blockchain uses a custom VM")
+	cmd.Flags().BoolVar(&cloneSkipDeploy, "skip-deploy", false, "only create the subnet configuration, don't deploy it locally")
+	cmd.Flags().BoolVarP(&forceCreate, forceFlag, "f", false, "overwrite the existing configuration if one exists")
+	return cmd
+}
+
+func cloneSubnet(cmd *cobra.Command, args []string) error {
+	if cloneBlockchainID == "" {
+		return exitcodes.UserInput(errors.New("the --blockchain-id flag is required"))
+	}
+	blockchainID, err := ids.FromString(cloneBlockchainID)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("invalid blockchain ID %q: %w", cloneBlockchainID, err))
+	}
+	network, err := networkFromFlag("network", cloneNetwork)
+	if err != nil {
+		return err
+	}
+	if moreThanOneVMSelected() {
+		return exitcodes.UserInput(errors.New("too many VMs selected. Provide at most one VM selection flag"))
+	}
+
+	createChainTx, err := subnet.GetDeployedChain(app, network, blockchainID)
+	if err != nil {
+		return err
+	}
+
+	subnetName := createChainTx.ChainName
+	if len(args) > 0 {
+		subnetName = args[0]
+	}
+	if err := checkInvalidSubnetNames(subnetName); err != nil {
+		return exitcodes.UserInput(fmt.Errorf("subnet name %q is invalid, provide another one as argument: %w", subnetName, err))
+	}
+	if app.GenesisExists(subnetName) && !forceCreate {
+		return exitcodes.UserInput(errors.New("configuration already exists. Use --" + forceFlag + " parameter to overwrite"))
+	}
+
+	vmType := getVMFromFlag()
+	if vmType == "" {
+		vmTypeStr, err := app.Prompt.CaptureList(
+			"What VM does the blockchain use?",
+			[]string{subnetEvm, customVM},
+		)
+		if err != nil {
+			return err
+		}
+		vmType = models.VMTypeFromString(vmTypeStr)
+	}
+
+	sc, keptVMID := subnet.CloneSidecar(subnetName, vmType, network, blockchainID, createChainTx)
+	if !keptVMID {
+		localVMID, err := utils.VMID(subnetName)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("VM ID %s is not derived from the chain name, the local chain will use VM ID %s",
+			createChainTx.VMID, localVMID)
+	}
+
+	if err := app.WriteGenesisFile(subnetName, createChainTx.GenesisData); err != nil {
+		return err
+	}
+	if err := app.CreateSidecar(sc); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Cloned blockchain %s from %s as subnet %s", blockchainID, network, subnetName)
+
+	if cloneSkipDeploy {
+		return nil
+	}
+	return deployToLocalNetwork(subnetName, app.GetGenesisPath(subnetName))
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rpcGasCap       uint64
+	rpcTxFeeCap     float64
+	localTxsEnabled bool
+	feeRecipient    string
+)
+
+// avalanche subnet configure
+func newConfigureCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "configure [subnetName]",
+		Short: "Configure the node-level settings of a subnet",
+		Long: `The subnet configure command sets the node-level settings of a subnet-evm
+chain in its chain config, which the nodes of the local network pass to the
+VM when the network starts. Without flags, the current settings are printed.
+
+The RPC of subnet-evm caps the gas of eth_call and eth_estimateGas at 50M, and
+the fee of the transactions it accepts at 100 AVAX. Load tests hitting these
+caps fail at the RPC, so raise them with --rpc-gas-cap and --rpc-tx-fee-cap,
+where 0 means no cap. --local-txs-enabled exempts the transactions sent
+through the RPC of a node from the pricing rules of its transaction pool.
+
+When the genesis sets allowFeeRecipients, --fee-recipient sets the reward
+address the nodes send the fees of the blocks they build to, instead of
+burning them.
+
+The settings of a subnet already deployed to the local network apply once the
+network is restarted with network stop and network start.`,
+		RunE:         configureSubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().Uint64Var(&rpcGasCap, "rpc-gas-cap", 0, "gas cap of eth_call and eth_estimateGas, 0 for no cap")
+	cmd.Flags().Float64Var(&rpcTxFeeCap, "rpc-tx-fee-cap", 0, "fee cap of the transactions sent through the RPC, in AVAX, 0 for no cap")
+	cmd.Flags().BoolVar(&localTxsEnabled, "local-txs-enabled", false, "exempt the transactions sent through the RPC from the pricing rules of the transaction pool")
+	cmd.Flags().StringVar(&feeRecipient, "fee-recipient", "", "reward address receiving the fees of the blocks built by the nodes, if the genesis allows fee recipients")
+	return cmd
+}
+
+func configureSubnet(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("failed loading subnet %s: %w", subnetName, err))
+	}
+	if sc.VM != models.SubnetEvm {
+		return exitcodes.UserInput(fmt.Errorf("%s runs a %s VM, only subnet-evm chains can be configured", subnetName, sc.VM))
+	}
+	chainConfig, err := app.LoadChainConfig(subnetName)
+	if err != nil {
+		return err
+	}
+
+	settings := vm.EvmChainSettings{}
+	if cmd.Flags().Changed("rpc-gas-cap") {
+		settings.RPCGasCap = &rpcGasCap
+	}
+	if cmd.Flags().Changed("rpc-tx-fee-cap") {
+		settings.RPCTxFeeCap = &rpcTxFeeCap
+	}
+	if cmd.Flags().Changed("local-txs-enabled") {
+		settings.LocalTxsEnabled = &localTxsEnabled
+	}
+	if cmd.Flags().Changed("fee-recipient") {
+		if !common.IsHexAddress(feeRecipient) {
+			return exitcodes.UserInput(fmt.Errorf("invalid --fee-recipient %q, must be an address", feeRecipient))
+		}
+		recipient := common.HexToAddress(feeRecipient)
+		settings.FeeRecipient = &recipient
+	}
+	if settings == (vm.EvmChainSettings{}) {
+		return printChainConfig(chainConfig)
+	}
+
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return fmt.Errorf("failed loading the genesis of %s: %w", subnetName, err)
+	}
+	var blockGasLimit *big.Int
+	if genesis.Config != nil {
+		blockGasLimit = genesis.Config.FeeConfig.GasLimit
+	}
+	if settings.FeeRecipient != nil {
+		if genesis.Config == nil {
+			return exitcodes.UserInput(fmt.Errorf("the genesis of %s has no chain config, so it burns the fees", subnetName))
+		}
+		if err := vm.CheckFeeRecipient(*genesis.Config, *settings.FeeRecipient); err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+	warnings, err := settings.Validate(blockGasLimit)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	for _, w := range warnings {
+		ux.Logger.PrintToUser("Warning: %s", w)
+	}
+
+	chainConfig, err = vm.UpdateChainConfig(chainConfig, settings)
+	if err != nil {
+		return fmt.Errorf("failed updating the chain config of %s: %w", subnetName, err)
+	}
+	if err := app.WriteChainConfigFile(subnetName, chainConfig); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Chain config of %s written to %s", subnetName, app.GetChainConfigPath(subnetName))
+	if _, ok := sc.Networks[localNetworkKey()]; ok {
+		ux.Logger.PrintToUser("Restart the local network with network stop and network start to apply it")
+	}
+	return nil
+}
+
+func printChainConfig(chainConfig []byte) error {
+	settings, err := vm.DescribeChainConfig(chainConfig)
+	if err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Setting", "Value"})
+	table.SetRowLine(true)
+	for _, s := range settings {
+		table.Append([]string{s[0], s[1]})
+	}
+	table.Render()
+	return nil
+}
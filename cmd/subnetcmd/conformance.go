@@ -0,0 +1,125 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conformanceRPCURL string
+	conformanceWSURL  string
+)
+
+// avalanche subnet conformance
+func newConformanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conformance [subnetName]",
+		Short: "Check the JSON-RPC API of a deployed chain conforms to Ethereum's",
+		Long: `The subnet conformance command runs a battery of read-only checks of the
+JSON-RPC API of the chain of a subnet against the Ethereum JSON-RPC
+specification: the methods of the eth namespace wallets and tools rely on,
+the EIP-1559 fields and eth_feeHistory, and the newHeads, logs and
+newPendingTransactions subscriptions of the WebSocket endpoint. It reports
+every deviation, and fails if there is any.
+
+The chain running on the local network is checked, unless --rpc-url gives the
+RPC of the chain, e.g. on a public network. The WebSocket endpoint is derived
+from the RPC of an avalanchego node, or else given with --ws-url.
+
+Use it to check a custom VM claiming EVM compatibility works with the
+Ethereum tooling.`,
+		RunE:         runConformance,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&conformanceRPCURL, "rpc-url", "", "RPC URL of the chain (default the one on the local network)")
+	cmd.Flags().StringVar(&conformanceWSURL, "ws-url", "", "WebSocket URL of the chain (default derived from the RPC URL)")
+	return cmd
+}
+
+func runConformance(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	chainID, err := expectedChainID(sc)
+	if err != nil {
+		return err
+	}
+
+	rpcURL := conformanceRPCURL
+	if rpcURL == "" {
+		if rpcURL, err = localRPCURL(sc); err != nil {
+			return exitcodes.UserInput(fmt.Errorf("%w, give the RPC of the chain with --rpc-url", err))
+		}
+	}
+	wsURL := conformanceWSURL
+	if wsURL == "" {
+		wsURL = subnet.WSURLFromRPC(rpcURL)
+	}
+
+	ux.Logger.PrintToUser("Checking the JSON-RPC API of %s at %s...", subnetName, rpcURL)
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	results, err := subnet.RunConformance(ctx, rpcURL, wsURL, chainID)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Result"})
+	table.SetAutoWrapText(false)
+	deviations := 0
+	for _, r := range results {
+		result := "ok"
+		switch {
+		case r.Skipped != "":
+			result = "skipped: " + r.Skipped
+		case r.Deviation != nil:
+			result = "DEVIATION: " + r.Deviation.Error()
+			deviations++
+		}
+		table.Append([]string{r.Check, result})
+	}
+	table.Render()
+	if deviations > 0 {
+		return fmt.Errorf("the JSON-RPC API of %s deviates from the specification in %d of %d checks", subnetName, deviations, len(results))
+	}
+	ux.Logger.PrintToUser("The JSON-RPC API of %s passed all the checks", subnetName)
+	return nil
+}
+
+// expectedChainID returns the chain ID of the chain of sc, from its genesis,
+// or nil if it isn't known
+func expectedChainID(sc models.Sidecar) (*big.Int, error) {
+	if sc.VM == models.SubnetEvm {
+		genesis, err := app.LoadEvmGenesis(sc.Name)
+		if err != nil {
+			return nil, err
+		}
+		if genesis.Config != nil {
+			return genesis.Config.ChainID, nil
+		}
+		return nil, nil
+	}
+	if chainID, ok := new(big.Int).SetString(sc.ChainID, 10); ok {
+		return chainID, nil
+	}
+	return nil, nil
+}
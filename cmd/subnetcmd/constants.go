@@ -8,4 +8,8 @@ const (
 	customVM  = "Custom"
 
 	forceFlag = "force"
+
+	// maxTokenDecimals is the most decimals a native token is displayed
+	// with, the balances being 256 bits integers
+	maxTokenDecimals = 77
 )
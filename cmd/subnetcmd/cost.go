@@ -0,0 +1,123 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	costNetwork      string
+	costValidators   uint64
+	costDurationStr  string
+	costPrimaryStake bool
+
+	// units of the cost duration, besides the ones of Go durations
+	costDurationUnits = map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+		"m": 30 * 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+)
+
+// avalanche subnet cost
+func newCostCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Estimate the AVAX needed to deploy a subnet",
+		Long: `The subnet cost command estimates how much AVAX deploying a subnet to Fuji or
+Mainnet takes, with the current fees of the network: the creation of the
+subnet and of its blockchain, and the addition of its validators.
+
+A validator can't be added to a subnet for longer than the maximum staking
+period, so it is added once for every staking period started within the
+--duration. The duration is given in days (d), weeks (w), months (m) or
+years (y), e.g. 6m, or as a Go duration such as 720h.
+
+With --primary-stake, the minimum stake of the validators on the primary
+network is included too, for validators not validating it yet. The stake
+is locked while validating, and returned afterwards.`,
+		RunE:         subnetCost,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&costNetwork, "network", "fuji", "network to estimate the cost on (fuji, mainnet)")
+	cmd.Flags().Uint64Var(&costValidators, "validators", 1, "number of validators of the subnet")
+	cmd.Flags().StringVar(&costDurationStr, "duration", "1y", "how long the validators validate the subnet")
+	cmd.Flags().BoolVar(&costPrimaryStake, "primary-stake", false, "include the stake of the validators on the primary network")
+	return cmd
+}
+
+// parseCostDuration parses a duration in days, weeks, months or years, or
+// a Go duration
+func parseCostDuration(s string) (time.Duration, error) {
+	for suffix, unit := range costDurationUnits {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSuffix(s, suffix), 10, 32)
+		if err != nil {
+			break
+		}
+		return time.Duration(n) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 30d, 6m or 1y", s)
+	}
+	return d, nil
+}
+
+func subnetCost(cmd *cobra.Command, args []string) error {
+	network, err := networkFromFlag("network", costNetwork)
+	if err != nil {
+		return err
+	}
+	if network == models.Local {
+		return exitcodes.UserInput(fmt.Errorf("deploying to the local network is free, use --network fuji or mainnet"))
+	}
+	duration, err := parseCostDuration(costDurationStr)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if duration <= 0 {
+		return exitcodes.UserInput(fmt.Errorf("--duration must be positive"))
+	}
+
+	fees, err := subnet.GetFeeParams(app, network)
+	if err != nil {
+		return err
+	}
+	cost := subnet.EstimateCost(fees, costValidators, duration, costPrimaryStake)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Item", "Count", "AVAX each", "AVAX"})
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.Append([]string{"Subnet creation", "1", subnet.FormatAVAX(fees.CreateSubnetTxFee), subnet.FormatAVAX(cost.CreateSubnet)})
+	table.Append([]string{"Blockchain creation", "1", subnet.FormatAVAX(fees.CreateBlockchainTxFee), subnet.FormatAVAX(cost.CreateBlockchain)})
+	table.Append([]string{"Validator additions", strconv.FormatUint(cost.ValidatorTxs, 10), subnet.FormatAVAX(fees.TxFee), subnet.FormatAVAX(cost.AddValidators)})
+	if costPrimaryStake {
+		table.Append([]string{"Primary network stake", strconv.FormatUint(costValidators, 10), subnet.FormatAVAX(fees.MinValidatorStake), subnet.FormatAVAX(cost.PrimaryStake)})
+	}
+	table.SetFooter([]string{"Total", "", "", subnet.FormatAVAX(cost.Total())})
+	table.Render()
+
+	ux.Logger.PrintToUser("Deploying to %s with %d validators for %s takes %s AVAX in fees.",
+		network, costValidators, costDurationStr, subnet.FormatAVAX(cost.Fees()))
+	if costPrimaryStake {
+		ux.Logger.PrintToUser("%s AVAX more are staked on the primary network, and returned once the validators stop validating.", subnet.FormatAVAX(cost.PrimaryStake))
+	}
+	return nil
+}
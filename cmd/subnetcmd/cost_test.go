@@ -0,0 +1,31 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCostDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	day := 24 * time.Hour
+	for s, expected := range map[string]time.Duration{
+		"30d":  30 * day,
+		"2w":   14 * day,
+		"6m":   180 * day,
+		"1y":   365 * day,
+		"720h": 30 * day,
+	} {
+		d, err := parseCostDuration(s)
+		assert.NoError(err, s)
+		assert.Equal(expected, d, s)
+	}
+	for _, s := range []string{"", "m", "-1d", "6 months"} {
+		_, err := parseCostDuration(s)
+		assert.Error(err, s)
+	}
+}
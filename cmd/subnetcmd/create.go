@@ -3,24 +3,48 @@
 package subnetcmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"unicode"
 
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/genesis"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 )
 
 var (
-	forceCreate  bool
-	useSubnetEvm bool
-	filename     string
-	useCustom    bool
+	forceCreate      bool
+	useSubnetEvm     bool
+	filename         string
+	useCustom        bool
+	vmAlias          string
+	vmIDOverride     string
+	tokenDecimals    int
+	forkFlags        map[string]string
+	burnFees         bool
+	feeRecipientFlag string
+	recordFile       string
+	replayFile       string
+	hardwareProfile  string
 
 	errIllegalNameCharacter = errors.New(
 		"illegal name character: only letters, no special characters allowed")
+	errForkNotEvm       = errors.New("--fork only applies to the genesis created for the subnet EVM")
+	errFeesNotEvm       = errors.New("--burn-fees and --fee-recipient only apply to the genesis created for the subnet EVM")
+	errSessionNotWizard = errors.New("--record and --replay only apply to the wizard, not to --file")
+	errHardwareNotEvm   = errors.New("--hardware-profile only applies to subnet EVM chains")
 )
 
 // avalanche subnet create
@@ -38,16 +62,60 @@ a file path with the --file flag. As more subnets reach maturity, you'll be
 able to use this tool to generate additional VM templates, such as the
 SpacesVM.
 
+So that pipelines don't need temporary files, --file also takes an http(s)
+URL to download the genesis from, served as JSON or plain text, or - to
+read it from stdin, along with --evm or --custom. Genesis files read from
+stdin or downloaded are limited to 10 MiB.
+
 By default, running the command with a subnetName that already exists will
 cause the command to fail. If you’d like to overwrite an existing
-configuration, pass the -f flag.`,
+configuration, pass the -f flag.
+
+The VM ID of the subnet is derived from its name, or from the --vm-alias
+flag if given. To match a blockchain created outside of the CLI, the VM ID
+can also be set explicitly with the --vm-id flag.
+
+The amounts of the native token are displayed with 18 decimals, as counted
+in wei, unless set otherwise with the --token-decimals flag.
+
+All the Ethereum hard forks subnet EVM supports are active at genesis,
+unless customized in the wizard or with the --fork flag, e.g.
+--fork byzantium=off to disable Byzantium and the following forks, for
+compatibility testing. Subnet EVM only activates Ethereum hard forks at
+genesis, while its own fork can be delayed to a unix timestamp with
+--fork subnetevm=1672531200.
+
+The wizard asks where the transaction fees go: burned, or sent by the nodes
+to the reward address of their chain config, which the genesis then allows.
+--burn-fees or --fee-recipient decide it without the prompt.
+
+The fees can be sized for the machines the validators run on, with the gas
+limit and target gas they keep up with: standard for 8 vCPU / 16 GB, or
+performance for 16 vCPU / 32 GB. Pick the profile in the wizard, or with
+--hardware-profile, which also records it for a subnet EVM genesis given
+with --file. subnet lint then warns about fees exceeding the profile.
+
+The answers given in the wizard can be saved with --record answers.json,
+and given again with --replay answers.json, e.g. to change a single value
+of the genesis without going through the whole wizard again: edit it in
+the answers file, or remove it to be asked for it. The prompts without an
+answer in the file are asked as usual.`,
 		Args: cobra.ExactArgs(1),
 		RunE: createGenesis,
 	}
-	cmd.Flags().StringVar(&filename, "file", "", "file path of genesis to use instead of the wizard")
+	cmd.Flags().StringVar(&filename, "file", "", "file path or http(s) URL of the genesis to use instead of the wizard, - to read it from stdin")
 	cmd.Flags().BoolVar(&useSubnetEvm, "evm", false, "use the SubnetEVM as the base template")
 	cmd.Flags().BoolVar(&useCustom, "custom", false, "use a custom VM template")
 	cmd.Flags().BoolVarP(&forceCreate, forceFlag, "f", false, "overwrite the existing configuration if one exists")
+	cmd.Flags().StringVar(&vmAlias, "vm-alias", "", "register the VM under this name instead of the subnet name")
+	cmd.Flags().StringVar(&vmIDOverride, "vm-id", "", "use this VM ID instead of deriving it from the VM name")
+	cmd.Flags().IntVar(&tokenDecimals, "token-decimals", constants.DefaultTokenDecimals, "number of decimals the native token amounts are displayed with")
+	cmd.Flags().BoolVar(&burnFees, "burn-fees", false, "burn the transaction fees of the chain")
+	cmd.Flags().StringVar(&feeRecipientFlag, "fee-recipient", "", "reward address the nodes send the transaction fees of the chain to")
+	cmd.Flags().StringVar(&hardwareProfile, "hardware-profile", "", "size the fees for the validator hardware, one of "+strings.Join(vm.HardwareProfileNames(), ", "))
+	cmd.Flags().StringVar(&recordFile, "record", "", "save the answers given in the wizard to this file")
+	cmd.Flags().StringVar(&replayFile, "replay", "", "answer the wizard with the answers saved in this file")
+	cmd.Flags().StringToStringVar(&forkFlags, "fork", nil, fmt.Sprintf("enable a fork with name=%s or disable it with name=%s, or set the subnetevm fork timestamp (forks: %s)", vm.ForkEnabled, vm.ForkDisabled, strings.Join(vm.ForkNames(), ", ")))
 	return cmd
 }
 
@@ -77,20 +145,62 @@ func getVMFromFlag() models.VMType {
 func createGenesis(cmd *cobra.Command, args []string) error {
 	subnetName := args[0]
 	if app.GenesisExists(subnetName) && !forceCreate {
-		return errors.New("configuration already exists. Use --" + forceFlag + " parameter to overwrite")
+		return exitcodes.UserInput(errors.New("configuration already exists. Use --" + forceFlag + " parameter to overwrite"))
 	}
 
 	if err := checkInvalidSubnetNames(subnetName); err != nil {
-		return fmt.Errorf("subnet name %q is invalid: %w", subnetName, err)
+		return exitcodes.UserInput(fmt.Errorf("subnet name %q is invalid: %w", subnetName, err))
 	}
 
 	if moreThanOneVMSelected() {
-		return errors.New("too many VMs selected. Provide at most one VM selection flag")
+		return exitcodes.UserInput(errors.New("too many VMs selected. Provide at most one VM selection flag"))
+	}
+
+	if err := checkVMIdentity(); err != nil {
+		return exitcodes.UserInput(err)
+	}
+
+	if tokenDecimals < 0 || tokenDecimals > maxTokenDecimals {
+		return exitcodes.UserInput(fmt.Errorf("--token-decimals must be between 0 and %d", maxTokenDecimals))
+	}
+
+	forks, err := vm.ParseForkActivations(forkFlags)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("invalid --fork: %w", err))
+	}
+	if len(forks) > 0 && (filename != "" || useCustom) {
+		return exitcodes.UserInput(errForkNotEvm)
+	}
+	fees, err := feeDestinationFromFlags()
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if fees != nil && (filename != "" || useCustom) {
+		return exitcodes.UserInput(errFeesNotEvm)
+	}
+	if hardwareProfile != "" {
+		if _, err := vm.GetHardwareProfile(hardwareProfile); err != nil {
+			return exitcodes.UserInput(fmt.Errorf("invalid --hardware-profile: %w", err))
+		}
+		if useCustom {
+			return exitcodes.UserInput(errHardwareNotEvm)
+		}
+	}
+	if (recordFile != "" || replayFile != "") && filename != "" {
+		return exitcodes.UserInput(errSessionNotWizard)
 	}
 
 	if filename == "" {
+		prompt := app.Prompt
+		defer func() {
+			app.Prompt = prompt
+		}()
+		replayer, recorder, err := startWizardSession()
+		if err != nil {
+			return err
+		}
+
 		var subnetType models.VMType
-		var err error
 		subnetType = getVMFromFlag()
 
 		if subnetType == "" {
@@ -111,18 +221,50 @@ func createGenesis(cmd *cobra.Command, args []string) error {
 
 		switch subnetType {
 		case subnetEvm:
-			genesisBytes, sc, err = vm.CreateEvmGenesis(subnetName, app)
+			var settings vm.EvmChainSettings
+			genesisBytes, sc, settings, err = vm.CreateEvmGenesis(subnetName, app, forks, fees, hardwareProfile)
 			if err != nil {
 				return err
 			}
+			if sc.FundedKeys, err = vm.FundedKeys(app, genesisBytes); err != nil {
+				return err
+			}
+			setVMIdentity(sc)
+			setTokenDecimals(cmd, sc)
 			if err = app.CreateSidecar(sc); err != nil {
 				return err
 			}
+			if settings != (vm.EvmChainSettings{}) {
+				chainConfig, err := vm.UpdateChainConfig(nil, settings)
+				if err != nil {
+					return err
+				}
+				if err := app.WriteChainConfigFile(subnetName, chainConfig); err != nil {
+					return err
+				}
+			}
 		case customVM:
+			if len(forks) > 0 {
+				return exitcodes.UserInput(errForkNotEvm)
+			}
+			if fees != nil {
+				return exitcodes.UserInput(errFeesNotEvm)
+			}
+			if hardwareProfile != "" {
+				return exitcodes.UserInput(errHardwareNotEvm)
+			}
 			genesisBytes, sc, err = vm.CreateCustomGenesis(subnetName, app)
 			if err != nil {
 				return err
 			}
+			setVMIdentity(sc)
+			if !genesis.IsTemplate(genesisBytes) {
+				genesisBytes, err = checkCustomGenesis(sc.GetVMName(), genesisBytes, app.Prompt, false)
+				if err != nil {
+					return err
+				}
+			}
+			setTokenDecimals(cmd, sc)
 			if err = app.CreateSidecar(sc); err != nil {
 				return err
 			}
@@ -134,16 +276,20 @@ func createGenesis(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		ux.Logger.PrintToUser("Successfully created genesis")
+		return finishWizardSession(replayer, recorder)
 	} else {
-		ux.Logger.PrintToUser("Using specified genesis")
-		err := app.CopyGenesisFile(filename, subnetName)
-		if err != nil {
-			return err
-		}
-
 		var subnetType models.VMType
 		subnetType = getVMFromFlag()
+		if subnetType == "" && filename == genesis.StdinSource {
+			// the prompts would read their answers from the genesis
+			return exitcodes.UserInput(errors.New("the VM of a genesis read from stdin must be given with --evm or --custom"))
+		}
 
+		ux.Logger.PrintToUser("Using specified genesis")
+		genesisBytes, err := genesis.ReadSource(filename)
+		if err != nil {
+			return exitcodes.UserInput(fmt.Errorf("failed reading the genesis: %w", err))
+		}
 		if subnetType == "" {
 			subnetTypeStr, err := app.Prompt.CaptureList(
 				"What VM does your genesis use?",
@@ -154,13 +300,33 @@ func createGenesis(cmd *cobra.Command, args []string) error {
 			}
 			subnetType = models.VMTypeFromString(subnetTypeStr)
 		}
+		if hardwareProfile != "" && subnetType != models.SubnetEvm {
+			return exitcodes.UserInput(errHardwareNotEvm)
+		}
 		sc := &models.Sidecar{
-			Name:      subnetName,
-			VM:        subnetType,
-			Subnet:    subnetName,
-			TokenName: "",
+			Name:            subnetName,
+			VM:              subnetType,
+			Subnet:          subnetName,
+			TokenName:       "",
+			HardwareProfile: hardwareProfile,
 		}
+		setVMIdentity(sc)
+		setTokenDecimals(cmd, sc)
 
+		if subnetType == models.CustomVM && !genesis.IsTemplate(genesisBytes) {
+			// the answers of the prompts would be read from the genesis
+			var prompt prompts.Prompter
+			if filename != genesis.StdinSource {
+				prompt = app.Prompt
+			}
+			genesisBytes, err = checkCustomGenesis(sc.GetVMName(), genesisBytes, prompt, false)
+			if err != nil {
+				return err
+			}
+		}
+		if err := app.WriteGenesisFile(subnetName, genesisBytes); err != nil {
+			return err
+		}
 		if err = app.CreateSidecar(sc); err != nil {
 			return err
 		}
@@ -169,6 +335,100 @@ func createGenesis(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkVMIdentity validates the VM alias and VM ID flags
+func checkVMIdentity() error {
+	if vmAlias != "" {
+		if _, err := utils.VMID(vmAlias); err != nil {
+			return fmt.Errorf("VM alias %q is invalid: %w", vmAlias, err)
+		}
+	}
+	if vmIDOverride != "" {
+		if _, err := ids.FromString(vmIDOverride); err != nil {
+			return fmt.Errorf("VM ID %q is invalid: %w", vmIDOverride, err)
+		}
+	}
+	return nil
+}
+
+// feeDestinationFromFlags returns where the fees go as decided by
+// --burn-fees or --fee-recipient, nil to ask in the wizard
+func feeDestinationFromFlags() (*vm.FeeDestination, error) {
+	switch {
+	case burnFees && feeRecipientFlag != "":
+		return nil, errors.New("--burn-fees and --fee-recipient are exclusive")
+	case burnFees:
+		return &vm.FeeDestination{}, nil
+	case feeRecipientFlag != "":
+		if !common.IsHexAddress(feeRecipientFlag) {
+			return nil, fmt.Errorf("invalid --fee-recipient %q, must be an address", feeRecipientFlag)
+		}
+		recipient := common.HexToAddress(feeRecipientFlag)
+		return &vm.FeeDestination{Recipient: &recipient}, nil
+	}
+	return nil, nil
+}
+
+// startWizardSession has app.Prompt replay the answers of --replay, and
+// record the answers for --record
+func startWizardSession() (*prompts.Replayer, *prompts.Recorder, error) {
+	var (
+		replayer *prompts.Replayer
+		recorder *prompts.Recorder
+	)
+	if replayFile != "" {
+		answers, err := prompts.LoadAnswers(replayFile)
+		if err != nil {
+			return nil, nil, exitcodes.UserInput(fmt.Errorf("failed reading --replay: %w", err))
+		}
+		replayer = prompts.NewReplayer(app.Prompt, answers)
+		app.Prompt = replayer
+	}
+	if recordFile != "" {
+		if err := app.CheckWritable("record the answers to " + recordFile); err != nil {
+			return nil, nil, err
+		}
+		recorder = prompts.NewRecorder(app.Prompt)
+		app.Prompt = recorder
+	}
+	return replayer, recorder, nil
+}
+
+// finishWizardSession reports the replayed answers the wizard didn't ask
+// for, and writes the recorded answers
+func finishWizardSession(replayer *prompts.Replayer, recorder *prompts.Recorder) error {
+	if replayer != nil {
+		for _, answer := range replayer.Unreplayed() {
+			ux.Logger.PrintToUser("Not asked %q, its answer %q in %s was left out", answer.Prompt, answer.Answer, replayFile)
+		}
+	}
+	if recorder == nil {
+		return nil
+	}
+	answersBytes, err := json.MarshalIndent(recorder.Answers(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(recordFile, answersBytes, application.WriteReadReadPerms); err != nil {
+		return fmt.Errorf("failed writing the answers: %w", err)
+	}
+	ux.Logger.PrintToUser("Answers saved to %s, replay them with --replay %s", recordFile, recordFile)
+	return nil
+}
+
+// setVMIdentity records the VM alias and VM ID flags in the sidecar
+func setVMIdentity(sc *models.Sidecar) {
+	sc.VMAlias = vmAlias
+	sc.VMID = vmIDOverride
+}
+
+// setTokenDecimals records the token decimals flag in the sidecar, if given
+func setTokenDecimals(cmd *cobra.Command, sc *models.Sidecar) {
+	if cmd.Flags().Changed("token-decimals") {
+		decimals := tokenDecimals
+		sc.TokenDecimals = &decimals
+	}
+}
+
 func checkInvalidSubnetNames(name string) error {
 	// this is currently exactly the same code as in avalanchego/vms/platformvm/create_chain_tx.go
 	for _, r := range name {
@@ -5,6 +5,7 @@ package subnetcmd
 import (
 	"testing"
 
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,3 +56,33 @@ func Test_moreThanOneVMSelected(t *testing.T) {
 		})
 	}
 }
+
+func Test_feeDestinationFromFlags(t *testing.T) {
+	assert := assert.New(t)
+	defer func() {
+		burnFees = false
+		feeRecipientFlag = ""
+	}()
+
+	fees, err := feeDestinationFromFlags()
+	assert.NoError(err)
+	assert.Nil(fees)
+
+	burnFees = true
+	fees, err = feeDestinationFromFlags()
+	assert.NoError(err)
+	assert.Equal(&vm.FeeDestination{}, fees)
+
+	feeRecipientFlag = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+	_, err = feeDestinationFromFlags()
+	assert.Error(err)
+
+	burnFees = false
+	fees, err = feeDestinationFromFlags()
+	assert.NoError(err)
+	assert.Equal("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", fees.Recipient.Hex())
+
+	feeRecipientFlag = "treasury"
+	_, err = feeDestinationFromFlags()
+	assert.Error(err)
+}
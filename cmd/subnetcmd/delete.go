@@ -3,6 +3,7 @@
 package subnetcmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -12,11 +13,14 @@ import (
 // avalanche subnet delete
 func newDeleteCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "delete",
+		Use:   "delete [subnetName]",
 		Short: "Delete a subnet configuration",
-		Long:  "The subnet delete command deletes an existing subnet configuration.",
-		RunE:  deleteGenesis,
-		Args:  cobra.ExactArgs(1),
+		Long: `The subnet delete command deletes an existing subnet configuration.
+
+It asks to type the name of the subnet to confirm, unless --yes is given.`,
+		RunE:         deleteGenesis,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
 	}
 }
 
@@ -25,10 +29,23 @@ func deleteGenesis(cmd *cobra.Command, args []string) error {
 	sidecar := app.GetSidecarPath(args[0])
 	genesis := app.GetGenesisPath(args[0])
 
-	if _, err := os.Stat(genesis); err == nil {
-		// exists
-		os.Remove(genesis)
-	} else {
+	if _, err := os.Stat(genesis); err != nil {
+		return err
+	}
+	if err := app.Prompt.ConfirmDestructive(
+		fmt.Sprintf("This deletes the configuration and genesis history of subnet %s.", args[0]),
+		args[0],
+	); err != nil {
+		return err
+	}
+	os.Remove(genesis)
+
+	// subnets only have a chain config once configured
+	if err := os.Remove(app.GetChainConfigPath(args[0])); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.RemoveAll(app.GetHistoryDir(args[0])); err != nil {
 		return err
 	}
 
@@ -8,19 +8,49 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/lock"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanche-cli/pkg/webhooks"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/spf13/cobra"
 )
 
 var (
 	deployLocal bool
 	keyName     string
+	genesisVars map[string]string
+	verbose     bool
+	vmSourceStr string
+	nodeProfile string
+	reportPath  string
+	// vmBinary is the VM plugin binary the validators of a public deploy
+	// install, to record its SHA-256
+	vmBinary string
+	// deployEnvironment is the project environment deployed, if any
+	deployEnvironment string
+	// promotedFrom is the environment whose genesis is promoted to
+	// deployEnvironment, if any
+	promotedFrom string
+	// vmSource is the source the VM is built from, if set by --vm-source
+	vmSource *binutils.VMSource
+	// followLogs streams the logs of the nodes of a local network started by
+	// the deploy
+	followLogs bool
+	// noConfirm downloads the bootstrap snapshot without asking on a metered
+	// connection
+	noConfirm bool
 )
 
 // avalanche subnet deploy
@@ -36,18 +66,91 @@ At the end of the call, the command will print the RPC URL you can use
 to interact with the subnet.
 
 Subnets may only be deployed once. Subsequent calls of deploy to the
-same network (local, Fuji, Mainnet) are not allowed. If you'd like to
+same network (local, Fuji, Mainnet) are not allowed, except locally, where
+they leave the deployment as is and print its endpoints again. If you'd like to
 redeploy a subnet locally for testing, you must first call avalanche
 network clean to reset all deployed chain state. Subsequent local
 deploys will redeploy the chain with fresh state. The same subnet can
 be deployed to multiple networks, so you can take your locally tested
-subnet and deploy it on Fuji or Mainnet.`,
+subnet and deploy it on Fuji or Mainnet.
+
+On Fuji and Mainnet, the control keys of the subnet are entered as P-Chain
+addresses, or picked among the local keys, listed with their address on the
+target network. Before anything else, the deploy checks that the signing key
+has the fees unlocked on the P-Chain, and otherwise prints the address to
+fund and the missing amount.
+
+If a project config (` + constants.ProjectConfigFileName + `) is found in the working directory
+or any of its parents, the subnet name, network and key default to the ones
+set in it.
+
+If the genesis is a template, its variables are resolved with the values the
+project config sets for the network under genesis-vars, overridden by
+--genesis-var. Use subnet render to preview the resolved genesis.
+
+With --environment, the deploy targets an environment of the project config,
+deploying to its network with its key and genesis variables by default, and
+records the environment along with the hash of the genesis deployed, for
+subnet promote to deploy the same genesis to the next environment.
+
+With --unsigned, the transactions are only built, to be signed separately,
+e.g. on air-gapped machines, with transaction sign and broadcast with
+transaction broadcast. The first run builds the transaction creating the
+subnet, and once it is broadcast, a second run builds the one creating the
+blockchain.
+
+To test unreleased VM fixes locally, --vm-source builds the VM plugin from a
+git ref of its repository instead of downloading a release, e.g.
+--vm-source github.com/ava-labs/subnet-evm@<commit>. The plugin is built from
+the plugin directory of the repository, if any, or from its root, unless
+given as <repository>//<package>@<ref>. Builds are kept by commit.
+
+With --node-profile fast-dev, a local network started by the deploy runs with
+consensus and networking settings tuned for a local network, so that it is
+healthy in seconds. The node-profile key of the config file sets the profile
+by default. It has no effect on a local network already running.
+
+With --follow-logs, the log lines of the nodes of a local network started by
+the deploy are printed as they are written, prefixed with the name of their
+node, instead of a progress bar, to follow the bootstrap and see its errors.
+
+Before downloading the bootstrap snapshot, the deploy prints its size and
+how long the download should take, then how long the network should take
+to bootstrap on this machine. On a metered connection, set with the metered
+key of the config file or AVALANCHE_METERED, it asks before downloading,
+unless --no-confirm is given.
+
+Local deploys print how long each of their phases took, to tell slow
+downloads from slow consensus. --report also writes it as JSON, along with
+the IDs of the deployed subnet and blockchain.
+
+The SHA-256 of the VM plugin binary deployed is recorded with the deploy, for
+subnet verify-binary to check the validators run the same one. Locally, it is
+the plugin installed, and on public networks, the subnet-evm release the CLI
+installs. The validators of other VMs install a binary the CLI doesn't know,
+given with --vm-binary to record it.
+
+With --async, a local deploy runs in the background, with its output in a
+log file, and the command returns right away with a tracking ID. subnet
+deploy-status prints how the deploy is going, and network wait --subnet
+blocks until the deployed blockchain is ready.`,
 		SilenceUsage: true,
 		RunE:         deploySubnet,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.RangeArgs(0, 1),
 	}
 	cmd.Flags().BoolVarP(&deployLocal, "local", "l", false, "deploy to a local network")
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use for fuji deploys")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "print the exact amounts of the funded addresses, in wei")
+	cmd.Flags().StringVar(&vmSourceStr, "vm-source", "", "build the VM from a git ref of its repository, as <repository>[//<package>]@<ref>, for local deploys")
+	cmd.Flags().StringVar(&nodeProfile, "node-profile", "", "node profile of a local network started by the deploy, one of "+strings.Join(config.NodeProfileNames(), ", "))
+	cmd.Flags().BoolVar(&followLogs, "follow-logs", false, "print the log lines of the nodes while a local network starts")
+	cmd.Flags().BoolVar(&noConfirm, "no-confirm", false, "download the bootstrap snapshot without asking on a metered connection")
+	cmd.Flags().StringVar(&reportPath, "report", "", "file to write the report of a local deploy to, as JSON")
+	cmd.Flags().StringVar(&vmBinary, "vm-binary", "", "VM plugin binary the validators install, to record its SHA-256, for public deploys")
+	cmd.Flags().StringVarP(&deployEnvironment, "environment", "e", "", "project environment to deploy to, setting the network, the key and the genesis variables")
+	addGenesisVarFlag(cmd)
+	addUnsignedFlags(cmd)
+	addAsyncFlags(cmd)
 	return cmd
 }
 
@@ -83,59 +186,159 @@ func getChainsInSubnet(subnetName string) ([]string, error) {
 
 // deploySubnet is the cobra command run for deploying subnets
 func deploySubnet(cmd *cobra.Command, args []string) error {
+	if trackingID != "" {
+		// this is the deploy running in the background, which may still
+		// see --async set in its environment
+		id := trackingID
+		trackingID, deployAsync = "", false
+		return runTrackedDeploy(id, func() error {
+			return deploySubnet(cmd, args)
+		})
+	}
 	chains, err := validateSubnetNameAndGetChains(args)
 	if err != nil {
 		return err
 	}
 
+	if vmSourceStr != "" {
+		source, err := binutils.ParseVMSource(vmSourceStr)
+		if err != nil {
+			return exitcodes.UserInput(err)
+		}
+		vmSource = &source
+	}
+	if nodeProfile != "" {
+		app.Conf.SetNodeProfile(nodeProfile)
+		if err := app.Conf.CheckNodeProfile(); err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+
 	// get the network to deploy to
 	var network models.Network
-	if deployLocal {
+	if deployEnvironment != "" {
+		network, err = applyEnvironment(deployEnvironment)
+		if err != nil {
+			return err
+		}
+	} else if deployLocal {
 		network = models.Local
+	} else if defaultNetwork := app.Conf.DefaultNetwork(); defaultNetwork != "" {
+		network, err = networkFromFlag("network", defaultNetwork)
+		if err != nil {
+			return err
+		}
 	} else {
 		networkStr, err := app.Prompt.CaptureList(
 			"Choose a network to deploy on",
 			[]string{models.Local.String(), models.Fuji.String(), models.Mainnet.String()},
+			prompts.WithDefault(models.Local.String()),
 		)
 		if err != nil {
 			return err
 		}
 		network = models.NetworkFromString(networkStr)
 	}
+	if err := app.CheckProfileBinding(network, keyName); err != nil {
+		return err
+	}
+
+	if deployAsync {
+		if network != models.Local || buildUnsigned {
+			return exitcodes.UserInput(errors.New("--async only applies to local deploys"))
+		}
+		return startAsyncDeploy(chains[0])
+	}
+
+	if vmSource != nil && network != models.Local {
+		return exitcodes.UserInput(errors.New("--vm-source only applies to local deploys, where the CLI installs the VM"))
+	}
+	if vmBinary != "" {
+		if network == models.Local {
+			return exitcodes.UserInput(errors.New("--vm-binary only applies to public deploys, the plugin of local deploys is the one installed"))
+		}
+		if info, err := os.Stat(vmBinary); err != nil || !info.Mode().IsRegular() {
+			return exitcodes.UserInput(fmt.Errorf("VM binary %s not found", vmBinary))
+		}
+	}
+	if reportPath != "" {
+		if network != models.Local || buildUnsigned {
+			return exitcodes.UserInput(errors.New("--report only applies to local deploys"))
+		}
+		if err := app.CheckWritable("write " + reportPath); err != nil {
+			return err
+		}
+	}
 
+	if buildUnsigned {
+		// nothing is deployed until the transaction is signed and issued
+		return deployToNetwork(network, chains)
+	}
+	event := webhooks.Event{Subnet: chains[0], Network: network.String()}
+	event.Type = webhooks.DeployStarted
+	webhooks.Notify(app, event)
+	err = deployToNetwork(network, chains)
+	if err != nil {
+		event.Type = webhooks.DeployFailed
+		event.Error = err.Error()
+	} else {
+		event.Type = webhooks.DeploySucceeded
+		if sc, err := app.LoadSidecar(chains[0]); err == nil {
+			networkKey := network.String()
+			if network == models.Local {
+				networkKey = localNetworkKey()
+			}
+			if data, ok := sc.Networks[networkKey]; ok {
+				event.Details = map[string]string{
+					"subnetID":     data.SubnetID.String(),
+					"blockchainID": data.BlockchainID.String(),
+				}
+			}
+		}
+	}
+	webhooks.Notify(app, event)
+	return err
+}
+
+// deployToNetwork deploys the first of chains to network, locally or with a
+// transaction built unsigned or issued with the key given
+func deployToNetwork(network models.Network, chains []string) error {
 	// deploy based on chosen network
 	ux.Logger.PrintToUser("Deploying %s to %s", chains, network.String())
 	chain := chains[0]
-	chainGenesis := filepath.Join(app.GetBaseDir(), fmt.Sprintf("%s_genesis.json", chain))
+	chainGenesis, cleanup, err := app.ResolveGenesisFile(chain, network, genesisVars)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// fail before prompting for anything if the genesis funds the ewoq key
+	if err := checkEwoqGenesis(network, chain, chainGenesis); err != nil {
+		return err
+	}
+	// and if the subnet-evm version deployed would ignore parts of its config
+	if err := checkVMCapabilities(chain, chainGenesis); err != nil {
+		return err
+	}
+
+	if buildUnsigned {
+		return deployUnsigned(network, chain, chainGenesis)
+	}
+
+	subnetLock, err := lockSubnetDeploy(chain)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := subnetLock.Release(); err != nil {
+			app.Log.Warn("failed releasing the deploy lock of %s: %s", chain, err)
+		}
+	}()
 
 	switch network {
 	case models.Local:
 		app.Log.Debug("Deploy local")
-		sc, err := app.LoadSidecar(chain)
-		if err != nil {
-			return fmt.Errorf("failed to load sidecar for later update: %w", err)
-		}
-		deployer := subnet.NewLocalSubnetDeployer(app)
-		subnetID, blockchainID, err := deployer.DeployToLocalNetwork(chain, chainGenesis)
-		if err != nil {
-			if deployer.BackendStartedHere() {
-				if innerErr := binutils.KillgRPCServerProcess(app); innerErr != nil {
-					app.Log.Warn("tried to kill the gRPC server process but it failed: %w", innerErr)
-				}
-			}
-			return err
-		}
-		if sc.Networks == nil {
-			sc.Networks = make(map[string]models.NetworkData)
-		}
-		sc.Networks[models.Local.String()] = models.NetworkData{
-			SubnetID:     subnetID,
-			BlockchainID: blockchainID,
-		}
-		if err := app.UpdateSidecar(&sc); err != nil {
-			return fmt.Errorf("creation of chains and subnet was successful, but failed to update sidecar: %w", err)
-		}
-		return nil
+		return deployToLocalNetwork(chain, chainGenesis)
 
 	case models.Fuji: // just make the switch pass
 		if keyName == "" {
@@ -144,6 +347,7 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		}
+		keyName = app.Conf.ResolveKeyAlias(keyName)
 
 	case models.Mainnet: // just make the switch pass, fuij/main implementation is the same (for now)
 	default:
@@ -151,6 +355,22 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	}
 
 	// from here on we are assuming a public deploy
+	if keyName == "" {
+		keyName = app.Conf.BoundKey()
+	}
+	if err := app.CheckProfileBinding(network, keyName); err != nil {
+		return err
+	}
+
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network)
+	// fail before prompting for anything if the key can't pay for the deploy
+	feeParams, err := subnet.GetFeeParams(app, network)
+	if err != nil {
+		return err
+	}
+	if err := deployer.CheckFunding(subnet.EstimateCost(feeParams, 0, 0, false).Fees()); err != nil {
+		return err
+	}
 
 	// prompt for control keys
 	controlKeys, cancelled, err := getControlKeys(network)
@@ -172,31 +392,210 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// deploy to public network
-	deployer := subnet.NewPublicDeployer(app, app.GetKeyPath(keyName), network)
-	subnetID, blockchainID, err := deployer.Deploy(controlKeys, threshold, chain, chainGenesis)
+	governance, err := captureGovernance(network, controlKeys, threshold)
 	if err != nil {
 		return err
 	}
 
-	// update sidecar
 	// TODO: need to do something for backwards compatibility?
 	sidecar, err := app.LoadSidecar(chain)
 	if err != nil {
 		return err
 	}
+
+	vmBinaryHash, err := subnet.VMBinaryHash(app, sidecar, vmBinary)
+	if err != nil {
+		return err
+	}
+
+	// deploy to public network
+	subnetID, blockchainID, err := deployer.Deploy(controlKeys, threshold, sidecar, chainGenesis)
+	if err != nil {
+		return err
+	}
+	if vmBinaryHash != "" {
+		ux.Logger.PrintToUser("VM binary SHA-256: %s", vmBinaryHash)
+	}
+
+	// update sidecar
 	nets := sidecar.Networks
 	if nets == nil {
 		nets = make(map[string]models.NetworkData)
 	}
-	nets[network.String()] = models.NetworkData{
+	netData := models.NetworkData{
 		SubnetID:     subnetID,
 		BlockchainID: blockchainID,
+		Governance:   governance,
+		VMBinaryHash: vmBinaryHash,
 	}
+	setLineage(chain, &netData)
+	nets[network.String()] = netData
 	sidecar.Networks = nets
 	return app.UpdateSidecar(&sidecar)
 }
 
+// checkEwoqGenesis refuses to deploy the genesis of chain to a public
+// network if it funds the ewoq key or makes it an admin, unless allowed
+func checkEwoqGenesis(network models.Network, chain, chainGenesis string) error {
+	if network == models.Local {
+		return nil
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return err
+	}
+	genesis, err := os.ReadFile(chainGenesis)
+	if err != nil {
+		return fmt.Errorf("failed reading chain genesis: %w", err)
+	}
+	return subnet.NewPublicDeployer(app, "", network).CheckEwoqGenesis(sc, genesis)
+}
+
+// checkVMCapabilities refuses to deploy chain if its genesis or upgrades use
+// features the subnet-evm version pinned doesn't support, which it would
+// silently ignore. VMs built from source are not checked, their version
+// being unknown.
+func checkVMCapabilities(chain, chainGenesis string) error {
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm || vmSource != nil {
+		return nil
+	}
+	genesis, err := os.ReadFile(chainGenesis)
+	if err != nil {
+		return fmt.Errorf("failed reading chain genesis: %w", err)
+	}
+	version, pinned := app.Conf.SubnetEVMVersion()
+	unsupported, err := vm.CheckSubnetEVMCapabilities(genesis, sc, version)
+	if err != nil || len(unsupported) == 0 {
+		return err
+	}
+	ux.Logger.PrintToUser("subnet-evm %s doesn't support these features of %s, it would run without them:", version, chain)
+	for _, feature := range unsupported {
+		ux.Logger.PrintToUser("  %s", feature)
+	}
+	minVersion := vm.MinSubnetEVMVersion(unsupported)
+	upgrade := fmt.Sprintf("pin subnet-evm %s or later in the versions of the project", minVersion)
+	if pinned {
+		upgrade = fmt.Sprintf("raise the subnet-evm version pinned by the project to %s or later", minVersion)
+	}
+	return exitcodes.UserInput(fmt.Errorf("subnet %s uses features subnet-evm %s doesn't support, %s, or remove them from its config", chain, version, upgrade))
+}
+
+// lockSubnetDeploy takes the deploy lock of chain: the deploys of different
+// subnets run concurrently, but not two deploys of the same one
+func lockSubnetDeploy(chain string) (*lock.Lock, error) {
+	path := filepath.Join(app.GetBaseDir(), chain+constants.DeployLockSuffix)
+	l, err := lock.Acquire(path, "avalanche subnet deploy "+chain)
+	if errors.Is(err, lock.ErrLocked) {
+		return nil, exitcodes.Locked(fmt.Errorf("%w, deploying %s already", err, chain))
+	}
+	return l, err
+}
+
+// deployToLocalNetwork deploys chain to the local network and records the
+// deployment in its sidecar
+func deployToLocalNetwork(chain, chainGenesis string) error {
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar for later update: %w", err)
+	}
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	deployer.SetVerbose(verbose)
+	deployer.SetFollowLogs(followLogs)
+	deployer.SetNoConfirm(noConfirm)
+	if vmSource != nil {
+		deployer.SetVMSource(*vmSource)
+	}
+	subnetID, blockchainID, err := deployer.DeployToLocalNetwork(sc, chainGenesis)
+	if err != nil {
+		if deployer.BackendStartedHere() {
+			if innerErr := binutils.KillgRPCServerProcess(app); innerErr != nil {
+				app.Log.Warn("tried to kill the gRPC server process but it failed: %s", innerErr)
+			}
+		}
+		return err
+	}
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	netData := models.NetworkData{
+		SubnetID:     subnetID,
+		BlockchainID: blockchainID,
+		VMBinaryHash: deployer.VMBinaryHash(),
+	}
+	setLineage(chain, &netData)
+	sc.Networks[localNetworkKey()] = netData
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return fmt.Errorf("creation of chains and subnet was successful, but failed to update sidecar: %w", err)
+	}
+	if reportPath != "" {
+		report := subnet.DeployReport{
+			Subnet:       chain,
+			SubnetID:     subnetID.String(),
+			BlockchainID: blockchainID.String(),
+			VMBinaryHash: deployer.VMBinaryHash(),
+			Phases:       deployer.PhaseDurations(),
+		}
+		if err := subnet.WriteDeployReport(reportPath, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEnvironment returns the network of the project environment name, and
+// defaults the key and the genesis variables of the deploy to its own
+func applyEnvironment(name string) (models.Network, error) {
+	env, err := app.Conf.Environment(name)
+	if err != nil {
+		return models.Undefined, exitcodes.UserInput(err)
+	}
+	network, err := networkFromFlag("network", env.Network)
+	if err != nil {
+		return models.Undefined, err
+	}
+	if deployLocal && network != models.Local {
+		return models.Undefined, exitcodes.UserInput(fmt.Errorf("environment %s deploys to %s, not to the local network", name, network))
+	}
+	if keyName == "" {
+		keyName = env.Key
+	}
+	// the variables of the environment override the ones of its network,
+	// and are overridden by --genesis-var
+	vars := map[string]string{}
+	for name, value := range env.GenesisVars {
+		vars[name] = value
+	}
+	for name, value := range genesisVars {
+		vars[name] = value
+	}
+	genesisVars = vars
+	return network, nil
+}
+
+// setLineage records in data the genesis of chain deployed, and the
+// environment deployed and the one it was promoted from, if any. It is
+// called once the chain is deployed, so a genesis which can't be read is
+// only warned about.
+func setLineage(chain string, data *models.NetworkData) {
+	genesisHash, err := subnet.GenesisHash(app.GetGenesisPath(chain))
+	if err != nil {
+		app.Log.Warn("failed hashing the genesis of %s: %s", chain, err)
+	}
+	data.GenesisHash = genesisHash
+	data.Environment = deployEnvironment
+	data.PromotedFrom = promotedFrom
+}
+
+// localNetworkKey returns the key under which local deploys of the
+// current profile are recorded in the sidecar
+func localNetworkKey() string {
+	return app.GetLocalNetworkKey()
+}
+
 func getControlKeys(network models.Network) ([]string, bool, error) {
 	controlKeysPrompt := "Configure which addresses may add new validators to the subnet.\n" +
 		"These addresses are known as your control keys. You will also\n" +
@@ -222,27 +621,40 @@ func getControlKeys(network models.Network) ([]string, bool, error) {
 // controlKeysLoop asks as many controlkeys the user requires, until Done or Cancel is selected
 func controlKeysLoop(controlKeysPrompt string, network models.Network) ([]string, bool, error) {
 	const (
-		addCtrlKey = "Add control key"
-		doneMsg    = "Done"
-		cancelMsg  = "Cancel"
+		addCtrlKey  = "Add control key"
+		addLocalKey = "Add the address of a local key"
+		doneMsg     = "Done"
+		cancelMsg   = "Cancel"
 	)
 
 	var controlKeys []string
 
+	keyNames, keyAddrs, err := storedKeyPChainAddresses(app.GetKeyDir(), network)
+	if err != nil {
+		return nil, false, err
+	}
+	options := []string{addCtrlKey, doneMsg, cancelMsg}
+	if len(keyNames) > 0 {
+		options = []string{addCtrlKey, addLocalKey, doneMsg, cancelMsg}
+	}
+
 	for {
-		listDecision, err := app.Prompt.CaptureList(
-			controlKeysPrompt, []string{addCtrlKey, doneMsg, cancelMsg},
-		)
+		listDecision, err := app.Prompt.CaptureList(controlKeysPrompt, options)
 		if err != nil {
 			return nil, false, err
 		}
 
 		switch listDecision {
-		case addCtrlKey:
-			controlKey, err := app.Prompt.CapturePChainAddress(
-				"Enter the P-Chain addresses which can add validators to this subnet (*must* be a PChain address: `P-...`)",
-				network,
-			)
+		case addCtrlKey, addLocalKey:
+			var controlKey string
+			if listDecision == addCtrlKey {
+				controlKey, err = app.Prompt.CapturePChainAddress(
+					"Enter the P-Chain addresses which can add validators to this subnet (*must* be a PChain address: `P-...`)",
+					network,
+				)
+			} else {
+				controlKey, err = captureKeyPChainAddress(keyNames, keyAddrs, network)
+			}
 			if err != nil {
 				return nil, false, err
 			}
@@ -261,6 +673,77 @@ func controlKeysLoop(controlKeysPrompt string, network models.Network) ([]string
 	}
 }
 
+// captureKeyPChainAddress asks for one of the local keys, listed with their
+// P-Chain address on network, and returns its address
+func captureKeyPChainAddress(keyNames []string, keyAddrs map[string]string, network models.Network) (string, error) {
+	labels := make([]string, len(keyNames))
+	for i, name := range keyNames {
+		labels[i] = fmt.Sprintf("%s (%s)", name, keyAddrs[name])
+	}
+	label, err := app.Prompt.CaptureList("Which key's P-Chain address can add validators to this subnet?", labels)
+	if err != nil {
+		return "", err
+	}
+	for i, l := range labels {
+		if l == label {
+			addr := keyAddrs[keyNames[i]]
+			// the address is derived for the network, so this only catches
+			// corrupted key files
+			if err := prompts.ValidatePChainAddress(addr, network); err != nil {
+				return "", fmt.Errorf("address %s of key %s: %w", addr, keyNames[i], err)
+			}
+			return addr, nil
+		}
+	}
+	return "", errors.New("unexpected option")
+}
+
+// storedKeyPChainAddresses returns the names of the keys stored in keyDir,
+// sorted, and their P-Chain address on network by name
+func storedKeyPChainAddresses(keyDir string, network models.Network) ([]string, map[string]string, error) {
+	var networkID uint32
+	switch network {
+	case models.Fuji:
+		networkID = avago_constants.FujiID
+	case models.Mainnet:
+		networkID = avago_constants.MainnetID
+	default:
+		networkID = avago_constants.LocalID
+	}
+	files, err := os.ReadDir(keyDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	names := []string{}
+	addrs := map[string]string{}
+	for _, f := range files {
+		keyPath := filepath.Join(keyDir, f.Name())
+		var k key.Addresser
+		switch {
+		case strings.HasSuffix(f.Name(), constants.RemoteKeySuffix):
+			k, err = key.LoadRemote(networkID, keyPath)
+		case strings.HasSuffix(f.Name(), constants.KeySuffix):
+			k, err = key.LoadSoftAddresses(networkID, keyPath)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed loading key %s: %w", f.Name(), err)
+		}
+		if len(k.P()) == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(f.Name(), constants.KeySuffix), constants.RemoteKeySuffix)
+		names = append(names, name)
+		addrs[name] = k.P()[0]
+	}
+	sort.Strings(names)
+	return names, addrs, nil
+}
+
 // getThreshold prompts for the threshold of addresses as a number
 func getThreshold(maxLen uint64) (uint32, error) {
 	threshold, err := app.Prompt.CaptureUint64("Enter required number of control key signatures to add a validator")
@@ -268,7 +751,7 @@ func getThreshold(maxLen uint64) (uint32, error) {
 		return 0, err
 	}
 	if threshold > maxLen {
-		return 0, fmt.Errorf("the threshold can't be bigger than the number of control keys")
+		return 0, exitcodes.UserInput(fmt.Errorf("the threshold can't be bigger than the number of control keys"))
 	}
 	return uint32(threshold), err
 }
@@ -282,21 +765,38 @@ func contains(list []string, element string) bool {
 	return false
 }
 
+// subnetNameFromArgs returns the subnet name given as argument, or
+// the default subnet of the project if there is none
+func subnetNameFromArgs(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if subnetName, ok := app.Conf.DefaultSubnet(); ok {
+		app.Log.Info("using subnet %s of the project config", subnetName)
+		return subnetName, nil
+	}
+	return "", exitcodes.UserInput(fmt.Errorf("no subnet name given and no subnets set in %s", constants.ProjectConfigFileName))
+}
+
 func validateSubnetNameAndGetChains(args []string) ([]string, error) {
+	subnetName, err := subnetNameFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
 	// this should not be necessary but some bright guy might just be creating
 	// the genesis by hand or something...
-	if err := checkInvalidSubnetNames(args[0]); err != nil {
-		return nil, fmt.Errorf("subnet name %s is invalid: %s", args[0], err)
+	if err := checkInvalidSubnetNames(subnetName); err != nil {
+		return nil, exitcodes.UserInput(fmt.Errorf("subnet name %s is invalid: %s", subnetName, err))
 	}
 	// Check subnet exists
 	// TODO create a file that lists chains by subnet for fast querying
-	chains, err := getChainsInSubnet(args[0])
+	chains, err := getChainsInSubnet(subnetName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to getChainsInSubnet: %w", err)
 	}
 
 	if len(chains) == 0 {
-		return nil, errors.New("Invalid subnet " + args[0])
+		return nil, exitcodes.UserInput(errors.New("Invalid subnet " + subnetName))
 	}
 
 	return chains, nil
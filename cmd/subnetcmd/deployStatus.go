@@ -0,0 +1,143 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	asyncFlag      = "async"
+	trackingIDFlag = "tracking-id"
+)
+
+var (
+	deployAsync bool
+	// trackingID is the deploy tracking record the deploy reports to, when
+	// running in the background
+	trackingID string
+)
+
+// addAsyncFlags adds the flags of the deploys running in the background
+func addAsyncFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&deployAsync, asyncFlag, false, "start the local deploy in the background and return a tracking ID for subnet deploy-status")
+	cmd.Flags().StringVar(&trackingID, trackingIDFlag, "", "tracking ID of the background deploy to report to")
+	_ = cmd.Flags().MarkHidden(trackingIDFlag)
+}
+
+// startAsyncDeploy runs the deploy of this invocation in the background, to
+// the local network, reporting to a new tracking record
+func startAsyncDeploy(chain string) error {
+	tracking, err := subnet.NewDeployTracking(app, chain)
+	if err != nil {
+		return err
+	}
+	args := []string{}
+	for _, arg := range os.Args[1:] {
+		if arg == "--"+asyncFlag || strings.HasPrefix(arg, "--"+asyncFlag+"=") {
+			continue
+		}
+		args = append(args, arg)
+	}
+	// the deploy in the background can't prompt, waits for this invocation
+	// to release the state lock, and is logged under its correlation ID
+	args = append(args,
+		"--local",
+		"--headless",
+		"--correlation-id", app.GetCorrelationID(),
+		"--"+trackingIDFlag, tracking.ID,
+		"--"+constants.LockWaitFlag, constants.AsyncDeployLockWait.String(),
+	)
+	if err := subnet.StartTrackedDeploy(app, tracking, args); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Deploying %s to the local network in the background, tracking ID: %s", chain, tracking.ID)
+	ux.Logger.PrintToUser("Output at: %s", tracking.LogFile)
+	ux.Logger.PrintToUser("Follow it with: avalanche subnet deploy-status %s", tracking.ID)
+	ux.Logger.PrintToUser("Or wait for it with: avalanche network wait --subnet %s", chain)
+	return nil
+}
+
+// runTrackedDeploy runs deploy, recording its progress and outcome in the
+// tracking record of id
+func runTrackedDeploy(id string, deploy func() error) error {
+	tracking, err := subnet.LoadDeployTracking(app, id)
+	if err != nil {
+		return err
+	}
+	tracking.Status = subnet.DeployRunning
+	tracking.Pid = os.Getpid()
+	if err := tracking.Save(app); err != nil {
+		return err
+	}
+	deployErr := deploy()
+	var subnetID, blockchainID string
+	if deployErr == nil {
+		if sc, err := app.LoadSidecar(tracking.Subnet); err == nil {
+			netData := sc.Networks[localNetworkKey()]
+			subnetID, blockchainID = netData.SubnetID.String(), netData.BlockchainID.String()
+		}
+	}
+	if err := tracking.Finish(app, subnetID, blockchainID, deployErr); err != nil {
+		app.Log.Warn("failed recording the outcome of the deploy of tracking ID %s: %s", id, err)
+	}
+	return deployErr
+}
+
+// avalanche subnet deploy-status
+func newDeployStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deploy-status [trackingID]",
+		Short: "Print the status of a deploy running in the background",
+		Long: `The subnet deploy-status command prints the status of a local deploy started
+in the background with subnet deploy --async, given its tracking ID:
+pending until it starts, then running, and succeeded or failed.
+
+It fails with the exit code of the deploy if the deploy failed, and
+succeeds otherwise, even while the deploy runs. Use network wait --subnet
+to block until the deployed blockchain is ready.`,
+		RunE:         printDeployStatus,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func printDeployStatus(cmd *cobra.Command, args []string) error {
+	tracking, err := subnet.LoadDeployTracking(app, args[0])
+	if err != nil {
+		return err
+	}
+	tracking.CheckAlive()
+
+	now := time.Now()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.Append([]string{"Tracking ID", tracking.ID})
+	table.Append([]string{"Subnet", tracking.Subnet})
+	table.Append([]string{"Status", string(tracking.Status)})
+	table.Append([]string{"Started", ux.FormatTime(tracking.StartedAt, now)})
+	table.Append([]string{"Updated", ux.FormatTime(tracking.UpdatedAt, now)})
+	if tracking.SubnetID != "" {
+		table.Append([]string{"SubnetID", tracking.SubnetID})
+		table.Append([]string{"BlockchainID", tracking.BlockchainID})
+	}
+	table.Append([]string{"Output", tracking.LogFile})
+	table.Render()
+
+	if tracking.Status == subnet.DeployFailed {
+		return exitcodes.New(exitcodes.ExitCode(tracking.ExitCode), errors.New(tracking.Error))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoredKeyPChainAddresses(t *testing.T) {
+	assert := assert.New(t)
+	keyDir := t.TempDir()
+
+	names, addrs, err := storedKeyPChainAddresses(filepath.Join(keyDir, "missing"), models.Fuji)
+	assert.NoError(err)
+	assert.Empty(names)
+	assert.Empty(addrs)
+
+	for _, name := range []string{"zed", "ewoq"} {
+		k, err := key.NewSoft(avago_constants.FujiID, key.WithPrivateKeyEncoded(key.EwoqPrivateKey))
+		assert.NoError(err)
+		assert.NoError(k.Save(filepath.Join(keyDir, name+".pk")))
+	}
+	assert.NoError(os.WriteFile(filepath.Join(keyDir, "notes.txt"), []byte("not a key"), 0o600))
+
+	for network, prefix := range map[models.Network]string{
+		models.Fuji:    "P-fuji1",
+		models.Mainnet: "P-avax1",
+		models.Local:   "P-local1",
+	} {
+		names, addrs, err := storedKeyPChainAddresses(keyDir, network)
+		assert.NoError(err)
+		assert.Equal([]string{"ewoq", "zed"}, names)
+		for _, name := range names {
+			assert.True(strings.HasPrefix(addrs[name], prefix), addrs[name])
+			assert.NoError(prompts.ValidatePChainAddress(addrs[name], network))
+		}
+	}
+
+	_, fujiAddrs, err := storedKeyPChainAddresses(keyDir, models.Fuji)
+	assert.NoError(err)
+	assert.Error(prompts.ValidatePChainAddress(fujiAddrs["ewoq"], models.Mainnet))
+}
+
+func TestBuildGovernance(t *testing.T) {
+	assert := assert.New(t)
+
+	keyNames := []string{"alice", "bob"}
+	keyAddrs := map[string]string{"alice": "P-fuji1a", "bob": "P-fuji1b"}
+	asked := []string{}
+	governance, err := buildGovernance([]string{"P-fuji1b", "P-fuji1c"}, 2, keyNames, keyAddrs, func(addr string) (string, error) {
+		asked = append(asked, addr)
+		return "ops team", nil
+	})
+	assert.NoError(err)
+	// only the holders of the addresses not owned locally are asked for
+	assert.Equal([]string{"P-fuji1c"}, asked)
+	assert.Equal(&models.SubnetGovernance{
+		ControlKeys: []models.ControlKey{
+			{Address: "P-fuji1b", Key: "bob"},
+			{Address: "P-fuji1c", Holder: "ops team"},
+		},
+		Threshold: 2,
+	}, governance)
+}
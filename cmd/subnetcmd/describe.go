@@ -4,15 +4,17 @@ package subnetcmd
 
 import (
 	"fmt"
-	"math/big"
 	"os"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/subnet-evm/core"
-	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
@@ -70,6 +72,12 @@ func printDetails(genesis core.Genesis, sc models.Sidecar) {
 	table.Append([]string{"Subnet Name", sc.Subnet})
 	table.Append([]string{"ChainID", genesis.Config.ChainID.String()})
 	table.Append([]string{"Token Name", app.GetTokenName(sc.Subnet)})
+	if sc.VMAlias != "" {
+		table.Append([]string{"VM Alias", sc.VMAlias})
+	}
+	if vmID, err := sc.GetVMID(); err == nil {
+		table.Append([]string{"VM ID", vmID.String()})
+	}
 	for net, data := range sc.Networks {
 		if data.SubnetID != ids.Empty {
 			table.Append([]string{fmt.Sprintf("%s SubnetID", net), data.SubnetID.String()})
@@ -77,6 +85,16 @@ func printDetails(genesis core.Genesis, sc models.Sidecar) {
 		if data.BlockchainID != ids.Empty {
 			table.Append([]string{fmt.Sprintf("%s BlockchainID", net), data.BlockchainID.String()})
 		}
+		if data.Environment != "" {
+			environment := data.Environment
+			if data.PromotedFrom != "" {
+				environment += fmt.Sprintf(" (promoted from %s)", data.PromotedFrom)
+			}
+			table.Append([]string{fmt.Sprintf("%s Environment", net), environment})
+		}
+		if data.GenesisHash != "" {
+			table.Append([]string{fmt.Sprintf("%s Genesis SHA-256", net), data.GenesisHash})
+		}
 	}
 	table.Render()
 }
@@ -109,11 +127,12 @@ func printGasTable(genesis core.Genesis) {
 	table.Append([]string{"MaxBlockGasCost", genesis.Config.FeeConfig.MaxBlockGasCost.String()})
 	table.Append([]string{"TargetBlockRate", strconv.FormatUint(genesis.Config.FeeConfig.TargetBlockRate, 10)})
 	table.Append([]string{"BlockGasCostStep", genesis.Config.FeeConfig.BlockGasCostStep.String()})
+	table.Append([]string{"Fees", vm.DescribeFeeDestination(*genesis.Config)})
 
 	table.Render()
 }
 
-func printAirdropTable(genesis core.Genesis) {
+func printAirdropTable(genesis core.Genesis, sc models.Sidecar) {
 	const art = `
           _         _
     /\   (_)       | |
@@ -127,14 +146,18 @@ func printAirdropTable(genesis core.Genesis) {
 	fmt.Print(art)
 	if len(genesis.Alloc) > 0 {
 		table := tablewriter.NewWriter(os.Stdout)
-		header := []string{"Address", "Airdrop Amount (10^18)", "Airdrop Amount (wei)"}
+		header := []string{"Address", fmt.Sprintf("Airdrop Amount (%s)", sc.TokenName), "Airdrop Amount (wei)"}
 		table.SetHeader(header)
 		table.SetRowLine(true)
 
+		addresses := make([]common.Address, 0, len(genesis.Alloc))
 		for address := range genesis.Alloc {
+			addresses = append(addresses, address)
+		}
+		sort.Slice(addresses, func(i, j int) bool { return addresses[i].Hex() < addresses[j].Hex() })
+		for _, address := range addresses {
 			amount := genesis.Alloc[address].Balance
-			formattedAmount := new(big.Int).Div(amount, big.NewInt(params.Ether))
-			table.Append([]string{address.Hex(), formattedAmount.String(), amount.String()})
+			table.Append([]string{address.Hex(), ux.FormatAmount(amount, sc.GetTokenDecimals()), amount.String()})
 		}
 
 		table.Render()
@@ -186,6 +209,53 @@ func printPrecompileTable(genesis core.Genesis) {
 	}
 }
 
+func printUpgradesTable(sc models.Sidecar) {
+	if len(sc.Upgrades) == 0 {
+		return
+	}
+	const art = `
+ _    _                                   _
+| |  | |                                 | |
+| |  | | _ __    __ _   _ __   __ _    __| |   ___   ___
+| |  | || '_ \  / _` + "`" + ` | | '__| / _` + "`" + ` |  / _` + "`" + ` |  / _ \ / __|
+| |__| || |_) || (_| | | |   | (_| | | (_| | |  __/ \__ \
+ \____/ | .__/  \__, | |_|    \__,_|  \__,_|  \___| |___/
+        | |      __/ |
+        |_|     |___/
+`
+	fmt.Print(art)
+	now := time.Now()
+	vm.PrintUpgrades(os.Stdout, sc, now)
+	if len(vm.PendingUpgrades(sc, now)) > 0 {
+		warnOldSubnetEVM()
+	}
+}
+
+func printGovernanceTables(sc models.Sidecar) {
+	networks := []string{}
+	for net, data := range sc.Networks {
+		if data.Governance != nil {
+			networks = append(networks, net)
+		}
+	}
+	if len(networks) == 0 {
+		return
+	}
+	const art = `
+  _____
+ / ____|
+| |  __  _____   _____ _ __ _ __   __ _ _ __   ___ ___
+| | |_ |/ _ \ \ / / _ \ '__| '_ \ / _` + "`" + ` | '_ \ / __/ _ \
+| |__| | (_) \ V /  __/ |  | | | | (_| | | | | (_|  __/
+ \_____|\___/ \_/ \___|_|  |_| |_|\__,_|_| |_|\___\___|
+`
+	fmt.Print(art)
+	sort.Strings(networks)
+	for _, net := range networks {
+		printGovernanceTable(net, *sc.Networks[net].Governance)
+	}
+}
+
 func describeSubnetEvmGenesis(sc models.Sidecar) error {
 	// Load genesis
 	genesis, err := app.LoadEvmGenesis(sc.Subnet)
@@ -197,8 +267,10 @@ func describeSubnetEvmGenesis(sc models.Sidecar) error {
 	// Write gas table
 	printGasTable(genesis)
 	// fmt.Printf("\n\n")
-	printAirdropTable(genesis)
+	printAirdropTable(genesis, sc)
 	printPrecompileTable(genesis)
+	printUpgradesTable(sc)
+	printGovernanceTables(sc)
 	return nil
 }
 
@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/docs"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	docsFormat     string
+	docsOutput     string
+	docsValidators bool
+)
+
+// avalanche subnet docs
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs [subnetName]",
+		Short: "Generate a page describing a subnet for the teams integrating it",
+		Long: `The subnet docs command generates a Markdown or HTML page describing the
+chain of a subnet, to hand off to the teams integrating it: its chain ID and
+token, its RPC endpoints on each network it is deployed to, its fee
+parameters, the admins of its precompiles and scheduled upgrades, and for
+each deployment the subnet and blockchain IDs, the control keys and the
+current validators.
+
+Everything but the validators comes from the subnet configuration and its
+deployment records. The validators are queried from the P-Chain of each
+network, which --validators=false skips.`,
+		RunE:         subnetDocs,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&docsFormat, "format", docs.Markdown, "format of the page [markdown, html]")
+	cmd.Flags().StringVarP(&docsOutput, "output", "o", "", "file to write the page to, instead of printing it")
+	cmd.Flags().BoolVar(&docsValidators, "validators", true, "list the current validators of each deployment")
+	return cmd
+}
+
+func subnetDocs(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if docsFormat != docs.Markdown && docsFormat != docs.HTML {
+		return exitcodes.UserInput(fmt.Errorf("invalid --format %q, must be one of %s, %s", docsFormat, docs.Markdown, docs.HTML))
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	var genesis *core.Genesis
+	if sc.VM == models.SubnetEvm {
+		evmGenesis, err := app.LoadEvmGenesis(subnetName)
+		if err != nil {
+			return err
+		}
+		genesis = &evmGenesis
+	}
+	endpoints := map[models.Network]string{
+		models.Local: binutils.LocalEndpoint(app),
+	}
+	for _, network := range []models.Network{models.Fuji, models.Mainnet} {
+		endpoint, err := app.GetAPIEndpoint(network)
+		if err != nil {
+			return err
+		}
+		endpoints[network] = endpoint
+	}
+	var listValidators docs.ValidatorsLister
+	if docsValidators {
+		listValidators = func(network models.Network, subnetID ids.ID) ([]docs.Validator, error) {
+			return currentValidators(endpoints[network], subnetID)
+		}
+	}
+	page := docs.Build(sc, genesis, localNetworkKey(), endpoints, listValidators)
+
+	var rendered bytes.Buffer
+	if err := docs.Render(&rendered, page, docsFormat); err != nil {
+		return err
+	}
+	if docsOutput == "" {
+		fmt.Print(rendered.String())
+		return nil
+	}
+	if err := app.CheckWritable("write " + docsOutput); err != nil {
+		return err
+	}
+	if err := os.WriteFile(docsOutput, rendered.Bytes(), application.WriteReadReadPerms); err != nil {
+		return fmt.Errorf("failed writing the page: %w", err)
+	}
+	ux.Logger.PrintToUser("Page of subnet %s written to %s", subnetName, docsOutput)
+	return nil
+}
+
+// currentValidators returns the current validators of subnetID from the
+// P-Chain of the API endpoint
+func currentValidators(endpoint string, subnetID ids.ID) ([]docs.Validator, error) {
+	if endpoint == "" {
+		return nil, errors.New("the local network is not running")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.EndpointCheckTimeout)
+	defer cancel()
+	current, err := platformvm.NewClient(endpoint).GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	validators := make([]docs.Validator, 0, len(current))
+	for _, v := range current {
+		validator := docs.Validator{
+			NodeID: v.NodeID.String(),
+			End:    time.Unix(int64(v.EndTime), 0).UTC(),
+		}
+		if v.Weight != nil {
+			validator.Weight = *v.Weight
+		}
+		validators = append(validators, validator)
+	}
+	return validators, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	governanceNetwork string
+	governanceOutput  string
+)
+
+// avalanche subnet governance
+func newGovernanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "governance [subnetName]",
+		Short: "Print who controls a deployed subnet",
+		Long: `The subnet governance command prints the control keys of the subnet deployed
+to the network, who holds each of them and how many of them must sign to add
+validators or blockchains to the subnet, as recorded when the subnet was
+created.
+
+With --output, it also writes a governance document stating it, whose
+message the holders of the control keys sign to agree on who controls the
+subnet.`,
+		RunE:         printGovernance,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&governanceNetwork, "network", "", "network the subnet is deployed to [fuji, mainnet] (default network of the project config)")
+	cmd.Flags().StringVarP(&governanceOutput, "output", "o", "", "file to write the governance document to")
+	return cmd
+}
+
+func printGovernance(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist: %w", subnetName, err))
+	}
+	networkName := governanceNetwork
+	if networkName == "" {
+		networkName = app.Conf.DefaultNetwork()
+	}
+	if networkName == "" {
+		return exitcodes.UserInput(fmt.Errorf("--network is required"))
+	}
+	network, err := networkFromFlag("network", networkName)
+	if err != nil {
+		return err
+	}
+	netData := sc.Networks[network.String()]
+	if netData.SubnetID == ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("%s is not deployed to %s", subnetName, network))
+	}
+	if netData.Governance == nil {
+		return exitcodes.UserInput(fmt.Errorf("the governance of %s on %s was not recorded when it was deployed", subnetName, network))
+	}
+
+	printGovernanceTable(network.String(), *netData.Governance)
+	if governanceOutput == "" {
+		return nil
+	}
+	doc := subnet.NewGovernanceDocument(subnetName, network, netData.SubnetID, *netData.Governance)
+	if err := doc.Save(governanceOutput); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Governance document written to %s", governanceOutput)
+	ux.Logger.PrintToUser("The holders of the control keys sign its message, of SHA-256 digest %s", doc.Digest)
+	return nil
+}
+
+func printGovernanceTable(network string, governance models.SubnetGovernance) {
+	ux.Logger.PrintToUser("%s: %d of %d control keys must sign", network, governance.Threshold, len(governance.ControlKeys))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Control Key", "Held By"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, k := range governance.ControlKeys {
+		table.Append([]string{k.Address, k.HeldBy()})
+	}
+	table.Render()
+}
+
+// buildGovernance returns the governance of a subnet of controlKeys, naming
+// the local keys holding them, from keyAddrs by name, and asking
+// captureHolder for the holders of the others
+func buildGovernance(
+	controlKeys []string,
+	threshold uint32,
+	keyNames []string,
+	keyAddrs map[string]string,
+	captureHolder func(string) (string, error),
+) (*models.SubnetGovernance, error) {
+	localKeys := map[string]string{}
+	for _, name := range keyNames {
+		if _, ok := localKeys[keyAddrs[name]]; !ok {
+			localKeys[keyAddrs[name]] = name
+		}
+	}
+	governance := &models.SubnetGovernance{Threshold: threshold}
+	for _, addr := range controlKeys {
+		k := models.ControlKey{Address: addr, Key: localKeys[addr]}
+		if k.Key == "" {
+			holder, err := captureHolder(addr)
+			if err != nil {
+				return nil, err
+			}
+			k.Holder = holder
+		}
+		governance.ControlKeys = append(governance.ControlKeys, k)
+	}
+	return governance, nil
+}
+
+// captureGovernance asks for the holders of the control keys of a subnet
+// to deploy to network which aren't local keys
+func captureGovernance(network models.Network, controlKeys []string, threshold uint32) (*models.SubnetGovernance, error) {
+	keyNames, keyAddrs, err := storedKeyPChainAddresses(app.GetKeyDir(), network)
+	if err != nil {
+		return nil, err
+	}
+	governance, err := buildGovernance(controlKeys, threshold, keyNames, keyAddrs, func(addr string) (string, error) {
+		return app.Prompt.CaptureString(fmt.Sprintf("Who holds control key %s? (e.g. a person or a team)", addr))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if external := governance.External(); len(external) > 0 {
+		ux.Logger.PrintToUser("%d of the control keys are held outside of this machine, their holders will need to sign for the subnet. "+
+			"Run subnet governance with --output once it is deployed to write a governance document for them to sign.", len(external))
+	}
+	return governance, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var restoreRevision int
+
+// avalanche subnet history
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history [subnetName]",
+		Short: "List and restore the previous revisions of a genesis",
+		Long: fmt.Sprintf(`The subnet history command lists the previous revisions of the genesis of
+a subnet, kept every time it is changed, e.g. by subnet upgradeGenesis.
+The %d most recent revisions are kept.
+
+With --restore, the given revision becomes the genesis of the subnet again.
+The genesis it replaces is kept as the latest revision, so that a restore
+can be undone. Blockchains already deployed keep the genesis they were
+created with.`, constants.MaxGenesisRevisions),
+		RunE:         genesisHistory,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().IntVar(&restoreRevision, "restore", 0, "revision to restore as the genesis of the subnet")
+	return cmd
+}
+
+func genesisHistory(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	if cmd.Flags().Changed("restore") {
+		return restoreGenesis(subnetName)
+	}
+	revisions, err := app.GetGenesisRevisions(subnetName)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		ux.Logger.PrintToUser("The genesis of %s was never changed", subnetName)
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Revision", "Replaced At", "Size"})
+	for _, r := range revisions {
+		table.Append([]string{
+			strconv.Itoa(r.Number),
+			r.Time.Format(time.RFC3339),
+			fmt.Sprintf("%d bytes", r.Size),
+		})
+	}
+	table.Render()
+	ux.Logger.PrintToUser("Restore a revision with: avalanche subnet history %s --restore <revision>", subnetName)
+	return nil
+}
+
+func restoreGenesis(subnetName string) error {
+	if err := app.RestoreGenesis(subnetName, restoreRevision); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Revision %d restored as the genesis of %s", restoreRevision, subnetName)
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if len(sc.Networks) > 0 {
+		ux.Logger.PrintToUser("%s is already deployed, the restored genesis only applies to its next deploys", subnetName)
+	}
+	return nil
+}
@@ -48,7 +48,7 @@ to be readable and writable).
 
 This command currently only supports subnets deployed on the Fuji testnet.`,
 		RunE: joinCmd,
-		Args: cobra.ExactArgs(1),
+		Args: cobra.RangeArgs(0, 1),
 	}
 	cmd.Flags().StringVar(&avagoConfigPath, "avalanchego-config", "", "file path of the avalanchego config file")
 	cmd.Flags().BoolVar(&printManual, "print", false, "if true, print the manual config without prompting")
@@ -156,14 +156,9 @@ func isNodeValidatingSubnet(subnetID ids.ID, network models.Network) (bool, erro
 		return false, err
 	}
 
-	var api string
-	switch network {
-	case models.Fuji:
-		api = constants.FujiAPIEndpoint
-	case models.Mainnet:
-		api = constants.MainnetAPIEndpoint
-	default:
-		return false, fmt.Errorf("network not supported")
+	api, err := app.GetAPIEndpoint(network)
+	if err != nil {
+		return false, err
 	}
 	ctx := context.Background()
 	nodeIDs := []ids.NodeID{nodeID}
@@ -0,0 +1,123 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var lintTarget string
+
+// avalanche subnet lint
+func newLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [subnetName]",
+		Short: "Check a subnet configuration for production readiness",
+		Long: `The subnet lint command checks a subnet configuration for problems
+before deploying it. Among others, it looks for allocations or precompile
+admins using the publicly known ewoq key, allocations to burn or precompile
+addresses or to mistyped addresses failing their checksum, precompiles without admins,
+unreasonable gas limits, chain IDs already used by other chains, fee
+recipients of the chain config the genesis ignores or burns the fees without,
+and gas limits or target gas exceeding what the hardware profile declared
+with subnet create --hardware-profile keeps up with.
+
+Checks are stricter the closer the target network is to production. The
+command fails if any finding has ERROR severity.`,
+		RunE:         lintSubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&lintTarget, "target", "mainnet", "network the subnet is meant to be deployed to [local, fuji, mainnet]")
+	return cmd
+}
+
+// networkFromFlag parses the value of a network flag
+func networkFromFlag(flagName, value string) (models.Network, error) {
+	switch strings.ToLower(value) {
+	case "local":
+		return models.Local, nil
+	case "fuji":
+		return models.Fuji, nil
+	case "mainnet":
+		return models.Mainnet, nil
+	}
+	return models.Undefined, exitcodes.UserInput(fmt.Errorf("invalid --%s %q, must be one of local, fuji, mainnet", flagName, value))
+}
+
+func lintSubnet(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	target, err := networkFromFlag("target", lintTarget)
+	if err != nil {
+		return err
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		ux.Logger.PrintToUser("Linting is only supported for SubnetEVM subnets, %s uses %s", subnetName, sc.VM)
+		return nil
+	}
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return err
+	}
+
+	genesisBytes, err := app.LoadGenesis(subnetName, models.Local, nil)
+	if err != nil {
+		return err
+	}
+
+	findings := vm.LintEvmGenesis(genesis, target)
+	checksumFindings, err := vm.LintAllocationChecksums(genesisBytes, target)
+	if err != nil {
+		return err
+	}
+	findings = append(findings, checksumFindings...)
+	if genesis.Config != nil {
+		chainConfigBytes, err := app.LoadChainConfig(subnetName)
+		if err != nil {
+			return err
+		}
+		feeFindings, err := vm.LintFeeRecipient(*genesis.Config, chainConfigBytes)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, feeFindings...)
+		findings = append(findings, vm.LintHardwareProfile(*genesis.Config, sc.HardwareProfile)...)
+	}
+	if len(findings) == 0 {
+		ux.Logger.PrintToUser("No problems found in subnet %s for %s", subnetName, target)
+		return nil
+	}
+	printLintFindings(findings)
+	if vm.HasLintErrors(findings) {
+		return fmt.Errorf("subnet %s is not ready to be deployed to %s", subnetName, target)
+	}
+	return nil
+}
+
+func printLintFindings(findings []vm.LintFinding) {
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{"Severity", "Rule", "Finding"}
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, f := range findings {
+		table.Append([]string{f.Severity.String(), f.Rule, f.Message})
+	}
+	table.Render()
+}
@@ -50,15 +50,15 @@ func listGenesis(cmd *cobra.Command, args []string) error {
 	deployedNames := map[string]struct{}{}
 	// if the server can not be contacted, or there is a problem with the query,
 	// DO NOT FAIL, just print No for deployed status
-	cli, err := binutils.NewGRPCClient()
+	cli, err := binutils.NewGRPCClient(app)
 	if err != nil {
-		app.Log.Warn("could not get connection to server: %w", err)
+		app.Log.Warn("could not get connection to server: %s", err)
 	}
 	if cli != nil {
 		ctx := binutils.GetAsyncContext()
 		resp, err := cli.Status(ctx)
 		if err != nil {
-			app.Log.Warn("failed to query server for status: %w", err)
+			app.Log.Warn("failed to query server for status: %s", err)
 		}
 
 		if resp != nil {
@@ -0,0 +1,139 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prometheusConfigPath string
+	metricsWatch         time.Duration
+)
+
+// avalanche subnet metrics
+func newMetricsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics [subnetName]",
+		Short: "Print the runtime metrics of a locally deployed subnet",
+		Long: `The subnet metrics command scrapes the Prometheus metrics of every node
+of the local network, and prints the key metrics of the subnet's chain: the
+block height, the gas used per second over the last blocks, the transaction
+pool size, and the number of peers of the node.
+
+With the --prometheus-config flag, a Prometheus scrape config for the
+subnet's chain on the local nodes is written to the given file, to be
+used by a real dashboard.
+
+With --watch, the metrics are refreshed in place until interrupted.`,
+		RunE:         subnetMetrics,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&prometheusConfigPath, "prometheus-config", "", "write a Prometheus scrape config for the subnet to this file")
+	ux.AddWatchFlag(cmd.Flags(), &metricsWatch)
+	return cmd
+}
+
+func subnetMetrics(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	blockchainID := sc.Networks[localNetworkKey()].BlockchainID
+	if blockchainID == ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s has not been deployed to the local network", subnetName))
+	}
+
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		return fmt.Errorf("failed to query the local network status: %w", err)
+	}
+	clusterInfo := status.GetClusterInfo()
+	if clusterInfo == nil {
+		return exitcodes.UserInput(errors.New("no local network running"))
+	}
+	if _, ok := clusterInfo.CustomVms[blockchainID.String()]; !ok {
+		return exitcodes.UserInput(fmt.Errorf("blockchain %s of subnet %s is not running on the local network", blockchainID, subnetName))
+	}
+
+	if prometheusConfigPath != "" {
+		if err := app.CheckWritable("write " + prometheusConfigPath); err != nil {
+			return err
+		}
+		conf, err := subnet.PrometheusScrapeConfig(clusterInfo, subnetName, blockchainID.String())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(prometheusConfigPath, conf, application.WriteReadReadPerms); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Prometheus scrape config written to %s", prometheusConfigPath)
+	}
+
+	if metricsWatch <= 0 {
+		return printChainMetrics(clusterInfo, subnetName, blockchainID.String())
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return ux.Watch(ctx, metricsWatch, func() error {
+		return printChainMetrics(clusterInfo, subnetName, blockchainID.String())
+	})
+}
+
+// printChainMetrics prints the metrics of the chain blockchainID of
+// subnetName on every node of the local network
+func printChainMetrics(clusterInfo *rpcpb.ClusterInfo, subnetName, blockchainID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	metrics, errs := subnet.GetChainMetrics(ctx, clusterInfo, blockchainID)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"node", "block height", "gas used/s", "tx pool pending", "tx pool queued", "peers"})
+	table.SetRowLine(true)
+	for _, m := range metrics {
+		table.Append([]string{
+			m.Node,
+			strconv.FormatFloat(m.BlockHeight, 'f', 0, 64),
+			strconv.FormatFloat(m.GasPerSecond, 'f', 0, 64),
+			strconv.FormatFloat(m.TxPoolPending, 'f', 0, 64),
+			strconv.FormatFloat(m.TxPoolQueued, 'f', 0, 64),
+			strconv.FormatFloat(m.Peers, 'f', 0, 64),
+		})
+	}
+	table.Render()
+	for node, err := range errs {
+		ux.Logger.PrintToUser("failed getting the metrics of %s: %s", node, err)
+	}
+	if len(metrics) == 0 && len(errs) > 0 {
+		return fmt.Errorf("failed getting the metrics of subnet %s from any node", subnetName)
+	}
+	return nil
+}
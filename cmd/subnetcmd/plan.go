@@ -0,0 +1,382 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planNetwork        string
+	planOutput         string
+	planControlKeys    []string
+	planThreshold      uint32
+	planValidatorsFile string
+)
+
+// avalanche subnet plan
+func newPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan [subnetName]",
+		Short: "Plan the deploy of a subnet, to review before applying it",
+		Long: `The subnet plan command computes the actions a deploy of the subnet would
+take on a network, given its current state there: creating the subnet,
+creating its blockchain, and adding the validators listed with --validators
+which don't validate it yet. The plan is written as a JSON document to
+review, e.g. in a pull request, and to apply as is with subnet apply.
+
+On Fuji and Mainnet, the plan records the signing key paying for the deploy,
+the fees it takes, and the control keys and threshold of the subnet to
+create, the address of the signing key by default. The validators file has
+the format of subnet addValidator --file. The validators without a start time
+start shortly after the plan is applied.
+
+On the local network, every node validates the subnet, so the plan only
+creates the subnet and its blockchain if they are not deployed yet.`,
+		RunE:         planSubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&planNetwork, "network", "fuji", "network to plan the deploy on [local, fuji, mainnet]")
+	cmd.Flags().StringVarP(&planOutput, "output", "o", "plan.json", "file to write the plan to")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "key paying for the deploy on fuji or mainnet")
+	cmd.Flags().StringSliceVar(&planControlKeys, "control-keys", nil, "P-Chain addresses of the control keys of the subnet to create (default the address of the key)")
+	cmd.Flags().Uint32Var(&planThreshold, "threshold", 1, "number of control key signatures required to add a validator")
+	cmd.Flags().StringVar(&planValidatorsFile, "validators", "", "CSV file of the validators the subnet must have")
+	cmd.Flags().StringToStringVar(&genesisVars, "genesis-var", nil, "value of a genesis template variable, as Name=value")
+	return cmd
+}
+
+// avalanche subnet apply
+func newApplyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply [planFile]",
+		Short: "Apply a plan made with subnet plan",
+		Long: `The subnet apply command takes the steps of a plan made with subnet plan.
+
+The plan is only applied if the genesis of the subnet and its state on the
+network are still the ones it was made against. Otherwise, make a new plan.`,
+		RunE:         applyPlan,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func planSubnet(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	network, err := networkFromFlag("network", planNetwork)
+	if err != nil {
+		return err
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	if network == models.Local {
+		for _, flag := range []string{"key", "control-keys", "threshold", "validators"} {
+			if cmd.Flags().Changed(flag) {
+				return exitcodes.UserInput(fmt.Errorf("--%s only applies to fuji and mainnet", flag))
+			}
+		}
+	}
+	if err := app.CheckWritable("write " + planOutput); err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	genesisHash, err := resolvedGenesisHash(subnetName, network, genesisVars)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if network != models.Local {
+		now = networkClock(network).Now()
+	}
+	defaultStart := now.Add(constants.StakingStartLeadTime)
+	validators := []subnet.ValidatorEntry{}
+	if planValidatorsFile != "" {
+		if validators, err = subnet.LoadValidatorsFile(planValidatorsFile, now); err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+
+	plan := subnet.Plan{
+		Version:     subnet.PlanVersion,
+		Subnet:      subnetName,
+		Network:     network.String(),
+		GenesisHash: genesisHash,
+		GenesisVars: genesisVars,
+		CreatedAt:   now.UTC().Truncate(time.Second),
+	}
+	state, err := planState(sc, network, validators)
+	if err != nil {
+		return err
+	}
+	plan.Steps = subnet.PlanSteps(state, validators, defaultStart)
+	if network != models.Local {
+		if err := planPublicDeploy(&plan, network, state); err != nil {
+			return err
+		}
+	}
+
+	if err := subnet.WritePlan(planOutput, plan); err != nil {
+		return err
+	}
+	printPlan(plan)
+	if len(plan.Steps) > 0 {
+		ux.Logger.PrintToUser("Plan written to %s, apply it with subnet apply %s", planOutput, planOutput)
+	}
+	return nil
+}
+
+// planPublicDeploy records in plan the key paying for it, its fees and the
+// owners of the subnet it creates
+func planPublicDeploy(plan *subnet.Plan, network models.Network, state subnet.PlanState) error {
+	var err error
+	if keyName == "" {
+		if keyName, err = captureKeyName(); err != nil {
+			return err
+		}
+	}
+	if err := app.CheckProfileBinding(network, keyName); err != nil {
+		return err
+	}
+	plan.Key = app.Conf.ResolveKeyAlias(keyName)
+	if state.SubnetID != ids.Empty {
+		plan.SubnetID = state.SubnetID.String()
+	}
+	if plan.HasAction(subnet.PlanCreateSubnet) {
+		controlKeys := planControlKeys
+		if len(controlKeys) == 0 {
+			_, addrs, err := storedKeyPChainAddresses(app.GetKeyDir(), network)
+			if err != nil {
+				return err
+			}
+			addr, ok := addrs[plan.Key]
+			if !ok {
+				return exitcodes.UserInput(fmt.Errorf("key %s not found", plan.Key))
+			}
+			controlKeys = []string{addr}
+		}
+		for i, addr := range controlKeys {
+			if controlKeys[i], err = prompts.ResolvePChainAddress(app.Prompt, addr, network, "control key"); err != nil {
+				return err
+			}
+		}
+		if planThreshold == 0 || int(planThreshold) > len(controlKeys) {
+			return exitcodes.UserInput(fmt.Errorf("the --threshold must be between 1 and the %d control keys", len(controlKeys)))
+		}
+		plan.ControlKeys = controlKeys
+		plan.Threshold = planThreshold
+	}
+
+	fees, err := subnet.GetFeeParams(app, network)
+	if err != nil {
+		return err
+	}
+	for _, step := range plan.Steps {
+		switch step.Action {
+		case subnet.PlanCreateSubnet:
+			plan.Fees += fees.CreateSubnetTxFee
+		case subnet.PlanCreateBlockchain:
+			plan.Fees += fees.CreateBlockchainTxFee
+		case subnet.PlanAddValidator:
+			plan.Fees += fees.TxFee
+		}
+	}
+	return nil
+}
+
+// planState returns the state of the subnet of sc on network, and which of
+// the validators already validate it
+func planState(sc models.Sidecar, network models.Network, validators []subnet.ValidatorEntry) (subnet.PlanState, error) {
+	networkKey := network.String()
+	if network == models.Local {
+		networkKey = localNetworkKey()
+	}
+	deployment := sc.Networks[networkKey]
+	state := subnet.PlanState{
+		SubnetID:     deployment.SubnetID,
+		BlockchainID: deployment.BlockchainID,
+		Validators:   map[ids.NodeID]bool{},
+	}
+	if network == models.Local || state.SubnetID == ids.Empty || len(validators) == 0 {
+		return state, nil
+	}
+	nodeIDs := make([]ids.NodeID, len(validators))
+	for i, v := range validators {
+		nodeIDs[i] = v.NodeID
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	deployer := subnet.NewPublicDeployer(app, "", network)
+	var err error
+	state.Validators, err = deployer.FetchValidators(ctx, state.SubnetID, nodeIDs)
+	return state, err
+}
+
+// resolvedGenesisHash returns the hash of the genesis of subnetName resolved
+// for network
+func resolvedGenesisHash(subnetName string, network models.Network, vars map[string]string) (string, error) {
+	genesisPath, cleanup, err := app.ResolveGenesisFile(subnetName, network, vars)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	return subnet.GenesisHash(genesisPath)
+}
+
+func printPlan(plan subnet.Plan) {
+	if len(plan.Steps) == 0 {
+		ux.Logger.PrintToUser("Subnet %s is up to date on %s, nothing to do", plan.Subnet, plan.Network)
+		return
+	}
+	ux.Logger.PrintToUser("Plan for subnet %s on %s:", plan.Subnet, plan.Network)
+	for _, step := range plan.Steps {
+		switch step.Action {
+		case subnet.PlanAddValidator:
+			start := "shortly after apply"
+			if step.Start != nil {
+				start = ux.FormatTime(*step.Start, time.Now())
+			}
+			ux.Logger.PrintToUser("  %s %s, weight %d, from %s for %s", step.Action, step.NodeID, step.Weight, start, step.Duration)
+		default:
+			ux.Logger.PrintToUser("  %s", step.Action)
+		}
+	}
+	if plan.Fees > 0 {
+		ux.Logger.PrintToUser("Fees: %s AVAX paid by key %s", subnet.FormatAVAX(plan.Fees), plan.Key)
+	}
+}
+
+func applyPlan(cmd *cobra.Command, args []string) error {
+	plan, err := subnet.LoadPlan(args[0])
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return exitcodes.UserInput(fmt.Errorf("plan %s not found", args[0]))
+		}
+		return exitcodes.UserInput(err)
+	}
+	network := models.NetworkFromString(plan.Network)
+	if network == models.Undefined {
+		return exitcodes.UserInput(fmt.Errorf("plan %s is for unknown network %q", args[0], plan.Network))
+	}
+	if !app.GenesisExists(plan.Subnet) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", plan.Subnet))
+	}
+	sc, err := app.LoadSidecar(plan.Subnet)
+	if err != nil {
+		return err
+	}
+	genesisPath, cleanup, err := app.ResolveGenesisFile(plan.Subnet, network, plan.GenesisVars)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	genesisHash, err := subnet.GenesisHash(genesisPath)
+	if err != nil {
+		return err
+	}
+	if genesisHash != plan.GenesisHash {
+		return exitcodes.UserInput(fmt.Errorf("the genesis of subnet %s changed since the plan was made, make a new plan with subnet plan", plan.Subnet))
+	}
+	validators, err := subnet.ValidatorEntries(plan.Steps, time.Time{})
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	state, err := planState(sc, network, validators)
+	if err != nil {
+		return err
+	}
+	if err := plan.CheckUpToDate(state); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if len(plan.Steps) == 0 {
+		ux.Logger.PrintToUser("Nothing to apply")
+		return nil
+	}
+	printPlan(plan)
+
+	if network == models.Local {
+		return deployToLocalNetwork(plan.Subnet, genesisPath)
+	}
+	return applyPublicPlan(plan, network, sc, state, genesisPath)
+}
+
+func applyPublicPlan(plan subnet.Plan, network models.Network, sc models.Sidecar, state subnet.PlanState, genesisPath string) error {
+	clock := networkClock(network)
+	for _, step := range plan.Steps {
+		if step.Start != nil && step.Start.Before(clock.Now().Add(constants.StakingStartLeadTime)) {
+			return exitcodes.UserInput(fmt.Errorf("validator %s was planned to start at %s, which has passed, make a new plan with subnet plan",
+				step.NodeID, ux.FormatTime(*step.Start, clock.Now())))
+		}
+	}
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(plan.Key), network)
+	if err := deployer.CheckFunding(plan.Fees); err != nil {
+		return err
+	}
+
+	subnetID := state.SubnetID
+	if plan.HasAction(subnet.PlanCreateSubnet) {
+		vmBinaryHash, err := subnet.VMBinaryHash(app, sc, "")
+		if err != nil {
+			return err
+		}
+		var blockchainID ids.ID
+		subnetID, blockchainID, err = deployer.Deploy(plan.ControlKeys, plan.Threshold, sc, genesisPath)
+		if err != nil {
+			return err
+		}
+		if sc.Networks == nil {
+			sc.Networks = make(map[string]models.NetworkData)
+		}
+		sc.Networks[network.String()] = models.NetworkData{
+			SubnetID:     subnetID,
+			BlockchainID: blockchainID,
+			VMBinaryHash: vmBinaryHash,
+		}
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+
+	validators, err := subnet.ValidatorEntries(plan.Steps, clock.Now().Add(constants.StakingStartLeadTime))
+	if err != nil {
+		return err
+	}
+	if len(validators) == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("Issuing transactions to add %d validators...", len(validators))
+	failed, err := deployer.AddValidators(subnetID, validators, constants.DefaultValidatorTxParallelism, func(u subnet.ValidatorTxUpdate) {
+		switch u.Status {
+		case subnet.ValidatorTxFailed:
+			ux.Logger.PrintToUser("%s: failed: %s", u.Validator.NodeID, u.Err)
+		case subnet.ValidatorTxCommitted:
+			ux.Logger.PrintToUser("%s: added with tx %s", u.Validator.NodeID, u.TxID)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d validators failed to be added", failed, len(validators))
+	}
+	ux.Logger.PrintToUser("Plan applied")
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promoteFrom string
+	promoteTo   string
+)
+
+// avalanche subnet promote
+func newPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote [subnetName]",
+		Short: "Deploy the genesis deployed to an environment to the next one",
+		Long: `The subnet promote command deploys a subnet to the project environment of
+--to, with the exact genesis it was deployed with to the environment of
+--from, and records that the deploy was promoted from there.
+
+The environments are set in the project config (` + constants.ProjectConfigFileName + `) under
+environments, each with the network it deploys to, and optionally the key
+deploying and the values of the genesis template variables. The promotion
+is refused if the genesis of the subnet changed since it was deployed to
+the environment of --from. subnet describe prints which environment each
+deploy is for, and the one it was promoted from.`,
+		RunE:         promoteSubnet,
+		Args:         cobra.RangeArgs(0, 1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&promoteFrom, "from", "", "environment the genesis is promoted from")
+	cmd.Flags().StringVar(&promoteTo, "to", "", "environment to deploy the genesis to")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use for public deploys (default the key of the environment)")
+	addGenesisVarFlag(cmd)
+	return cmd
+}
+
+func promoteSubnet(cmd *cobra.Command, args []string) error {
+	if promoteFrom == "" || promoteTo == "" {
+		return exitcodes.UserInput(fmt.Errorf("the environments to promote from and to must be set with --from and --to"))
+	}
+	if promoteFrom == promoteTo {
+		return exitcodes.UserInput(fmt.Errorf("can't promote %s to itself", promoteFrom))
+	}
+	subnetName, err := subnetNameFromArgs(args)
+	if err != nil {
+		return err
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	from, err := environmentDeployment(subnetName, promoteFrom)
+	if err != nil {
+		return err
+	}
+	to, err := environmentDeployment(subnetName, promoteTo)
+	if err != nil {
+		return err
+	}
+	genesisHash, err := subnet.GenesisHash(app.GetGenesisPath(subnetName))
+	if err != nil {
+		return err
+	}
+	if err := checkPromotion(subnetName, genesisHash, from, to); err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Promoting %s from %s to %s", subnetName, promoteFrom, promoteTo)
+	deployEnvironment = promoteTo
+	promotedFrom = promoteFrom
+	return deploySubnet(cmd, []string{subnetName})
+}
+
+// envDeployment is the deploy of a subnet to the network of an environment
+type envDeployment struct {
+	Environment string
+	Network     models.Network
+	Data        models.NetworkData
+}
+
+// environmentDeployment returns the deploy of subnetName to the network of
+// environment env, if any
+func environmentDeployment(subnetName string, env string) (envDeployment, error) {
+	environment, err := app.Conf.Environment(env)
+	if err != nil {
+		return envDeployment{}, exitcodes.UserInput(err)
+	}
+	network, err := networkFromFlag("network", environment.Network)
+	if err != nil {
+		return envDeployment{}, err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return envDeployment{}, err
+	}
+	networkKey := network.String()
+	if network == models.Local {
+		networkKey = localNetworkKey()
+	}
+	return envDeployment{Environment: env, Network: network, Data: sc.Networks[networkKey]}, nil
+}
+
+// checkPromotion returns an error if the genesis of subnetName, of hash
+// genesisHash, can't be promoted from the deploy from to the one to
+func checkPromotion(subnetName string, genesisHash string, from, to envDeployment) error {
+	if from.Data.BlockchainID == ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("%s is not deployed to %s (%s), deploy it with subnet deploy --environment %s",
+			subnetName, from.Environment, from.Network, from.Environment))
+	}
+	if from.Data.Environment != "" && from.Data.Environment != from.Environment {
+		return exitcodes.UserInput(fmt.Errorf("%s was deployed to %s for environment %s, not %s",
+			subnetName, from.Network, from.Data.Environment, from.Environment))
+	}
+	if from.Data.GenesisHash == "" {
+		return exitcodes.UserInput(fmt.Errorf("the genesis %s was deployed with to %s was not recorded, it can't be promoted", subnetName, from.Environment))
+	}
+	if genesisHash != from.Data.GenesisHash {
+		return exitcodes.UserInput(fmt.Errorf(
+			"the genesis of %s changed since it was deployed to %s, restore the one deployed with subnet history --restore, or deploy the new one to %s first",
+			subnetName, from.Environment, from.Environment,
+		))
+	}
+	if to.Data.BlockchainID != ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("%s is already deployed to %s (%s)", subnetName, to.Environment, to.Network))
+	}
+	return nil
+}
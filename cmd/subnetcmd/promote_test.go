@@ -0,0 +1,44 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPromotion(t *testing.T) {
+	assert := assert.New(t)
+
+	dev := envDeployment{
+		Environment: "dev",
+		Network:     models.Local,
+		Data:        models.NetworkData{BlockchainID: ids.GenerateTestID(), GenesisHash: "abc", Environment: "dev"},
+	}
+	staging := envDeployment{Environment: "staging", Network: models.Fuji}
+	assert.NoError(checkPromotion("mySubnet", "abc", dev, staging))
+
+	// the genesis changed since it was deployed to dev
+	assert.ErrorContains(checkPromotion("mySubnet", "def", dev, staging), "changed since it was deployed to dev")
+
+	// staging is not deployed yet
+	assert.ErrorContains(checkPromotion("mySubnet", "abc", staging, dev), "not deployed to staging")
+
+	// staging is already deployed
+	deployed := staging
+	deployed.Data = models.NetworkData{BlockchainID: ids.GenerateTestID()}
+	assert.ErrorContains(checkPromotion("mySubnet", "abc", dev, deployed), "already deployed to staging")
+
+	// the deploy to the network was made for another environment
+	other := dev
+	other.Data.Environment = "qa"
+	assert.ErrorContains(checkPromotion("mySubnet", "abc", other, staging), "for environment qa, not dev")
+
+	// deploys predating the environments don't record their genesis
+	unrecorded := dev
+	unrecorded.Data.GenesisHash = ""
+	assert.ErrorContains(checkPromotion("mySubnet", "abc", unrecorded, staging), "was not recorded")
+}
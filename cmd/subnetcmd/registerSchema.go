@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/utils/storage"
+	"github.com/spf13/cobra"
+)
+
+var forceRegisterSchema bool
+
+// avalanche subnet register-schema
+func newRegisterSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-schema [vmName] [schemaFile]",
+		Short: "Register the JSON schema of the genesis of a custom VM",
+		Long: `The subnet register-schema command registers a JSON schema describing the
+genesis format of a custom VM, under the name the VM is registered under:
+the --vm-alias given to subnet create, or else the subnet name.
+
+subnet create --custom then checks the genesis of the VM against the schema,
+prompting for the required properties the genesis misses first, and refuses
+a genesis which doesn't follow it. subnet validate-genesis checks existing
+genesis the same way.
+
+The schema is checked with a subset of JSON Schema: type, enum, properties,
+required, additionalProperties, items, minimum, maximum, minLength,
+maxLength and pattern. The title, description and default of a required
+property are used to prompt for it. Schemas using other keywords, such as
+$ref or oneOf, are refused.
+
+Use --force to replace the schema registered for a VM already.`,
+		RunE:         registerSchema,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceRegisterSchema, forceFlag, "f", false, "replace the schema registered for the VM")
+	return cmd
+}
+
+func registerSchema(cmd *cobra.Command, args []string) error {
+	vmName, schemaFile := args[0], args[1]
+	if err := vm.CheckSchemaVMName(vmName); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if exists, _ := storage.FileExists(app.GetGenesisSchemaPath(vmName)); exists && !forceRegisterSchema {
+		return exitcodes.UserInput(fmt.Errorf("a genesis schema is registered for %s already, use --%s to replace it", vmName, forceFlag))
+	}
+	schemaBytes, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("failed reading the schema: %w", err))
+	}
+	if err := vm.SaveGenesisSchema(app, vmName, schemaBytes); err != nil {
+		return exitcodes.UserInput(fmt.Errorf("invalid schema %s: %w", schemaFile, err))
+	}
+	ux.Logger.PrintToUser("Registered the genesis schema of %s", vmName)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/spf13/cobra"
+)
+
+var renderNetwork string
+
+// avalanche subnet render
+func newRenderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render [subnetName]",
+		Short: "Print the genesis a deploy would use",
+		Long: `The subnet render command prints the genesis of a subnet with its template
+variables resolved as subnet deploy would for the network, without deploying
+anything.
+
+A genesis is a template when it references variables such as
+{{.TreasuryAddress}}, so that the same subnet can be deployed to several
+networks with different values. The values are set per network in the
+project config (` + constants.ProjectConfigFileName + `) under genesis-vars, and
+--genesis-var overrides them. A genesis which is not a template is printed
+as is.`,
+		RunE:         renderGenesis,
+		Args:         cobra.RangeArgs(0, 1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&renderNetwork, "network", "", "network to resolve the variables for [local, fuji, mainnet] (default network of the project config, or local)")
+	addGenesisVarFlag(cmd)
+	return cmd
+}
+
+func addGenesisVarFlag(cmd *cobra.Command) {
+	cmd.Flags().StringToStringVar(&genesisVars, "genesis-var", nil, "value of a genesis template variable, as Name=value")
+}
+
+func renderGenesis(cmd *cobra.Command, args []string) error {
+	subnetName, err := subnetNameFromArgs(args)
+	if err != nil {
+		return err
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	networkName := renderNetwork
+	if networkName == "" {
+		networkName = app.Conf.DefaultNetwork()
+	}
+	if networkName == "" {
+		networkName = "local"
+	}
+	network, err := networkFromFlag("network", networkName)
+	if err != nil {
+		return err
+	}
+	rendered, err := app.LoadGenesis(subnetName, network, genesisVars)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(rendered))
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sendFrom   string
+	sendTo     string
+	sendAmount string
+	sendRPCURL string
+)
+
+// avalanche subnet send
+func newSendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send [subnetName]",
+		Short: "Send native tokens on the chain of a subnet with a stored key",
+		Long: `The subnet send command transfers native tokens of a subnet-evm chain from
+the C-Chain address of a stored key, signing the transaction with it, and
+waits for the transaction to be accepted.
+
+--to is the receiving address, or the name of a stored key to send to its
+C-Chain address. --amount is in tokens, with up to 18 decimals.
+
+The transaction is sent to the chain running on the local network, unless
+--rpc-url gives the RPC of the chain, e.g. on a public network.`,
+		RunE:         sendTokens,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&sendFrom, "from", "", "key sending the tokens")
+	cmd.Flags().StringVar(&sendTo, "to", "", "address or key receiving the tokens")
+	cmd.Flags().StringVar(&sendAmount, "amount", "", "amount of tokens to send")
+	cmd.Flags().StringVar(&sendRPCURL, "rpc-url", "", "RPC URL of the chain (default the one on the local network)")
+	return cmd
+}
+
+func sendTokens(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s is not a subnet-evm subnet", subnetName))
+	}
+	if sendFrom == "" || sendTo == "" || sendAmount == "" {
+		return exitcodes.UserInput(errors.New("--from, --to and --amount are required"))
+	}
+	amount, err := subnet.ParseTokenAmount(sendAmount, sc.GetTokenDecimals())
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if amount.Sign() == 0 {
+		return exitcodes.UserInput(errors.New("the amount to send must be positive"))
+	}
+
+	fromKey := app.Conf.ResolveKeyAlias(sendFrom)
+	if !app.KeyExists(fromKey) {
+		return exitcodes.UserInput(fmt.Errorf("key %s does not exist", fromKey))
+	}
+	if app.IsRemoteKey(fromKey) {
+		return exitcodes.UserInput(fmt.Errorf("key %s is a remote key, which can't sign EVM transactions", fromKey))
+	}
+	sk, err := key.LoadSoft(constants.LocalNetworkID, app.GetKeyPath(fromKey))
+	if err != nil {
+		return err
+	}
+	ecdsaKey, err := crypto.ToECDSA(sk.Raw())
+	if err != nil {
+		return err
+	}
+	to, err := sendRecipient(sendTo)
+	if err != nil {
+		return err
+	}
+
+	rpcURL := sendRPCURL
+	if rpcURL == "" {
+		if rpcURL, err = localRPCURL(sc); err != nil {
+			return exitcodes.UserInput(fmt.Errorf("%w, give the RPC of the chain with --rpc-url", err))
+		}
+	}
+
+	ux.Logger.PrintToUser("Sending %s %s from %s to %s...", ux.FormatAmount(amount, sc.GetTokenDecimals()), sc.TokenName, sk.C(), to.Hex())
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	txHash, err := subnet.SendNative(ctx, rpcURL, ecdsaKey, to, amount)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Transaction accepted: %s", txHash.Hex())
+	return nil
+}
+
+// sendRecipient returns the address to, or the C-Chain address of the stored
+// key named to
+func sendRecipient(to string) (common.Address, error) {
+	if common.IsHexAddress(to) {
+		return common.HexToAddress(to), nil
+	}
+	keyName := app.Conf.ResolveKeyAlias(to)
+	if !app.KeyExists(keyName) || app.IsRemoteKey(keyName) {
+		return common.Address{}, exitcodes.UserInput(fmt.Errorf("--to %q is neither an address nor a stored key", to))
+	}
+	k, err := key.LoadSoft(constants.LocalNetworkID, app.GetKeyPath(keyName))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(k.C()), nil
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsNetwork    string
+	statsGlacierURL string
+	statsMetricsURL string
+)
+
+// avalanche subnet stats
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats [subnetName]",
+		Short: "Print the activity of a subnet deployed to Fuji or mainnet",
+		Long: `The subnet stats command queries the public index APIs for the chain of a
+subnet-evm subnet deployed to Fuji or mainnet, and prints a summary of its
+health: the block height and the time of the last block, the number of
+transactions since genesis, the transactions of the latest blocks, and the
+number of validators of the subnet.
+
+The blocks come from the Glacier API and the transaction count from the
+metrics API, which may take a while to index a new chain. The validators
+come from the P-Chain of the API endpoint of the network.`,
+		RunE:         subnetStats,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&statsNetwork, "network", "fuji", "network the subnet is deployed to [fuji, mainnet]")
+	cmd.Flags().StringVar(&statsGlacierURL, "glacier-url", constants.GlacierAPIURL, "URL of the Glacier API")
+	cmd.Flags().StringVar(&statsMetricsURL, "metrics-url", constants.MetricsAPIURL, "URL of the metrics API")
+	return cmd
+}
+
+func subnetStats(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	network, err := networkFromFlag("network", statsNetwork)
+	if err != nil {
+		return err
+	}
+	if network == models.Local {
+		return exitcodes.UserInput(errors.New("the public index APIs only know the chains of fuji and mainnet, use subnet metrics for the local network"))
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s is a %s subnet, only the chains of %s subnets are indexed", subnetName, sc.VM, models.SubnetEvm))
+	}
+	deployment := sc.Networks[network.String()]
+	if deployment.BlockchainID == ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s has not been deployed to %s", subnetName, network))
+	}
+	chainID, err := strconv.ParseUint(sc.ChainID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chain ID %q of subnet %s: %w", sc.ChainID, subnetName, err)
+	}
+	api, err := app.GetAPIEndpoint(network)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	client := subnet.StatsClient{GlacierURL: statsGlacierURL, MetricsURL: statsMetricsURL}
+	stats, err := client.GetChainStats(ctx, platformvm.NewClient(api), deployment.SubnetID, chainID)
+	if err != nil {
+		return err
+	}
+	printChainStats(subnetName, network, deployment, stats)
+	return nil
+}
+
+func printChainStats(subnetName string, network models.Network, deployment models.NetworkData, stats subnet.ChainStats) {
+	ux.Logger.PrintToUser("Stats of subnet %s on %s", subnetName, network)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Parameter", "Value"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Append([]string{"Subnet ID", deployment.SubnetID.String()})
+	table.Append([]string{"Blockchain ID", deployment.BlockchainID.String()})
+	table.Append([]string{"Block Height", strconv.FormatUint(stats.BlockHeight, 10)})
+	if stats.RecentBlocks > 0 {
+		table.Append([]string{"Last Block", formatStatsDuration(time.Since(stats.LastBlockTime)) + " ago"})
+	}
+	txCount := "not indexed yet"
+	if stats.TxCount != nil {
+		txCount = strconv.FormatUint(*stats.TxCount, 10)
+	}
+	table.Append([]string{"Transactions", txCount})
+	if stats.RecentBlocks > 0 {
+		table.Append([]string{"Recent Activity", fmt.Sprintf("%d txs in the last %d blocks, over %s",
+			stats.RecentTxs, stats.RecentBlocks, formatStatsDuration(stats.LastBlockTime.Sub(stats.RecentSince)))})
+	}
+	table.Append([]string{"Validators", strconv.Itoa(stats.Validators)})
+	table.Render()
+}
+
+// formatStatsDuration formats d to the second
+func formatStatsDuration(d time.Duration) string {
+	formatted := strings.TrimSpace(ux.FormatDuration(d.Round(time.Second)))
+	if formatted == "" {
+		return "0 seconds"
+	}
+	return formatted
+}
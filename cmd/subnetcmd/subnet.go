@@ -37,6 +37,10 @@ manage your subnet configurations and live deployments.`,
 	cmd.AddCommand(newDeleteCmd())
 	// subnet deploy
 	cmd.AddCommand(newDeployCmd())
+	// subnet undeploy
+	cmd.AddCommand(newUndeployCmd())
+	// subnet configure
+	cmd.AddCommand(newConfigureCmd())
 	// subnet describe
 	cmd.AddCommand(newDescribeCmd())
 	// subnet list
@@ -45,5 +49,51 @@ manage your subnet configurations and live deployments.`,
 	cmd.AddCommand(newJoinCmd())
 	// subnet addValidator
 	cmd.AddCommand(newAddValidatorCmd())
+	// subnet lint
+	cmd.AddCommand(newLintCmd())
+	// subnet verify
+	cmd.AddCommand(newVerifyCmd())
+	// subnet clone
+	cmd.AddCommand(newCloneCmd())
+	// subnet metrics
+	cmd.AddCommand(newMetricsCmd())
+	// subnet cost
+	cmd.AddCommand(newCostCmd())
+	// subnet upgrade
+	cmd.AddCommand(newUpgradeCmd())
+	// subnet upgradeGenesis
+	cmd.AddCommand(newUpgradeGenesisCmd())
+	// subnet history
+	cmd.AddCommand(newHistoryCmd())
+	// subnet render
+	cmd.AddCommand(newRenderCmd())
+	// subnet stats
+	cmd.AddCommand(newStatsCmd())
+	// subnet plan
+	cmd.AddCommand(newPlanCmd())
+	// subnet apply
+	cmd.AddCommand(newApplyCmd())
+	// subnet governance
+	cmd.AddCommand(newGovernanceCmd())
+	// subnet promote
+	cmd.AddCommand(newPromoteCmd())
+	// subnet deploy-status
+	cmd.AddCommand(newDeployStatusCmd())
+	// subnet accounts
+	cmd.AddCommand(newAccountsCmd())
+	// subnet send
+	cmd.AddCommand(newSendCmd())
+	// subnet conformance
+	cmd.AddCommand(newConformanceCmd())
+	// subnet docs
+	cmd.AddCommand(newDocsCmd())
+	// subnet validators
+	cmd.AddCommand(newValidatorsCmd())
+	// subnet register-schema
+	cmd.AddCommand(newRegisterSchemaCmd())
+	// subnet validate-genesis
+	cmd.AddCommand(newValidateGenesisCmd())
+	// subnet verify-binary
+	cmd.AddCommand(newVerifyBinaryCmd())
 	return cmd
 }
@@ -0,0 +1,61 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var undeployLocal bool
+
+// avalanche subnet undeploy
+func newUndeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undeploy [subnetName]",
+		Short: "Remove a subnet from the local network",
+		Long: `The subnet undeploy command removes the blockchain of a subnet from the
+running local network, while the other deployed subnets keep running. The
+nodes are restarted without tracking the subnet, and its VM binary is
+removed unless another subnet uses it. The subnet can be deployed again
+afterwards.
+
+The blockchain stays registered on the P-Chain of the local network until
+network clean. Blockchains of public networks can't be undeployed.`,
+		RunE:         undeploySubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&undeployLocal, "local", "l", false, "undeploy from the local network")
+	return cmd
+}
+
+func undeploySubnet(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !undeployLocal {
+		return exitcodes.UserInput(errors.New("only local deployments can be undeployed, use --local"))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("failed loading subnet %s: %w", subnetName, err))
+	}
+
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	blockchainID, err := deployer.UndeployFromLocalNetwork(sc)
+	if err != nil {
+		return err
+	}
+	if _, ok := sc.Networks[localNetworkKey()]; ok {
+		delete(sc.Networks, localNetworkKey())
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return fmt.Errorf("blockchain undeployed, but failed to update sidecar: %w", err)
+		}
+	}
+	ux.Logger.PrintToUser("Blockchain %s of %s undeployed from the local network", blockchainID, subnetName)
+	return nil
+}
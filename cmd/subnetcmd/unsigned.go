@@ -0,0 +1,162 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/spf13/cobra"
+)
+
+var (
+	buildUnsigned bool
+	bundleFile    string
+	payerAddrStr  string
+)
+
+func addUnsignedFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&buildUnsigned, "unsigned", false, "only build the transaction, to be signed with transaction sign and broadcast with transaction broadcast")
+	cmd.Flags().StringVar(&bundleFile, "output-file", "", "file to write the unsigned transaction to (default <subnetName>_<transaction>.json)")
+	cmd.Flags().StringVar(&payerAddrStr, "payer", "", "P-Chain address paying the fees of the unsigned transaction (default the address of the key)")
+}
+
+// unsignedPayer returns the address paying for the unsigned transactions,
+// given with --payer or derived from the key
+func unsignedPayer(network models.Network) (ids.ShortID, error) {
+	if payerAddrStr != "" {
+		payerAddr, err := prompts.ResolvePChainAddress(app.Prompt, payerAddrStr, network, "payer address")
+		if err != nil {
+			return ids.ShortEmpty, err
+		}
+		payer, err := address.ParseToID(payerAddr)
+		if err != nil {
+			return ids.ShortEmpty, exitcodes.UserInput(fmt.Errorf("invalid payer address %s: %w", payerAddr, err))
+		}
+		return payer, nil
+	}
+	if keyName == "" {
+		var err error
+		keyName, err = captureKeyName()
+		if err != nil {
+			return ids.ShortEmpty, err
+		}
+	}
+	if err := app.CheckProfileBinding(network, keyName); err != nil {
+		return ids.ShortEmpty, err
+	}
+	keyName = app.Conf.ResolveKeyAlias(keyName)
+	return subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network).PayerAddress()
+}
+
+// deployUnsigned builds the next transaction needed to deploy chain to
+// network: the one creating the subnet, and once it is broadcast, the one
+// creating the blockchain
+func deployUnsigned(network models.Network, chain, chainGenesis string) error {
+	if network == models.Local {
+		return exitcodes.UserInput(fmt.Errorf("--unsigned is only supported for public networks"))
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return err
+	}
+	payer, err := unsignedPayer(network)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network)
+
+	var bundle *subnet.TxBundle
+	netData := sc.Networks[network.String()]
+	switch {
+	case netData.SubnetID == ids.Empty:
+		controlKeys, cancelled, err := getControlKeys(network)
+		if err != nil {
+			return err
+		}
+		if cancelled {
+			ux.Logger.PrintToUser("User cancelled. No subnet deployed")
+			return nil
+		}
+		threshold, err := getThreshold(uint64(len(controlKeys)))
+		if err != nil {
+			return err
+		}
+		governance, err := captureGovernance(network, controlKeys, threshold)
+		if err != nil {
+			return err
+		}
+		bundle, err = deployer.BuildUnsignedCreateSubnet(payer, controlKeys, threshold, sc)
+		if err != nil {
+			return err
+		}
+		bundle.Governance = governance
+	case netData.BlockchainID == ids.Empty:
+		ux.Logger.PrintToUser("Subnet %s is already created, building the transaction creating the blockchain", netData.SubnetID)
+		bundle, err = deployer.BuildUnsignedCreateChain(payer, netData.SubnetID, sc, chainGenesis)
+		if err != nil {
+			return err
+		}
+	default:
+		return exitcodes.UserInput(fmt.Errorf("%s is already deployed to %s", chain, network))
+	}
+	if err := saveBundle(bundle); err != nil {
+		return err
+	}
+	if bundle.Kind == subnet.BundleCreateSubnetTx {
+		ux.Logger.PrintToUser("Once it is broadcast, run subnet deploy --unsigned again to build the transaction creating the blockchain.")
+	}
+	return nil
+}
+
+// addValidatorUnsigned builds the transaction adding nodeID as a validator
+// of the subnet
+func addValidatorUnsigned(
+	network models.Network,
+	subnetName string,
+	subnetID ids.ID,
+	nodeID ids.NodeID,
+	weight uint64,
+	start time.Time,
+	duration time.Duration,
+) error {
+	payer, err := unsignedPayer(network)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), network)
+	bundle, err := deployer.BuildUnsignedAddSubnetValidator(payer, subnetName, subnetID, nodeID, weight, start, duration)
+	if err != nil {
+		return err
+	}
+	return saveBundle(bundle)
+}
+
+// saveBundle writes bundle to the output file, and tells how to get it
+// signed and broadcast
+func saveBundle(bundle *subnet.TxBundle) error {
+	path := bundleFile
+	if path == "" {
+		path = fmt.Sprintf("%s_%s.json", bundle.SubnetName, bundle.Kind)
+	}
+	if err := bundle.Save(path); err != nil {
+		return fmt.Errorf("failed writing the unsigned transaction: %w", err)
+	}
+	_, needed, err := bundle.Signatures()
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Unsigned %s written to %s, it needs %d signatures.", bundle.Kind, path, needed)
+	ux.Logger.PrintToUser("Sign it with each of the required keys, on air-gapped machines if wanted:")
+	ux.Logger.PrintToUser("  avalanche transaction sign %s --key <keyName> --offline", path)
+	ux.Logger.PrintToUser("then broadcast it with:")
+	ux.Logger.PrintToUser("  avalanche transaction broadcast %s", path)
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradePrecompile string
+	upgradeTime       string
+	upgradeDisable    bool
+	upgradeAdmins     []string
+)
+
+// avalanche subnet upgrade
+func newUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade [subnetName]",
+		Short: "Schedule the activation or deactivation of a precompile",
+		Long: `The subnet upgrade command schedules a network upgrade of a subnet-evm chain,
+enabling or disabling one of its stateful precompiles from the given time,
+and records it in the subnet configuration. Without --precompile, it lists
+the scheduled upgrades.
+
+The upgrade must be deployed to the nodes of the chain before its activation
+time, and needs nodes running subnet-evm ` + constants.PrecompileUpgradesSubnetEVMVersion + ` or later. subnet describe and
+network status remind of the upcoming upgrades.`,
+		RunE:         upgradeSubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&upgradePrecompile, "precompile", "", fmt.Sprintf("precompile to upgrade %v", vm.Precompiles))
+	cmd.Flags().StringVar(&upgradeTime, "time", "", "activation time of the upgrade, in RFC3339 format or as a unix timestamp")
+	cmd.Flags().BoolVar(&upgradeDisable, "disable", false, "disable the precompile instead of enabling it")
+	cmd.Flags().StringSliceVar(&upgradeAdmins, "admin-address", nil, "admin addresses of the allow list of the enabled precompile")
+	return cmd
+}
+
+// parseUpgradeTime parses an RFC3339 time or a unix timestamp
+func parseUpgradeTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	unix, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid activation time %q, expected RFC3339 format or a unix timestamp", s)
+	}
+	return time.Unix(unix, 0), nil
+}
+
+func upgradeSubnet(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("failed loading subnet %s: %w", subnetName, err))
+	}
+	now := time.Now()
+
+	if upgradePrecompile == "" {
+		if len(sc.Upgrades) == 0 {
+			ux.Logger.PrintToUser("No upgrades scheduled for %s", subnetName)
+			return nil
+		}
+		vm.PrintUpgrades(os.Stdout, sc, now)
+		return nil
+	}
+	if upgradeTime == "" {
+		return exitcodes.UserInput(fmt.Errorf("the activation time of the upgrade must be given with --time"))
+	}
+	activation, err := parseUpgradeTime(upgradeTime)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return err
+	}
+	upgrade := models.NetworkUpgrade{
+		Precompile:     upgradePrecompile,
+		Timestamp:      activation.Unix(),
+		Disable:        upgradeDisable,
+		AdminAddresses: upgradeAdmins,
+	}
+	if err := vm.ScheduleUpgrade(&sc, genesis, upgrade, now); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Upgrade of %s scheduled at %s", upgradePrecompile, activation.UTC().Format(time.RFC3339))
+	warnOldSubnetEVM()
+	return nil
+}
+
+// warnOldSubnetEVM warns if the subnet-evm version in use can't honor the
+// scheduled upgrades
+func warnOldSubnetEVM() {
+	version, _ := app.Conf.SubnetEVMVersion()
+	if err := vm.SupportsUpgrades(version); err != nil {
+		ux.Logger.PrintToUser("Warning: %s. Upgrade the nodes before the activation time", err)
+	}
+}
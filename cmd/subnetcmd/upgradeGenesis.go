@@ -0,0 +1,102 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/spf13/cobra"
+)
+
+// suffix of the copy of the genesis kept when upgrading it
+const genesisBackupSuffix = ".bak"
+
+var upgradeGenesisDryRun bool
+
+// avalanche subnet upgradeGenesis
+func newUpgradeGenesisCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgradeGenesis [subnetName]",
+		Short: "Upgrade a genesis to the format of the subnet-evm version in use",
+		Long: `The subnet upgradeGenesis command upgrades the genesis of a subnet-evm
+subnet created with an older version to the format expected by the
+subnet-evm version in use, either the one pinned by the project or the
+default one of this tool. Renamed fields are moved and required sections
+the genesis lacks are added.
+
+The changes are previewed before the genesis is updated, and the previous
+genesis is kept next to it with a ` + genesisBackupSuffix + ` suffix. With --dry-run, only the
+preview is printed.`,
+		RunE:         upgradeGenesis,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&upgradeGenesisDryRun, "dry-run", false, "only preview the changes")
+	return cmd
+}
+
+func upgradeGenesis(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return exitcodes.UserInput(fmt.Errorf("genesis upgrades are only supported for %s subnets, %s uses %s", models.SubnetEvm, subnetName, sc.VM))
+	}
+	genesisPath := app.GetGenesisPath(subnetName)
+	genesisBytes, err := os.ReadFile(genesisPath)
+	if err != nil {
+		return err
+	}
+
+	version, _ := app.Conf.SubnetEVMVersion()
+	migration, err := vm.MigrateGenesis(genesisBytes, version)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if len(migration.Applied) == 0 {
+		ux.Logger.PrintToUser("The genesis of %s already has the format of subnet-evm %s", subnetName, version)
+		return nil
+	}
+
+	ux.Logger.PrintToUser("Upgrading the genesis of %s to the format of subnet-evm %s:", subnetName, version)
+	for _, applied := range migration.Applied {
+		ux.Logger.PrintToUser("  * %s", applied)
+	}
+	ux.Logger.PrintToUser("")
+	for _, line := range migration.Diff {
+		ux.Logger.PrintToUser("  %s", line)
+	}
+	ux.Logger.PrintToUser("")
+	if upgradeGenesisDryRun {
+		return nil
+	}
+
+	apply, err := app.Prompt.CaptureYesNo("Apply these changes?", prompts.WithDefault(prompts.No))
+	if err != nil {
+		return err
+	}
+	if !apply {
+		ux.Logger.PrintToUser("Genesis of %s left unchanged", subnetName)
+		return nil
+	}
+	if err := os.WriteFile(genesisPath+genesisBackupSuffix, genesisBytes, application.WriteReadReadPerms); err != nil {
+		return err
+	}
+	if err := app.WriteGenesisFile(subnetName, migration.Genesis); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Genesis of %s upgraded, the previous one is kept at %s", subnetName, genesisPath+genesisBackupSuffix)
+	return nil
+}
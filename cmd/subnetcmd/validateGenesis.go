@@ -0,0 +1,166 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/genesis"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/utils/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateGenesisFile    string
+	validateGenesisVM      string
+	validateGenesisNetwork string
+)
+
+// avalanche subnet validate-genesis
+func newValidateGenesisCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-genesis [subnetName]",
+		Short: "Check the genesis of a custom VM against its registered schema",
+		Long: `The subnet validate-genesis command checks the genesis of a subnet of a
+custom VM against the JSON schema registered for the VM with subnet
+register-schema, listing every value which doesn't follow it. The command
+fails if there is any.
+
+The required properties the genesis misses are prompted for first, and the
+genesis is saved with them.
+
+To check a genesis file before creating a subnet with it, give it with
+--file, along with the name of its VM with --vm.
+
+A genesis template is checked with its variables resolved for --network, as
+subnet render prints it, and is not completed with prompts.`,
+		RunE:         validateGenesis,
+		Args:         cobra.RangeArgs(0, 1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&validateGenesisFile, "file", "", "genesis file to check instead of the genesis of a subnet")
+	cmd.Flags().StringVar(&validateGenesisVM, "vm", "", "name of the VM the --file genesis is for")
+	cmd.Flags().StringVar(&validateGenesisNetwork, "network", "", "network to resolve the variables of a template for [local, fuji, mainnet] (default network of the project config, or local)")
+	addGenesisVarFlag(cmd)
+	return cmd
+}
+
+func validateGenesis(cmd *cobra.Command, args []string) error {
+	if validateGenesisFile != "" {
+		if len(args) > 0 {
+			return exitcodes.UserInput(errors.New("give either a subnet name or --file, not both"))
+		}
+		if validateGenesisVM == "" {
+			return exitcodes.UserInput(errors.New("the VM of the --file genesis must be given with --vm"))
+		}
+		genesisBytes, err := os.ReadFile(validateGenesisFile)
+		if err != nil {
+			return exitcodes.UserInput(fmt.Errorf("failed reading the genesis: %w", err))
+		}
+		if genesis.IsTemplate(genesisBytes) {
+			return exitcodes.UserInput(errors.New("the --file genesis is a template, check it once its subnet is created"))
+		}
+		checked, err := checkCustomGenesis(validateGenesisVM, genesisBytes, app.Prompt, true)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(checked, genesisBytes) {
+			if err := os.WriteFile(validateGenesisFile, checked, application.WriteReadReadPerms); err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("Saved the values given to %s", validateGenesisFile)
+		}
+		ux.Logger.PrintToUser("The genesis follows the schema of %s", validateGenesisVM)
+		return nil
+	}
+
+	if validateGenesisVM != "" {
+		return exitcodes.UserInput(errors.New("--vm only applies to a --file genesis, the VM of a subnet is the one it was created with"))
+	}
+	subnetName, err := subnetNameFromArgs(args)
+	if err != nil {
+		return err
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.CustomVM {
+		ux.Logger.PrintToUser("Genesis schemas are only registered for custom VMs, %s uses %s", subnetName, sc.VM)
+		return nil
+	}
+	vmName := sc.GetVMName()
+	genesisBytes, err := os.ReadFile(app.GetGenesisPath(subnetName))
+	if err != nil {
+		return err
+	}
+	if genesis.IsTemplate(genesisBytes) {
+		networkName := validateGenesisNetwork
+		if networkName == "" {
+			networkName = app.Conf.DefaultNetwork()
+		}
+		if networkName == "" {
+			networkName = "local"
+		}
+		network, err := networkFromFlag("network", networkName)
+		if err != nil {
+			return err
+		}
+		rendered, err := app.LoadGenesis(subnetName, network, genesisVars)
+		if err != nil {
+			return err
+		}
+		if _, err := checkCustomGenesis(vmName, rendered, nil, true); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("The genesis of %s for %s follows the schema of %s", subnetName, network, vmName)
+		return nil
+	}
+	checked, err := checkCustomGenesis(vmName, genesisBytes, app.Prompt, true)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(checked, genesisBytes) {
+		if err := app.WriteGenesisFile(subnetName, checked); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Saved the values given to the genesis of %s", subnetName)
+	}
+	ux.Logger.PrintToUser("The genesis of %s follows the schema of %s", subnetName, vmName)
+	return nil
+}
+
+// checkCustomGenesis checks the genesis of the custom VM of vmName against the
+// schema registered for it, prompting for the required properties it misses
+// with prompt, if given. A VM without a schema passes unless mustHaveSchema.
+func checkCustomGenesis(vmName string, genesisBytes []byte, prompt prompts.Prompter, mustHaveSchema bool) ([]byte, error) {
+	if err := vm.CheckSchemaVMName(vmName); err != nil {
+		// no schema can be registered under such a name
+		if !mustHaveSchema {
+			return genesisBytes, nil
+		}
+		return nil, exitcodes.UserInput(err)
+	}
+	if mustHaveSchema {
+		if exists, _ := storage.FileExists(app.GetGenesisSchemaPath(vmName)); !exists {
+			return nil, exitcodes.UserInput(fmt.Errorf("no genesis schema is registered for %s, register one with avalanche subnet register-schema", vmName))
+		}
+	}
+	checked, err := vm.CheckCustomGenesis(app, vmName, genesisBytes, prompt)
+	if errors.Is(err, vm.ErrGenesisViolatesSchema) {
+		return nil, exitcodes.UserInput(err)
+	}
+	return checked, err
+}
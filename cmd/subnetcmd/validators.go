@@ -0,0 +1,167 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validatorsNetwork    string
+	validatorsMinVersion string
+	validatorsInfoAPI    bool
+)
+
+// avalanche subnet validators
+func newValidatorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validators",
+		Short: "Inspect the validators of a deployed subnet",
+		Long: `The subnet validators command suite provides tools to inspect the validator
+set of a subnet deployed to a network.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// subnet validators health
+	cmd.AddCommand(newValidatorsHealthCmd())
+	return cmd
+}
+
+// avalanche subnet validators health
+func newValidatorsHealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health [subnetName]",
+		Short: "Print the version and uptime of the validators of a subnet",
+		Long: `The subnet validators health command lists the current validators of the
+subnet on the P-Chain, along with the avalanchego version they run and their
+uptime, to find the nodes to upgrade ahead of a network upgrade.
+
+The version and uptime of each validator come from its own info API, on
+port 9650 of its IP, where reachable, which --info-api=false skips. For the
+others, they are as seen by the node of the API endpoint of the network: the
+version the validator reported when connecting to it, and the uptime it
+observed. The validators not connected to it are listed without either.
+
+The validators running an older version than --min-version, or than the node
+of the API endpoint if not given, are flagged as outdated, and the command
+then exits with an error.`,
+		RunE:         validatorsHealth,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&validatorsNetwork, "network", "", "network the subnet is deployed to [local, fuji, mainnet] (default network of the project config)")
+	cmd.Flags().StringVar(&validatorsMinVersion, "min-version", "", "avalanchego version the validators must run at least, e.g. v1.8.0 (default version of the API endpoint)")
+	cmd.Flags().BoolVar(&validatorsInfoAPI, "info-api", true, "query the info API of each validator, where reachable")
+	return cmd
+}
+
+func validatorsHealth(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist: %w", subnetName, err))
+	}
+	networkName := validatorsNetwork
+	if networkName == "" {
+		networkName = app.Conf.DefaultNetwork()
+	}
+	if networkName == "" {
+		return exitcodes.UserInput(errors.New("--network is required"))
+	}
+	network, err := networkFromFlag("network", networkName)
+	if err != nil {
+		return err
+	}
+	if validatorsMinVersion != "" {
+		if _, err := subnet.ParseNodeVersion(validatorsMinVersion); err != nil {
+			return exitcodes.UserInput(fmt.Errorf("invalid --min-version: %w", err))
+		}
+	}
+	deployment := sc.Networks[network.String()]
+	if network == models.Local {
+		deployment = sc.Networks[localNetworkKey()]
+	}
+	if deployment.SubnetID == ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s has not been deployed to %s", subnetName, network))
+	}
+
+	var endpoint string
+	// the nodes of the local network serve their APIs on other ports than
+	// the default one, and are all seen by the first one anyway
+	queryNodes := validatorsInfoAPI && network != models.Local
+	if network == models.Local {
+		endpoint = binutils.LocalEndpoint(app)
+		if endpoint == "" {
+			return exitcodes.UserInput(errors.New("the local network is not running"))
+		}
+	} else if endpoint, err = app.GetAPIEndpoint(network); err != nil {
+		return err
+	}
+	var nodeInfo func(ip string) subnet.NodeInfoClient
+	if queryNodes {
+		nodeInfo = func(ip string) subnet.NodeInfoClient {
+			return info.NewClient(subnet.NodeInfoURI(ip))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	health, err := subnet.GetValidatorsHealth(ctx, platformvm.NewClient(endpoint), info.NewClient(endpoint), nodeInfo, deployment.SubnetID, validatorsMinVersion)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Validators of subnet %s on %s, checked against avalanchego %s", subnetName, network, health.MinVersion)
+	printValidatorsHealth(health)
+	if outdated := health.Outdated(); len(outdated) > 0 {
+		return exitcodes.Unhealthy(fmt.Errorf("%d of %d validators run an older version than %s", len(outdated), len(health.Validators), health.MinVersion))
+	}
+	return nil
+}
+
+func printValidatorsHealth(health subnet.ValidatorsHealth) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node ID", "Weight", "Version", "Uptime", "Source", "End"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, v := range health.Validators {
+		version := v.Version
+		switch {
+		case !v.Connected && v.Version == "":
+			version = "unknown, not connected"
+		case v.Outdated:
+			version += " (outdated)"
+		}
+		uptime := "unknown"
+		if v.Uptime != nil {
+			uptime = strconv.FormatFloat(*v.Uptime, 'f', 2, 64) + "%"
+		}
+		table.Append([]string{
+			v.NodeID.String(),
+			strconv.FormatUint(v.Weight, 10),
+			version,
+			uptime,
+			string(v.Source),
+			v.End.Format(constants.TimeParseLayout),
+		})
+	}
+	table.Render()
+}
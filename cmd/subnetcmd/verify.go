@@ -0,0 +1,81 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var verifyNetwork string
+
+// avalanche subnet verify
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [subnetName]",
+		Short: "Verify a deployed blockchain uses the local genesis",
+		Long: `The subnet verify command fetches the genesis a subnet's blockchain was
+created with from the P-Chain, and compares it to the locally stored genesis.
+The genesis are first compared byte by byte, and if they differ, as JSON
+documents, listing every value which changed.
+
+Use this command to detect a local configuration edited after the deploy,
+or a deploy of a different configuration than expected.`,
+		RunE:         verifySubnet,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&verifyNetwork, "network", "fuji", "network the subnet was deployed to [fuji, mainnet]")
+	return cmd
+}
+
+func verifySubnet(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	network, err := networkFromFlag("network", verifyNetwork)
+	if err != nil {
+		return err
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	blockchainID := sc.Networks[network.String()].BlockchainID
+	if blockchainID == ids.Empty {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s has not been deployed to %s", subnetName, network))
+	}
+
+	localGenesis, err := app.LoadGenesis(subnetName, network, nil)
+	if err != nil {
+		return err
+	}
+	deployedGenesis, err := subnet.GetDeployedGenesis(app, network, blockchainID)
+	if err != nil {
+		return err
+	}
+
+	diff, err := subnet.CompareGenesis(localGenesis, deployedGenesis)
+	if err != nil {
+		return err
+	}
+	switch {
+	case diff.Identical:
+		ux.Logger.PrintToUser("The genesis of blockchain %s on %s is identical to the local genesis", blockchainID, network)
+	case len(diff.Differences) == 0:
+		ux.Logger.PrintToUser("The genesis of blockchain %s on %s is equivalent to the local genesis, only its formatting differs", blockchainID, network)
+	default:
+		ux.Logger.PrintToUser("The genesis of blockchain %s on %s differs from the local genesis:", blockchainID, network)
+		for _, d := range diff.Differences {
+			ux.Logger.PrintToUser("  %s", d)
+		}
+		return fmt.Errorf("deployed genesis of subnet %s does not match the local genesis", subnetName)
+	}
+	return nil
+}
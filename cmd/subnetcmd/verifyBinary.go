@@ -0,0 +1,157 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyBinaryNetwork string
+	// verifyPluginDir is the plugin dir of the node, and verifyPlugin the
+	// plugin itself, if installed elsewhere
+	verifyPluginDir string
+	verifyPlugin    string
+)
+
+// avalanche subnet verify-binary
+func newVerifyBinaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-binary [subnetName]",
+		Short: "Verify a node runs the VM binary deployed",
+		Long: `The subnet verify-binary command checks the VM plugin binary installed on a
+node is the one deployed, by comparing its SHA-256 to the one recorded by
+subnet deploy, to audit the binaries the validators of a subnet run.
+
+The plugin is looked up by the VM ID of the subnet in the plugin dir of the
+node, ~/.avalanchego/plugins by default, or in the one given with
+--plugin-dir. --plugin checks a plugin installed under another path. On the
+local network, the plugins of all its nodes are checked by default.`,
+		RunE:         verifyBinary,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&verifyBinaryNetwork, "network", "fuji", "network the subnet was deployed to [local, fuji, mainnet]")
+	cmd.Flags().StringVar(&verifyPluginDir, "plugin-dir", "", "plugin dir of the node (default ~/.avalanchego/plugins)")
+	cmd.Flags().StringVar(&verifyPlugin, "plugin", "", "path of the VM plugin binary to check")
+	return cmd
+}
+
+func verifyBinary(cmd *cobra.Command, args []string) error {
+	subnetName := args[0]
+	network, err := networkFromFlag("network", verifyBinaryNetwork)
+	if err != nil {
+		return err
+	}
+	if verifyPluginDir != "" && verifyPlugin != "" {
+		return exitcodes.UserInput(errors.New("--plugin-dir and --plugin are mutually exclusive"))
+	}
+	if !app.GenesisExists(subnetName) {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s does not exist", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	networkKey := network.String()
+	if network == models.Local {
+		networkKey = localNetworkKey()
+	}
+	data, ok := sc.Networks[networkKey]
+	if !ok {
+		return exitcodes.UserInput(fmt.Errorf("subnet %s has not been deployed to %s", subnetName, network))
+	}
+	if data.VMBinaryHash == "" {
+		return exitcodes.UserInput(fmt.Errorf("no VM binary was recorded by the deploy of subnet %s to %s, deploy it with this version of the CLI to record it", subnetName, network))
+	}
+
+	plugins, err := pluginsToVerify(sc, network)
+	if err != nil {
+		return err
+	}
+	mismatches := 0
+	for _, plugin := range plugins {
+		hash, err := subnet.VerifyVMBinary(plugin, data.VMBinaryHash)
+		switch {
+		case errors.Is(err, subnet.ErrVMBinaryMismatch):
+			mismatches++
+			ux.Logger.PrintToUser("MISMATCH %s: SHA-256 %s", plugin, hash)
+		case err != nil:
+			if errors.Is(err, os.ErrNotExist) {
+				return exitcodes.UserInput(fmt.Errorf("no VM plugin binary at %s", plugin))
+			}
+			return err
+		default:
+			ux.Logger.PrintToUser("OK %s", plugin)
+		}
+	}
+	if mismatches > 0 {
+		ux.Logger.PrintToUser("The VM binary deployed to %s has SHA-256 %s", network, data.VMBinaryHash)
+		return fmt.Errorf("%d VM plugin binaries of subnet %s do not match the one deployed", mismatches, subnetName)
+	}
+	ux.Logger.PrintToUser("The VM plugin binary of subnet %s matches the one deployed to %s, SHA-256 %s", subnetName, network, data.VMBinaryHash)
+	return nil
+}
+
+// pluginsToVerify returns the paths of the plugins of the VM of sc to
+// verify, the ones of the nodes of the local network if none is given
+func pluginsToVerify(sc models.Sidecar, network models.Network) ([]string, error) {
+	if verifyPlugin != "" {
+		return []string{verifyPlugin}, nil
+	}
+	if verifyPluginDir != "" {
+		path, err := subnet.PluginPath(verifyPluginDir, sc)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	if network != models.Local {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path, err := subnet.PluginPath(filepath.Join(home, ".avalanchego", "plugins"), sc)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil || status.GetClusterInfo() == nil {
+		return nil, exitcodes.UserInput(errors.New("no local network running, start it with network start or give the plugin to verify"))
+	}
+	pluginDirs := map[string]bool{}
+	for _, nodeInfo := range status.GetClusterInfo().GetNodeInfos() {
+		if pluginDir := nodeInfo.GetPluginDir(); pluginDir != "" {
+			pluginDirs[pluginDir] = true
+		}
+	}
+	plugins := []string{}
+	for pluginDir := range pluginDirs {
+		path, err := subnet.PluginPath(pluginDir, sc)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, path)
+	}
+	sort.Strings(plugins)
+	return plugins, nil
+}
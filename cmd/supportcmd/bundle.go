@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package supportcmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/support"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bundleOutput string
+	bundleLogs   int
+)
+
+// avalanche support bundle
+func newBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Archive the logs and configuration for a bug report",
+		Long: `The support bundle command writes a single archive to attach to a GitHub
+issue, holding:
+
+  - the versions of the CLI, avalanchego and subnet-evm, and the OS details
+  - the logs of the most recent invocations of the CLI
+  - the output of the backend and the node logs of the last local network run
+  - the sidecars of the subnets and the config file
+
+Private keys and the API endpoints of the config file are redacted, and key
+files are never included. Node logs are cut to their last 5MB. Check the
+content of the archive before sharing it.`,
+		RunE:         createBundle,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "file to write the archive to (default avalanche-support-<timestamp>.tar.gz)")
+	cmd.Flags().IntVar(&bundleLogs, "cli-logs", 5, "number of the most recent CLI logs to include")
+	return cmd
+}
+
+func createBundle(cmd *cobra.Command, args []string) error {
+	if bundleLogs < 0 {
+		return exitcodes.UserInput(errors.New("--cli-logs can't be negative"))
+	}
+	if bundleOutput == "" {
+		bundleOutput = fmt.Sprintf("avalanche-support-%s.tar.gz", time.Now().Format("20060102_150405"))
+	}
+	if err := app.CheckWritable("write " + bundleOutput); err != nil {
+		return err
+	}
+	names, err := support.Create(app, bundleOutput, support.Options{
+		Version:    cmd.Root().Version,
+		ConfigFile: viper.ConfigFileUsed(),
+		CLILogs:    bundleLogs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed creating the support bundle: %w", err)
+	}
+	for _, name := range names {
+		ux.Logger.PrintToUser("  %s", name)
+	}
+	ux.Logger.PrintToUser("Support bundle of %d files written to %s, attach it to your issue at https://github.com/ava-labs/avalanche-cli/issues", len(names), bundleOutput)
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/spf13/cobra"
+)
+
+// avalanche transaction broadcast
+func newBroadcastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broadcast [transactionFile]",
+		Short: "Issue a signed transaction to the network",
+		Long: `The transaction broadcast command issues the fully signed transaction in the
+file to the network it was built for, and waits for the P-Chain to accept it.
+
+The file is either one written by --unsigned and signed with transaction
+sign, or a signed P-Chain transaction exported by a wallet or an air-gapped
+signing device, hex encoded or raw, which needs --network.
+
+Once the subnet or the blockchain of a file written by --unsigned is
+created, its ID is recorded in the subnet configuration, as with a regular
+deploy.`,
+		RunE:         broadcastTx,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&broadcastNetwork, "network", "", "network to issue the transaction on [fuji, mainnet] (default the network of a file written by --unsigned)")
+	return cmd
+}
+
+var broadcastNetwork string
+
+func broadcastTx(cmd *cobra.Command, args []string) error {
+	txBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(txBytes), []byte("{")) {
+		return broadcastSignedTx(txBytes)
+	}
+	bundle, err := subnet.LoadTxBundle(args[0])
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	if broadcastNetwork != "" && !strings.EqualFold(broadcastNetwork, bundle.Network) {
+		return exitcodes.UserInput(fmt.Errorf("the %s was built for %s, not for %s", bundle.Kind, bundle.Network, broadcastNetwork))
+	}
+	present, needed, err := bundle.Signatures()
+	if err != nil {
+		return err
+	}
+	if present < needed {
+		return exitcodes.UserInput(fmt.Errorf("the %s has only %d of the %d signatures needed", bundle.Kind, present, needed))
+	}
+
+	network := models.NetworkFromString(bundle.Network)
+	deployer := subnet.NewPublicDeployer(app, "", network)
+	txID, err := deployer.Broadcast(bundle)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Transaction %s accepted, its status is %s", txID, status.Committed)
+
+	if bundle.Kind == subnet.BundleAddSubnetValidatorTx {
+		return nil
+	}
+	sc, err := app.LoadSidecar(bundle.SubnetName)
+	if err != nil {
+		return fmt.Errorf("the transaction was broadcast, but failed to load the subnet configuration: %w", err)
+	}
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	netData := sc.Networks[network.String()]
+	switch bundle.Kind {
+	case subnet.BundleCreateSubnetTx:
+		netData.SubnetID = txID
+		netData.Governance = bundle.Governance
+		ux.Logger.PrintToUser("Subnet has been created with ID: %s", txID)
+	case subnet.BundleCreateChainTx:
+		netData.BlockchainID = txID
+		ux.Logger.PrintToUser("Blockchain has been created with ID: %s", txID)
+	}
+	sc.Networks[network.String()] = netData
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return fmt.Errorf("the transaction was broadcast, but failed to update the subnet configuration: %w", err)
+	}
+	return nil
+}
+
+// broadcastSignedTx issues a signed transaction exported outside of the CLI
+// to the network of --network
+func broadcastSignedTx(txBytes []byte) error {
+	var (
+		network   models.Network
+		networkID uint32
+	)
+	switch strings.ToLower(broadcastNetwork) {
+	case "fuji":
+		network, networkID = models.Fuji, avago_constants.FujiID
+	case "mainnet":
+		network, networkID = models.Mainnet, avago_constants.MainnetID
+	case "":
+		return exitcodes.UserInput(errors.New("the network to issue the transaction on must be set with --network"))
+	default:
+		return exitcodes.UserInput(fmt.Errorf("invalid --network %q, must be one of fuji, mainnet", broadcastNetwork))
+	}
+	tx, err := subnet.ParseSignedTx(txBytes)
+	if err != nil {
+		return exitcodes.UserInput(fmt.Errorf("the file is not a signed P-Chain transaction: %w", err))
+	}
+	if txNetworkID, ok := subnet.TxNetworkID(tx); ok && txNetworkID != networkID {
+		return exitcodes.UserInput(fmt.Errorf(
+			"the transaction was built for network %s, not for %s",
+			avago_constants.NetworkName(txNetworkID), network,
+		))
+	}
+	if len(tx.Creds) == 0 {
+		return exitcodes.UserInput(errors.New("the transaction is not signed"))
+	}
+
+	deployer := subnet.NewPublicDeployer(app, "", network)
+	txID, err := deployer.BroadcastTx(tx)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Transaction %s accepted, its status is %s", txID, status.Committed)
+	return nil
+}
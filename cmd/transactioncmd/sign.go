@@ -0,0 +1,88 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keyName string
+	offline bool
+)
+
+// avalanche transaction sign
+func newSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign [transactionFile]",
+		Short: "Add the signatures of a key to a transaction",
+		Long: `The transaction sign command adds the signatures of the given key to the
+transaction in the file, wherever the key is required, and writes it back.
+Signatures already in the file are kept, so the file can be passed from one
+key holder to the next.
+
+Signing with a local key never reaches the network. Use --offline to make sure
+of it, which refuses keys held by a remote signer.`,
+		RunE:         signTx,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "the key to sign with")
+	cmd.Flags().BoolVar(&offline, "offline", false, "refuse to sign with keys that need network access")
+	return cmd
+}
+
+func signTx(cmd *cobra.Command, args []string) error {
+	if keyName == "" {
+		keyName = app.Conf.BoundKey()
+	}
+	if keyName == "" {
+		return exitcodes.UserInput(errors.New("the key to sign with must be given with --key"))
+	}
+	if err := app.Conf.CheckProfileBinding("", keyName); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	keyName = app.Conf.ResolveKeyAlias(keyName)
+	if !app.KeyExists(keyName) {
+		return exitcodes.UserInput(fmt.Errorf("key %s does not exist", keyName))
+	}
+	if offline && app.IsRemoteKey(keyName) {
+		return exitcodes.UserInput(fmt.Errorf("key %s is held by a remote signer and can't sign offline", keyName))
+	}
+
+	bundleFile := args[0]
+	bundle, err := subnet.LoadTxBundle(bundleFile)
+	if err != nil {
+		return exitcodes.UserInput(err)
+	}
+	before, _, err := bundle.Signatures()
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, app.GetSigningKeyPath(keyName), models.NetworkFromString(bundle.Network))
+	if err := deployer.SignBundle(bundle); err != nil {
+		return err
+	}
+	present, needed, err := bundle.Signatures()
+	if err != nil {
+		return err
+	}
+	if present == before {
+		return exitcodes.UserInput(fmt.Errorf("key %s is not required to sign this %s, or has already signed it", keyName, bundle.Kind))
+	}
+	if err := bundle.Save(bundleFile); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("%s signed with %s, it has %d of the %d signatures needed.", bundle.Kind, keyName, present, needed)
+	if present == needed {
+		ux.Logger.PrintToUser("It can now be broadcast with: avalanche transaction broadcast %s", bundleFile)
+	}
+	return nil
+}
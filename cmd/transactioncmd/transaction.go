@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche transaction
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "transaction",
+		Short: "Sign and broadcast transactions built with --unsigned",
+		Long: `The transaction command suite separates building, signing and broadcasting
+the P-Chain transactions of a subnet, so that the keys never have to be on a
+machine connected to the network.
+
+Transactions built with subnet deploy --unsigned or subnet addValidator
+--unsigned are written to a file along with everything needed to sign them.
+Each holder of a required key signs the file with transaction sign, and
+once all the signatures are in, transaction broadcast issues it.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// transaction sign
+	cmd.AddCommand(newSignCmd())
+	// transaction broadcast
+	cmd.AddCommand(newBroadcastCmd())
+	return cmd
+}
@@ -0,0 +1,356 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package tutorialcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Avalanche
+
+	// keepTutorial leaves the local network and the subnet of the tutorial
+	// once it ends, to explore them
+	keepTutorial bool
+)
+
+// step is a stage of the tutorial, explained before it runs
+type step struct {
+	title       string
+	explanation string
+	run         func(s *session) error
+}
+
+// session is what the steps of the tutorial learn along the way
+type session struct {
+	// rpcURL is the RPC URL of the blockchain of the tutorial, once deployed
+	rpcURL string
+}
+
+// avalanche tutorial
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "tutorial",
+		Short: "Walk through creating, deploying and using your first subnet",
+		Long: `The tutorial command walks you through creating your first subnet, deploying
+it to a local network and sending a transaction on its blockchain, explaining
+each step before running it with the same code as the commands it stands for.
+
+The tutorial runs in a sandbox: its local network is the one of profile
+tutorial, isolated from your own, and its subnet is named tutorial. Both are
+deleted once the tutorial ends, unless --keep is given to explore them with
+--profile tutorial.`,
+		RunE:         runTutorial,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&keepTutorial, "keep", false, "keep the local network and the subnet of the tutorial once it ends")
+	return cmd
+}
+
+// steps returns the steps of the tutorial, in order
+func steps() []step {
+	return []step{
+		{
+			title: "Creating a subnet",
+			explanation: `A subnet is a set of validators running one or more blockchains. Each
+blockchain runs a virtual machine, a VM, which defines its state and its
+transactions. This tutorial uses Subnet-EVM, the VM of the C-Chain, so the
+Ethereum tools work with your chain.
+
+A blockchain starts from its genesis, which sets its chain ID, its fees and
+who holds its native token at the start. The tutorial creates a genesis with
+chain ID ` + fmt.Sprint(constants.TutorialChainID) + `, and 1 million tokens held by the address of the ewoq key,
+the test key of the local networks, which must never hold real funds.
+
+This is what avalanche subnet create does, with a wizard asking for each
+setting.`,
+			run: createSubnet,
+		},
+		{
+			title: "Deploying the subnet to a local network",
+			explanation: `A local network is a network of avalanchego nodes, running on this machine,
+where everything costs nothing and happens in seconds. Its nodes run the
+Primary Network, and the subnets deployed to it.
+
+Deploying the subnet issues two transactions on the P-Chain: one creating
+the subnet, and one creating its blockchain. The nodes then install the
+Subnet-EVM plugin and start validating the blockchain. The first deploy
+downloads avalanchego and Subnet-EVM, and starts the network, which takes a
+few minutes.
+
+This is what avalanche subnet deploy --local does.`,
+			run: deploySubnet,
+		},
+		{
+			title: "Using your blockchain",
+			explanation: `Every node serves the JSON-RPC API of your blockchain, the one of Ethereum,
+so wallets such as MetaMask, and tools such as Hardhat or Foundry, can use
+it with its RPC URL and chain ID.
+
+The tutorial checks the balance of the funded address, sends 1 token from it
+to a new address, and checks both balances once the transaction is accepted.
+
+This is what avalanche subnet accounts and avalanche subnet send do.`,
+			run: sendTokens,
+		},
+	}
+}
+
+func runTutorial(cmd *cobra.Command, args []string) error {
+	if err := enterSandbox(); err != nil {
+		return err
+	}
+	if app.GenesisExists(constants.TutorialSubnetName) {
+		restart, err := app.Prompt.CaptureYesNo(fmt.Sprintf(
+			"A subnet named %s exists already, most likely left by a previous tutorial, delete it and start over", constants.TutorialSubnetName))
+		if err != nil {
+			return err
+		}
+		if !restart {
+			return exitcodes.UserInput(fmt.Errorf("subnet %s exists already, delete it with subnet delete %s", constants.TutorialSubnetName, constants.TutorialSubnetName))
+		}
+		if err := cleanSandbox(); err != nil {
+			return err
+		}
+		if err := enterSandbox(); err != nil {
+			return err
+		}
+	}
+
+	ux.Logger.PrintToUser("Welcome to Avalanche! This tutorial creates your first subnet, deploys it to a")
+	ux.Logger.PrintToUser("local network and sends a transaction on its blockchain. It runs in a sandbox,")
+	ux.Logger.PrintToUser("the profile %s, which leaves your own subnets and networks alone.", constants.TutorialProfile)
+	s := &session{}
+	all := steps()
+	for i, st := range all {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Step %d of %d: %s", i+1, len(all), st.title)
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(st.explanation)
+		ux.Logger.PrintToUser("")
+		next, err := app.Prompt.CaptureYesNo("Continue")
+		if err != nil {
+			return abort(s, err)
+		}
+		if !next {
+			return finish(s, false)
+		}
+		if err := st.run(s); err != nil {
+			return abort(s, err)
+		}
+	}
+	return finish(s, true)
+}
+
+// enterSandbox switches to the profile of the tutorial, whose local network
+// is isolated from the ones of the user
+func enterSandbox() error {
+	app.SetProfile(constants.TutorialProfile)
+	for _, dir := range []string{app.GetRunDir(), app.GetSnapshotsDir()} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed creating the profile dir %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// cleanSandbox stops the local network of the tutorial, and deletes it with
+// its profile and the subnet of the tutorial
+func cleanSandbox() error {
+	if isRunning, err := binutils.NewProcessChecker().IsServerProcessRunning(app); err == nil && isRunning {
+		if err := binutils.KillgRPCServerProcess(app); err != nil {
+			app.Log.Warn("failed killing the server process of the tutorial: %s", err)
+		}
+	}
+	name := constants.TutorialSubnetName
+	for _, path := range []string{app.GetGenesisPath(name), app.GetSidecarPath(name), app.GetChainConfigPath(name)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.RemoveAll(app.GetHistoryDir(name)); err != nil {
+		return err
+	}
+	return os.RemoveAll(app.GetProfileDir())
+}
+
+// abort ends the tutorial on err
+func abort(s *session, err error) error {
+	if finishErr := finish(s, false); finishErr != nil {
+		app.Log.Warn("failed deleting the sandbox of the tutorial: %s", finishErr)
+	}
+	return err
+}
+
+// finish ends the tutorial, completed or not, deleting its sandbox unless
+// kept
+func finish(s *session, completed bool) error {
+	ux.Logger.PrintToUser("")
+	if completed {
+		ux.Logger.PrintToUser("Congratulations, you created, deployed and used your first subnet! Next:")
+		ux.Logger.PrintToUser("  avalanche subnet create mySubnet         create your own subnet with the wizard")
+		ux.Logger.PrintToUser("  avalanche subnet deploy mySubnet --local deploy it to your local network")
+		ux.Logger.PrintToUser("  avalanche subnet deploy mySubnet --fuji  deploy it to the Fuji testnet")
+		ux.Logger.PrintToUser("  avalanche network status                 see the nodes of your local network")
+	}
+	if keepTutorial {
+		ux.Logger.PrintToUser("The local network and the subnet of the tutorial are kept, use them with --profile %s, e.g.:", constants.TutorialProfile)
+		ux.Logger.PrintToUser("  avalanche network status --profile %s", constants.TutorialProfile)
+		if s.rpcURL != "" {
+			ux.Logger.PrintToUser("The RPC URL of the blockchain is %s, with chain ID %d", s.rpcURL, constants.TutorialChainID)
+		}
+		ux.Logger.PrintToUser("Run avalanche tutorial again to delete them and start over.")
+		return nil
+	}
+	ux.Logger.PrintToUser("Deleting the sandbox of the tutorial...")
+	return cleanSandbox()
+}
+
+func createSubnet(s *session) error {
+	tokenName, err := app.Prompt.CaptureString("Symbol of the native token of your blockchain, e.g. TKN")
+	if err != nil {
+		return err
+	}
+	tokenName = strings.ToUpper(strings.TrimSpace(tokenName))
+	allocation, err := vm.DefaultAllocation()
+	if err != nil {
+		return err
+	}
+	genesisBytes, sc, err := vm.BuildEvmGenesis(vm.CreateParams{
+		Name:       constants.TutorialSubnetName,
+		ChainID:    big.NewInt(constants.TutorialChainID),
+		TokenName:  tokenName,
+		Allocation: allocation,
+	})
+	if err != nil {
+		return err
+	}
+	if err := app.WriteGenesisFile(constants.TutorialSubnetName, genesisBytes); err != nil {
+		return err
+	}
+	if err := app.CreateSidecar(sc); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Created subnet %s, with its genesis in %s", constants.TutorialSubnetName, app.GetGenesisPath(constants.TutorialSubnetName))
+	ux.Logger.PrintToUser("and the settings of the CLI for it in %s", app.GetSidecarPath(constants.TutorialSubnetName))
+	return nil
+}
+
+func deploySubnet(s *session) error {
+	sc, err := app.LoadSidecar(constants.TutorialSubnetName)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	subnetID, blockchainID, err := deployer.DeployToLocalNetwork(sc, app.GetGenesisPath(constants.TutorialSubnetName))
+	if err != nil {
+		return err
+	}
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	sc.Networks[app.GetLocalNetworkKey()] = models.NetworkData{
+		SubnetID:     subnetID,
+		BlockchainID: blockchainID,
+		VMBinaryHash: deployer.VMBinaryHash(),
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	if s.rpcURL, err = localRPCURL(blockchainID); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Subnet %s is deployed, its blockchain %s runs on every node", subnetID, blockchainID)
+	return nil
+}
+
+// localRPCURL returns the RPC URL of blockchainID on the first node of the
+// local network
+func localRPCURL(blockchainID ids.ID) (string, error) {
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		return "", fmt.Errorf("failed to query the local network status: %w", err)
+	}
+	nodeInfos := status.GetClusterInfo().GetNodeInfos()
+	names := make([]string, 0, len(nodeInfos))
+	for name := range nodeInfos {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", errors.New("no local node running")
+	}
+	sort.Strings(names)
+	return ux.RPCEndpoint(nodeInfos[names[0]].GetUri(), blockchainID.String()), nil
+}
+
+func sendTokens(s *session) error {
+	sc, err := app.LoadSidecar(constants.TutorialSubnetName)
+	if err != nil {
+		return err
+	}
+	ewoq, err := crypto.HexToECDSA(vm.PrefundedEwoqPrivate)
+	if err != nil {
+		return err
+	}
+	recipient, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+	from, to := vm.PrefundedEwoqAddress, crypto.PubkeyToAddress(recipient.PublicKey)
+	ux.Logger.PrintToUser("RPC URL: %s", s.rpcURL)
+	ux.Logger.PrintToUser("Chain ID: %d", constants.TutorialChainID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	if err := printBalances(ctx, s, sc, from, to); err != nil {
+		return err
+	}
+	amount, err := subnet.ParseTokenAmount("1", sc.GetTokenDecimals())
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Sending 1 %s from %s to %s...", sc.TokenName, from, to)
+	txHash, err := subnet.SendNative(ctx, s.rpcURL, ewoq, to, amount)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Transaction %s accepted", txHash)
+	return printBalances(ctx, s, sc, from, to)
+}
+
+func printBalances(ctx context.Context, s *session, sc models.Sidecar, addrs ...common.Address) error {
+	balances, err := subnet.GetBalances(ctx, s.rpcURL, addrs)
+	if err != nil {
+		return err
+	}
+	for i, addr := range addrs {
+		ux.Logger.PrintToUser("  %s: %s %s", addr, ux.FormatAmount(balances[i], sc.GetTokenDecimals()), sc.TokenName)
+	}
+	return nil
+}
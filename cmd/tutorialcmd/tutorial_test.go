@@ -0,0 +1,54 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package tutorialcmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSandbox(t *testing.T) {
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	baseDir := t.TempDir()
+	prompt := &mocks.Prompter{}
+	prompt.On("CaptureString", mock.Anything).Return(" tkn ", nil)
+	app = application.New()
+	app.Setup(baseDir, logging.NoLog{}, nil, prompt)
+
+	assert.NoError(enterSandbox())
+	assert.Equal(constants.TutorialProfile, app.GetProfile())
+	profileDir := filepath.Join(baseDir, constants.ProfilesDir, constants.TutorialProfile)
+	assert.Equal(profileDir, app.GetProfileDir())
+	assert.DirExists(app.GetRunDir())
+
+	assert.NoError(createSubnet(&session{}))
+	assert.True(app.GenesisExists(constants.TutorialSubnetName))
+	sc, err := app.LoadSidecar(constants.TutorialSubnetName)
+	assert.NoError(err)
+	assert.Equal("TKN", sc.TokenName)
+	genesis, err := app.LoadEvmGenesis(constants.TutorialSubnetName)
+	assert.NoError(err)
+	assert.EqualValues(constants.TutorialChainID, genesis.Config.ChainID.Int64())
+
+	// the files of the user are left alone
+	other := filepath.Join(baseDir, "mySubnet"+constants.SidecarSuffix)
+	assert.NoError(os.WriteFile(other, []byte("{}"), 0o600))
+
+	assert.NoError(cleanSandbox())
+	assert.False(app.GenesisExists(constants.TutorialSubnetName))
+	assert.NoFileExists(app.GetSidecarPath(constants.TutorialSubnetName))
+	assert.NoDirExists(profileDir)
+	assert.FileExists(other)
+}
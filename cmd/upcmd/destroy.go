@@ -0,0 +1,66 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche up destroy
+func newDestroyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "destroy",
+		Short: "Undeploy the subnets of a topology file from the local network",
+		Long: `The up destroy command undeploys every subnet of the topology from the
+local network, every subnet before the ones it depends on, while the other
+subnets of the network keep running. The configurations of the subnets are
+kept, so up deploys them again.`,
+		RunE:         destroyTopology,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func destroyTopology(cmd *cobra.Command, args []string) error {
+	t, err := loadTopology()
+	if err != nil {
+		return err
+	}
+	clusterInfo, err := localClusterInfo()
+	if err != nil {
+		return err
+	}
+	if clusterInfo == nil {
+		ux.Logger.PrintToUser("No local network running")
+		return nil
+	}
+	ordered, err := t.Order()
+	if err != nil {
+		return err
+	}
+
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	undeployed := 0
+	for i := len(ordered) - 1; i >= 0; i-- {
+		name := ordered[i].Name
+		state, err := subnetState(name, clusterInfo)
+		if err != nil {
+			return err
+		}
+		if state.BlockchainID == "" {
+			continue
+		}
+		if err := undeploySubnet(deployer, name); err != nil {
+			return err
+		}
+		undeployed++
+	}
+	if undeployed == 0 {
+		ux.Logger.PrintToUser("None of the subnets of %s is deployed", t.Path)
+		return nil
+	}
+	ux.Logger.PrintToUser("The subnets of %s are undeployed", t.Path)
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche up diff
+func newDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff",
+		Short: "Preview the changes up would make",
+		Long: `The up diff command prints what up would do to every subnet of the
+topology, in the order it would do it, along with the changes to the genesis
+of the subnets whose genesis differs from the topology. Nothing is changed.`,
+		RunE:         topologyDiff,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func topologyDiff(cmd *cobra.Command, args []string) error {
+	t, err := loadTopology()
+	if err != nil {
+		return err
+	}
+	clusterInfo, err := localClusterInfo()
+	if err != nil {
+		return err
+	}
+	changes, _, err := plan(t, clusterInfo)
+	if err != nil {
+		return err
+	}
+	printChanges(changes, true)
+	if !hasChanges(changes) {
+		ux.Logger.PrintToUser("The subnets of %s are up to date", t.Path)
+	}
+	return nil
+}
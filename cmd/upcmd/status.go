@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/topology"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche up status
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Compare the subnets of a topology file with the local network",
+		Long: `The up status command prints, for every subnet of the topology, whether
+its configuration matches the topology, the blockchain it is deployed as on
+the local network with its liveness there, and its validators.`,
+		RunE:         topologyStatus,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func topologyStatus(cmd *cobra.Command, args []string) error {
+	t, err := loadTopology()
+	if err != nil {
+		return err
+	}
+	clusterInfo, err := localClusterInfo()
+	if err != nil {
+		return err
+	}
+	changes, states, err := plan(t, clusterInfo)
+	if err != nil {
+		return err
+	}
+
+	vmsHealth := map[string]subnet.VMHealth{}
+	if clusterInfo != nil {
+		health := subnet.CheckClusterHealth(context.Background(), clusterInfo, subnet.NewNodeHealthClient)
+		for _, vm := range health.VMs {
+			vmsHealth[vm.BlockchainID] = vm
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Subnet", "VM", "Depends On", "Configuration", "Local Network", "Validators"})
+	table.SetRowLine(true)
+	for _, change := range changes {
+		state := states[change.Subnet.Name]
+		vmType := state.VM
+		if !state.Exists {
+			vmType = change.Subnet.VMType()
+		}
+		configuration := "up to date"
+		switch change.Action {
+		case topology.ActionCreate:
+			configuration = "missing"
+		case topology.ActionUpdate, topology.ActionRedeploy:
+			configuration = strings.Join(change.Reasons, ", ")
+		}
+		deployment := "not deployed"
+		if clusterInfo == nil {
+			deployment = "not running"
+		} else if state.BlockchainID != "" {
+			deployment = state.BlockchainID
+			if vm, ok := vmsHealth[state.BlockchainID]; ok {
+				deployment += fmt.Sprintf(" (%s)", vm.Liveness)
+			}
+		}
+		validators := "-"
+		if state.BlockchainID != "" {
+			validators = fmt.Sprint(len(subnet.ValidatorNodeNames(clusterInfo)))
+		}
+		if change.Subnet.Validators > 0 {
+			validators += fmt.Sprintf(" (min %d)", change.Subnet.Validators)
+		}
+		table.Append([]string{
+			change.Subnet.Name,
+			string(vmType),
+			strings.Join(change.Subnet.DependsOn, ", "),
+			configuration,
+			deployment,
+			validators,
+		})
+	}
+	table.Render()
+	return nil
+}
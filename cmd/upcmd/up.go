@@ -0,0 +1,328 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/topology"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Avalanche
+
+	topologyFile string
+)
+
+// avalanche up
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Deploy the subnets of a topology file to the local network",
+		Long: `The up command brings the local network to the state described by a
+topology file: it creates the subnets of the topology which don't exist yet,
+updates the ones whose VM or genesis changed, and deploys them all to the
+local network, every subnet after the ones it depends on. Subnets already up
+to date are left alone, so up can be run again after editing the topology.
+
+The topology file is a YAML file, topology.yaml in the working directory by
+default:
+
+  subnets:
+    - name: tokens
+      vm: subnet-evm          # subnet-evm or custom, defaults to subnet-evm
+      genesis: ./tokens.json  # relative to the topology file
+      validators: 3           # minimum number of validators
+    - name: bridge
+      genesis: ./bridge.json
+      depends-on: [tokens]
+
+A subnet without a genesis must have been created with subnet create
+already. Every node of the local network validates every subnet, so up fails
+if a subnet requires more validators than the network has nodes.
+
+A top-level api-nodes: N adds N API nodes to the local network, api1 to apiN,
+which track every subnet without validating it, as the RPC nodes dapps use in
+production. It overrides the api-nodes of the project config.
+
+Use up diff to preview the changes, up status to compare the topology with
+the local network, and up destroy to undeploy its subnets.`,
+		RunE:         upTopology,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.PersistentFlags().StringVarP(&topologyFile, "file", "f", constants.TopologyFileName, "topology file describing the subnets")
+	// up status
+	cmd.AddCommand(newStatusCmd())
+	// up diff
+	cmd.AddCommand(newDiffCmd())
+	// up destroy
+	cmd.AddCommand(newDestroyCmd())
+	return cmd
+}
+
+func upTopology(cmd *cobra.Command, args []string) error {
+	t, err := loadTopology()
+	if err != nil {
+		return err
+	}
+	clusterInfo, err := localClusterInfo()
+	if err != nil {
+		return err
+	}
+	if clusterInfo != nil {
+		if err := topology.CheckValidators(t, len(subnet.ValidatorNodeNames(clusterInfo))); err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+	changes, _, err := plan(t, clusterInfo)
+	if err != nil {
+		return err
+	}
+	if !hasChanges(changes) {
+		ux.Logger.PrintToUser("The subnets of %s are up to date", t.Path)
+		return nil
+	}
+	printChanges(changes, false)
+
+	deployer := subnet.NewLocalSubnetDeployer(app)
+	if t.APINodes > 0 {
+		deployer.SetAPINodes(t.APINodes)
+	}
+	for _, change := range changes {
+		name := change.Subnet.Name
+		switch change.Action {
+		case topology.ActionNone:
+			continue
+		case topology.ActionCreate:
+			err = createSubnet(change.Subnet)
+		case topology.ActionUpdate:
+			err = updateSubnet(change.Subnet)
+		case topology.ActionRedeploy:
+			if err = undeploySubnet(deployer, name); err == nil {
+				err = updateSubnet(change.Subnet)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Deploying %s to the local network", name)
+		if err := deploySubnet(deployer, name); err != nil {
+			return fmt.Errorf("failed deploying %s: %w", name, err)
+		}
+	}
+
+	if clusterInfo == nil {
+		// the network was started by the first deploy
+		if clusterInfo, err = localClusterInfo(); err != nil {
+			return err
+		}
+		if err := topology.CheckValidators(t, len(subnet.ValidatorNodeNames(clusterInfo))); err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+	ux.Logger.PrintToUser("The subnets of %s are up", t.Path)
+	return nil
+}
+
+func loadTopology() (*topology.Topology, error) {
+	if _, err := os.Stat(topologyFile); err != nil {
+		return nil, exitcodes.UserInput(fmt.Errorf("topology file %s not found, use --file to give its path: %w", topologyFile, err))
+	}
+	t, err := topology.Load(topologyFile)
+	if err != nil {
+		return nil, exitcodes.UserInput(err)
+	}
+	return t, nil
+}
+
+// localClusterInfo returns the state of the local network, without the
+// undeployed blockchains, or nil if it is not running
+func localClusterInfo() (*rpcpb.ClusterInfo, error) {
+	isRunning, err := binutils.NewProcessChecker().IsServerProcessRunning(app)
+	if err != nil {
+		return nil, exitcodes.Backend(fmt.Errorf("failed querying if server process is running: %w", err))
+	}
+	if !isRunning {
+		return nil, nil
+	}
+	cli, err := binutils.NewGRPCClient(app)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	status, err := cli.Status(binutils.GetAsyncContext())
+	if err != nil {
+		// TODO: use error type not string comparison
+		if strings.Contains(err.Error(), "not bootstrapped") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return subnet.FilterUndeployed(app, status.GetClusterInfo())
+}
+
+// plan returns the changes bringing the subnets of t to their state in the
+// topology, given the local network state in clusterInfo, along with the
+// current state of the subnets by name
+func plan(t *topology.Topology, clusterInfo *rpcpb.ClusterInfo) ([]topology.Change, map[string]topology.SubnetState, error) {
+	states := map[string]topology.SubnetState{}
+	for _, spec := range t.Subnets {
+		state, err := subnetState(spec.Name, clusterInfo)
+		if err != nil {
+			return nil, nil, err
+		}
+		states[spec.Name] = state
+	}
+	changes, err := topology.Plan(t, states)
+	if err != nil {
+		return nil, nil, exitcodes.UserInput(err)
+	}
+	return changes, states, nil
+}
+
+func subnetState(name string, clusterInfo *rpcpb.ClusterInfo) (topology.SubnetState, error) {
+	if !app.GenesisExists(name) {
+		return topology.SubnetState{}, nil
+	}
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		return topology.SubnetState{}, fmt.Errorf("failed loading subnet %s: %w", name, err)
+	}
+	genesisBytes, err := os.ReadFile(app.GetGenesisPath(name))
+	if err != nil {
+		return topology.SubnetState{}, err
+	}
+	state := topology.SubnetState{Exists: true, VM: sc.VM, Genesis: genesisBytes}
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return topology.SubnetState{}, err
+	}
+	for blockchainID, vmInfo := range clusterInfo.GetCustomVms() {
+		if vmInfo.GetVmId() == vmID.String() {
+			state.BlockchainID = blockchainID
+		}
+	}
+	return state, nil
+}
+
+func hasChanges(changes []topology.Change) bool {
+	for _, change := range changes {
+		if change.Action != topology.ActionNone {
+			return true
+		}
+	}
+	return false
+}
+
+// printChanges prints the action planned for every subnet, and with
+// genesisDiff, the changes to their genesis
+func printChanges(changes []topology.Change, genesisDiff bool) {
+	for _, change := range changes {
+		if len(change.Reasons) == 0 {
+			ux.Logger.PrintToUser("%-9s %s", change.Action, change.Subnet.Name)
+			continue
+		}
+		ux.Logger.PrintToUser("%-9s %s (%s)", change.Action, change.Subnet.Name, strings.Join(change.Reasons, ", "))
+		if genesisDiff {
+			for _, line := range change.GenesisDiff {
+				ux.Logger.PrintToUser("            %s", line)
+			}
+		}
+	}
+}
+
+func createSubnet(spec topology.SubnetSpec) error {
+	ux.Logger.PrintToUser("Creating %s from %s", spec.Name, spec.Genesis)
+	if err := app.CopyGenesisFile(spec.Genesis, spec.Name); err != nil {
+		return err
+	}
+	sc := &models.Sidecar{
+		Name:   spec.Name,
+		VM:     spec.VMType(),
+		Subnet: spec.Name,
+	}
+	return app.CreateSidecar(sc)
+}
+
+func updateSubnet(spec topology.SubnetSpec) error {
+	ux.Logger.PrintToUser("Updating %s", spec.Name)
+	sc, err := app.LoadSidecar(spec.Name)
+	if err != nil {
+		return fmt.Errorf("failed loading subnet %s: %w", spec.Name, err)
+	}
+	if spec.Genesis != "" {
+		if err := app.CopyGenesisFile(spec.Genesis, spec.Name); err != nil {
+			return err
+		}
+	}
+	if spec.VM != "" {
+		sc.VM = spec.VMType()
+	}
+	return app.UpdateSidecar(&sc)
+}
+
+// deploySubnet deploys the subnet to the local network and records the
+// deployment in its sidecar
+func deploySubnet(deployer *subnet.LocalSubnetDeployer, name string) error {
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		return fmt.Errorf("failed loading subnet %s: %w", name, err)
+	}
+	genesisPath, cleanup, err := app.ResolveGenesisFile(name, models.Local, nil)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	subnetID, blockchainID, err := deployer.DeployToLocalNetwork(sc, genesisPath)
+	if err != nil {
+		if deployer.BackendStartedHere() {
+			if innerErr := binutils.KillgRPCServerProcess(app); innerErr != nil {
+				app.Log.Warn("tried to kill the gRPC server process but it failed: %s", innerErr)
+			}
+		}
+		return err
+	}
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	sc.Networks[app.GetLocalNetworkKey()] = models.NetworkData{
+		SubnetID:     subnetID,
+		BlockchainID: blockchainID,
+		VMBinaryHash: deployer.VMBinaryHash(),
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return fmt.Errorf("creation of chains and subnet was successful, but failed to update sidecar: %w", err)
+	}
+	return nil
+}
+
+// undeploySubnet removes the subnet from the local network and its
+// deployment from its sidecar
+func undeploySubnet(deployer *subnet.LocalSubnetDeployer, name string) error {
+	ux.Logger.PrintToUser("Undeploying %s from the local network", name)
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		return fmt.Errorf("failed loading subnet %s: %w", name, err)
+	}
+	if _, err := deployer.UndeployFromLocalNetwork(sc); err != nil {
+		return fmt.Errorf("failed undeploying %s: %w", name, err)
+	}
+	delete(sc.Networks, app.GetLocalNetworkKey())
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return fmt.Errorf("blockchain undeployed, but failed to update sidecar: %w", err)
+	}
+	return nil
+}
@@ -23,6 +23,27 @@ func (_m *PluginBinaryDownloader) Download(vmIDs map[string]struct{}, pluginDir
 	return r0
 }
 
+// SubnetEVMBinary provides a mock function with given fields: binDir
+func (_m *PluginBinaryDownloader) SubnetEVMBinary(binDir string) (string, error) {
+	ret := _m.Called(binDir)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(binDir)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(binDir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewPluginBinaryDownloader interface {
 	mock.TestingT
 	Cleanup(func())
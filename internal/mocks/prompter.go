@@ -12,6 +12,8 @@ import (
 
 	models "github.com/ava-labs/avalanche-cli/pkg/models"
 
+	prompts "github.com/ava-labs/avalanche-cli/pkg/prompts"
+
 	time "time"
 )
 
@@ -20,13 +22,20 @@ type Prompter struct {
 	mock.Mock
 }
 
-// CaptureAddress provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureAddress(promptStr string) (common.Address, error) {
-	ret := _m.Called(promptStr)
+// CaptureAddress provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureAddress(promptStr string, opts ...prompts.Option) (common.Address, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 common.Address
-	if rf, ok := ret.Get(0).(func(string) common.Address); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) common.Address); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(common.Address)
@@ -34,8 +43,8 @@ func (_m *Prompter) CaptureAddress(promptStr string) (common.Address, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,20 +52,27 @@ func (_m *Prompter) CaptureAddress(promptStr string) (common.Address, error) {
 	return r0, r1
 }
 
-// CaptureDate provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureDate(promptStr string) (time.Time, error) {
-	ret := _m.Called(promptStr)
+// CaptureDate provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureDate(promptStr string, opts ...prompts.Option) (time.Time, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 time.Time
-	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) time.Time); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(time.Time)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -64,20 +80,27 @@ func (_m *Prompter) CaptureDate(promptStr string) (time.Time, error) {
 	return r0, r1
 }
 
-// CaptureDuration provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureDuration(promptStr string) (time.Duration, error) {
-	ret := _m.Called(promptStr)
+// CaptureDuration provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureDuration(promptStr string, opts ...prompts.Option) (time.Duration, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 time.Duration
-	if rf, ok := ret.Get(0).(func(string) time.Duration); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) time.Duration); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(time.Duration)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -85,20 +108,27 @@ func (_m *Prompter) CaptureDuration(promptStr string) (time.Duration, error) {
 	return r0, r1
 }
 
-// CaptureExistingFilepath provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureExistingFilepath(promptStr string) (string, error) {
-	ret := _m.Called(promptStr)
+// CaptureExistingFilepath provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureExistingFilepath(promptStr string, opts ...prompts.Option) (string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) string); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,20 +136,27 @@ func (_m *Prompter) CaptureExistingFilepath(promptStr string) (string, error) {
 	return r0, r1
 }
 
-// CaptureIndex provides a mock function with given fields: promptStr, options
-func (_m *Prompter) CaptureIndex(promptStr string, options []common.Address) (int, error) {
-	ret := _m.Called(promptStr, options)
+// CaptureIndex provides a mock function with given fields: promptStr, options, opts
+func (_m *Prompter) CaptureIndex(promptStr string, options []common.Address, opts ...prompts.Option) (int, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr, options)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(string, []common.Address) int); ok {
-		r0 = rf(promptStr, options)
+	if rf, ok := ret.Get(0).(func(string, []common.Address, ...prompts.Option) int); ok {
+		r0 = rf(promptStr, options, opts...)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, []common.Address) error); ok {
-		r1 = rf(promptStr, options)
+	if rf, ok := ret.Get(1).(func(string, []common.Address, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, options, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -127,20 +164,27 @@ func (_m *Prompter) CaptureIndex(promptStr string, options []common.Address) (in
 	return r0, r1
 }
 
-// CaptureList provides a mock function with given fields: promptStr, options
-func (_m *Prompter) CaptureList(promptStr string, options []string) (string, error) {
-	ret := _m.Called(promptStr, options)
+// CaptureList provides a mock function with given fields: promptStr, options, opts
+func (_m *Prompter) CaptureList(promptStr string, options []string, opts ...prompts.Option) (string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr, options)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string, []string) string); ok {
-		r0 = rf(promptStr, options)
+	if rf, ok := ret.Get(0).(func(string, []string, ...prompts.Option) string); ok {
+		r0 = rf(promptStr, options, opts...)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, []string) error); ok {
-		r1 = rf(promptStr, options)
+	if rf, ok := ret.Get(1).(func(string, []string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, options, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -148,20 +192,27 @@ func (_m *Prompter) CaptureList(promptStr string, options []string) (string, err
 	return r0, r1
 }
 
-// CaptureNoYes provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureNoYes(promptStr string) (bool, error) {
-	ret := _m.Called(promptStr)
+// CaptureNoYes provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureNoYes(promptStr string, opts ...prompts.Option) (bool, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) bool); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -169,13 +220,20 @@ func (_m *Prompter) CaptureNoYes(promptStr string) (bool, error) {
 	return r0, r1
 }
 
-// CaptureNodeID provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
-	ret := _m.Called(promptStr)
+// CaptureNodeID provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureNodeID(promptStr string, opts ...prompts.Option) (ids.NodeID, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 ids.NodeID
-	if rf, ok := ret.Get(0).(func(string) ids.NodeID); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) ids.NodeID); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(ids.NodeID)
@@ -183,8 +241,36 @@ func (_m *Prompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CapturePChainAddress provides a mock function with given fields: promptStr, network, opts
+func (_m *Prompter) CapturePChainAddress(promptStr string, network models.Network, opts ...prompts.Option) (string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr, network)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, models.Network, ...prompts.Option) string); ok {
+		r0 = rf(promptStr, network, opts...)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, models.Network, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, network, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -192,20 +278,27 @@ func (_m *Prompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
 	return r0, r1
 }
 
-// CapturePChainAddress provides a mock function with given fields: promptStr, network
-func (_m *Prompter) CapturePChainAddress(promptStr string, network models.Network) (string, error) {
-	ret := _m.Called(promptStr, network)
+// CapturePassword provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CapturePassword(promptStr string, opts ...prompts.Option) (string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string, models.Network) string); ok {
-		r0 = rf(promptStr, network)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) string); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, models.Network) error); ok {
-		r1 = rf(promptStr, network)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -213,13 +306,20 @@ func (_m *Prompter) CapturePChainAddress(promptStr string, network models.Networ
 	return r0, r1
 }
 
-// CapturePositiveBigInt provides a mock function with given fields: promptStr
-func (_m *Prompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
-	ret := _m.Called(promptStr)
+// CapturePositiveBigInt provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CapturePositiveBigInt(promptStr string, opts ...prompts.Option) (*big.Int, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 *big.Int
-	if rf, ok := ret.Get(0).(func(string) *big.Int); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) *big.Int); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*big.Int)
@@ -227,8 +327,8 @@ func (_m *Prompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -236,20 +336,27 @@ func (_m *Prompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 	return r0, r1
 }
 
-// CaptureString provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureString(promptStr string) (string, error) {
-	ret := _m.Called(promptStr)
+// CaptureString provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureString(promptStr string, opts ...prompts.Option) (string, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) string); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -257,20 +364,27 @@ func (_m *Prompter) CaptureString(promptStr string) (string, error) {
 	return r0, r1
 }
 
-// CaptureUint64 provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureUint64(promptStr string) (uint64, error) {
-	ret := _m.Called(promptStr)
+// CaptureUint64 provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureUint64(promptStr string, opts ...prompts.Option) (uint64, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 uint64
-	if rf, ok := ret.Get(0).(func(string) uint64); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) uint64); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -278,20 +392,27 @@ func (_m *Prompter) CaptureUint64(promptStr string) (uint64, error) {
 	return r0, r1
 }
 
-// CaptureWeight provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureWeight(promptStr string) (uint64, error) {
-	ret := _m.Called(promptStr)
+// CaptureWeight provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureWeight(promptStr string, opts ...prompts.Option) (uint64, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 uint64
-	if rf, ok := ret.Get(0).(func(string) uint64); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) uint64); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -299,20 +420,27 @@ func (_m *Prompter) CaptureWeight(promptStr string) (uint64, error) {
 	return r0, r1
 }
 
-// CaptureYesNo provides a mock function with given fields: promptStr
-func (_m *Prompter) CaptureYesNo(promptStr string) (bool, error) {
-	ret := _m.Called(promptStr)
+// CaptureYesNo provides a mock function with given fields: promptStr, opts
+func (_m *Prompter) CaptureYesNo(promptStr string, opts ...prompts.Option) (bool, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
-		r0 = rf(promptStr)
+	if rf, ok := ret.Get(0).(func(string, ...prompts.Option) bool); ok {
+		r0 = rf(promptStr, opts...)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(promptStr)
+	if rf, ok := ret.Get(1).(func(string, ...prompts.Option) error); ok {
+		r1 = rf(promptStr, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -320,6 +448,27 @@ func (_m *Prompter) CaptureYesNo(promptStr string) (bool, error) {
 	return r0, r1
 }
 
+// ConfirmDestructive provides a mock function with given fields: promptStr, name, opts
+func (_m *Prompter) ConfirmDestructive(promptStr string, name string, opts ...prompts.Option) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, promptStr, name)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, ...prompts.Option) error); ok {
+		r0 = rf(promptStr, name, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPrompter interface {
 	mock.TestingT
 	Cleanup(func())
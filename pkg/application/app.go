@@ -5,11 +5,17 @@ package application
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/genesis"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -20,13 +26,27 @@ const (
 	WriteReadReadPerms = 0o644
 )
 
-var errChainIDExists = errors.New("the provided chain ID already exists! Try another one")
+var (
+	errChainIDExists = errors.New("the provided chain ID already exists! Try another one")
+
+	ErrReadOnly = errors.New("read-only mode is on")
+	// ErrEwoq refuses the operations on the public networks involving the
+	// ewoq key, whose private key ships with the CLI and avalanchego
+	ErrEwoq = errors.New("the ewoq key is publicly known, anyone can take the funds it holds or controls")
+)
 
 type Avalanche struct {
 	Log     logging.Logger
 	baseDir string
+	logFile string
+	profile string
 	Conf    *config.Config
 	Prompt  prompts.Prompter
+
+	// correlationID identifies the current invocation in the logs
+	correlationID string
+	// version is the version of the CLI, empty for development builds
+	version string
 }
 
 func New() *Avalanche {
@@ -40,41 +60,315 @@ func (app *Avalanche) Setup(baseDir string, log logging.Logger, conf *config.Con
 	app.Prompt = prompt
 }
 
+// SetLogFile records the log file used by the current invocation
+func (app *Avalanche) SetLogFile(logFile string) {
+	app.logFile = logFile
+}
+
+// GetLogFile returns the log file used by the current invocation
+func (app *Avalanche) GetLogFile() string {
+	return app.logFile
+}
+
+// SetVersion records the version of the CLI, as recorded along with the
+// state it writes
+func (app *Avalanche) SetVersion(version string) {
+	app.version = version
+}
+
+// GetVersion returns the version of the CLI, empty for development builds
+func (app *Avalanche) GetVersion() string {
+	return app.version
+}
+
+// SetProfile selects the local network profile used by the current invocation
+func (app *Avalanche) SetProfile(profile string) {
+	app.profile = profile
+}
+
+// GetProfile returns the local network profile used by the current invocation
+func (app *Avalanche) GetProfile() string {
+	if app.profile == "" {
+		return constants.DefaultProfile
+	}
+	return app.profile
+}
+
+// IsDefaultProfile returns true if the current invocation uses the default
+// local network, whose files are not namespaced for backwards compatibility
+func (app *Avalanche) IsDefaultProfile() bool {
+	return app.GetProfile() == constants.DefaultProfile
+}
+
+// GetLocalNetworkKey returns the key under which local deploys of the
+// current profile are recorded in the sidecars
+func (app *Avalanche) GetLocalNetworkKey() string {
+	if app.IsDefaultProfile() {
+		return models.Local.String()
+	}
+	return fmt.Sprintf("%s (%s)", models.Local.String(), app.GetProfile())
+}
+
+func (app *Avalanche) GetProfilesDir() string {
+	return filepath.Join(app.baseDir, constants.ProfilesDir)
+}
+
+// GetProfileBindingPath returns the file holding the network, key and
+// endpoints the profile name is bound to
+func (app *Avalanche) GetProfileBindingPath(name string) string {
+	return filepath.Join(app.GetProfilesDir(), name, constants.ProfileBindingFile)
+}
+
+// CheckProfileBinding returns an error if network, or keyName when given,
+// are not the ones the profile in use is bound to
+func (app *Avalanche) CheckProfileBinding(network models.Network, keyName string) error {
+	networkName := strings.ToLower(network.String())
+	if network == models.Local {
+		networkName = "local"
+	}
+	if err := app.Conf.CheckProfileBinding(networkName, keyName); err != nil {
+		return exitcodes.UserInput(err)
+	}
+	return nil
+}
+
+func (app *Avalanche) GetProfileDir() string {
+	if app.IsDefaultProfile() {
+		return app.baseDir
+	}
+	return filepath.Join(app.GetProfilesDir(), app.GetProfile())
+}
+
+func (app *Avalanche) GetLogDir() string {
+	return filepath.Join(app.baseDir, constants.LogDir)
+}
+
+// GetCliLogFiles returns the per invocation log files of the CLI,
+// ordered from the most recent to the oldest one
+func (app *Avalanche) GetCliLogFiles() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(app.GetLogDir(), constants.CliLogPrefix+"*"+constants.LogSuffix))
+	if err != nil {
+		return nil, err
+	}
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	files := make([]logFile, 0, len(matches))
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{path: m, modTime: info.ModTime()})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].modTime.Equal(files[j].modTime) {
+			return files[i].path > files[j].path
+		}
+		return files[i].modTime.After(files[j].modTime)
+	})
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+func (app *Avalanche) GetLocalesDir() string {
+	return filepath.Join(app.baseDir, constants.LocalesDir)
+}
+
+func (app *Avalanche) GetReleaseCachePath() string {
+	return filepath.Join(app.baseDir, constants.ReleaseCacheFile)
+}
+
+func (app *Avalanche) GetPhaseTimingsPath() string {
+	return filepath.Join(app.baseDir, constants.PhaseTimingsFile)
+}
+
+// GetRunFile returns the record of the backend of the profile
 func (app *Avalanche) GetRunFile() string {
-	return filepath.Join(app.GetRunDir(), constants.ServerRunFile)
+	return app.GetServiceRecordPath(constants.BackendService)
+}
+
+// GetServicesDir returns the dir of the records of the background processes
+// of the profile
+func (app *Avalanche) GetServicesDir() string {
+	return filepath.Join(app.GetRunDir(), constants.ServicesDir)
+}
+
+// GetServiceRecordPath returns the record of the background process name
+func (app *Avalanche) GetServiceRecordPath(name string) string {
+	return filepath.Join(app.GetServicesDir(), name+constants.ServiceRecordSuffix)
+}
+
+// GetDeploysDir returns the dir of the tracking records of the deploys
+// running in the background
+func (app *Avalanche) GetDeploysDir() string {
+	return filepath.Join(app.GetRunDir(), constants.DeploysDir)
+}
+
+// GetDeployCheckpointPath returns the file recording the phases completed by
+// the failed local deploy of subnetName
+func (app *Avalanche) GetDeployCheckpointPath(subnetName string) string {
+	return filepath.Join(app.GetRunDir(), constants.DeployCheckpointsDir, subnetName+".json")
+}
+
+// GetProxyRunFile returns the record of the RPC proxy of the profile
+func (app *Avalanche) GetProxyRunFile() string {
+	return app.GetServiceRecordPath(constants.ProxyService)
+}
+
+// GetServeTokenPath returns the file holding the token of the running API
+// server of the profile
+func (app *Avalanche) GetServeTokenPath() string {
+	return filepath.Join(app.GetRunDir(), constants.ServeTokenFile)
+}
+
+// GetUndeployedChainsPath returns the file recording the blockchains removed
+// from the local network of the profile
+func (app *Avalanche) GetUndeployedChainsPath() string {
+	return filepath.Join(app.GetRunDir(), constants.UndeployedChainsFile)
 }
 
 func (app *Avalanche) GetSnapshotsDir() string {
-	return filepath.Join(app.baseDir, constants.SnapshotsDirName)
+	return filepath.Join(app.GetProfileDir(), constants.SnapshotsDirName)
 }
 
 func (app *Avalanche) GetBaseDir() string {
 	return app.baseDir
 }
 
+func (app *Avalanche) GetObservabilityDir() string {
+	return filepath.Join(app.GetProfileDir(), constants.ObservabilityDir)
+}
+
 func (app *Avalanche) GetRunDir() string {
-	return filepath.Join(app.baseDir, constants.RunDir)
+	return filepath.Join(app.GetProfileDir(), constants.RunDir)
 }
 
 func (app *Avalanche) GetGenesisPath(subnetName string) string {
 	return filepath.Join(app.baseDir, subnetName+constants.GenesisSuffix)
 }
 
+// GetGenesisSchemaPath returns the JSON schema registered for the genesis of
+// the custom VM of vmName
+func (app *Avalanche) GetGenesisSchemaPath(vmName string) string {
+	return filepath.Join(app.baseDir, constants.GenesisSchemasDir, vmName+constants.GenesisSchemaSuffix)
+}
+
 func (app *Avalanche) GetSidecarPath(subnetName string) string {
 	return filepath.Join(app.baseDir, subnetName+constants.SidecarSuffix)
 }
 
+// GetBridgePath returns the file recording the bridge of the subnet on the
+// local network of the profile
+func (app *Avalanche) GetBridgePath(subnetName string) string {
+	return filepath.Join(app.GetRunDir(), subnetName+constants.BridgeSuffix)
+}
+
+// GetAPIEndpoint returns the API endpoint of the public network, which may be
+// overridden with --endpoint or in the config file. Of several endpoints
+// configured for the network, it is the first.
+func (app *Avalanche) GetAPIEndpoint(network models.Network) (string, error) {
+	endpoints, err := app.GetAPIEndpoints(network)
+	if err != nil {
+		return "", err
+	}
+	return endpoints[0], nil
+}
+
+// GetAPIEndpoints returns the API endpoints to choose from for the public
+// network: the one given with --endpoint, or else the ones of the config
+// file, or else the public one
+func (app *Avalanche) GetAPIEndpoints(network models.Network) ([]string, error) {
+	if network != models.Fuji && network != models.Mainnet {
+		return nil, fmt.Errorf("unsupported network %s", network)
+	}
+	endpoints := []string{}
+	for _, endpoint := range app.Conf.APIEndpoints(network.String()) {
+		endpoints = append(endpoints, strings.TrimSuffix(endpoint, "/"))
+	}
+	if len(endpoints) > 0 {
+		return endpoints, nil
+	}
+	if network == models.Fuji {
+		return []string{constants.FujiAPIEndpoint}, nil
+	}
+	return []string{constants.MainnetAPIEndpoint}, nil
+}
+
+// GetEndpointSelectionsPath returns the file recording the API endpoint
+// picked for each public network
+func (app *Avalanche) GetEndpointSelectionsPath() string {
+	return filepath.Join(app.baseDir, constants.EndpointSelectionsFile)
+}
+
 func (app *Avalanche) GetKeyDir() string {
 	return filepath.Join(app.baseDir, constants.KeyDir)
 }
 
+// GetKeyAgentSocket returns the socket of the agent holding the unlocked
+// encrypted keys
+func (app *Avalanche) GetKeyAgentSocket() string {
+	return filepath.Join(app.baseDir, constants.KeyAgentDir, constants.KeyAgentSocket)
+}
+
 func (app *Avalanche) GetKeyPath(keyName string) string {
 	return filepath.Join(app.baseDir, constants.KeyDir, keyName+constants.KeySuffix)
 }
 
+// CheckWritable returns an error if the read-only mode is on, saying action
+// is refused
+func (app *Avalanche) CheckWritable(action string) error {
+	if app.Conf != nil && app.Conf.ReadOnly() {
+		return exitcodes.UserInput(fmt.Errorf("%w, refusing to %s", ErrReadOnly, action))
+	}
+	return nil
+}
+
+// CheckEwoq returns an error if action, which involves the ewoq key, is
+// refused on network: on Fuji and Mainnet, unless --i-know-what-im-doing
+func (app *Avalanche) CheckEwoq(network models.Network, action string) error {
+	if network != models.Fuji && network != models.Mainnet {
+		return nil
+	}
+	if app.Conf != nil && app.Conf.AllowEwoq() {
+		app.Log.Warn("allowing to %s on %s with the ewoq key", action, network)
+		return nil
+	}
+	return exitcodes.UserInput(fmt.Errorf("refusing to %s on %s: %w, pass --%s to proceed anyway", action, network, ErrEwoq, constants.AllowEwoqFlag))
+}
+
 func (app *Avalanche) WriteGenesisFile(subnetName string, genesisBytes []byte) error {
-	genesisPath := app.GetGenesisPath(subnetName)
-	return os.WriteFile(genesisPath, genesisBytes, WriteReadReadPerms)
+	if err := app.CheckWritable("write the genesis of " + subnetName); err != nil {
+		return err
+	}
+	return app.writeGenesis(subnetName, genesisBytes)
+}
+
+// GetChainConfigPath returns the path of the chain config of subnetName, which
+// the nodes of the local network pass to its VM
+func (app *Avalanche) GetChainConfigPath(subnetName string) string {
+	return filepath.Join(app.baseDir, subnetName+constants.ChainConfigSuffix)
+}
+
+func (app *Avalanche) WriteChainConfigFile(subnetName string, chainConfigBytes []byte) error {
+	if err := app.CheckWritable("write the chain config of " + subnetName); err != nil {
+		return err
+	}
+	return writeFileAtomic(app.GetChainConfigPath(subnetName), chainConfigBytes)
+}
+
+// LoadChainConfig returns the chain config of subnetName, or nil if it has
+// none
+func (app *Avalanche) LoadChainConfig(subnetName string) ([]byte, error) {
+	chainConfigBytes, err := readStateFile(app.GetChainConfigPath(subnetName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return chainConfigBytes, err
 }
 
 func (app *Avalanche) GenesisExists(subnetName string) bool {
@@ -83,33 +377,113 @@ func (app *Avalanche) GenesisExists(subnetName string) bool {
 	return err == nil
 }
 
+// GetRemoteKeyPath returns the path of the configuration of keyName, if
+// it is held by a remote signer
+func (app *Avalanche) GetRemoteKeyPath(keyName string) string {
+	return filepath.Join(app.baseDir, constants.KeyDir, keyName+constants.RemoteKeySuffix)
+}
+
+// IsRemoteKey returns true if keyName is held by a remote signer
+func (app *Avalanche) IsRemoteKey(keyName string) bool {
+	_, err := os.Stat(app.GetRemoteKeyPath(keyName))
+	return err == nil
+}
+
+// GetSigningKeyPath returns the path of the remote key configuration of
+// keyName if it is a remote key, or of its private key otherwise
+func (app *Avalanche) GetSigningKeyPath(keyName string) string {
+	if app.IsRemoteKey(keyName) {
+		return app.GetRemoteKeyPath(keyName)
+	}
+	return app.GetKeyPath(keyName)
+}
+
 func (app *Avalanche) KeyExists(keyName string) bool {
 	keyPath := app.GetKeyPath(keyName)
 	_, err := os.Stat(keyPath)
-	return err == nil
+	return err == nil || app.IsRemoteKey(keyName)
 }
 
 func (app *Avalanche) CopyGenesisFile(inputFilename string, subnetName string) error {
-	genesisBytes, err := os.ReadFile(inputFilename)
+	if err := app.CheckWritable("write the genesis of " + subnetName); err != nil {
+		return err
+	}
+	genesisBytes, err := readStateFile(inputFilename)
 	if err != nil {
 		return err
 	}
-	genesisPath := app.GetGenesisPath(subnetName)
-	return os.WriteFile(genesisPath, genesisBytes, WriteReadReadPerms)
+	return app.writeGenesis(subnetName, genesisBytes)
 }
 
-func (app *Avalanche) CopyKeyFile(inputFilename string, keyName string) error {
-	keyBytes, err := os.ReadFile(inputFilename)
+// LoadGenesis returns the genesis of subnetName. If it is a template, its
+// variables are resolved with the values the project sets for network,
+// overridden by vars.
+func (app *Avalanche) LoadGenesis(subnetName string, network models.Network, vars map[string]string) ([]byte, error) {
+	genesisBytes, err := readStateFile(app.GetGenesisPath(subnetName))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	keyPath := app.GetKeyPath(keyName)
-	return os.WriteFile(keyPath, keyBytes, WriteReadReadPerms)
+	if !genesis.IsTemplate(genesisBytes) {
+		return genesisBytes, nil
+	}
+	networkName := strings.ToLower(network.String())
+	if network == models.Local {
+		networkName = "local"
+	}
+	// the config files lower the case of the names, match them
+	resolved := map[string]string{}
+	for name, value := range app.Conf.GenesisVars(networkName) {
+		resolved[strings.ToLower(name)] = value
+	}
+	for name, value := range vars {
+		resolved[strings.ToLower(name)] = value
+	}
+	rendered, err := genesis.Render(genesisBytes, resolved)
+	if err != nil {
+		return nil, exitcodes.UserInput(fmt.Errorf("genesis of %s for %s: %w", subnetName, networkName, err))
+	}
+	return rendered, nil
 }
 
-func (app *Avalanche) LoadEvmGenesis(subnetName string) (core.Genesis, error) {
+// ResolveGenesisFile returns the path of the genesis of subnetName, with its
+// template variables resolved for network as in LoadGenesis, and a function
+// removing any file written to that end once the genesis has been used
+func (app *Avalanche) ResolveGenesisFile(subnetName string, network models.Network, vars map[string]string) (string, func(), error) {
 	genesisPath := app.GetGenesisPath(subnetName)
-	jsonBytes, err := os.ReadFile(genesisPath)
+	genesisBytes, err := readStateFile(genesisPath)
+	if err != nil {
+		return "", nil, err
+	}
+	if !genesis.IsTemplate(genesisBytes) {
+		return genesisPath, func() {}, nil
+	}
+	rendered, err := app.LoadGenesis(subnetName, network, vars)
+	if err != nil {
+		return "", nil, err
+	}
+	f, err := os.CreateTemp("", subnetName+"-genesis-*.json")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		_ = os.Remove(f.Name())
+	}
+	if _, err := f.Write(rendered); err != nil {
+		_ = f.Close()
+		cleanup()
+		return "", nil, err
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return f.Name(), cleanup, nil
+}
+
+// LoadEvmGenesis returns the subnet-evm genesis of subnetName, with the
+// template variables of the local network if it is a template
+func (app *Avalanche) LoadEvmGenesis(subnetName string) (core.Genesis, error) {
+	jsonBytes, err := app.LoadGenesis(subnetName, models.Local, nil)
 	if err != nil {
 		return core.Genesis{}, err
 	}
@@ -120,6 +494,9 @@ func (app *Avalanche) LoadEvmGenesis(subnetName string) (core.Genesis, error) {
 }
 
 func (app *Avalanche) CreateSidecar(sc *models.Sidecar) error {
+	if err := app.CheckWritable("write the sidecar of " + sc.Name); err != nil {
+		return err
+	}
 	if sc.TokenName == "" {
 		sc.TokenName = constants.DefaultTokenName
 	}
@@ -139,13 +516,12 @@ func (app *Avalanche) CreateSidecar(sc *models.Sidecar) error {
 		return nil
 	}
 
-	sidecarPath := app.GetSidecarPath(sc.Name)
-	return os.WriteFile(sidecarPath, scBytes, WriteReadReadPerms)
+	return app.writeSidecar(sc.Name, scBytes)
 }
 
 func (app *Avalanche) LoadSidecar(subnetName string) (models.Sidecar, error) {
 	sidecarPath := app.GetSidecarPath(subnetName)
-	jsonBytes, err := os.ReadFile(sidecarPath)
+	jsonBytes, err := readStateFile(sidecarPath)
 	if err != nil {
 		return models.Sidecar{}, err
 	}
@@ -161,14 +537,16 @@ func (app *Avalanche) LoadSidecar(subnetName string) (models.Sidecar, error) {
 }
 
 func (app *Avalanche) UpdateSidecar(sc *models.Sidecar) error {
+	if err := app.CheckWritable("write the sidecar of " + sc.Name); err != nil {
+		return err
+	}
 	sc.Version = constants.SidecarVersion
 	scBytes, err := json.MarshalIndent(sc, "", "    ")
 	if err != nil {
 		return nil
 	}
 
-	sidecarPath := app.GetSidecarPath(sc.Name)
-	return os.WriteFile(sidecarPath, scBytes, WriteReadReadPerms)
+	return app.writeSidecar(sc.Name, scBytes)
 }
 
 func (app *Avalanche) GetTokenName(subnetName string) string {
@@ -4,12 +4,16 @@ package application
 
 import (
 	"encoding/json"
+	"errors"
 	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -52,6 +56,26 @@ func TestUpdateSideCar(t *testing.T) {
 	assert.Equal(*sc, control)
 }
 
+func TestReadOnly(t *testing.T) {
+	assert := assert.New(t)
+	ap := newTestApp(t)
+	ap.Conf = config.New()
+	assert.NoError(ap.CheckWritable("test"))
+
+	ap.Conf.SetReadOnly(true)
+	err := ap.CheckWritable("test")
+	assert.True(errors.Is(err, ErrReadOnly))
+	assert.Equal(exitcodes.UserInputError, exitcodes.FromError(err))
+
+	err = ap.WriteGenesisFile(subnetName1, []byte("genesis"))
+	assert.True(errors.Is(err, ErrReadOnly))
+	assert.False(ap.GenesisExists(subnetName1))
+	err = ap.CreateSidecar(&models.Sidecar{Name: subnetName1})
+	assert.True(errors.Is(err, ErrReadOnly))
+	_, err = os.Stat(ap.GetSidecarPath(subnetName1))
+	assert.True(os.IsNotExist(err))
+}
+
 func Test_writeGenesisFile_success(t *testing.T) {
 	assert := assert.New(t)
 	genesisBytes := []byte("genesis")
@@ -456,3 +480,26 @@ func newTestApp(t *testing.T) *Avalanche {
 		Log:     logging.NoLog{},
 	}
 }
+
+func TestGetCliLogFiles(t *testing.T) {
+	assert := assert.New(t)
+	ap := newTestApp(t)
+
+	err := os.MkdirAll(ap.GetLogDir(), constants.DefaultPerms755)
+	assert.NoError(err)
+
+	older := filepath.Join(ap.GetLogDir(), constants.CliLogPrefix+"20220101-000000-1"+constants.LogSuffix)
+	newer := filepath.Join(ap.GetLogDir(), constants.CliLogPrefix+"20220102-000000-2"+constants.LogSuffix)
+	other := filepath.Join(ap.GetLogDir(), "server"+constants.LogSuffix)
+	for _, f := range []string{older, newer, other} {
+		err = os.WriteFile(f, []byte("log"), WriteReadReadPerms)
+		assert.NoError(err)
+	}
+	now := time.Now()
+	assert.NoError(os.Chtimes(older, now.Add(-time.Hour), now.Add(-time.Hour)))
+	assert.NoError(os.Chtimes(newer, now, now))
+
+	logFiles, err := ap.GetCliLogFiles()
+	assert.NoError(err)
+	assert.Equal([]string{newer, older}, logFiles)
+}
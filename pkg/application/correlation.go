@@ -0,0 +1,73 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+var correlationIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,64}$`)
+
+// NewCorrelationID returns a random correlation ID for an invocation
+func NewCorrelationID() (string, error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(idBytes), nil
+}
+
+// CheckCorrelationID returns an error if id can't be a correlation ID, which
+// is at most 64 letters, digits, '_', '.' or '-'
+func CheckCorrelationID(id string) error {
+	if !correlationIDRegex.MatchString(id) {
+		return fmt.Errorf("invalid correlation ID %q: only up to 64 letters, digits, '_', '.' and '-' are allowed", id)
+	}
+	return nil
+}
+
+// SetCorrelationID sets the ID identifying the current invocation in the
+// logs of the CLI and of the backend
+func (app *Avalanche) SetCorrelationID(id string) {
+	app.correlationID = id
+}
+
+// GetCorrelationID returns the ID identifying the current invocation
+func (app *Avalanche) GetCorrelationID() string {
+	return app.correlationID
+}
+
+// GetInvocationsLogPath returns the log of the invocations changing the
+// state of the current profile
+func (app *Avalanche) GetInvocationsLogPath() string {
+	return filepath.Join(app.GetRunDir(), constants.InvocationsLogFile)
+}
+
+// RecordInvocation appends the current invocation, running command, to the
+// invocations log of the profile, so that the artifacts of its run dir, such
+// as the backend logs, can be correlated with the invocation. The log is
+// rotated once over constants.MaxInvocationsLogSize.
+func (app *Avalanche) RecordInvocation(command string) error {
+	path := app.GetInvocationsLogPath()
+	if info, err := os.Stat(path); err == nil && info.Size() > constants.MaxInvocationsLogSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s correlation-id=%s pid=%d log=%s command=%q\n",
+		time.Now().Format(time.RFC3339), app.correlationID, os.Getpid(), app.logFile, command)
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationID(t *testing.T) {
+	assert := assert.New(t)
+
+	id, err := NewCorrelationID()
+	assert.NoError(err)
+	assert.NoError(CheckCorrelationID(id))
+	assert.NoError(CheckCorrelationID("ci-run_42.1"))
+	assert.Error(CheckCorrelationID(""))
+	assert.Error(CheckCorrelationID("id with spaces"))
+	assert.Error(CheckCorrelationID(strings.Repeat("a", 65)))
+}
+
+func TestRecordInvocation(t *testing.T) {
+	assert := assert.New(t)
+	ap := newTestApp(t)
+	assert.NoError(os.MkdirAll(ap.GetRunDir(), constants.DefaultPerms755))
+	ap.SetCorrelationID("0123456789abcdef")
+
+	assert.NoError(ap.RecordInvocation("avalanche subnet deploy"))
+	assert.NoError(ap.RecordInvocation("avalanche network clean"))
+	content, err := os.ReadFile(ap.GetInvocationsLogPath())
+	assert.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(lines, 2)
+	assert.Contains(lines[0], "correlation-id=0123456789abcdef")
+	assert.Contains(lines[1], `command="avalanche network clean"`)
+
+	// the log is rotated once too large
+	assert.NoError(os.WriteFile(ap.GetInvocationsLogPath(), make([]byte, constants.MaxInvocationsLogSize+1), WriteReadReadPerms))
+	assert.NoError(ap.RecordInvocation("avalanche subnet deploy"))
+	info, err := os.Stat(ap.GetInvocationsLogPath())
+	assert.NoError(err)
+	assert.Less(info.Size(), int64(1024))
+	assert.FileExists(ap.GetInvocationsLogPath() + ".1")
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
+)
+
+const (
+	genesisRevisionPrefix = "genesis_"
+	revisionSuffix        = ".json"
+	sidecarBackupFile     = "sidecar.json"
+)
+
+// GenesisRevision is a previous version of the genesis of a subnet
+type GenesisRevision struct {
+	// Number orders the revisions, from 1 for the oldest kept
+	Number int
+	// Time is when the revision was replaced
+	Time time.Time
+	Path string
+	Size int64
+}
+
+// GetHistoryDir returns the directory holding the previous revisions of the
+// files of subnetName
+func (app *Avalanche) GetHistoryDir(subnetName string) string {
+	return filepath.Join(app.baseDir, constants.HistoryDir, subnetName)
+}
+
+// GetGenesisRevisions returns the previous revisions of the genesis of
+// subnetName, from the oldest
+func (app *Avalanche) GetGenesisRevisions(subnetName string) ([]GenesisRevision, error) {
+	matches, err := filepath.Glob(filepath.Join(app.GetHistoryDir(subnetName), genesisRevisionPrefix+"*"+revisionSuffix))
+	if err != nil {
+		return nil, err
+	}
+	revisions := []GenesisRevision{}
+	for _, path := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), genesisRevisionPrefix), revisionSuffix)
+		nanos, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, GenesisRevision{Time: time.Unix(0, nanos), Path: path, Size: info.Size()})
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Time.Before(revisions[j].Time)
+	})
+	for i := range revisions {
+		revisions[i].Number = i + 1
+	}
+	return revisions, nil
+}
+
+// RestoreGenesis makes the revision number of the genesis of subnetName the
+// current one. The genesis it replaces becomes the latest revision, so that
+// the restore can be undone.
+func (app *Avalanche) RestoreGenesis(subnetName string, number int) error {
+	revisions, err := app.GetGenesisRevisions(subnetName)
+	if err != nil {
+		return err
+	}
+	if number < 1 || number > len(revisions) {
+		return exitcodes.UserInput(fmt.Errorf("%s has no genesis revision %d, run subnet history %s to list them", subnetName, number, subnetName))
+	}
+	genesisBytes, err := readStateFile(revisions[number-1].Path)
+	if err != nil {
+		return err
+	}
+	return app.WriteGenesisFile(subnetName, genesisBytes)
+}
+
+// writeGenesis atomically replaces the genesis of subnetName, keeping the
+// previous one as a revision
+func (app *Avalanche) writeGenesis(subnetName string, genesisBytes []byte) error {
+	genesisPath := app.GetGenesisPath(subnetName)
+	previous, err := readStateFile(genesisPath)
+	switch {
+	case err == nil && !bytes.Equal(previous, genesisBytes):
+		if err := app.archiveGenesis(subnetName, previous); err != nil {
+			return fmt.Errorf("failed keeping the previous genesis of %s: %w", subnetName, err)
+		}
+	case err != nil && !os.IsNotExist(err):
+		return err
+	}
+	return writeFileAtomic(genesisPath, genesisBytes)
+}
+
+func (app *Avalanche) archiveGenesis(subnetName string, genesisBytes []byte) error {
+	historyDir := app.GetHistoryDir(subnetName)
+	if err := os.MkdirAll(historyDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	revisionPath := filepath.Join(historyDir, fmt.Sprintf("%s%d%s", genesisRevisionPrefix, time.Now().UnixNano(), revisionSuffix))
+	if err := writeFileAtomic(revisionPath, genesisBytes); err != nil {
+		return err
+	}
+	revisions, err := app.GetGenesisRevisions(subnetName)
+	if err != nil {
+		return err
+	}
+	for len(revisions) > constants.MaxGenesisRevisions {
+		if err := os.Remove(revisions[0].Path); err != nil {
+			return err
+		}
+		revisions = revisions[1:]
+	}
+	return nil
+}
+
+// writeSidecar atomically replaces the sidecar of subnetName, keeping a
+// backup of the previous one
+func (app *Avalanche) writeSidecar(subnetName string, scBytes []byte) error {
+	sidecarPath := app.GetSidecarPath(subnetName)
+	previous, err := readStateFile(sidecarPath)
+	switch {
+	case err == nil && !bytes.Equal(previous, scBytes):
+		historyDir := app.GetHistoryDir(subnetName)
+		if err := os.MkdirAll(historyDir, constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(historyDir, sidecarBackupFile), previous); err != nil {
+			return fmt.Errorf("failed keeping the previous sidecar of %s: %w", subnetName, err)
+		}
+	case err != nil && !os.IsNotExist(err):
+		return err
+	}
+	return writeFileAtomic(sidecarPath, scBytes)
+}
+
+// writeFileAtomic writes data to path through a temporary file renamed over
+// it, so that concurrent readers and commands interrupted halfway never see
+// a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	defer timings.Track(timings.FileIO)()
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, WriteReadReadPerms)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+	}
+	return err
+}
+
+// readStateFile reads the file at path, timed as file IO
+func readStateFile(path string) ([]byte, error) {
+	defer timings.Track(timings.FileIO)()
+	return os.ReadFile(path)
+}
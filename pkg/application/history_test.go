@@ -0,0 +1,90 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestGenesisHistory(t *testing.T) {
+	assert := assert.New(t)
+	ap := newTestApp(t)
+
+	assert.NoError(ap.WriteGenesisFile(subnetName1, []byte("v1")))
+	revisions, err := ap.GetGenesisRevisions(subnetName1)
+	assert.NoError(err)
+	assert.Empty(revisions)
+
+	assert.NoError(ap.WriteGenesisFile(subnetName1, []byte("v2")))
+	// unchanged genesis are not kept again
+	assert.NoError(ap.WriteGenesisFile(subnetName1, []byte("v2")))
+	assert.NoError(ap.WriteGenesisFile(subnetName1, []byte("v3")))
+	revisions, err = ap.GetGenesisRevisions(subnetName1)
+	assert.NoError(err)
+	assert.Len(revisions, 2)
+	assert.Equal(1, revisions[0].Number)
+	assert.Equal("v1", readFile(t, revisions[0].Path))
+	assert.Equal("v2", readFile(t, revisions[1].Path))
+
+	assert.NoError(ap.RestoreGenesis(subnetName1, 1))
+	assert.Equal("v1", readFile(t, ap.GetGenesisPath(subnetName1)))
+	revisions, err = ap.GetGenesisRevisions(subnetName1)
+	assert.NoError(err)
+	assert.Len(revisions, 3)
+	assert.Equal("v3", readFile(t, revisions[2].Path))
+
+	err = ap.RestoreGenesis(subnetName1, 4)
+	assert.Error(err)
+	assert.Equal(exitcodes.UserInputError, exitcodes.FromError(err))
+
+	// no temporary file is left behind
+	matches, err := filepath.Glob(filepath.Join(ap.GetBaseDir(), ".*"))
+	assert.NoError(err)
+	assert.Empty(matches)
+}
+
+func TestGenesisHistoryLimit(t *testing.T) {
+	assert := assert.New(t)
+	ap := newTestApp(t)
+
+	for i := 0; i <= constants.MaxGenesisRevisions+1; i++ {
+		assert.NoError(ap.WriteGenesisFile(subnetName1, []byte{byte(i)}))
+	}
+	revisions, err := ap.GetGenesisRevisions(subnetName1)
+	assert.NoError(err)
+	assert.Len(revisions, constants.MaxGenesisRevisions)
+	// the oldest revision was dropped
+	assert.Equal(string([]byte{1}), readFile(t, revisions[0].Path))
+}
+
+func TestSidecarBackup(t *testing.T) {
+	assert := assert.New(t)
+	ap := newTestApp(t)
+
+	sc := &models.Sidecar{Name: subnetName1, VM: models.SubnetEvm, ChainID: "42"}
+	assert.NoError(ap.CreateSidecar(sc))
+	backupPath := filepath.Join(ap.GetHistoryDir(subnetName1), sidecarBackupFile)
+	_, err := os.Stat(backupPath)
+	assert.True(os.IsNotExist(err))
+	previous := readFile(t, ap.GetSidecarPath(subnetName1))
+
+	sc.ChainID = "43"
+	assert.NoError(ap.UpdateSidecar(sc))
+	assert.Equal(previous, readFile(t, backupPath))
+	info, err := os.Stat(ap.GetSidecarPath(subnetName1))
+	assert.NoError(err)
+	assert.Equal(os.FileMode(WriteReadReadPerms), info.Mode().Perm())
+}
@@ -0,0 +1,455 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/secret"
+)
+
+const (
+	formatVersion = 1
+
+	manifestEntry = "manifest.json"
+	configEntry   = "config.json"
+	// suffix of the entries of private keys, encrypted with the passphrase
+	encryptedSuffix = ".enc"
+
+	snapshotPrefix = "anr-snapshot-"
+
+	keyPerms = 0o600
+)
+
+var (
+	ErrWrongPassphrase = errors.New("wrong passphrase, or corrupted backup")
+	ErrConflict        = errors.New("files of the backup already exist")
+	errInvalidEntry    = errors.New("invalid backup entry")
+)
+
+// PassphraseFunc returns the passphrase protecting the private keys. It is
+// only called if the backup has private keys.
+type PassphraseFunc func() (string, error)
+
+// Manifest describes the content of a backup
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// KeySalt is the salt the encryption key of the private keys is derived
+	// from the passphrase with
+	KeySalt   string   `json:"keySalt,omitempty"`
+	Subnets   []string `json:"subnets"`
+	Keys      []string `json:"keys"`
+	Snapshots []string `json:"snapshots"`
+	Config    bool     `json:"config"`
+}
+
+// backupFiles returns the files of baseDir to back up, by kind, relative to
+// baseDir: the genesis, sidecar and chain config of the subnets, the keys, and
+// the files of the custom snapshots of all profiles. The default snapshot is
+// left out, as it is restored from the bootstrap snapshot.
+func backupFiles(baseDir string) (subnets []string, keys []string, snapshots map[string][]string, err error) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && (strings.HasSuffix(name, constants.GenesisSuffix) ||
+			strings.HasSuffix(name, constants.SidecarSuffix) ||
+			strings.HasSuffix(name, constants.ChainConfigSuffix)) {
+			subnets = append(subnets, name)
+		}
+	}
+
+	keyEntries, err := os.ReadDir(filepath.Join(baseDir, constants.KeyDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, nil, err
+	}
+	for _, e := range keyEntries {
+		name := e.Name()
+		if !e.IsDir() && (strings.HasSuffix(name, constants.KeySuffix) || strings.HasSuffix(name, constants.RemoteKeySuffix)) {
+			keys = append(keys, filepath.Join(constants.KeyDir, name))
+		}
+	}
+
+	snapshotsDirs := []string{constants.SnapshotsDirName}
+	profiles, err := os.ReadDir(filepath.Join(baseDir, constants.ProfilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, nil, err
+	}
+	for _, p := range profiles {
+		if p.IsDir() {
+			snapshotsDirs = append(snapshotsDirs, filepath.Join(constants.ProfilesDir, p.Name(), constants.SnapshotsDirName))
+		}
+	}
+	snapshots = map[string][]string{}
+	for _, dir := range snapshotsDirs {
+		snapshotEntries, err := os.ReadDir(filepath.Join(baseDir, dir))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, nil, nil, err
+		}
+		for _, e := range snapshotEntries {
+			name := e.Name()
+			if !e.IsDir() || !strings.HasPrefix(name, snapshotPrefix) || name == snapshotPrefix+constants.DefaultSnapshotName {
+				continue
+			}
+			snapshotDir := filepath.Join(dir, name)
+			err := filepath.Walk(filepath.Join(baseDir, snapshotDir), func(path string, info os.FileInfo, err error) error {
+				if err != nil || !info.Mode().IsRegular() {
+					return err
+				}
+				rel, err := filepath.Rel(baseDir, path)
+				if err != nil {
+					return err
+				}
+				snapshots[snapshotDir] = append(snapshots[snapshotDir], rel)
+				return nil
+			})
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	return subnets, keys, snapshots, nil
+}
+
+// Create archives the subnets, keys and custom snapshots of baseDir, and the
+// config file at configFile if it exists, into a gzipped tar file at
+// archivePath. The private keys are encrypted with the passphrase.
+func Create(baseDir string, configFile string, archivePath string, passphrase PassphraseFunc) (*Manifest, error) {
+	subnets, keys, snapshots, err := backupFiles(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing the files to back up: %w", err)
+	}
+	manifest := &Manifest{
+		Version:   formatVersion,
+		CreatedAt: time.Now().UTC(),
+		Subnets:   subnets,
+		Keys:      keys,
+	}
+	for dir := range snapshots {
+		manifest.Snapshots = append(manifest.Snapshots, dir)
+	}
+	sort.Strings(manifest.Snapshots)
+	if configFile != "" {
+		if _, err := os.Stat(configFile); err == nil {
+			manifest.Config = true
+		}
+	}
+
+	var encryptionKey []byte
+	for _, k := range keys {
+		if !strings.HasSuffix(k, constants.KeySuffix) {
+			continue
+		}
+		pass, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		salt, err := secret.NewSalt()
+		if err != nil {
+			return nil, err
+		}
+		encryptionKey, err = secret.DeriveKey(pass, salt)
+		if err != nil {
+			return nil, err
+		}
+		manifest.KeySalt = hex.EncodeToString(salt)
+		break
+	}
+
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	err = writeArchive(f, baseDir, configFile, manifest, snapshots, encryptionKey)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeArchive(
+	w io.Writer,
+	baseDir string,
+	configFile string,
+	manifest *Manifest,
+	snapshots map[string][]string,
+	encryptionKey []byte,
+) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the manifest comes first, to know how to restore the next entries
+	if err := writeEntry(tw, manifestEntry, manifestBytes, 0o644); err != nil {
+		return err
+	}
+	if manifest.Config {
+		if err := writeFile(tw, configEntry, configFile, nil); err != nil {
+			return err
+		}
+	}
+	for _, name := range manifest.Subnets {
+		if err := writeFile(tw, name, filepath.Join(baseDir, name), nil); err != nil {
+			return err
+		}
+	}
+	for _, name := range manifest.Keys {
+		if strings.HasSuffix(name, constants.KeySuffix) {
+			err = writeFile(tw, name+encryptedSuffix, filepath.Join(baseDir, name), encryptionKey)
+		} else {
+			err = writeFile(tw, name, filepath.Join(baseDir, name), nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, dir := range manifest.Snapshots {
+		for _, name := range snapshots[dir] {
+			if err := writeFile(tw, name, filepath.Join(baseDir, name), nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeFile adds the file at path to the archive as name, encrypted with
+// encryptionKey if set
+func writeFile(tw *tar.Writer, name string, path string, encryptionKey []byte) error {
+	if encryptionKey == nil {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    filepath.ToSlash(name),
+			Mode:    int64(info.Mode().Perm()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}); err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	encrypted, err := secret.Seal(encryptionKey, content)
+	if err != nil {
+		return err
+	}
+	return writeEntry(tw, name, encrypted, keyPerms)
+}
+
+func writeEntry(tw *tar.Writer, name string, content []byte, perms os.FileMode) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    filepath.ToSlash(name),
+		Mode:    int64(perms),
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// Restore extracts the backup at archivePath into baseDir, and its config
+// file to configFile. Nothing is restored if any file already exists, unless
+// overwrite is set, or if the private keys can't be decrypted.
+func Restore(baseDir string, configFile string, archivePath string, passphrase PassphraseFunc, overwrite bool) (*Manifest, error) {
+	staging, err := os.MkdirTemp(baseDir, "restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	manifest, files, err := extractArchive(archivePath, staging, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	// where each staged file goes
+	targets := make(map[string]string, len(files))
+	conflicts := []string{}
+	for _, name := range files {
+		target := filepath.Join(baseDir, name)
+		if name == configEntry {
+			target = configFile
+		}
+		if _, err := os.Stat(target); err == nil {
+			conflicts = append(conflicts, target)
+		}
+		targets[name] = target
+	}
+	if len(conflicts) > 0 && !overwrite {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, strings.Join(conflicts, ", "))
+	}
+	for _, name := range files {
+		if err := os.MkdirAll(filepath.Dir(targets[name]), constants.DefaultPerms755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(filepath.Join(staging, name), targets[name]); err != nil {
+			return nil, fmt.Errorf("failed restoring %s: %w", targets[name], err)
+		}
+	}
+	return manifest, nil
+}
+
+// extractArchive extracts the backup at archivePath into dir, decrypting the
+// private keys. Returns its manifest and the files extracted, relative to dir.
+func extractArchive(archivePath string, dir string, passphrase PassphraseFunc) (*Manifest, []string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s is not a backup: %w", archivePath, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var (
+		manifest      *Manifest
+		encryptionKey []byte
+		files         []string
+	)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed reading backup: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := filepath.FromSlash(header.Name)
+		if !isLocal(name) {
+			return nil, nil, fmt.Errorf("%w %q", errInvalidEntry, header.Name)
+		}
+
+		if manifest == nil {
+			if name != manifestEntry {
+				return nil, nil, fmt.Errorf("%s is not a backup: no manifest", archivePath)
+			}
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, nil, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			if manifest.Version != formatVersion {
+				return nil, nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+			}
+			continue
+		}
+
+		perms := os.FileMode(header.Mode).Perm()
+		if strings.HasSuffix(name, encryptedSuffix) {
+			if encryptionKey == nil {
+				encryptionKey, err = manifestKey(manifest, passphrase)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			encrypted, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, nil, err
+			}
+			content, err := secret.Open(encryptionKey, encrypted)
+			if errors.Is(err, secret.ErrOpen) {
+				return nil, nil, ErrWrongPassphrase
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			name = strings.TrimSuffix(name, encryptedSuffix)
+			if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), constants.DefaultPerms755); err != nil {
+				return nil, nil, err
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), content, keyPerms); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			if err := extractFile(tr, filepath.Join(dir, name), perms); err != nil {
+				return nil, nil, err
+			}
+		}
+		files = append(files, name)
+	}
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("%s is not a backup: no manifest", archivePath)
+	}
+	return manifest, files, nil
+}
+
+func extractFile(r io.Reader, path string, perms os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perms)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// isLocal returns true if name is a relative path which stays within the
+// directory it is relative to
+func isLocal(name string) bool {
+	if name == "" || filepath.IsAbs(name) {
+		return false
+	}
+	clean := filepath.Clean(name)
+	return clean != ".." && !strings.HasPrefix(clean, ".."+string(os.PathSeparator))
+}
+
+func manifestKey(manifest *Manifest, passphrase PassphraseFunc) ([]byte, error) {
+	salt, err := hex.DecodeString(manifest.KeySalt)
+	if err != nil || len(salt) != secret.SaltLen {
+		return nil, errors.New("invalid key salt in the backup manifest")
+	}
+	pass, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+	return secret.DeriveKey(pass, salt)
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func staticPassphrase(p string) PassphraseFunc {
+	return func() (string, error) { return p, nil }
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	src := t.TempDir()
+	config := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, filepath.Join(src, "test"+constants.GenesisSuffix), "genesis")
+	writeTestFile(t, filepath.Join(src, "test"+constants.SidecarSuffix), "sidecar")
+	writeTestFile(t, filepath.Join(src, constants.KeyDir, "k"+constants.KeySuffix), "secret")
+	writeTestFile(t, filepath.Join(src, constants.KeyDir, "r"+constants.RemoteKeySuffix), "remote")
+	writeTestFile(t, filepath.Join(src, constants.SnapshotsDirName, snapshotPrefix+"mine", "db", "data"), "snapshot")
+	writeTestFile(t, filepath.Join(src, constants.SnapshotsDirName, snapshotPrefix+constants.DefaultSnapshotName, "data"), "default")
+	writeTestFile(t, filepath.Join(src, constants.ProfilesDir, "p", constants.SnapshotsDirName, snapshotPrefix+"other", "data"), "profile")
+	writeTestFile(t, filepath.Join(src, "logs", "cli.log"), "logs")
+	writeTestFile(t, config, "{}")
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	manifest, err := Create(src, config, archive, staticPassphrase("pass"))
+	assert.NoError(err)
+	assert.Len(manifest.Subnets, 2)
+	assert.Len(manifest.Keys, 2)
+	assert.Equal([]string{
+		filepath.Join(constants.ProfilesDir, "p", constants.SnapshotsDirName, snapshotPrefix+"other"),
+		filepath.Join(constants.SnapshotsDirName, snapshotPrefix+"mine"),
+	}, manifest.Snapshots)
+	assert.True(manifest.Config)
+
+	dst := t.TempDir()
+	restoredConfig := filepath.Join(t.TempDir(), "config.json")
+	_, err = Restore(dst, restoredConfig, archive, staticPassphrase("wrong"), false)
+	assert.ErrorIs(err, ErrWrongPassphrase)
+	_, err = os.Stat(filepath.Join(dst, "test"+constants.GenesisSuffix))
+	assert.True(os.IsNotExist(err))
+
+	_, err = Restore(dst, restoredConfig, archive, staticPassphrase("pass"), false)
+	assert.NoError(err)
+	for path, content := range map[string]string{
+		filepath.Join(dst, "test"+constants.SidecarSuffix):                                                         "sidecar",
+		filepath.Join(dst, constants.KeyDir, "k"+constants.KeySuffix):                                              "secret",
+		filepath.Join(dst, constants.KeyDir, "r"+constants.RemoteKeySuffix):                                        "remote",
+		filepath.Join(dst, constants.SnapshotsDirName, snapshotPrefix+"mine", "db", "data"):                        "snapshot",
+		filepath.Join(dst, constants.ProfilesDir, "p", constants.SnapshotsDirName, snapshotPrefix+"other", "data"): "profile",
+		restoredConfig: "{}",
+	} {
+		b, err := os.ReadFile(path)
+		assert.NoError(err)
+		assert.Equal(content, string(b))
+	}
+	info, err := os.Stat(filepath.Join(dst, constants.KeyDir, "k"+constants.KeySuffix))
+	assert.NoError(err)
+	assert.Equal(os.FileMode(keyPerms), info.Mode().Perm())
+	_, err = os.Stat(filepath.Join(dst, constants.SnapshotsDirName, snapshotPrefix+constants.DefaultSnapshotName))
+	assert.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dst, "logs"))
+	assert.True(os.IsNotExist(err))
+
+	_, err = Restore(dst, restoredConfig, archive, staticPassphrase("pass"), false)
+	assert.ErrorIs(err, ErrConflict)
+	_, err = Restore(dst, restoredConfig, archive, staticPassphrase("pass"), true)
+	assert.NoError(err)
+}
+
+func TestBackupWithoutKeysNeedsNoPassphrase(t *testing.T) {
+	assert := assert.New(t)
+
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "test"+constants.SidecarSuffix), "sidecar")
+	noPassphrase := func() (string, error) { return "", errors.New("unexpected prompt") }
+
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	manifest, err := Create(src, "", archive, noPassphrase)
+	assert.NoError(err)
+	assert.Empty(manifest.KeySalt)
+	_, err = Restore(t.TempDir(), filepath.Join(t.TempDir(), "config.json"), archive, noPassphrase, false)
+	assert.NoError(err)
+}
+
+func TestIsLocal(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(isLocal("key/k.pk"))
+	assert.True(isLocal("a/../b"))
+	assert.False(isLocal("../b"))
+	assert.False(isLocal("a/../../b"))
+	assert.False(isLocal("/etc/passwd"))
+	assert.False(isLocal(""))
+}
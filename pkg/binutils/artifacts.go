@@ -0,0 +1,186 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+type artifactFormat int
+
+const (
+	unknownArtifact artifactFormat = iota
+	zipArtifact
+	tarGzArtifact
+	tarXzArtifact
+	tarZstdArtifact
+	tarArtifact
+	executableArtifact
+)
+
+func (f artifactFormat) String() string {
+	switch f {
+	case zipArtifact:
+		return "zip"
+	case tarGzArtifact:
+		return "tar.gz"
+	case tarXzArtifact:
+		return "tar.xz"
+	case tarZstdArtifact:
+		return "tar.zst"
+	case tarArtifact:
+		return "tar"
+	case executableArtifact:
+		return "executable"
+	}
+	return "unknown"
+}
+
+// tar archives carry their magic after the first header fields
+const tarMagicOffset = 257
+
+// number of bytes read from an artifact to detect its format
+const artifactSniffLen = tarMagicOffset + 8
+
+var errUnknownArtifact = errors.New("unrecognized artifact format")
+
+var artifactMagics = []struct {
+	magic  []byte
+	format artifactFormat
+}{
+	{[]byte("PK\x03\x04"), zipArtifact},
+	{[]byte{0x1f, 0x8b}, tarGzArtifact},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, tarXzArtifact},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, tarZstdArtifact},
+	// ELF
+	{[]byte{0x7f, 'E', 'L', 'F'}, executableArtifact},
+	// Mach-O 32 and 64 bits, both endiannesses, and universal binaries
+	{[]byte{0xfe, 0xed, 0xfa, 0xce}, executableArtifact},
+	{[]byte{0xfe, 0xed, 0xfa, 0xcf}, executableArtifact},
+	{[]byte{0xce, 0xfa, 0xed, 0xfe}, executableArtifact},
+	{[]byte{0xcf, 0xfa, 0xed, 0xfe}, executableArtifact},
+	{[]byte{0xca, 0xfe, 0xba, 0xbe}, executableArtifact},
+	// scripts
+	{[]byte("#!"), executableArtifact},
+}
+
+// detectArtifactFormat returns the format of the artifact given its first bytes
+func detectArtifactFormat(header []byte) artifactFormat {
+	for _, m := range artifactMagics {
+		if bytes.HasPrefix(header, m.magic) {
+			return m.format
+		}
+	}
+	if len(header) >= tarMagicOffset+5 && string(header[tarMagicOffset:tarMagicOffset+5]) == "ustar" {
+		return tarArtifact
+	}
+	return unknownArtifact
+}
+
+// sniffArtifactFormat reads the first bytes of the file at path to detect its format
+func sniffArtifactFormat(path string) (artifactFormat, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return unknownArtifact, err
+	}
+	defer f.Close()
+	header := make([]byte, artifactSniffLen)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return unknownArtifact, fmt.Errorf("failed reading artifact %s: %w", path, err)
+	}
+	return detectArtifactFormat(header[:n]), nil
+}
+
+// InstallArtifact installs the downloaded artifact at artifactPath into binDir.
+// The format is detected by magic bytes: zip, tar, tar.gz, tar.xz and tar.zst
+// archives are extracted, while bare executables are copied as binDir/binName.
+// binName may be empty if the artifact is known to be an archive.
+func InstallArtifact(artifactPath string, binDir string, binName string) error {
+	defer timings.Track(timings.FileIO)()
+	format, err := sniffArtifactFormat(artifactPath)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case zipArtifact:
+		return installZipArchive(artifactPath, binDir)
+	case tarGzArtifact:
+		return installTarGzArchive(artifactPath, binDir)
+	case tarXzArtifact, tarZstdArtifact, tarArtifact:
+		return installCompressedTarArchive(artifactPath, binDir, format)
+	case executableArtifact:
+		return installExecutable(artifactPath, binDir, binName)
+	}
+	return fmt.Errorf("%s: %w", artifactPath, errUnknownArtifact)
+}
+
+// installCompressedTarArchive extracts a tar archive compressed with xz,
+// zstd, or not compressed at all
+func installCompressedTarArchive(archivePath string, binDir string, format artifactFormat) error {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	var tarStream io.Reader
+	switch format {
+	case tarXzArtifact:
+		tarStream, err = xz.NewReader(archive)
+		if err != nil {
+			return fmt.Errorf("failed creating xz reader from binary stream: %w", err)
+		}
+	case tarZstdArtifact:
+		decoder, err := zstd.NewReader(archive)
+		if err != nil {
+			return fmt.Errorf("failed creating zstd reader from binary stream: %w", err)
+		}
+		defer decoder.Close()
+		tarStream = decoder
+	default:
+		tarStream = archive
+	}
+	return installTarArchive(tarStream, binDir)
+}
+
+// installExecutable copies a bare executable into binDir as binName
+func installExecutable(executablePath string, binDir string, binName string) error {
+	if binName == "" {
+		return fmt.Errorf("%s is a bare executable but no binary name was given for it", executablePath)
+	}
+	target, err := sanitizeArchivePath(binDir, binName)
+	if err != nil {
+		return err
+	}
+	if target == filepath.Clean(binDir) {
+		return fmt.Errorf("invalid binary name %q", binName)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), constants.DefaultPerms755); err != nil {
+		return fmt.Errorf("failed to create app binary directory: %w", err)
+	}
+	in, err := os.Open(executablePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, constants.DefaultPerms755)
+	if err != nil {
+		return fmt.Errorf("failed opening binary file %s: %w", target, err)
+	}
+	if err := newExtractionLimit().copy(out, in, binName); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
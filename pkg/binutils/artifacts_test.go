@@ -0,0 +1,113 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package binutils
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+)
+
+func writeTestTar(t *testing.T, path string, wrap func(io.Writer) (io.WriteCloser, error), name string, content []byte) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w, err := wrap(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(w)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestInstallArtifactArchives(t *testing.T) {
+	assert := assert.New(t)
+
+	wrappers := map[string]func(io.Writer) (io.WriteCloser, error){
+		"tar": func(w io.Writer) (io.WriteCloser, error) { return nopWriteCloser{w}, nil },
+		"tar.xz": func(w io.Writer) (io.WriteCloser, error) {
+			return xz.NewWriter(w)
+		},
+		"tar.zst": func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		},
+	}
+	content := []byte("vm binary")
+	for ext, wrap := range wrappers {
+		path := filepath.Join(t.TempDir(), "vm."+ext)
+		writeTestTar(t, path, wrap, "subnet-evm", content)
+
+		format, err := sniffArtifactFormat(path)
+		assert.NoError(err)
+		assert.Equal(ext, format.String())
+
+		installDir := t.TempDir()
+		assert.NoError(InstallArtifact(path, installDir, ""), ext)
+		installed, err := os.ReadFile(filepath.Join(installDir, "subnet-evm"))
+		assert.NoError(err, ext)
+		assert.Equal(content, installed, ext)
+	}
+
+	tgzPath := writeTestTarGz(t, "subnet-evm", content)
+	format, err := sniffArtifactFormat(tgzPath)
+	assert.NoError(err)
+	assert.Equal(tarGzArtifact, format)
+
+	zipPath := writeTestZip(t, map[string]os.FileMode{"subnet-evm": 0o755}, content)
+	format, err = sniffArtifactFormat(zipPath)
+	assert.NoError(err)
+	assert.Equal(zipArtifact, format)
+}
+
+func TestInstallArtifactExecutable(t *testing.T) {
+	assert := assert.New(t)
+
+	content := append([]byte{0x7f, 'E', 'L', 'F'}, []byte("vm binary")...)
+	path := filepath.Join(t.TempDir(), "vm")
+	assert.NoError(os.WriteFile(path, content, 0o600))
+
+	installDir := filepath.Join(t.TempDir(), "bin")
+	assert.Error(InstallArtifact(path, installDir, ""))
+	assert.Error(InstallArtifact(path, installDir, "../evil"))
+
+	assert.NoError(InstallArtifact(path, installDir, "subnet-evm"))
+	target := filepath.Join(installDir, "subnet-evm")
+	installed, err := os.ReadFile(target)
+	assert.NoError(err)
+	assert.Equal(content, installed)
+	info, err := os.Stat(target)
+	assert.NoError(err)
+	assert.NotZero(info.Mode().Perm() & 0o100)
+}
+
+func TestInstallArtifactUnknown(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "vm")
+	assert.NoError(os.WriteFile(path, []byte("not a binary"), 0o600))
+	assert.ErrorIs(InstallArtifact(path, t.TempDir(), "subnet-evm"), errUnknownArtifact)
+}
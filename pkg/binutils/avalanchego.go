@@ -0,0 +1,92 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	avalanchegoName = "avalanchego"
+	zipExtension    = "zip"
+)
+
+// DownloadAvalancheGo downloads the given release version of avalanchego,
+// and installs it into binDir. It returns the directory it is installed in.
+func DownloadAvalancheGo(log logging.Logger, version, binDir string) (string, error) {
+	// TODO: would be nice if we could also here just use DownloadReleaseVersion(),
+	// but unfortunately we don't have a consistent naming scheme between avalanchego and subnet-evm
+	// releases and names (and supported `goos`).
+	// Doing so therefore would require adding some questionable complexity.
+	// The goal MUST be to have some sort of mature binary management
+
+	// NOTE: if any of the underlying URLs change (github changes, release file names, etc.) this fails
+	arch := runtime.GOARCH
+	goos := runtime.GOOS
+	var avalanchegoURL string
+	var ext string
+
+	switch goos {
+	case "linux":
+		avalanchegoURL = fmt.Sprintf(
+			"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-linux-%s-%s.tar.gz",
+			version,
+			arch,
+			version,
+		)
+		ext = "tar.gz"
+	case "darwin":
+		avalanchegoURL = fmt.Sprintf(
+			"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-macos-%s.zip",
+			version,
+			version,
+		)
+		ext = zipExtension
+		// EXPERMENTAL WIN, no support
+	case "windows":
+		avalanchegoURL = fmt.Sprintf(
+			"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-win-%s-experimental.zip",
+			version,
+			version,
+		)
+		ext = zipExtension
+	default:
+		return "", fmt.Errorf("OS not supported: %s", goos)
+	}
+
+	log.Debug("starting download from %s...", avalanchegoURL)
+
+	resp, err := GithubGet(avalanchegoURL, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
+	}
+
+	archive, err := DownloadToTempFile(resp.Body, binDir)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(archive)
+
+	log.Debug("download successful. installing archive...")
+	if err := InstallArchive(archive, binDir); err != nil {
+		return "", err
+	}
+	avagoSubDir := avalanchegoName + "-" + version
+	if ext == zipExtension {
+		// zip contains a build subdir instead of the avagoSubDir expected from tar.gz
+		if err := os.Rename(filepath.Join(binDir, "build"), filepath.Join(binDir, avagoSubDir)); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(binDir, avagoSubDir), nil
+}
@@ -5,7 +5,6 @@ package binutils
 import (
 	"archive/tar"
 	"archive/zip"
-	"bytes"
 	"compress/gzip"
 	"errors"
 	"fmt"
@@ -15,9 +14,12 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/storage"
 	"github.com/coreos/go-semver/semver"
 )
 
@@ -29,6 +31,7 @@ var (
 
 type PluginBinaryDownloader interface {
 	Download(vmIDs map[string]struct{}, pluginDir, binDir string) error
+	SubnetEVMBinary(binDir string) (string, error)
 }
 
 type BinaryChecker interface {
@@ -38,7 +41,8 @@ type BinaryChecker interface {
 type (
 	binaryChecker          struct{}
 	pluginBinaryDownloader struct {
-		log logging.Logger
+		log  logging.Logger
+		conf *config.Config
 	}
 )
 
@@ -46,80 +50,202 @@ func NewBinaryChecker() BinaryChecker {
 	return &binaryChecker{}
 }
 
-func NewPluginBinaryDownloader(log logging.Logger) PluginBinaryDownloader {
+func NewPluginBinaryDownloader(log logging.Logger, conf *config.Config) PluginBinaryDownloader {
 	return &pluginBinaryDownloader{
-		log: log,
+		log:  log,
+		conf: conf,
 	}
 }
 
+var errArchiveTooLarge = errors.New("exceeds the extraction size limit")
+
+// maximum total size of the files extracted from an archive
+var maxExtractedSize int64 = 4 * maxCopy
+
 // Sanitize archive file pathing from "G305: Zip Slip vulnerability"
 func sanitizeArchivePath(d, t string) (v string, err error) {
 	v = filepath.Join(d, t)
-	if strings.HasPrefix(v, filepath.Clean(d)) {
+	root := filepath.Clean(d)
+	if v == root || strings.HasPrefix(v, root+string(os.PathSeparator)) {
 		return v, nil
 	}
 
 	return "", fmt.Errorf("%s: %s", "content filepath is tainted", t)
 }
 
-// InstallArchive installs the binary archive downloaded
-func InstallArchive(ext string, archive []byte, binDir string) error {
-	if ext == "zip" {
-		return installZipArchive(archive, binDir)
+// extractionLimit bounds the size of the files extracted from an archive,
+// against archives decompressing to much more than their own size
+type extractionLimit struct {
+	remaining int64
+}
+
+func newExtractionLimit() *extractionLimit {
+	return &extractionLimit{remaining: maxExtractedSize}
+}
+
+// copy writes the archive entry name from src to dst, failing if it exceeds
+// the size limits
+func (l *extractionLimit) copy(dst io.Writer, src io.Reader, name string) error {
+	limit := l.remaining
+	if limit > maxCopy {
+		limit = maxCopy
+	}
+	n, err := io.CopyN(dst, src, limit+1)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed writing archive entry %s to disk: %w", name, err)
+	}
+	if n > limit {
+		return fmt.Errorf("archive entry %s %w", name, errArchiveTooLarge)
 	}
-	return installTarGzArchive(archive, binDir)
+	l.remaining -= n
+	return nil
 }
 
-// installZipArchive expects a byte stream of a zip file
-func installZipArchive(zipfile []byte, binDir string) error {
-	bytesReader := bytes.NewReader(zipfile)
-	zipReader, err := zip.NewReader(bytesReader, int64(len(zipfile)))
+// DownloadToTempFile streams body into a new temporary file in dir, and
+// returns its path. The caller is responsible for removing the file.
+func DownloadToTempFile(body io.Reader, dir string) (string, error) {
+	defer timings.Track(timings.Download)()
+	if err := os.MkdirAll(dir, constants.DefaultPerms755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "download-*")
+	if err != nil {
+		return "", err
+	}
+	n, err := io.CopyN(f, body, maxCopy+1)
+	if err == io.EOF {
+		err = nil
+	}
+	if err == nil && n > maxCopy {
+		err = fmt.Errorf("download %w", errArchiveTooLarge)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// InstallArchive extracts the binary archive file at archivePath into binDir.
+// The archive format is detected from its content, see InstallArtifact.
+func InstallArchive(archivePath string, binDir string) error {
+	return InstallArtifact(archivePath, binDir, "")
+}
+
+// CreateTarGzArchive writes the content of srcDir to a tar.gz archive at
+// archivePath, under rootName, so that InstallArchive extracts it to a
+// rootName directory
+func CreateTarGzArchive(srcDir string, rootName string, archivePath string) error {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// links and special files are never extracted
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(rootName, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return fmt.Errorf("failed creating archive %s: %w", archivePath, err)
+	}
+	return nil
+}
+
+// installZipArchive expects the path of a zip file
+func installZipArchive(zipPath string, binDir string) error {
+	zipReader, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return fmt.Errorf("failed creating zip reader from binary stream: %w", err)
 	}
+	defer zipReader.Close()
 
 	if err := os.MkdirAll(binDir, constants.DefaultPerms755); err != nil {
 		return fmt.Errorf("failed to create app binary directory: %w", err)
 	}
 
+	limit := newExtractionLimit()
+
 	// Closure to address file descriptors issue, uses Close to to not leave open descriptors
 	extractAndWriteFile := func(f *zip.File) error {
-		rc, err := f.Open()
-		if err != nil {
-			return fmt.Errorf("failed opening zip file: %w", err)
-		}
-
 		// check for zip slip
 		path, err := sanitizeArchivePath(binDir, f.Name)
 		if err != nil {
 			return err
 		}
 
-		if f.FileInfo().IsDir() {
-			if err := os.MkdirAll(path, f.Mode()); err != nil {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(path, constants.DefaultPerms755); err != nil {
 				return fmt.Errorf("failed creating directory from zip entry: %w", err)
 			}
-		} else {
-			if err := os.MkdirAll(filepath.Dir(path), f.Mode()); err != nil {
-				return fmt.Errorf("failed creating file from zip entry: %w", err)
-			}
-			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-			if err != nil {
-				return fmt.Errorf("failed opening file from zip entry: %w", err)
-			}
+			return nil
+		case !mode.IsRegular():
+			return fmt.Errorf("unsupported zip entry %s of type %s", f.Name, mode.Type())
+		}
 
-			_, err = io.CopyN(f, rc, maxCopy)
-			if err != nil && err != io.EOF {
-				return fmt.Errorf("failed writing zip file entry to disk: %w", err)
-			}
-			if err := f.Close(); err != nil {
-				return err
-			}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed opening zip file: %w", err)
+		}
+		defer rc.Close()
+
+		if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+			return fmt.Errorf("failed creating file from zip entry: %w", err)
 		}
-		if err := rc.Close(); err != nil {
+		out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+		if err != nil {
+			return fmt.Errorf("failed opening file from zip entry: %w", err)
+		}
+		if err := limit.copy(out, rc, f.Name); err != nil {
+			out.Close()
 			return err
 		}
-		return nil
+		return out.Close()
 	}
 
 	for _, f := range zipReader.File {
@@ -132,15 +258,28 @@ func installZipArchive(zipfile []byte, binDir string) error {
 	return nil
 }
 
-// installTarGzArchive expects a byte array in targz format
-func installTarGzArchive(targz []byte, binDir string) error {
-	byteReader := bytes.NewReader(targz)
-	uncompressedStream, err := gzip.NewReader(byteReader)
+// installTarGzArchive expects the path of a file in targz format
+func installTarGzArchive(targzPath string, binDir string) error {
+	targz, err := os.Open(targzPath)
+	if err != nil {
+		return err
+	}
+	defer targz.Close()
+
+	uncompressedStream, err := gzip.NewReader(targz)
 	if err != nil {
 		return fmt.Errorf("failed creating gzip reader from avalanchego binary stream: %w", err)
 	}
+	defer uncompressedStream.Close()
 
-	tarReader := tar.NewReader(uncompressedStream)
+	return installTarArchive(uncompressedStream, binDir)
+}
+
+// installTarArchive extracts an uncompressed tar stream
+func installTarArchive(tarStream io.Reader, binDir string) error {
+	limit := newExtractionLimit()
+
+	tarReader := tar.NewReader(tarStream)
 	for {
 		header, err := tarReader.Next()
 		switch {
@@ -161,7 +300,7 @@ func installTarGzArchive(targz []byte, binDir string) error {
 			return err
 		}
 
-		// check the file type
+		// check the file type, links and special files are never extracted
 		switch header.Typeflag {
 		// if its a dir and it doesn't exist create it
 		case tar.TypeDir:
@@ -172,13 +311,17 @@ func installTarGzArchive(targz []byte, binDir string) error {
 			}
 		// if it's a file create it
 		case tar.TypeReg:
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			if err := os.MkdirAll(filepath.Dir(target), constants.DefaultPerms755); err != nil {
+				return fmt.Errorf("failed creating directory from tar entry %w", err)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
 			if err != nil {
 				return fmt.Errorf("failed opening new file from tar entry %w", err)
 			}
 			// copy over contents
-			if _, err := io.CopyN(f, tarReader, maxCopy); err != nil && err != io.EOF {
-				return fmt.Errorf("failed writing tar entry contents to disk: %w", err)
+			if err := limit.copy(f, tarReader, header.Name); err != nil {
+				f.Close()
+				return err
 			}
 			// manually close here after each file operation; defering would cause each file close
 			// to wait until all operations have completed.
@@ -245,23 +388,63 @@ func (d *pluginBinaryDownloader) Download(vmIDs map[string]struct{}, pluginDir,
 
 // getVMBinary downloads the binary from the binary server URL
 func (d *pluginBinaryDownloader) DownloadVM(vmID string, pluginDir, binDir string) error {
+	// TODO: we are hardcoding the release version
+	// until we have a better binary, dependency and version management
+	// as per https://github.com/ava-labs/avalanche-cli/pull/17#discussion_r887164924
+	_, pinned := d.conf.SubnetEVMVersion()
+	/*
+		version, err := GetLatestReleaseVersion(constants.SubnetEVMReleaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to get latest subnet-evm release version: %w", err)
+		}
+	*/
+
 	binaryPath := filepath.Join(pluginDir, vmID)
 	info, err := os.Stat(binaryPath)
-	if err == nil {
+	// a version pinned by the project is always copied over,
+	// as the existing binary may be of another version
+	if err == nil && !pinned {
 		if info.Mode().IsRegular() {
 			d.log.Debug("binary already exists, skipping download")
 			return nil
 		}
 		return fmt.Errorf("binary plugin path %q was found but is not a regular file", binaryPath)
 	}
-	if !errors.Is(err, os.ErrNotExist) {
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	evmPath, err := d.SubnetEVMBinary(binDir)
+	if err != nil {
 		return err
 	}
 
-	binChecker := NewBinaryChecker()
-	exists, subnetEVMDir, err := binChecker.ExistsWithLatestVersion(binDir, subnetEVMName+"-v")
+	if err := copyFile(evmPath, binaryPath); err != nil {
+		return fmt.Errorf("failed copying subnet-evm to plugin dir: %w", err)
+	}
+
+	return nil
+}
+
+// SubnetEVMBinary returns the path of the binary of the subnet-evm release
+// the plugins are installed from, the version pinned by the project or else
+// the latest one in binDir, downloading it if missing
+func (d *pluginBinaryDownloader) SubnetEVMBinary(binDir string) (string, error) {
+	version, pinned := d.conf.SubnetEVMVersion()
+	var (
+		exists       bool
+		subnetEVMDir string
+		err          error
+	)
+	if pinned {
+		subnetEVMDir = filepath.Join(binDir, subnetEVMName+"-"+version)
+		exists, err = storage.FolderExists(subnetEVMDir)
+	} else {
+		binChecker := NewBinaryChecker()
+		exists, subnetEVMDir, err = binChecker.ExistsWithLatestVersion(binDir, subnetEVMName+"-v")
+	}
 	if err != nil {
-		return fmt.Errorf("failed trying to locate plugin binary: %s", binDir)
+		return "", fmt.Errorf("failed trying to locate plugin binary: %s", binDir)
 	}
 	if exists {
 		d.log.Debug("local plugin binary found. skipping installation")
@@ -271,32 +454,14 @@ func (d *pluginBinaryDownloader) DownloadVM(vmID string, pluginDir, binDir strin
 		cancel := make(chan struct{})
 		go ux.PrintWait(cancel)
 
-		// TODO: we are hardcoding the release version
-		// until we have a better binary, dependency and version management
-		// as per https://github.com/ava-labs/avalanche-cli/pull/17#discussion_r887164924
-		version := constants.SubnetEVMReleaseVersion
-		/*
-			version, err := GetLatestReleaseVersion(constants.SubnetEVMReleaseURL)
-			if err != nil {
-				return fmt.Errorf("failed to get latest subnet-evm release version: %w", err)
-			}
-		*/
-
 		subnetEVMDir, err = DownloadReleaseVersion(d.log, subnetEVMName, version, binDir)
 		if err != nil {
-			return fmt.Errorf("failed downloading subnet-evm version: %w", err)
+			return "", fmt.Errorf("failed downloading subnet-evm version: %w", err)
 		}
 		close(cancel)
 		fmt.Println()
 	}
-
-	evmPath := filepath.Join(subnetEVMDir, subnetEVMName)
-
-	if err := copyFile(evmPath, binaryPath); err != nil {
-		return fmt.Errorf("failed copying subnet-evm to plugin dir: %w", err)
-	}
-
-	return nil
+	return filepath.Join(subnetEVMDir, subnetEVMName), nil
 }
 
 // cleanupPluginDir removes all other plugins other than the given and `evm`
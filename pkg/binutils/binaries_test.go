@@ -34,10 +34,7 @@ func TestInstallZipArchive(t *testing.T) {
 	assert.NoError(err)
 	defer os.RemoveAll(installDir)
 
-	zipBytes, err := os.ReadFile(zip)
-	assert.NoError(err)
-
-	err = installZipArchive(zipBytes, installDir)
+	err = installZipArchive(zip, installDir)
 	assert.NoError(err)
 
 	checkFunc(archivePath)
@@ -59,10 +56,7 @@ func TestInstallGzipArchive(t *testing.T) {
 	assert.NoError(err)
 	defer os.RemoveAll(installDir)
 
-	tgzBytes, err := os.ReadFile(tgz)
-	assert.NoError(err)
-
-	err = installTarGzArchive(tgzBytes, installDir)
+	err = installTarGzArchive(tgz, installDir)
 	assert.NoError(err)
 
 	checkFunc(archivePath)
@@ -227,3 +221,142 @@ func createTestArchivePath(t *testing.T, assert *assert.Assertions) (string, fun
 
 	return testDir, checkFunc
 }
+
+func writeTestZip(t *testing.T, entries map[string]os.FileMode, content []byte) string {
+	path := filepath.Join(t.TempDir(), "test.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zipWriter := zip.NewWriter(f)
+	for name, mode := range entries {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(mode)
+		w, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writeTestTarGz(t *testing.T, name string, content []byte) string {
+	path := filepath.Join(t.TempDir(), "test.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSanitizeArchivePath(t *testing.T) {
+	assert := assert.New(t)
+
+	root := filepath.Join(os.TempDir(), "bin")
+	for _, name := range []string{"avalanchego", "build/plugins/evm", "./a/../b"} {
+		_, err := sanitizeArchivePath(root, name)
+		assert.NoError(err, name)
+	}
+	// a sibling directory sharing the prefix of the root is outside of it
+	for _, name := range []string{"../evil", "../bin2/evil", "a/../../evil"} {
+		_, err := sanitizeArchivePath(root, name)
+		assert.Error(err, name)
+	}
+}
+
+func TestInstallArchiveRejectsTaintedEntries(t *testing.T) {
+	assert := assert.New(t)
+
+	parent := t.TempDir()
+	installDir := filepath.Join(parent, "bin")
+
+	zipPath := writeTestZip(t, map[string]os.FileMode{"../bin2/evil": 0o644}, []byte("evil"))
+	assert.Error(InstallArchive(zipPath, installDir))
+	assert.NoFileExists(filepath.Join(parent, "bin2", "evil"))
+
+	tgzPath := writeTestTarGz(t, "../evil", []byte("evil"))
+	assert.Error(InstallArchive(tgzPath, installDir))
+	assert.NoFileExists(filepath.Join(parent, "evil"))
+
+	zipPath = writeTestZip(t, map[string]os.FileMode{"link": os.ModeSymlink | 0o777}, []byte("/etc/passwd"))
+	assert.ErrorContains(InstallArchive(zipPath, installDir), "unsupported zip entry")
+}
+
+func TestInstallArchiveSizeLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	defaultMax := maxExtractedSize
+	maxExtractedSize = 16
+	defer func() { maxExtractedSize = defaultMax }()
+
+	content := make([]byte, 17)
+	zipPath := writeTestZip(t, map[string]os.FileMode{"big": 0o644}, content)
+	err := InstallArchive(zipPath, t.TempDir())
+	assert.ErrorIs(err, errArchiveTooLarge)
+
+	tgzPath := writeTestTarGz(t, "big", content)
+	err = InstallArchive(tgzPath, t.TempDir())
+	assert.ErrorIs(err, errArchiveTooLarge)
+
+	// the limit applies to the total extracted size, files missing a parent
+	// directory entry are still extracted
+	tgzPath = writeTestTarGz(t, "sub/dir/small", content[:16])
+	installDir := t.TempDir()
+	assert.NoError(InstallArchive(tgzPath, installDir))
+	assert.FileExists(filepath.Join(installDir, "sub", "dir", "small"))
+}
+
+func TestDownloadToTempFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := filepath.Join(t.TempDir(), "downloads")
+	path, err := DownloadToTempFile(strings.NewReader("archive"), dir)
+	assert.NoError(err)
+	assert.Equal(dir, filepath.Dir(path))
+	content, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("archive", string(content))
+}
+
+func TestCreateTarGzArchive(t *testing.T) {
+	assert := assert.New(t)
+
+	src := t.TempDir()
+	assert.NoError(os.MkdirAll(filepath.Join(src, "db", "node1"), perms.ReadWriteExecute))
+	assert.NoError(os.WriteFile(filepath.Join(src, "network.json"), []byte("network"), perms.ReadWrite))
+	assert.NoError(os.WriteFile(filepath.Join(src, "db", "node1", "data"), []byte("data"), perms.ReadWrite))
+
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	assert.NoError(CreateTarGzArchive(src, "anr-snapshot-test", archive))
+
+	dst := t.TempDir()
+	assert.NoError(InstallArchive(archive, dst))
+	content, err := os.ReadFile(filepath.Join(dst, "anr-snapshot-test", "network.json"))
+	assert.NoError(err)
+	assert.Equal("network", string(content))
+	content, err = os.ReadFile(filepath.Join(dst, "anr-snapshot-test", "db", "node1", "data"))
+	assert.NoError(err)
+	assert.Equal("data", string(content))
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/coreos/go-semver/semver"
+)
+
+var (
+	errNotExecutable = errors.New("is not an executable")
+
+	// downloadAvalancheGo and downloadSubnetEVM install a release into
+	// binDir, and return the directory it is installed in
+	downloadAvalancheGo = DownloadAvalancheGo
+	downloadSubnetEVM   = func(log logging.Logger, version, binDir string) (string, error) {
+		return DownloadReleaseVersion(log, subnetEVMName, version, binDir)
+	}
+)
+
+// CachedFile is a file of a release kept in the local cache
+type CachedFile struct {
+	Release string `json:"release"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// cachedRelease is a release the local networks need
+type cachedRelease struct {
+	name     string
+	version  string
+	download func(log logging.Logger, version, binDir string) (string, error)
+}
+
+// CheckReleaseVersion checks version is the tag of a release, like v1.7.14
+func CheckReleaseVersion(version string) error {
+	if !strings.HasPrefix(version, "v") {
+		return fmt.Errorf("invalid release version %q, must start with v", version)
+	}
+	if _, err := semver.NewVersion(version[1:]); err != nil {
+		return fmt.Errorf("invalid release version %q: %w", version, err)
+	}
+	return nil
+}
+
+// WarmCache downloads the releases of avalanchego and subnet-evm local
+// networks need into binDir, unless they are there already, and verifies
+// them. Releases failing verification are downloaded again. It returns the
+// manifest of the cached files.
+func WarmCache(log logging.Logger, binDir, avalancheGoVersion, subnetEVMVersion string) ([]CachedFile, error) {
+	releases := []cachedRelease{
+		{name: avalanchegoName, version: avalancheGoVersion, download: downloadAvalancheGo},
+		{name: subnetEVMName, version: subnetEVMVersion, download: downloadSubnetEVM},
+	}
+	manifest := []CachedFile{}
+	for _, release := range releases {
+		files, err := warmRelease(log, binDir, release)
+		if err != nil {
+			return nil, err
+		}
+		manifest = append(manifest, files...)
+	}
+	return manifest, nil
+}
+
+func warmRelease(log logging.Logger, binDir string, release cachedRelease) ([]CachedFile, error) {
+	dir := filepath.Join(binDir, release.name+"-"+release.version)
+	files, err := verifyRelease(release, dir)
+	switch {
+	case err == nil:
+		log.Debug("%s %s already cached in %s", release.name, release.version, dir)
+		return files, nil
+	case errors.Is(err, os.ErrNotExist):
+		ux.Logger.PrintToUser("Downloading %s %s...", release.name, release.version)
+	default:
+		ux.Logger.PrintToUser("Cached %s %s is invalid (%s), downloading it again...", release.name, release.version, err)
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+	}
+	dir, err = release.download(log, release.version, binDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed downloading %s %s: %w", release.name, release.version, err)
+	}
+	files, err = verifyRelease(release, dir)
+	if err != nil {
+		return nil, fmt.Errorf("downloaded %s %s failed verification: %w", release.name, release.version, err)
+	}
+	return files, nil
+}
+
+// verifyRelease checks the binary of the release installed in dir is an
+// executable, and returns the files of the release
+func verifyRelease(release cachedRelease, dir string) ([]CachedFile, error) {
+	binaryPath := filepath.Join(dir, release.name)
+	info, err := os.Stat(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	format, err := sniffArtifactFormat(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 || format != executableArtifact {
+		return nil, fmt.Errorf("%s %w", binaryPath, errNotExecutable)
+	}
+
+	files := []CachedFile{}
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		sum, err := SHA256File(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, CachedFile{
+			Release: release.name,
+			Version: release.version,
+			Path:    path,
+			Size:    info.Size(),
+			SHA256:  sum,
+		})
+		return nil
+	})
+	return files, err
+}
+
+// SHA256File returns the SHA-256 of the file at path, hex encoded
+func SHA256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReleaseVersion(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(CheckReleaseVersion("v1.7.14"))
+	assert.Error(CheckReleaseVersion("1.7.14"))
+	assert.Error(CheckReleaseVersion("vlatest"))
+}
+
+func TestWarmCache(t *testing.T) {
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	downloads := map[string]int{}
+	fakeDownload := func(name string) func(logging.Logger, string, string) (string, error) {
+		return func(_ logging.Logger, version, binDir string) (string, error) {
+			downloads[name]++
+			dir := filepath.Join(binDir, name+"-"+version)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return "", err
+			}
+			return dir, os.WriteFile(filepath.Join(dir, name), []byte("\x7fELF binary"), 0o755)
+		}
+	}
+	defer func(avalanchego, subnetEVM func(logging.Logger, string, string) (string, error)) {
+		downloadAvalancheGo, downloadSubnetEVM = avalanchego, subnetEVM
+	}(downloadAvalancheGo, downloadSubnetEVM)
+	downloadAvalancheGo = fakeDownload(avalanchegoName)
+	downloadSubnetEVM = fakeDownload(subnetEVMName)
+
+	binDir := t.TempDir()
+	manifest, err := WarmCache(logging.NoLog{}, binDir, "v1.7.14", "v0.2.4")
+	assert.NoError(err)
+	assert.Len(manifest, 2)
+	assert.Equal(avalanchegoName, manifest[0].Release)
+	assert.Equal("v1.7.14", manifest[0].Version)
+	assert.Equal(int64(len("\x7fELF binary")), manifest[0].Size)
+	assert.Len(manifest[0].SHA256, 64)
+	assert.Equal(map[string]int{avalanchegoName: 1, subnetEVMName: 1}, downloads)
+
+	// cached releases are not downloaded again
+	_, err = WarmCache(logging.NoLog{}, binDir, "v1.7.14", "v0.2.4")
+	assert.NoError(err)
+	assert.Equal(map[string]int{avalanchegoName: 1, subnetEVMName: 1}, downloads)
+
+	// invalid releases are
+	assert.NoError(os.WriteFile(filepath.Join(binDir, "subnet-evm-v0.2.4", subnetEVMName), []byte("truncated"), 0o755))
+	_, err = WarmCache(logging.NoLog{}, binDir, "v1.7.14", "v0.2.4")
+	assert.NoError(err)
+	assert.Equal(map[string]int{avalanchegoName: 1, subnetEVMName: 2}, downloads)
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// versionTimeout bounds how long a binary runs to print its version, as a
+// plugin not knowing --version may not exit right away
+const versionTimeout = 10 * time.Second
+
+// rpcChainVMProtocols are the releases of avalanchego by the version of the
+// RPCChainVM protocol they speak with the VM plugins. A plugin fails its
+// handshake with the releases speaking another version than its own.
+var rpcChainVMProtocols = map[uint][]string{
+	14: {"v1.7.13"},
+	15: {"v1.7.14", "v1.7.15", "v1.7.16", "v1.7.17", "v1.7.18"},
+	16: {"v1.8.0", "v1.8.1", "v1.8.2", "v1.8.3", "v1.8.4", "v1.8.5", "v1.8.6"},
+}
+
+// subnetEVMProtocols are the releases of subnet-evm by the version of the
+// RPCChainVM protocol they speak, for the releases which don't report it
+// with --version
+var subnetEVMProtocols = map[uint][]string{
+	14: {"v0.2.3", "v0.2.4"},
+}
+
+var (
+	// avalancheGoVersionRegex matches the version printed by avalanchego
+	// --version, e.g. avalanche/1.7.16 [database=v1.4.5, commit=...]
+	avalancheGoVersionRegex = regexp.MustCompile(`avalanche/(\d+\.\d+\.\d+)`)
+	// rpcChainVMRegex matches the RPCChainVM protocol printed by the plugins
+	// reporting it with --version, e.g. Subnet-EVM/v0.4.0 [AvalancheGo=v1.9.0, rpcchainvm=17]
+	rpcChainVMRegex = regexp.MustCompile(`rpcchainvm=(\d+)`)
+	// releaseVersionRegex matches the version printed by subnet-evm --version
+	releaseVersionRegex = regexp.MustCompile(`v\d+\.\d+\.\d+`)
+
+	// runVersion runs the binary at path with --version and returns its
+	// combined output
+	runVersion = func(path string) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+		defer cancel()
+		return exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	}
+)
+
+// RPCChainVMProtocol returns the version of the RPCChainVM protocol
+// avalanchego speaks at version, false if it isn't known
+func RPCChainVMProtocol(avalancheGoVersion string) (uint, bool) {
+	return findProtocol(rpcChainVMProtocols, avalancheGoVersion)
+}
+
+func findProtocol(protocols map[uint][]string, version string) (uint, bool) {
+	for protocol, versions := range protocols {
+		for _, v := range versions {
+			if v == version {
+				return protocol, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ProtocolReleases returns the releases of avalanchego speaking protocol, as
+// a user friendly string
+func ProtocolReleases(protocol uint) string {
+	versions := rpcChainVMProtocols[protocol]
+	if len(versions) == 0 {
+		return "none known"
+	}
+	return strings.Join(versions, ", ")
+}
+
+// CompatibilityMatrix returns the releases of avalanchego and subnet-evm by
+// the RPCChainVM protocol they speak, one line per protocol
+func CompatibilityMatrix() []string {
+	protocols := []uint{}
+	for protocol := range rpcChainVMProtocols {
+		protocols = append(protocols, protocol)
+	}
+	sort.Slice(protocols, func(i, j int) bool {
+		return protocols[i] < protocols[j]
+	})
+	lines := make([]string, 0, len(protocols))
+	for _, protocol := range protocols {
+		line := fmt.Sprintf("protocol %d: avalanchego %s", protocol, ProtocolReleases(protocol))
+		if versions := subnetEVMProtocols[protocol]; len(versions) > 0 {
+			line += "; subnet-evm " + strings.Join(versions, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// AvalancheGoBinaryVersion returns the release version the avalanchego binary
+// at path reports with --version. Builds of a branch report the release they
+// are based on.
+func AvalancheGoBinaryVersion(path string) (string, error) {
+	out, err := runVersion(path)
+	if err != nil {
+		return "", fmt.Errorf("failed running %s --version: %w: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	match := avalancheGoVersionRegex.FindSubmatch(out)
+	if match == nil {
+		return "", fmt.Errorf("%s --version printed %q, which is not the version of avalanchego", path, strings.TrimSpace(string(out)))
+	}
+	return "v" + string(match[1]), nil
+}
+
+// PluginProtocol returns the version the VM plugin at path reports with
+// --version, and the RPCChainVM protocol it speaks: the one it reports, or
+// the one of its subnet-evm release. ok is false if the protocol is unknown,
+// e.g. for a VM not reporting it.
+func PluginProtocol(path string) (version string, protocol uint, ok bool) {
+	out, err := runVersion(path)
+	if err != nil {
+		return "", 0, false
+	}
+	version = strings.TrimSpace(string(out))
+	if match := rpcChainVMRegex.FindSubmatch(out); match != nil {
+		advertised, err := strconv.ParseUint(string(match[1]), 10, 32)
+		if err == nil {
+			return version, uint(advertised), true
+		}
+	}
+	if release := releaseVersionRegex.Find(out); release != nil {
+		protocol, ok = findProtocol(subnetEVMProtocols, string(release))
+	}
+	return version, protocol, ok
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCChainVMProtocol(t *testing.T) {
+	assert := assert.New(t)
+
+	protocol, ok := RPCChainVMProtocol("v1.7.16")
+	assert.True(ok)
+	assert.Equal(uint(15), protocol)
+	protocol, ok = RPCChainVMProtocol("v1.7.13")
+	assert.True(ok)
+	assert.Equal(uint(14), protocol)
+	_, ok = RPCChainVMProtocol("v0.1.0")
+	assert.False(ok)
+
+	assert.Equal("v1.7.13", ProtocolReleases(14))
+	assert.Equal("none known", ProtocolReleases(1))
+}
+
+func TestAvalancheGoBinaryVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	binPath := filepath.Join(dir, "avalanchego")
+	assert.NoError(os.WriteFile(binPath, []byte("#!/bin/sh\necho 'avalanche/1.7.16 [database=v1.4.5, commit=abc]'\n"), 0o755))
+	version, err := AvalancheGoBinaryVersion(binPath)
+	assert.NoError(err)
+	assert.Equal("v1.7.16", version)
+
+	otherPath := filepath.Join(dir, "other")
+	assert.NoError(os.WriteFile(otherPath, []byte("#!/bin/sh\necho 'other 1.0.0'\n"), 0o755))
+	_, err = AvalancheGoBinaryVersion(otherPath)
+	assert.ErrorContains(err, "not the version of avalanchego")
+
+	_, err = AvalancheGoBinaryVersion(filepath.Join(dir, "missing"))
+	assert.Error(err)
+}
+
+func TestPluginProtocol(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	writePlugin := func(name string, script string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+		return path
+	}
+
+	// the protocol reported by the plugin wins
+	version, protocol, ok := PluginProtocol(writePlugin("advertised", "echo 'Subnet-EVM/v0.4.0 [AvalancheGo=v1.9.0, rpcchainvm=17]'"))
+	assert.True(ok)
+	assert.Equal(uint(17), protocol)
+	assert.Equal("Subnet-EVM/v0.4.0 [AvalancheGo=v1.9.0, rpcchainvm=17]", version)
+
+	// the one of the subnet-evm releases not reporting it is known
+	version, protocol, ok = PluginProtocol(writePlugin("release", "echo v0.2.4@0123abc"))
+	assert.True(ok)
+	assert.Equal(uint(14), protocol)
+	assert.Equal("v0.2.4@0123abc", version)
+
+	_, _, ok = PluginProtocol(writePlugin("unknown", "echo v9.9.9"))
+	assert.False(ok)
+	_, _, ok = PluginProtocol(writePlugin("failing", "echo 'This binary is a plugin.'; exit 1"))
+	assert.False(ok)
+}
+
+func TestCompatibilityMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	matrix := CompatibilityMatrix()
+	assert.Len(matrix, len(rpcChainVMProtocols))
+	assert.Equal("protocol 14: avalanchego v1.7.13; subnet-evm v0.2.3, v0.2.4", matrix[0])
+	assert.Contains(matrix[1], "protocol 15: avalanchego v1.7.14")
+}
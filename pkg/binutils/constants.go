@@ -5,10 +5,13 @@ package binutils
 import "time"
 
 const (
-	gRPCClientLogLevel  = "error"
-	gRPCServerEndpoint  = ":8097"
-	gRPCGatewayEndpoint = ":8098"
-	gRPCDialTimeout     = 10 * time.Second
+	gRPCClientLogLevel     = "error"
+	defaultGRPCServerPort  = 8097
+	defaultGRPCGatewayPort = 8098
+	firstProfileGRPCPort   = 8100
+	gRPCDialTimeout        = 10 * time.Second
+
+	profilePortsFile = "ports.json"
 
 	subnetEVMName = "subnet-evm"
 	maxCopy       = 2147483648 // 2 GB
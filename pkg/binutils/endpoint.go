@@ -0,0 +1,58 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// LocalEndpoint returns the URI of the first node of the local network. The
+// nodes of the default profile are on known ports, so their URI is known even
+// if the network isn't running, unlike the ones of other profiles, for which
+// an empty string is returned then.
+func LocalEndpoint(app *application.Avalanche) string {
+	if uri := runningNodeURI(app); uri != "" {
+		return uri
+	}
+	if app.IsDefaultProfile() {
+		return constants.LocalAPIEndpoint
+	}
+	return ""
+}
+
+// runningNodeURI returns the URI of the first node of the running local
+// network, or an empty string if it can't be queried
+func runningNodeURI(app *application.Avalanche) string {
+	isRunning, err := NewProcessChecker().IsServerProcessRunning(app)
+	if err != nil || !isRunning {
+		return ""
+	}
+	cli, err := NewGRPCClient(app)
+	if err != nil {
+		app.Log.Warn("could not get connection to server: %s", err)
+		return ""
+	}
+	defer cli.Close()
+	status, err := cli.Status(GetAsyncContext())
+	if err != nil {
+		// TODO: use error type not string comparison
+		if !strings.Contains(err.Error(), "not bootstrapped") {
+			app.Log.Warn("failed to query server for status: %s", err)
+		}
+		return ""
+	}
+	nodeInfos := status.GetClusterInfo().GetNodeInfos()
+	names := make([]string, 0, len(nodeInfos))
+	for name := range nodeInfos {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return nodeInfos[names[0]].GetUri()
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const (
+	githubTokenEnvVar = "GITHUB_TOKEN"
+	// rate limits resetting sooner than this are waited for, instead of failing
+	maxRateLimitWait = time.Minute
+	// githubHeadTimeout bounds the HEAD requests telling the size of a
+	// download, which is only informative
+	githubHeadTimeout = 10 * time.Second
+)
+
+var (
+	errRateLimited = errors.New("GitHub rate limit exceeded")
+
+	// GITHUB_TOKEN is only ever sent to these hosts
+	githubTokenHosts = map[string]bool{
+		"api.github.com": true,
+	}
+)
+
+// GithubGet issues a GET request for a GitHub URL. For the GitHub API, the
+// token in GITHUB_TOKEN is used if set, to get a higher rate limit. If etag is
+// set, the request is conditional, and a 304 response means the resource did
+// not change since. A rate limit resetting within a minute is waited for once,
+// otherwise an error telling when to retry is returned.
+// Responses with any other status are returned as is.
+func GithubGet(rawURL, etag string) (*http.Response, error) {
+	defer timings.Track(timings.Download)()
+	for retried := false; ; retried = true {
+		req, err := newGithubRequest(rawURL, etag)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if retried || wait > maxRateLimitWait {
+			return nil, rateLimitError(rawURL, wait)
+		}
+		time.Sleep(wait)
+	}
+}
+
+// GithubContentLength returns the size of the file at rawURL, from the
+// headers of a HEAD request, or -1 if the server doesn't tell it
+func GithubContentLength(rawURL string) (int64, error) {
+	req, err := newGithubRequest(rawURL, "")
+	if err != nil {
+		return 0, err
+	}
+	req.Method = http.MethodHead
+	client := &http.Client{Timeout: githubHeadTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
+	}
+	return resp.ContentLength, nil
+}
+
+func newGithubRequest(rawURL, etag string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if u, err := url.Parse(rawURL); err == nil && githubTokenHosts[u.Host] {
+		if token := os.Getenv(githubTokenEnvVar); token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	return req, nil
+}
+
+// rateLimitWait tells if the response is a rate limit error, and how long to
+// wait before the limit resets
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		// forbidden for another reason
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, true
+	}
+	wait := time.Unix(reset, 0).Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+func rateLimitError(rawURL string, wait time.Duration) error {
+	msg := fmt.Sprintf("%s for %s", errRateLimited, rawURL)
+	if wait > 0 {
+		msg += fmt.Sprintf(", retry in %s", wait.Round(time.Second))
+	}
+	if os.Getenv(githubTokenEnvVar) == "" {
+		msg += fmt.Sprintf(". Set %s to a GitHub token to get a higher rate limit", githubTokenEnvVar)
+	}
+	return exitcodes.Backend(fmt.Errorf("%s: %w", msg, errRateLimited))
+}
+
+type releaseCacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// releaseCache keeps GitHub API responses by URL, with their ETag,
+// so that unchanged resources don't count against the rate limit
+type releaseCache struct {
+	path    string
+	lock    sync.Mutex
+	Entries map[string]releaseCacheEntry `json:"entries"`
+}
+
+// loadReleaseCache reads the cache stored at path.
+// A missing or unreadable file just results in an empty cache.
+func loadReleaseCache(path string) *releaseCache {
+	c := &releaseCache{
+		path:    path,
+		Entries: map[string]releaseCacheEntry{},
+	}
+	cacheBytes, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(cacheBytes, c); err != nil || c.Entries == nil {
+		c.Entries = map[string]releaseCacheEntry{}
+	}
+	return c
+}
+
+func (c *releaseCache) get(rawURL string) (releaseCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.Entries[rawURL]
+	return entry, ok
+}
+
+func (c *releaseCache) put(rawURL string, entry releaseCacheEntry) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Entries[rawURL] = entry
+	cacheBytes, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, cacheBytes, perms.ReadWrite)
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// trustTestServer lets GITHUB_TOKEN be sent to the test server
+func trustTestServer(t *testing.T, server *httptest.Server) {
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	githubTokenHosts[u.Host] = true
+	t.Cleanup(func() { delete(githubTokenHosts, u.Host) })
+}
+
+func TestGetLatestReleaseVersionCache(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv(githubTokenEnvVar, "secret")
+
+	requests := 0
+	rateLimited := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal("token secret", r.Header.Get("Authorization"))
+		if rateLimited {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.3"}`))
+	}))
+	defer server.Close()
+	trustTestServer(t, server)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+
+	version, err := GetLatestReleaseVersion(app, server.URL)
+	assert.NoError(err)
+	assert.Equal("v1.2.3", version)
+
+	// the second lookup is conditional and served from the cache
+	version, err = GetLatestReleaseVersion(app, server.URL)
+	assert.NoError(err)
+	assert.Equal("v1.2.3", version)
+	assert.Equal(2, requests)
+
+	// rate limited lookups fall back to the cache
+	rateLimited = true
+	version, err = GetLatestReleaseVersion(app, server.URL)
+	assert.NoError(err)
+	assert.Equal("v1.2.3", version)
+
+	// but fail with a clear error without one
+	_, err = GetLatestReleaseVersion(app, server.URL+"/other")
+	assert.True(errors.Is(err, errRateLimited))
+	assert.Equal(exitcodes.BackendFailure, exitcodes.FromError(err))
+	assert.Contains(err.Error(), "retry in")
+}
+
+func TestGithubGetRetriesShortRateLimit(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv(githubTokenEnvVar, "")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// the token is never sent to hosts other than GitHub
+		assert.Empty(r.Header.Get("Authorization"))
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := GithubGet(server.URL, "")
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(2, requests)
+}
+
+func TestRateLimitWait(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1_000_000, 0)
+
+	tests := []struct {
+		name            string
+		status          int
+		headers         map[string]string
+		expectedWait    time.Duration
+		expectedLimited bool
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "not found", status: http.StatusNotFound},
+		{name: "forbidden", status: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "10"}},
+		{
+			name:            "retry after",
+			status:          http.StatusTooManyRequests,
+			headers:         map[string]string{"Retry-After": "30"},
+			expectedWait:    30 * time.Second,
+			expectedLimited: true,
+		},
+		{
+			name:            "reset",
+			status:          http.StatusForbidden,
+			headers:         map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1000120"},
+			expectedWait:    2 * time.Minute,
+			expectedLimited: true,
+		},
+		{
+			name:            "reset passed",
+			status:          http.StatusForbidden,
+			headers:         map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "999999"},
+			expectedLimited: true,
+		},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		for k, v := range tt.headers {
+			resp.Header.Set(k, v)
+		}
+		wait, limited := rateLimitWait(resp, now)
+		assert.Equal(tt.expectedLimited, limited, tt.name)
+		assert.Equal(tt.expectedWait, wait, tt.name)
+	}
+}
@@ -4,6 +4,7 @@ package binutils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,26 +12,46 @@ import (
 	"path/filepath"
 	"runtime"
 
+	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 )
 
-// GetLatestReleaseVersion returns the latest available version from github
-func GetLatestReleaseVersion(releaseURL string) (string, error) {
+// GetLatestReleaseVersion returns the latest available version from github.
+// Responses are cached in the app dir with their ETag, and the cached version
+// is used if the rate limit is hit.
+func GetLatestReleaseVersion(app *application.Avalanche, releaseURL string) (string, error) {
 	// TODO: Question if there is a less error prone (= simpler) way to install latest avalanchego
 	// Maybe the binary package manager should also allow the actual avalanchego binary for download
-	resp, err := http.Get(releaseURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to get latest version from %s: %w", releaseURL, err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to get latest version from %s: unexpected http status code: %d", releaseURL, resp.StatusCode)
-	}
-	defer resp.Body.Close()
+	cache := loadReleaseCache(app.GetReleaseCachePath())
+	cached, isCached := cache.get(releaseURL)
 
-	jsonBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to get latest binary version from %s: %w", releaseURL, err)
+	var jsonBytes []byte
+	resp, err := GithubGet(releaseURL, cached.ETag)
+	switch {
+	case err != nil && isCached && errors.Is(err, errRateLimited):
+		app.Log.Warn("%s, using the cached latest version", err)
+		jsonBytes = cached.Body
+	case err != nil:
+		return "", fmt.Errorf("failed to get latest version from %s: %w", releaseURL, err)
+	default:
+		defer resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusNotModified && isCached:
+			app.Log.Debug("latest version from %s unchanged since last lookup", releaseURL)
+			jsonBytes = cached.Body
+		case resp.StatusCode == http.StatusOK:
+			jsonBytes, err = io.ReadAll(resp.Body)
+			if err != nil {
+				return "", fmt.Errorf("failed to get latest binary version from %s: %w", releaseURL, err)
+			}
+			entry := releaseCacheEntry{ETag: resp.Header.Get("ETag"), Body: jsonBytes}
+			if err := cache.put(releaseURL, entry); err != nil {
+				app.Log.Warn("failed caching latest version from %s: %s", releaseURL, err)
+			}
+		default:
+			return "", fmt.Errorf("failed to get latest version from %s: unexpected http status code: %d", releaseURL, resp.StatusCode)
+		}
 	}
 
 	var jsonStr map[string]interface{}
@@ -38,7 +59,7 @@ func GetLatestReleaseVersion(releaseURL string) (string, error) {
 		return "", fmt.Errorf("failed to unmarshal binary json version string: %w", err)
 	}
 
-	version := jsonStr["tag_name"].(string)
+	version, _ := jsonStr["tag_name"].(string)
 	if version == "" || version[0] != 'v' {
 		return "", fmt.Errorf("invalid version string: %s", version)
 	}
@@ -59,7 +80,6 @@ func DownloadReleaseVersion(
 	arch := runtime.GOARCH
 	goos := runtime.GOOS
 	var downloadURL string
-	var ext string
 
 	switch goos {
 	case "linux":
@@ -71,7 +91,6 @@ func DownloadReleaseVersion(
 			version[1:], // WARN subnet-evm isn't consistent in its release naming, it's omitting the v in the file name...
 			arch,
 		)
-		ext = "tar.gz"
 	case "darwin":
 		downloadURL = fmt.Sprintf(
 			"https://github.com/ava-labs/%s/releases/download/%s/%s_%s_darwin_%s.tar.gz",
@@ -82,34 +101,34 @@ func DownloadReleaseVersion(
 			arch,
 		)
 		// subnet-evm supports darwin and linux only
-		ext = "tar.gz"
 	default:
 		return "", fmt.Errorf("OS not supported: %s", goos)
 	}
 
 	log.Debug("starting download from %s...", downloadURL)
 
-	resp, err := http.Get(downloadURL)
+	resp, err := GithubGet(downloadURL, "")
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
 	}
-	defer resp.Body.Close()
 
-	archive, err := io.ReadAll(resp.Body)
+	archive, err := DownloadToTempFile(resp.Body, binDir)
 	if err != nil {
 		return "", err
 	}
+	defer os.Remove(archive)
 
 	installDir := filepath.Join(binDir, repo+"-"+version)
 	if err := os.MkdirAll(installDir, perms.ReadWriteExecute); err != nil {
 		return "", fmt.Errorf("failed creating %s installation directory: %s", repo, err)
 	}
 
-	log.Debug("download successful. installing archive...")
-	if err := InstallArchive(ext, archive, installDir); err != nil {
+	log.Debug("download successful. installing artifact...")
+	if err := InstallArtifact(archive, installDir, repo); err != nil {
 		return "", err
 	}
 	return installDir, nil
@@ -0,0 +1,320 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPool holds the gRPC clients of the backends, for the lifetime of
+// the command
+var defaultPool = NewClientPool(dialGRPC, constants.RequestTimeout)
+
+// CloseGRPCClients closes the connections to the backends, once the command
+// is done with them
+func CloseGRPCClients() error {
+	return defaultPool.Close()
+}
+
+// ClientPool shares a gRPC client per backend among the operations of a
+// command, instead of dialing the backend for each of them. The clients are
+// safe for concurrent use, and their Close is a no-op, the pool closing them
+// instead.
+type ClientPool struct {
+	dial    func(endpoint string) (client.Client, error)
+	timeout time.Duration
+
+	lock    sync.Mutex
+	clients map[string]*pooledClient
+}
+
+// NewClientPool creates a pool dialing the backends with dial, and setting
+// timeout as the deadline of the calls whose context has none
+func NewClientPool(dial func(endpoint string) (client.Client, error), timeout time.Duration) *ClientPool {
+	return &ClientPool{
+		dial:    dial,
+		timeout: timeout,
+		clients: map[string]*pooledClient{},
+	}
+}
+
+// Get returns the client of the backend at endpoint, run by the process
+// serverPID. The client is dialed again if the backend was restarted since,
+// or if it could not be reached.
+func (p *ClientPool) Get(endpoint string, serverPID int) (client.Client, error) {
+	p.lock.Lock()
+	c, ok := p.clients[endpoint]
+	if !ok {
+		c = &pooledClient{pool: p, endpoint: endpoint}
+		p.clients[endpoint] = c
+	}
+	p.lock.Unlock()
+	if _, err := c.refresh(serverPID); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes all the clients of the pool
+func (p *ClientPool) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var firstErr error
+	for endpoint, c := range p.clients {
+		if err := c.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.clients, endpoint)
+	}
+	return firstErr
+}
+
+// withDeadline sets the default deadline of the pool to ctx if it has none
+func (p *ClientPool) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || p.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.timeout)
+}
+
+// pooledClient is the client of a backend, whose connection is replaced when
+// the backend is restarted or can't be reached anymore
+type pooledClient struct {
+	pool     *ClientPool
+	endpoint string
+
+	lock      sync.Mutex
+	cli       client.Client
+	serverPID int
+	broken    bool
+}
+
+// refresh dials the backend again if the connection is missing, broken, or
+// to a previous run of the backend, and returns the connection
+func (c *pooledClient) refresh(serverPID int) (client.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cli != nil && !c.broken && c.serverPID == serverPID {
+		return c.cli, nil
+	}
+	if c.cli != nil {
+		_ = c.cli.Close()
+		c.cli = nil
+	}
+	cli, err := c.pool.dial(c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c.cli, c.serverPID, c.broken = cli, serverPID, false
+	return cli, nil
+}
+
+// connection returns the connection, dialed again if missing or broken
+func (c *pooledClient) connection() (client.Client, error) {
+	c.lock.Lock()
+	serverPID := c.serverPID
+	c.lock.Unlock()
+	return c.refresh(serverPID)
+}
+
+func (c *pooledClient) close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cli == nil {
+		return nil
+	}
+	err := c.cli.Close()
+	c.cli = nil
+	return err
+}
+
+// call runs an RPC with the default deadline if ctx has none, marking the
+// connection broken if the backend can't be reached, for the next call to
+// dial it again
+func (c *pooledClient) call(ctx context.Context, rpc func(context.Context, client.Client) error) error {
+	defer timings.Track(timings.GRPC)()
+	cli, err := c.connection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.pool.withDeadline(ctx)
+	defer cancel()
+	err = rpc(ctx, cli)
+	if status.Code(err) == codes.Unavailable {
+		c.lock.Lock()
+		if c.cli == cli {
+			c.broken = true
+		}
+		c.lock.Unlock()
+	}
+	return err
+}
+
+// read runs an RPC without side effects, which is retried once on a new
+// connection if the backend can't be reached, e.g. as it was restarted
+func (c *pooledClient) read(ctx context.Context, rpc func(context.Context, client.Client) error) error {
+	err := c.call(ctx, rpc)
+	if status.Code(err) != codes.Unavailable || ctx.Err() != nil {
+		return err
+	}
+	return c.call(ctx, rpc)
+}
+
+// Close is a no-op, the pool closes the connection
+func (*pooledClient) Close() error {
+	return nil
+}
+
+func (c *pooledClient) Ping(ctx context.Context) (resp *rpcpb.PingResponse, err error) {
+	err = c.read(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.Ping(ctx)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) Start(ctx context.Context, execPath string, opts ...client.OpOption) (resp *rpcpb.StartResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.Start(ctx, execPath, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) CreateBlockchains(ctx context.Context, blockchainSpecs []*rpcpb.BlockchainSpec) (resp *rpcpb.CreateBlockchainsResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.CreateBlockchains(ctx, blockchainSpecs)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) CreateSubnets(ctx context.Context, opts ...client.OpOption) (resp *rpcpb.CreateSubnetsResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.CreateSubnets(ctx, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) Health(ctx context.Context) (resp *rpcpb.HealthResponse, err error) {
+	err = c.read(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.Health(ctx)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) URIs(ctx context.Context) (uris []string, err error) {
+	err = c.read(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		uris, err = cli.URIs(ctx)
+		return err
+	})
+	return uris, err
+}
+
+func (c *pooledClient) Status(ctx context.Context) (resp *rpcpb.StatusResponse, err error) {
+	err = c.read(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.Status(ctx)
+		return err
+	})
+	return resp, err
+}
+
+// StreamStatus streams for as long as ctx lasts, without a default deadline
+func (c *pooledClient) StreamStatus(ctx context.Context, pushInterval time.Duration) (<-chan *rpcpb.ClusterInfo, error) {
+	cli, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	return cli.StreamStatus(ctx, pushInterval)
+}
+
+func (c *pooledClient) RemoveNode(ctx context.Context, name string) (resp *rpcpb.RemoveNodeResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.RemoveNode(ctx, name)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) RestartNode(ctx context.Context, name string, opts ...client.OpOption) (resp *rpcpb.RestartNodeResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.RestartNode(ctx, name, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) AddNode(ctx context.Context, name string, execPath string, opts ...client.OpOption) (resp *rpcpb.AddNodeResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.AddNode(ctx, name, execPath, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) Stop(ctx context.Context) (resp *rpcpb.StopResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.Stop(ctx)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) AttachPeer(ctx context.Context, nodeName string) (resp *rpcpb.AttachPeerResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.AttachPeer(ctx, nodeName)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) SendOutboundMessage(ctx context.Context, nodeName string, peerID string, op uint32, msgBody []byte) (resp *rpcpb.SendOutboundMessageResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.SendOutboundMessage(ctx, nodeName, peerID, op, msgBody)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) SaveSnapshot(ctx context.Context, snapshotName string) (resp *rpcpb.SaveSnapshotResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.SaveSnapshot(ctx, snapshotName)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) LoadSnapshot(ctx context.Context, snapshotName string, opts ...client.OpOption) (resp *rpcpb.LoadSnapshotResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.LoadSnapshot(ctx, snapshotName, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) RemoveSnapshot(ctx context.Context, snapshotName string) (resp *rpcpb.RemoveSnapshotResponse, err error) {
+	err = c.call(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		resp, err = cli.RemoveSnapshot(ctx, snapshotName)
+		return err
+	})
+	return resp, err
+}
+
+func (c *pooledClient) GetSnapshotNames(ctx context.Context) (names []string, err error) {
+	err = c.read(ctx, func(ctx context.Context, cli client.Client) (err error) {
+		names, err = cli.GetSnapshotNames(ctx)
+		return err
+	})
+	return names, err
+}
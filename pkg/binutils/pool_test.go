@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientPool(t *testing.T) {
+	assert := assert.New(t)
+
+	dials := 0
+	var clients []*mocks.Client
+	// the first connection fails its first status call, as if the backend
+	// was restarted
+	dial := func(endpoint string) (client.Client, error) {
+		dials++
+		c := &mocks.Client{}
+		if dials == 1 {
+			c.On("Status", mock.Anything).Return(nil, status.Error(codes.Unavailable, "connection refused")).Once()
+		}
+		c.On("Status", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ok
+		})).Return(&rpcpb.StatusResponse{}, nil)
+		c.On("Close").Return(nil)
+		clients = append(clients, c)
+		return c, nil
+	}
+	pool := NewClientPool(dial, time.Minute)
+
+	cli, err := pool.Get("127.0.0.1:8097", 10)
+	assert.NoError(err)
+	assert.NoError(cli.Close())
+	again, err := pool.Get("127.0.0.1:8097", 10)
+	assert.NoError(err)
+	assert.Same(cli, again)
+	assert.Equal(1, dials)
+
+	// a read is retried on a new connection, with the default deadline
+	_, err = cli.Status(context.Background())
+	assert.NoError(err)
+	assert.Equal(2, dials)
+	clients[0].AssertCalled(t, "Close")
+
+	// a restarted backend is dialed again
+	_, err = pool.Get("127.0.0.1:8097", 11)
+	assert.NoError(err)
+	assert.Equal(3, dials)
+	_, err = pool.Get("127.0.0.1:8098", 11)
+	assert.NoError(err)
+	assert.Equal(4, dials)
+
+	assert.NoError(pool.Close())
+	clients[2].AssertCalled(t, "Close")
+	clients[3].AssertCalled(t, "Close")
+	// a client used after the pool is closed is dialed again
+	_, err = cli.Status(context.Background())
+	assert.NoError(err)
+	assert.Equal(5, dials)
+}
@@ -4,30 +4,42 @@ package binutils
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"os/signal"
-	"path"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/supervisor"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/client"
 	"github.com/ava-labs/avalanche-network-runner/server"
-	"github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
-	"github.com/ava-labs/avalanchego/utils/perms"
-	"github.com/docker/docker/pkg/reexec"
-	"github.com/shirou/gopsutil/process"
+	"google.golang.org/grpc/metadata"
 )
 
-// errGRPCTimeout is a common error message if the gRPC server can't be reached
-var errGRPCTimeout = errors.New("timed out trying to contact backend controller, it is most probably not running")
+// correlationIDMetadataKey is the gRPC metadata the correlation ID of the
+// invocation is sent to the backend as
+const correlationIDMetadataKey = "x-correlation-id"
+
+var (
+	// errGRPCTimeout is a common error message if the gRPC server can't be reached
+	errGRPCTimeout = errors.New("timed out trying to contact backend controller, it is most probably not running")
+
+	// correlationID identifies the invocation in the requests to the backend
+	correlationID string
+)
+
+// SetCorrelationID sets the correlation ID of the invocation, sent along
+// with the requests to the backend made with GetAsyncContext
+func SetCorrelationID(id string) {
+	correlationID = id
+}
 
 // ProcessChecker is responsible for checking if the gRPC server is running
 type ProcessChecker interface {
@@ -44,119 +56,141 @@ func NewProcessChecker() ProcessChecker {
 }
 
 // NewGRPCClient hides away the details (params) of creating a gRPC server connection
-func NewGRPCClient() (client.Client, error) {
+// to the backend of the current profile. The connection is shared with the
+// other clients of the backend in the command, and closed once the command
+// is done, Close being a no-op.
+func NewGRPCClient(app *application.Avalanche) (client.Client, error) {
+	ports, err := GetGRPCPorts(app)
+	if err != nil {
+		return nil, err
+	}
+	// the backend is dialed again once restarted, 0 if it is not running
+	serverPID, _ := GetServerPID(app)
+	return defaultPool.Get(ports.serverEndpoint(), serverPID)
+}
+
+// dialGRPC connects to the backend at endpoint
+func dialGRPC(endpoint string) (client.Client, error) {
 	client, err := client.New(client.Config{
 		LogLevel:    gRPCClientLogLevel,
-		Endpoint:    gRPCServerEndpoint,
+		Endpoint:    endpoint,
 		DialTimeout: gRPCDialTimeout,
 	})
 	if errors.Is(err, context.DeadlineExceeded) {
 		err = errGRPCTimeout
 	}
-	return client, err
+	if err == nil && traceLog != nil {
+		client = newTracingClient(client, traceLog)
+	}
+	return client, exitcodes.Backend(err)
 }
 
-// NewGRPCClient hides away the details (params) of creating a gRPC server
-func NewGRPCServer(snapshotsDir string) (server.Server, error) {
+// NewGRPCServer hides away the details (params) of creating a gRPC server
+// for the current profile
+func NewGRPCServer(app *application.Avalanche) (server.Server, error) {
+	ports, err := GetGRPCPorts(app)
+	if err != nil {
+		return nil, err
+	}
 	return server.New(server.Config{
-		Port:                gRPCServerEndpoint,
-		GwPort:              gRPCGatewayEndpoint,
+		Port:                ports.serverEndpoint(),
+		GwPort:              ports.gatewayEndpoint(),
 		DialTimeout:         gRPCDialTimeout,
-		SnapshotsDir:        snapshotsDir,
+		SnapshotsDir:        app.GetSnapshotsDir(),
 		RedirectNodesOutput: false,
 	})
 }
 
-// IsServerProcessRunning returns true if the gRPC server is running,
-// or false if not
+// IsServerProcessRunning returns true if the gRPC server is running, or
+// being restarted by its supervisor, or false if not
 func (rpr *realProcessRunner) IsServerProcessRunning(app *application.Avalanche) (bool, error) {
-	pid, err := GetServerPID(app)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return false, err
-		}
-	}
-
-	// get OS process list
-	procs, err := process.Processes()
-	if err != nil {
+	svc, err := supervisor.Get(app, constants.BackendService)
+	if err != nil || svc == nil {
 		return false, err
 	}
-
-	p32 := int32(pid)
-	// iterate all processes...
-	for _, p := range procs {
-		if p.Pid == p32 {
-			return true, nil
-		}
-	}
-	return false, nil
-}
-
-type runFile struct {
-	Pid                int    `json:"pid"`
-	GRPCserverFileName string `json:"gRPCserverFileName"`
+	return svc.Running(), nil
 }
 
+// GetServerPID returns the pid of the backend of the profile of app
 func GetServerPID(app *application.Avalanche) (int, error) {
-	var rf runFile
-	serverRunFilePath := app.GetRunFile()
-	run, err := os.ReadFile(serverRunFilePath)
+	svc, err := supervisor.Get(app, constants.BackendService)
 	if err != nil {
-		return 0, fmt.Errorf("failed reading process info file at %s: %s", serverRunFilePath, err)
+		return 0, err
 	}
-	if err := json.Unmarshal(run, &rf); err != nil {
-		return 0, fmt.Errorf("failed unmarshalling server run file at %s: %s", serverRunFilePath, err)
+	if svc == nil || svc.Pid == 0 {
+		return 0, fmt.Errorf("failed reading pid from run file at %s", app.GetRunFile())
 	}
+	return svc.Pid, nil
+}
 
-	if rf.Pid == 0 {
-		return 0, fmt.Errorf("failed reading pid from info file at %s: %s", serverRunFilePath, err)
+// GetServerCLIVersion returns the version of the CLI running the backend of
+// the profile, empty if unknown, e.g. for backends started before versions
+// were recorded
+func GetServerCLIVersion(app *application.Avalanche) (string, error) {
+	svc, err := supervisor.Get(app, constants.BackendService)
+	if err != nil {
+		return "", err
+	}
+	if svc == nil {
+		return "", fmt.Errorf("no backend recorded at %s", app.GetRunFile())
 	}
-	return rf.Pid, nil
+	return svc.CLIVersion, nil
 }
 
 // StartServerProcess starts the gRPC server as a reentrant process of this binary
-// it just executes `avalanche-cli backend start`
+// it just executes `avalanche-cli backend start`, under a supervisor restarting
+// it if it fails
 func StartServerProcess(app *application.Avalanche) error {
-	thisBin := reexec.Self()
-
-	args := []string{"backend", "start"}
-	cmd := exec.Command(thisBin, args...)
-
-	outputDirPrefix := path.Join(app.GetRunDir(), "server")
-	outputDir, err := utils.MkDirWithTimestamp(outputDirPrefix)
-	if err != nil {
-		return err
-	}
-
-	outputFile, err := os.Create(path.Join(outputDir, "avalanche-cli-backend"))
+	args := []string{"backend", "start", "--profile", app.GetProfile()}
+	// the backend logs which invocation started it
+	if app.GetCorrelationID() != "" {
+		args = append(args, "--correlation-id", app.GetCorrelationID())
+	}
+	svc, err := supervisor.Start(app, supervisor.Spec{
+		Name:          constants.BackendService,
+		Args:          args,
+		Restart:       supervisor.RestartOnFailure,
+		MaxRestarts:   constants.ServiceMaxRestarts,
+		OutputDir:     "server",
+		OutputFile:    "avalanche-cli-backend",
+		CorrelationID: app.GetCorrelationID(),
+		CLIVersion:    app.GetVersion(),
+	})
 	if err != nil {
 		return err
 	}
-	// TODO: should this be redirected to this app's log file instead?
-	cmd.Stdout = outputFile
-	cmd.Stderr = outputFile
-
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-
-	ux.Logger.PrintToUser("Backend controller started, pid: %d, output at: %s", cmd.Process.Pid, outputFile.Name())
-
-	rf := runFile{
-		Pid:                cmd.Process.Pid,
-		GRPCserverFileName: outputFile.Name(),
-	}
+	ux.Logger.PrintToUser("Backend controller started, pid: %d, output at: %s", svc.Pid, svc.OutputFile)
+	return nil
+}
 
-	rfBytes, err := json.Marshal(&rf)
+// RunServerInProcess runs the gRPC server of the profile of app in this
+// process, e.g. for Go tests, which can't reenter their binary as the
+// backend. It is recorded as the running server of the profile. The returned
+// function stops the server, and waits for it to be done.
+func RunServerInProcess(app *application.Avalanche) (func(), error) {
+	s, err := NewGRPCServer(app)
 	if err != nil {
-		return err
-	}
-
-	if err := os.WriteFile(app.GetRunFile(), rfBytes, perms.ReadWrite); err != nil {
-		app.Log.Warn("could not write gRPC process info to file: %s", err)
+		return nil, err
+	}
+	serverCtx, serverCancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := s.Run(serverCtx); err != nil {
+			app.Log.Debug("in process server closed: %s", err)
+		}
+	}()
+	svc := &supervisor.Service{Name: constants.BackendService, Pid: os.Getpid(), StartedAt: time.Now()}
+	if err := supervisor.Register(app, svc); err != nil {
+		serverCancel()
+		<-done
+		return nil, err
 	}
-	return nil
+	return func() {
+		serverCancel()
+		<-done
+		_ = supervisor.Remove(app, constants.BackendService)
+	}, nil
 }
 
 // GetAsyncContext returns a timeout context with the cancel function suppressed
@@ -169,11 +203,14 @@ func GetAsyncContext() context.Context {
 	// when the deadline is reached
 	_ = cancel
 
+	if correlationID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, correlationIDMetadataKey, correlationID)
+	}
 	return ctx
 }
 
 func KillgRPCServerProcess(app *application.Avalanche) error {
-	cli, err := NewGRPCClient()
+	cli, err := NewGRPCClient(app)
 	if err != nil {
 		return err
 	}
@@ -190,21 +227,8 @@ func KillgRPCServerProcess(app *application.Avalanche) error {
 		return fmt.Errorf("failed stopping gRPC server process: %s", err)
 	}
 
-	pid, err := GetServerPID(app)
-	if err != nil {
-		return fmt.Errorf("failed getting PID from run file: %s", err)
-	}
-	proc, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("could not find process with pid %d: %s", pid, err)
-	}
-	if err := proc.Signal(os.Interrupt); err != nil {
-		return fmt.Errorf("failed killing process with pid %d: %s", pid, err)
-	}
-
-	serverRunFilePath := app.GetRunFile()
-	if err := os.Remove(serverRunFilePath); err != nil {
-		return fmt.Errorf("failed removing run file %s: %s", serverRunFilePath, err)
+	if err := supervisor.Stop(app, constants.BackendService); err != nil && !errors.Is(err, supervisor.ErrNotRunning) {
+		return fmt.Errorf("failed stopping gRPC server process: %s", err)
 	}
 	return nil
 }
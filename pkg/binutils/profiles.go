@@ -0,0 +1,106 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+// GRPCPorts are the ports the backend of a local network profile listens on
+type GRPCPorts struct {
+	Server  int `json:"server"`
+	Gateway int `json:"gateway"`
+}
+
+func (p GRPCPorts) serverEndpoint() string {
+	return fmt.Sprintf(":%d", p.Server)
+}
+
+func (p GRPCPorts) gatewayEndpoint() string {
+	return fmt.Sprintf(":%d", p.Gateway)
+}
+
+// GetGRPCPorts returns the backend ports of the profile of app.
+// The default profile uses the well known default ports. Any other profile
+// gets the first pair of ports not used by another profile assigned on first
+// use, and keeps it afterwards.
+func GetGRPCPorts(app *application.Avalanche) (GRPCPorts, error) {
+	if app.IsDefaultProfile() {
+		return GRPCPorts{Server: defaultGRPCServerPort, Gateway: defaultGRPCGatewayPort}, nil
+	}
+	portsFile := filepath.Join(app.GetProfileDir(), profilePortsFile)
+	if ports, err := loadGRPCPorts(portsFile); err == nil {
+		return ports, nil
+	} else if !os.IsNotExist(err) {
+		return GRPCPorts{}, err
+	}
+
+	used := map[int]struct{}{
+		defaultGRPCServerPort:  {},
+		defaultGRPCGatewayPort: {},
+	}
+	otherPortsFiles, err := filepath.Glob(filepath.Join(app.GetProfilesDir(), "*", profilePortsFile))
+	if err != nil {
+		return GRPCPorts{}, err
+	}
+	for _, f := range otherPortsFiles {
+		ports, err := loadGRPCPorts(f)
+		if err != nil {
+			continue
+		}
+		used[ports.Server] = struct{}{}
+		used[ports.Gateway] = struct{}{}
+	}
+	port := firstProfileGRPCPort
+	for {
+		_, serverUsed := used[port]
+		_, gatewayUsed := used[port+1]
+		if !serverUsed && !gatewayUsed {
+			break
+		}
+		port += 2
+	}
+	ports := GRPCPorts{Server: port, Gateway: port + 1}
+	if err := SetGRPCPorts(app, ports); err != nil {
+		return GRPCPorts{}, err
+	}
+	return ports, nil
+}
+
+// SetGRPCPorts assigns the backend ports of the profile of app, which must
+// not be the default profile, e.g. to use ports known to be free
+func SetGRPCPorts(app *application.Avalanche, ports GRPCPorts) error {
+	if app.IsDefaultProfile() {
+		return fmt.Errorf("the ports of the default profile can't be set")
+	}
+	portsBytes, err := json.Marshal(&ports)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(app.GetProfileDir(), perms.ReadWriteExecute); err != nil {
+		return err
+	}
+	portsFile := filepath.Join(app.GetProfileDir(), profilePortsFile)
+	if err := os.WriteFile(portsFile, portsBytes, perms.ReadWrite); err != nil {
+		return fmt.Errorf("failed writing ports of profile %s: %w", app.GetProfile(), err)
+	}
+	return nil
+}
+
+func loadGRPCPorts(portsFile string) (GRPCPorts, error) {
+	var ports GRPCPorts
+	portsBytes, err := os.ReadFile(portsFile)
+	if err != nil {
+		return ports, err
+	}
+	if err := json.Unmarshal(portsBytes, &ports); err != nil {
+		return ports, fmt.Errorf("failed unmarshalling profile ports file %s: %w", portsFile, err)
+	}
+	return ports, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package binutils
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGRPCPorts(t *testing.T) {
+	assert := assert.New(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+
+	ports, err := GetGRPCPorts(app)
+	assert.NoError(err)
+	assert.Equal(GRPCPorts{Server: defaultGRPCServerPort, Gateway: defaultGRPCGatewayPort}, ports)
+
+	app.SetProfile("first")
+	first, err := GetGRPCPorts(app)
+	assert.NoError(err)
+	assert.Equal(GRPCPorts{Server: firstProfileGRPCPort, Gateway: firstProfileGRPCPort + 1}, first)
+
+	app.SetProfile("second")
+	second, err := GetGRPCPorts(app)
+	assert.NoError(err)
+	assert.Equal(GRPCPorts{Server: firstProfileGRPCPort + 2, Gateway: firstProfileGRPCPort + 3}, second)
+
+	// ports are kept once assigned
+	app.SetProfile("first")
+	ports, err = GetGRPCPorts(app)
+	assert.NoError(err)
+	assert.Equal(first, ports)
+}
+
+func TestSetGRPCPorts(t *testing.T) {
+	assert := assert.New(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+	assert.Error(SetGRPCPorts(app, GRPCPorts{Server: 20000, Gateway: 20001}))
+
+	app.SetProfile("tests")
+	assert.NoError(SetGRPCPorts(app, GRPCPorts{Server: 20000, Gateway: 20001}))
+	ports, err := GetGRPCPorts(app)
+	assert.NoError(err)
+	assert.Equal(GRPCPorts{Server: 20000, Gateway: 20001}, ports)
+
+	// assigned ports are not given to other profiles
+	app.SetProfile("other")
+	ports, err = GetGRPCPorts(app)
+	assert.NoError(err)
+	assert.Equal(GRPCPorts{Server: firstProfileGRPCPort, Gateway: firstProfileGRPCPort + 1}, ports)
+}
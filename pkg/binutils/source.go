@@ -0,0 +1,219 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// vmBuildsDir holds the VM binaries built from source, by commit
+	vmBuildsDir = "builds"
+	// vmBinaryName is the name of the binary built in its build directory
+	vmBinaryName = "vm"
+)
+
+var (
+	errInvalidVMSource = errors.New("invalid VM source, expected <repository>[//<package>]@<ref>")
+
+	fullCommitRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	// scpLikeURLRegex matches the git URLs like git@github.com:org/repo.git
+	scpLikeURLRegex = regexp.MustCompile(`^[^/]+@[^/]+:`)
+
+	// runCommand runs name with args in dir, with env added to the
+	// environment, and returns its combined output
+	runCommand = func(dir string, env []string, name string, args ...string) ([]byte, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		return cmd.CombinedOutput()
+	}
+)
+
+// VMSource is a git ref of the repository of a VM, to build its plugin from
+type VMSource struct {
+	// Repository is cloned from, a go import path like
+	// github.com/ava-labs/subnet-evm, a URL, or a local path
+	Repository string
+	// Package is the main package of the plugin in the repository, plugin
+	// if the repository has that directory, or its root otherwise
+	Package string
+	// Ref is the commit, branch or tag to build
+	Ref string
+}
+
+// ParseVMSource parses a VM source written as <repository>[//<package>]@<ref>,
+// e.g. github.com/ava-labs/subnet-evm@v0.2.5
+func ParseVMSource(source string) (VMSource, error) {
+	at := strings.LastIndex(source, "@")
+	if at <= 0 || at == len(source)-1 {
+		return VMSource{}, fmt.Errorf("%w: %q", errInvalidVMSource, source)
+	}
+	repository, ref := source[:at], source[at+1:]
+	pkg := ""
+	// the package is separated from the repository by the first // which
+	// is not the one of a URL scheme
+	schemeEnd := 0
+	if i := strings.Index(repository, "://"); i >= 0 {
+		schemeEnd = i + len("://")
+	}
+	if i := strings.Index(repository[schemeEnd:], "//"); i >= 0 {
+		repository, pkg = repository[:schemeEnd+i], repository[schemeEnd+i+len("//"):]
+		if pkg == "" {
+			return VMSource{}, fmt.Errorf("%w: %q", errInvalidVMSource, source)
+		}
+	}
+	// refs and repositories are passed to git, they can't be options
+	if strings.HasPrefix(ref, "-") || strings.HasPrefix(repository, "-") ||
+		strings.ContainsAny(ref, " \t\n") || strings.Contains(pkg, "..") {
+		return VMSource{}, fmt.Errorf("%w: %q", errInvalidVMSource, source)
+	}
+	return VMSource{Repository: repository, Package: pkg, Ref: ref}, nil
+}
+
+func (s VMSource) String() string {
+	if s.Package != "" {
+		return s.Repository + "//" + s.Package + "@" + s.Ref
+	}
+	return s.Repository + "@" + s.Ref
+}
+
+// cloneURL returns the URL git clones the repository from
+func (s VMSource) cloneURL() string {
+	if strings.Contains(s.Repository, "://") || filepath.IsAbs(s.Repository) ||
+		strings.HasPrefix(s.Repository, ".") || scpLikeURLRegex.MatchString(s.Repository) {
+		return s.Repository
+	}
+	return "https://" + s.Repository
+}
+
+// buildDir returns the directory the build of the commit of the source is
+// kept in
+func (s VMSource) buildDir(binDir, commit string) string {
+	name := unsafePathChars.ReplaceAllString(s.Repository+"-"+s.Package, "_")
+	return filepath.Join(binDir, vmBuildsDir, name+"-"+commit)
+}
+
+// goToolchain returns the Go toolchain pinned by the toolchain directive of
+// a go.mod file, if any
+func goToolchain(goMod []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(goMod))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "toolchain" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// BuildVMFromSource builds the plugin of a VM from the git ref of its
+// repository, and returns the path of the binary. Builds are kept by
+// commit in binDir: a commit is only built once, and a full commit hash
+// doesn't need the repository to be fetched again.
+func BuildVMFromSource(log logging.Logger, source VMSource, binDir string) (string, error) {
+	if fullCommitRegex.MatchString(source.Ref) {
+		binaryPath := filepath.Join(source.buildDir(binDir, source.Ref), vmBinaryName)
+		if _, err := os.Stat(binaryPath); err == nil {
+			log.Debug("VM already built from source at %s", binaryPath)
+			return binaryPath, nil
+		}
+	}
+
+	checkoutDir, err := os.MkdirTemp("", "vm-source-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(checkoutDir)
+
+	ux.Logger.PrintToUser("Fetching %s...", source)
+	gitSteps := [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", source.cloneURL()},
+		{"fetch", "--quiet", "--depth", "1", "origin", source.Ref},
+		{"checkout", "--quiet", "--detach", "FETCH_HEAD"},
+	}
+	for _, args := range gitSteps {
+		if out, err := runCommand(checkoutDir, nil, "git", args...); err != nil {
+			return "", fmt.Errorf("failed fetching %s: git %s: %w: %s", source, args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+	out, err := runCommand(checkoutDir, nil, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed resolving the commit of %s: %w", source, err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	buildDir := source.buildDir(binDir, commit)
+	binaryPath := filepath.Join(buildDir, vmBinaryName)
+	if _, err := os.Stat(binaryPath); err == nil {
+		log.Debug("VM already built from source at %s", binaryPath)
+		return binaryPath, nil
+	}
+
+	pkg := source.Package
+	if pkg == "" {
+		if info, err := os.Stat(filepath.Join(checkoutDir, "plugin")); err == nil && info.IsDir() {
+			pkg = "plugin"
+		} else {
+			pkg = "."
+		}
+	}
+	goMod, err := os.ReadFile(filepath.Join(checkoutDir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("%s is not a go module: %w", source, err)
+	}
+	// builds don't depend on the environment of the user, and use the Go
+	// toolchain pinned by the repository, if any
+	env := []string{"GOFLAGS=-mod=readonly", "GOWORK=off"}
+	if toolchain := goToolchain(goMod); toolchain != "" {
+		env = append(env, "GOTOOLCHAIN="+toolchain)
+	}
+	goVersion, err := runCommand(checkoutDir, env, "go", "env", "GOVERSION")
+	if err != nil {
+		return "", fmt.Errorf("failed finding the go toolchain to build %s: %w: %s", source, err, strings.TrimSpace(string(goVersion)))
+	}
+
+	ux.Logger.PrintToUser("Building %s at commit %s with %s...", source, commit, strings.TrimSpace(string(goVersion)))
+	if err := os.MkdirAll(buildDir, constants.DefaultPerms755); err != nil {
+		return "", err
+	}
+	// built to a temporary name, so that a failed build is never reused
+	tmpBinaryPath := binaryPath + ".tmp"
+	out, err = runCommand(checkoutDir, env, "go", "build", "-trimpath", "-ldflags", "-buildid=",
+		"-o", tmpBinaryPath, "./"+filepath.ToSlash(filepath.Clean(pkg)))
+	if err != nil {
+		_ = os.RemoveAll(buildDir)
+		return "", fmt.Errorf("failed building %s: %w: %s", source, err, strings.TrimSpace(string(out)))
+	}
+	if err := os.Rename(tmpBinaryPath, binaryPath); err != nil {
+		return "", err
+	}
+	return binaryPath, nil
+}
+
+// InstallVMFromSource builds the plugin of a VM from source, and installs it
+// in pluginDir as the plugin of vmID
+func InstallVMFromSource(log logging.Logger, source VMSource, vmID, pluginDir, binDir string) error {
+	binaryPath, err := BuildVMFromSource(log, source, binDir)
+	if err != nil {
+		return err
+	}
+	if err := copyFile(binaryPath, filepath.Join(pluginDir, vmID)); err != nil {
+		return fmt.Errorf("failed copying the VM built from source to the plugin dir: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVMSource(t *testing.T) {
+	type test struct {
+		source   string
+		expected VMSource
+		err      bool
+	}
+	tests := []test{
+		{
+			source:   "github.com/ava-labs/subnet-evm@v0.2.5",
+			expected: VMSource{Repository: "github.com/ava-labs/subnet-evm", Ref: "v0.2.5"},
+		},
+		{
+			source:   "github.com/org/vms//cmd/myvm@main",
+			expected: VMSource{Repository: "github.com/org/vms", Package: "cmd/myvm", Ref: "main"},
+		},
+		{
+			source:   "https://git.example.com/vm.git//plugin@0123abc",
+			expected: VMSource{Repository: "https://git.example.com/vm.git", Package: "plugin", Ref: "0123abc"},
+		},
+		{
+			source:   "git@github.com:org/vm.git@main",
+			expected: VMSource{Repository: "git@github.com:org/vm.git", Ref: "main"},
+		},
+		{source: "github.com/ava-labs/subnet-evm", err: true},
+		{source: "github.com/ava-labs/subnet-evm@", err: true},
+		{source: "github.com/ava-labs/subnet-evm@--upload-pack=x", err: true},
+		{source: "github.com/org/vms//../x@main", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert := assert.New(t)
+			source, err := ParseVMSource(tt.source)
+			if tt.err {
+				assert.ErrorIs(err, errInvalidVMSource)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.expected, source)
+			assert.Equal(tt.source, source.String())
+		})
+	}
+}
+
+func TestVMSourceCloneURL(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("https://github.com/ava-labs/subnet-evm", VMSource{Repository: "github.com/ava-labs/subnet-evm"}.cloneURL())
+	assert.Equal("git@github.com:org/vm.git", VMSource{Repository: "git@github.com:org/vm.git"}.cloneURL())
+	assert.Equal("/src/vm", VMSource{Repository: "/src/vm"}.cloneURL())
+}
+
+func TestGoToolchain(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("go1.21.5", goToolchain([]byte("module x\n\ngo 1.21\n\ntoolchain go1.21.5\n")))
+	assert.Equal("", goToolchain([]byte("module x\n\ngo 1.17\n")))
+}
+
+func TestBuildVMFromSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	repo := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/vm\n\ngo 1.17\n",
+		"plugin/main.go": "package main\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		assert.NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+	}
+	git := func(args ...string) string {
+		out, err := runCommand(repo, []string{
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		}, "git", args...)
+		assert.NoError(err, string(out))
+		return string(out)
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "vm")
+
+	binDir := t.TempDir()
+	source := VMSource{Repository: repo, Ref: "main"}
+	binaryPath, err := BuildVMFromSource(logging.NoLog{}, source, binDir)
+	assert.NoError(err)
+	assert.FileExists(binaryPath)
+
+	// the build of the commit is reused
+	again, err := BuildVMFromSource(logging.NoLog{}, source, binDir)
+	assert.NoError(err)
+	assert.Equal(binaryPath, again)
+
+	pluginDir := t.TempDir()
+	assert.NoError(InstallVMFromSource(logging.NoLog{}, source, "vmID", pluginDir, binDir))
+	assert.FileExists(filepath.Join(pluginDir, "vmID"))
+
+	_, err = BuildVMFromSource(logging.NoLog{}, VMSource{Repository: repo, Ref: "missing"}, binDir)
+	assert.Error(err)
+}
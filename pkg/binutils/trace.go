@@ -0,0 +1,215 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// traceLog is where the requests to the backend and their responses are
+// traced, nil if they aren't
+var traceLog logging.Logger
+
+// SetGRPCTrace traces the requests to the backend made by the invocation,
+// and their responses, to log
+func SetGRPCTrace(log logging.Logger) {
+	traceLog = log
+}
+
+// tracingClient traces the requests made with cli and their responses
+type tracingClient struct {
+	cli client.Client
+	log logging.Logger
+}
+
+func newTracingClient(cli client.Client, log logging.Logger) client.Client {
+	return &tracingClient{cli: cli, log: log}
+}
+
+// trace logs the request to method with args, and returns the function
+// logging its response, or error
+func (t *tracingClient) trace(method string, args ...interface{}) func(resp interface{}, err error) {
+	request := ""
+	for i := 0; i+1 < len(args); i += 2 {
+		request += fmt.Sprintf(" %s=%s", args[i], truncatePayload(fmt.Sprintf("%v", args[i+1])))
+	}
+	t.log.Info("grpc trace: -> %s%s", method, request)
+	start := time.Now()
+	return func(resp interface{}, err error) {
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err != nil {
+			t.log.Info("grpc trace: <- %s failed after %s: %s", method, elapsed, err)
+			return
+		}
+		t.log.Info("grpc trace: <- %s in %s: %s", method, elapsed, truncatePayload(fmt.Sprintf("%v", resp)))
+	}
+}
+
+// truncatePayload truncates payload to constants.GRPCTraceMaxPayload
+// bytes, e.g. for the genesis of the blockchains created
+func truncatePayload(payload string) string {
+	if len(payload) <= constants.GRPCTraceMaxPayload {
+		return payload
+	}
+	return fmt.Sprintf("%s...(%d bytes truncated)", payload[:constants.GRPCTraceMaxPayload], len(payload)-constants.GRPCTraceMaxPayload)
+}
+
+// options renders the options of a request, which are only visible once
+// applied
+func options(opts []client.OpOption) string {
+	op := client.Op{}
+	for _, opt := range opts {
+		opt(&op)
+	}
+	return fmt.Sprintf("%+v", op)
+}
+
+func (t *tracingClient) Ping(ctx context.Context) (*rpcpb.PingResponse, error) {
+	done := t.trace("Ping")
+	resp, err := t.cli.Ping(ctx)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) Start(ctx context.Context, execPath string, opts ...client.OpOption) (*rpcpb.StartResponse, error) {
+	done := t.trace("Start", "execPath", execPath, "opts", options(opts))
+	resp, err := t.cli.Start(ctx, execPath, opts...)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) CreateBlockchains(ctx context.Context, blockchainSpecs []*rpcpb.BlockchainSpec) (*rpcpb.CreateBlockchainsResponse, error) {
+	done := t.trace("CreateBlockchains", "blockchainSpecs", blockchainSpecs)
+	resp, err := t.cli.CreateBlockchains(ctx, blockchainSpecs)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) CreateSubnets(ctx context.Context, opts ...client.OpOption) (*rpcpb.CreateSubnetsResponse, error) {
+	done := t.trace("CreateSubnets", "opts", options(opts))
+	resp, err := t.cli.CreateSubnets(ctx, opts...)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) Health(ctx context.Context) (*rpcpb.HealthResponse, error) {
+	done := t.trace("Health")
+	resp, err := t.cli.Health(ctx)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) URIs(ctx context.Context) ([]string, error) {
+	done := t.trace("URIs")
+	uris, err := t.cli.URIs(ctx)
+	done(uris, err)
+	return uris, err
+}
+
+func (t *tracingClient) Status(ctx context.Context) (*rpcpb.StatusResponse, error) {
+	done := t.trace("Status")
+	resp, err := t.cli.Status(ctx)
+	done(resp, err)
+	return resp, err
+}
+
+// StreamStatus traces the cluster infos streamed, as they are received
+func (t *tracingClient) StreamStatus(ctx context.Context, pushInterval time.Duration) (<-chan *rpcpb.ClusterInfo, error) {
+	done := t.trace("StreamStatus", "pushInterval", pushInterval)
+	infos, err := t.cli.StreamStatus(ctx, pushInterval)
+	if err != nil {
+		done(nil, err)
+		return nil, err
+	}
+	done("stream opened", nil)
+	traced := make(chan *rpcpb.ClusterInfo)
+	go func() {
+		defer close(traced)
+		for info := range infos {
+			t.log.Info("grpc trace: <- StreamStatus: %s", truncatePayload(fmt.Sprintf("%v", info)))
+			traced <- info
+		}
+	}()
+	return traced, nil
+}
+
+func (t *tracingClient) RemoveNode(ctx context.Context, name string) (*rpcpb.RemoveNodeResponse, error) {
+	done := t.trace("RemoveNode", "name", name)
+	resp, err := t.cli.RemoveNode(ctx, name)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) RestartNode(ctx context.Context, name string, opts ...client.OpOption) (*rpcpb.RestartNodeResponse, error) {
+	done := t.trace("RestartNode", "name", name, "opts", options(opts))
+	resp, err := t.cli.RestartNode(ctx, name, opts...)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) AddNode(ctx context.Context, name string, execPath string, opts ...client.OpOption) (*rpcpb.AddNodeResponse, error) {
+	done := t.trace("AddNode", "name", name, "execPath", execPath, "opts", options(opts))
+	resp, err := t.cli.AddNode(ctx, name, execPath, opts...)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) Stop(ctx context.Context) (*rpcpb.StopResponse, error) {
+	done := t.trace("Stop")
+	resp, err := t.cli.Stop(ctx)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) AttachPeer(ctx context.Context, nodeName string) (*rpcpb.AttachPeerResponse, error) {
+	done := t.trace("AttachPeer", "nodeName", nodeName)
+	resp, err := t.cli.AttachPeer(ctx, nodeName)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) SendOutboundMessage(ctx context.Context, nodeName string, peerID string, op uint32, msgBody []byte) (*rpcpb.SendOutboundMessageResponse, error) {
+	done := t.trace("SendOutboundMessage", "nodeName", nodeName, "peerID", peerID, "op", op, "msgBody", fmt.Sprintf("%x", msgBody))
+	resp, err := t.cli.SendOutboundMessage(ctx, nodeName, peerID, op, msgBody)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) Close() error {
+	return t.cli.Close()
+}
+
+func (t *tracingClient) SaveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.SaveSnapshotResponse, error) {
+	done := t.trace("SaveSnapshot", "snapshotName", snapshotName)
+	resp, err := t.cli.SaveSnapshot(ctx, snapshotName)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) LoadSnapshot(ctx context.Context, snapshotName string, opts ...client.OpOption) (*rpcpb.LoadSnapshotResponse, error) {
+	done := t.trace("LoadSnapshot", "snapshotName", snapshotName, "opts", options(opts))
+	resp, err := t.cli.LoadSnapshot(ctx, snapshotName, opts...)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) RemoveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.RemoveSnapshotResponse, error) {
+	done := t.trace("RemoveSnapshot", "snapshotName", snapshotName)
+	resp, err := t.cli.RemoveSnapshot(ctx, snapshotName)
+	done(resp, err)
+	return resp, err
+}
+
+func (t *tracingClient) GetSnapshotNames(ctx context.Context) ([]string, error) {
+	done := t.trace("GetSnapshotNames")
+	names, err := t.cli.GetSnapshotNames(ctx)
+	done(names, err)
+	return names, err
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package binutils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestTracingClient(t *testing.T) {
+	assert := assert.New(t)
+
+	out := nopCloser{&bytes.Buffer{}}
+	log := logging.NewLogger(false, "", logging.NewWrappedCore(logging.Info, out, logging.Plain.FileEncoder()))
+	cli := &mocks.Client{}
+	cli.On("Start", mock.Anything, "/bin/avalanchego", mock.Anything, mock.Anything).Return(&rpcpb.StartResponse{}, nil)
+	genesis := strings.Repeat("g", 2*constants.GRPCTraceMaxPayload)
+	cli.On("CreateBlockchains", mock.Anything, mock.Anything).Return(nil, errors.New("no network"))
+	traced := newTracingClient(cli, log)
+
+	_, err := traced.Start(context.Background(), "/bin/avalanchego", client.WithNumNodes(3), client.WithPluginDir("/plugins"))
+	assert.NoError(err)
+	_, err = traced.CreateBlockchains(context.Background(), []*rpcpb.BlockchainSpec{{VmName: "subnetevm", Genesis: genesis}})
+	assert.EqualError(err, "no network")
+
+	trace := out.String()
+	assert.Contains(trace, `grpc trace: -> Start execPath=/bin/avalanchego opts={numNodes:3`)
+	assert.Contains(trace, "pluginDir:/plugins")
+	assert.Contains(trace, "grpc trace: <- Start in ")
+	assert.Contains(trace, `grpc trace: -> CreateBlockchains blockchainSpecs=[vm_name:"subnetevm"`)
+	assert.Contains(trace, "bytes truncated)")
+	assert.NotContains(trace, genesis)
+	assert.Contains(trace, "grpc trace: <- CreateBlockchains failed after ")
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package bridge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const writeReadReadPerms = 0o644
+
+// Backend is the client of a chain a bridge is deployed on
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Chain is a chain a bridge is deployed on
+type Chain struct {
+	// Name identifies the chain in the messages and in the deployment
+	Name    string
+	Backend Backend
+	ChainID *big.Int
+}
+
+// Deployment records the bridges of a subnet, deployed on its chain and on
+// the C-Chain, and how far the relayer got on each of them
+type Deployment struct {
+	Subnet string `json:"subnet"`
+	// BlockchainID is the blockchain of the subnet the bridge was deployed
+	// on, so that a redeployed subnet is detected
+	BlockchainID string         `json:"blockchainID"`
+	Relayer      common.Address `json:"relayer"`
+	SubnetBridge DeployedBridge `json:"subnetBridge"`
+	CChainBridge DeployedBridge `json:"cChainBridge"`
+}
+
+// DeployedBridge is a bridge contract of a deployment
+type DeployedBridge struct {
+	Address common.Address `json:"address"`
+	// NextBlock is the first block the relayer hasn't looked for deposits in
+	NextBlock uint64 `json:"nextBlock"`
+}
+
+// LoadDeployment reads the deployment recorded in path
+func LoadDeployment(path string) (Deployment, error) {
+	var d Deployment
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(bytes, &d); err != nil {
+		return d, fmt.Errorf("failed unmarshalling bridge deployment %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// WriteDeployment records the deployment in path
+func WriteDeployment(path string, d Deployment) error {
+	bytes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bytes, '\n'), writeReadReadPerms)
+}
+
+// Deploy deploys a bridge on chain with key, releasing with relayer, and
+// funds it with liquidity for the releases. It returns the deployed bridge.
+func Deploy(
+	ctx context.Context,
+	chain Chain,
+	key *ecdsa.PrivateKey,
+	relayer common.Address,
+	liquidity *big.Int,
+) (DeployedBridge, error) {
+	opts, err := transactOpts(ctx, chain, key)
+	if err != nil {
+		return DeployedBridge{}, err
+	}
+	address, tx, contract, err := bind.DeployContract(opts, ABI, Bytecode, chain.Backend, relayer)
+	if err != nil {
+		return DeployedBridge{}, fmt.Errorf("failed deploying the bridge on %s: %w", chain.Name, err)
+	}
+	receipt, err := waitSuccess(ctx, chain, tx)
+	if err != nil {
+		return DeployedBridge{}, fmt.Errorf("failed deploying the bridge on %s: %w", chain.Name, err)
+	}
+	bridge := DeployedBridge{Address: address, NextBlock: receipt.BlockNumber.Uint64()}
+	if liquidity == nil || liquidity.Sign() == 0 {
+		return bridge, nil
+	}
+	opts.Value = liquidity
+	tx, err = contract.Transfer(opts)
+	if err != nil {
+		return bridge, fmt.Errorf("failed funding the bridge on %s: %w", chain.Name, err)
+	}
+	if _, err := waitSuccess(ctx, chain, tx); err != nil {
+		return bridge, fmt.Errorf("failed funding the bridge on %s: %w", chain.Name, err)
+	}
+	return bridge, nil
+}
+
+// Deposit deposits amount with key in the bridge at address on chain, to be
+// released to recipient on the other chain. It returns the deposit
+// transaction.
+func Deposit(
+	ctx context.Context,
+	chain Chain,
+	address common.Address,
+	key *ecdsa.PrivateKey,
+	recipient common.Address,
+	amount *big.Int,
+) (*types.Transaction, error) {
+	opts, err := transactOpts(ctx, chain, key)
+	if err != nil {
+		return nil, err
+	}
+	opts.Value = amount
+	contract := bind.NewBoundContract(address, ABI, chain.Backend, chain.Backend, chain.Backend)
+	tx, err := contract.Transact(opts, "deposit", recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed depositing on %s: %w", chain.Name, err)
+	}
+	if _, err := waitSuccess(ctx, chain, tx); err != nil {
+		return nil, fmt.Errorf("failed depositing on %s: %w", chain.Name, err)
+	}
+	return tx, nil
+}
+
+func transactOpts(ctx context.Context, chain Chain, key *ecdsa.PrivateKey) (*bind.TransactOpts, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chain.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+	return opts, nil
+}
+
+// waitSuccess waits for tx to be accepted on chain, and checks it succeeded
+func waitSuccess(ctx context.Context, chain Chain, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, chain.Backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s reverted", tx.Hash())
+	}
+	return receipt, nil
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package bridge
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind/backends"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// acceptingBackend accepts a block with every transaction sent to it
+type acceptingBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (b acceptingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit(true)
+	return nil
+}
+
+func newTestChain(name string, funded ...common.Address) Chain {
+	balance := new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e18))
+	alloc := core.GenesisAlloc{}
+	for _, address := range funded {
+		alloc[address] = core.GenesisAccount{Balance: balance}
+	}
+	return Chain{
+		Name:    name,
+		Backend: acceptingBackend{backends.NewSimulatedBackend(alloc, 8_000_000)},
+		ChainID: big.NewInt(1337),
+	}
+}
+
+func TestBridge(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	relayerKey, err := crypto.GenerateKey()
+	assert.NoError(err)
+	userKey, err := crypto.GenerateKey()
+	assert.NoError(err)
+	relayer := crypto.PubkeyToAddress(relayerKey.PublicKey)
+	user := crypto.PubkeyToAddress(userKey.PublicKey)
+	recipient := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	subnetChain := newTestChain("subnet", relayer, user)
+	cChain := newTestChain("C-Chain", relayer, user)
+	liquidity := big.NewInt(1e18)
+	subnetBridge, err := Deploy(ctx, subnetChain, relayerKey, relayer, liquidity)
+	assert.NoError(err)
+	cChainBridge, err := Deploy(ctx, cChain, relayerKey, relayer, liquidity)
+	assert.NoError(err)
+
+	contract := bind.NewBoundContract(cChainBridge.Address, ABI, cChain.Backend, cChain.Backend, cChain.Backend)
+	out := []interface{}{}
+	assert.NoError(contract.Call(&bind.CallOpts{}, &out, "relayer"))
+	assert.Equal(relayer, out[0])
+	balance, err := cChain.Backend.(acceptingBackend).BalanceAt(ctx, cChainBridge.Address, nil)
+	assert.NoError(err)
+	assert.Equal(liquidity, balance)
+
+	// deposits must have a value and a recipient
+	_, err = Deposit(ctx, subnetChain, subnetBridge.Address, userKey, recipient, nil)
+	assert.Error(err)
+	_, err = Deposit(ctx, subnetChain, subnetBridge.Address, userKey, common.Address{}, big.NewInt(1))
+	assert.Error(err)
+
+	amount := big.NewInt(1000)
+	_, err = Deposit(ctx, subnetChain, subnetBridge.Address, userKey, recipient, amount)
+	assert.NoError(err)
+	_, err = Deposit(ctx, subnetChain, subnetBridge.Address, userKey, recipient, amount)
+	assert.NoError(err)
+
+	deployment := Deployment{Subnet: "test", Relayer: relayer, SubnetBridge: subnetBridge, CChainBridge: cChainBridge}
+	r := NewRelayer(subnetChain, cChain, &deployment, relayerKey)
+	transfers, err := r.Relay(ctx)
+	assert.NoError(err)
+	assert.Len(transfers, 2)
+	for i, transfer := range transfers {
+		assert.Equal("subnet", transfer.From)
+		assert.Equal("C-Chain", transfer.To)
+		assert.Equal(user, transfer.Sender)
+		assert.Equal(recipient, transfer.Recipient)
+		assert.Equal(amount, transfer.Amount)
+		assert.Equal(int64(i), transfer.Nonce.Int64())
+		assert.True(transfer.Released)
+	}
+	balance, err = cChain.Backend.(acceptingBackend).BalanceAt(ctx, recipient, nil)
+	assert.NoError(err)
+	assert.Equal(big.NewInt(2000), balance)
+
+	// a relayer starting over skips the released deposits
+	deployment.SubnetBridge = subnetBridge
+	transfers, err = NewRelayer(subnetChain, cChain, &deployment, relayerKey).Relay(ctx)
+	assert.NoError(err)
+	assert.Len(transfers, 2)
+	for _, transfer := range transfers {
+		assert.False(transfer.Released)
+	}
+	balance, err = cChain.Backend.(acceptingBackend).BalanceAt(ctx, recipient, nil)
+	assert.NoError(err)
+	assert.Equal(big.NewInt(2000), balance)
+
+	// only the relayer releases
+	opts, err := transactOpts(ctx, cChain, userKey)
+	assert.NoError(err)
+	opts.GasLimit = 100_000
+	tx, err := contract.Transact(opts, "release", user, amount, big.NewInt(5))
+	if assert.NoError(err) {
+		_, err = waitSuccess(ctx, cChain, tx)
+		assert.Error(err)
+	}
+
+	transfers, err = r.Relay(ctx)
+	assert.NoError(err)
+	assert.Empty(transfers)
+}
+
+func TestDeployment(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "bridge.json")
+	_, err := LoadDeployment(path)
+	assert.Error(err)
+
+	d := Deployment{
+		Subnet:       "test",
+		BlockchainID: "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM",
+		Relayer:      common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"),
+		SubnetBridge: DeployedBridge{Address: common.HexToAddress("0x1"), NextBlock: 3},
+		CChainBridge: DeployedBridge{Address: common.HexToAddress("0x2"), NextBlock: 7},
+	}
+	assert.NoError(WriteDeployment(path, d))
+	loaded, err := LoadDeployment(path)
+	assert.NoError(err)
+	assert.Equal(d, loaded)
+}
@@ -0,0 +1,239 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bridge prototypes the movement of the native tokens between two
+// EVM chains of a local network, such as a subnet and the C-Chain: a bridge
+// contract on each chain locks the deposits, and a relayer releases them
+// from the contract on the other chain.
+package bridge
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ava-labs/subnet-evm/core/vm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// contractABI is the ABI of the bridge contract. The contract is the
+// following Solidity one, assembled by hand in assembleBridge, so that the
+// CLI doesn't need a compiler:
+//
+//	contract NativeBridge {
+//	    address public relayer;
+//	    uint256 public depositCount;
+//	    mapping(uint256 => bool) public released;
+//
+//	    event Deposit(address indexed sender, address indexed recipient, uint256 amount, uint256 nonce);
+//	    event Release(address indexed recipient, uint256 amount, uint256 nonce);
+//
+//	    constructor(address _relayer) { relayer = _relayer; }
+//
+//	    // funds the releases
+//	    receive() external payable {}
+//
+//	    function deposit(address recipient) external payable {
+//	        require(msg.value > 0 && recipient != address(0));
+//	        emit Deposit(msg.sender, recipient, msg.value, depositCount);
+//	        depositCount++;
+//	    }
+//
+//	    function release(address recipient, uint256 amount, uint256 nonce) external {
+//	        require(msg.value == 0 && msg.sender == relayer && !released[nonce]);
+//	        released[nonce] = true;
+//	        emit Release(recipient, amount, nonce);
+//	        (bool ok, ) = recipient.call{value: amount}("");
+//	        require(ok);
+//	    }
+//	}
+const contractABI = `[
+	{"type":"constructor","inputs":[{"name":"_relayer","type":"address"}],"stateMutability":"nonpayable"},
+	{"type":"receive","stateMutability":"payable"},
+	{"type":"function","name":"relayer","inputs":[],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"depositCount","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"released","inputs":[{"name":"","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"view"},
+	{"type":"function","name":"deposit","inputs":[{"name":"recipient","type":"address"}],"outputs":[],"stateMutability":"payable"},
+	{"type":"function","name":"release","inputs":[{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"},{"name":"nonce","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Deposit","inputs":[{"name":"sender","type":"address","indexed":true},{"name":"recipient","type":"address","indexed":true},{"name":"amount","type":"uint256","indexed":false},{"name":"nonce","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Release","inputs":[{"name":"recipient","type":"address","indexed":true},{"name":"amount","type":"uint256","indexed":false},{"name":"nonce","type":"uint256","indexed":false}],"anonymous":false}
+]`
+
+// storage slots of the bridge contract
+const (
+	relayerSlot      = 0
+	depositCountSlot = 1
+	releasedSlot     = 2
+)
+
+var (
+	// ABI of the bridge contract
+	ABI = mustParseABI()
+	// Bytecode deploys the bridge contract, followed by the ABI encoded
+	// address of the relayer
+	Bytecode = mustAssembleBridge()
+)
+
+func mustParseABI() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func mustAssembleBridge() []byte {
+	code, err := assembleBridge()
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+// assembler builds EVM bytecode, with jumps to labels
+type assembler struct {
+	code   []byte
+	labels map[string]int
+	// jumps are the offsets of the PUSH2 operands of the jumps, by label
+	jumps map[int]string
+}
+
+func newAssembler() *assembler {
+	return &assembler{labels: map[string]int{}, jumps: map[int]string{}}
+}
+
+func (a *assembler) op(ops ...vm.OpCode) *assembler {
+	for _, op := range ops {
+		a.code = append(a.code, byte(op))
+	}
+	return a
+}
+
+// push pushes data, of 1 to 32 bytes
+func (a *assembler) push(data []byte) *assembler {
+	a.code = append(a.code, byte(vm.PUSH1)+byte(len(data)-1))
+	a.code = append(a.code, data...)
+	return a
+}
+
+func (a *assembler) pushByte(b byte) *assembler {
+	return a.push([]byte{b})
+}
+
+// pushLabel pushes the offset of label, as a PUSH2
+func (a *assembler) pushLabel(label string) *assembler {
+	a.code = append(a.code, byte(vm.PUSH2))
+	a.jumps[len(a.code)] = label
+	a.code = append(a.code, 0, 0)
+	return a
+}
+
+// label marks a jump destination
+func (a *assembler) label(label string) *assembler {
+	a.labels[label] = len(a.code)
+	return a.op(vm.JUMPDEST)
+}
+
+func (a *assembler) bytes() ([]byte, error) {
+	for offset, label := range a.jumps {
+		dest, ok := a.labels[label]
+		if !ok {
+			return nil, fmt.Errorf("unknown label %s", label)
+		}
+		a.code[offset], a.code[offset+1] = byte(dest>>8), byte(dest)
+	}
+	return a.code, nil
+}
+
+// selector returns the function selector of method of the ABI
+func selector(method string) []byte {
+	return ABI.Methods[method].ID
+}
+
+func assembleBridge() ([]byte, error) {
+	addressMask := bytes.Repeat([]byte{0xff}, common.AddressLength)
+	depositTopic := ABI.Events["Deposit"].ID.Bytes()
+	releaseTopic := ABI.Events["Release"].ID.Bytes()
+
+	runtime := newAssembler()
+	// plain transfers fund the contract
+	runtime.op(vm.CALLDATASIZE, vm.ISZERO).pushLabel("receive").op(vm.JUMPI)
+	runtime.pushByte(0).op(vm.CALLDATALOAD).pushByte(0xe0).op(vm.SHR)
+	for _, method := range []string{"deposit", "release", "relayer", "depositCount", "released"} {
+		runtime.op(vm.DUP1).push(selector(method)).op(vm.EQ).pushLabel(method).op(vm.JUMPI)
+	}
+	runtime.label("revert").pushByte(0).op(vm.DUP1, vm.REVERT)
+
+	runtime.label("receive").op(vm.STOP)
+
+	runtime.label("deposit").
+		op(vm.CALLVALUE, vm.ISZERO).pushLabel("revert").op(vm.JUMPI).
+		// recipient, kept on the stack for the log
+		pushByte(4).op(vm.CALLDATALOAD).push(addressMask).op(vm.AND).
+		op(vm.DUP1, vm.ISZERO).pushLabel("revert").op(vm.JUMPI).
+		op(vm.CALLVALUE).pushByte(0).op(vm.MSTORE).
+		pushByte(depositCountSlot).op(vm.SLOAD).pushByte(0x20).op(vm.MSTORE).
+		op(vm.CALLER).push(depositTopic).pushByte(0x40).pushByte(0).op(vm.LOG3).
+		pushByte(depositCountSlot).op(vm.SLOAD).pushByte(1).op(vm.ADD).pushByte(depositCountSlot).op(vm.SSTORE).
+		op(vm.STOP)
+
+	runtime.label("release").
+		op(vm.CALLVALUE).pushLabel("revert").op(vm.JUMPI).
+		op(vm.CALLER).pushByte(relayerSlot).op(vm.SLOAD).op(vm.EQ, vm.ISZERO).pushLabel("revert").op(vm.JUMPI).
+		// slot of released[nonce]
+		pushByte(0x44).op(vm.CALLDATALOAD).pushByte(0).op(vm.MSTORE).
+		pushByte(releasedSlot).pushByte(0x20).op(vm.MSTORE).
+		pushByte(0x40).pushByte(0).op(vm.KECCAK256).
+		op(vm.DUP1, vm.SLOAD).pushLabel("revert").op(vm.JUMPI).
+		pushByte(1).op(vm.SWAP1, vm.SSTORE).
+		pushByte(0x24).op(vm.CALLDATALOAD).pushByte(0).op(vm.MSTORE).
+		pushByte(0x44).op(vm.CALLDATALOAD).pushByte(0x20).op(vm.MSTORE).
+		// recipient, kept on the stack for the call
+		pushByte(4).op(vm.CALLDATALOAD).push(addressMask).op(vm.AND).
+		op(vm.DUP1).push(releaseTopic).pushByte(0x40).pushByte(0).op(vm.LOG2).
+		pushByte(0).pushByte(0).pushByte(0).pushByte(0).
+		pushByte(0x24).op(vm.CALLDATALOAD).
+		op(vm.DUP6, vm.GAS, vm.CALL).
+		op(vm.ISZERO).pushLabel("revert").op(vm.JUMPI).
+		op(vm.STOP)
+
+	runtime.label("relayer").
+		pushByte(relayerSlot).op(vm.SLOAD).pushByte(0).op(vm.MSTORE).
+		pushByte(0x20).pushByte(0).op(vm.RETURN)
+
+	runtime.label("depositCount").
+		pushByte(depositCountSlot).op(vm.SLOAD).pushByte(0).op(vm.MSTORE).
+		pushByte(0x20).pushByte(0).op(vm.RETURN)
+
+	runtime.label("released").
+		pushByte(4).op(vm.CALLDATALOAD).pushByte(0).op(vm.MSTORE).
+		pushByte(releasedSlot).pushByte(0x20).op(vm.MSTORE).
+		pushByte(0x40).pushByte(0).op(vm.KECCAK256).op(vm.SLOAD).pushByte(0).op(vm.MSTORE).
+		pushByte(0x20).pushByte(0).op(vm.RETURN)
+
+	runtimeCode, err := runtime.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// the constructor stores the relayer, appended to the code, and returns
+	// the runtime code, appended to its own
+	constructor := newAssembler().
+		pushByte(0x20).pushByte(0x20).op(vm.CODESIZE, vm.SUB).pushByte(0).op(vm.CODECOPY).
+		pushByte(0).op(vm.MLOAD).pushByte(relayerSlot).op(vm.SSTORE)
+	const constructorTailSize = 3 + 1 + 3 + 2 + 1 + 2 + 1
+	runtimeOffset := len(constructor.code) + constructorTailSize
+	constructor.
+		push([]byte{byte(len(runtimeCode) >> 8), byte(len(runtimeCode))}).op(vm.DUP1).
+		push([]byte{byte(runtimeOffset >> 8), byte(runtimeOffset)}).pushByte(0).op(vm.CODECOPY).
+		pushByte(0).op(vm.RETURN)
+	constructorCode, err := constructor.bytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(constructorCode) != runtimeOffset {
+		return nil, fmt.Errorf("constructor is %d bytes, expected %d", len(constructorCode), runtimeOffset)
+	}
+	return append(constructorCode, runtimeCode...), nil
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package bridge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Transfer is a deposit on a chain, released on the other one
+type Transfer struct {
+	From      string
+	To        string
+	Sender    common.Address
+	Recipient common.Address
+	Amount    *big.Int
+	Nonce     *big.Int
+	// Released is false when the deposit had been released before, e.g. by a
+	// relayer interrupted before it could record its progress
+	Released bool
+}
+
+// Relayer releases on each chain of a pair the deposits made in the bridge
+// of the other chain
+type Relayer struct {
+	chains  [2]Chain
+	bridges [2]*DeployedBridge
+	key     *ecdsa.PrivateKey
+}
+
+// NewRelayer returns a relayer between the bridges of the deployment on the
+// subnet chain and on the C-Chain, releasing with key. The progress of the
+// relayer is kept in deployment.
+func NewRelayer(subnetChain, cChain Chain, deployment *Deployment, key *ecdsa.PrivateKey) *Relayer {
+	return &Relayer{
+		chains:  [2]Chain{subnetChain, cChain},
+		bridges: [2]*DeployedBridge{&deployment.SubnetBridge, &deployment.CChainBridge},
+		key:     key,
+	}
+}
+
+// Relay releases the deposits accepted since the last call, in both
+// directions, and returns them
+func (r *Relayer) Relay(ctx context.Context) ([]Transfer, error) {
+	transfers := []Transfer{}
+	for from := range r.chains {
+		to := 1 - from
+		relayed, err := r.relay(ctx, from, to)
+		transfers = append(transfers, relayed...)
+		if err != nil {
+			return transfers, err
+		}
+	}
+	return transfers, nil
+}
+
+// Run relays every interval until ctx is done, calling onRelay after every
+// round that relayed deposits, or failed. It returns once ctx is done.
+func (r *Relayer) Run(ctx context.Context, interval time.Duration, onRelay func([]Transfer, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		transfers, err := r.Relay(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if len(transfers) > 0 || err != nil {
+			onRelay(transfers, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay releases on chain to the deposits accepted on chain from
+func (r *Relayer) relay(ctx context.Context, from, to int) ([]Transfer, error) {
+	src, dst := r.chains[from], r.chains[to]
+	srcBridge, dstBridge := r.bridges[from], r.bridges[to]
+	head, err := src.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting the last block of %s: %w", src.Name, err)
+	}
+	last := head.Number.Uint64()
+	if last < srcBridge.NextBlock {
+		return nil, nil
+	}
+	logs, err := src.Backend.FilterLogs(ctx, interfaces.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(srcBridge.NextBlock),
+		ToBlock:   new(big.Int).SetUint64(last),
+		Addresses: []common.Address{srcBridge.Address},
+		Topics:    [][]common.Hash{{ABI.Events["Deposit"].ID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed getting the deposits on %s: %w", src.Name, err)
+	}
+
+	dstContract := bind.NewBoundContract(dstBridge.Address, ABI, dst.Backend, dst.Backend, dst.Backend)
+	transfers := []Transfer{}
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) != 3 {
+			continue
+		}
+		values, err := ABI.Unpack("Deposit", log.Data)
+		if err != nil {
+			return transfers, fmt.Errorf("invalid deposit on %s in transaction %s: %w", src.Name, log.TxHash, err)
+		}
+		transfer := Transfer{
+			From:      src.Name,
+			To:        dst.Name,
+			Sender:    common.BytesToAddress(log.Topics[1].Bytes()),
+			Recipient: common.BytesToAddress(log.Topics[2].Bytes()),
+			Amount:    values[0].(*big.Int),
+			Nonce:     values[1].(*big.Int),
+		}
+		released, err := r.isReleased(ctx, dstContract, transfer.Nonce)
+		if err != nil {
+			return transfers, fmt.Errorf("failed checking deposit %s of %s on %s: %w", transfer.Nonce, src.Name, dst.Name, err)
+		}
+		if !released {
+			if err := r.release(ctx, dst, dstContract, transfer); err != nil {
+				return transfers, fmt.Errorf("failed releasing deposit %s of %s on %s: %w", transfer.Nonce, src.Name, dst.Name, err)
+			}
+			transfer.Released = true
+		}
+		transfers = append(transfers, transfer)
+	}
+	srcBridge.NextBlock = last + 1
+	return transfers, nil
+}
+
+func (r *Relayer) isReleased(ctx context.Context, contract *bind.BoundContract, nonce *big.Int) (bool, error) {
+	out := []interface{}{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "released", nonce); err != nil {
+		return false, err
+	}
+	return out[0].(bool), nil
+}
+
+func (r *Relayer) release(ctx context.Context, chain Chain, contract *bind.BoundContract, transfer Transfer) error {
+	opts, err := transactOpts(ctx, chain, r.key)
+	if err != nil {
+		return err
+	}
+	tx, err := contract.Transact(opts, "release", transfer.Recipient, transfer.Amount, transfer.Nonce)
+	if err != nil {
+		return err
+	}
+	_, err = waitSuccess(ctx, chain, tx)
+	return err
+}
@@ -5,18 +5,111 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/spf13/viper"
 )
 
-type Config struct{}
+const (
+	// endpointsKey holds the API endpoints of the public networks in the
+	// config file, by lower case network name
+	endpointsKey = "endpoints"
+	// readOnlyKey turns on the read-only mode in the config file
+	readOnlyKey = "read-only"
+	// keyUnlockTTLKey sets how long unlocked keys are held in the config file
+	keyUnlockTTLKey = "key-unlock-ttl"
+	// nodeConfigKey holds the global node config of the local networks in
+	// the config file
+	nodeConfigKey = "node-config"
+	// nodeConfigsKey holds the node configs overridden per node of the local
+	// networks in the config file, by node name
+	nodeConfigsKey = "node-configs"
+	// nodeProfileKey selects the node profile of the local networks in the
+	// config file
+	nodeProfileKey = "node-profile"
+	// avalancheGoPathKey sets the avalanchego binary the local networks run
+	// in the config file, instead of a managed release
+	avalancheGoPathKey = "avalanchego-path"
+	// webhooksKey holds the webhooks receiving the events of the CLI in the
+	// config file
+	webhooksKey = "webhooks"
+	// publicAPIKey holds how the requests to the public API endpoints are
+	// retried and rate limited in the config file
+	publicAPIKey = "public-api"
+	// tlsKey holds the CA bundle, the client certificate and the minimum
+	// TLS version of the outbound connections in the config file
+	tlsKey = "tls"
+	// meteredKey tells in the config file that the connection is metered,
+	// so that large downloads are confirmed first
+	meteredKey = "metered"
+	// stateRemoteKey holds the remote state push and state pull sync the
+	// subnets with in the config file, unless the project sets one
+	stateRemoteKey = "state-remote"
+	// httpHostKey is the node config setting the interface the API of the
+	// nodes listens on
+	httpHostKey = "http-host"
+
+	// DefaultNodeProfile runs the nodes of the local networks with the
+	// defaults of avalanchego
+	DefaultNodeProfile = "default"
+	// FastDevNodeProfile tunes the consensus and the networking of the nodes
+	// of the local networks, so that they come up and accept transactions
+	// in seconds
+	FastDevNodeProfile = "fast-dev"
+)
+
+// nodeProfiles holds the node settings of the node profiles. The fast-dev
+// settings are fit for the five nodes of a local network: the production
+// sample sizes and commit thresholds are sized for thousands of validators,
+// and its gossip and health check frequencies for the latency of the internet.
+var nodeProfiles = map[string]map[string]interface{}{
+	DefaultNodeProfile: {},
+	FastDevNodeProfile: {
+		"snow-sample-size":                   2,
+		"snow-quorum-size":                   2,
+		"snow-virtuous-commit-threshold":     5,
+		"snow-rogue-commit-threshold":        10,
+		"consensus-gossip-frequency":         "250ms",
+		"network-peer-list-gossip-frequency": "250ms",
+		"network-max-reconnect-delay":        "1s",
+		"health-check-frequency":             "2s",
+	},
+}
+
+type Config struct {
+	project *ProjectConfig
+	// binding is the network, key and endpoints of the profile in use
+	binding *ProfileBinding
+	// endpoint overrides the API endpoint of the public networks
+	endpoint string
+	// readOnly refuses the operations mutating any state
+	readOnly bool
+	// allowEwoq lets the operations on the public networks involve the
+	// publicly known ewoq key
+	allowEwoq bool
+	// inContainer has the nodes listen on all the interfaces by default
+	inContainer bool
+	// nodeProfile overrides the node profile of the config file
+	nodeProfile string
+	// avalancheGoPath overrides the avalanchego binary of the config file
+	avalancheGoPath string
+}
 
 func New() *Config {
 	return &Config{}
 }
 
+// LoadNodeConfig returns the global node config of the local networks. In a
+// container, see SetInContainer, the nodes listen on all the interfaces
+// unless configured otherwise, so that their ports can be published to the
+// host.
 func (c *Config) LoadNodeConfig() (string, error) {
-	globalConfigs := viper.GetStringMap("node-config")
+	globalConfigs := c.GlobalNodeConfig()
 	if len(globalConfigs) == 0 {
 		return "", nil
 	}
@@ -26,3 +119,276 @@ func (c *Config) LoadNodeConfig() (string, error) {
 	}
 	return string(configStr), nil
 }
+
+// GlobalNodeConfig returns the settings of the global node config of the
+// local networks, which LoadNodeConfig marshals: the ones of the node
+// profile, overridden by the node-config ones
+func (c *Config) GlobalNodeConfig() map[string]interface{} {
+	globalConfigs := map[string]interface{}{}
+	for k, v := range nodeProfiles[c.NodeProfile()] {
+		globalConfigs[k] = v
+	}
+	for k, v := range viper.GetStringMap(nodeConfigKey) {
+		globalConfigs[k] = v
+	}
+	if _, ok := globalConfigs[httpHostKey]; !ok && c.inContainer {
+		globalConfigs[httpHostKey] = "0.0.0.0"
+	}
+	return globalConfigs
+}
+
+// NodeConfigs returns the settings of the node config overridden for some
+// nodes of the local networks, by node name, e.g. to have a single node with
+// debug logs
+func (c *Config) NodeConfigs() (map[string]map[string]interface{}, error) {
+	nodeConfigs := map[string]map[string]interface{}{}
+	for name, settings := range viper.GetStringMap(nodeConfigsKey) {
+		settingsMap, ok := settings.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s of node %s must be an object of node settings", nodeConfigsKey, name)
+		}
+		nodeConfigs[name] = settingsMap
+	}
+	return nodeConfigs, nil
+}
+
+// NodeProfileNames returns the names of the node profiles
+func NodeProfileNames() []string {
+	return []string{DefaultNodeProfile, FastDevNodeProfile}
+}
+
+// SetNodeProfile overrides the node profile of the local networks, e.g. with
+// the one given with --node-profile
+func (c *Config) SetNodeProfile(profile string) {
+	c.nodeProfile = profile
+}
+
+// NodeProfile returns the node profile of the local networks: the override
+// if set, or the one of the config file, or DefaultNodeProfile
+func (c *Config) NodeProfile() string {
+	if c.nodeProfile != "" {
+		return c.nodeProfile
+	}
+	if profile := viper.GetString(nodeProfileKey); profile != "" {
+		return profile
+	}
+	return DefaultNodeProfile
+}
+
+// CheckNodeProfile checks the node profile of the local networks is known
+func (c *Config) CheckNodeProfile() error {
+	profile := c.NodeProfile()
+	if _, ok := nodeProfiles[profile]; !ok {
+		return fmt.Errorf("unknown node profile %q, must be one of %s", profile, strings.Join(NodeProfileNames(), ", "))
+	}
+	return nil
+}
+
+// SetAvalancheGoPath overrides the avalanchego binary the local networks
+// run, e.g. with the one given with --avalanchego-path
+func (c *Config) SetAvalancheGoPath(path string) {
+	c.avalancheGoPath = path
+}
+
+// AvalancheGoPath returns the avalanchego binary the local networks run: the
+// override if set, or the one of the config file. Returns an empty string if
+// neither is set, for the managed release to be used.
+func (c *Config) AvalancheGoPath() string {
+	if c != nil && c.avalancheGoPath != "" {
+		return c.avalancheGoPath
+	}
+	return viper.GetString(avalancheGoPathKey)
+}
+
+// SetInContainer tells whether the CLI runs in a container, such as a
+// codespace or a devcontainer
+func (c *Config) SetInContainer(inContainer bool) {
+	c.inContainer = inContainer
+}
+
+// SetEndpoint overrides the API endpoint of the public networks, e.g. with
+// the one given with --endpoint
+func (c *Config) SetEndpoint(endpoint string) {
+	c.endpoint = endpoint
+}
+
+// APIEndpoints returns the API endpoints to choose from for the public
+// network: the override if set, or the ones the profile in use is bound to
+// for the network, or the ones configured for the network in the config
+// file, either a single endpoint or a list. Returns none if none is set.
+func (c *Config) APIEndpoints(network string) []string {
+	if c.endpoint != "" {
+		return []string{c.endpoint}
+	}
+	if b := c.binding; b != nil && len(b.Endpoints) > 0 && strings.EqualFold(b.Network, network) {
+		return append([]string{}, b.Endpoints...)
+	}
+	endpoints := []string{}
+	switch configured := viper.Get(endpointsKey + "." + strings.ToLower(network)).(type) {
+	case string:
+		if configured != "" {
+			endpoints = append(endpoints, configured)
+		}
+	case []interface{}:
+		for _, endpoint := range configured {
+			if s, ok := endpoint.(string); ok && s != "" {
+				endpoints = append(endpoints, s)
+			}
+		}
+	case []string:
+		endpoints = append(endpoints, configured...)
+	}
+	return endpoints
+}
+
+// Webhook is an URL the events of the CLI are posted to, signed with its
+// secret
+type Webhook struct {
+	URL    string `mapstructure:"url"`
+	Secret string `mapstructure:"secret"`
+	// Events are the types of the events posted, all of them if empty
+	Events []string `mapstructure:"events"`
+}
+
+// Webhooks returns the webhooks of the config file
+func (c *Config) Webhooks() ([]Webhook, error) {
+	webhooks := []Webhook{}
+	if err := viper.UnmarshalKey(webhooksKey, &webhooks); err != nil {
+		return nil, fmt.Errorf("%s must be a list of objects with an url, a secret and events: %w", webhooksKey, err)
+	}
+	for i, webhook := range webhooks {
+		if webhook.URL == "" {
+			return nil, fmt.Errorf("webhook %d of %s has no url", i+1, webhooksKey)
+		}
+		if webhook.Secret == "" {
+			return nil, fmt.Errorf("webhook %s has no secret to sign the events with", webhook.URL)
+		}
+	}
+	return webhooks, nil
+}
+
+// PublicAPILimits sets how the requests to the API endpoints of the public
+// networks are retried and rate limited
+type PublicAPILimits struct {
+	// Retries is how many times a request the endpoint is too busy for is
+	// retried, 0 to never retry
+	Retries int
+	// RetryBackoff is how long the first retry waits for, doubled for each
+	// of the next ones
+	RetryBackoff time.Duration
+	// RequestsPerSecond caps the rate of the requests, 0 for no cap
+	RequestsPerSecond float64
+}
+
+// PublicAPILimits returns the limits of the requests to the API endpoints of
+// the public networks, from the config file or else the defaults
+func (c *Config) PublicAPILimits() (PublicAPILimits, error) {
+	limits := PublicAPILimits{
+		Retries:           constants.DefaultPublicAPIRetries,
+		RetryBackoff:      constants.DefaultPublicAPIRetryBackoff,
+		RequestsPerSecond: constants.DefaultPublicAPIRequestsPerSecond,
+	}
+	if viper.IsSet(publicAPIKey + ".retries") {
+		limits.Retries = viper.GetInt(publicAPIKey + ".retries")
+	}
+	if viper.IsSet(publicAPIKey + ".retry-backoff") {
+		limits.RetryBackoff = viper.GetDuration(publicAPIKey + ".retry-backoff")
+	}
+	if viper.IsSet(publicAPIKey + ".requests-per-second") {
+		limits.RequestsPerSecond = viper.GetFloat64(publicAPIKey + ".requests-per-second")
+	}
+	if limits.Retries < 0 || limits.RetryBackoff < 0 || limits.RequestsPerSecond < 0 {
+		return limits, fmt.Errorf("the retries, retry-backoff and requests-per-second of %s can't be negative", publicAPIKey)
+	}
+	return limits, nil
+}
+
+// TLSSettings are the TLS settings of the outbound HTTPS connections, e.g.
+// to reach the API endpoints through the private gateway of an enterprise
+type TLSSettings struct {
+	// CABundle is a PEM file of the certificate authorities trusted on top
+	// of the ones of the system
+	CABundle string
+	// ClientCert and ClientKey are the PEM files of the certificate the CLI
+	// authenticates with, for mutual TLS
+	ClientCert string
+	ClientKey  string
+	// MinVersion is the minimum TLS version, 1.2 or 1.3, the default of Go
+	// if empty
+	MinVersion string
+}
+
+// IsSet returns true if any setting differs from the defaults
+func (s TLSSettings) IsSet() bool {
+	return s != TLSSettings{}
+}
+
+// TLSSettings returns the TLS settings of the outbound connections from the
+// config file
+func (c *Config) TLSSettings() (TLSSettings, error) {
+	settings := TLSSettings{
+		CABundle:   viper.GetString(tlsKey + ".ca-bundle"),
+		ClientCert: viper.GetString(tlsKey + ".client-cert"),
+		ClientKey:  viper.GetString(tlsKey + ".client-key"),
+		MinVersion: viper.GetString(tlsKey + ".min-version"),
+	}
+	if (settings.ClientCert == "") != (settings.ClientKey == "") {
+		return settings, fmt.Errorf("both the client-cert and the client-key of %s must be set", tlsKey)
+	}
+	switch settings.MinVersion {
+	case "", "1.2", "1.3":
+	default:
+		return settings, fmt.Errorf("invalid min-version %q of %s, must be 1.2 or 1.3", settings.MinVersion, tlsKey)
+	}
+	return settings, nil
+}
+
+// SetReadOnly turns on the read-only mode, e.g. with --read-only
+func (c *Config) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// ReadOnly returns true if the operations mutating any state must be
+// refused, either from --read-only or from the config file
+func (c *Config) ReadOnly() bool {
+	return c.readOnly || viper.GetBool(readOnlyKey)
+}
+
+// SetAllowEwoq lets the operations on Fuji and Mainnet involve the ewoq key,
+// e.g. with --i-know-what-im-doing
+func (c *Config) SetAllowEwoq(allowEwoq bool) {
+	c.allowEwoq = allowEwoq
+}
+
+// AllowEwoq returns true if the operations on Fuji and Mainnet involving the
+// publicly known ewoq key are allowed
+func (c *Config) AllowEwoq() bool {
+	return c.allowEwoq
+}
+
+// Metered returns true if the connection is metered, from the config file
+// or from AVALANCHE_METERED
+func (c *Config) Metered() bool {
+	if metered, err := strconv.ParseBool(os.Getenv(constants.EnvVarPrefix + "METERED")); err == nil {
+		return metered
+	}
+	return viper.GetBool(meteredKey)
+}
+
+// StateRemote returns the remote the subnets are pushed to and pulled from,
+// from the project configuration or else from the config file
+func (c *Config) StateRemote() string {
+	if project := c.GetProject(); project != nil && project.StateRemote != "" {
+		return project.StateRemote
+	}
+	return viper.GetString(stateRemoteKey)
+}
+
+// KeyUnlockTTL returns how long an unlocked encrypted key is held for the
+// following commands, 0 to prompt for the passphrase on every command
+func (c *Config) KeyUnlockTTL() time.Duration {
+	if !viper.IsSet(keyUnlockTTLKey) {
+		return constants.DefaultKeyUnlockTTL
+	}
+	return viper.GetDuration(keyUnlockTTLKey)
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -52,6 +54,157 @@ func Test_LoadNodeConfig_NoConfig(t *testing.T) {
 	assert.Empty(config)
 }
 
+func Test_LoadNodeConfig_InContainer(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+	cf.SetInContainer(true)
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+
+	config, err := cf.LoadNodeConfig()
+	assert.NoError(err)
+	assert.Equal(`{"http-host":"0.0.0.0"}`, config)
+
+	// an http-host of the user is kept
+	viper.Set("node-config", map[string]interface{}{"http-host": "127.0.0.1"})
+	defer viper.Set("node-config", nil)
+	config, err = cf.LoadNodeConfig()
+	assert.NoError(err)
+	assert.Equal(`{"http-host":"127.0.0.1"}`, config)
+}
+
+func TestNodeConfigs(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	nodeConfigs, err := cf.NodeConfigs()
+	assert.NoError(err)
+	assert.Empty(nodeConfigs)
+
+	err = useViper("node-configs-config")
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{"log-level": "info"}, cf.GlobalNodeConfig())
+	nodeConfigs, err = cf.NodeConfigs()
+	assert.NoError(err)
+	assert.Equal(map[string]map[string]interface{}{
+		"node1": {"log-level": "debug"},
+		"node3": {"index-enabled": true},
+	}, nodeConfigs)
+
+	viper.Set("node-configs", map[string]interface{}{"node1": "debug"})
+	_, err = cf.NodeConfigs()
+	assert.Error(err)
+}
+
+func TestNodeProfile(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	assert.Equal(DefaultNodeProfile, cf.NodeProfile())
+	assert.NoError(cf.CheckNodeProfile())
+	assert.Empty(cf.GlobalNodeConfig())
+
+	// the node-config settings override the ones of the profile
+	err = useViper("node-profile-config")
+	assert.NoError(err)
+	assert.Equal(FastDevNodeProfile, cf.NodeProfile())
+	assert.NoError(cf.CheckNodeProfile())
+	globalConfig := cf.GlobalNodeConfig()
+	assert.Equal(2, globalConfig["snow-sample-size"])
+	assert.Equal("5s", globalConfig["health-check-frequency"])
+
+	cf.SetNodeProfile(DefaultNodeProfile)
+	assert.Equal(map[string]interface{}{"health-check-frequency": "5s"}, cf.GlobalNodeConfig())
+
+	cf.SetNodeProfile("fastest")
+	assert.Error(cf.CheckNodeProfile())
+}
+
+func TestAPIEndpoints(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	assert.Empty(cf.APIEndpoints("Fuji"))
+
+	err = useViper("endpoints-config")
+	assert.NoError(err)
+	assert.Equal([]string{"https://fuji.example.com"}, cf.APIEndpoints("Fuji"))
+	assert.Empty(cf.APIEndpoints("Mainnet"))
+
+	err = useViper("endpoints-list-config")
+	assert.NoError(err)
+	assert.Equal([]string{"https://fuji-1.example.com", "https://fuji-2.example.com"}, cf.APIEndpoints("Fuji"))
+
+	cf.SetEndpoint("http://127.0.0.1:9650")
+	assert.Equal([]string{"http://127.0.0.1:9650"}, cf.APIEndpoints("Fuji"))
+	assert.Equal([]string{"http://127.0.0.1:9650"}, cf.APIEndpoints("Mainnet"))
+}
+
+func TestReadOnly(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	assert.False(cf.ReadOnly())
+
+	err = useViper("read-only-config")
+	assert.NoError(err)
+	assert.True(cf.ReadOnly())
+
+	err = useViper("empty-config")
+	assert.NoError(err)
+	cf.SetReadOnly(true)
+	assert.True(cf.ReadOnly())
+}
+
+func TestAvalancheGoPath(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	assert.Empty(cf.AvalancheGoPath())
+
+	err = useViper("avalanchego-path-config")
+	assert.NoError(err)
+	assert.Equal("/usr/local/bin/avalanchego", cf.AvalancheGoPath())
+
+	cf.SetAvalancheGoPath("/tmp/avalanchego/build/avalanchego")
+	assert.Equal("/tmp/avalanchego/build/avalanchego", cf.AvalancheGoPath())
+}
+
+func TestWebhooks(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	webhooks, err := cf.Webhooks()
+	assert.NoError(err)
+	assert.Empty(webhooks)
+
+	err = useViper("webhooks-config")
+	assert.NoError(err)
+	webhooks, err = cf.Webhooks()
+	assert.NoError(err)
+	assert.Equal([]Webhook{
+		{URL: "https://hooks.example.com/all", Secret: "s3cret"},
+		{URL: "https://hooks.example.com/failures", Secret: "0ther", Events: []string{"deploy.failed"}},
+	}, webhooks)
+
+	viper.Set(webhooksKey, []interface{}{map[string]interface{}{"url": "https://hooks.example.com"}})
+	_, err = cf.Webhooks()
+	assert.ErrorContains(err, "no secret")
+}
+
 func useViper(configName string) error {
 	viper.Reset()
 	viper.SetConfigName(configName)
@@ -60,3 +213,28 @@ func useViper(configName string) error {
 
 	return viper.ReadInConfig()
 }
+
+func TestPublicAPILimits(t *testing.T) {
+	assert := assert.New(t)
+	cf := New()
+
+	err := useViper("empty-config")
+	assert.NoError(err)
+	limits, err := cf.PublicAPILimits()
+	assert.NoError(err)
+	assert.Equal(PublicAPILimits{
+		Retries:           constants.DefaultPublicAPIRetries,
+		RetryBackoff:      constants.DefaultPublicAPIRetryBackoff,
+		RequestsPerSecond: constants.DefaultPublicAPIRequestsPerSecond,
+	}, limits)
+
+	err = useViper("public-api-config")
+	assert.NoError(err)
+	limits, err = cf.PublicAPILimits()
+	assert.NoError(err)
+	assert.Equal(PublicAPILimits{Retries: 2, RetryBackoff: 500 * time.Millisecond, RequestsPerSecond: 2.5}, limits)
+
+	viper.Set(publicAPIKey+".retries", -1)
+	_, err = cf.PublicAPILimits()
+	assert.Error(err)
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// CheckProfileName returns an error if name can't name a profile, whose
+// files are kept in a dir of that name
+func CheckProfileName(name string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: only letters, digits, '-' and '_' are allowed", name)
+	}
+	return nil
+}
+
+// ProfileBinding binds a profile to a network, and optionally to the key
+// signing its transactions and to the API endpoints reaching it, so that the
+// commands run with the profile can't use another one by mistake, such as
+// signing a mainnet transaction with a test key
+type ProfileBinding struct {
+	// Name is the name of the profile
+	Name string `json:"-"`
+	// Network is the network of the profile (local, fuji, mainnet)
+	Network string `json:"network"`
+	// Key is the name of the key signing the transactions of the profile
+	Key string `json:"key,omitempty"`
+	// Endpoints are the API endpoints of the network, for fuji and mainnet
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// Validate checks the network of the binding is known, and that its endpoints
+// are URLs of a public network
+func (b *ProfileBinding) Validate() error {
+	switch strings.ToLower(b.Network) {
+	case "local", "fuji", "mainnet":
+	default:
+		return fmt.Errorf("network %q must be one of local, fuji, mainnet", b.Network)
+	}
+	if len(b.Endpoints) > 0 && strings.EqualFold(b.Network, "local") {
+		return errors.New("the endpoints of the local network are the ones of its nodes, and can't be bound")
+	}
+	for _, endpoint := range b.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint %q must be an http or https URL", endpoint)
+		}
+	}
+	return nil
+}
+
+// LoadProfileBinding reads the binding of the profile name at path, or
+// returns nil if the profile is not bound
+func LoadProfileBinding(name string, path string) (*ProfileBinding, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading the binding of profile %s: %w", name, err)
+	}
+	b := &ProfileBinding{}
+	if err := json.Unmarshal(content, b); err != nil {
+		return nil, fmt.Errorf("failed parsing the binding of profile %s: %w", name, err)
+	}
+	b.Name = name
+	if err := b.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid binding of profile %s: %w", name, err)
+	}
+	return b, nil
+}
+
+// SaveProfileBinding writes the binding b to path
+func SaveProfileBinding(path string, b *ProfileBinding) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
+// SetProfileBinding sets the binding of the profile in use, if any
+func (c *Config) SetProfileBinding(b *ProfileBinding) {
+	c.binding = b
+}
+
+// GetProfileBinding returns the binding of the profile in use, or nil if it
+// is not bound
+func (c *Config) GetProfileBinding() *ProfileBinding {
+	if c == nil {
+		return nil
+	}
+	return c.binding
+}
+
+// BoundKey returns the key the profile in use is bound to, if any
+func (c *Config) BoundKey() string {
+	if b := c.GetProfileBinding(); b != nil {
+		return b.Key
+	}
+	return ""
+}
+
+// CheckProfileBinding returns an error if network or keyName, when given,
+// are not the ones the profile in use is bound to. keyName is compared once
+// its project alias is resolved.
+func (c *Config) CheckProfileBinding(network string, keyName string) error {
+	b := c.GetProfileBinding()
+	if b == nil {
+		return nil
+	}
+	if network != "" && !strings.EqualFold(network, b.Network) {
+		return fmt.Errorf("profile %s is bound to %s, not to %s: run without --profile, or with a profile of %s", b.Name, b.Network, network, network)
+	}
+	if keyName != "" && b.Key != "" && c.ResolveKeyAlias(keyName) != c.ResolveKeyAlias(b.Key) {
+		return fmt.Errorf("profile %s signs with key %s, not with %s", b.Name, b.Key, keyName)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileBinding(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "prod", "profile.json")
+	binding, err := LoadProfileBinding("prod", path)
+	assert.NoError(err)
+	assert.Nil(binding)
+
+	err = SaveProfileBinding(path, &ProfileBinding{
+		Network:   "mainnet",
+		Key:       "ops",
+		Endpoints: []string{"https://mainnet.example.com"},
+	})
+	assert.NoError(err)
+	binding, err = LoadProfileBinding("prod", path)
+	assert.NoError(err)
+	assert.Equal("prod", binding.Name)
+
+	cf := New()
+	cf.SetProject(&ProjectConfig{Network: "fuji", Keys: map[string]string{"ops": "ops-ledger"}})
+	assert.Equal("fuji", cf.DefaultNetwork())
+	assert.Empty(cf.BoundKey())
+	assert.NoError(cf.CheckProfileBinding("fuji", "other"))
+
+	cf.SetProfileBinding(binding)
+	assert.Equal("mainnet", cf.DefaultNetwork())
+	assert.Equal("ops", cf.BoundKey())
+	assert.Equal([]string{"https://mainnet.example.com"}, cf.APIEndpoints("Mainnet"))
+	assert.Empty(cf.APIEndpoints("Fuji"))
+	cf.SetEndpoint("https://other.example.com")
+	assert.Equal([]string{"https://other.example.com"}, cf.APIEndpoints("Mainnet"))
+
+	assert.NoError(cf.CheckProfileBinding("mainnet", "ops"))
+	// keys are compared once their alias is resolved
+	assert.NoError(cf.CheckProfileBinding("mainnet", "ops-ledger"))
+	assert.NoError(cf.CheckProfileBinding("", ""))
+	assert.ErrorContains(cf.CheckProfileBinding("fuji", ""), "profile prod is bound to mainnet, not to fuji")
+	assert.ErrorContains(cf.CheckProfileBinding("mainnet", "test"), "profile prod signs with key ops, not with test")
+}
+
+func TestProfileBindingValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&ProfileBinding{Network: "local"}).Validate())
+	assert.ErrorContains((&ProfileBinding{Network: "devnet"}).Validate(), "must be one of local, fuji, mainnet")
+	assert.ErrorContains((&ProfileBinding{Network: "local", Endpoints: []string{"http://127.0.0.1:9650"}}).Validate(), "can't be bound")
+	assert.ErrorContains((&ProfileBinding{Network: "fuji", Endpoints: []string{"api.avax-test.network"}}).Validate(), "must be an http or https URL")
+
+	assert.NoError(CheckProfileName("prod_1"))
+	assert.ErrorContains(CheckProfileName("../prod"), "invalid profile name")
+}
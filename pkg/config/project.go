@@ -0,0 +1,264 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/spf13/viper"
+)
+
+// ProjectConfig is the configuration of a project, discovered from the
+// working directory, which pins the behavior of the tool for that project
+type ProjectConfig struct {
+	// Path is the file the configuration was loaded from
+	Path string `mapstructure:"-"`
+	// Subnets are the subnets of the project, the first one is used if
+	// a command requiring a subnet name is called without one
+	Subnets []string `mapstructure:"subnets"`
+	// Network is the default network to deploy to
+	Network string `mapstructure:"network"`
+	// Profile is the local network profile of the project
+	Profile string `mapstructure:"profile"`
+	// Versions pins the versions of the binaries to use for local networks
+	Versions ProjectVersions `mapstructure:"versions"`
+	// APINodes is the number of nodes added to the local networks which
+	// track the deployed subnets without validating them, as the RPC nodes
+	// dapps use in production
+	APINodes int `mapstructure:"api-nodes"`
+	// Keys maps aliases to the names of stored keys
+	Keys map[string]string `mapstructure:"keys"`
+	// GenesisVars are the values of the variables of genesis templates,
+	// per network (local, fuji, mainnet)
+	GenesisVars map[string]map[string]string `mapstructure:"genesis-vars"`
+	// Environments are the stages a subnet is deployed to in turn, e.g.
+	// dev, staging and prod, by name
+	Environments map[string]ProjectEnvironment `mapstructure:"environments"`
+	// StateRemote is where the team of the project shares the definitions
+	// of its subnets with state push and state pull
+	StateRemote string `mapstructure:"state-remote"`
+}
+
+// ProjectEnvironment is a stage of the deployment of the subnets of a project
+type ProjectEnvironment struct {
+	// Network the environment deploys to (local, fuji, mainnet), which is
+	// not the network of any other environment
+	Network string `mapstructure:"network"`
+	// Key is the key deploying to the environment
+	Key string `mapstructure:"key"`
+	// GenesisVars are the values of the variables of genesis templates for
+	// the environment, overriding the ones of its network
+	GenesisVars map[string]string `mapstructure:"genesis-vars"`
+}
+
+type ProjectVersions struct {
+	AvalancheGo string `mapstructure:"avalanchego"`
+	SubnetEVM   string `mapstructure:"subnet-evm"`
+	// Nodes pins another avalanchego version for some nodes of the local
+	// networks, by node name, e.g. to rehearse a rolling upgrade
+	Nodes map[string]string `mapstructure:"nodes"`
+}
+
+// FindProjectConfig looks for the project configuration file in dir and
+// all its parents, and returns its path or an empty string if there is none
+func FindProjectConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, constants.ProjectConfigFileName)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig reads the project configuration at path
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed reading project config %s: %w", path, err)
+	}
+	project := &ProjectConfig{}
+	if err := v.Unmarshal(project); err != nil {
+		return nil, fmt.Errorf("failed parsing project config %s: %w", path, err)
+	}
+	project.Path = path
+	if err := project.checkEnvironments(); err != nil {
+		return nil, fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+	if err := project.checkNodeVersions(); err != nil {
+		return nil, fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+	if project.APINodes < 0 {
+		return nil, fmt.Errorf("invalid project config %s: api-nodes is negative", path)
+	}
+	return project, nil
+}
+
+// checkEnvironments checks that each environment deploys to its own network,
+// as a subnet is deployed once per network
+func (p *ProjectConfig) checkEnvironments() error {
+	names := make([]string, 0, len(p.Environments))
+	for name := range p.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	byNetwork := map[string]string{}
+	for _, name := range names {
+		network := strings.ToLower(p.Environments[name].Network)
+		switch network {
+		case "local", "fuji", "mainnet":
+		default:
+			return fmt.Errorf("environment %s has network %q, must be one of local, fuji, mainnet", name, p.Environments[name].Network)
+		}
+		if other, ok := byNetwork[network]; ok {
+			return fmt.Errorf("environments %s and %s both deploy to %s", other, name, network)
+		}
+		byNetwork[network] = name
+	}
+	return nil
+}
+
+// checkNodeVersions checks that the versions pinned per node are releases
+func (p *ProjectConfig) checkNodeVersions() error {
+	for name, version := range p.Versions.Nodes {
+		if !strings.HasPrefix(version, "v") {
+			return fmt.Errorf("node %s has avalanchego version %q, must be a release such as v1.7.13", name, version)
+		}
+	}
+	return nil
+}
+
+// SetProject sets the project configuration to be used
+func (c *Config) SetProject(project *ProjectConfig) {
+	c.project = project
+}
+
+// GetProject returns the project configuration, or nil if there is none
+func (c *Config) GetProject() *ProjectConfig {
+	if c == nil {
+		return nil
+	}
+	return c.project
+}
+
+// DefaultSubnet returns the first subnet pinned by the project, if any
+func (c *Config) DefaultSubnet() (string, bool) {
+	project := c.GetProject()
+	if project == nil || len(project.Subnets) == 0 {
+		return "", false
+	}
+	return project.Subnets[0], true
+}
+
+// DefaultNetwork returns the network to deploy to the profile in use is bound
+// to, or else the one pinned by the project, if any
+func (c *Config) DefaultNetwork() string {
+	if b := c.GetProfileBinding(); b != nil && b.Network != "" {
+		return b.Network
+	}
+	if project := c.GetProject(); project != nil {
+		return project.Network
+	}
+	return ""
+}
+
+// ResolveKeyAlias returns the name of the key the alias stands for
+// in the project, or the name itself if it is not an alias
+func (c *Config) ResolveKeyAlias(name string) string {
+	if project := c.GetProject(); project != nil {
+		if keyName, ok := project.Keys[name]; ok {
+			return keyName
+		}
+	}
+	return name
+}
+
+// GenesisVars returns the values of the genesis template variables the
+// project sets for network (local, fuji or mainnet)
+func (c *Config) GenesisVars(network string) map[string]string {
+	vars := map[string]string{}
+	if project := c.GetProject(); project != nil {
+		for name, value := range project.GenesisVars[network] {
+			vars[name] = value
+		}
+	}
+	return vars
+}
+
+// Environment returns the environment name of the project
+func (c *Config) Environment(name string) (ProjectEnvironment, error) {
+	project := c.GetProject()
+	if project == nil || len(project.Environments) == 0 {
+		return ProjectEnvironment{}, fmt.Errorf("no environments are set in the project config (%s)", constants.ProjectConfigFileName)
+	}
+	env, ok := project.Environments[name]
+	if !ok {
+		names := make([]string, 0, len(project.Environments))
+		for name := range project.Environments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return ProjectEnvironment{}, fmt.Errorf("unknown environment %q, must be one of %s", name, strings.Join(names, ", "))
+	}
+	return env, nil
+}
+
+// AvalancheGoVersion returns the avalanchego version pinned by the project,
+// or the default version of this tool. pinned is true for the former.
+func (c *Config) AvalancheGoVersion() (version string, pinned bool) {
+	if project := c.GetProject(); project != nil && project.Versions.AvalancheGo != "" {
+		return project.Versions.AvalancheGo, true
+	}
+	return constants.AvalancheGoReleaseVersion, false
+}
+
+// NodeAvalancheGoVersions returns the avalanchego versions pinned by the
+// project for some nodes of the local networks, by node name. The other nodes
+// run the one of AvalancheGoVersion.
+func (c *Config) NodeAvalancheGoVersions() map[string]string {
+	versions := map[string]string{}
+	if project := c.GetProject(); project != nil {
+		for name, version := range project.Versions.Nodes {
+			versions[name] = version
+		}
+	}
+	return versions
+}
+
+// APINodes returns the number of API nodes the project adds to the local
+// networks, which track the deployed subnets without validating them
+func (c *Config) APINodes() int {
+	if project := c.GetProject(); project != nil {
+		return project.APINodes
+	}
+	return 0
+}
+
+// SubnetEVMVersion returns the subnet-evm version pinned by the project,
+// or the default version of this tool. pinned is true for the former.
+func (c *Config) SubnetEVMVersion() (version string, pinned bool) {
+	if project := c.GetProject(); project != nil && project.Versions.SubnetEVM != "" {
+		return project.Versions.SubnetEVM, true
+	}
+	return constants.SubnetEVMReleaseVersion, false
+}
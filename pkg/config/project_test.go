@@ -0,0 +1,142 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+const testProjectConfig = `
+subnets:
+  - mySubnet
+  - otherSubnet
+network: fuji
+profile: myproject
+versions:
+  avalanchego: v1.7.14
+  nodes:
+    node4: v1.7.15
+    node5: v1.7.15
+api-nodes: 2
+keys:
+  deployer: team-fuji-key
+`
+
+func Test_ProjectConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "some", "nested", "dir")
+	err := os.MkdirAll(nested, 0o755)
+	assert.NoError(err)
+
+	path, err := FindProjectConfig(nested)
+	assert.NoError(err)
+	// there may be a project config above the temp dir, but not in it
+	assert.NotContains(path, root)
+
+	projectFile := filepath.Join(root, constants.ProjectConfigFileName)
+	err = os.WriteFile(projectFile, []byte(testProjectConfig), 0o600)
+	assert.NoError(err)
+
+	path, err = FindProjectConfig(nested)
+	assert.NoError(err)
+	assert.Equal(projectFile, path)
+
+	project, err := LoadProjectConfig(path)
+	assert.NoError(err)
+	assert.Equal([]string{"mySubnet", "otherSubnet"}, project.Subnets)
+	assert.Equal("myproject", project.Profile)
+
+	cf := New()
+	cf.SetProject(project)
+	subnet, ok := cf.DefaultSubnet()
+	assert.True(ok)
+	assert.Equal("mySubnet", subnet)
+	assert.Equal("fuji", cf.DefaultNetwork())
+	assert.Equal("team-fuji-key", cf.ResolveKeyAlias("deployer"))
+	assert.Equal("other", cf.ResolveKeyAlias("other"))
+	version, pinned := cf.AvalancheGoVersion()
+	assert.True(pinned)
+	assert.Equal("v1.7.14", version)
+	assert.Equal(map[string]string{"node4": "v1.7.15", "node5": "v1.7.15"}, cf.NodeAvalancheGoVersions())
+	assert.Equal(2, cf.APINodes())
+	version, pinned = cf.SubnetEVMVersion()
+	assert.False(pinned)
+	assert.Equal(constants.SubnetEVMReleaseVersion, version)
+
+	// without project, and even without config, defaults apply
+	var noConfig *Config
+	_, ok = noConfig.DefaultSubnet()
+	assert.False(ok)
+	assert.Equal("deployer", noConfig.ResolveKeyAlias("deployer"))
+	version, pinned = noConfig.AvalancheGoVersion()
+	assert.False(pinned)
+	assert.Equal(constants.AvalancheGoReleaseVersion, version)
+	assert.Empty(noConfig.NodeAvalancheGoVersions())
+	assert.Zero(noConfig.APINodes())
+
+	err = os.WriteFile(projectFile, []byte("versions:\n  nodes:\n    node1: latest\n"), 0o600)
+	assert.NoError(err)
+	_, err = LoadProjectConfig(projectFile)
+	assert.ErrorContains(err, "node node1")
+
+	err = os.WriteFile(projectFile, []byte("api-nodes: -1\n"), 0o600)
+	assert.NoError(err)
+	_, err = LoadProjectConfig(projectFile)
+	assert.ErrorContains(err, "api-nodes is negative")
+}
+
+func TestProjectEnvironments(t *testing.T) {
+	assert := assert.New(t)
+
+	writeProject := func(content string) string {
+		path := filepath.Join(t.TempDir(), constants.ProjectConfigFileName)
+		assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	project, err := LoadProjectConfig(writeProject(`
+environments:
+  dev:
+    network: local
+  staging:
+    network: fuji
+    key: team-fuji-key
+    genesis-vars:
+      TreasuryAddress: "0x1f2C8b45cBc4Cd1B8A2a21D1e1F3B6a7A3cE45E2"
+`))
+	assert.NoError(err)
+	cf := New()
+	cf.SetProject(project)
+	env, err := cf.Environment("staging")
+	assert.NoError(err)
+	assert.Equal("fuji", env.Network)
+	assert.Equal("team-fuji-key", env.Key)
+	assert.Equal("0x1f2C8b45cBc4Cd1B8A2a21D1e1F3B6a7A3cE45E2", env.GenesisVars["treasuryaddress"])
+	_, err = cf.Environment("prod")
+	assert.ErrorContains(err, "must be one of dev, staging")
+	_, err = New().Environment("dev")
+	assert.ErrorContains(err, "no environments")
+
+	_, err = LoadProjectConfig(writeProject(`
+environments:
+  dev:
+    network: fuji
+  staging:
+    network: Fuji
+`))
+	assert.ErrorContains(err, "environments dev and staging both deploy to fuji")
+	_, err = LoadProjectConfig(writeProject(`
+environments:
+  dev:
+    network: devnet
+`))
+	assert.ErrorContains(err, "must be one of local, fuji, mainnet")
+}
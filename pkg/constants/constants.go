@@ -11,15 +11,74 @@ const (
 
 	SubnetEVMReleaseVersion   = "v0.2.3"
 	AvalancheGoReleaseVersion = "v1.7.13"
+	// first subnet-evm release honoring precompile upgrades scheduled after
+	// genesis
+	PrecompileUpgradesSubnetEVMVersion = "v0.2.8"
 
 	LatestAvagoReleaseURL = "https://api.github.com/repos/ava-labs/avalanchego/releases/latest"
 	SubnetEVMReleaseURL   = "https://api.github.com/repos/ava-labs/subnet-evm/releases/latest"
 
+	// ServerRunFile is the run file of the backends started before the
+	// services registry
 	ServerRunFile      = "gRPCserver.run"
+	LockFile           = "avalanche.lock"
 	AvalancheCliBinDir = "bin"
 	RunDir             = "runs"
 	SidecarSuffix      = "_sidecar.json"
 	GenesisSuffix      = "_genesis.json"
+	ChainConfigSuffix  = "_chain_config.json"
+	BridgeSuffix       = "_bridge.json"
+	// StateSyncFileName records, per remote, the files of the subnets as of
+	// their last push or pull
+	StateSyncFileName = "state_sync.json"
+
+	// LockWaitFlag sets how long a command waits for the state lock
+	LockWaitFlag = "lock-wait"
+	// AllowEwoqFlag lets the operations on the public networks involve the
+	// publicly known ewoq key
+	AllowEwoqFlag = "i-know-what-im-doing"
+	// DeploysDir holds the tracking records of the deploys running in the
+	// background, in the run dir
+	DeploysDir = "deploys"
+	// DeployCheckpointsDir holds the phases completed by the local deploys
+	// which failed, in the run dir
+	DeployCheckpointsDir = "checkpoints"
+	// DeployLockSuffix names the lock held by the deploy of a subnet, so that
+	// the deploys of different subnets run concurrently but not the ones of
+	// the same subnet
+	DeployLockSuffix = "_deploy.lock"
+	// NetworkLockFile is the lock held, in the run dir, by the local deploy
+	// starting the network or creating its blockchain on it
+	NetworkLockFile = "network.lock"
+	// DeployCoordinationWait is how long a local deploy waits for the
+	// concurrent ones to install their plugins or create their blockchains
+	DeployCoordinationWait = 30 * time.Minute
+	// InstallLockFile is the lock held by the local deploy installing
+	// avalanchego, the default snapshot or plugins
+	InstallLockFile = "install.lock"
+	// PluginClaimsSuffix names the dir recording, next to the plugin dir,
+	// the plugins of the local deploys in progress
+	PluginClaimsSuffix = ".claims"
+	// AsyncDeployLockWait is how long a deploy running in the background
+	// waits for the command starting it to release the state lock
+	AsyncDeployLockWait = time.Minute
+	// InvocationsLogFile records the invocations changing the state of a
+	// profile, with their correlation IDs, in its run dir
+	InvocationsLogFile = "invocations.log"
+	// MaxInvocationsLogSize is the size over which the invocations log is
+	// rotated
+	MaxInvocationsLogSize = 1024 * 1024
+
+	// HistoryDir holds the previous revisions of the genesis and sidecar of
+	// each subnet
+	HistoryDir = "history"
+	// MaxGenesisRevisions is how many previous revisions of a genesis are kept
+	MaxGenesisRevisions = 20
+	// GenesisSchemasDir holds the JSON schemas registered for the genesis of
+	// custom VMs, one per VM name
+	GenesisSchemasDir = "schemas"
+	// GenesisSchemaSuffix names the schema of a VM, in the schemas dir
+	GenesisSchemaSuffix = ".schema.json"
 
 	SidecarVersion = "1.1.0"
 
@@ -27,14 +86,112 @@ const (
 	MaxNumOfLogFiles = 5
 	RetainOldFiles   = 0 // retain all old log files
 
-	RequestTimeout = 3 * time.Minute
+	LogDir                 = "logs"
+	CliLogPrefix           = "cli-"
+	LogSuffix              = ".log"
+	MaxNumOfInvocationLogs = 20
+	DefaultLogLevel        = "ERROR"
+	DefaultLogFormat       = "colors"
+
+	LocalesDir = "locales"
+
+	ProfilesDir    = "profiles"
+	DefaultProfile = "default"
+	// ProfileBindingFile holds, in the dir of a profile, the network, key
+	// and API endpoints it is bound to
+	ProfileBindingFile = "profile.json"
+	// TutorialProfile runs the local network of avalanche tutorial, and
+	// TutorialSubnetName is the subnet it creates, with chain ID
+	// TutorialChainID
+	TutorialProfile    = "tutorial"
+	TutorialSubnetName = "tutorial"
+	TutorialChainID    = 31337
+
+	ReleaseCacheFile = "release_cache.json"
+
+	PhaseTimingsFile      = "phase_timings.json"
+	MaxPhaseTimingSamples = 10
+
+	RequestTimeout       = 3 * time.Minute
+	EndpointCheckTimeout = 10 * time.Second
+
+	// EndpointSelectionsFile records the API endpoint picked for each public
+	// network, kept for EndpointSelectionTTL while it stays healthy
+	EndpointSelectionsFile = "endpoint_selections.json"
+	EndpointSelectionTTL   = time.Hour
+	// DefaultPublicAPIRetries, DefaultPublicAPIRetryBackoff and
+	// DefaultPublicAPIRequestsPerSecond limit the requests to the API
+	// endpoints of the public networks, which throttle busy clients
+	DefaultPublicAPIRetries           = 4
+	DefaultPublicAPIRetryBackoff      = time.Second
+	DefaultPublicAPIRequestsPerSecond = 10
+	// MaxPublicAPIRetryWait caps how long a request is retried after, even
+	// if the endpoint asks for longer
+	MaxPublicAPIRetryWait = time.Minute
+	// WebhookTimeout bounds how long the events are posted to the webhooks for
+	WebhookTimeout = 5 * time.Second
 
 	FujiAPIEndpoint    = "https://api.avax-test.network"
 	MainnetAPIEndpoint = "https://api.avax.network"
+	// GlacierAPIURL and MetricsAPIURL are the public index APIs of the chains
+	// of Fuji and mainnet
+	GlacierAPIURL = "https://glacier-api.avax.network"
+	MetricsAPIURL = "https://metrics.avax.network"
+	// LocalAPIEndpoint is the API endpoint of the first node of the local
+	// network of the default profile
+	LocalAPIEndpoint = "http://127.0.0.1:9650"
+	// NodeAPIPort is the default port of the API of avalanchego, where the
+	// info API of a validator is looked for
+	NodeAPIPort = 9650
+	// NodeInfoTimeout bounds how long the info API of a validator is queried
+	// for, most of them not exposing it
+	NodeInfoTimeout = 3 * time.Second
 
 	DefaultTokenName = "TEST"
+	// DefaultTokenDecimals is the number of decimals of the native token of
+	// EVM chains, whose balances are counted in wei
+	DefaultTokenDecimals = 18
 
 	HealthCheckInterval = 100 * time.Millisecond
+	// DefaultNetworkWaitTimeout bounds how long network wait waits for the
+	// local network to be ready
+	DefaultNetworkWaitTimeout = 5 * time.Minute
+
+	SmokeTestTimeout         = 1 * time.Minute
+	SmokeTestRequestInterval = 100 * time.Millisecond
+
+	ObservabilityDir      = "observability"
+	PrometheusImage       = "prom/prometheus:v2.37.0"
+	GrafanaImage          = "grafana/grafana:9.0.5"
+	DefaultPrometheusPort = 9090
+	DefaultGrafanaPort    = 3000
+
+	UndeployedChainsFile = "undeployed.json"
+	// ProxyRunFile is the run file of the proxies started before the
+	// services registry
+	ProxyRunFile         = "proxy.run"
+	DefaultProxyPort     = 8545
+	ProxyRefreshInterval = 5 * time.Second
+	ServeTokenFile       = "serve.token"
+	DefaultServePort     = 9710
+	HostsFile            = "/etc/hosts"
+
+	// ServicesDir is the dir of the records of the background processes of a
+	// profile in its run dir
+	ServicesDir         = "services"
+	BackendService      = "backend"
+	ProxyService        = "proxy"
+	ServiceRecordSuffix = ".json"
+	ServiceStopTimeout  = 10 * time.Second
+	ServiceRestartDelay = time.Second
+	ServiceMaxRestarts  = 5
+
+	// GRPCTraceMaxPayload is the size over which the requests and responses
+	// traced with --trace-grpc are truncated
+	GRPCTraceMaxPayload = 4096
+
+	// SchemaSuffix suffixes the JSON schemas exported by schema export
+	SchemaSuffix = ".schema.json"
 
 	// it's unlikely anyone would want to name a snapshot `default`
 	// but let's add some more entropy
@@ -42,15 +199,51 @@ const (
 	DefaultSnapshotName          = "default-1654102509"
 	BootstrapSnapshotURL         = "https://github.com/ava-labs/avalanche-cli/raw/main/assets/bootstrapSnapshot.tar.gz"
 	BootstrapSnapshotArchiveName = "bootstrapSnapshot.tar.gz"
-
-	KeyDir    = "key"
-	KeySuffix = ".pk"
+	BootstrapSnapshotRecordName  = "bootstrapSnapshot.json"
+	// SnapshotRecordSuffix names the record of the versions which saved a
+	// snapshot, in the snapshots dir
+	SnapshotRecordSuffix = "_snapshot.json"
+	// validated subnets preloaded in a rebuilt bootstrap snapshot
+	BootstrapSnapshotSubnets = 5
+	// LocalNetworkID is the network ID of the local network, unless the
+	// bootstrap snapshot is rebuilt with another one
+	LocalNetworkID = 1337
+
+	KeyDir          = "key"
+	KeySuffix       = ".pk"
+	RemoteKeySuffix = ".remote.json"
+	// KeyAgentDir holds the socket of the agent holding the unlocked keys,
+	// only accessible by the user
+	KeyAgentDir    = "agent"
+	KeyAgentSocket = "agent.sock"
+	// DefaultKeyUnlockTTL is how long the agent holds an unlocked key
+	DefaultKeyUnlockTTL = 15 * time.Minute
+
+	StakingDir     = "staking"
+	StakerCertFile = "staker.crt"
+	StakerKeyFile  = "staker.key"
+	// FleetManifestFile lists the NodeIDs of the nodes generated by node
+	// keygen, as a validators file
+	FleetManifestFile = "validators.csv"
 
 	TimeParseLayout      = "2006-01-02 15:04:05"
 	MinStakeDuration     = 24 * 14 * time.Hour
 	MaxStakeDuration     = 24 * 365 * time.Hour
 	StakingStartLeadTime = 25 * time.Second
+	// ClockSkewTolerance is how far the local clock may be off the time of a
+	// network before the start times of validators are computed from the
+	// latter
+	ClockSkewTolerance = 5 * time.Second
+	// DefaultValidatorTxParallelism is how many transactions adding the
+	// validators of a batch are issued at once
+	DefaultValidatorTxParallelism = 8
 
 	DefaultConfigFileName = ".avalanche-cli"
 	DefaultConfigFileType = "json"
+
+	// EnvVarPrefix prefixes the environment variables setting the flags
+	EnvVarPrefix = "AVALANCHE_"
+
+	ProjectConfigFileName = ".avalanche.yaml"
+	TopologyFileName      = "topology.yaml"
 )
@@ -0,0 +1,116 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package devenv detects containerized development environments, such as
+// GitHub Codespaces and devcontainers, where the local network runs in a
+// container and is reached from the host through forwarded ports.
+package devenv
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Kind is a kind of environment the CLI runs in
+type Kind int
+
+const (
+	// None is a machine of its own, where the nodes are reached directly
+	None Kind = iota
+	// Container is any other container, whose ports are published by its
+	// runtime, e.g. with docker run -p
+	Container
+	// DevContainer is a devcontainer opened by an editor, which forwards
+	// its ports to the host
+	DevContainer
+	// Codespaces is a GitHub codespace, whose ports are forwarded to URLs
+	// of their own
+	Codespaces
+)
+
+// defaultForwardingDomain is the domain of the forwarded ports of GitHub
+// Codespaces, unless told otherwise by the codespace
+const defaultForwardingDomain = "app.github.dev"
+
+// containerMarkers are the files container runtimes create in containers
+var containerMarkers = []string{"/.dockerenv", "/run/.containerenv"}
+
+// Environment is the environment the CLI runs in
+type Environment struct {
+	Kind Kind
+	// codespace and forwardingDomain make up the forwarded URLs of Codespaces
+	codespace        string
+	forwardingDomain string
+}
+
+var (
+	detectOnce sync.Once
+	detected   Environment
+)
+
+// Detect returns the environment the CLI runs in
+func Detect() Environment {
+	detectOnce.Do(func() {
+		detected = detect(os.Getenv, func(path string) bool {
+			_, err := os.Stat(path)
+			return err == nil
+		})
+	})
+	return detected
+}
+
+func detect(getenv func(string) string, exists func(string) bool) Environment {
+	if getenv("CODESPACES") == "true" && getenv("CODESPACE_NAME") != "" {
+		domain := getenv("GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN")
+		if domain == "" {
+			domain = defaultForwardingDomain
+		}
+		return Environment{Kind: Codespaces, codespace: getenv("CODESPACE_NAME"), forwardingDomain: domain}
+	}
+	if getenv("REMOTE_CONTAINERS") == "true" || getenv("DEVCONTAINER") == "true" {
+		return Environment{Kind: DevContainer}
+	}
+	for _, marker := range containerMarkers {
+		if exists(marker) {
+			return Environment{Kind: Container}
+		}
+	}
+	return Environment{}
+}
+
+// InContainer returns true if the CLI runs in a container, where the nodes
+// must listen on all interfaces to be reached from the host
+func (e Environment) InContainer() bool {
+	return e.Kind != None
+}
+
+// HostURL returns the URL the host reaches uri at, uri being served in the
+// environment: the forwarded URL of its port in Codespaces, or uri with a
+// wildcard host replaced by localhost in other containers, whose ports are
+// forwarded or published to the same ports of the host
+func (e Environment) HostURL(uri string) string {
+	if e.Kind == None {
+		return uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Port() == "" {
+		return uri
+	}
+	if e.Kind == Codespaces {
+		switch u.Scheme {
+		case "http":
+			u.Scheme = "https"
+		case "ws":
+			u.Scheme = "wss"
+		}
+		u.Host = fmt.Sprintf("%s-%s.%s", e.codespace, u.Port(), e.forwardingDomain)
+		return u.String()
+	}
+	switch u.Hostname() {
+	case "0.0.0.0", "::", "":
+		u.Host = "localhost:" + u.Port()
+	}
+	return u.String()
+}
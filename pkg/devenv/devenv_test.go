@@ -0,0 +1,44 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package devenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	assert := assert.New(t)
+
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	noFiles := func(string) bool { return false }
+
+	e := detect(env(map[string]string{"CODESPACES": "true", "CODESPACE_NAME": "fuzzy-space"}), noFiles)
+	assert.Equal(Codespaces, e.Kind)
+	assert.Equal("https://fuzzy-space-9650.app.github.dev/ext/bc/C/rpc", e.HostURL("http://0.0.0.0:9650/ext/bc/C/rpc"))
+	assert.Equal("wss://fuzzy-space-9652.app.github.dev/ext/bc/C/ws", e.HostURL("ws://127.0.0.1:9652/ext/bc/C/ws"))
+
+	e = detect(env(map[string]string{
+		"CODESPACES":     "true",
+		"CODESPACE_NAME": "fuzzy-space",
+		"GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN": "preview.app.github.dev",
+	}), noFiles)
+	assert.Equal("https://fuzzy-space-9650.preview.app.github.dev", e.HostURL("http://127.0.0.1:9650"))
+
+	e = detect(env(map[string]string{"REMOTE_CONTAINERS": "true"}), noFiles)
+	assert.Equal(DevContainer, e.Kind)
+	assert.Equal("http://localhost:9650/ext/bc/C/rpc", e.HostURL("http://0.0.0.0:9650/ext/bc/C/rpc"))
+	assert.Equal("http://127.0.0.1:9650", e.HostURL("http://127.0.0.1:9650"))
+
+	e = detect(env(nil), func(path string) bool { return path == "/.dockerenv" })
+	assert.Equal(Container, e.Kind)
+	assert.True(e.InContainer())
+
+	e = detect(env(nil), noFiles)
+	assert.False(e.InContainer())
+	assert.Equal("http://0.0.0.0:9650", e.HostURL("http://0.0.0.0:9650"))
+}
@@ -0,0 +1,418 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package docs renders the page describing a subnet to the teams integrating
+// it: its chain parameters, where to reach it on each network and who
+// controls it.
+package docs
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The formats a page is rendered in
+const (
+	Markdown = "markdown"
+	HTML     = "html"
+)
+
+// networks are the networks a chain can be deployed to, in page order
+var networks = []models.Network{models.Local, models.Fuji, models.Mainnet}
+
+// Parameter is a named value of the chain
+type Parameter struct {
+	Name  string
+	Value string
+}
+
+// Precompile is a stateful precompile enabled at genesis, with the addresses
+// administering its allow list
+type Precompile struct {
+	Name   string
+	Admins []string
+}
+
+// Upgrade is a precompile upgrade scheduled for the chain
+type Upgrade struct {
+	Precompile string
+	Change     string
+	Activation time.Time
+}
+
+// Validator is a current validator of a subnet
+type Validator struct {
+	NodeID string
+	Weight uint64
+	End    time.Time
+}
+
+// Deployment is the chain deployed to a network
+type Deployment struct {
+	Network      string
+	Environment  string
+	SubnetID     string
+	BlockchainID string
+	// RPCURL and WSURL are empty if the API endpoint of the network is
+	// unknown
+	RPCURL string
+	WSURL  string
+	// ControlKeys and Threshold are only known for the subnets the CLI
+	// created
+	ControlKeys []models.ControlKey
+	Threshold   uint32
+	Validators  []Validator
+	// ValidatorsError tells why the validators couldn't be listed
+	ValidatorsError string
+}
+
+// Page describes a subnet
+type Page struct {
+	Subnet        string
+	VM            string
+	VMID          string
+	ChainID       string
+	TokenName     string
+	TokenDecimals int
+	// Fees, Precompiles and Upgrades are only known for subnet-evm chains
+	Fees        []Parameter
+	Precompiles []Precompile
+	Upgrades    []Upgrade
+	Deployments []Deployment
+	Generated   time.Time
+}
+
+// ValidatorsLister returns the current validators of subnetID on network
+type ValidatorsLister func(network models.Network, subnetID ids.ID) ([]Validator, error)
+
+// Build returns the page of the subnet of sc. genesis is nil for the VMs
+// whose genesis the CLI doesn't know. The deployments are the ones to the
+// local network, recorded under localKey, Fuji and mainnet. endpoints holds
+// the API endpoint of each network, a network without one gets no RPC URLs.
+// listValidators, if not nil, lists the validators of each deployment.
+func Build(
+	sc models.Sidecar,
+	genesis *core.Genesis,
+	localKey string,
+	endpoints map[models.Network]string,
+	listValidators ValidatorsLister,
+) Page {
+	page := Page{
+		Subnet:        sc.Name,
+		VM:            string(sc.VM),
+		ChainID:       sc.ChainID,
+		TokenName:     sc.TokenName,
+		TokenDecimals: sc.GetTokenDecimals(),
+		Generated:     time.Now().UTC(),
+	}
+	if vmID, err := sc.GetVMID(); err == nil {
+		page.VMID = vmID.String()
+	}
+	if genesis != nil && genesis.Config != nil {
+		if page.ChainID == "" && genesis.Config.ChainID != nil {
+			page.ChainID = genesis.Config.ChainID.String()
+		}
+		page.Fees = fees(genesis)
+		page.Precompiles = precompiles(genesis)
+	}
+	for _, u := range sc.Upgrades {
+		change := "enable"
+		if u.Disable {
+			change = "disable"
+		}
+		if len(u.AdminAddresses) > 0 {
+			change += " (admins " + strings.Join(u.AdminAddresses, ", ") + ")"
+		}
+		page.Upgrades = append(page.Upgrades, Upgrade{
+			Precompile: u.Precompile,
+			Change:     change,
+			Activation: u.ActivationTime().UTC(),
+		})
+	}
+	for _, network := range networks {
+		key := network.String()
+		if network == models.Local {
+			key = localKey
+		}
+		data, ok := sc.Networks[key]
+		if !ok || data.BlockchainID == ids.Empty {
+			continue
+		}
+		deployment := Deployment{
+			Network:      key,
+			Environment:  data.Environment,
+			SubnetID:     data.SubnetID.String(),
+			BlockchainID: data.BlockchainID.String(),
+		}
+		if endpoint := endpoints[network]; endpoint != "" {
+			deployment.RPCURL = ux.RPCEndpoint(endpoint, data.BlockchainID.String())
+			deployment.WSURL = ux.WSEndpoint(endpoint, data.BlockchainID.String())
+		}
+		if data.Governance != nil {
+			deployment.ControlKeys = data.Governance.ControlKeys
+			deployment.Threshold = data.Governance.Threshold
+		}
+		if listValidators != nil {
+			validators, err := listValidators(network, data.SubnetID)
+			if err != nil {
+				deployment.ValidatorsError = err.Error()
+			}
+			sort.Slice(validators, func(i, j int) bool { return validators[i].NodeID < validators[j].NodeID })
+			deployment.Validators = validators
+		}
+		page.Deployments = append(page.Deployments, deployment)
+	}
+	return page
+}
+
+func fees(genesis *core.Genesis) []Parameter {
+	fee := genesis.Config.FeeConfig
+	return []Parameter{
+		{"Gas limit", bigString(fee.GasLimit)},
+		{"Target block rate (s)", strconv.FormatUint(fee.TargetBlockRate, 10)},
+		{"Min base fee (wei)", bigString(fee.MinBaseFee)},
+		{"Target gas (per 10s)", bigString(fee.TargetGas)},
+		{"Base fee change denominator", bigString(fee.BaseFeeChangeDenominator)},
+		{"Min block gas cost", bigString(fee.MinBlockGasCost)},
+		{"Max block gas cost", bigString(fee.MaxBlockGasCost)},
+		{"Block gas cost step", bigString(fee.BlockGasCostStep)},
+		{"Fees", vm.DescribeFeeDestination(*genesis.Config)},
+	}
+}
+
+func precompiles(genesis *core.Genesis) []Precompile {
+	allowLists := []struct {
+		name   string
+		config precompile.AllowListConfig
+	}{
+		{"Contract deployer allow list", genesis.Config.ContractDeployerAllowListConfig.AllowListConfig},
+		{"Transaction allow list", genesis.Config.TxAllowListConfig.AllowListConfig},
+		{"Native minter", genesis.Config.ContractNativeMinterConfig.AllowListConfig},
+	}
+	enabled := []Precompile{}
+	for _, allowList := range allowLists {
+		if allowList.config.BlockTimestamp == nil {
+			continue
+		}
+		enabled = append(enabled, Precompile{
+			Name:   allowList.name,
+			Admins: hexAddresses(allowList.config.AllowListAdmins),
+		})
+	}
+	return enabled
+}
+
+func hexAddresses(addresses []common.Address) []string {
+	hexes := make([]string, len(addresses))
+	for i, address := range addresses {
+		hexes[i] = address.Hex()
+	}
+	return hexes
+}
+
+func bigString(n *big.Int) string {
+	if n == nil {
+		return "-"
+	}
+	return n.String()
+}
+
+// Render writes page to w in format, Markdown or HTML
+func Render(w io.Writer, page Page, format string) error {
+	switch format {
+	case Markdown:
+		return markdownTemplate.Execute(w, page)
+	case HTML:
+		return htmlTemplate.Execute(w, page)
+	}
+	return exitcodes.UserInput(fmt.Errorf("unknown format %q, must be one of %s, %s", format, Markdown, HTML))
+}
+
+var funcs = map[string]interface{}{
+	// cell escapes the pipes of a Markdown table cell
+	"cell": func(s string) string { return strings.ReplaceAll(s, "|", `\|`) },
+	"date": func(t time.Time) string { return t.Format(time.RFC3339) },
+	"join": strings.Join,
+}
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(funcs).Parse(`# {{cell .Subnet}}
+
+_Generated on {{date .Generated}} from the configuration of the subnet._
+
+## Chain
+
+| Parameter | Value |
+| --- | --- |
+| EVM chain ID | {{if .ChainID}}{{.ChainID}}{{else}}-{{end}} |
+| Token | {{cell .TokenName}} ({{.TokenDecimals}} decimals) |
+| VM | {{.VM}} |
+| VM ID | {{.VMID}} |
+
+## Endpoints
+{{if .Deployments}}
+| Network | Environment | RPC URL | WebSocket URL |
+| --- | --- | --- | --- |
+{{- range .Deployments}}
+| {{.Network}} | {{if .Environment}}{{cell .Environment}}{{else}}-{{end}} | {{if .RPCURL}}{{.RPCURL}}{{else}}unknown{{end}} | {{if .WSURL}}{{.WSURL}}{{else}}unknown{{end}} |
+{{- end}}
+{{else}}
+The subnet is not deployed.
+{{end}}
+{{- if .Fees}}
+## Fees
+
+| Parameter | Value |
+| --- | --- |
+{{- range .Fees}}
+| {{.Name}} | {{.Value}} |
+{{- end}}
+{{end}}
+{{- if .Precompiles}}
+## Precompiles
+
+| Precompile | Admins |
+| --- | --- |
+{{- range .Precompiles}}
+| {{.Name}} | {{if .Admins}}{{join .Admins ", "}}{{else}}none{{end}} |
+{{- end}}
+{{end}}
+{{- if .Upgrades}}
+## Scheduled upgrades
+
+| Precompile | Change | Activation |
+| --- | --- | --- |
+{{- range .Upgrades}}
+| {{cell .Precompile}} | {{cell .Change}} | {{date .Activation}} |
+{{- end}}
+{{end}}
+{{- range .Deployments}}
+## {{.Network}}
+
+| Parameter | Value |
+| --- | --- |
+| Subnet ID | {{.SubnetID}} |
+| Blockchain ID | {{.BlockchainID}} |
+{{if .ControlKeys}}
+{{.Threshold}} of the following control keys must sign to add validators or blockchains:
+
+| Address | Held by |
+| --- | --- |
+{{- range .ControlKeys}}
+| {{.Address}} | {{cell .HeldBy}} |
+{{- end}}
+{{end}}
+{{- if .ValidatorsError}}
+The validators could not be listed: {{.ValidatorsError}}
+{{else if .Validators}}
+| Validator | Weight | Until |
+| --- | --- | --- |
+{{- range .Validators}}
+| {{.NodeID}} | {{.Weight}} | {{date .End}} |
+{{- end}}
+{{end}}
+{{- end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Subnet}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+td { font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Subnet}}</h1>
+<p><em>Generated on {{date .Generated}} from the configuration of the subnet.</em></p>
+<h2>Chain</h2>
+<table>
+<tr><th>EVM chain ID</th><td>{{if .ChainID}}{{.ChainID}}{{else}}-{{end}}</td></tr>
+<tr><th>Token</th><td>{{.TokenName}} ({{.TokenDecimals}} decimals)</td></tr>
+<tr><th>VM</th><td>{{.VM}}</td></tr>
+<tr><th>VM ID</th><td>{{.VMID}}</td></tr>
+</table>
+<h2>Endpoints</h2>
+{{- if .Deployments}}
+<table>
+<tr><th>Network</th><th>Environment</th><th>RPC URL</th><th>WebSocket URL</th></tr>
+{{- range .Deployments}}
+<tr><td>{{.Network}}</td><td>{{if .Environment}}{{.Environment}}{{else}}-{{end}}</td><td>{{if .RPCURL}}{{.RPCURL}}{{else}}unknown{{end}}</td><td>{{if .WSURL}}{{.WSURL}}{{else}}unknown{{end}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>The subnet is not deployed.</p>
+{{- end}}
+{{- if .Fees}}
+<h2>Fees</h2>
+<table>
+{{- range .Fees}}
+<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Precompiles}}
+<h2>Precompiles</h2>
+<table>
+<tr><th>Precompile</th><th>Admins</th></tr>
+{{- range .Precompiles}}
+<tr><td>{{.Name}}</td><td>{{if .Admins}}{{join .Admins ", "}}{{else}}none{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Upgrades}}
+<h2>Scheduled upgrades</h2>
+<table>
+<tr><th>Precompile</th><th>Change</th><th>Activation</th></tr>
+{{- range .Upgrades}}
+<tr><td>{{.Precompile}}</td><td>{{.Change}}</td><td>{{date .Activation}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .Deployments}}
+<h2>{{.Network}}</h2>
+<table>
+<tr><th>Subnet ID</th><td>{{.SubnetID}}</td></tr>
+<tr><th>Blockchain ID</th><td>{{.BlockchainID}}</td></tr>
+</table>
+{{- if .ControlKeys}}
+<p>{{.Threshold}} of the following control keys must sign to add validators or blockchains:</p>
+<table>
+<tr><th>Address</th><th>Held by</th></tr>
+{{- range .ControlKeys}}
+<tr><td>{{.Address}}</td><td>{{.HeldBy}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .ValidatorsError}}
+<p>The validators could not be listed: {{.ValidatorsError}}</p>
+{{- else if .Validators}}
+<table>
+<tr><th>Validator</th><th>Weight</th><th>Until</th></tr>
+{{- range .Validators}}
+<tr><td>{{.NodeID}}</td><td>{{.Weight}}</td><td>{{date .End}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,124 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package docs
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSidecar() (models.Sidecar, *core.Genesis) {
+	admin := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	config := *params.TestChainConfig
+	config.ChainID = big.NewInt(12345)
+	config.ContractNativeMinterConfig = precompile.ContractNativeMinterConfig{
+		AllowListConfig: precompile.AllowListConfig{
+			BlockTimestamp:  big.NewInt(0),
+			AllowListAdmins: []common.Address{admin},
+		},
+	}
+	sc := models.Sidecar{
+		Name:      "mychain",
+		VM:        models.SubnetEvm,
+		TokenName: "MYC",
+		Networks: map[string]models.NetworkData{
+			models.Local.String(): {
+				SubnetID:     ids.GenerateTestID(),
+				BlockchainID: ids.GenerateTestID(),
+			},
+			models.Fuji.String(): {
+				SubnetID:     ids.GenerateTestID(),
+				BlockchainID: ids.GenerateTestID(),
+				Environment:  "staging",
+				Governance: &models.SubnetGovernance{
+					ControlKeys: []models.ControlKey{
+						{Address: "P-fuji1abc", Key: "ops"},
+						{Address: "P-fuji1def", Holder: "treasury | finance"},
+					},
+					Threshold: 2,
+				},
+			},
+			// local deploys of another profile
+			"Local Network (other)": {
+				SubnetID:     ids.GenerateTestID(),
+				BlockchainID: ids.GenerateTestID(),
+			},
+		},
+	}
+	return sc, &core.Genesis{Config: &config}
+}
+
+func TestBuild(t *testing.T) {
+	assert := assert.New(t)
+
+	sc, genesis := testSidecar()
+	end := time.Unix(1700000000, 0).UTC()
+	page := Build(sc, genesis, models.Local.String(), map[models.Network]string{
+		models.Fuji: "https://api.avax-test.network",
+	}, func(network models.Network, subnetID ids.ID) ([]Validator, error) {
+		if network == models.Local {
+			return nil, errors.New("the local network is not running")
+		}
+		assert.Equal(sc.Networks[models.Fuji.String()].SubnetID, subnetID)
+		return []Validator{
+			{NodeID: "NodeID-B", Weight: 20, End: end},
+			{NodeID: "NodeID-A", Weight: 20, End: end},
+		}, nil
+	})
+
+	assert.Equal("mychain", page.Subnet)
+	assert.Equal("12345", page.ChainID)
+	assert.Equal(18, page.TokenDecimals)
+	assert.Equal([]Precompile{{Name: "Native minter", Admins: []string{"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"}}}, page.Precompiles)
+	assert.NotEmpty(page.Fees)
+
+	// the local deploys of other profiles are left out
+	assert.Len(page.Deployments, 2)
+	local, fuji := page.Deployments[0], page.Deployments[1]
+	assert.Equal(models.Local.String(), local.Network)
+	assert.Empty(local.RPCURL)
+	assert.Equal("the local network is not running", local.ValidatorsError)
+
+	blockchainID := sc.Networks[models.Fuji.String()].BlockchainID.String()
+	assert.Equal("https://api.avax-test.network/ext/bc/"+blockchainID+"/rpc", fuji.RPCURL)
+	assert.Equal("wss://api.avax-test.network/ext/bc/"+blockchainID+"/ws", fuji.WSURL)
+	assert.Equal("staging", fuji.Environment)
+	assert.Equal(uint32(2), fuji.Threshold)
+	assert.Equal("NodeID-A", fuji.Validators[0].NodeID)
+}
+
+func TestRender(t *testing.T) {
+	assert := assert.New(t)
+
+	sc, genesis := testSidecar()
+	page := Build(sc, genesis, models.Local.String(), map[models.Network]string{
+		models.Fuji: "https://api.avax-test.network",
+	}, nil)
+	page.Subnet = "<mychain>"
+
+	var markdown bytes.Buffer
+	assert.NoError(Render(&markdown, page, Markdown))
+	assert.Contains(markdown.String(), "# <mychain>\n")
+	assert.Contains(markdown.String(), "| EVM chain ID | 12345 |")
+	assert.Contains(markdown.String(), "| Native minter | 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC |")
+	assert.Contains(markdown.String(), "| P-fuji1def | treasury \\| finance |")
+	assert.Contains(markdown.String(), "2 of the following control keys")
+
+	var html bytes.Buffer
+	assert.NoError(Render(&html, page, HTML))
+	assert.Contains(html.String(), "<h1>&lt;mychain&gt;</h1>")
+	assert.Contains(html.String(), "<tr><td>P-fuji1abc</td><td>local key ops</td></tr>")
+
+	assert.Error(Render(&html, page, "pdf"))
+}
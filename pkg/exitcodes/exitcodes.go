@@ -0,0 +1,126 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package exitcodes defines the exit codes returned by the CLI, so that
+// wrappers and CI pipelines can branch on the class of a failure
+// instead of parsing the error output.
+package exitcodes
+
+import (
+	"errors"
+	"strings"
+)
+
+type ExitCode int
+
+// Exit codes returned by the CLI. These are part of the public
+// interface of the tool: do not change existing values.
+const (
+	Success ExitCode = 0
+	// GenericError is returned for any failure not classified otherwise
+	GenericError ExitCode = 1
+	// UserInputError is returned for invalid arguments, flags or configurations
+	UserInputError ExitCode = 2
+	// NetworkUnhealthy is returned if a network could not reach a healthy state
+	NetworkUnhealthy ExitCode = 3
+	// InsufficientFunds is returned if a key can not pay for a transaction
+	InsufficientFunds ExitCode = 4
+	// BackendFailure is returned if the backend controller can't be started or reached
+	BackendFailure ExitCode = 5
+	// StateLocked is returned if another invocation is mutating the app state
+	StateLocked ExitCode = 6
+)
+
+func (c ExitCode) String() string {
+	switch c {
+	case Success:
+		return "success"
+	case GenericError:
+		return "generic error"
+	case UserInputError:
+		return "user input error"
+	case NetworkUnhealthy:
+		return "network unhealthy"
+	case InsufficientFunds:
+		return "insufficient funds"
+	case BackendFailure:
+		return "backend failure"
+	case StateLocked:
+		return "state locked"
+	}
+	return "unknown exit code"
+}
+
+// Error attaches an exit code to an error
+type Error struct {
+	Code ExitCode
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New attaches the given exit code to err. Returns nil if err is nil.
+func New(code ExitCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// UserInput marks err as caused by invalid user input
+func UserInput(err error) error {
+	return New(UserInputError, err)
+}
+
+// Unhealthy marks err as caused by a network which is not healthy
+func Unhealthy(err error) error {
+	return New(NetworkUnhealthy, err)
+}
+
+// Funds marks err as caused by insufficient funds
+func Funds(err error) error {
+	return New(InsufficientFunds, err)
+}
+
+// Backend marks err as caused by the backend controller
+func Backend(err error) error {
+	return New(BackendFailure, err)
+}
+
+// Locked marks err as caused by another invocation holding the state lock
+func Locked(err error) error {
+	return New(StateLocked, err)
+}
+
+// FromError returns the exit code for err. Errors without an attached
+// exit code which report insufficient funds (as returned by the wallet)
+// are classified as such, any other error is a GenericError.
+func FromError(err error) ExitCode {
+	if err == nil {
+		return Success
+	}
+	var codeErr *Error
+	if errors.As(err, &codeErr) {
+		return codeErr.Code
+	}
+	if IsInsufficientFunds(err) {
+		return InsufficientFunds
+	}
+	return GenericError
+}
+
+// IsInsufficientFunds returns true if err reports that the funds
+// available to a key are not enough to issue a transaction
+func IsInsufficientFunds(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "insufficient funds") || strings.Contains(msg, "insufficient balance")
+}
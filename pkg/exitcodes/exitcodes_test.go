@@ -0,0 +1,25 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package exitcodes
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromError(t *testing.T) {
+	assert := assert.New(t)
+	testErr := errors.New("test")
+
+	assert.Equal(Success, FromError(nil))
+	assert.Equal(GenericError, FromError(testErr))
+	assert.Equal(UserInputError, FromError(UserInput(testErr)))
+	assert.Equal(NetworkUnhealthy, FromError(fmt.Errorf("wrapped: %w", Unhealthy(testErr))))
+	assert.Equal(BackendFailure, FromError(Backend(testErr)))
+	assert.Equal(StateLocked, FromError(Locked(testErr)))
+	assert.Equal(InsufficientFunds, FromError(errors.New("couldn't issue tx: insufficient funds: provided 0")))
+	assert.Nil(New(UserInputError, nil))
+}
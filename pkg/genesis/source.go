@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// StdinSource is the source of a genesis read from the standard input
+	StdinSource = "-"
+	// MaxSourceSize bounds the size of a genesis read from the standard
+	// input or downloaded
+	MaxSourceSize = 10 * 1024 * 1024
+	// sourceTimeout bounds how long downloading a genesis takes
+	sourceTimeout = 30 * time.Second
+)
+
+// sourceContentTypes are the content types a genesis is downloaded as. Raw
+// files of git forges are served as text/plain.
+var sourceContentTypes = map[string]bool{
+	"application/json":         true,
+	"text/plain":               true,
+	"application/octet-stream": true,
+}
+
+// IsURLSource returns true if source is a URL a genesis is downloaded from
+func IsURLSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// ReadSource returns the genesis at source: a file path, an http(s) URL, or
+// StdinSource for the standard input
+func ReadSource(source string) ([]byte, error) {
+	return readSource(source, os.Stdin, http.DefaultClient)
+}
+
+func readSource(source string, stdin io.Reader, client *http.Client) ([]byte, error) {
+	switch {
+	case source == StdinSource:
+		return readLimited(stdin, "the standard input")
+	case IsURLSource(source):
+		return download(source, client)
+	}
+	return os.ReadFile(source)
+}
+
+func download(url string, client *http.Client) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sourceTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed downloading %s: %s", url, resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !sourceContentTypes[mediaType] {
+			return nil, fmt.Errorf("%s is served as %q, not as a JSON or text file", url, contentType)
+		}
+	}
+	if resp.ContentLength > MaxSourceSize {
+		return nil, fmt.Errorf("%s is %d bytes, more than the %d bytes limit", url, resp.ContentLength, MaxSourceSize)
+	}
+	return readLimited(resp.Body, url)
+}
+
+// readLimited reads r, failing if it is larger than MaxSourceSize
+func readLimited(r io.Reader, name string) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, MaxSourceSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", name, err)
+	}
+	if len(content) > MaxSourceSize {
+		return nil, fmt.Errorf("%s is more than the %d bytes limit", name, MaxSourceSize)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	return content, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSource(t *testing.T) {
+	assert := assert.New(t)
+
+	genesis := `{"config":{}}`
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	assert.NoError(os.WriteFile(path, []byte(genesis), 0o600))
+	content, err := readSource(path, nil, nil)
+	assert.NoError(err)
+	assert.Equal(genesis, string(content))
+
+	content, err = readSource(StdinSource, strings.NewReader(genesis), nil)
+	assert.NoError(err)
+	assert.Equal(genesis, string(content))
+	_, err = readSource(StdinSource, strings.NewReader(""), nil)
+	assert.ErrorContains(err, "the standard input is empty")
+	_, err = readSource(StdinSource, strings.NewReader(strings.Repeat("a", MaxSourceSize+1)), nil)
+	assert.ErrorContains(err, "more than the")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genesis.json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(genesis))
+		case "/raw":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte(genesis))
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		case "/big":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(strings.Repeat("a", MaxSourceSize+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	assert.True(IsURLSource(server.URL))
+	for _, p := range []string{"/genesis.json", "/raw"} {
+		content, err = readSource(server.URL+p, nil, server.Client())
+		assert.NoError(err)
+		assert.Equal(genesis, string(content))
+	}
+	_, err = readSource(server.URL+"/page", nil, server.Client())
+	assert.ErrorContains(err, `served as "text/html"`)
+	_, err = readSource(server.URL+"/big", nil, server.Client())
+	assert.ErrorContains(err, "limit")
+	_, err = readSource(server.URL+"/missing", nil, server.Client())
+	assert.ErrorContains(err, "404")
+}
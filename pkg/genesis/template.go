@@ -0,0 +1,139 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package genesis resolves genesis templates, genesis files referencing
+// variables such as {{.TreasuryAddress}} which are only known once the
+// network a subnet is deployed to is chosen.
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// IsTemplate returns true if genesis references template variables
+func IsTemplate(genesis []byte) bool {
+	return bytes.Contains(genesis, []byte("{{"))
+}
+
+// Vars returns the sorted names of the variables genesis references
+func Vars(genesis []byte) ([]string, error) {
+	tmpl, err := parseTemplate(genesis)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]struct{}{}
+	if tmpl.Tree != nil {
+		collectVars(tmpl.Tree.Root, names)
+	}
+	vars := make([]string, 0, len(names))
+	for name := range names {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+	return vars, nil
+}
+
+// Render resolves the variables genesis references with vars, whose names
+// are matched case insensitively, as the config files don't preserve their
+// case. Every referenced variable must be set, and the result must be JSON.
+func Render(genesis []byte, vars map[string]string) ([]byte, error) {
+	if !IsTemplate(genesis) {
+		return genesis, nil
+	}
+	names, err := Vars(genesis)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]string, len(names))
+	missing := []string{}
+	for _, name := range names {
+		value, ok := lookup(vars, name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		data[name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("genesis template variables not set: %s", strings.Join(missing, ", "))
+	}
+	tmpl, err := parseTemplate(genesis)
+	if err != nil {
+		return nil, err
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return nil, fmt.Errorf("failed rendering genesis template: %w", err)
+	}
+	if !json.Valid(rendered.Bytes()) {
+		return nil, fmt.Errorf("rendered genesis is not valid JSON, check the values of %s", strings.Join(names, ", "))
+	}
+	return rendered.Bytes(), nil
+}
+
+func parseTemplate(genesis []byte) (*template.Template, error) {
+	tmpl, err := template.New("genesis").Option("missingkey=error").Parse(string(genesis))
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func lookup(vars map[string]string, name string) (string, bool) {
+	if value, ok := vars[name]; ok {
+		return value, true
+	}
+	for k, value := range vars {
+		if strings.EqualFold(k, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// collectVars adds the names of the fields of the data node references
+// to names, as in {{.Name}} or {{if .Name}}
+func collectVars(node parse.Node, names map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectVars(child, names)
+		}
+	case *parse.ActionNode:
+		collectVars(n.Pipe, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectVars(cmd, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectVars(arg, names)
+		}
+	case *parse.FieldNode:
+		names[n.Ident[0]] = struct{}{}
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, names)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, names)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, names)
+	}
+}
+
+func collectBranch(n *parse.BranchNode, names map[string]struct{}) {
+	collectVars(n.Pipe, names)
+	collectVars(n.List, names)
+	collectVars(n.ElseList, names)
+}
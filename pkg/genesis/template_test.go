@@ -0,0 +1,44 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl := []byte(`{"config":{"chainId":{{.ChainID}}},"alloc":{"{{.TreasuryAddress}}":{"balance":"0x1"}}{{if .Extra}},"extraData":"{{.Extra}}"{{end}}}`)
+	assert.True(IsTemplate(tmpl))
+	vars, err := Vars(tmpl)
+	assert.NoError(err)
+	assert.Equal([]string{"ChainID", "Extra", "TreasuryAddress"}, vars)
+
+	// names are matched case insensitively
+	rendered, err := Render(tmpl, map[string]string{
+		"chainid":         "12345",
+		"TreasuryAddress": "8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+		"extra":           "",
+	})
+	assert.NoError(err)
+	assert.Equal(`{"config":{"chainId":12345},"alloc":{"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC":{"balance":"0x1"}}}`, string(rendered))
+
+	_, err = Render(tmpl, map[string]string{"ChainID": "1"})
+	assert.EqualError(err, "genesis template variables not set: Extra, TreasuryAddress")
+
+	_, err = Render(tmpl, map[string]string{"ChainID": "not a number", "TreasuryAddress": "a", "Extra": ""})
+	assert.ErrorContains(err, "not valid JSON")
+
+	_, err = Render([]byte(`{"a": {{.A}`), nil)
+	assert.ErrorContains(err, "invalid genesis template")
+
+	plain := []byte(`{"config":{}}`)
+	assert.False(IsTemplate(plain))
+	rendered, err = Render(plain, nil)
+	assert.NoError(err)
+	assert.Equal(plain, rendered)
+}
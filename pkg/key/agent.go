@@ -0,0 +1,294 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/pkg/reexec"
+)
+
+const (
+	agentOpGet    = "get"
+	agentOpAdd    = "add"
+	agentOpRemove = "remove"
+	agentOpList   = "list"
+
+	agentDialTimeout = time.Second
+	// agentIdleTimeout is how long the agent waits without holding any key
+	// before exiting
+	agentIdleTimeout = time.Minute
+	agentCheckPeriod = time.Second
+	agentStartWait   = 5 * time.Second
+
+	agentDirPerms = 0o700
+)
+
+var ErrAgentNotRunning = errors.New("the key agent is not running")
+
+// agentRequest is a request to the key agent, sent on a connection of its own
+type agentRequest struct {
+	Op      string        `json:"op"`
+	KeyPath string        `json:"keyPath,omitempty"`
+	Key     string        `json:"key,omitempty"`
+	TTL     time.Duration `json:"ttl,omitempty"`
+}
+
+type agentResponse struct {
+	Key     string               `json:"key,omitempty"`
+	Expires map[string]time.Time `json:"expires,omitempty"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// agentKey is a private key held by the agent until it expires
+type agentKey struct {
+	raw     []byte
+	expires time.Time
+}
+
+// Agent holds unlocked private keys in memory for a limited time, and hands
+// them out to the commands of the session over a unix socket, only reachable
+// by the user
+type Agent struct {
+	lock         sync.Mutex
+	keys         map[string]*agentKey
+	lastActivity time.Time
+}
+
+// ServeAgent runs an agent listening on socketPath, until ctx is done or it
+// has held no key for a minute. The keys are wiped from memory on exit.
+func ServeAgent(ctx context.Context, socketPath string) error {
+	if err := os.MkdirAll(filepath.Dir(socketPath), agentDirPerms); err != nil {
+		return err
+	}
+	// the directory of the socket keeps other users away from it
+	if err := os.Chmod(filepath.Dir(socketPath), agentDirPerms); err != nil {
+		return err
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	a := &Agent{keys: map[string]*agentKey{}, lastActivity: time.Now()}
+	defer a.close()
+	go func() {
+		ticker := time.NewTicker(agentCheckPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = ln.Close()
+				return
+			case <-ticker.C:
+				if a.expire() {
+					_ = ln.Close()
+					return
+				}
+			}
+		}
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			// closed on exit
+			return nil
+		}
+		go a.serve(conn)
+	}
+}
+
+func (a *Agent) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(agentDialTimeout))
+	var req agentRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	resp, err := a.handle(req)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+func (a *Agent) handle(req agentRequest) (agentResponse, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.lastActivity = time.Now()
+	switch req.Op {
+	case agentOpGet:
+		k, ok := a.keys[req.KeyPath]
+		if !ok || time.Now().After(k.expires) {
+			return agentResponse{}, nil
+		}
+		return agentResponse{Key: hex.EncodeToString(k.raw)}, nil
+	case agentOpAdd:
+		raw, err := hex.DecodeString(req.Key)
+		if err != nil {
+			return agentResponse{}, err
+		}
+		a.remove(req.KeyPath)
+		// keep the key out of swap, if the memory lock limit allows it
+		_ = syscall.Mlock(raw)
+		a.keys[req.KeyPath] = &agentKey{raw: raw, expires: time.Now().Add(req.TTL)}
+		return agentResponse{}, nil
+	case agentOpRemove:
+		if req.KeyPath == "" {
+			a.removeAll()
+		} else {
+			a.remove(req.KeyPath)
+		}
+		return agentResponse{}, nil
+	case agentOpList:
+		expires := map[string]time.Time{}
+		for keyPath, k := range a.keys {
+			expires[keyPath] = k.expires
+		}
+		return agentResponse{Expires: expires}, nil
+	}
+	return agentResponse{}, fmt.Errorf("unknown operation %q", req.Op)
+}
+
+// expire wipes the expired keys, and returns true if the agent has been
+// idle for long enough to exit
+func (a *Agent) expire() bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	now := time.Now()
+	for keyPath, k := range a.keys {
+		if now.After(k.expires) {
+			a.remove(keyPath)
+			a.lastActivity = now
+		}
+	}
+	return len(a.keys) == 0 && now.Sub(a.lastActivity) > agentIdleTimeout
+}
+
+// close wipes all the keys from memory
+func (a *Agent) close() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.removeAll()
+}
+
+// removeAll wipes all the keys from memory. The caller holds the lock.
+func (a *Agent) removeAll() {
+	for keyPath := range a.keys {
+		a.remove(keyPath)
+	}
+}
+
+// remove wipes the key of keyPath from memory. The caller holds the lock.
+func (a *Agent) remove(keyPath string) {
+	k, ok := a.keys[keyPath]
+	if !ok {
+		return
+	}
+	for i := range k.raw {
+		k.raw[i] = 0
+	}
+	_ = syscall.Munlock(k.raw)
+	delete(a.keys, keyPath)
+}
+
+// AgentClient talks to the key agent listening on a unix socket
+type AgentClient struct {
+	socketPath string
+}
+
+func NewAgentClient(socketPath string) *AgentClient {
+	return &AgentClient{socketPath: socketPath}
+}
+
+func (c *AgentClient) call(req agentRequest) (agentResponse, error) {
+	var resp agentResponse
+	conn, err := net.DialTimeout("unix", c.socketPath, agentDialTimeout)
+	if err != nil {
+		return resp, ErrAgentNotRunning
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(agentDialTimeout))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, err
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// Running returns true if the agent is listening
+func (c *AgentClient) Running() bool {
+	_, err := c.call(agentRequest{Op: agentOpList})
+	return err == nil
+}
+
+// Get returns the private key of keyPath held by the agent, or nil
+func (c *AgentClient) Get(keyPath string) ([]byte, error) {
+	resp, err := c.call(agentRequest{Op: agentOpGet, KeyPath: keyPath})
+	if err != nil || resp.Key == "" {
+		return nil, err
+	}
+	return hex.DecodeString(resp.Key)
+}
+
+// Add hands the private key of keyPath to the agent, for ttl
+func (c *AgentClient) Add(keyPath string, raw []byte, ttl time.Duration) error {
+	_, err := c.call(agentRequest{Op: agentOpAdd, KeyPath: keyPath, Key: hex.EncodeToString(raw), TTL: ttl})
+	return err
+}
+
+// Remove wipes the private key of keyPath from the agent, or all of them if
+// keyPath is empty
+func (c *AgentClient) Remove(keyPath string) error {
+	_, err := c.call(agentRequest{Op: agentOpRemove, KeyPath: keyPath})
+	return err
+}
+
+// List returns when the keys held by the agent expire, by key path
+func (c *AgentClient) List() (map[string]time.Time, error) {
+	resp, err := c.call(agentRequest{Op: agentOpList})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Expires, nil
+}
+
+// StartAgentProcess starts the agent listening on socketPath as a reentrant
+// process of this binary, and waits for it to listen.
+// It just executes `avalanche key agent`
+func StartAgentProcess(socketPath string) (*AgentClient, error) {
+	cmd := exec.Command(reexec.Self(), "key", "agent", "--socket", socketPath)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	// the agent outlives this process
+	_ = cmd.Process.Release()
+	c := NewAgentClient(socketPath)
+	for start := time.Now(); time.Since(start) < agentStartWait; time.Sleep(100 * time.Millisecond) {
+		if c.Running() {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("the key agent didn't start listening on %s", socketPath)
+}
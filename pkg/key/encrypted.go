@@ -0,0 +1,174 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/secret"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+)
+
+const encryptedKeyVersion = 1
+
+var (
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrLocked is returned when loading an encrypted key without a way to
+	// unlock it
+	ErrLocked = errors.New("the key is encrypted and can't be unlocked here")
+)
+
+// encryptedKeyFile is the content of a key file encrypted with a passphrase.
+// The addresses are kept in clear, to list the key without unlocking it.
+type encryptedKeyFile struct {
+	Version  int    `json:"version"`
+	Address  string `json:"address"`
+	CAddress string `json:"cAddress"`
+	Salt     string `json:"salt"`
+	Sealed   string `json:"sealed"`
+}
+
+// SaveEncrypted saves the private key to disk, encrypted with passphrase
+func (m *SoftKey) SaveEncrypted(p string, passphrase string) error {
+	salt, err := secret.NewSalt()
+	if err != nil {
+		return err
+	}
+	encryptionKey, err := secret.DeriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	sealed, err := secret.Seal(encryptionKey, m.privKeyRaw)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(encryptedKeyFile{
+		Version:  encryptedKeyVersion,
+		Address:  m.privKey.PublicKey().Address().String(),
+		CAddress: m.C(),
+		Salt:     hex.EncodeToString(salt),
+		Sealed:   hex.EncodeToString(sealed),
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, content, fsModeWrite)
+}
+
+// IsEncrypted returns true if the key file at keyPath is encrypted
+func IsEncrypted(keyPath string) (bool, error) {
+	content, err := os.ReadFile(keyPath)
+	if err != nil {
+		return false, err
+	}
+	_, ok := parseEncryptedKeyFile(content)
+	return ok, nil
+}
+
+// parseEncryptedKeyFile returns the content of an encrypted key file, and
+// false if content is not one
+func parseEncryptedKeyFile(content []byte) (encryptedKeyFile, bool) {
+	var f encryptedKeyFile
+	if !bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return f, false
+	}
+	if err := json.Unmarshal(content, &f); err != nil || f.Version == 0 {
+		return f, false
+	}
+	return f, true
+}
+
+// decrypt returns the private key of the file, encrypted with passphrase
+func (f encryptedKeyFile) decrypt(passphrase string) (*crypto.PrivateKeySECP256K1R, error) {
+	if f.Version != encryptedKeyVersion {
+		return nil, fmt.Errorf("unsupported encrypted key version %d", f.Version)
+	}
+	salt, err := hex.DecodeString(f.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt in encrypted key: %w", err)
+	}
+	sealed, err := hex.DecodeString(f.Sealed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted key: %w", err)
+	}
+	encryptionKey, err := secret.DeriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	skBytes, err := secret.Open(encryptionKey, sealed)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return toPrivateKey(skBytes)
+}
+
+func toPrivateKey(skBytes []byte) (*crypto.PrivateKeySECP256K1R, error) {
+	rpk, err := keyFactory.ToPrivateKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	privKey, ok := rpk.(*crypto.PrivateKeySECP256K1R)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	return privKey, nil
+}
+
+// LockedKey holds the addresses of an encrypted key, readable without
+// unlocking it
+type LockedKey struct {
+	pAddr string
+	cAddr string
+}
+
+// LoadLocked loads the addresses of the encrypted key at keyPath
+func LoadLocked(networkID uint32, keyPath string) (*LockedKey, error) {
+	content, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := parseEncryptedKeyFile(content)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an encrypted key", keyPath)
+	}
+	addr, err := ids.ShortFromString(f.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address in encrypted key: %w", err)
+	}
+	pAddr, err := address.Format("P", getHRP(networkID), addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &LockedKey{pAddr: pAddr, cAddr: f.CAddress}, nil
+}
+
+func (k *LockedKey) P() []string { return []string{k.pAddr} }
+
+func (k *LockedKey) C() string { return k.cAddr }
+
+// Addresser gives the addresses of a key
+type Addresser interface {
+	P() []string
+	C() string
+}
+
+// LoadSoftAddresses loads the addresses of the local key at keyPath, without
+// unlocking it if it is encrypted
+func LoadSoftAddresses(networkID uint32, keyPath string) (Addresser, error) {
+	encrypted, err := IsEncrypted(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted {
+		return LoadLocked(networkID, keyPath)
+	}
+	return LoadSoft(networkID, keyPath)
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedKey(t *testing.T) {
+	assert := assert.New(t)
+	defer SetUnlocker(nil)
+
+	k, err := NewSoft(fallbackNetworkID)
+	assert.NoError(err)
+	keyPath := filepath.Join(t.TempDir(), "key.pk")
+	assert.NoError(k.SaveEncrypted(keyPath, "secret"))
+
+	encrypted, err := IsEncrypted(keyPath)
+	assert.NoError(err)
+	assert.True(encrypted)
+
+	// the addresses are readable while locked
+	locked, err := LoadSoftAddresses(fallbackNetworkID, keyPath)
+	assert.NoError(err)
+	assert.Equal(k.P(), locked.P())
+	assert.Equal(k.C(), locked.C())
+
+	SetUnlocker(nil)
+	_, err = LoadSoft(fallbackNetworkID, keyPath)
+	assert.ErrorIs(err, ErrLocked)
+
+	passphrase := "wrong"
+	SetUnlocker(&Unlocker{
+		Passphrase:  func(string) (string, error) { return passphrase, nil },
+		AgentSocket: filepath.Join(t.TempDir(), "agent.sock"),
+	})
+	_, err = LoadSoft(fallbackNetworkID, keyPath)
+	assert.ErrorIs(err, ErrWrongPassphrase)
+
+	passphrase = "secret"
+	unlocked, err := LoadSoft(fallbackNetworkID, keyPath)
+	assert.NoError(err)
+	assert.Equal(k.Raw(), unlocked.Raw())
+}
+
+func TestUnlockWithAgent(t *testing.T) {
+	assert := assert.New(t)
+	defer SetUnlocker(nil)
+
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "agent", "agent.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error)
+	go func() {
+		served <- ServeAgent(ctx, socketPath)
+	}()
+	agent := NewAgentClient(socketPath)
+	assert.Eventually(agent.Running, time.Second, 10*time.Millisecond)
+
+	k, err := NewSoft(fallbackNetworkID)
+	assert.NoError(err)
+	keyPath := filepath.Join(dir, "key.pk")
+	assert.NoError(k.SaveEncrypted(keyPath, "secret"))
+
+	prompts := 0
+	SetUnlocker(&Unlocker{
+		Passphrase: func(string) (string, error) {
+			prompts++
+			return "secret", nil
+		},
+		AgentSocket: socketPath,
+		TTL:         time.Minute,
+	})
+	for i := 0; i < 2; i++ {
+		unlocked, err := LoadSoft(fallbackNetworkID, keyPath)
+		assert.NoError(err)
+		assert.Equal(k.Raw(), unlocked.Raw())
+	}
+	// the second load got the key from the agent
+	assert.Equal(1, prompts)
+
+	assert.NoError(Lock(keyPath))
+	_, err = LoadSoft(fallbackNetworkID, keyPath)
+	assert.NoError(err)
+	assert.Equal(2, prompts)
+
+	// a key file replaced since it was unlocked is unlocked again
+	k2, err := NewSoft(fallbackNetworkID)
+	assert.NoError(err)
+	assert.NoError(k2.SaveEncrypted(keyPath, "secret"))
+	unlocked, err := LoadSoft(fallbackNetworkID, keyPath)
+	assert.NoError(err)
+	assert.Equal(k2.Raw(), unlocked.Raw())
+	assert.Equal(3, prompts)
+
+	cancel()
+	assert.NoError(<-served)
+	assert.False(agent.Running())
+}
+
+func TestAgentExpiry(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &Agent{keys: map[string]*agentKey{}, lastActivity: time.Now()}
+	_, err := a.handle(agentRequest{Op: agentOpAdd, KeyPath: "/k", Key: "0102", TTL: time.Hour})
+	assert.NoError(err)
+	_, err = a.handle(agentRequest{Op: agentOpAdd, KeyPath: "/expired", Key: "0304", TTL: -time.Second})
+	assert.NoError(err)
+	expired := a.keys["/expired"].raw
+
+	resp, err := a.handle(agentRequest{Op: agentOpGet, KeyPath: "/expired"})
+	assert.NoError(err)
+	assert.Empty(resp.Key)
+
+	assert.False(a.expire())
+	assert.NotContains(a.keys, "/expired")
+	// wiped from memory
+	assert.Equal([]byte{0, 0}, expired)
+
+	resp, err = a.handle(agentRequest{Op: agentOpGet, KeyPath: "/k"})
+	assert.NoError(err)
+	assert.Equal("0102", resp.Key)
+
+	_, err = a.handle(agentRequest{Op: "unknown"})
+	assert.Error(err)
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+)
+
+// EwoqAddress returns the address of the publicly known ewoq key, the same
+// on the P-Chain of every network
+func EwoqAddress() ids.ShortID {
+	sk, err := decodePrivateKey(EwoqPrivateKey)
+	if err != nil {
+		// EwoqPrivateKey is a valid constant
+		panic(err)
+	}
+	return sk.PublicKey().Address()
+}
+
+// IsEwoq returns true if k is the publicly known ewoq key, whose funds
+// anyone can take
+func IsEwoq(k *SoftKey) bool {
+	return k.privKeyEncoded == EwoqPrivateKey
+}
+
+// IsEwoqAddress returns true if addr is the address of the ewoq key on the
+// P-Chain or the X-Chain of any network, e.g. P-fuji18jma8ppw3nhx5r4ap8clazz0dps7rv5u6wmu4t
+func IsEwoqAddress(addr string) bool {
+	id, err := address.ParseToID(addr)
+	return err == nil && id == EwoqAddress()
+}
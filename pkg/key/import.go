@@ -0,0 +1,89 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package key
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/cb58"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+// Format is an encoding of a private key
+type Format string
+
+const (
+	// FormatHex is the 32 bytes of the key in hex, with or without 0x, as
+	// exported by MetaMask and written in key files
+	FormatHex Format = "hex"
+	// FormatCB58 is the key in CB58, with or without the PrivateKey- prefix
+	// of avalanchego, as in EwoqPrivateKey
+	FormatCB58 Format = "cb58"
+)
+
+// privKeyBytes is the length of a secp256k1 private key
+const privKeyBytes = privKeySize / 2
+
+var ErrUnknownKeyFormat = errors.New("unrecognized private key encoding, expected 64 hex characters with or without 0x, CB58, or PrivateKey- followed by CB58")
+
+// ParsePrivateKey decodes a private key given in format, or in any of the
+// supported formats if format is empty, and returns the format it was in.
+// Surrounding whitespace is ignored.
+func ParsePrivateKey(encoded string, format Format) (*crypto.PrivateKeySECP256K1R, Format, error) {
+	encoded = strings.TrimSpace(encoded)
+	if format == "" {
+		format = detectFormat(encoded)
+		if format == "" {
+			return nil, "", ErrUnknownKeyFormat
+		}
+	}
+	var (
+		skBytes []byte
+		err     error
+	)
+	switch format {
+	case FormatHex:
+		skBytes, err = hex.DecodeString(trimHexPrefix(encoded))
+		if err == nil && len(skBytes) != privKeyBytes {
+			err = ErrInvalidPrivateKeyLen
+		}
+	case FormatCB58:
+		skBytes, err = cb58.Decode(strings.TrimPrefix(encoded, privKeyEncPfx))
+	default:
+		return nil, "", fmt.Errorf("unsupported private key format %q", format)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s private key: %w", format, err)
+	}
+	privKey, err := toPrivateKey(skBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s private key: %w", format, err)
+	}
+	return privKey, format, nil
+}
+
+// detectFormat returns the format encoded is in, or an empty format if it
+// is in none of them
+func detectFormat(encoded string) Format {
+	if strings.HasPrefix(encoded, privKeyEncPfx) {
+		return FormatCB58
+	}
+	raw := trimHexPrefix(encoded)
+	if _, err := hex.DecodeString(raw); err == nil && len(raw) == privKeySize {
+		return FormatHex
+	}
+	if _, err := cb58.Decode(encoded); err == nil {
+		return FormatCB58
+	}
+	return ""
+}
+
+func trimHexPrefix(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s[2:]
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	assert := assert.New(t)
+
+	ewoq, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	assert.NoError(err)
+	ewoqHex := hex.EncodeToString(ewoq.Raw())
+
+	for _, tc := range []struct {
+		encoded string
+		format  Format
+	}{
+		// as exported by MetaMask
+		{ewoqHex, FormatHex},
+		{"0x" + ewoqHex, FormatHex},
+		{"0X" + strings.ToUpper(ewoqHex) + "\n", FormatHex},
+		{EwoqPrivateKey, FormatCB58},
+		{rawEwoqPk, FormatCB58},
+	} {
+		privKey, format, err := ParsePrivateKey(tc.encoded, "")
+		assert.NoError(err, tc.encoded)
+		assert.Equal(tc.format, format, tc.encoded)
+		assert.Equal(ewoq.Raw(), privKey.Bytes(), tc.encoded)
+
+		_, _, err = ParsePrivateKey(tc.encoded, tc.format)
+		assert.NoError(err, tc.encoded)
+	}
+
+	_, _, err = ParsePrivateKey("not a key", "")
+	assert.ErrorIs(err, ErrUnknownKeyFormat)
+	// a valid key in another format than the given one
+	_, _, err = ParsePrivateKey(ewoqHex, FormatCB58)
+	assert.Error(err)
+	_, _, err = ParsePrivateKey(EwoqPrivateKey, FormatHex)
+	assert.Error(err)
+	_, _, err = ParsePrivateKey("0x"+ewoqHex[2:], FormatHex)
+	assert.ErrorIs(err, ErrInvalidPrivateKeyLen)
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+
+// cliRunner runs a CLI tool with the given arguments and returns its output
+type cliRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func runCLI(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("%s is required to use the remote key, but it was not found in the PATH", name)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// awsKMSSigner signs with an AWS KMS key through the aws CLI, using its
+// configured credentials
+type awsKMSSigner struct {
+	keyID string
+	run   cliRunner
+}
+
+func (s *awsKMSSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	out, err := s.run(ctx, "aws", "kms", "get-public-key",
+		"--key-id", s.keyID,
+		"--output", "text",
+		"--query", "PublicKey",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (s *awsKMSSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	// fileb:// passes the raw bytes with both versions of the aws CLI
+	dir, err := os.MkdirTemp("", "avalanche-cli-kms")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	digestFile := filepath.Join(dir, "digest")
+	if err := os.WriteFile(digestFile, digest, fsModeWrite); err != nil {
+		return nil, err
+	}
+	out, err := s.run(ctx, "aws", "kms", "sign",
+		"--key-id", s.keyID,
+		"--message", "fileb://"+digestFile,
+		"--message-type", "DIGEST",
+		"--signing-algorithm", "ECDSA_SHA_256",
+		"--output", "text",
+		"--query", "Signature",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+}
+
+// gcpKMSSigner signs with a GCP KMS key version through the KMS REST API,
+// authenticated with the access token of the gcloud CLI
+type gcpKMSSigner struct {
+	keyVersion string
+	run        cliRunner
+	endpoint   string
+	client     *http.Client
+}
+
+func (s *gcpKMSSigner) call(ctx context.Context, method string, path string, body interface{}, resp interface{}) error {
+	token, err := s.run(ctx, "gcloud", "auth", "print-access-token")
+	if err != nil {
+		return err
+	}
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = gcpKMSEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Content-Type", "application/json")
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
+		return fmt.Errorf("GCP KMS returned %s: %s", httpResp.Status, apiErr.Error.Message)
+	}
+	return json.NewDecoder(httpResp.Body).Decode(resp)
+}
+
+func (s *gcpKMSSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	var resp struct {
+		PEM string `json:"pem"`
+	}
+	if err := s.call(ctx, http.MethodGet, s.keyVersion+"/publicKey", nil, &resp); err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(resp.PEM))
+	if block == nil {
+		return nil, errors.New("invalid PEM public key from GCP KMS")
+	}
+	return block.Bytes, nil
+}
+
+func (s *gcpKMSSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"digest": map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest)},
+	}
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	if err := s.call(ctx, http.MethodPost, s.keyVersion+":asymmetricSign", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Signature)
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+
+	eth_crypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// AWSKMSProvider signs with an AWS KMS key of spec ECC_SECG_P256K1
+	AWSKMSProvider = "aws-kms"
+	// GCPKMSProvider signs with a GCP KMS key version of algorithm
+	// EC_SIGN_SECP256K1_SHA256
+	GCPKMSProvider = "gcp-kms"
+)
+
+var (
+	ErrUnknownProvider      = errors.New("unknown remote signer provider")
+	ErrNotSECP256K1         = errors.New("the remote key is not a secp256k1 key")
+	ErrInvalidRemoteSig     = errors.New("invalid signature from the remote signer")
+	ErrRemotePubKeyMismatch = errors.New("the public key of the remote key does not match its configuration")
+
+	oidSECP256K1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// RemoteKeyConfig is the stored configuration of a key held by a remote
+// signer, such as a cloud KMS. The private key never leaves the signer.
+type RemoteKeyConfig struct {
+	Provider string `json:"provider"`
+	// KeyID is the AWS KMS key ID or ARN, or the GCP KMS key version
+	// resource name
+	KeyID string `json:"keyID"`
+	// PublicKey is the compressed public key, in hex, cached on creation
+	// so that addresses can be shown without reaching the signer
+	PublicKey string `json:"publicKey"`
+}
+
+// RemoteSigner signs digests with a secp256k1 key it holds
+type RemoteSigner interface {
+	// PublicKey returns the DER encoded SubjectPublicKeyInfo of the key
+	PublicKey(ctx context.Context) ([]byte, error)
+	// SignDigest returns the DER encoded ECDSA signature of the digest
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// NewRemoteSigner returns the signer of the provider for the key keyID
+func NewRemoteSigner(provider string, keyID string) (RemoteSigner, error) {
+	switch provider {
+	case AWSKMSProvider:
+		return &awsKMSSigner{keyID: keyID, run: runCLI}, nil
+	case GCPKMSProvider:
+		return &gcpKMSSigner{keyVersion: keyID, run: runCLI}, nil
+	}
+	return nil, fmt.Errorf("%w %q, expected %s or %s", ErrUnknownProvider, provider, AWSKMSProvider, GCPKMSProvider)
+}
+
+// RemoteKey is a key held by a remote signer
+type RemoteKey struct {
+	signer RemoteSigner
+	pubKey *crypto.PublicKeySECP256K1R
+	pAddr  string
+}
+
+// NewRemoteKey returns the key of signer, fetching its public key
+func NewRemoteKey(ctx context.Context, networkID uint32, signer RemoteSigner) (*RemoteKey, error) {
+	der, err := signer.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching the public key of the remote key: %w", err)
+	}
+	pubKey, err := parseSECP256K1PublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	return newRemoteKey(networkID, signer, pubKey)
+}
+
+func newRemoteKey(networkID uint32, signer RemoteSigner, pubKey *crypto.PublicKeySECP256K1R) (*RemoteKey, error) {
+	pAddr, err := address.Format("P", getHRP(networkID), pubKey.Address().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteKey{
+		signer: signer,
+		pubKey: pubKey,
+		pAddr:  pAddr,
+	}, nil
+}
+
+// LoadRemote loads the remote key configured at keyPath
+func LoadRemote(networkID uint32, keyPath string) (*RemoteKey, error) {
+	cfg, err := LoadRemoteKeyConfig(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := NewRemoteSigner(cfg.Provider, cfg.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	pubKeyBytes, err := hex.DecodeString(cfg.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", keyPath, err)
+	}
+	pubKey, err := keyFactory.ToPublicKey(pubKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", keyPath, err)
+	}
+	return newRemoteKey(networkID, signer, pubKey.(*crypto.PublicKeySECP256K1R))
+}
+
+// LoadRemoteKeyConfig reads the remote key configuration at keyPath
+func LoadRemoteKeyConfig(keyPath string) (RemoteKeyConfig, error) {
+	var cfg RemoteKeyConfig
+	b, err := os.ReadFile(keyPath)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed parsing remote key %s: %w", keyPath, err)
+	}
+	return cfg, nil
+}
+
+// Config returns the configuration of the key, to be stored
+func (k *RemoteKey) Config(provider string, keyID string) RemoteKeyConfig {
+	return RemoteKeyConfig{
+		Provider:  provider,
+		KeyID:     keyID,
+		PublicKey: hex.EncodeToString(k.pubKey.Bytes()),
+	}
+}
+
+// Save writes the configuration of the key to keyPath
+func (k *RemoteKey) Save(keyPath string, provider string, keyID string) error {
+	b, err := json.MarshalIndent(k.Config(provider, keyID), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(keyPath, b, fsModeWrite)
+}
+
+// P returns the formatted P-Chain address of the key
+func (k *RemoteKey) P() []string {
+	return []string{k.pAddr}
+}
+
+// C returns the C-Chain address of the key in Ethereum format
+func (k *RemoteKey) C() string {
+	return eth_crypto.PubkeyToAddress(*k.pubKey.ToECDSA()).Hex()
+}
+
+// Address returns the raw address of the key
+func (k *RemoteKey) Address() ids.ShortID {
+	return k.pubKey.Address()
+}
+
+// SignHash returns the recoverable signature [r || s || v] of hash, as
+// expected in P-Chain credentials
+func (k *RemoteKey) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	der, err := k.signer.SignDigest(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer failed: %w", err)
+	}
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRemoteSig, err)
+	}
+	// only the low S form is valid on the P-Chain
+	n := eth_crypto.S256().Params().N
+	if sig.S.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		sig.S = new(big.Int).Sub(n, sig.S)
+	}
+
+	rsv := make([]byte, crypto.SECP256K1RSigLen)
+	sig.R.FillBytes(rsv[:32])
+	sig.S.FillBytes(rsv[32:64])
+	// the signer does not tell the recovery ID, find the one giving our key
+	for v := byte(0); v < 2; v++ {
+		rsv[64] = v
+		pubKey, err := keyFactory.RecoverHashPublicKey(hash, rsv)
+		if err == nil && pubKey.Address() == k.pubKey.Address() {
+			return rsv, nil
+		}
+	}
+	return nil, ErrRemotePubKeyMismatch
+}
+
+// parseSECP256K1PublicKey parses a DER encoded SubjectPublicKeyInfo of a
+// secp256k1 key, which the standard library does not support
+func parseSECP256K1PublicKey(der []byte) (*crypto.PublicKeySECP256K1R, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSECP256K1) {
+		return nil, ErrNotSECP256K1
+	}
+	pubKey, err := keyFactory.ToPublicKey(spki.PublicKey.RightAlign())
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	// addresses are derived from the compressed form
+	compressed := eth_crypto.CompressPubkey(pubKey.(*crypto.PublicKeySECP256K1R).ToECDSA())
+	pubKey, err = keyFactory.ToPublicKey(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return pubKey.(*crypto.PublicKeySECP256K1R), nil
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	eth_crypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKMS holds a local key, and signs the way a KMS does
+type fakeKMS struct {
+	sk *ecdsa.PrivateKey
+	// return the high S form of the signatures
+	highS bool
+}
+
+func newFakeKMS(t *testing.T) *fakeKMS {
+	sk, err := eth_crypto.GenerateKey()
+	assert.NoError(t, err)
+	return &fakeKMS{sk: sk}
+}
+
+func (f *fakeKMS) PublicKey(context.Context) ([]byte, error) {
+	params, err := asn1.Marshal(oidSECP256K1)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: eth_crypto.FromECDSAPub(&f.sk.PublicKey), BitLength: 65 * 8},
+	})
+}
+
+func (f *fakeKMS) SignDigest(_ context.Context, digest []byte) ([]byte, error) {
+	rsv, err := eth_crypto.Sign(digest, f.sk)
+	if err != nil {
+		return nil, err
+	}
+	r, s := new(big.Int).SetBytes(rsv[:32]), new(big.Int).SetBytes(rsv[32:64])
+	if f.highS {
+		s = new(big.Int).Sub(eth_crypto.S256().Params().N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func TestRemoteKeySignHash(t *testing.T) {
+	assert := assert.New(t)
+
+	kms := newFakeKMS(t)
+	k, err := NewRemoteKey(context.Background(), fallbackNetworkID, kms)
+	assert.NoError(err)
+	assert.Equal(eth_crypto.PubkeyToAddress(kms.sk.PublicKey).Hex(), k.C())
+
+	hash := hashing.ComputeHash256([]byte("unsigned tx"))
+	for _, highS := range []bool{false, true} {
+		kms.highS = highS
+		sig, err := k.SignHash(context.Background(), hash)
+		assert.NoError(err)
+		// valid P-Chain signatures recover the address of the key
+		pubKey, err := keyFactory.RecoverHashPublicKey(hash, sig)
+		assert.NoError(err)
+		assert.Equal(k.Address(), pubKey.Address())
+	}
+
+	// a signer holding another key is detected
+	other, err := newRemoteKey(fallbackNetworkID, newFakeKMS(t), k.pubKey)
+	assert.NoError(err)
+	_, err = other.SignHash(context.Background(), hash)
+	assert.ErrorIs(err, ErrRemotePubKeyMismatch)
+}
+
+func TestRemoteKeySaveLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	k, err := NewRemoteKey(context.Background(), fallbackNetworkID, newFakeKMS(t))
+	assert.NoError(err)
+	keyPath := filepath.Join(t.TempDir(), "key.remote.json")
+	assert.NoError(k.Save(keyPath, AWSKMSProvider, "alias/deployer"))
+
+	loaded, err := LoadRemote(fallbackNetworkID, keyPath)
+	assert.NoError(err)
+	assert.Equal(k.P(), loaded.P())
+	assert.Equal(k.Address(), loaded.Address())
+	assert.IsType(&awsKMSSigner{}, loaded.signer)
+
+	_, err = NewRemoteSigner("vault", "key")
+	assert.ErrorIs(err, ErrUnknownProvider)
+}
+
+func TestAWSKMSSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	kms := newFakeKMS(t)
+	calls := [][]string{}
+	signer := &awsKMSSigner{
+		keyID: "alias/deployer",
+		run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			calls = append(calls, append([]string{name}, args...))
+			var out []byte
+			var err error
+			switch args[1] {
+			case "get-public-key":
+				out, err = kms.PublicKey(ctx)
+			case "sign":
+				out, err = kms.SignDigest(ctx, make([]byte, 32))
+			}
+			return []byte(base64.StdEncoding.EncodeToString(out) + "\n"), err
+		},
+	}
+	k, err := NewRemoteKey(context.Background(), fallbackNetworkID, signer)
+	assert.NoError(err)
+	_, err = k.SignHash(context.Background(), make([]byte, 32))
+	assert.NoError(err)
+
+	assert.Len(calls, 2)
+	sign := strings.Join(calls[1], " ")
+	assert.Contains(sign, "--key-id alias/deployer")
+	assert.Contains(sign, "--message-type DIGEST --signing-algorithm ECDSA_SHA_256")
+}
+
+func TestGCPKMSSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	kms := newFakeKMS(t)
+	version := "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/" + version + "/publicKey":
+			der, _ := kms.PublicKey(r.Context())
+			fmt.Fprintf(w, `{"pem": %q}`, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		case "/" + version + ":asymmetricSign":
+			sig, _ := kms.SignDigest(r.Context(), make([]byte, 32))
+			fmt.Fprintf(w, `{"signature": %q}`, base64.StdEncoding.EncodeToString(sig))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"message": "not found"}}`)
+		}
+	}))
+	defer s.Close()
+
+	signer := &gcpKMSSigner{
+		keyVersion: version,
+		endpoint:   s.URL + "/",
+		run: func(context.Context, string, ...string) ([]byte, error) {
+			return []byte("token\n"), nil
+		},
+	}
+	k, err := NewRemoteKey(context.Background(), fallbackNetworkID, signer)
+	assert.NoError(err)
+	_, err = k.SignHash(context.Background(), make([]byte, 32))
+	assert.NoError(err)
+
+	signer.keyVersion = "unknown"
+	_, err = signer.PublicKey(context.Background())
+	assert.ErrorContains(err, "not found")
+}
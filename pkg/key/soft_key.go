@@ -146,12 +146,21 @@ func NewSoft(networkID uint32, opts ...SOpOption) (*SoftKey, error) {
 }
 
 // LoadSoft loads the private key from disk and creates the corresponding SoftKey.
+// Encrypted keys are unlocked with the Unlocker set with SetUnlocker.
 func LoadSoft(networkID uint32, keyPath string) (*SoftKey, error) {
 	kb, err := os.ReadFile(keyPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if f, ok := parseEncryptedKeyFile(kb); ok {
+		privKey, err := loadEncrypted(keyPath, f)
+		if err != nil {
+			return nil, err
+		}
+		return NewSoft(networkID, WithPrivateKey(privKey))
+	}
+
 	// in case, it's already encoded
 	k, err := NewSoft(networkID, WithPrivateKeyEncoded(string(kb)))
 	if err == nil {
@@ -0,0 +1,141 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/crypto"
+)
+
+// Unlocker unlocks the encrypted keys loaded by a command. The unlocked keys
+// are held by the key agent for a while, so that the following commands of
+// the session don't prompt for the passphrase again.
+type Unlocker struct {
+	// Passphrase prompts for the passphrase of the key at keyPath
+	Passphrase func(keyPath string) (string, error)
+	// AgentSocket is the socket of the key agent
+	AgentSocket string
+	// TTL is how long the agent holds an unlocked key, 0 to not hold it
+	TTL time.Duration
+	// Log reports failures to reach the agent, which don't prevent unlocking
+	Log func(format string, args ...interface{})
+}
+
+// unlocker unlocks the encrypted keys, they can't be loaded without one
+var unlocker *Unlocker
+
+// SetUnlocker sets how the encrypted keys loaded by LoadSoft are unlocked
+func SetUnlocker(u *Unlocker) {
+	unlocker = u
+}
+
+// Unlock unlocks the encrypted key at keyPath, prompting for its passphrase
+// unless the agent holds it already, and has the agent hold it for ttl
+func Unlock(keyPath string, ttl time.Duration) error {
+	if unlocker == nil {
+		return ErrLocked
+	}
+	if ttl <= 0 {
+		return errors.New("the unlock duration must be positive")
+	}
+	content, err := os.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+	f, ok := parseEncryptedKeyFile(content)
+	if !ok {
+		return fmt.Errorf("%s is not an encrypted key", keyPath)
+	}
+	u := *unlocker
+	u.TTL = ttl
+	privKey, err := u.unlock(keyPath, f)
+	if err != nil {
+		return err
+	}
+	// held already, refresh its expiry
+	return u.agent().Add(agentKeyPath(keyPath), privKey.Bytes(), ttl)
+}
+
+// Lock has the agent forget the key at keyPath, or all the keys if keyPath is
+// empty
+func Lock(keyPath string) error {
+	if unlocker == nil {
+		return ErrAgentNotRunning
+	}
+	if keyPath != "" {
+		keyPath = agentKeyPath(keyPath)
+	}
+	return unlocker.agent().Remove(keyPath)
+}
+
+func (u *Unlocker) agent() *AgentClient {
+	return NewAgentClient(u.AgentSocket)
+}
+
+// unlock returns the private key of the encrypted key file f at keyPath
+func (u *Unlocker) unlock(keyPath string, f encryptedKeyFile) (*crypto.PrivateKeySECP256K1R, error) {
+	agent := u.agent()
+	path := agentKeyPath(keyPath)
+	raw, err := agent.Get(path)
+	if err != nil && !errors.Is(err, ErrAgentNotRunning) {
+		u.log("failed getting key %s from the key agent: %s", keyPath, err)
+	}
+	if raw != nil {
+		privKey, err := toPrivateKey(raw)
+		// the key file may have been replaced since
+		if err == nil && privKey.PublicKey().Address().String() == f.Address {
+			return privKey, nil
+		}
+	}
+
+	passphrase, err := u.Passphrase(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := f.decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if u.TTL <= 0 {
+		return privKey, nil
+	}
+	if !agent.Running() {
+		if agent, err = StartAgentProcess(u.AgentSocket); err != nil {
+			u.log("failed starting the key agent: %s", err)
+			return privKey, nil
+		}
+	}
+	if err := agent.Add(path, privKey.Bytes(), u.TTL); err != nil {
+		u.log("failed handing key %s to the key agent: %s", keyPath, err)
+	}
+	return privKey, nil
+}
+
+func (u *Unlocker) log(format string, args ...interface{}) {
+	if u.Log != nil {
+		u.Log(format, args...)
+	}
+}
+
+// agentKeyPath is the path the agent holds the key at keyPath by
+func agentKeyPath(keyPath string) string {
+	if abs, err := filepath.Abs(keyPath); err == nil {
+		return abs
+	}
+	return keyPath
+}
+
+// loadEncrypted returns the private key of the encrypted key file f at
+// keyPath
+func loadEncrypted(keyPath string, f encryptedKeyFile) (*crypto.PrivateKeySECP256K1R, error) {
+	if unlocker == nil {
+		return nil, ErrLocked
+	}
+	return unlocker.unlock(keyPath, f)
+}
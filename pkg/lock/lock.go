@@ -0,0 +1,132 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package lock serializes the CLI invocations mutating the app state
+// directory, with an advisory lock on a file in it. The lock is released by
+// the kernel when the process holding it exits, so it can't outlive a crashed
+// invocation. The invocations which can run concurrently with each other
+// take it shared.
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrLocked = errors.New("another avalanche command is running")
+
+// acquirePollInterval is how often AcquireWait retries taking the lock
+const acquirePollInterval = 100 * time.Millisecond
+
+// Lock is an acquired lock
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock on the file at path, creating it if needed, and
+// records the process and command holding it. Fails with ErrLocked if
+// another process holds it.
+func Acquire(path string, command string) (*Lock, error) {
+	return acquire(path, command, syscall.LOCK_EX)
+}
+
+// AcquireShared takes the lock on the file at path as Acquire does, but
+// shared with the other processes taking it shared. Fails with ErrLocked if
+// another process holds it exclusively.
+func AcquireShared(path string, command string) (*Lock, error) {
+	return acquire(path, command, syscall.LOCK_SH)
+}
+
+func acquire(path string, command string, how int) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), how|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w%s", ErrLocked, holder(path))
+		}
+		return nil, fmt.Errorf("failed locking %s: %w", path, err)
+	}
+	// the holder is only informative, failing to record it is not fatal. Of
+	// the processes sharing the lock, the last one is recorded.
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(fmt.Sprintf("%d\n%s\n%s\n", os.Getpid(), time.Now().Format(time.RFC3339), command)), 0)
+	}
+	return &Lock{file: f}, nil
+}
+
+// AcquireWait takes the lock on the file at path as Acquire does, waiting
+// for at most timeout for the process holding it to release it
+func AcquireWait(path string, command string, timeout time.Duration) (*Lock, error) {
+	return acquireWait(path, command, syscall.LOCK_EX, timeout)
+}
+
+// AcquireSharedWait takes the lock on the file at path as AcquireShared
+// does, waiting for at most timeout for the process holding it exclusively
+// to release it
+func AcquireSharedWait(path string, command string, timeout time.Duration) (*Lock, error) {
+	return acquireWait(path, command, syscall.LOCK_SH, timeout)
+}
+
+func acquireWait(path string, command string, how int, timeout time.Duration) (*Lock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		l, err := acquire(path, command, how)
+		if !errors.Is(err, ErrLocked) || time.Now().After(deadline) {
+			return l, err
+		}
+		time.Sleep(acquirePollInterval)
+	}
+}
+
+// Release releases the lock. The lock file is left in place: another
+// process may have opened it already, and would lock a file nobody else sees
+// if it was removed.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	// truncating first, so nobody reads a stale holder
+	_ = l.file.Truncate(0)
+	err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
+
+// ForceUnlock removes the lock file at path, so that the next invocation
+// locks a new one regardless of the process holding the current one. Only
+// meant to recover from a stuck invocation.
+func ForceUnlock(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// holder describes the process holding the lock at path, as recorded by
+// Acquire, or returns an empty string if unknown
+func holder(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.SplitN(strings.TrimSpace(string(content)), "\n", 3)
+	if len(lines) < 3 {
+		return ""
+	}
+	pid, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(" (pid %d, since %s: %s)", pid, lines[1], lines[2])
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquire(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	l, err := Acquire(path, "avalanche subnet deploy")
+	assert.NoError(err)
+
+	_, err = Acquire(path, "avalanche network clean")
+	assert.ErrorIs(err, ErrLocked)
+	assert.Contains(err.Error(), fmt.Sprintf("pid %d", os.Getpid()))
+	assert.Contains(err.Error(), "avalanche subnet deploy")
+
+	assert.NoError(l.Release())
+	l, err = Acquire(path, "avalanche network clean")
+	assert.NoError(err)
+	assert.NoError(l.Release())
+	// releasing twice is harmless
+	assert.NoError(l.Release())
+}
+
+func TestAcquireWait(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	held, err := Acquire(path, "avalanche subnet deploy")
+	assert.NoError(err)
+	_, err = AcquireWait(path, "avalanche subnet deploy", 200*time.Millisecond)
+	assert.ErrorIs(err, ErrLocked)
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		_ = held.Release()
+	})
+	l, err := AcquireWait(path, "avalanche subnet deploy", 5*time.Second)
+	assert.NoError(err)
+	assert.NoError(l.Release())
+}
+
+func TestAcquireShared(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	first, err := AcquireShared(path, "avalanche subnet deploy")
+	assert.NoError(err)
+	second, err := AcquireShared(path, "avalanche subnet deploy")
+	assert.NoError(err)
+	_, err = Acquire(path, "avalanche network clean")
+	assert.ErrorIs(err, ErrLocked)
+
+	assert.NoError(first.Release())
+	_, err = Acquire(path, "avalanche network clean")
+	assert.ErrorIs(err, ErrLocked)
+	assert.NoError(second.Release())
+
+	exclusive, err := Acquire(path, "avalanche network clean")
+	assert.NoError(err)
+	_, err = AcquireSharedWait(path, "avalanche subnet deploy", 200*time.Millisecond)
+	assert.ErrorIs(err, ErrLocked)
+	assert.NoError(exclusive.Release())
+}
+
+func TestForceUnlock(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	stuck, err := Acquire(path, "avalanche subnet deploy")
+	assert.NoError(err)
+	defer stuck.Release()
+
+	assert.NoError(ForceUnlock(path))
+	l, err := Acquire(path, "avalanche network clean")
+	assert.NoError(err)
+	assert.NoError(l.Release())
+
+	// nothing to unlock
+	assert.NoError(ForceUnlock(filepath.Join(t.TempDir(), "missing.lock")))
+}
@@ -2,11 +2,87 @@
 // See the file LICENSE for licensing terms.
 package models
 
-import "github.com/ava-labs/avalanchego/ids"
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+)
 
 type NetworkData struct {
 	SubnetID     ids.ID
 	BlockchainID ids.ID
+	// Governance records who controls the subnet, if it was created by the CLI
+	Governance *SubnetGovernance `json:",omitempty"`
+	// GenesisHash is the SHA-256 of the genesis deployed, before its
+	// template variables are resolved
+	GenesisHash string `json:",omitempty"`
+	// Environment is the project environment deployed to the network
+	Environment string `json:",omitempty"`
+	// PromotedFrom is the environment the deploy was promoted from, whose
+	// genesis it deployed
+	PromotedFrom string `json:",omitempty"`
+	// VMBinaryHash is the SHA-256 of the VM plugin binary deployed, for the
+	// validators to check they run the same
+	VMBinaryHash string `json:",omitempty"`
+}
+
+// ControlKey is an address whose signature can authorize changes to a subnet
+type ControlKey struct {
+	Address string
+	// Key is the name of the local key holding the address, if any
+	Key string `json:",omitempty"`
+	// Holder is who holds the address when it isn't a local key, e.g. a
+	// person or a team
+	Holder string `json:",omitempty"`
+}
+
+// HeldBy returns who holds the address, as a user friendly string
+func (k ControlKey) HeldBy() string {
+	switch {
+	case k.Key != "":
+		return "local key " + k.Key
+	case k.Holder != "":
+		return k.Holder
+	}
+	return "unknown"
+}
+
+// SubnetGovernance is the control keys of a subnet, Threshold of which must
+// sign to add validators or blockchains to it
+type SubnetGovernance struct {
+	ControlKeys []ControlKey
+	Threshold   uint32
+}
+
+// External returns the control keys not held by a local key, whose holders
+// must sign for the subnet as well
+func (g SubnetGovernance) External() []ControlKey {
+	external := []ControlKey{}
+	for _, k := range g.ControlKeys {
+		if k.Key == "" {
+			external = append(external, k)
+		}
+	}
+	return external
+}
+
+// NetworkUpgrade schedules the activation or deactivation of a stateful
+// precompile of a subnet-evm chain after its genesis
+type NetworkUpgrade struct {
+	Precompile string
+	// Timestamp is the unix time of the first block the upgrade applies to
+	Timestamp int64
+	Disable   bool
+	// AdminAddresses of the allow list of the enabled precompile
+	AdminAddresses []string `json:",omitempty"`
+}
+
+// ActivationTime returns the time the upgrade applies from
+func (u NetworkUpgrade) ActivationTime() time.Time {
+	return time.Unix(u.Timestamp, 0)
 }
 
 type Sidecar struct {
@@ -14,7 +90,59 @@ type Sidecar struct {
 	VM        VMType
 	Subnet    string
 	TokenName string
-	ChainID   string
-	Version   string
-	Networks  map[string]NetworkData
+	// TokenDecimals is the number of decimals the native token amounts are
+	// displayed with, constants.DefaultTokenDecimals if nil
+	TokenDecimals *int `json:",omitempty"`
+	ChainID       string
+	Version       string
+	Networks      map[string]NetworkData
+	// VMAlias is the name the VM is registered under, defaults to Name
+	VMAlias string
+	// VMID overrides the VM ID derived from the VM alias, e.g. to match a
+	// blockchain created outside of the CLI
+	VMID string
+	// Upgrades are the precompile upgrades scheduled for the chain, by
+	// activation time
+	Upgrades []NetworkUpgrade `json:",omitempty"`
+	// FundedKeys are the stored keys the genesis funds, by their C-Chain
+	// address, so that the funded accounts can be used
+	FundedKeys map[string]string `json:",omitempty"`
+	// HardwareProfile is the class of machines the validators run on, which
+	// the fees of a subnet EVM chain are sized for, if declared
+	HardwareProfile string `json:",omitempty"`
+}
+
+// GetTokenDecimals returns the number of decimals the native token amounts
+// are displayed with
+func (sc Sidecar) GetTokenDecimals() int {
+	if sc.TokenDecimals == nil {
+		return constants.DefaultTokenDecimals
+	}
+	return *sc.TokenDecimals
+}
+
+// GetVMName returns the name the VM of the chain is registered under
+func (sc Sidecar) GetVMName() string {
+	if sc.VMAlias != "" {
+		return sc.VMAlias
+	}
+	return sc.Name
+}
+
+// GetVMID returns the VM ID of the chain, either the explicit override or
+// the one derived from its VM name
+func (sc Sidecar) GetVMID() (ids.ID, error) {
+	if sc.VMID != "" {
+		vmID, err := ids.FromString(sc.VMID)
+		if err != nil {
+			return ids.Empty, fmt.Errorf("invalid VM ID %q: %w", sc.VMID, err)
+		}
+		return vmID, nil
+	}
+	vmName := sc.GetVMName()
+	vmID, err := utils.VMID(vmName)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed to create VM ID from %s: %w", vmName, err)
+	}
+	return vmID, nil
 }
@@ -0,0 +1,57 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidecarGetVMID(t *testing.T) {
+	assert := assert.New(t)
+
+	sc := Sidecar{Name: "test"}
+	assert.Equal("test", sc.GetVMName())
+	vmID, err := sc.GetVMID()
+	assert.NoError(err)
+	assert.Equal("tGBrM2SXkAdNsqzb3SaFZZWMNdzjjFEUKteheTa4dhUwnfQyu", vmID.String())
+
+	sc.VMAlias = "myvm"
+	assert.Equal("myvm", sc.GetVMName())
+	expected, err := utils.VMID("myvm")
+	assert.NoError(err)
+	vmID, err = sc.GetVMID()
+	assert.NoError(err)
+	assert.Equal(expected, vmID)
+
+	override := ids.GenerateTestID()
+	sc.VMID = override.String()
+	vmID, err = sc.GetVMID()
+	assert.NoError(err)
+	assert.Equal(override, vmID)
+
+	sc.VMID = "not an id"
+	_, err = sc.GetVMID()
+	assert.Error(err)
+}
+
+func TestSubnetGovernanceExternal(t *testing.T) {
+	assert := assert.New(t)
+
+	g := SubnetGovernance{
+		ControlKeys: []ControlKey{
+			{Address: "P-fuji1a", Key: "mykey"},
+			{Address: "P-fuji1b", Holder: "ops team"},
+			{Address: "P-fuji1c"},
+		},
+		Threshold: 2,
+	}
+	external := g.External()
+	assert.Len(external, 2)
+	assert.Equal("ops team", external[0].HeldBy())
+	assert.Equal("unknown", external[1].HeldBy())
+	assert.Equal("local key mykey", g.ControlKeys[0].HeldBy())
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package observability
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetricsPath is the path of the Prometheus metrics of an avalanchego node
+const MetricsPath = "/ext/metrics"
+
+// PrometheusConfig is the subset of the Prometheus configuration file used
+// to scrape avalanchego nodes
+type PrometheusConfig struct {
+	Global        *PrometheusGlobalConfig  `yaml:"global,omitempty"`
+	ScrapeConfigs []PrometheusScrapeConfig `yaml:"scrape_configs"`
+}
+
+type PrometheusGlobalConfig struct {
+	ScrapeInterval string `yaml:"scrape_interval"`
+}
+
+type PrometheusScrapeConfig struct {
+	JobName              string                   `yaml:"job_name"`
+	MetricsPath          string                   `yaml:"metrics_path"`
+	StaticConfigs        []PrometheusStaticConfig `yaml:"static_configs"`
+	MetricRelabelConfigs []PrometheusRelabel      `yaml:"metric_relabel_configs,omitempty"`
+}
+
+type PrometheusStaticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+type PrometheusRelabel struct {
+	SourceLabels []string `yaml:"source_labels"`
+	Regex        string   `yaml:"regex"`
+	Action       string   `yaml:"action"`
+}
+
+// NodeStaticConfigs returns a static config per node, given by name with its
+// URI, labeled with the node name and the extra labels. If targetHost is not
+// empty, it replaces the host of the node URIs, keeping their port.
+func NodeStaticConfigs(nodeURIs map[string]string, targetHost string, labels map[string]string) ([]PrometheusStaticConfig, error) {
+	nodes := make([]string, 0, len(nodeURIs))
+	for name := range nodeURIs {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+
+	staticConfigs := []PrometheusStaticConfig{}
+	for _, name := range nodes {
+		u, err := url.Parse(nodeURIs[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid URI of node %s: %w", name, err)
+		}
+		target := u.Host
+		if targetHost != "" {
+			target = fmt.Sprintf("%s:%s", targetHost, u.Port())
+		}
+		nodeLabels := map[string]string{"node": name}
+		for k, v := range labels {
+			nodeLabels[k] = v
+		}
+		staticConfigs = append(staticConfigs, PrometheusStaticConfig{
+			Targets: []string{target},
+			Labels:  nodeLabels,
+		})
+	}
+	return staticConfigs, nil
+}
+
+// prometheusStackConfig returns the configuration of the Prometheus server of
+// the observability stack, scraping all the metrics of every node
+func prometheusStackConfig(nodeURIs map[string]string, targetHost string) ([]byte, error) {
+	staticConfigs, err := NodeStaticConfigs(nodeURIs, targetHost, nil)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(PrometheusConfig{
+		Global: &PrometheusGlobalConfig{ScrapeInterval: "5s"},
+		ScrapeConfigs: []PrometheusScrapeConfig{{
+			JobName:       "avalanchego",
+			MetricsPath:   MetricsPath,
+			StaticConfigs: staticConfigs,
+		}},
+	})
+}
+
+// grafanaDatasourceConfig returns the Grafana provisioning of the Prometheus
+// datasource
+func grafanaDatasourceConfig(prometheusURL string) ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": 1,
+		"datasources": []map[string]interface{}{{
+			"name":      "Prometheus",
+			"type":      "prometheus",
+			"access":    "proxy",
+			"url":       prometheusURL,
+			"isDefault": true,
+		}},
+	})
+}
+
+// grafanaDashboardsConfig returns the Grafana provisioning of the dashboards
+// found in dashboardsDir
+func grafanaDashboardsConfig(dashboardsDir string) ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": 1,
+		"providers": []map[string]interface{}{{
+			"name":    "avalanche-cli",
+			"type":    "file",
+			"options": map[string]string{"path": dashboardsDir},
+		}},
+	})
+}
+
+// chainMetricQuery returns a query of the per chain metric named
+// avalanche_<chain>_<name>, with the chain set as label
+func chainMetricQuery(name string) string {
+	return fmt.Sprintf(
+		`label_replace({__name__=~"avalanche_.+_%[1]s"}, "chain", "$1", "__name__", "avalanche_(.+)_%[1]s")`,
+		name,
+	)
+}
+
+type dashboardPanel struct {
+	title  string
+	expr   string
+	legend string
+}
+
+// the panels of the local network dashboard
+var dashboardPanels = []dashboardPanel{
+	{"Last accepted height", chainMetricQuery("last_accepted_height"), "{{node}} {{chain}}"},
+	{"Blocks accepted per second", "rate(" + chainMetricQuery("last_accepted_height") + "[1m])", "{{node}} {{chain}}"},
+	{"Tx pool pending", chainMetricQuery("vm_txpool_pending"), "{{node}} {{chain}}"},
+	{"Tx pool queued", chainMetricQuery("vm_txpool_queued"), "{{node}} {{chain}}"},
+	{"Peers", "avalanche_network_peers", "{{node}}"},
+}
+
+// grafanaDashboard returns the Grafana dashboard of the local network nodes
+// and chains
+func grafanaDashboard() ([]byte, error) {
+	panels := []map[string]interface{}{}
+	for i, p := range dashboardPanels {
+		panels = append(panels, map[string]interface{}{
+			"id":         i + 1,
+			"type":       "timeseries",
+			"title":      p.title,
+			"datasource": map[string]string{"type": "prometheus"},
+			"gridPos":    map[string]int{"h": 8, "w": 12, "x": (i % 2) * 12, "y": (i / 2) * 8},
+			"targets": []map[string]string{{
+				"refId":        "A",
+				"expr":         p.expr,
+				"legendFormat": p.legend,
+			}},
+		})
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"uid":           "avalanche-cli-local",
+		"title":         "Avalanche local network",
+		"schemaVersion": 36,
+		"refresh":       "5s",
+		"time":          map[string]string{"from": "now-15m", "to": "now"},
+		"panels":        panels,
+	}, "", "  ")
+}
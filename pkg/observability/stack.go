@@ -0,0 +1,190 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package observability
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+const (
+	prometheusConfigFile = "prometheus.yml"
+	grafanaDir           = "grafana"
+	grafanaDashboardFile = "avalanche.json"
+
+	// paths inside the containers
+	containerPrometheusConfig = "/etc/prometheus/prometheus.yml"
+	containerGrafanaConfig    = "/etc/grafana/provisioning"
+	containerGrafanaDashboard = "/var/lib/grafana/dashboards"
+
+	// host of the docker host from the containers, when not using host networking
+	dockerHost = "host.docker.internal"
+)
+
+var errDockerNotFound = errors.New("docker is required to run the observability stack, but it was not found in the PATH")
+
+// dockerRunner runs the docker CLI with the given arguments
+type dockerRunner func(args ...string) ([]byte, error)
+
+func runDocker(args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, errDockerNotFound
+	}
+	return exec.Command("docker", args...).CombinedOutput()
+}
+
+// Stack is a Prometheus and Grafana stack, run in docker, scraping the nodes
+// of a local network
+type Stack struct {
+	dir            string
+	profile        string
+	prometheusPort uint16
+	grafanaPort    uint16
+	// avalanchego only listens on the loopback interface, which the
+	// containers can only reach through the host network on linux
+	hostNetwork bool
+	docker      dockerRunner
+}
+
+// NewStack returns the observability stack of the local network of profile,
+// whose configuration is stored in dir
+func NewStack(dir string, profile string, prometheusPort uint16, grafanaPort uint16) *Stack {
+	return &Stack{
+		dir:            dir,
+		profile:        profile,
+		prometheusPort: prometheusPort,
+		grafanaPort:    grafanaPort,
+		hostNetwork:    runtime.GOOS == "linux",
+		docker:         runDocker,
+	}
+}
+
+func (s *Stack) prometheusContainer() string {
+	return "avalanche-cli-prometheus-" + s.profile
+}
+
+func (s *Stack) grafanaContainer() string {
+	return "avalanche-cli-grafana-" + s.profile
+}
+
+// GrafanaURL returns the URL of the Grafana UI
+func (s *Stack) GrafanaURL() string {
+	return fmt.Sprintf("http://localhost:%d", s.grafanaPort)
+}
+
+// PrometheusURL returns the URL of the Prometheus UI
+func (s *Stack) PrometheusURL() string {
+	return fmt.Sprintf("http://localhost:%d", s.prometheusPort)
+}
+
+// writeConfig writes the configuration files of the stack for the nodes,
+// given by name with their URI
+func (s *Stack) writeConfig(nodeURIs map[string]string) error {
+	targetHost, prometheusHost := "", "127.0.0.1"
+	if !s.hostNetwork {
+		targetHost, prometheusHost = dockerHost, dockerHost
+	}
+	prometheusConfig, err := prometheusStackConfig(nodeURIs, targetHost)
+	if err != nil {
+		return err
+	}
+	datasources, err := grafanaDatasourceConfig(fmt.Sprintf("http://%s:%d", prometheusHost, s.prometheusPort))
+	if err != nil {
+		return err
+	}
+	dashboards, err := grafanaDashboardsConfig(containerGrafanaDashboard)
+	if err != nil {
+		return err
+	}
+	dashboard, err := grafanaDashboard()
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		filepath.Join(s.dir, prometheusConfigFile):                                         prometheusConfig,
+		filepath.Join(s.dir, grafanaDir, "provisioning", "datasources", "datasources.yml"): datasources,
+		filepath.Join(s.dir, grafanaDir, "provisioning", "dashboards", "dashboards.yml"):   dashboards,
+		filepath.Join(s.dir, grafanaDir, "dashboards", grafanaDashboardFile):               dashboard,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		// the containers don't run as the current user
+		if err := os.WriteFile(path, content, application.WriteReadReadPerms); err != nil {
+			return fmt.Errorf("failed writing %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// networkArgs returns the docker run arguments exposing port
+func (s *Stack) networkArgs(port uint16) []string {
+	if s.hostNetwork {
+		return []string{"--network", "host"}
+	}
+	return []string{"-p", fmt.Sprintf("%d:%d", port, port)}
+}
+
+// Start writes the configuration of the stack for the nodes, given by name
+// with their URI, and (re)starts its containers
+func (s *Stack) Start(nodeURIs map[string]string) error {
+	if err := s.writeConfig(nodeURIs); err != nil {
+		return err
+	}
+	// restart from scratch, the node ports may have changed
+	if err := s.Stop(); err != nil {
+		return err
+	}
+
+	prometheusArgs := []string{"run", "-d", "--name", s.prometheusContainer()}
+	prometheusArgs = append(prometheusArgs, s.networkArgs(s.prometheusPort)...)
+	prometheusArgs = append(prometheusArgs,
+		"-v", filepath.Join(s.dir, prometheusConfigFile)+":"+containerPrometheusConfig+":ro",
+		constants.PrometheusImage,
+		"--config.file="+containerPrometheusConfig,
+		"--storage.tsdb.path=/prometheus",
+		fmt.Sprintf("--web.listen-address=:%d", s.prometheusPort),
+	)
+	if out, err := s.docker(prometheusArgs...); err != nil {
+		return fmt.Errorf("failed starting prometheus: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	grafanaArgs := []string{"run", "-d", "--name", s.grafanaContainer()}
+	grafanaArgs = append(grafanaArgs, s.networkArgs(s.grafanaPort)...)
+	grafanaArgs = append(grafanaArgs,
+		"-v", filepath.Join(s.dir, grafanaDir, "provisioning")+":"+containerGrafanaConfig+":ro",
+		"-v", filepath.Join(s.dir, grafanaDir, "dashboards")+":"+containerGrafanaDashboard+":ro",
+		"-e", fmt.Sprintf("GF_SERVER_HTTP_PORT=%d", s.grafanaPort),
+		"-e", "GF_AUTH_ANONYMOUS_ENABLED=true",
+		"-e", "GF_AUTH_ANONYMOUS_ORG_ROLE=Admin",
+		"-e", "GF_DASHBOARDS_DEFAULT_HOME_DASHBOARD_PATH="+containerGrafanaDashboard+"/"+grafanaDashboardFile,
+		constants.GrafanaImage,
+	)
+	if out, err := s.docker(grafanaArgs...); err != nil {
+		// don't leave half of the stack running
+		_ = s.Stop()
+		return fmt.Errorf("failed starting grafana: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Stop removes the containers of the stack, if running
+func (s *Stack) Stop() error {
+	for _, container := range []string{s.grafanaContainer(), s.prometheusContainer()} {
+		out, err := s.docker("rm", "-f", container)
+		if err != nil && !strings.Contains(string(out), "No such container") {
+			return fmt.Errorf("failed removing container %s: %w: %s", container, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
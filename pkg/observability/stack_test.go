@@ -0,0 +1,101 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package observability
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+var testNodeURIs = map[string]string{
+	"node2": "http://127.0.0.1:9652",
+	"node1": "http://127.0.0.1:9650",
+}
+
+// fakeDocker records the docker invocations, and fails those starting with failOn
+type fakeDocker struct {
+	calls  [][]string
+	failOn string
+}
+
+func (f *fakeDocker) run(args ...string) ([]byte, error) {
+	f.calls = append(f.calls, args)
+	if args[0] == "rm" {
+		return []byte("Error: No such container: " + args[2]), errors.New("exit status 1")
+	}
+	if f.failOn != "" && strings.Contains(strings.Join(args, " "), f.failOn) {
+		return []byte("boom"), errors.New("exit status 1")
+	}
+	return nil, nil
+}
+
+func newTestStack(t *testing.T, hostNetwork bool) (*Stack, *fakeDocker) {
+	docker := &fakeDocker{}
+	s := NewStack(t.TempDir(), "default", 9090, 3000)
+	s.hostNetwork = hostNetwork
+	s.docker = docker.run
+	return s, docker
+}
+
+func TestStackStart(t *testing.T) {
+	assert := assert.New(t)
+
+	s, docker := newTestStack(t, false)
+	assert.NoError(s.Start(testNodeURIs))
+
+	// both containers are first removed, then started
+	assert.Len(docker.calls, 4)
+	assert.Equal([]string{"rm", "-f", "avalanche-cli-grafana-default"}, docker.calls[0])
+	assert.Equal([]string{"rm", "-f", "avalanche-cli-prometheus-default"}, docker.calls[1])
+	assert.Contains(strings.Join(docker.calls[2], " "), "--name avalanche-cli-prometheus-default -p 9090:9090")
+	assert.Contains(strings.Join(docker.calls[3], " "), "--name avalanche-cli-grafana-default -p 3000:3000")
+
+	promBytes, err := os.ReadFile(filepath.Join(s.dir, prometheusConfigFile))
+	assert.NoError(err)
+	var conf PrometheusConfig
+	assert.NoError(yaml.Unmarshal(promBytes, &conf))
+	assert.Equal([]string{"host.docker.internal:9650"}, conf.ScrapeConfigs[0].StaticConfigs[0].Targets)
+	assert.Equal([]string{"host.docker.internal:9652"}, conf.ScrapeConfigs[0].StaticConfigs[1].Targets)
+
+	datasources, err := os.ReadFile(filepath.Join(s.dir, grafanaDir, "provisioning", "datasources", "datasources.yml"))
+	assert.NoError(err)
+	assert.Contains(string(datasources), "http://host.docker.internal:9090")
+
+	dashboardBytes, err := os.ReadFile(filepath.Join(s.dir, grafanaDir, "dashboards", grafanaDashboardFile))
+	assert.NoError(err)
+	var dashboard map[string]interface{}
+	assert.NoError(json.Unmarshal(dashboardBytes, &dashboard))
+	assert.Len(dashboard["panels"], len(dashboardPanels))
+}
+
+func TestStackStartHostNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	s, docker := newTestStack(t, true)
+	assert.NoError(s.Start(testNodeURIs))
+	assert.Contains(strings.Join(docker.calls[2], " "), "--network host")
+
+	promBytes, err := os.ReadFile(filepath.Join(s.dir, prometheusConfigFile))
+	assert.NoError(err)
+	var conf PrometheusConfig
+	assert.NoError(yaml.Unmarshal(promBytes, &conf))
+	assert.Equal([]string{"127.0.0.1:9650"}, conf.ScrapeConfigs[0].StaticConfigs[0].Targets)
+}
+
+func TestStackStartFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	s, docker := newTestStack(t, false)
+	docker.failOn = "grafana/grafana"
+	assert.ErrorContains(s.Start(testNodeURIs), "failed starting grafana")
+	// the prometheus container is removed again
+	last := docker.calls[len(docker.calls)-1]
+	assert.Equal([]string{"rm", "-f", "avalanche-cli-prometheus-default"}, last)
+}
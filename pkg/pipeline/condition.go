@@ -0,0 +1,184 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// conditionSuccess runs the step if no step failed before, the default
+	conditionSuccess = "success"
+	// conditionFailure runs the step only if a step failed before
+	conditionFailure = "failure"
+	// conditionAlways runs the step whatever failed before, e.g. to clean up
+	conditionAlways = "always"
+)
+
+var varRegexp = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// Condition is when a step runs: success, failure, always, or a comparison
+// of two values, "a == b" or "a != b", or a single value, true unless empty,
+// false, no or 0. The values may refer to variables, and the steps with a
+// comparison or a value only run if no step failed before.
+type Condition struct {
+	keyword string
+	lhs     string
+	op      string
+	rhs     string
+}
+
+// ParseCondition parses the condition of a step, success if empty
+func ParseCondition(s string) (Condition, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "", conditionSuccess:
+		return Condition{keyword: conditionSuccess}, nil
+	case conditionFailure, conditionAlways:
+		return Condition{keyword: s}, nil
+	}
+	c := Condition{lhs: s}
+	for _, op := range []string{"==", "!="} {
+		if i := strings.Index(s, op); i >= 0 {
+			c = Condition{lhs: unquote(s[:i]), op: op, rhs: unquote(s[i+len(op):])}
+			break
+		}
+	}
+	if strings.Contains(c.rhs, "==") || strings.Contains(c.rhs, "!=") {
+		return Condition{}, fmt.Errorf("invalid condition %q, only one comparison is allowed", s)
+	}
+	for _, operand := range []string{c.lhs, c.rhs} {
+		if err := checkVarRefs(operand); err != nil {
+			return Condition{}, fmt.Errorf("invalid condition %q: %w", s, err)
+		}
+	}
+	return c, nil
+}
+
+// Holds returns true if the step of the condition must run, failed telling
+// if a step failed before, with its variables expanded by expand
+func (c Condition) Holds(failed bool, expand func(string) (string, error)) (bool, error) {
+	switch c.keyword {
+	case conditionSuccess:
+		return !failed, nil
+	case conditionFailure:
+		return failed, nil
+	case conditionAlways:
+		return true, nil
+	}
+	if failed {
+		return false, nil
+	}
+	lhs, err := expand(c.lhs)
+	if err != nil {
+		return false, err
+	}
+	switch c.op {
+	case "":
+		return truthy(lhs), nil
+	default:
+		rhs, err := expand(c.rhs)
+		if err != nil {
+			return false, err
+		}
+		return (lhs == rhs) == (c.op == "=="), nil
+	}
+}
+
+func truthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "no", "0":
+		return false
+	}
+	return true
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Vars are the variables of a running pipeline
+type Vars struct {
+	values map[string]string
+	// outcomes are the outcomes of the steps run so far, by name
+	outcomes map[string]StepOutcome
+}
+
+// NewVars returns the variables of p, overridden by overrides
+func NewVars(p *Pipeline, overrides map[string]string) (*Vars, error) {
+	v := &Vars{values: map[string]string{}, outcomes: map[string]StepOutcome{}}
+	for name, value := range p.Vars {
+		v.values[name] = value
+	}
+	for name, value := range overrides {
+		if err := checkVarName(name); err != nil {
+			return nil, err
+		}
+		v.values[name] = value
+	}
+	return v, nil
+}
+
+// Set sets the variable name to value
+func (v *Vars) Set(name, value string) {
+	v.values[name] = value
+}
+
+// Expand replaces the references to variables in s with their values:
+// ${name} for the variables of the pipeline, ${env.NAME} for the environment
+// variables and ${steps.<step>.outcome} for the outcome of a step run before,
+// success, failure or skipped
+func (v *Vars) Expand(s string) (string, error) {
+	var err error
+	expanded := varRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		value, lookupErr := v.lookup(strings.TrimSpace(ref[2 : len(ref)-1]))
+		if lookupErr != nil && err == nil {
+			err = lookupErr
+		}
+		return value
+	})
+	return expanded, err
+}
+
+func (v *Vars) lookup(ref string) (string, error) {
+	parts := strings.Split(ref, ".")
+	switch {
+	case len(parts) == 1:
+		value, ok := v.values[ref]
+		if !ok {
+			return "", fmt.Errorf("variable %s is not defined, set it in vars or with --var %s=<value>", ref, ref)
+		}
+		return value, nil
+	case len(parts) == 2 && parts[0] == "env":
+		return os.Getenv(parts[1]), nil
+	case len(parts) == 3 && parts[0] == "steps" && parts[2] == "outcome":
+		outcome, ok := v.outcomes[parts[1]]
+		if !ok {
+			return "", fmt.Errorf("step %s has not run yet", parts[1])
+		}
+		return string(outcome), nil
+	}
+	return "", fmt.Errorf("invalid reference ${%s}", ref)
+}
+
+// checkVarRefs checks the references to variables in s are well formed
+func checkVarRefs(s string) error {
+	for _, match := range varRegexp.FindAllStringSubmatch(s, -1) {
+		parts := strings.Split(strings.TrimSpace(match[1]), ".")
+		switch {
+		case len(parts) == 1 && nameRegexp.MatchString(parts[0]):
+		case len(parts) == 2 && parts[0] == "env" && parts[1] != "":
+		case len(parts) == 3 && parts[0] == "steps" && parts[2] == "outcome":
+		default:
+			return fmt.Errorf("invalid reference %s", match[0])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pipeline runs the steps of a YAML pipeline file one after the
+// other: avalanche commands and shell commands, with variables and
+// conditions, for teams to script the lifecycle of their subnets.
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Pipeline is a sequence of steps run by avalanche run
+type Pipeline struct {
+	// Path is the file the pipeline was loaded from
+	Path string `yaml:"-"`
+	// Vars are the values of the variables, which --var overrides
+	Vars  map[string]string `yaml:"vars"`
+	Steps []Step            `yaml:"steps"`
+}
+
+// Step is one operation of a pipeline, either an avalanche command or a
+// shell command
+type Step struct {
+	// Name identifies the step in the output and in the variables of its
+	// outcome, defaults to step-<number>
+	Name string `yaml:"name"`
+	// Avalanche are the arguments of the avalanche command of the step,
+	// without the avalanche binary
+	Avalanche string `yaml:"avalanche"`
+	// Run is the shell command of the step
+	Run string `yaml:"run"`
+	// If is the condition of the step, see Condition
+	If string `yaml:"if"`
+	// ContinueOnError doesn't fail the pipeline when the step fails
+	ContinueOnError bool `yaml:"continue-on-error"`
+	// Output is the variable the standard output of the step is stored in,
+	// trimmed of its surrounding spaces
+	Output string `yaml:"output"`
+}
+
+// Command returns how the step is shown to the user
+func (s Step) Command() string {
+	if s.Run != "" {
+		return s.Run
+	}
+	return "avalanche " + s.Avalanche
+}
+
+// Load reads the pipeline at path and validates it
+func Load(path string) (*Pipeline, error) {
+	pipelineBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading pipeline %s: %w", path, err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(pipelineBytes))
+	// a misspelt key would silently change what the pipeline does
+	decoder.KnownFields(true)
+	p := &Pipeline{}
+	if err := decoder.Decode(p); err != nil {
+		return nil, fmt.Errorf("failed parsing pipeline %s: %w", path, err)
+	}
+	p.Path = path
+	for i := range p.Steps {
+		if p.Steps[i].Name == "" {
+			p.Steps[i].Name = fmt.Sprintf("step-%d", i+1)
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pipeline %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Dir is the directory the steps run in, the one of the pipeline file
+func (p *Pipeline) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// Validate checks the variables and the steps of the pipeline are well
+// named, and that every step has a single command and a valid condition
+func (p *Pipeline) Validate() error {
+	for name := range p.Vars {
+		if err := checkVarName(name); err != nil {
+			return err
+		}
+	}
+	if len(p.Steps) == 0 {
+		return errors.New("no steps defined")
+	}
+	names := map[string]bool{}
+	for _, s := range p.Steps {
+		if !nameRegexp.MatchString(s.Name) {
+			return fmt.Errorf("invalid step name %q, only letters, digits, '_' and '-' are allowed", s.Name)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("step %s is defined more than once", s.Name)
+		}
+		names[s.Name] = true
+		switch {
+		case s.Avalanche == "" && s.Run == "":
+			return fmt.Errorf("step %s has neither an avalanche nor a run command", s.Name)
+		case s.Avalanche != "" && s.Run != "":
+			return fmt.Errorf("step %s has both an avalanche and a run command", s.Name)
+		}
+		if s.Avalanche != "" {
+			if _, err := SplitArgs(s.Avalanche); err != nil {
+				return fmt.Errorf("step %s: %w", s.Name, err)
+			}
+		}
+		if err := checkVarRefs(s.Command()); err != nil {
+			return fmt.Errorf("step %s: %w", s.Name, err)
+		}
+		if _, err := ParseCondition(s.If); err != nil {
+			return fmt.Errorf("step %s: %w", s.Name, err)
+		}
+		if s.Output != "" {
+			if err := checkVarName(s.Output); err != nil {
+				return fmt.Errorf("step %s: %w", s.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func checkVarName(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	return nil
+}
+
+// SplitArgs splits command into arguments on spaces as a shell does, the
+// spaces between quotes or escaped with a backslash being part of the
+// arguments
+func SplitArgs(command string) ([]string, error) {
+	args := []string{}
+	var (
+		arg     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range command {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", command)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return args, nil
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePipeline(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := Load(writePipeline(t, `
+vars:
+  subnet: tokens
+steps:
+  - avalanche: subnet create ${subnet} --evm
+  - name: tests
+    run: go test ./...
+    if: ${steps.step-1.outcome} == success
+`))
+	assert.NoError(err)
+	assert.Equal("tokens", p.Vars["subnet"])
+	assert.Equal("step-1", p.Steps[0].Name)
+	assert.Equal("avalanche subnet create ${subnet} --evm", p.Steps[0].Command())
+
+	for content, expected := range map[string]string{
+		"steps: []":                              "no steps defined",
+		"steps:\n  - name: a\n":                  "neither an avalanche nor a run command",
+		"steps:\n  - avalanche: a\n    run: b\n": "both an avalanche and a run command",
+		"steps:\n  - avalanche: a\n  - avalanche: b\n    name: step-1\n": "defined more than once",
+		"steps:\n  - avalanche: a\n    iff: always\n":                    "field iff not found",
+		"steps:\n  - avalanche: 'a \"b'\n":                               "unterminated quote",
+		"steps:\n  - avalanche: a ${x.y}\n":                              "invalid reference ${x.y}",
+		"steps:\n  - run: a\n    if: a == b == c\n":                      "only one comparison",
+		"vars:\n  a.b: c\nsteps:\n  - run: a\n":                          "invalid variable name",
+	} {
+		_, err := Load(writePipeline(t, content))
+		assert.ErrorContains(err, expected, content)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	args, err := SplitArgs(`subnet  create "my subnet" --file 'a b.json' c\ d`)
+	assert.NoError(err)
+	assert.Equal([]string{"subnet", "create", "my subnet", "--file", "a b.json", "c d"}, args)
+	args, err = SplitArgs(`key create ""`)
+	assert.NoError(err)
+	assert.Equal([]string{"key", "create", ""}, args)
+	_, err = SplitArgs("  ")
+	assert.Error(err)
+}
+
+func TestCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	vars, err := NewVars(&Pipeline{Vars: map[string]string{"network": "local", "tests": "false"}}, map[string]string{"network": "fuji"})
+	assert.NoError(err)
+	for s, expected := range map[string][2]bool{
+		// holding without and with a failure before
+		"":                        {true, false},
+		"always":                  {true, true},
+		"failure":                 {false, true},
+		"${network} == fuji":      {true, false},
+		"${network} != 'fuji'":    {false, false},
+		"${tests}":                {false, false},
+		"yes":                     {true, false},
+		`"${network}" == "local"`: {false, false},
+	} {
+		c, err := ParseCondition(s)
+		assert.NoError(err, s)
+		for i, failed := range []bool{false, true} {
+			holds, err := c.Holds(failed, vars.Expand)
+			assert.NoError(err, s)
+			assert.Equal(expected[i], holds, "%s, failed before: %t", s, failed)
+		}
+	}
+
+	c, err := ParseCondition("${missing} == a")
+	assert.NoError(err)
+	_, err = c.Holds(false, vars.Expand)
+	assert.ErrorContains(err, "--var missing=<value>")
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := Load(writePipeline(t, `
+vars:
+  subnet: my subnet
+steps:
+  - name: create
+    avalanche: subnet create "${subnet}"
+  - name: id
+    run: echo ${subnet}
+    output: id
+  - name: tests
+    run: fail
+    continue-on-error: true
+  - name: snapshot
+    avalanche: network stop ${id}
+    if: ${steps.tests.outcome} == success
+  - name: deploy
+    avalanche: subnet deploy --local
+  - name: after
+    run: never
+  - name: report
+    run: report ${steps.deploy.outcome}
+    if: failure
+  - name: cleanup
+    avalanche: network clean
+    if: always
+`))
+	assert.NoError(err)
+	vars, err := NewVars(p, nil)
+	assert.NoError(err)
+
+	ran := []string{}
+	var out bytes.Buffer
+	runner := &Runner{
+		Self:       "avalanche",
+		GlobalArgs: []string{"--profile=ci"},
+		Shell:      "sh",
+		Out:        &out,
+		Exec: func(dir string, name string, args []string, stdout io.Writer) error {
+			assert.Equal(filepath.Dir(p.Path), dir)
+			ran = append(ran, name+" "+strings.Join(args, "|"))
+			switch {
+			case args[len(args)-1] == "echo my subnet":
+				fmt.Fprintln(stdout, " subnet-id ")
+			case args[len(args)-1] == "fail", args[len(args)-1] == "--local":
+				return errors.New("exit status 1")
+			}
+			return nil
+		},
+	}
+	results, err := runner.Run(p, vars)
+	assert.ErrorContains(err, "step deploy failed: exit status 1")
+	assert.Equal([]string{
+		"avalanche --profile=ci|subnet|create|my subnet",
+		"sh -c|echo my subnet",
+		"sh -c|fail",
+		"avalanche --profile=ci|subnet|deploy|--local",
+		"sh -c|report failure",
+		"avalanche --profile=ci|network|clean",
+	}, ran)
+	outcomes := []StepOutcome{}
+	for _, result := range results {
+		outcomes = append(outcomes, result.Outcome)
+	}
+	assert.Equal([]StepOutcome{
+		OutcomeSuccess, OutcomeSuccess, OutcomeFailure, OutcomeSkipped,
+		OutcomeFailure, OutcomeSkipped, OutcomeSuccess, OutcomeSuccess,
+	}, outcomes)
+	assert.Contains(out.String(), "==> create: avalanche subnet create my subnet")
+	assert.Contains(out.String(), "[4/8] snapshot skipped")
+
+	// a dry run runs nothing, the outputs being placeholders
+	ran = []string{}
+	out.Reset()
+	runner.DryRun = true
+	vars, err = NewVars(p, nil)
+	assert.NoError(err)
+	_, err = runner.Run(p, vars)
+	assert.NoError(err)
+	assert.Empty(ran)
+	assert.Contains(out.String(), "==> snapshot: avalanche network stop <output of id>")
+}
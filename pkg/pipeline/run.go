@@ -0,0 +1,149 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// StepOutcome is how a step of a pipeline ended
+type StepOutcome string
+
+const (
+	OutcomeSuccess StepOutcome = "success"
+	OutcomeFailure StepOutcome = "failure"
+	// OutcomeSkipped didn't run, its condition not holding
+	OutcomeSkipped StepOutcome = "skipped"
+)
+
+// StepResult is how a step of a pipeline went
+type StepResult struct {
+	Step     Step
+	Outcome  StepOutcome
+	Duration time.Duration
+	// Err is why the step failed
+	Err error
+}
+
+// ExecFunc runs the program name with args in dir, writing its standard
+// output to stdout
+type ExecFunc func(dir string, name string, args []string, stdout io.Writer) error
+
+// Runner runs the steps of pipelines
+type Runner struct {
+	// Self is the avalanche binary the avalanche steps run
+	Self string
+	// GlobalArgs are given to every avalanche step before its own arguments,
+	// e.g. the global flags the pipeline was run with
+	GlobalArgs []string
+	// Shell is the shell the run steps run with, as Shell -c <command>
+	Shell string
+	Exec  ExecFunc
+	// Out is where the steps and their standard output are printed
+	Out io.Writer
+	// DryRun prints the steps instead of running them, as if they succeeded
+	DryRun bool
+}
+
+// Run runs the steps of p one after the other with the variables of vars.
+// A failing step fails the pipeline, unless it continues on error, and the
+// steps after it only run if their condition says so. Returns the results
+// of all the steps, and the first failure of the pipeline.
+func (r *Runner) Run(p *Pipeline, vars *Vars) ([]StepResult, error) {
+	results := []StepResult{}
+	var failure error
+	for i, step := range p.Steps {
+		result := r.runStep(p, vars, step, failure != nil)
+		result.Step = step
+		vars.outcomes[step.Name] = result.Outcome
+		results = append(results, result)
+		switch result.Outcome {
+		case OutcomeSkipped:
+			fmt.Fprintf(r.Out, "[%d/%d] %s skipped\n", i+1, len(p.Steps), step.Name)
+		case OutcomeSuccess:
+			fmt.Fprintf(r.Out, "[%d/%d] %s succeeded in %s\n", i+1, len(p.Steps), step.Name, result.Duration.Round(time.Millisecond))
+		case OutcomeFailure:
+			fmt.Fprintf(r.Out, "[%d/%d] %s failed in %s: %s\n", i+1, len(p.Steps), step.Name, result.Duration.Round(time.Millisecond), result.Err)
+			if !step.ContinueOnError && failure == nil {
+				failure = fmt.Errorf("step %s failed: %w", step.Name, result.Err)
+			}
+		}
+	}
+	return results, failure
+}
+
+func (r *Runner) runStep(p *Pipeline, vars *Vars, step Step, failed bool) StepResult {
+	condition, err := ParseCondition(step.If)
+	if err != nil {
+		return StepResult{Outcome: OutcomeFailure, Err: err}
+	}
+	holds, err := condition.Holds(failed, vars.Expand)
+	if err != nil {
+		return StepResult{Outcome: OutcomeFailure, Err: err}
+	}
+	if !holds {
+		return StepResult{Outcome: OutcomeSkipped}
+	}
+	name, args, err := r.command(vars, step)
+	if err != nil {
+		return StepResult{Outcome: OutcomeFailure, Err: err}
+	}
+	shown := "avalanche " + strings.Join(args[len(r.GlobalArgs):], " ")
+	if step.Run != "" {
+		shown = args[1]
+	}
+	fmt.Fprintf(r.Out, "==> %s: %s\n", step.Name, shown)
+	if r.DryRun {
+		if step.Output != "" {
+			vars.Set(step.Output, "<output of "+step.Name+">")
+		}
+		return StepResult{Outcome: OutcomeSuccess}
+	}
+	var output bytes.Buffer
+	stdout := r.Out
+	if step.Output != "" {
+		stdout = io.MultiWriter(r.Out, &output)
+	}
+	start := time.Now()
+	err = r.Exec(p.Dir(), name, args, stdout)
+	result := StepResult{Outcome: OutcomeSuccess, Duration: time.Since(start), Err: err}
+	if err != nil {
+		result.Outcome = OutcomeFailure
+		return result
+	}
+	if step.Output != "" {
+		vars.Set(step.Output, strings.TrimSpace(output.String()))
+	}
+	return result
+}
+
+// command returns the program the step runs, with its arguments, its
+// variables expanded
+func (r *Runner) command(vars *Vars, step Step) (string, []string, error) {
+	if step.Run != "" {
+		command, err := vars.Expand(step.Run)
+		if err != nil {
+			return "", nil, err
+		}
+		return r.Shell, []string{"-c", command}, nil
+	}
+	// the arguments are split before being expanded, for a value with
+	// spaces to remain a single argument
+	stepArgs, err := SplitArgs(step.Avalanche)
+	if err != nil {
+		return "", nil, err
+	}
+	args := append([]string{}, r.GlobalArgs...)
+	for _, arg := range stepArgs {
+		expanded, err := vars.Expand(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, expanded)
+	}
+	return r.Self, args, nil
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+)
+
+// ErrHeadless is returned when prompting for input in headless mode
+var ErrHeadless = errors.New("can't prompt in headless mode")
+
+// NewHeadlessPrompter creates a prompter for headless mode, in which all the
+// inputs must be given with flags, environment variables or config files.
+// Prompts with a default answer take it, the others fail right away instead
+// of hanging.
+func NewHeadlessPrompter(defaults ...Option) Prompter {
+	return &realPrompter{defaults: defaults, headless: true, out: os.Stdout}
+}
+
+func headlessError(promptStr string) error {
+	return exitcodes.UserInput(fmt.Errorf("%w, give the input for %q with a flag, environment variable or config file instead", ErrHeadless, promptStr))
+}
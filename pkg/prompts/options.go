@@ -0,0 +1,217 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+)
+
+var (
+	// ErrInterrupted is returned when a prompt is interrupted with Ctrl+C
+	ErrInterrupted = errors.New("prompt interrupted")
+	// ErrNoInput is returned when the input of a prompt ends before it is
+	// answered, e.g. when reading answers from a pipe
+	ErrNoInput = errors.New("no more input to answer the prompt")
+	// ErrPromptTimeout is returned when a prompt without a default answer
+	// is not answered in time
+	ErrPromptTimeout = errors.New("prompt not answered in time")
+	// ErrNotConfirmed is returned when the name typed to confirm a
+	// destructive operation is not the one of what it destroys
+	ErrNotConfirmed = errors.New("operation not confirmed")
+)
+
+// Option customizes a prompt
+type Option func(*options)
+
+type options struct {
+	defaultAnswer *string
+	timeout       time.Duration
+	assumeYes     bool
+	clock         func() time.Time
+}
+
+// WithDefault sets the answer of the prompt when the user just presses
+// enter, which is shown in brackets after the prompt. For lists, the default
+// must be one of the options, and is selected to begin with.
+func WithDefault(answer string) Option {
+	return func(o *options) {
+		o.defaultAnswer = &answer
+	}
+}
+
+// WithTimeout answers the prompt with its default once timeout elapses
+// without an answer, or fails it if it has none. A timeout of 0 waits for
+// the answer forever.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithAssumeYes confirms the destructive operations without asking to type
+// the name of what they destroy
+func WithAssumeYes(assumeYes bool) Option {
+	return func(o *options) {
+		o.assumeYes = assumeYes
+	}
+}
+
+// WithClock sets the clock the dates entered relative to now are relative
+// to, and must be after, e.g. the time of the network instead of the local
+// clock
+func WithClock(clock func() time.Time) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// now returns the time of the clock set with WithClock, or else the local
+// time
+func (o options) now() time.Time {
+	if o.clock == nil {
+		return time.Now()
+	}
+	return o.clock()
+}
+
+func buildOptions(base []Option, opts []Option) options {
+	var o options
+	for _, opt := range base {
+		opt(&o)
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// label returns the prompt with its default answer, if any, in brackets
+func (o options) label(promptStr string, masked bool) string {
+	if o.defaultAnswer == nil || *o.defaultAnswer == "" {
+		return promptStr
+	}
+	if masked {
+		return promptStr + " [********]"
+	}
+	return fmt.Sprintf("%s [%s]", promptStr, *o.defaultAnswer)
+}
+
+// promptError turns the errors of promptui into the ones of this package
+func promptError(promptStr string, err error) error {
+	switch {
+	case errors.Is(err, promptui.ErrInterrupt):
+		return exitcodes.UserInput(ErrInterrupted)
+	case errors.Is(err, promptui.ErrEOF):
+		return exitcodes.UserInput(fmt.Errorf("%w %q", ErrNoInput, promptStr))
+	}
+	return err
+}
+
+// runWithTimeout runs a prompt reading from stdin, and closes stdin once
+// timeout elapses, for the prompt to return. It reports whether it did.
+func runWithTimeout(stdin io.Closer, timeout time.Duration, run func() error) (bool, error) {
+	if timeout <= 0 {
+		return false, run()
+	}
+	var fired int32
+	timer := time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&fired, 1)
+		_ = stdin.Close()
+	})
+	err := run()
+	timer.Stop()
+	return atomic.LoadInt32(&fired) == 1, err
+}
+
+// newStdin returns a reader of the standard input which can be closed to
+// cancel a running prompt, without closing the standard input itself
+func newStdin() *readline.CancelableStdin {
+	return readline.NewCancelableStdin(os.Stdin)
+}
+
+// stdinIsTerminal returns true if the prompts can be interactive
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// lineReader reads the answers of the prompts line by line when the
+// standard input is not a terminal, e.g. a pipe. Lines are read in the
+// background so that a prompt can stop waiting without losing the answer
+// of the next one.
+type lineReader struct {
+	r     io.Reader
+	once  sync.Once
+	lines chan lineResult
+}
+
+type lineResult struct {
+	line string
+	err  error
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: r, lines: make(chan lineResult)}
+}
+
+func (l *lineReader) start() {
+	go func() {
+		scanner := bufio.NewScanner(l.r)
+		for scanner.Scan() {
+			l.lines <- lineResult{line: strings.TrimRight(scanner.Text(), "\r")}
+		}
+		err := scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		for {
+			l.lines <- lineResult{err: err}
+		}
+	}()
+}
+
+// next returns the next line, or a timeout error once timeout elapses if
+// it is positive
+func (l *lineReader) next(timeout time.Duration) (string, error) {
+	l.once.Do(l.start)
+	if timeout <= 0 {
+		res := <-l.lines
+		return res.line, res.err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case res := <-l.lines:
+		return res.line, res.err
+	case <-timer.C:
+		return "", ErrPromptTimeout
+	}
+}
+
+// matchOption returns the index of the option answer selects, by its
+// value, case insensitively, or its position from 1
+func matchOption(answer string, items []string) (int, error) {
+	answer = strings.TrimSpace(answer)
+	for i, item := range items {
+		if strings.EqualFold(item, answer) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(items) {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("%q is not one of %s", answer, strings.Join(items, ", "))
+}
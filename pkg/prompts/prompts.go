@@ -5,12 +5,15 @@ package prompts
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
@@ -25,28 +28,63 @@ const (
 	No  = "No"
 )
 
+// Prompter asks the user for inputs. Every prompt takes Options, to set a
+// default answer or a timeout.
 type Prompter interface {
-	CapturePositiveBigInt(promptStr string) (*big.Int, error)
-	CaptureAddress(promptStr string) (common.Address, error)
-	CaptureExistingFilepath(promptStr string) (string, error)
-	CaptureYesNo(promptStr string) (bool, error)
-	CaptureNoYes(promptStr string) (bool, error)
-	CaptureList(promptStr string, options []string) (string, error)
-	CaptureString(promptStr string) (string, error)
-	CaptureIndex(promptStr string, options []common.Address) (int, error)
-	CaptureDuration(promptStr string) (time.Duration, error)
-	CaptureDate(promptStr string) (time.Time, error)
-	CaptureNodeID(promptStr string) (ids.NodeID, error)
-	CaptureWeight(promptStr string) (uint64, error)
-	CaptureUint64(promptStr string) (uint64, error)
-	CapturePChainAddress(promptStr string, network models.Network) (string, error)
+	CapturePositiveBigInt(promptStr string, opts ...Option) (*big.Int, error)
+	CaptureAddress(promptStr string, opts ...Option) (common.Address, error)
+	CaptureExistingFilepath(promptStr string, opts ...Option) (string, error)
+	CaptureYesNo(promptStr string, opts ...Option) (bool, error)
+	CaptureNoYes(promptStr string, opts ...Option) (bool, error)
+	CaptureList(promptStr string, options []string, opts ...Option) (string, error)
+	CaptureString(promptStr string, opts ...Option) (string, error)
+	CapturePassword(promptStr string, opts ...Option) (string, error)
+	CaptureIndex(promptStr string, options []common.Address, opts ...Option) (int, error)
+	CaptureDuration(promptStr string, opts ...Option) (time.Duration, error)
+	CaptureDate(promptStr string, opts ...Option) (time.Time, error)
+	CaptureNodeID(promptStr string, opts ...Option) (ids.NodeID, error)
+	CaptureWeight(promptStr string, opts ...Option) (uint64, error)
+	CaptureUint64(promptStr string, opts ...Option) (uint64, error)
+	CapturePChainAddress(promptStr string, network models.Network, opts ...Option) (string, error)
+	ConfirmDestructive(promptStr string, name string, opts ...Option) error
 }
 
-type realPrompter struct{}
+type realPrompter struct {
+	// defaults are the options of every prompt, which the options of a
+	// prompt override
+	defaults []Option
+	// headless answers the prompts with their default answer, and fails
+	// the ones without
+	headless bool
+	// lines reads the answers line by line when the standard input is not
+	// a terminal
+	lines *lineReader
+	// out is where the prompts without a terminal are written
+	out io.Writer
+}
+
+// errEmptyAnswer is an empty answer, standing for the default answer
+var errEmptyAnswer = errors.New("empty answer")
+
+// UseASCIIIcons replaces the unicode icons of the prompts with ASCII ones,
+// for terminals and screen readers which can not deal with them
+func UseASCIIIcons() {
+	promptui.IconInitial = promptui.Styler(promptui.FGBlue)("?")
+	promptui.IconGood = promptui.Styler(promptui.FGGreen)("v")
+	promptui.IconWarn = promptui.Styler(promptui.FGYellow)("!")
+	promptui.IconBad = promptui.Styler(promptui.FGRed)("x")
+	promptui.IconSelect = promptui.Styler(promptui.FGBold)(">")
+}
 
-// NewProcessChecker creates a new process checker which can respond if the server is running
-func NewPrompter() Prompter {
-	return &realPrompter{}
+// NewPrompter creates a prompter for the terminal, applying defaults to
+// every prompt. When the standard input is not a terminal, the answers are
+// read from it line by line.
+func NewPrompter(defaults ...Option) Prompter {
+	p := &realPrompter{defaults: defaults, out: os.Stdout}
+	if !stdinIsTerminal() {
+		p.lines = newLineReader(os.Stdin)
+	}
+	return p
 }
 
 func validatePositiveBigInt(input string) error {
@@ -75,12 +113,12 @@ func validateStakingDuration(input string) error {
 	return nil
 }
 
-func validateTime(input string) error {
-	t, err := time.Parse(constants.TimeParseLayout, input)
+func validateTime(input string, now time.Time) error {
+	t, err := ux.ParseTime(input, now)
 	if err != nil {
 		return err
 	}
-	if t.Before(time.Now().Add(constants.StakingStartLeadTime)) {
+	if t.Before(now.Add(constants.StakingStartLeadTime)) {
 		return fmt.Errorf("time should be at least start from now + %s", constants.StakingStartLeadTime)
 	}
 	return err
@@ -95,6 +133,11 @@ func validateAddress(input string) error {
 	if !common.IsHexAddress(input) {
 		return errors.New("invalid address")
 	}
+	// a mixed case address has a checksum, catching typos
+	hex := input[len(input)-2*common.AddressLength:]
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && common.HexToAddress(hex).Hex()[2:] != hex {
+		return errors.New("address does not match its checksum, it is most likely mistyped")
+	}
 	return nil
 }
 
@@ -127,82 +170,51 @@ func validateBiggerThanZero(input string) error {
 	return nil
 }
 
-func (*realPrompter) CaptureDuration(promptStr string) (time.Duration, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateStakingDuration,
-	}
-
-	durationStr, err := prompt.Run()
+func (p *realPrompter) CaptureDuration(promptStr string, opts ...Option) (time.Duration, error) {
+	durationStr, err := p.input(promptStr, validateStakingDuration, 0, opts)
 	if err != nil {
 		return 0, err
 	}
-
 	return time.ParseDuration(durationStr)
 }
 
-func (*realPrompter) CaptureDate(promptStr string) (time.Time, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateTime,
-	}
-
-	timeStr, err := prompt.Run()
+func (p *realPrompter) CaptureDate(promptStr string, opts ...Option) (time.Time, error) {
+	o := buildOptions(p.defaults, opts)
+	timeStr, err := p.input(promptStr, func(input string) error {
+		return validateTime(input, o.now())
+	}, 0, opts)
 	if err != nil {
 		return time.Time{}, err
 	}
-
-	return time.Parse(constants.TimeParseLayout, timeStr)
+	return ux.ParseTime(timeStr, o.now())
 }
 
-func (*realPrompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateNodeID,
-	}
-
-	nodeIDStr, err := prompt.Run()
+func (p *realPrompter) CaptureNodeID(promptStr string, opts ...Option) (ids.NodeID, error) {
+	nodeIDStr, err := p.input(promptStr, validateNodeID, 0, opts)
 	if err != nil {
 		return ids.EmptyNodeID, err
 	}
 	return ids.NodeIDFromString(nodeIDStr)
 }
 
-func (*realPrompter) CaptureWeight(promptStr string) (uint64, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateWeight,
-	}
-
-	amountStr, err := prompt.Run()
+func (p *realPrompter) CaptureWeight(promptStr string, opts ...Option) (uint64, error) {
+	amountStr, err := p.input(promptStr, validateWeight, 0, opts)
 	if err != nil {
 		return 0, err
 	}
-
 	return strconv.ParseUint(amountStr, 10, 64)
 }
 
-func (*realPrompter) CaptureUint64(promptStr string) (uint64, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateBiggerThanZero,
-	}
-
-	amountStr, err := prompt.Run()
+func (p *realPrompter) CaptureUint64(promptStr string, opts ...Option) (uint64, error) {
+	amountStr, err := p.input(promptStr, validateBiggerThanZero, 0, opts)
 	if err != nil {
 		return 0, err
 	}
-
 	return strconv.ParseUint(amountStr, 10, 64)
 }
 
-func (*realPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validatePositiveBigInt,
-	}
-
-	amountStr, err := prompt.Run()
+func (p *realPrompter) CapturePositiveBigInt(promptStr string, opts ...Option) (*big.Int, error) {
+	amountStr, err := p.input(promptStr, validatePositiveBigInt, 0, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -216,13 +228,13 @@ func (*realPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 }
 
 func validatePChainAddress(input string) (string, error) {
-	chainID, hrp, _, err := address.Parse(input)
+	chainID, hrp, _, err := address.Parse(strings.TrimSpace(input))
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("malformed address, expected P-<hrp>1...: %w", err)
 	}
 
 	if chainID != "P" {
-		return "", errors.New("this is not a PChain address")
+		return "", fmt.Errorf("this is not a PChain address but an address of the %s-Chain", chainID)
 	}
 	return hrp, nil
 }
@@ -233,7 +245,7 @@ func validatePChainFujiAddress(input string) error {
 		return err
 	}
 	if hrp != avago_constants.FujiHRP {
-		return errors.New("this is not a fuji address")
+		return fmt.Errorf("this address is for %s, not fuji", hrp)
 	}
 	return nil
 }
@@ -244,7 +256,7 @@ func validatePChainMainAddress(input string) error {
 		return err
 	}
 	if hrp != avago_constants.MainnetHRP {
-		return errors.New("this is not a mainnet address")
+		return fmt.Errorf("this address is for %s, not mainnet", hrp)
 	}
 	return nil
 }
@@ -257,7 +269,7 @@ func validatePChainLocalAddress(input string) error {
 	// ANR uses the `custom` HRP for local networks,
 	// but the `local` HRP also exists...
 	if hrp != avago_constants.LocalHRP && hrp != avago_constants.FallbackHRP {
-		return errors.New("this is not a local nor custom address")
+		return fmt.Errorf("this address is for %s, not local nor custom", hrp)
 	}
 	return nil
 }
@@ -277,22 +289,91 @@ func getPChainValidationFunc(network models.Network) func(string) error {
 	}
 }
 
-func (*realPrompter) CapturePChainAddress(promptStr string, network models.Network) (string, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: getPChainValidationFunc(network),
+// ValidatePChainAddress checks address is a P-Chain address of network
+func ValidatePChainAddress(address string, network models.Network) error {
+	return getPChainValidationFunc(network)(address)
+}
+
+// networkHRP returns the HRP of the addresses of network, the custom one
+// for the local network as ANR uses it
+func networkHRP(network models.Network) (string, bool) {
+	switch network {
+	case models.Fuji:
+		return avago_constants.FujiHRP, true
+	case models.Mainnet:
+		return avago_constants.MainnetHRP, true
+	case models.Local:
+		return avago_constants.FallbackHRP, true
 	}
+	return "", false
+}
 
-	return prompt.Run()
+// ConvertPChainAddress returns the P-Chain address on network of the key of
+// addr, a P-Chain address of another network, e.g. the fuji address of the
+// key of an avax address, and true. Returns false if addr is not the
+// P-Chain address of another network.
+func ConvertPChainAddress(addr string, network models.Network) (string, bool) {
+	if ValidatePChainAddress(addr, network) == nil {
+		return "", false
+	}
+	chainID, _, addrBytes, err := address.Parse(strings.TrimSpace(addr))
+	if err != nil || chainID != "P" {
+		return "", false
+	}
+	hrp, ok := networkHRP(network)
+	if !ok {
+		return "", false
+	}
+	converted, err := address.Format("P", hrp, addrBytes)
+	if err != nil {
+		return "", false
+	}
+	return converted, true
 }
 
-func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateAddress,
+// ResolvePChainAddress returns addr, which must be a P-Chain address of
+// network. If it is the P-Chain address of another network instead, the
+// address of the same key on network is returned, once confirmed with p.
+// what names addr in the errors, e.g. "control key".
+func ResolvePChainAddress(p Prompter, addr string, network models.Network, what string, opts ...Option) (string, error) {
+	converted, ok := ConvertPChainAddress(addr, network)
+	if !ok {
+		if err := ValidatePChainAddress(addr, network); err != nil {
+			return "", exitcodes.UserInput(fmt.Errorf("invalid %s %q: %w", what, addr, err))
+		}
+		return addr, nil
 	}
+	// the confirmation has no default, whatever the default address
+	noDefault := func(o *options) {
+		o.defaultAnswer = nil
+	}
+	yes, err := p.CaptureYesNo(fmt.Sprintf("The %s %s is an address of another network than %s. Use the address of the same key on %s, %s, instead?",
+		what, addr, network, network, converted), append(opts, noDefault)...)
+	if err != nil {
+		return "", err
+	}
+	if !yes {
+		return "", exitcodes.UserInput(fmt.Errorf("invalid %s %q: %w", what, addr, ValidatePChainAddress(addr, network)))
+	}
+	return converted, nil
+}
 
-	addressStr, err := prompt.Run()
+func (p *realPrompter) CapturePChainAddress(promptStr string, network models.Network, opts ...Option) (string, error) {
+	validate := getPChainValidationFunc(network)
+	addr, err := p.input(promptStr, func(input string) error {
+		if _, ok := ConvertPChainAddress(input, network); ok {
+			return nil
+		}
+		return validate(input)
+	}, 0, opts)
+	if err != nil {
+		return "", err
+	}
+	return ResolvePChainAddress(p, addr, network, "address", opts...)
+}
+
+func (p *realPrompter) CaptureAddress(promptStr string, opts ...Option) (common.Address, error) {
+	addressStr, err := p.input(promptStr, validateAddress, 0, opts)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -301,82 +382,233 @@ func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
 	return addressHex, nil
 }
 
-func (*realPrompter) CaptureExistingFilepath(promptStr string) (string, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateExistingFilepath,
-	}
+func (p *realPrompter) CaptureExistingFilepath(promptStr string, opts ...Option) (string, error) {
+	return p.input(promptStr, validateExistingFilepath, 0, opts)
+}
 
-	pathStr, err := prompt.Run()
+func (p *realPrompter) yesNoBase(promptStr string, orderedOptions []string, opts []Option) (bool, error) {
+	decision, err := p.CaptureList(promptStr, orderedOptions, opts...)
 	if err != nil {
-		return "", err
+		return false, err
 	}
+	return decision == Yes, nil
+}
 
-	return pathStr, nil
+func (p *realPrompter) CaptureYesNo(promptStr string, opts ...Option) (bool, error) {
+	return p.yesNoBase(promptStr, []string{Yes, No}, opts)
 }
 
-func yesNoBase(promptStr string, orderedOptions []string) (bool, error) {
-	prompt := promptui.Select{
-		Label: promptStr,
-		Items: orderedOptions,
-	}
+func (p *realPrompter) CaptureNoYes(promptStr string, opts ...Option) (bool, error) {
+	return p.yesNoBase(promptStr, []string{No, Yes}, opts)
+}
 
-	_, decision, err := prompt.Run()
+func (p *realPrompter) CaptureList(promptStr string, options []string, opts ...Option) (string, error) {
+	i, err := p.selectItem(promptStr, options, opts)
 	if err != nil {
-		return false, err
+		return "", err
 	}
-	return decision == Yes, nil
+	return options[i], nil
 }
 
-func (*realPrompter) CaptureYesNo(promptStr string) (bool, error) {
-	return yesNoBase(promptStr, []string{Yes, No})
+func (p *realPrompter) CaptureString(promptStr string, opts ...Option) (string, error) {
+	return p.input(promptStr, func(input string) error {
+		if input == "" {
+			return errors.New("string cannot be empty")
+		}
+		return nil
+	}, 0, opts)
 }
 
-func (*realPrompter) CaptureNoYes(promptStr string) (bool, error) {
-	return yesNoBase(promptStr, []string{No, Yes})
+// CapturePassword asks for a secret, which is masked while typed
+func (p *realPrompter) CapturePassword(promptStr string, opts ...Option) (string, error) {
+	return p.input(promptStr, func(input string) error {
+		if input == "" {
+			return errors.New("password cannot be empty")
+		}
+		return nil
+	}, '*', opts)
 }
 
-func (*realPrompter) CaptureList(promptStr string, options []string) (string, error) {
-	prompt := promptui.Select{
-		Label: promptStr,
-		Items: options,
+func (p *realPrompter) CaptureIndex(promptStr string, options []common.Address, opts ...Option) (int, error) {
+	items := make([]string, len(options))
+	for i, option := range options {
+		items[i] = option.Hex()
 	}
+	return p.selectItem(promptStr, items, opts)
+}
 
-	_, listDecision, err := prompt.Run()
+// ConfirmDestructive asks to type name, the name of what the operation of
+// promptStr destroys, to confirm it, unless WithAssumeYes is set. Returns
+// ErrNotConfirmed if another name is typed.
+func (p *realPrompter) ConfirmDestructive(promptStr string, name string, opts ...Option) error {
+	o := buildOptions(p.defaults, opts)
+	if o.assumeYes {
+		return nil
+	}
+	if p.headless {
+		return exitcodes.UserInput(fmt.Errorf("%w, confirm %q with --yes instead", ErrHeadless, promptStr))
+	}
+	// the name must be typed, it has no default
+	noDefault := func(o *options) {
+		o.defaultAnswer = nil
+	}
+	answer, err := p.input(fmt.Sprintf("%s Type %q to confirm", promptStr, name), func(string) error {
+		return nil
+	}, 0, append(opts, noDefault))
 	if err != nil {
-		return "", err
+		return err
+	}
+	if strings.TrimSpace(answer) != name {
+		return exitcodes.UserInput(fmt.Errorf("%w: typed %q instead of %q", ErrNotConfirmed, answer, name))
 	}
-	return listDecision, nil
+	return nil
 }
 
-func (*realPrompter) CaptureString(promptStr string) (string, error) {
+// input asks for an answer valid for validate, masked with mask if set. An
+// empty answer stands for the default answer, if any.
+func (p *realPrompter) input(promptStr string, validate func(string) error, mask rune, opts []Option) (string, error) {
+	o := buildOptions(p.defaults, opts)
+	if o.defaultAnswer != nil {
+		if err := validate(*o.defaultAnswer); err != nil {
+			return "", fmt.Errorf("invalid default answer to %q: %w", promptStr, err)
+		}
+	}
+	if p.headless {
+		return p.fallback(promptStr, o, ErrHeadless)
+	}
+	label := o.label(promptStr, mask != 0)
+
+	if p.lines != nil {
+		fmt.Fprintf(p.out, "%s: ", label)
+		answer, err := p.lines.next(o.timeout)
+		fmt.Fprintln(p.out)
+		if err == nil && answer == "" {
+			err = errEmptyAnswer
+		}
+		if err != nil {
+			return p.fallback(promptStr, o, err)
+		}
+		if err := validate(answer); err != nil {
+			return "", exitcodes.UserInput(fmt.Errorf("invalid answer to %q: %w", promptStr, err))
+		}
+		return answer, nil
+	}
+
+	stdin := newStdin()
 	prompt := promptui.Prompt{
-		Label: promptStr,
+		Label: label,
+		Mask:  mask,
+		Stdin: stdin,
 		Validate: func(input string) error {
-			if input == "" {
-				return errors.New("string cannot be empty")
+			if input == "" && o.defaultAnswer != nil {
+				return nil
 			}
-			return nil
+			return validate(input)
 		},
 	}
+	var answer string
+	timedOut, err := runWithTimeout(stdin, o.timeout, func() (err error) {
+		answer, err = prompt.Run()
+		return err
+	})
+	switch {
+	case err != nil && timedOut:
+		return p.fallback(promptStr, o, ErrPromptTimeout)
+	case err != nil:
+		return "", promptError(promptStr, err)
+	case answer == "":
+		return p.fallback(promptStr, o, errEmptyAnswer)
+	}
+	return answer, nil
+}
 
-	str, err := prompt.Run()
-	if err != nil {
-		return "", err
+// selectItem asks to select one of items, and returns its index
+func (p *realPrompter) selectItem(promptStr string, items []string, opts []Option) (int, error) {
+	o := buildOptions(p.defaults, opts)
+	defaultIndex := -1
+	if o.defaultAnswer != nil {
+		for i, item := range items {
+			if item == *o.defaultAnswer {
+				defaultIndex = i
+			}
+		}
+		if defaultIndex < 0 {
+			return 0, fmt.Errorf("default answer %q to %q is not one of %s", *o.defaultAnswer, promptStr, strings.Join(items, ", "))
+		}
+	}
+	selectDefault := func(err error) (int, error) {
+		if _, err := p.fallback(promptStr, o, err); err != nil {
+			return 0, err
+		}
+		return defaultIndex, nil
 	}
+	if p.headless {
+		return selectDefault(ErrHeadless)
+	}
+	label := o.label(promptStr, false)
 
-	return str, nil
-}
+	if p.lines != nil {
+		fmt.Fprintln(p.out, label)
+		for i, item := range items {
+			fmt.Fprintf(p.out, "  %d. %s\n", i+1, item)
+		}
+		fmt.Fprint(p.out, "> ")
+		answer, err := p.lines.next(o.timeout)
+		fmt.Fprintln(p.out)
+		if err == nil && answer == "" {
+			err = errEmptyAnswer
+		}
+		if err != nil {
+			return selectDefault(err)
+		}
+		i, err := matchOption(answer, items)
+		if err != nil {
+			return 0, exitcodes.UserInput(fmt.Errorf("invalid answer to %q: %w", promptStr, err))
+		}
+		return i, nil
+	}
 
-func (*realPrompter) CaptureIndex(promptStr string, options []common.Address) (int, error) {
+	stdin := newStdin()
 	prompt := promptui.Select{
-		Label: promptStr,
-		Items: options,
+		Label: label,
+		Items: items,
+		Stdin: stdin,
 	}
-
-	listIndex, _, err := prompt.Run()
-	if err != nil {
-		return 0, err
+	if defaultIndex > 0 {
+		prompt.CursorPos = defaultIndex
 	}
-	return listIndex, nil
+	var i int
+	timedOut, err := runWithTimeout(stdin, o.timeout, func() (err error) {
+		i, _, err = prompt.Run()
+		return err
+	})
+	switch {
+	case err != nil && timedOut:
+		return selectDefault(ErrPromptTimeout)
+	case err != nil:
+		return 0, promptError(promptStr, err)
+	}
+	return i, nil
+}
+
+// fallback returns the default answer of a prompt left unanswered because
+// of err, or the error to return if it has none
+func (p *realPrompter) fallback(promptStr string, o options, err error) (string, error) {
+	if o.defaultAnswer != nil {
+		if errors.Is(err, ErrPromptTimeout) {
+			fmt.Fprintf(p.out, "No answer after %s, using the default %q\n", ux.FormatDuration(o.timeout), *o.defaultAnswer)
+		}
+		return *o.defaultAnswer, nil
+	}
+	switch {
+	case errors.Is(err, ErrHeadless):
+		return "", headlessError(promptStr)
+	case errors.Is(err, ErrPromptTimeout):
+		return "", exitcodes.UserInput(fmt.Errorf("%w: %q, after %s", ErrPromptTimeout, promptStr, ux.FormatDuration(o.timeout)))
+	case errors.Is(err, io.EOF):
+		return "", exitcodes.UserInput(fmt.Errorf("%w %q", ErrNoInput, promptStr))
+	case errors.Is(err, errEmptyAnswer):
+		return "", exitcodes.UserInput(fmt.Errorf("no answer to %q", promptStr))
+	}
+	return "", err
 }
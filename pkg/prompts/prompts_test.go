@@ -0,0 +1,180 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPipePrompter(input string, defaults ...Option) *realPrompter {
+	return &realPrompter{defaults: defaults, lines: newLineReader(strings.NewReader(input)), out: &bytes.Buffer{}}
+}
+
+func TestPipedAnswers(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newPipePrompter("mySubnet\n\n2\nfuji\n\nmaybe\n")
+	name, err := p.CaptureString("Name")
+	assert.NoError(err)
+	assert.Equal("mySubnet", name)
+
+	// an empty line takes the default answer
+	name, err = p.CaptureString("Name", WithDefault("other"))
+	assert.NoError(err)
+	assert.Equal("other", name)
+
+	networks := []string{"Local Network", "Fuji", "Mainnet"}
+	network, err := p.CaptureList("Network", networks)
+	assert.NoError(err)
+	assert.Equal("Fuji", network)
+	network, err = p.CaptureList("Network", networks)
+	assert.NoError(err)
+	assert.Equal("Fuji", network)
+
+	yes, err := p.CaptureYesNo("Sure?", WithDefault(No))
+	assert.NoError(err)
+	assert.False(yes)
+
+	_, err = p.CaptureYesNo("Sure?")
+	assert.ErrorContains(err, `"maybe" is not one of Yes, No`)
+
+	// the end of the input takes the default answer, or fails without one
+	weight, err := p.CaptureWeight("Weight", WithDefault("20"))
+	assert.NoError(err)
+	assert.Equal(uint64(20), weight)
+	_, err = p.CaptureString("Name")
+	assert.ErrorIs(err, ErrNoInput)
+
+	_, err = p.CaptureList("Network", networks, WithDefault("Devnet"))
+	assert.ErrorContains(err, `default answer "Devnet"`)
+}
+
+func TestPromptTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	r, w := io.Pipe()
+	defer w.Close()
+	p := &realPrompter{
+		defaults: []Option{WithTimeout(10 * time.Millisecond)},
+		lines:    newLineReader(r),
+		out:      &bytes.Buffer{},
+	}
+	answer, err := p.CaptureList("Network", []string{"Local Network", "Fuji"}, WithDefault("Fuji"))
+	assert.NoError(err)
+	assert.Equal("Fuji", answer)
+
+	_, err = p.CaptureString("Name")
+	assert.ErrorIs(err, ErrPromptTimeout)
+
+	// a late answer is kept for the next prompt
+	go func() {
+		_, _ = w.Write([]byte("late\n"))
+	}()
+	answer, err = p.CaptureString("Name", WithTimeout(0))
+	assert.NoError(err)
+	assert.Equal("late", answer)
+}
+
+func TestHeadlessDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewHeadlessPrompter()
+	yes, err := p.CaptureNoYes("Sure?", WithDefault(No))
+	assert.NoError(err)
+	assert.False(yes)
+
+	_, err = p.CaptureNoYes("Sure?")
+	assert.ErrorIs(err, ErrHeadless)
+
+	_, err = p.CaptureUint64("Threshold", WithDefault("0"))
+	assert.ErrorContains(err, "invalid default answer")
+}
+
+func TestConfirmDestructive(t *testing.T) {
+	assert := assert.New(t)
+
+	p := newPipePrompter("mySubnet\nother\n\n")
+	assert.NoError(p.ConfirmDestructive("Delete?", "mySubnet"))
+	assert.ErrorIs(p.ConfirmDestructive("Delete?", "mySubnet"), ErrNotConfirmed)
+	// an empty answer doesn't confirm, even with a default
+	assert.Error(p.ConfirmDestructive("Delete?", "mySubnet", WithDefault("mySubnet")))
+
+	headless := NewHeadlessPrompter()
+	assert.ErrorIs(headless.ConfirmDestructive("Delete?", "mySubnet"), ErrHeadless)
+	headless = NewHeadlessPrompter(WithAssumeYes(true))
+	assert.NoError(headless.ConfirmDestructive("Delete?", "mySubnet"))
+}
+
+func TestOptionLabel(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Name", buildOptions(nil, nil).label("Name", false))
+	o := buildOptions([]Option{WithDefault("a")}, []Option{WithDefault("b")})
+	assert.Equal("Name [b]", o.label("Name", false))
+	assert.Equal("Passphrase [********]", o.label("Passphrase", true))
+}
+
+func TestConvertPChainAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	key := ids.GenerateTestShortID()
+	fujiAddr, _ := address.Format("P", avago_constants.FujiHRP, key.Bytes())
+	mainnetAddr, _ := address.Format("P", avago_constants.MainnetHRP, key.Bytes())
+	xAddr, _ := address.Format("X", avago_constants.MainnetHRP, key.Bytes())
+
+	converted, ok := ConvertPChainAddress(mainnetAddr, models.Fuji)
+	assert.True(ok)
+	assert.Equal(fujiAddr, converted)
+	converted, ok = ConvertPChainAddress(fujiAddr, models.Mainnet)
+	assert.True(ok)
+	assert.Equal(mainnetAddr, converted)
+
+	// addresses of the network, of another chain or malformed aren't
+	_, ok = ConvertPChainAddress(fujiAddr, models.Fuji)
+	assert.False(ok)
+	_, ok = ConvertPChainAddress(xAddr, models.Fuji)
+	assert.False(ok)
+	_, ok = ConvertPChainAddress("P-fuji1xyz", models.Fuji)
+	assert.False(ok)
+}
+
+func TestResolvePChainAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	key := ids.GenerateTestShortID()
+	fujiAddr, _ := address.Format("P", avago_constants.FujiHRP, key.Bytes())
+	mainnetAddr, _ := address.Format("P", avago_constants.MainnetHRP, key.Bytes())
+
+	// the conversion is confirmed when the address is typed
+	p := newPipePrompter(mainnetAddr + "\nYes\n" + mainnetAddr + "\nNo\n")
+	addr, err := p.CapturePChainAddress("Control key", models.Fuji)
+	assert.NoError(err)
+	assert.Equal(fujiAddr, addr)
+	_, err = p.CapturePChainAddress("Control key", models.Fuji)
+	assert.ErrorContains(err, "this address is for avax, not fuji")
+
+	// or when given with a flag
+	p = newPipePrompter("Yes\n")
+	addr, err = ResolvePChainAddress(p, mainnetAddr, models.Fuji, "control key")
+	assert.NoError(err)
+	assert.Equal(fujiAddr, addr)
+	addr, err = ResolvePChainAddress(p, fujiAddr, models.Fuji, "control key")
+	assert.NoError(err)
+	assert.Equal(fujiAddr, addr)
+
+	// the malformed address is named
+	_, err = ResolvePChainAddress(p, "P-fuji1xyz", models.Fuji, "control key")
+	assert.ErrorContains(err, `invalid control key "P-fuji1xyz": malformed address`)
+	_, err = ResolvePChainAddress(NewHeadlessPrompter(), mainnetAddr, models.Fuji, "control key")
+	assert.ErrorIs(err, ErrHeadless)
+}
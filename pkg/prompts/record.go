@@ -0,0 +1,328 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Answer is the answer given to a prompt, as it would be typed or selected
+type Answer struct {
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer"`
+}
+
+// Answers are the answers given in a session, in the order of the prompts
+type Answers struct {
+	Answers []Answer `json:"answers"`
+}
+
+// LoadAnswers reads the answers file at path
+func LoadAnswers(path string) (Answers, error) {
+	var answers Answers
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return answers, err
+	}
+	if err := json.Unmarshal(content, &answers); err != nil {
+		return answers, fmt.Errorf("invalid answers file %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// Recorder records the answers given to the prompts of the Prompter it
+// wraps. Passwords and the confirmations of destructive operations are
+// never recorded.
+type Recorder struct {
+	Prompter
+	answers []Answer
+}
+
+// NewRecorder creates a recorder of the answers given to p
+func NewRecorder(p Prompter) *Recorder {
+	return &Recorder{Prompter: p}
+}
+
+// Answers returns the answers recorded so far
+func (r *Recorder) Answers() Answers {
+	return Answers{Answers: append([]Answer{}, r.answers...)}
+}
+
+func (r *Recorder) record(promptStr string, answer string, err error) {
+	if err == nil {
+		r.answers = append(r.answers, Answer{Prompt: promptStr, Answer: answer})
+	}
+}
+
+func (r *Recorder) CapturePositiveBigInt(promptStr string, opts ...Option) (*big.Int, error) {
+	n, err := r.Prompter.CapturePositiveBigInt(promptStr, opts...)
+	r.record(promptStr, n.String(), err)
+	return n, err
+}
+
+func (r *Recorder) CaptureAddress(promptStr string, opts ...Option) (common.Address, error) {
+	addr, err := r.Prompter.CaptureAddress(promptStr, opts...)
+	r.record(promptStr, addr.Hex(), err)
+	return addr, err
+}
+
+func (r *Recorder) CaptureExistingFilepath(promptStr string, opts ...Option) (string, error) {
+	path, err := r.Prompter.CaptureExistingFilepath(promptStr, opts...)
+	r.record(promptStr, path, err)
+	return path, err
+}
+
+func (r *Recorder) CaptureYesNo(promptStr string, opts ...Option) (bool, error) {
+	yes, err := r.Prompter.CaptureYesNo(promptStr, opts...)
+	r.record(promptStr, yesNo(yes), err)
+	return yes, err
+}
+
+func (r *Recorder) CaptureNoYes(promptStr string, opts ...Option) (bool, error) {
+	yes, err := r.Prompter.CaptureNoYes(promptStr, opts...)
+	r.record(promptStr, yesNo(yes), err)
+	return yes, err
+}
+
+func (r *Recorder) CaptureList(promptStr string, options []string, opts ...Option) (string, error) {
+	option, err := r.Prompter.CaptureList(promptStr, options, opts...)
+	r.record(promptStr, option, err)
+	return option, err
+}
+
+func (r *Recorder) CaptureString(promptStr string, opts ...Option) (string, error) {
+	s, err := r.Prompter.CaptureString(promptStr, opts...)
+	r.record(promptStr, s, err)
+	return s, err
+}
+
+func (r *Recorder) CaptureIndex(promptStr string, options []common.Address, opts ...Option) (int, error) {
+	i, err := r.Prompter.CaptureIndex(promptStr, options, opts...)
+	if err == nil {
+		r.record(promptStr, options[i].Hex(), err)
+	}
+	return i, err
+}
+
+func (r *Recorder) CaptureDuration(promptStr string, opts ...Option) (time.Duration, error) {
+	d, err := r.Prompter.CaptureDuration(promptStr, opts...)
+	r.record(promptStr, d.String(), err)
+	return d, err
+}
+
+func (r *Recorder) CaptureDate(promptStr string, opts ...Option) (time.Time, error) {
+	t, err := r.Prompter.CaptureDate(promptStr, opts...)
+	r.record(promptStr, t.Format(time.RFC3339), err)
+	return t, err
+}
+
+func (r *Recorder) CaptureNodeID(promptStr string, opts ...Option) (ids.NodeID, error) {
+	nodeID, err := r.Prompter.CaptureNodeID(promptStr, opts...)
+	r.record(promptStr, nodeID.String(), err)
+	return nodeID, err
+}
+
+func (r *Recorder) CaptureWeight(promptStr string, opts ...Option) (uint64, error) {
+	weight, err := r.Prompter.CaptureWeight(promptStr, opts...)
+	r.record(promptStr, strconv.FormatUint(weight, 10), err)
+	return weight, err
+}
+
+func (r *Recorder) CaptureUint64(promptStr string, opts ...Option) (uint64, error) {
+	n, err := r.Prompter.CaptureUint64(promptStr, opts...)
+	r.record(promptStr, strconv.FormatUint(n, 10), err)
+	return n, err
+}
+
+func (r *Recorder) CapturePChainAddress(promptStr string, network models.Network, opts ...Option) (string, error) {
+	addr, err := r.Prompter.CapturePChainAddress(promptStr, network, opts...)
+	r.record(promptStr, addr, err)
+	return addr, err
+}
+
+func yesNo(yes bool) string {
+	if yes {
+		return Yes
+	}
+	return No
+}
+
+// Replayer answers the prompts with the answers of a recorded session,
+// validated as if they were typed. Each prompt takes the first answer not
+// replayed yet to the same prompt, so that answers can be edited, or
+// removed for the Prompter it wraps to ask them again, and the answers to
+// the prompts the edits skip are left out.
+type Replayer struct {
+	Prompter
+	answers  []Answer
+	replayed []bool
+	// headless validates and parses the answers, given as defaults
+	headless Prompter
+	out      io.Writer
+}
+
+// NewReplayer creates a replayer of answers, asking p the prompts they
+// don't answer
+func NewReplayer(p Prompter, answers Answers) *Replayer {
+	return &Replayer{
+		Prompter: p,
+		answers:  answers.Answers,
+		replayed: make([]bool, len(answers.Answers)),
+		headless: NewHeadlessPrompter(),
+		out:      os.Stdout,
+	}
+}
+
+// Unreplayed returns the answers to prompts which were not asked
+func (r *Replayer) Unreplayed() []Answer {
+	unreplayed := []Answer{}
+	for i, answer := range r.answers {
+		if !r.replayed[i] {
+			unreplayed = append(unreplayed, answer)
+		}
+	}
+	return unreplayed
+}
+
+// next returns the answer to replay to promptStr, if any
+func (r *Replayer) next(promptStr string) (string, []Option, bool) {
+	for i, answer := range r.answers {
+		if !r.replayed[i] && answer.Prompt == promptStr {
+			r.replayed[i] = true
+			fmt.Fprintf(r.out, "%s: %s (replayed)\n", promptStr, answer.Answer)
+			return answer.Answer, []Option{WithDefault(answer.Answer)}, true
+		}
+	}
+	return "", nil, false
+}
+
+// replayError returns err of an answer which can't be replayed, if any
+func replayError(promptStr string, answer string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return exitcodes.UserInput(fmt.Errorf("can't replay %q as the answer to %q: %w", answer, promptStr, err))
+}
+
+func (r *Replayer) CapturePositiveBigInt(promptStr string, opts ...Option) (*big.Int, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		n, err := r.headless.CapturePositiveBigInt(promptStr, append(opts, answerOpts...)...)
+		return n, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CapturePositiveBigInt(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureAddress(promptStr string, opts ...Option) (common.Address, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		addr, err := r.headless.CaptureAddress(promptStr, append(opts, answerOpts...)...)
+		return addr, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureAddress(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureExistingFilepath(promptStr string, opts ...Option) (string, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		path, err := r.headless.CaptureExistingFilepath(promptStr, append(opts, answerOpts...)...)
+		return path, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureExistingFilepath(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureYesNo(promptStr string, opts ...Option) (bool, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		yes, err := r.headless.CaptureYesNo(promptStr, append(opts, answerOpts...)...)
+		return yes, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureYesNo(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureNoYes(promptStr string, opts ...Option) (bool, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		yes, err := r.headless.CaptureNoYes(promptStr, append(opts, answerOpts...)...)
+		return yes, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureNoYes(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureList(promptStr string, options []string, opts ...Option) (string, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		option, err := r.headless.CaptureList(promptStr, options, append(opts, answerOpts...)...)
+		return option, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureList(promptStr, options, opts...)
+}
+
+func (r *Replayer) CaptureString(promptStr string, opts ...Option) (string, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		s, err := r.headless.CaptureString(promptStr, append(opts, answerOpts...)...)
+		return s, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureString(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureIndex(promptStr string, options []common.Address, opts ...Option) (int, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		i, err := r.headless.CaptureIndex(promptStr, options, append(opts, answerOpts...)...)
+		return i, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureIndex(promptStr, options, opts...)
+}
+
+func (r *Replayer) CaptureDuration(promptStr string, opts ...Option) (time.Duration, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		d, err := r.headless.CaptureDuration(promptStr, append(opts, answerOpts...)...)
+		return d, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureDuration(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureDate(promptStr string, opts ...Option) (time.Time, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		t, err := r.headless.CaptureDate(promptStr, append(opts, answerOpts...)...)
+		return t, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureDate(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureNodeID(promptStr string, opts ...Option) (ids.NodeID, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		nodeID, err := r.headless.CaptureNodeID(promptStr, append(opts, answerOpts...)...)
+		return nodeID, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureNodeID(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureWeight(promptStr string, opts ...Option) (uint64, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		weight, err := r.headless.CaptureWeight(promptStr, append(opts, answerOpts...)...)
+		return weight, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureWeight(promptStr, opts...)
+}
+
+func (r *Replayer) CaptureUint64(promptStr string, opts ...Option) (uint64, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		n, err := r.headless.CaptureUint64(promptStr, append(opts, answerOpts...)...)
+		return n, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CaptureUint64(promptStr, opts...)
+}
+
+func (r *Replayer) CapturePChainAddress(promptStr string, network models.Network, opts ...Option) (string, error) {
+	if answer, answerOpts, ok := r.next(promptStr); ok {
+		addr, err := r.headless.CapturePChainAddress(promptStr, network, append(opts, answerOpts...)...)
+		return addr, replayError(promptStr, answer, err)
+	}
+	return r.Prompter.CapturePChainAddress(promptStr, network, opts...)
+}
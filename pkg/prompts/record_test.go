@@ -0,0 +1,73 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// interview asks the prompts of a wizard, in which the supply is only asked
+// for a custom token
+func interview(p Prompter) (string, bool, uint64, error) {
+	name, err := p.CaptureString("Token name")
+	if err != nil {
+		return "", false, 0, err
+	}
+	custom, err := p.CaptureYesNo("Custom supply?")
+	if err != nil || !custom {
+		return name, custom, 0, err
+	}
+	supply, err := p.CaptureUint64("Supply")
+	return name, custom, supply, err
+}
+
+func TestRecordReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	recorder := NewRecorder(newPipePrompter("TOK\nYes\n1000\n"))
+	_, _, _, err := interview(recorder)
+	assert.NoError(err)
+	answers := recorder.Answers()
+	assert.Equal([]Answer{
+		{Prompt: "Token name", Answer: "TOK"},
+		{Prompt: "Custom supply?", Answer: Yes},
+		{Prompt: "Supply", Answer: "1000"},
+	}, answers.Answers)
+
+	// the answers are replayed without asking
+	replayer := NewReplayer(newPipePrompter(""), answers)
+	replayer.out = &bytes.Buffer{}
+	name, custom, supply, err := interview(replayer)
+	assert.NoError(err)
+	assert.Equal("TOK", name)
+	assert.True(custom)
+	assert.Equal(uint64(1000), supply)
+	assert.Empty(replayer.Unreplayed())
+
+	// a removed answer is asked again
+	edited := Answers{Answers: []Answer{answers.Answers[0], answers.Answers[1]}}
+	replayer = NewReplayer(newPipePrompter("5\n"), edited)
+	replayer.out = &bytes.Buffer{}
+	_, _, supply, err = interview(replayer)
+	assert.NoError(err)
+	assert.Equal(uint64(5), supply)
+
+	// an edited answer skipping a prompt leaves its answer out
+	edited = Answers{Answers: []Answer{answers.Answers[0], {Prompt: "Custom supply?", Answer: No}, answers.Answers[2]}}
+	replayer = NewReplayer(newPipePrompter(""), edited)
+	replayer.out = &bytes.Buffer{}
+	_, custom, _, err = interview(replayer)
+	assert.NoError(err)
+	assert.False(custom)
+	assert.Equal([]Answer{answers.Answers[2]}, replayer.Unreplayed())
+
+	// the answers are validated as if typed
+	edited = Answers{Answers: []Answer{answers.Answers[0], {Prompt: "Custom supply?", Answer: "maybe"}}}
+	replayer = NewReplayer(newPipePrompter(""), edited)
+	replayer.out = &bytes.Buffer{}
+	_, _, _, err = interview(replayer)
+	assert.ErrorContains(err, `can't replay "maybe" as the answer to "Custom supply?"`)
+}
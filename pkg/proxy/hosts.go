@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package proxy
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// the entries of the hosts file managed by this tool are kept between these
+// lines, so that they can be updated without touching the others
+const (
+	hostsBlockBegin = "# BEGIN avalanche-cli proxy"
+	hostsBlockEnd   = "# END avalanche-cli proxy"
+)
+
+// HostsEntries returns the hosts file lines resolving the Hostname of every
+// subnet to the loopback address, sorted by host name
+func HostsEntries(subnetNames []string) []string {
+	entries := make([]string, 0, len(subnetNames))
+	for _, name := range subnetNames {
+		entries = append(entries, "127.0.0.1 "+Hostname(name))
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// UpdateHostsFile replaces the entries managed by this tool in the hosts file
+// at path with the ones of subnetNames. Without subnetNames, the managed
+// entries are removed.
+func UpdateHostsFile(path string, subnetNames []string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{}
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		switch {
+		case strings.TrimSpace(line) == hostsBlockBegin:
+			inBlock = true
+		case strings.TrimSpace(line) == hostsBlockEnd:
+			inBlock = false
+		case !inBlock:
+			lines = append(lines, line)
+		}
+	}
+	if inBlock {
+		return fmt.Errorf("%s has no %q line closing the entries of the proxy", path, hostsBlockEnd)
+	}
+	if len(subnetNames) > 0 {
+		lines = append(lines, hostsBlockBegin)
+		lines = append(lines, HostsEntries(subnetNames)...)
+		lines = append(lines, hostsBlockEnd)
+	}
+
+	// the hosts file is written in place, as it is often a mount point which
+	// can't be replaced
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), info.Mode().Perm())
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateHostsFile(t *testing.T) {
+	assert := assert.New(t)
+
+	original := "127.0.0.1 localhost\n::1 localhost\n"
+	path := filepath.Join(t.TempDir(), "hosts")
+	assert.NoError(os.WriteFile(path, []byte(original), 0o644))
+
+	assert.NoError(UpdateHostsFile(path, []string{"subnetB", "SubnetA"}))
+	content, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(original+
+		"# BEGIN avalanche-cli proxy\n"+
+		"127.0.0.1 subneta.avax.local\n"+
+		"127.0.0.1 subnetb.avax.local\n"+
+		"# END avalanche-cli proxy\n", string(content))
+
+	// the managed entries are replaced, the others kept
+	assert.NoError(UpdateHostsFile(path, []string{"subnetC"}))
+	content, err = os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(original+
+		"# BEGIN avalanche-cli proxy\n"+
+		"127.0.0.1 subnetc.avax.local\n"+
+		"# END avalanche-cli proxy\n", string(content))
+
+	assert.NoError(UpdateHostsFile(path, nil))
+	content, err = os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(original, string(content))
+
+	assert.NoError(os.WriteFile(path, []byte(original+"# BEGIN avalanche-cli proxy\n"), 0o644))
+	assert.Error(UpdateHostsFile(path, nil))
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/supervisor"
+)
+
+// ErrNotRunning is returned when stopping a proxy which is not running
+var ErrNotRunning = errors.New("the RPC proxy is not running")
+
+// RunInfo is the information of a running proxy process
+type RunInfo struct {
+	Pid        int    `json:"pid"`
+	Port       uint16 `json:"port"`
+	OutputFile string `json:"outputFile"`
+}
+
+// URL returns the base URL of the proxy
+func (ri RunInfo) URL() string {
+	return fmt.Sprintf("http://localhost:%d", ri.Port)
+}
+
+// GetRunInfo returns the information of the proxy of the profile of app, and
+// whether its process is running
+func GetRunInfo(app *application.Avalanche) (RunInfo, bool, error) {
+	svc, err := supervisor.Get(app, constants.ProxyService)
+	if err != nil || svc == nil {
+		return RunInfo{}, false, err
+	}
+	return runInfo(svc), svc.Running(), nil
+}
+
+// StartProcess starts the proxy of the profile of app, listening on port, as
+// a reentrant process of this binary, restarted if it fails.
+// It just executes `avalanche network proxy serve`
+func StartProcess(app *application.Avalanche, port uint16) (RunInfo, error) {
+	svc, err := supervisor.Start(app, supervisor.Spec{
+		Name:          constants.ProxyService,
+		Args:          []string{"network", "proxy", "serve", "--port", strconv.Itoa(int(port)), "--profile", app.GetProfile()},
+		Restart:       supervisor.RestartOnFailure,
+		MaxRestarts:   constants.ServiceMaxRestarts,
+		OutputDir:     "proxy",
+		OutputFile:    "avalanche-cli-proxy",
+		Port:          int(port),
+		CorrelationID: app.GetCorrelationID(),
+		CLIVersion:    app.GetVersion(),
+	})
+	if err != nil {
+		return RunInfo{}, err
+	}
+	return runInfo(svc), nil
+}
+
+// StopProcess stops the proxy of the profile of app
+func StopProcess(app *application.Avalanche) error {
+	err := supervisor.Stop(app, constants.ProxyService)
+	if errors.Is(err, supervisor.ErrNotRunning) {
+		return ErrNotRunning
+	}
+	return err
+}
+
+func runInfo(svc *supervisor.Service) RunInfo {
+	return RunInfo{Pid: svc.Pid, Port: uint16(svc.Port), OutputFile: svc.OutputFile}
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// healthPath is the path of the health API of an avalanchego node
+	healthPath = "/ext/health"
+	// DomainSuffix is the domain the blockchains are served under by host
+	// name, as an alternative to their path
+	DomainSuffix = ".avax.local"
+)
+
+// Target is a blockchain served by the proxy, and the URIs of the healthy
+// nodes it can be routed to, in order of preference
+type Target struct {
+	BlockchainID string
+	NodeURIs     []string
+}
+
+// Proxy is a reverse proxy exposing every blockchain of the local network
+// under a stable path, /<subnetName>/rpc and /<subnetName>/ws, routed to a
+// healthy node. /<subnetName> is an alias of /<subnetName>/rpc.
+// The blockchains are also served at the root of their Hostname, for the
+// requests whose host resolves to the proxy, e.g. through a hosts file entry.
+type Proxy struct {
+	lock    sync.RWMutex
+	targets map[string]Target
+	log     logging.Logger
+}
+
+// New returns a proxy with no routes
+func New(log logging.Logger) *Proxy {
+	return &Proxy{
+		targets: map[string]Target{},
+		log:     log,
+	}
+}
+
+// SetTargets replaces the routes of the proxy with targets, given by subnet
+// name. It returns true if the routes changed.
+func (p *Proxy) SetTargets(targets map[string]Target) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if equalTargets(p.targets, targets) {
+		return false
+	}
+	p.targets = targets
+	return true
+}
+
+func equalTargets(a, b map[string]Target) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, ta := range a {
+		tb, ok := b[name]
+		if !ok || ta.BlockchainID != tb.BlockchainID || strings.Join(ta.NodeURIs, ",") != strings.Join(tb.NodeURIs, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Proxy) getTarget(name string) (Target, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	t, ok := p.targets[name]
+	return t, ok
+}
+
+// getTargetByHost returns the target whose Hostname is host, ignoring the
+// port, along with its subnet name. The last result is false if host is not
+// under DomainSuffix; a zero Target is returned if no subnet has this host.
+func (p *Proxy) getTargetByHost(host string) (string, Target, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	if !strings.HasSuffix(host, DomainSuffix) {
+		return "", Target{}, false
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for name, t := range p.targets {
+		if Hostname(name) == host {
+			return name, t, true
+		}
+	}
+	return strings.TrimSuffix(host, DomainSuffix), Target{}, true
+}
+
+// Hostname returns the host name the blockchain of subnetName is served at,
+// <subnetName>.avax.local
+func Hostname(subnetName string) string {
+	return strings.ToLower(subnetName) + DomainSuffix
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// /<subnetName>[/rpc|/ws], or [/rpc|/ws] at <subnetName>.avax.local
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	name, target, byHost := p.getTargetByHost(r.Host)
+	ok := byHost && target.BlockchainID != ""
+	if byHost {
+		parts = append([]string{name}, parts...)
+		if parts[1] == "" {
+			parts = parts[:1]
+		}
+	} else {
+		name = parts[0]
+		target, ok = p.getTarget(name)
+	}
+	if len(parts) > 2 {
+		http.NotFound(w, r)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("no blockchain deployed for subnet %q", name), http.StatusNotFound)
+		return
+	}
+	if len(target.NodeURIs) == 0 {
+		http.Error(w, fmt.Sprintf("no healthy node for subnet %q", name), http.StatusServiceUnavailable)
+		return
+	}
+
+	var endpoint string
+	switch {
+	case len(parts) == 1 || parts[1] == "rpc":
+		endpoint = ux.RPCEndpoint(target.NodeURIs[0], target.BlockchainID)
+	case parts[1] == "ws":
+		// the websocket handshake is a plain HTTP request to the node,
+		// upgraded by the reverse proxy
+		endpoint = fmt.Sprintf("%s/ext/bc/%s/ws", target.NodeURIs[0], target.BlockchainID)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rp := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = u.Scheme
+			req.URL.Host = u.Host
+			req.URL.Path = u.Path
+			req.URL.RawPath = ""
+			req.Host = u.Host
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			p.log.Warn("failed proxying request for subnet %s to %s: %s", name, u, err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	rp.ServeHTTP(w, r)
+}
+
+// nodeHealthy returns true if the node at uri reports itself healthy
+func nodeHealthy(ctx context.Context, client *http.Client, uri string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri+healthPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// BuildTargets returns the targets of every blockchain of the cluster, by
+// subnet name, routed to its healthy nodes sorted by name. subnetNames maps
+// the VM IDs of the local subnets to their names; blockchains of unknown VMs
+// are exposed under their VM name.
+func BuildTargets(ctx context.Context, clusterInfo *rpcpb.ClusterInfo, subnetNames map[string]string) map[string]Target {
+	nodes := make([]string, 0, len(clusterInfo.GetNodeInfos()))
+	for name := range clusterInfo.GetNodeInfos() {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+
+	client := &http.Client{}
+	healthyURIs := []string{}
+	for _, name := range nodes {
+		uri := clusterInfo.NodeInfos[name].GetUri()
+		if nodeHealthy(ctx, client, uri) {
+			healthyURIs = append(healthyURIs, uri)
+		}
+	}
+
+	targets := map[string]Target{}
+	for blockchainID, vmInfo := range clusterInfo.GetCustomVms() {
+		name, ok := subnetNames[vmInfo.GetVmId()]
+		if !ok {
+			name = vmInfo.GetVmName()
+		}
+		targets[name] = Target{
+			BlockchainID: blockchainID,
+			NodeURIs:     healthyURIs,
+		}
+	}
+	return targets
+}
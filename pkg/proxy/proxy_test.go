@@ -0,0 +1,131 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBlockchainID = "S4mMqUXe7vHsGiRAma6bv3CKnyaLssyAxmQ2KvFpX1KEvfFCD"
+
+// newTestNode returns a node answering with its path, healthy or not
+func newTestNode(healthy bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == healthPath && !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, r.URL.Path)
+	}))
+}
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url) //nolint:gosec
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestBuildTargets(t *testing.T) {
+	assert := assert.New(t)
+
+	unhealthy := newTestNode(false)
+	defer unhealthy.Close()
+	healthy := newTestNode(true)
+	defer healthy.Close()
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", Uri: unhealthy.URL},
+			"node2": {Name: "node2", Uri: healthy.URL},
+		},
+		CustomVms: map[string]*rpcpb.CustomVmInfo{
+			testBlockchainID: {VmName: "mySubnet", VmId: "vmID1", BlockchainId: testBlockchainID},
+			"otherChain":     {VmName: "other", VmId: "vmID2", BlockchainId: "otherChain"},
+		},
+	}
+	targets := BuildTargets(context.Background(), clusterInfo, map[string]string{"vmID1": "renamed"})
+	assert.Equal(map[string]Target{
+		"renamed": {BlockchainID: testBlockchainID, NodeURIs: []string{healthy.URL}},
+		"other":   {BlockchainID: "otherChain", NodeURIs: []string{healthy.URL}},
+	}, targets)
+}
+
+func TestProxyRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	node := newTestNode(true)
+	defer node.Close()
+
+	p := New(logging.NoLog{})
+	s := httptest.NewServer(p)
+	defer s.Close()
+
+	code, _ := get(t, s.URL+"/mySubnet/rpc")
+	assert.Equal(http.StatusNotFound, code)
+
+	assert.True(p.SetTargets(map[string]Target{"mySubnet": {BlockchainID: testBlockchainID, NodeURIs: []string{node.URL}}}))
+	assert.False(p.SetTargets(map[string]Target{"mySubnet": {BlockchainID: testBlockchainID, NodeURIs: []string{node.URL}}}))
+
+	code, body := get(t, s.URL+"/mySubnet/rpc")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("/ext/bc/"+testBlockchainID+"/rpc", body)
+	_, body = get(t, s.URL+"/mySubnet")
+	assert.Equal("/ext/bc/"+testBlockchainID+"/rpc", body)
+	_, body = get(t, s.URL+"/mySubnet/ws")
+	assert.Equal("/ext/bc/"+testBlockchainID+"/ws", body)
+	code, _ = get(t, s.URL+"/mySubnet/other")
+	assert.Equal(http.StatusNotFound, code)
+
+	// no healthy node
+	assert.True(p.SetTargets(map[string]Target{"mySubnet": {BlockchainID: testBlockchainID}}))
+	code, _ = get(t, s.URL+"/mySubnet/rpc")
+	assert.Equal(http.StatusServiceUnavailable, code)
+}
+
+func TestProxyHostRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	node := newTestNode(true)
+	defer node.Close()
+
+	p := New(logging.NoLog{})
+	s := httptest.NewServer(p)
+	defer s.Close()
+	p.SetTargets(map[string]Target{"mySubnet": {BlockchainID: testBlockchainID, NodeURIs: []string{node.URL}}})
+
+	getHost := func(host string, path string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, s.URL+path, nil)
+		assert.NoError(err)
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := getHost("mysubnet.avax.local:8545", "/")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("/ext/bc/"+testBlockchainID+"/rpc", body)
+	_, body = getHost("MySubnet.avax.local", "/rpc")
+	assert.Equal("/ext/bc/"+testBlockchainID+"/rpc", body)
+	_, body = getHost("mysubnet.avax.local", "/ws")
+	assert.Equal("/ext/bc/"+testBlockchainID+"/ws", body)
+	code, _ = getHost("mysubnet.avax.local", "/mySubnet/rpc")
+	assert.Equal(http.StatusNotFound, code)
+	code, body = getHost("other.avax.local", "/")
+	assert.Equal(http.StatusNotFound, code)
+	assert.Contains(body, `"other"`)
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package publicapi retries and rate limits the requests to the API endpoints
+// of the public networks, which throttle busy clients and briefly fail under
+// load, so that a deploy or a validator query rides through it instead of
+// failing.
+package publicapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"golang.org/x/time/rate"
+)
+
+// Transport retries the requests to the hosts of the public API endpoints
+// which get a transient status, and caps their rate. The requests to any
+// other host go through Base untouched.
+type Transport struct {
+	Base   http.RoundTripper
+	Hosts  map[string]bool
+	Limits config.PublicAPILimits
+	// OnRetry is called before each retry, with the status which caused it
+	OnRetry func(req *http.Request, status int, wait time.Duration, attempt int)
+
+	limiter *rate.Limiter
+}
+
+// NewTransport creates a transport limiting the requests to hosts over base
+func NewTransport(base http.RoundTripper, hosts []string, limits config.PublicAPILimits) *Transport {
+	t := &Transport{
+		Base:   base,
+		Hosts:  map[string]bool{},
+		Limits: limits,
+	}
+	for _, host := range hosts {
+		t.Hosts[host] = true
+	}
+	if limits.RequestsPerSecond > 0 {
+		burst := int(limits.RequestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), burst)
+	}
+	return t
+}
+
+// Install has the requests of http.DefaultClient, which the avalanchego API
+// clients use, to the public API endpoints of app and the public index APIs
+// retried and rate limited as configured
+func Install(app *application.Avalanche) error {
+	limits, err := app.Conf.PublicAPILimits()
+	if err != nil {
+		return err
+	}
+	urls := []string{constants.GlacierAPIURL, constants.MetricsAPIURL}
+	for _, network := range []models.Network{models.Fuji, models.Mainnet} {
+		endpoints, err := app.GetAPIEndpoints(network)
+		if err != nil {
+			return err
+		}
+		urls = append(urls, endpoints...)
+	}
+	hosts := []string{}
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || parsed.Host == "" {
+			app.Log.Warn("not limiting the requests to invalid API endpoint %q", u)
+			continue
+		}
+		hosts = append(hosts, parsed.Host)
+	}
+	base := http.DefaultClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport := NewTransport(base, hosts, limits)
+	transport.OnRetry = func(req *http.Request, status int, wait time.Duration, attempt int) {
+		app.Log.Info("%s answered %d to %s, retrying in %s", req.URL.Host, status, req.URL.Path, wait)
+		ux.Logger.PrintToUser("The API endpoint %s is busy (%d), retrying in %s (%d/%d)...",
+			req.URL.Host, status, wait.Round(time.Millisecond), attempt, limits.Retries)
+	}
+	http.DefaultClient.Transport = transport
+	return nil
+}
+
+// RoundTrip sends req, retrying it if it is to a public API endpoint and
+// gets a transient status. Once out of retries, the last response is
+// returned for the caller to report its status.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.Hosts[req.URL.Host] {
+		return t.Base.RoundTrip(req)
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if t.limiter != nil {
+			if err := t.limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.Base.RoundTrip(req)
+		if err != nil || !transient(resp.StatusCode) || attempt >= t.Limits.Retries {
+			return resp, err
+		}
+		// a request with a body which can't be sent again is not retried
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+		wait := t.retryWait(resp, attempt)
+		resp.Body.Close()
+		if t.OnRetry != nil {
+			t.OnRetry(req, resp.StatusCode, wait, attempt+1)
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// transient returns true for the statuses of an endpoint throttling the
+// client, or of its gateway not reaching the node. The node didn't process
+// the request, so even the issuance of a transaction can be retried.
+func transient(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryWait returns how long to wait before retrying after resp, the
+// Retry-After the endpoint asks for if any, or else the backoff doubled for
+// each retry, capped to constants.MaxPublicAPIRetryWait
+func (t *Transport) retryWait(resp *http.Response, attempt int) time.Duration {
+	wait := t.Limits.RetryBackoff << attempt
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	}
+	if wait > constants.MaxPublicAPIRetryWait || wait < 0 {
+		wait = constants.MaxPublicAPIRetryWait
+	}
+	return wait
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("gave up retrying: %w", ctx.Err())
+	}
+}
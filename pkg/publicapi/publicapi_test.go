@@ -0,0 +1,141 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package publicapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newEndpoint serves the statuses in order, then 200, and returns the bodies
+// of the requests received
+func newEndpoint(statuses ...int) (*httptest.Server, func() []string) {
+	var (
+		lock   sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		bodies = append(bodies, string(body))
+		status := http.StatusOK
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		lock.Unlock()
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+	}))
+	return server, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return bodies
+	}
+}
+
+func newClient(server *httptest.Server, limits config.PublicAPILimits, retries *[]int) *http.Client {
+	u, _ := url.Parse(server.URL)
+	transport := NewTransport(http.DefaultTransport, []string{u.Host}, limits)
+	transport.OnRetry = func(_ *http.Request, status int, _ time.Duration, _ int) {
+		*retries = append(*retries, status)
+	}
+	return &http.Client{Transport: transport}
+}
+
+func TestRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	server, bodies := newEndpoint(http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	defer server.Close()
+	retries := []int{}
+	client := newClient(server, config.PublicAPILimits{Retries: 3, RetryBackoff: time.Millisecond}, &retries)
+
+	resp, err := client.Post(server.URL, "application/json", bytes.NewBufferString(`{"method":"platform.getHeight"}`))
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal([]int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, retries)
+	// the body is sent again on each retry
+	assert.Equal([]string{`{"method":"platform.getHeight"}`, `{"method":"platform.getHeight"}`, `{"method":"platform.getHeight"}`}, bodies())
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	server, bodies := newEndpoint(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	defer server.Close()
+	retries := []int{}
+	client := newClient(server, config.PublicAPILimits{Retries: 1, RetryBackoff: time.Millisecond}, &retries)
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusBadGateway, resp.StatusCode)
+	assert.Len(bodies(), 2)
+}
+
+func TestNotRetried(t *testing.T) {
+	assert := assert.New(t)
+
+	// errors of the node itself are not transient
+	server, bodies := newEndpoint(http.StatusInternalServerError)
+	defer server.Close()
+	retries := []int{}
+	client := newClient(server, config.PublicAPILimits{Retries: 3, RetryBackoff: time.Millisecond}, &retries)
+	resp, err := client.Get(server.URL)
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	assert.Len(bodies(), 1)
+
+	// nor are the requests to other hosts
+	other, otherBodies := newEndpoint(http.StatusTooManyRequests)
+	defer other.Close()
+	resp, err = client.Get(other.URL)
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	assert.Len(otherBodies(), 1)
+	assert.Empty(retries)
+}
+
+func TestRateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	server, _ := newEndpoint()
+	defer server.Close()
+	retries := []int{}
+	client := newClient(server, config.PublicAPILimits{RequestsPerSecond: 20}, &retries)
+
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		resp, err := client.Get(server.URL)
+		assert.NoError(err)
+		resp.Body.Close()
+	}
+	// the burst of 20 requests goes through at once, the next ones at 20/s
+	assert.GreaterOrEqual(time.Since(start), 200*time.Millisecond)
+}
+
+func TestRetryWait(t *testing.T) {
+	assert := assert.New(t)
+
+	transport := NewTransport(http.DefaultTransport, nil, config.PublicAPILimits{Retries: 10, RetryBackoff: time.Second})
+	resp := &http.Response{Header: http.Header{}}
+	assert.Equal(time.Second, transport.retryWait(resp, 0))
+	assert.Equal(4*time.Second, transport.retryWait(resp, 2))
+	assert.Equal(time.Minute, transport.retryWait(resp, 8))
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(7*time.Second, transport.retryWait(resp, 2))
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registry
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// networks are the networks a chain can be deployed to, in listing order
+var networks = []models.Network{models.Local, models.Fuji, models.Mainnet}
+
+// decimals of the native token of subnet-evm chains
+const nativeDecimals = 18
+
+// Chain is a subnet-evm chain known to the CLI, on one of the networks it is
+// deployed to
+type Chain struct {
+	Subnet      string
+	ChainID     *big.Int
+	TokenSymbol string
+	// Network is Undefined for a chain which isn't deployed
+	Network      models.Network
+	BlockchainID ids.ID
+	// RPCURL is empty if the API endpoint of the network is unknown
+	RPCURL string
+}
+
+// Deployed returns true if the chain is deployed to a network
+func (c Chain) Deployed() bool {
+	return c.Network != models.Undefined
+}
+
+// Build returns the subnet-evm chains of the created subnets, once for each
+// network they are deployed to, or once without network if they aren't
+// deployed. Chains are sorted by subnet name, then by network. endpoints holds
+// the API endpoint of each network, a network without one gets no RPC URL.
+func Build(app *application.Avalanche, endpoints map[models.Network]string) ([]Chain, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	chains := []Chain{}
+	for _, name := range names {
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading subnet %s: %w", name, err)
+		}
+		if sc.VM != models.SubnetEvm {
+			continue
+		}
+		chainID, err := chainID(app, sc)
+		if err != nil {
+			return nil, err
+		}
+		chain := Chain{
+			Subnet:      sc.Name,
+			ChainID:     chainID,
+			TokenSymbol: sc.TokenName,
+		}
+		deployed := false
+		for _, network := range networks {
+			key := network.String()
+			if network == models.Local {
+				key = app.GetLocalNetworkKey()
+			}
+			networkData, ok := sc.Networks[key]
+			if !ok || networkData.BlockchainID == ids.Empty {
+				continue
+			}
+			deployment := chain
+			deployment.Network = network
+			deployment.BlockchainID = networkData.BlockchainID
+			if endpoint := endpoints[network]; endpoint != "" {
+				deployment.RPCURL = ux.RPCEndpoint(endpoint, networkData.BlockchainID.String())
+			}
+			chains = append(chains, deployment)
+			deployed = true
+		}
+		if !deployed {
+			chains = append(chains, chain)
+		}
+	}
+	return chains, nil
+}
+
+// chainID returns the EVM chain ID of the chain of sc, read from its genesis
+// for sidecars older than the chain ID field
+func chainID(app *application.Avalanche, sc models.Sidecar) (*big.Int, error) {
+	if sc.ChainID != "" {
+		chainID, ok := new(big.Int).SetString(sc.ChainID, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid chain ID %q in the sidecar of %s", sc.ChainID, sc.Name)
+		}
+		return chainID, nil
+	}
+	genesis, err := app.LoadEvmGenesis(sc.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading the genesis of %s: %w", sc.Name, err)
+	}
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		return nil, fmt.Errorf("the genesis of %s has no chain ID", sc.Name)
+	}
+	return genesis.Config.ChainID, nil
+}
+
+// NativeCurrency is the native token of a chain in a ChainListEntry
+type NativeCurrency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// ChainListEntry describes a chain in the format of the chain lists used by
+// wallets and frontends, as in https://github.com/ethereum-lists/chains
+type ChainListEntry struct {
+	Name           string         `json:"name"`
+	Chain          string         `json:"chain"`
+	RPC            []string       `json:"rpc"`
+	Faucets        []string       `json:"faucets"`
+	NativeCurrency NativeCurrency `json:"nativeCurrency"`
+	InfoURL        string         `json:"infoURL"`
+	ShortName      string         `json:"shortName"`
+	ChainID        *big.Int       `json:"chainId"`
+	NetworkID      *big.Int       `json:"networkId"`
+}
+
+// ChainList returns the chain list entries of the chains deployed to network.
+// A chain list can't hold a chain ID twice, so the output is per network.
+func ChainList(chains []Chain, network models.Network) []ChainListEntry {
+	entries := []ChainListEntry{}
+	for _, c := range chains {
+		if c.Network != network {
+			continue
+		}
+		rpc := []string{}
+		if c.RPCURL != "" {
+			rpc = append(rpc, c.RPCURL)
+		}
+		entries = append(entries, ChainListEntry{
+			Name:  fmt.Sprintf("%s (%s)", c.Subnet, network),
+			Chain: c.TokenSymbol,
+			RPC:   rpc,
+			// no faucets are run for subnets
+			Faucets: []string{},
+			NativeCurrency: NativeCurrency{
+				Name:     c.TokenSymbol,
+				Symbol:   c.TokenSymbol,
+				Decimals: nativeDecimals,
+			},
+			ShortName: strings.ToLower(fmt.Sprintf("%s-%s", c.Subnet, shortNetworkName(network))),
+			ChainID:   c.ChainID,
+			// subnet-evm uses the chain ID as network ID
+			NetworkID: c.ChainID,
+		})
+	}
+	return entries
+}
+
+func shortNetworkName(network models.Network) string {
+	if network == models.Local {
+		return "local"
+	}
+	return network.String()
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package registry
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	assert := assert.New(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+
+	localID, fujiID := ids.GenerateTestID(), ids.GenerateTestID()
+	sidecars := []*models.Sidecar{
+		{
+			Name:      "deployed",
+			VM:        models.SubnetEvm,
+			TokenName: "DPL",
+			ChainID:   "11111",
+			Networks: map[string]models.NetworkData{
+				app.GetLocalNetworkKey(): {BlockchainID: localID},
+				models.Fuji.String():     {BlockchainID: fujiID},
+			},
+		},
+		{
+			Name:    "created",
+			VM:      models.SubnetEvm,
+			ChainID: "22222",
+		},
+		{
+			Name: "custom",
+			VM:   models.CustomVM,
+		},
+	}
+	for _, sc := range sidecars {
+		assert.NoError(app.CreateSidecar(sc))
+	}
+
+	chains, err := Build(app, map[models.Network]string{
+		models.Local: "http://127.0.0.1:9650",
+		models.Fuji:  "https://api.avax-test.network",
+	})
+	assert.NoError(err)
+	// custom VMs have no EVM chain ID, and are left out
+	assert.Equal([]Chain{
+		{Subnet: "created", ChainID: big.NewInt(22222), TokenSymbol: "TEST"},
+		{
+			Subnet:       "deployed",
+			ChainID:      big.NewInt(11111),
+			TokenSymbol:  "DPL",
+			Network:      models.Local,
+			BlockchainID: localID,
+			RPCURL:       "http://127.0.0.1:9650/ext/bc/" + localID.String() + "/rpc",
+		},
+		{
+			Subnet:       "deployed",
+			ChainID:      big.NewInt(11111),
+			TokenSymbol:  "DPL",
+			Network:      models.Fuji,
+			BlockchainID: fujiID,
+			RPCURL:       "https://api.avax-test.network/ext/bc/" + fujiID.String() + "/rpc",
+		},
+	}, chains)
+}
+
+func TestChainList(t *testing.T) {
+	assert := assert.New(t)
+
+	chains := []Chain{
+		{Subnet: "created", ChainID: big.NewInt(22222), TokenSymbol: "TEST"},
+		{Subnet: "Deployed", ChainID: big.NewInt(11111), TokenSymbol: "DPL", Network: models.Local, RPCURL: "http://127.0.0.1:9650/ext/bc/x/rpc"},
+		{Subnet: "Deployed", ChainID: big.NewInt(11111), TokenSymbol: "DPL", Network: models.Fuji},
+	}
+
+	assert.Equal([]ChainListEntry{{
+		Name:           "Deployed (Local Network)",
+		Chain:          "DPL",
+		RPC:            []string{"http://127.0.0.1:9650/ext/bc/x/rpc"},
+		Faucets:        []string{},
+		NativeCurrency: NativeCurrency{Name: "DPL", Symbol: "DPL", Decimals: 18},
+		ShortName:      "deployed-local",
+		ChainID:        big.NewInt(11111),
+		NetworkID:      big.NewInt(11111),
+	}}, ChainList(chains, models.Local))
+
+	// without endpoint, the chain is exported without RPC URL
+	fuji := ChainList(chains, models.Fuji)
+	assert.Len(fuji, 1)
+	assert.Equal("deployed-fuji", fuji[0].ShortName)
+	assert.Empty(fuji[0].RPC)
+
+	assert.Empty(ChainList(chains, models.Mainnet))
+}
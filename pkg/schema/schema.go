@@ -0,0 +1,176 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/topology"
+)
+
+// draft is the JSON Schema dialect of the schemas
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Format is a file format of the CLI, described by the Go type the CLI
+// reads and writes it with
+type Format struct {
+	Name        string
+	Description string
+	value       interface{}
+	// tag is the struct tag naming the fields in the file
+	tag string
+}
+
+// Formats are the file formats schemas are exported for
+var Formats = []Format{
+	{
+		Name:        "sidecar",
+		Description: "The sidecar of a subnet, <subnetName>_sidecar.json in ~/.avalanche-cli, recording how it was created and where it is deployed",
+		value:       models.Sidecar{},
+		tag:         "json",
+	},
+	{
+		Name:        "subnet-spec",
+		Description: "A subnet of a topology file, its desired state on the local network",
+		value:       topology.SubnetSpec{},
+		tag:         "mapstructure",
+	},
+	{
+		Name:        "topology",
+		Description: "A topology file, the subnets deployed together to the local network by up",
+		value:       topology.Topology{},
+		tag:         "mapstructure",
+	},
+	{
+		Name:        "answers",
+		Description: "An answers file, the answers to the wizard of subnet create saved with --record and replayed with --replay",
+		value:       prompts.Answers{},
+		tag:         "json",
+	},
+	{
+		Name:        "deploy-report",
+		Description: "The report of a local deploy written with subnet deploy --report",
+		value:       subnet.DeployReport{},
+		tag:         "json",
+	},
+}
+
+// enums are the values of the types with a closed set of values
+var enums = map[reflect.Type][]interface{}{
+	reflect.TypeOf(models.VMType("")): {
+		models.SubnetEvm, models.SpacesVM, models.BlobVM, models.TimestampVM, models.CustomVM,
+	},
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// FormatNames returns the names of the formats
+func FormatNames() []string {
+	names := make([]string, 0, len(Formats))
+	for _, f := range Formats {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// GetFormat returns the format name
+func GetFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return Format{}, fmt.Errorf("unknown format %q, expected one of %s", name, strings.Join(FormatNames(), ", "))
+}
+
+// Schema returns the JSON schema of f
+func (f Format) Schema() map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(f.value), f.tag)
+	schema["$schema"] = draft
+	schema["title"] = f.Name
+	schema["description"] = f.Description
+	return schema
+}
+
+// JSON returns the JSON schema of f, indented
+func (f Format) JSON() ([]byte, error) {
+	return json.MarshalIndent(f.Schema(), "", "  ")
+}
+
+// typeSchema returns the schema of the values of t, with the fields of its
+// structs named by their tag
+func typeSchema(t reflect.Type, tag string) map[string]interface{} {
+	if values, ok := enums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]interface{}{"type": "integer", "description": "duration in nanoseconds"}
+	case t.Kind() != reflect.Ptr && (t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)):
+		// IDs and the like marshal themselves as strings
+		return map[string]interface{}{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), tag)
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// bytes are encoded in base64
+			return map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), tag)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), tag)}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		addFields(properties, t, tag)
+		return map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+	// interfaces hold any value
+	return map[string]interface{}{}
+}
+
+// addFields adds the schemas of the fields of the struct t to properties,
+// the ones of its embedded structs included
+func addFields(properties map[string]interface{}, t reflect.Type, tag string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFields(properties, field.Type, tag)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type, tag)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func properties(schema map[string]interface{}) map[string]interface{} {
+	return schema["properties"].(map[string]interface{})
+}
+
+func TestSidecarSchema(t *testing.T) {
+	assert := assert.New(t)
+	format, err := GetFormat("sidecar")
+	assert.NoError(err)
+	schema := format.Schema()
+	assert.Equal(draft, schema["$schema"])
+	assert.Equal("sidecar", schema["title"])
+
+	props := properties(schema)
+	networks := props["Networks"].(map[string]interface{})
+	assert.Equal("object", networks["type"])
+	assert.Contains(networks, "additionalProperties")
+	vm := props["VM"].(map[string]interface{})
+	assert.Contains(vm["enum"], models.SubnetEvm)
+	assert.Equal("array", props["Upgrades"].(map[string]interface{})["type"])
+
+	// every field the CLI writes is described
+	sidecarBytes, err := json.Marshal(models.Sidecar{Name: "test", VM: models.SubnetEvm})
+	assert.NoError(err)
+	var sidecar map[string]interface{}
+	assert.NoError(json.Unmarshal(sidecarBytes, &sidecar))
+	for key := range sidecar {
+		assert.Contains(props, key)
+	}
+
+	schemaBytes, err := format.JSON()
+	assert.NoError(err)
+	assert.True(json.Valid(schemaBytes))
+}
+
+func TestTopologySchema(t *testing.T) {
+	assert := assert.New(t)
+	format, err := GetFormat("topology")
+	assert.NoError(err)
+	props := properties(format.Schema())
+	assert.Contains(props, "api-nodes")
+	assert.NotContains(props, "Path")
+	subnets := props["subnets"].(map[string]interface{})
+	spec := subnets["items"].(map[string]interface{})
+	assert.Contains(properties(spec), "depends-on")
+	assert.Equal(false, spec["additionalProperties"])
+}
+
+func TestDeployReportSchema(t *testing.T) {
+	assert := assert.New(t)
+	format, err := GetFormat("deploy-report")
+	assert.NoError(err)
+	assert.Contains(properties(format.Schema()), "vmBinarySHA256")
+}
+
+func TestGetFormat(t *testing.T) {
+	assert := assert.New(t)
+	_, err := GetFormat("nope")
+	assert.ErrorContains(err, `unknown format "nope"`)
+	assert.Equal([]string{"sidecar", "subnet-spec", "topology", "answers", "deploy-report"}, FormatNames())
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package server exposes the lifecycle of the subnets on the local network
+// over a REST API, for IDE extensions and portals to drive the CLI
+// programmatically. Every request must carry the bearer token of the server.
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// APIPrefix is the path all the routes of the API are under
+	APIPrefix = "/v1/subnets"
+
+	tokenBytes = 32
+	// maxBodySize bounds the request bodies, which carry at most a genesis
+	maxBodySize = 10 << 20
+)
+
+// ErrNotFound is returned by a Backend asked about a subnet that doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// SubnetStatus is the state of a subnet and of its deployment to the local
+// network
+type SubnetStatus struct {
+	Name         string `json:"name"`
+	VM           string `json:"vm"`
+	SubnetID     string `json:"subnetID,omitempty"`
+	BlockchainID string `json:"blockchainID,omitempty"`
+	// Running is true if the blockchain is running on the local network
+	Running bool `json:"running"`
+	// Endpoints are the RPC endpoints of the blockchain on the local nodes
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// CreateRequest is the spec of a subnet to create
+type CreateRequest struct {
+	Name string `json:"name"`
+	// VM is subnet-evm or custom, defaults to subnet-evm
+	VM      string          `json:"vm"`
+	Genesis json.RawMessage `json:"genesis"`
+}
+
+// Backend is the deployer functionality the server exposes
+type Backend interface {
+	ListSubnets() ([]SubnetStatus, error)
+	CreateSubnet(req CreateRequest) (SubnetStatus, error)
+	DeployLocal(name string) (SubnetStatus, error)
+	Status(name string) (SubnetStatus, error)
+}
+
+// Server routes the API requests to its backend:
+//
+//	GET  /v1/subnets                      list the subnets
+//	POST /v1/subnets                      create a subnet from a CreateRequest
+//	GET  /v1/subnets/<name>               status of a subnet
+//	GET  /v1/subnets/<name>/endpoints     RPC endpoints of a subnet
+//	POST /v1/subnets/<name>/deploy        deploy a subnet to the local network
+type Server struct {
+	backend Backend
+	token   string
+	log     logging.Logger
+}
+
+// New returns a server of backend, authenticating the requests with token
+func New(backend Backend, token string, log logging.Logger) *Server {
+	return &Server{backend: backend, token: token, log: log}
+}
+
+// NewToken returns a random token to authenticate the requests with
+func NewToken() (string, error) {
+	token := make([]byte, tokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path != APIPrefix && !strings.HasPrefix(path, APIPrefix+"/") {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route %s", r.URL.Path))
+		return
+	}
+	parts := []string{}
+	if rest := strings.TrimPrefix(path, APIPrefix+"/"); rest != path {
+		parts = strings.Split(rest, "/")
+	}
+	s.log.Info("%s %s", r.Method, r.URL.Path)
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		subnets, err := s.backend.ListSubnets()
+		s.respond(w, subnets, err)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var req CreateRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid subnet spec: %w", err))
+			return
+		}
+		status, err := s.backend.CreateSubnet(req)
+		s.respond(w, status, err)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		status, err := s.backend.Status(parts[0])
+		s.respond(w, status, err)
+	case len(parts) == 2 && parts[1] == "endpoints" && r.Method == http.MethodGet:
+		status, err := s.backend.Status(parts[0])
+		if status.Endpoints == nil {
+			status.Endpoints = []string{}
+		}
+		s.respond(w, status.Endpoints, err)
+	case len(parts) == 2 && parts[1] == "deploy" && r.Method == http.MethodPost:
+		status, err := s.backend.DeployLocal(parts[0])
+		s.respond(w, status, err)
+	case len(parts) <= 2:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed on %s", r.Method, r.URL.Path))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route %s", r.URL.Path))
+	}
+}
+
+// respond writes result as JSON, or the error of the backend with the HTTP
+// status matching its class
+func (s *Server) respond(w http.ResponseWriter, result interface{}, err error) {
+	if err != nil {
+		s.log.Info("request failed: %s", err)
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.log.Warn("failed writing response: %s", err)
+	}
+}
+
+func errorStatus(err error) int {
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound
+	}
+	switch exitcodes.FromError(err) {
+	case exitcodes.UserInputError:
+		return http.StatusBadRequest
+	case exitcodes.StateLocked:
+		return http.StatusConflict
+	case exitcodes.BackendFailure, exitcodes.NetworkUnhealthy:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "secret"
+
+// fakeBackend keeps the subnets in memory, deployed when asked to
+type fakeBackend struct {
+	subnets map[string]SubnetStatus
+	order   []string
+	locked  bool
+}
+
+func (b *fakeBackend) ListSubnets() ([]SubnetStatus, error) {
+	statuses := []SubnetStatus{}
+	for _, name := range b.order {
+		statuses = append(statuses, b.subnets[name])
+	}
+	return statuses, nil
+}
+
+func (b *fakeBackend) CreateSubnet(req CreateRequest) (SubnetStatus, error) {
+	if _, ok := b.subnets[req.Name]; ok {
+		return SubnetStatus{}, exitcodes.UserInput(fmt.Errorf("subnet %s already exists", req.Name))
+	}
+	b.subnets[req.Name] = SubnetStatus{Name: req.Name, VM: req.VM}
+	b.order = append(b.order, req.Name)
+	return b.subnets[req.Name], nil
+}
+
+func (b *fakeBackend) DeployLocal(name string) (SubnetStatus, error) {
+	if b.locked {
+		return SubnetStatus{}, exitcodes.Locked(errors.New("another avalanche command is running"))
+	}
+	status, err := b.Status(name)
+	if err != nil {
+		return status, err
+	}
+	status.Running = true
+	status.Endpoints = []string{"http://127.0.0.1:9650/ext/bc/" + name + "/rpc"}
+	b.subnets[name] = status
+	return status, nil
+}
+
+func (b *fakeBackend) Status(name string) (SubnetStatus, error) {
+	status, ok := b.subnets[name]
+	if !ok {
+		return SubnetStatus{}, fmt.Errorf("subnet %s %w", name, ErrNotFound)
+	}
+	return status, nil
+}
+
+func request(t *testing.T, s *httptest.Server, method, path, token, body string) (int, string) {
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestServer(t *testing.T) {
+	assert := assert.New(t)
+
+	backend := &fakeBackend{subnets: map[string]SubnetStatus{}}
+	s := httptest.NewServer(New(backend, testToken, logging.NoLog{}))
+	defer s.Close()
+
+	// every request is authenticated
+	code, _ := request(t, s, http.MethodGet, APIPrefix, "", "")
+	assert.Equal(http.StatusUnauthorized, code)
+	code, _ = request(t, s, http.MethodGet, APIPrefix, "wrong", "")
+	assert.Equal(http.StatusUnauthorized, code)
+
+	code, body := request(t, s, http.MethodGet, APIPrefix, testToken, "")
+	assert.Equal(http.StatusOK, code)
+	assert.JSONEq(`[]`, body)
+
+	spec := `{"name": "tokens", "vm": "subnet-evm", "genesis": {"config": {}}}`
+	code, body = request(t, s, http.MethodPost, APIPrefix, testToken, spec)
+	assert.Equal(http.StatusOK, code)
+	assert.JSONEq(`{"name": "tokens", "vm": "subnet-evm", "running": false}`, body)
+	code, _ = request(t, s, http.MethodPost, APIPrefix, testToken, spec)
+	assert.Equal(http.StatusBadRequest, code)
+	code, _ = request(t, s, http.MethodPost, APIPrefix, testToken, `{"name": "tokens", "unknown": true}`)
+	assert.Equal(http.StatusBadRequest, code)
+
+	code, body = request(t, s, http.MethodGet, APIPrefix+"/tokens/endpoints", testToken, "")
+	assert.Equal(http.StatusOK, code)
+	assert.JSONEq(`[]`, body)
+
+	code, body = request(t, s, http.MethodPost, APIPrefix+"/tokens/deploy", testToken, "")
+	assert.Equal(http.StatusOK, code)
+	var status SubnetStatus
+	assert.NoError(json.Unmarshal([]byte(body), &status))
+	assert.True(status.Running)
+
+	code, body = request(t, s, http.MethodGet, APIPrefix+"/tokens/endpoints", testToken, "")
+	assert.Equal(http.StatusOK, code)
+	assert.JSONEq(`["http://127.0.0.1:9650/ext/bc/tokens/rpc"]`, body)
+
+	code, body = request(t, s, http.MethodGet, APIPrefix+"/", testToken, "")
+	assert.Equal(http.StatusOK, code)
+	assert.Contains(body, `"running":true`)
+
+	// the errors of the backend map to HTTP statuses
+	code, body = request(t, s, http.MethodGet, APIPrefix+"/missing", testToken, "")
+	assert.Equal(http.StatusNotFound, code)
+	assert.JSONEq(`{"error": "subnet missing not found"}`, body)
+	backend.locked = true
+	code, _ = request(t, s, http.MethodPost, APIPrefix+"/tokens/deploy", testToken, "")
+	assert.Equal(http.StatusConflict, code)
+
+	code, _ = request(t, s, http.MethodDelete, APIPrefix+"/tokens", testToken, "")
+	assert.Equal(http.StatusMethodNotAllowed, code)
+	code, _ = request(t, s, http.MethodGet, "/v2/subnets", testToken, "")
+	assert.Equal(http.StatusNotFound, code)
+	code, _ = request(t, s, http.MethodGet, APIPrefix+"/tokens/deploy/now", testToken, "")
+	assert.Equal(http.StatusNotFound, code)
+}
+
+func TestNewToken(t *testing.T) {
+	assert := assert.New(t)
+	a, err := NewToken()
+	assert.NoError(err)
+	b, err := NewToken()
+	assert.NoError(err)
+	assert.Len(a, 2*tokenBytes)
+	assert.NotEqual(a, b)
+}
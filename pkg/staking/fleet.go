@@ -0,0 +1,86 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package staking
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// FleetNode is a node of a planned validator fleet, with its key pair
+// generated ahead of the provisioning of its machine
+type FleetNode struct {
+	// Dir holds the staker.crt and staker.key of the node
+	Dir    string
+	NodeID ids.NodeID
+}
+
+// FleetNodeDir returns the directory of the i-th node, from 1, of a fleet
+// of count nodes generated in dir. The names are padded to sort in order.
+func FleetNodeDir(dir string, i int, count int) string {
+	width := len(strconv.Itoa(count))
+	if width < 2 {
+		width = 2
+	}
+	return filepath.Join(dir, fmt.Sprintf("node-%0*d", width, i))
+}
+
+// GenerateFleet generates the key pairs of count nodes, each in its own
+// directory of dir, and writes the manifest of the fleet, listing their
+// NodeIDs with weight in the order of the directories, in the format of
+// the validators files of subnet addValidator. Nothing existing is
+// overwritten.
+func GenerateFleet(dir string, count int, weight uint64) ([]FleetNode, error) {
+	if count < 1 {
+		return nil, errors.New("the count of nodes must be at least 1")
+	}
+	if weight == 0 {
+		return nil, errors.New("the weight must be a positive integer")
+	}
+	manifestPath := filepath.Join(dir, constants.FleetManifestFile)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil, fmt.Errorf("%s already exists, refusing to overwrite it", manifestPath)
+	}
+	nodes := make([]FleetNode, count)
+	for i := range nodes {
+		nodeDir := FleetNodeDir(dir, i+1, count)
+		nodeID, err := GenerateKeyPair(filepath.Join(nodeDir, constants.StakerCertFile), filepath.Join(nodeDir, constants.StakerKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = FleetNode{Dir: nodeDir, NodeID: nodeID}
+	}
+	return nodes, writeFleetManifest(manifestPath, nodes, weight)
+}
+
+func writeFleetManifest(path string, nodes []FleetNode, weight uint64) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, certPerms)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// the directories aren't a column, the validators file having none for
+	// them, so that the manifest is given to subnet addValidator as is
+	if _, err := fmt.Fprintf(f, "# generated by node keygen, the key pairs of the rows are in the directories %s to %s, in order\n",
+		filepath.Base(nodes[0].Dir), filepath.Base(nodes[len(nodes)-1].Dir)); err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"nodeID", "weight", "start", "duration"}); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := w.Write([]string{node.NodeID.String(), strconv.FormatUint(weight, 10), "", ""}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
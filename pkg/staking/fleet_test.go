@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package staking
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateFleet(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	nodes, err := GenerateFleet(dir, 3, 30)
+	assert.NoError(err)
+	assert.Len(nodes, 3)
+	assert.Equal(filepath.Join(dir, "node-03"), nodes[2].Dir)
+	assert.Equal(filepath.Join(dir, "node-100"), FleetNodeDir(dir, 100, 100))
+	assert.Equal(filepath.Join(dir, "node-007"), FleetNodeDir(dir, 7, 100))
+
+	// the manifest is a validators file, in the order of the directories
+	validators, err := subnet.LoadValidatorsFile(filepath.Join(dir, constants.FleetManifestFile), time.Now())
+	assert.NoError(err)
+	assert.Len(validators, 3)
+	for i, node := range nodes {
+		nodeID, err := NodeIDFromCertFile(filepath.Join(node.Dir, constants.StakerCertFile))
+		assert.NoError(err)
+		assert.Equal(nodeID, node.NodeID)
+		assert.Equal(nodeID, validators[i].NodeID)
+		assert.Equal(uint64(30), validators[i].Weight)
+	}
+
+	// nothing is overwritten
+	_, err = GenerateFleet(dir, 3, 30)
+	assert.ErrorContains(err, "already exists")
+	_, err = GenerateFleet(t.TempDir(), 0, 30)
+	assert.Error(err)
+}
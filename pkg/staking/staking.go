@@ -0,0 +1,116 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package staking
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_staking "github.com/ava-labs/avalanchego/staking"
+)
+
+const (
+	certPerms = 0o644
+	keyPerms  = 0o600
+)
+
+var ErrNoStakingCert = errors.New("no staking certificate found")
+
+// KeyPairPaths returns the paths avalanchego reads the staking certificate and
+// key of the node with data directory nodeDir from
+func KeyPairPaths(nodeDir string) (string, string) {
+	stakingDir := filepath.Join(nodeDir, constants.StakingDir)
+	return filepath.Join(stakingDir, constants.StakerCertFile), filepath.Join(stakingDir, constants.StakerKeyFile)
+}
+
+// FindCert returns the path of the staking certificate in dir, which can be
+// either a node data directory or directly the directory holding the certificate
+func FindCert(dir string) (string, error) {
+	certPath, _ := KeyPairPaths(dir)
+	candidates := []string{
+		certPath,
+		filepath.Join(dir, constants.StakerCertFile),
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w in %s", ErrNoStakingCert, dir)
+}
+
+// NodeIDFromCert returns the NodeID derived from the PEM encoded staking certificate
+func NodeIDFromCert(certBytes []byte) (ids.NodeID, error) {
+	block, _ := pem.Decode(certBytes)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return ids.EmptyNodeID, errors.New("staking certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ids.EmptyNodeID, fmt.Errorf("failed parsing staking certificate: %w", err)
+	}
+	return ids.NodeIDFromCert(cert), nil
+}
+
+// NodeIDFromCertFile returns the NodeID derived from the staking certificate at certPath
+func NodeIDFromCertFile(certPath string) (ids.NodeID, error) {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	return NodeIDFromCert(certBytes)
+}
+
+// GenerateKeyPair writes a new staking certificate and key to certPath and
+// keyPath, and returns the NodeID they correspond to
+func GenerateKeyPair(certPath, keyPath string) (ids.NodeID, error) {
+	certBytes, keyBytes, err := avago_staking.NewCertAndKeyBytes()
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	if err := writeKeyPair(certPath, keyPath, certBytes, keyBytes); err != nil {
+		return ids.EmptyNodeID, err
+	}
+	return NodeIDFromCert(certBytes)
+}
+
+// CopyKeyPair copies the staking certificate and key from the src paths to the
+// dst paths, after checking they form a valid pair. Returns the NodeID of the pair.
+func CopyKeyPair(srcCertPath, srcKeyPath, dstCertPath, dstKeyPath string) (ids.NodeID, error) {
+	certBytes, err := os.ReadFile(srcCertPath)
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	keyBytes, err := os.ReadFile(srcKeyPath)
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	if _, err := avago_staking.LoadTLSCertFromBytes(keyBytes, certBytes); err != nil {
+		return ids.EmptyNodeID, fmt.Errorf("invalid staking key pair: %w", err)
+	}
+	if err := writeKeyPair(dstCertPath, dstKeyPath, certBytes, keyBytes); err != nil {
+		return ids.EmptyNodeID, err
+	}
+	return NodeIDFromCert(certBytes)
+}
+
+func writeKeyPair(certPath, keyPath string, certBytes, keyBytes []byte) error {
+	for _, path := range []string{certPath, keyPath} {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists, refusing to overwrite it", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(certPath, certBytes, certPerms); err != nil {
+		return err
+	}
+	return os.WriteFile(keyPath, keyBytes, keyPerms)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package staking
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_staking "github.com/ava-labs/avalanchego/staking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPairLifecycle(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeDir := t.TempDir()
+	certPath, keyPath := KeyPairPaths(nodeDir)
+	assert.Equal(filepath.Join(nodeDir, "staking", "staker.crt"), certPath)
+	assert.Equal(filepath.Join(nodeDir, "staking", "staker.key"), keyPath)
+
+	_, err := FindCert(nodeDir)
+	assert.True(errors.Is(err, ErrNoStakingCert))
+
+	nodeID, err := GenerateKeyPair(certPath, keyPath)
+	assert.NoError(err)
+	assert.NotEqual(ids.EmptyNodeID, nodeID)
+
+	// the NodeID must be the one avalanchego derives from the pair
+	tlsCert, err := avago_staking.LoadTLSCertFromFiles(keyPath, certPath)
+	assert.NoError(err)
+	assert.Equal(ids.NodeIDFromCert(tlsCert.Leaf), nodeID)
+
+	found, err := FindCert(nodeDir)
+	assert.NoError(err)
+	assert.Equal(certPath, found)
+	readID, err := NodeIDFromCertFile(found)
+	assert.NoError(err)
+	assert.Equal(nodeID, readID)
+
+	// existing pairs are never overwritten
+	_, err = GenerateKeyPair(certPath, keyPath)
+	assert.Error(err)
+
+	// export into a flat directory
+	exportDir := t.TempDir()
+	exportCert := filepath.Join(exportDir, constants.StakerCertFile)
+	exportKey := filepath.Join(exportDir, constants.StakerKeyFile)
+	copiedID, err := CopyKeyPair(certPath, keyPath, exportCert, exportKey)
+	assert.NoError(err)
+	assert.Equal(nodeID, copiedID)
+	found, err = FindCert(exportDir)
+	assert.NoError(err)
+	assert.Equal(exportCert, found)
+	info, err := os.Stat(exportKey)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(keyPerms), info.Mode().Perm())
+}
+
+func TestCopyKeyPairMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	certA, keyA := filepath.Join(dir, "a.crt"), filepath.Join(dir, "a.key")
+	certB, keyB := filepath.Join(dir, "b.crt"), filepath.Join(dir, "b.key")
+	_, err := GenerateKeyPair(certA, keyA)
+	assert.NoError(err)
+	_, err = GenerateKeyPair(certB, keyB)
+	assert.NoError(err)
+
+	_, err = CopyKeyPair(certA, keyB, filepath.Join(dir, "c.crt"), filepath.Join(dir, "c.key"))
+	assert.Error(err)
+	_, err = os.Stat(filepath.Join(dir, "c.crt"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestNodeIDFromCertInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NodeIDFromCert([]byte("not a certificate"))
+	assert.Error(err)
+	_, err = NodeIDFromCert([]byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"))
+	assert.Error(err)
+}
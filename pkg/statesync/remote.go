@@ -0,0 +1,192 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statesync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	gitPrefix  = "git+"
+	s3Prefix   = "s3://"
+	gcsPrefix  = "gs://"
+	filePrefix = "file://"
+)
+
+var (
+	// scpLikeURLRegex matches the git URLs like git@github.com:org/repo.git
+	scpLikeURLRegex = regexp.MustCompile(`^[^/]+@[^/]+:`)
+
+	// runCommand runs name with args in dir, and returns its combined output
+	runCommand = func(dir string, name string, args ...string) ([]byte, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		return cmd.CombinedOutput()
+	}
+)
+
+// Remote is where the state of a team is shared. Its files are checked out
+// to a local directory, updated there, and published back.
+type Remote interface {
+	// Checkout returns a directory holding the files of the remote
+	Checkout() (string, error)
+	// Publish makes the remote hold the files of the checkout, with message
+	// describing the change, where the remote keeps a history
+	Publish(message string) error
+	// Close removes the checkout
+	Close() error
+	String() string
+}
+
+// ParseRemote returns the remote of url: a git repository as
+// git+<url>[#branch], or any URL ending in .git, an S3 bucket as
+// s3://bucket/prefix, a GCS bucket as gs://bucket/prefix, or else a
+// directory, e.g. on a shared drive, as a path or a file:// URL
+func ParseRemote(url string) (Remote, error) {
+	switch {
+	case url == "":
+		return nil, errors.New("no remote given")
+	case strings.HasPrefix(url, s3Prefix):
+		return &bucketRemote{url: url, tool: "aws",
+			pullArgs: []string{"s3", "sync", "--delete", "--only-show-errors", url},
+			pushArgs: []string{"s3", "sync", "--delete", "--only-show-errors"},
+		}, nil
+	case strings.HasPrefix(url, gcsPrefix):
+		return &bucketRemote{url: url, tool: "gsutil",
+			pullArgs: []string{"-q", "-m", "rsync", "-r", "-d", url},
+			pushArgs: []string{"-q", "-m", "rsync", "-r", "-d"},
+		}, nil
+	case strings.HasPrefix(url, gitPrefix), strings.HasSuffix(strings.SplitN(url, "#", 2)[0], ".git"), scpLikeURLRegex.MatchString(url):
+		repository, branch := strings.TrimPrefix(url, gitPrefix), ""
+		if i := strings.LastIndex(repository, "#"); i >= 0 {
+			repository, branch = repository[:i], repository[i+1:]
+		}
+		return &gitRemote{url: url, repository: repository, branch: branch}, nil
+	}
+	return &dirRemote{path: strings.TrimPrefix(url, filePrefix)}, nil
+}
+
+// dirRemote is a directory, written in place
+type dirRemote struct {
+	path string
+}
+
+func (r *dirRemote) Checkout() (string, error) {
+	if err := os.MkdirAll(r.path, 0o755); err != nil {
+		return "", fmt.Errorf("failed creating the remote directory %s: %w", r.path, err)
+	}
+	return r.path, nil
+}
+
+func (r *dirRemote) Publish(string) error { return nil }
+
+func (r *dirRemote) Close() error { return nil }
+
+func (r *dirRemote) String() string { return r.path }
+
+// gitRemote is a git repository, cloned to a temporary directory and pushed
+// with a commit
+type gitRemote struct {
+	url        string
+	repository string
+	branch     string
+	dir        string
+}
+
+func (r *gitRemote) Checkout() (string, error) {
+	dir, err := os.MkdirTemp("", "state-git-*")
+	if err != nil {
+		return "", err
+	}
+	r.dir = dir
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if r.branch != "" {
+		args = append(args, "--branch", r.branch)
+	}
+	if out, err := runCommand(dir, "git", append(args, r.repository, ".")...); err != nil {
+		return "", fmt.Errorf("failed cloning %s: %w: %s", r.repository, err, strings.TrimSpace(string(out)))
+	}
+	return dir, nil
+}
+
+func (r *gitRemote) Publish(message string) error {
+	if out, err := runCommand(r.dir, "git", "add", "-A"); err != nil {
+		return fmt.Errorf("failed staging the changes: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	out, err := runCommand(r.dir, "git", "status", "--porcelain")
+	if err != nil {
+		return fmt.Errorf("failed listing the changes: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if len(strings.TrimSpace(string(out))) == 0 {
+		return nil
+	}
+	if out, err := runCommand(r.dir, "git", "commit", "--quiet", "-m", message); err != nil {
+		return fmt.Errorf("failed committing the changes: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	ref := "HEAD"
+	if r.branch != "" {
+		ref = "HEAD:" + r.branch
+	}
+	if out, err := runCommand(r.dir, "git", "push", "--quiet", "origin", ref); err != nil {
+		return fmt.Errorf("failed pushing to %s, pull the changes pushed meanwhile and try again: %w: %s", r.repository, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (r *gitRemote) Close() error {
+	if r.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(r.dir)
+}
+
+func (r *gitRemote) String() string { return r.url }
+
+// bucketRemote is a prefix of a cloud storage bucket, synced to a temporary
+// directory with the CLI of the cloud, which holds the credentials
+type bucketRemote struct {
+	url  string
+	tool string
+	// pullArgs sync the bucket to the directory given after them, pushArgs
+	// the directory given after them to the bucket
+	pullArgs []string
+	pushArgs []string
+	dir      string
+}
+
+func (r *bucketRemote) Checkout() (string, error) {
+	if _, err := exec.LookPath(r.tool); err != nil {
+		return "", fmt.Errorf("%s is required to sync the state with %s, install it and set up its credentials: %w", r.tool, r.url, err)
+	}
+	dir, err := os.MkdirTemp("", "state-bucket-*")
+	if err != nil {
+		return "", err
+	}
+	r.dir = dir
+	if out, err := runCommand(dir, r.tool, append(r.pullArgs, dir)...); err != nil {
+		return "", fmt.Errorf("failed downloading %s: %w: %s", r.url, err, strings.TrimSpace(string(out)))
+	}
+	return dir, nil
+}
+
+func (r *bucketRemote) Publish(string) error {
+	if out, err := runCommand(r.dir, r.tool, append(r.pushArgs, r.dir+string(filepath.Separator), r.url)...); err != nil {
+		return fmt.Errorf("failed uploading to %s: %w: %s", r.url, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (r *bucketRemote) Close() error {
+	if r.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(r.dir)
+}
+
+func (r *bucketRemote) String() string { return r.url }
@@ -0,0 +1,304 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statesync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+const WriteReadReadPerms = 0o644
+
+// Action is what a sync does to a file
+type Action string
+
+const (
+	ActionAdd      Action = "add"
+	ActionUpdate   Action = "update"
+	ActionDelete   Action = "delete"
+	ActionConflict Action = "conflict"
+)
+
+// Change is a file a sync changes
+type Change struct {
+	File   string
+	Action Action
+}
+
+// ConflictError tells the files changed on both sides since the last sync
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s changed both locally and on the remote since the last sync", strings.Join(e.Files, ", "))
+}
+
+// Options are the settings of a push or a pull
+type Options struct {
+	// Force overwrites the files changed on both sides, instead of failing
+	Force bool
+	// DryRun only returns the changes
+	DryRun bool
+	// Message describes a push, on the remotes which keep a history
+	Message string
+}
+
+// syncedFile returns true if the file of the base dir name is shared: the
+// genesis, the sidecar and the chain config of the subnets. Keys, snapshots
+// and the binaries are never shared.
+func syncedFile(name string) bool {
+	return strings.HasSuffix(name, constants.GenesisSuffix) ||
+		strings.HasSuffix(name, constants.SidecarSuffix) ||
+		strings.HasSuffix(name, constants.ChainConfigSuffix)
+}
+
+// readFiles returns the shared files of dir, by name, the sidecars without
+// their local network deployments, which only exist on the machine which
+// made them
+func readFiles(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || !syncedFile(entry.Name()) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(entry.Name(), constants.SidecarSuffix) {
+			if content, err = mergeLocalNetworks(content, nil); err != nil {
+				return nil, fmt.Errorf("failed reading %s: %w", entry.Name(), err)
+			}
+		}
+		files[entry.Name()] = content
+	}
+	return files, nil
+}
+
+// isLocalNetwork returns true for the keys of the deployments to the local
+// networks, of any profile
+func isLocalNetwork(network string) bool {
+	return strings.HasPrefix(network, models.Local.String())
+}
+
+// mergeLocalNetworks returns the sidecar content, with its local network
+// deployments replaced by the ones of the sidecar local, if any
+func mergeLocalNetworks(content []byte, local []byte) ([]byte, error) {
+	var sc models.Sidecar
+	if err := json.Unmarshal(content, &sc); err != nil {
+		return nil, err
+	}
+	for network := range sc.Networks {
+		if isLocalNetwork(network) {
+			delete(sc.Networks, network)
+		}
+	}
+	if local != nil {
+		var localSc models.Sidecar
+		if err := json.Unmarshal(local, &localSc); err != nil {
+			return nil, err
+		}
+		for network, data := range localSc.Networks {
+			if !isLocalNetwork(network) {
+				continue
+			}
+			if sc.Networks == nil {
+				sc.Networks = map[string]models.NetworkData{}
+			}
+			sc.Networks[network] = data
+		}
+	}
+	return json.MarshalIndent(sc, "", "    ")
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// record is the record of the synced files, by remote, then by file name,
+// as the hash of their content as of the last sync
+type record map[string]map[string]string
+
+func loadRecord(baseDir string) (record, error) {
+	content, err := os.ReadFile(filepath.Join(baseDir, constants.StateSyncFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := record{}
+	if err := json.Unmarshal(content, &r); err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", constants.StateSyncFileName, err)
+	}
+	return r, nil
+}
+
+func (r record) save(baseDir string) error {
+	content, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(baseDir, constants.StateSyncFileName), content, WriteReadReadPerms)
+}
+
+// plan returns the changes making to hold the files of from: the files
+// changed on from since the last sync, base, are taken, the ones changed on
+// to only are kept, and the ones changed on both are conflicts, unless force
+func plan(from map[string][]byte, to map[string][]byte, base map[string]string, force bool) []Change {
+	names := map[string]struct{}{}
+	for name := range from {
+		names[name] = struct{}{}
+	}
+	for name := range to {
+		names[name] = struct{}{}
+	}
+	changes := []Change{}
+	for name := range names {
+		fromContent, inFrom := from[name]
+		toContent, inTo := to[name]
+		fromHash, toHash := "", ""
+		if inFrom {
+			fromHash = hash(fromContent)
+		}
+		if inTo {
+			toHash = hash(toContent)
+		}
+		if fromHash == toHash || fromHash == base[name] {
+			continue
+		}
+		action := ActionUpdate
+		switch {
+		case toHash != base[name] && !force:
+			action = ActionConflict
+		case !inFrom:
+			action = ActionDelete
+		case !inTo:
+			action = ActionAdd
+		}
+		changes = append(changes, Change{File: name, Action: action})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+	return changes
+}
+
+// Push updates the remote with the subnets of baseDir changed since the
+// last sync. The subnets changed on the remote meanwhile are left as they
+// are there, unless changed locally too, which is a conflict.
+func Push(baseDir string, remote Remote, opts Options) ([]Change, error) {
+	return syncFiles(baseDir, remote, opts, true)
+}
+
+// Pull updates baseDir with the subnets of the remote changed since the
+// last sync, keeping the local network deployments of their sidecars. The
+// subnets changed locally meanwhile are left as they are, unless changed on
+// the remote too, which is a conflict.
+func Pull(baseDir string, remote Remote, opts Options) ([]Change, error) {
+	return syncFiles(baseDir, remote, opts, false)
+}
+
+func syncFiles(baseDir string, remote Remote, opts Options, push bool) ([]Change, error) {
+	rec, err := loadRecord(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := remote.Checkout()
+	if err != nil {
+		return nil, err
+	}
+	defer remote.Close()
+	local, err := readFiles(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	remoteFiles, err := readFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	base := rec[remote.String()]
+
+	from, to, toDir := remoteFiles, local, baseDir
+	if push {
+		from, to, toDir = local, remoteFiles, dir
+	}
+	changes := plan(from, to, base, opts.Force)
+	conflicts := []string{}
+	for _, change := range changes {
+		if change.Action == ActionConflict {
+			conflicts = append(conflicts, change.File)
+		}
+	}
+	if len(conflicts) > 0 {
+		return changes, &ConflictError{Files: conflicts}
+	}
+	if opts.DryRun {
+		return changes, nil
+	}
+
+	for _, change := range changes {
+		path := filepath.Join(toDir, change.File)
+		if change.Action == ActionDelete {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			delete(to, change.File)
+			continue
+		}
+		content := from[change.File]
+		if !push && strings.HasSuffix(change.File, constants.SidecarSuffix) {
+			// the local network deployments are kept, without being shared
+			current, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			if content, err = mergeLocalNetworks(content, current); err != nil {
+				return nil, err
+			}
+		}
+		if err := os.WriteFile(path, content, WriteReadReadPerms); err != nil {
+			return nil, err
+		}
+		to[change.File] = from[change.File]
+	}
+	if push && len(changes) > 0 {
+		if err := remote.Publish(opts.Message); err != nil {
+			return nil, err
+		}
+	}
+
+	// the base of the next sync is what both sides hold now, the files
+	// still differing keeping the base they were compared with
+	newBase := map[string]string{}
+	for name, content := range from {
+		if toContent, ok := to[name]; ok && hash(toContent) == hash(content) {
+			newBase[name] = hash(content)
+		} else if h, ok := base[name]; ok {
+			newBase[name] = h
+		}
+	}
+	for name, h := range base {
+		if _, ok := from[name]; !ok {
+			if _, ok := to[name]; ok {
+				newBase[name] = h
+			}
+		}
+	}
+	rec[remote.String()] = newBase
+	return changes, rec.save(baseDir)
+}
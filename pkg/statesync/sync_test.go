@@ -0,0 +1,169 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statesync
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSidecar(t *testing.T, dir string, sc models.Sidecar) {
+	content, err := json.MarshalIndent(sc, "", "    ")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, sc.Name+"_sidecar.json"), content, 0o600))
+}
+
+func readSidecar(t *testing.T, dir string, name string) models.Sidecar {
+	content, err := os.ReadFile(filepath.Join(dir, name+"_sidecar.json"))
+	assert.NoError(t, err)
+	var sc models.Sidecar
+	assert.NoError(t, json.Unmarshal(content, &sc))
+	return sc
+}
+
+func TestParseRemote(t *testing.T) {
+	assert := assert.New(t)
+
+	for url, expected := range map[string]Remote{
+		"git+https://example.com/team/subnets#main": &gitRemote{url: "git+https://example.com/team/subnets#main", repository: "https://example.com/team/subnets", branch: "main"},
+		"git@github.com:team/subnets.git":           &gitRemote{url: "git@github.com:team/subnets.git", repository: "git@github.com:team/subnets.git"},
+		"file:///mnt/shared/subnets":                &dirRemote{path: "/mnt/shared/subnets"},
+		"/mnt/shared/subnets":                       &dirRemote{path: "/mnt/shared/subnets"},
+	} {
+		remote, err := ParseRemote(url)
+		assert.NoError(err)
+		assert.Equal(expected, remote, url)
+	}
+	remote, err := ParseRemote("s3://bucket/subnets")
+	assert.NoError(err)
+	assert.Equal("aws", remote.(*bucketRemote).tool)
+	remote, err = ParseRemote("gs://bucket/subnets")
+	assert.NoError(err)
+	assert.Equal("gsutil", remote.(*bucketRemote).tool)
+	_, err = ParseRemote("")
+	assert.Error(err)
+}
+
+func TestPlan(t *testing.T) {
+	assert := assert.New(t)
+
+	a, b := []byte("a"), []byte("b")
+	base := map[string]string{"same": hash(a), "taken": hash(a), "kept": hash(a), "both": hash(a), "deleted": hash(a)}
+	from := map[string][]byte{"same": a, "taken": b, "kept": a, "both": b, "added": a}
+	to := map[string][]byte{"same": a, "taken": a, "kept": b, "both": []byte("c"), "deleted": a}
+	assert.Equal([]Change{
+		{File: "added", Action: ActionAdd},
+		{File: "both", Action: ActionConflict},
+		{File: "deleted", Action: ActionDelete},
+		{File: "taken", Action: ActionUpdate},
+	}, plan(from, to, base, false))
+	assert.Contains(plan(from, to, base, true), Change{File: "both", Action: ActionUpdate})
+}
+
+func TestPushPull(t *testing.T) {
+	assert := assert.New(t)
+
+	alice, bob := t.TempDir(), t.TempDir()
+	remote, err := ParseRemote(t.TempDir())
+	assert.NoError(err)
+
+	fuji := models.NetworkData{SubnetID: [32]byte{1}}
+	writeSidecar(t, alice, models.Sidecar{Name: "tokens", Networks: map[string]models.NetworkData{
+		"Fuji":          fuji,
+		"Local Network": {SubnetID: [32]byte{2}},
+	}})
+	assert.NoError(os.WriteFile(filepath.Join(alice, "tokens_genesis.json"), []byte("{}"), 0o600))
+	assert.NoError(os.WriteFile(filepath.Join(alice, "key.pk"), []byte("secret"), 0o600))
+
+	changes, err := Push(alice, remote, Options{})
+	assert.NoError(err)
+	assert.Len(changes, 2)
+	remoteDir, err := remote.Checkout()
+	assert.NoError(err)
+	assert.NoFileExists(filepath.Join(remoteDir, "key.pk"))
+	assert.NotContains(readSidecar(t, remoteDir, "tokens").Networks, "Local Network")
+
+	// bob keeps the local deployments, and is then in sync
+	writeSidecar(t, bob, models.Sidecar{Name: "tokens", Networks: map[string]models.NetworkData{
+		"Local Network (dev)": {SubnetID: [32]byte{3}},
+	}})
+	_, err = Pull(bob, remote, Options{})
+	assert.ErrorContains(err, "tokens_sidecar.json changed both locally and on the remote")
+	changes, err = Pull(bob, remote, Options{Force: true})
+	assert.NoError(err)
+	assert.Len(changes, 2)
+	assert.Equal(map[string]models.NetworkData{
+		"Fuji":                fuji,
+		"Local Network (dev)": {SubnetID: [32]byte{3}},
+	}, readSidecar(t, bob, "tokens").Networks)
+	changes, err = Push(bob, remote, Options{})
+	assert.NoError(err)
+	assert.Empty(changes)
+
+	// a deletion by bob is pulled by alice, and a change by alice, made
+	// meanwhile, conflicts with a change by bob
+	assert.NoError(os.Remove(filepath.Join(bob, "tokens_genesis.json")))
+	_, err = Push(bob, remote, Options{})
+	assert.NoError(err)
+	writeSidecar(t, alice, models.Sidecar{Name: "tokens", Version: "1"})
+	writeSidecar(t, bob, models.Sidecar{Name: "tokens", Version: "2"})
+	_, err = Push(bob, remote, Options{})
+	assert.NoError(err)
+	changes, err = Pull(alice, remote, Options{DryRun: true})
+	assert.ErrorContains(err, "tokens_sidecar.json")
+	assert.Contains(changes, Change{File: "tokens_genesis.json", Action: ActionDelete})
+	assert.FileExists(filepath.Join(alice, "tokens_genesis.json"))
+	_, err = Pull(alice, remote, Options{Force: true})
+	assert.NoError(err)
+	assert.NoFileExists(filepath.Join(alice, "tokens_genesis.json"))
+	assert.Equal("2", readSidecar(t, alice, "tokens").Version)
+}
+
+func TestGitRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	assert := assert.New(t)
+	for _, v := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(v, "test")
+	}
+	for _, v := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(v, "test@example.com")
+	}
+
+	repository := t.TempDir()
+	out, err := runCommand(repository, "git", "init", "--quiet", "--bare", "--initial-branch=main")
+	assert.NoError(err, string(out))
+	remote, err := ParseRemote("git+" + repository + "#main")
+	assert.NoError(err)
+	// an empty repository can't be cloned with a branch, so the first push
+	// is made to an initialized one
+	seed := t.TempDir()
+	for _, args := range [][]string{
+		{"clone", "--quiet", repository, "."},
+		{"commit", "--quiet", "--allow-empty", "-m", "init"},
+		{"push", "--quiet", "origin", "HEAD:main"},
+	} {
+		out, err := runCommand(seed, "git", args...)
+		assert.NoError(err, string(out))
+	}
+
+	alice, bob := t.TempDir(), t.TempDir()
+	writeSidecar(t, alice, models.Sidecar{Name: "tokens"})
+	changes, err := Push(alice, remote, Options{Message: "Add tokens"})
+	assert.NoError(err)
+	assert.Len(changes, 1)
+	changes, err = Pull(bob, remote, Options{})
+	assert.NoError(err)
+	assert.Equal([]Change{{File: "tokens_sidecar.json", Action: ActionAdd}}, changes)
+	assert.Equal("tokens", readSidecar(t, bob, "tokens").Name)
+	out, err = runCommand(repository, "git", "log", "--format=%s", "main")
+	assert.NoError(err)
+	assert.Equal("Add tokens\ninit\n", string(out))
+}
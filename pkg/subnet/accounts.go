@@ -0,0 +1,30 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetBalances returns the balances of addrs at the last block of the EVM
+// chain at rpcURL, in the order of addrs
+func GetBalances(ctx context.Context, rpcURL string, addrs []common.Address) ([]*big.Int, error) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	balances := make([]*big.Int, len(addrs))
+	for i, addr := range addrs {
+		if balances[i], err = client.BalanceAt(ctx, addr, nil); err != nil {
+			return nil, fmt.Errorf("failed getting the balance of %s: %w", addr.Hex(), err)
+		}
+	}
+	return balances, nil
+}
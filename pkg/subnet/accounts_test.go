@@ -0,0 +1,47 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBalances(t *testing.T) {
+	assert := assert.New(t)
+
+	funded := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	empty := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []string        `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "eth_getBalance" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		result := "0x0"
+		if strings.EqualFold(req.Params[0], funded.Hex()) {
+			result = "0xde0b6b3a7640000"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%q}`, req.ID, result)
+	}))
+	defer s.Close()
+
+	balances, err := GetBalances(context.Background(), s.URL, []common.Address{funded, empty})
+	assert.NoError(err)
+	assert.Len(balances, 2)
+	assert.Equal("1000000000000000000", balances[0].String())
+	assert.Zero(balances[1].Sign())
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// chainAliaser is the part of the admin API of a node managing chain aliases
+type chainAliaser interface {
+	AliasChain(ctx context.Context, chainID string, alias string, options ...rpc.Option) error
+	GetChainAliases(ctx context.Context, chainID string, options ...rpc.Option) ([]string, error)
+}
+
+var newChainAliaser = func(nodeURI string) chainAliaser {
+	return admin.NewClient(nodeURI)
+}
+
+// AliasChains aliases every custom blockchain of the cluster with the name of
+// its VM on every node, so that its endpoints are also served at
+// /ext/bc/<vmName>, see ChainAlias. Blockchains already aliased are skipped.
+// The aliases don't survive a restart of the nodes, so this must be called
+// again once the network is restarted.
+func AliasChains(ctx context.Context, clusterInfo *rpcpb.ClusterInfo) error {
+	nodeNames := make([]string, 0, len(clusterInfo.GetNodeInfos()))
+	for name := range clusterInfo.GetNodeInfos() {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	failures := []string{}
+	for _, nodeName := range nodeNames {
+		aliaser := newChainAliaser(clusterInfo.NodeInfos[nodeName].GetUri())
+		for blockchainID, vmInfo := range clusterInfo.GetCustomVms() {
+			alias := ChainAlias(vmInfo)
+			if err := aliasChain(ctx, aliaser, blockchainID, alias); err != nil {
+				failures = append(failures, fmt.Sprintf("%s as %s on %s: %s", blockchainID, alias, nodeName, err))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("failed aliasing blockchains %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func aliasChain(ctx context.Context, aliaser chainAliaser, blockchainID string, alias string) error {
+	aliases, err := aliaser.GetChainAliases(ctx, blockchainID)
+	if err != nil {
+		return err
+	}
+	for _, a := range aliases {
+		if a == alias {
+			return nil
+		}
+	}
+	return aliaser.AliasChain(ctx, blockchainID, alias)
+}
+
+// ChainAlias returns the alias of a custom blockchain set by AliasChains
+func ChainAlias(vmInfo *rpcpb.CustomVmInfo) string {
+	return vmInfo.GetVmName()
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAliaser keeps the chain aliases of a node
+type fakeAliaser struct {
+	aliases map[string][]string
+	fail    bool
+}
+
+func (f *fakeAliaser) AliasChain(_ context.Context, chainID string, alias string, _ ...rpc.Option) error {
+	if f.fail {
+		return errors.New("admin API disabled")
+	}
+	f.aliases[chainID] = append(f.aliases[chainID], alias)
+	return nil
+}
+
+func (f *fakeAliaser) GetChainAliases(_ context.Context, chainID string, _ ...rpc.Option) ([]string, error) {
+	return append([]string{chainID}, f.aliases[chainID]...), nil
+}
+
+func TestAliasChains(t *testing.T) {
+	assert := assert.New(t)
+
+	nodes := map[string]*fakeAliaser{
+		"http://node1": {aliases: map[string][]string{}},
+		"http://node2": {aliases: map[string][]string{}},
+	}
+	defer func(f func(string) chainAliaser) { newChainAliaser = f }(newChainAliaser)
+	newChainAliaser = func(uri string) chainAliaser { return nodes[uri] }
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", Uri: "http://node1"},
+			"node2": {Name: "node2", Uri: "http://node2"},
+		},
+		CustomVms: map[string]*rpcpb.CustomVmInfo{
+			"chain1": {VmName: "mySubnet", BlockchainId: "chain1"},
+			"chain2": {VmName: "other", BlockchainId: "chain2"},
+		},
+	}
+	assert.NoError(AliasChains(context.Background(), clusterInfo))
+	// aliasing again skips the aliases already set
+	assert.NoError(AliasChains(context.Background(), clusterInfo))
+	for _, node := range nodes {
+		assert.Equal(map[string][]string{"chain1": {"mySubnet"}, "chain2": {"other"}}, node.aliases)
+	}
+
+	nodes["http://node2"] = &fakeAliaser{aliases: map[string][]string{}, fail: true}
+	err := AliasChains(context.Background(), clusterInfo)
+	assert.ErrorContains(err, "chain1 as mySubnet on node2: admin API disabled")
+	assert.ErrorContains(err, "chain2 as other on node2: admin API disabled")
+	assert.NotContains(err.Error(), "node1")
+}
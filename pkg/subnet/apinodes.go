@@ -0,0 +1,172 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+)
+
+// apiNodePrefix names the API nodes of the local network, api1, api2 and so
+// on, apart from the validators of the snapshot, node1 to node5
+const apiNodePrefix = "api"
+
+// IsAPINode returns true if the node of the local network named name is an
+// API node, which tracks the deployed subnets without validating them
+func IsAPINode(name string) bool {
+	n, err := strconv.Atoi(strings.TrimPrefix(name, apiNodePrefix))
+	return strings.HasPrefix(name, apiNodePrefix) && err == nil && n > 0
+}
+
+// ValidatorNodeNames returns the names of the nodes of the local network
+// which validate its subnets, all but the API nodes, in order
+func ValidatorNodeNames(clusterInfo *rpcpb.ClusterInfo) []string {
+	names := []string{}
+	for name := range clusterInfo.GetNodeInfos() {
+		if !IsAPINode(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// APINodeNames returns the names of the API nodes of the local network, in
+// order
+func APINodeNames(clusterInfo *rpcpb.ClusterInfo) []string {
+	names := []string{}
+	for name := range clusterInfo.GetNodeInfos() {
+		if IsAPINode(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apiNodeName returns the name of the i-th API node, from 1
+func apiNodeName(i int) string {
+	return apiNodePrefix + strconv.Itoa(i)
+}
+
+// apiNodeConfig returns the config of an API node, the global node config
+// merged with its own settings of node-configs, tracking subnetIDs
+func apiNodeConfig(globalConfig map[string]interface{}, nodeConfig map[string]interface{}, subnetIDs []string) (string, error) {
+	config := map[string]interface{}{}
+	for k, v := range globalConfig {
+		config[k] = v
+	}
+	for k, v := range nodeConfig {
+		config[k] = v
+	}
+	ids := append([]string{}, subnetIDs...)
+	sort.Strings(ids)
+	config["whitelisted-subnets"] = strings.Join(ids, ",")
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(configBytes), nil
+}
+
+// RemoveAPINodes removes the API nodes of the local network beyond the first
+// keep ones. The network runner adds every node of the network as a
+// validator of the subnets it creates, which the API nodes can't be, as they
+// don't validate the primary network, so they are all removed before a
+// blockchain is created. Returns the info of the network once healthy.
+func (d *LocalSubnetDeployer) RemoveAPINodes(
+	ctx context.Context,
+	cli client.Client,
+	clusterInfo *rpcpb.ClusterInfo,
+	keep int,
+) (*rpcpb.ClusterInfo, error) {
+	removed := false
+	for _, name := range APINodeNames(clusterInfo) {
+		if n, _ := strconv.Atoi(strings.TrimPrefix(name, apiNodePrefix)); n <= keep {
+			continue
+		}
+		ux.Logger.PrintToUser("Removing API node %s...", name)
+		if _, err := cli.RemoveNode(ctx, name); err != nil {
+			return nil, fmt.Errorf("failed removing API node %s: %w", name, err)
+		}
+		removed = true
+	}
+	if !removed {
+		return clusterInfo, nil
+	}
+	clusterInfo, err := d.WaitForHealthy(ctx, cli, d.healthCheckInterval)
+	if err != nil {
+		return nil, fmt.Errorf("the network failed to become healthy once its API nodes were removed: %w", err)
+	}
+	return FilterUndeployed(d.app, clusterInfo)
+}
+
+// AddAPINodes adds the API nodes of the local network it misses, api1 to
+// apiN for the number of API nodes set by the project config or topology,
+// and removes the others. They run avalancheGoBinPath with the global node
+// config and their own settings of node-configs, and track all the subnets
+// with a blockchain deployed, without validating them. Returns the info of
+// the network once healthy.
+func (d *LocalSubnetDeployer) AddAPINodes(
+	ctx context.Context,
+	cli client.Client,
+	clusterInfo *rpcpb.ClusterInfo,
+	avalancheGoBinPath string,
+) (*rpcpb.ClusterInfo, error) {
+	clusterInfo, err := d.RemoveAPINodes(ctx, cli, clusterInfo, d.apiNodes)
+	if err != nil {
+		return nil, err
+	}
+	missing := []string{}
+	for i := 1; i <= d.apiNodes; i++ {
+		if _, ok := clusterInfo.GetNodeInfos()[apiNodeName(i)]; !ok {
+			missing = append(missing, apiNodeName(i))
+		}
+	}
+	if len(missing) == 0 {
+		return clusterInfo, nil
+	}
+	nodeConfigs, err := d.app.Conf.NodeConfigs()
+	if err != nil {
+		return nil, exitcodes.UserInput(err)
+	}
+	subnetIDs := []string{}
+	tracked := map[string]bool{}
+	for _, vmInfo := range clusterInfo.GetCustomVms() {
+		if subnetID := vmInfo.GetSubnetId(); subnetID != "" && !tracked[subnetID] {
+			tracked[subnetID] = true
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+	}
+	chainConfigs, err := LocalChainConfigs(d.app)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range missing {
+		config, err := apiNodeConfig(d.app.Conf.GlobalNodeConfig(), nodeConfigs[name], subnetIDs)
+		if err != nil {
+			return nil, err
+		}
+		opts := []client.OpOption{client.WithGlobalNodeConfig(config)}
+		if len(chainConfigs) > 0 {
+			opts = append(opts, client.WithChainConfigs(chainConfigs))
+		}
+		ux.Logger.PrintToUser("Adding API node %s...", name)
+		if _, err := cli.AddNode(ctx, name, avalancheGoBinPath, opts...); err != nil {
+			return nil, fmt.Errorf("failed adding API node %s: %w", name, err)
+		}
+	}
+	if clusterInfo, err = d.WaitForHealthy(ctx, cli, d.healthCheckInterval); err != nil {
+		return nil, fmt.Errorf("the network failed to become healthy once its API nodes were added: %w", err)
+	}
+	return FilterUndeployed(d.app, clusterInfo)
+}
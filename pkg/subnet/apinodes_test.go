@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPINodeNames(t *testing.T) {
+	assert := setupTest(t)
+
+	assert.True(IsAPINode("api1"))
+	assert.True(IsAPINode("api12"))
+	assert.False(IsAPINode("api"))
+	assert.False(IsAPINode("api0"))
+	assert.False(IsAPINode("apiary"))
+	assert.False(IsAPINode("node1"))
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node2": {Name: "node2"},
+			"api1":  {Name: "api1"},
+			"node1": {Name: "node1"},
+		},
+	}
+	assert.Equal([]string{"node1", "node2"}, ValidatorNodeNames(clusterInfo))
+	assert.Equal([]string{"api1"}, APINodeNames(clusterInfo))
+	assert.Empty(APINodeNames(nil))
+}
+
+func TestAPINodeConfig(t *testing.T) {
+	assert := setupTest(t)
+
+	configStr, err := apiNodeConfig(
+		map[string]interface{}{"log-level": "info", "http-host": "0.0.0.0"},
+		map[string]interface{}{"log-level": "debug"},
+		[]string{testSubnetID2, testSubnetID1},
+	)
+	assert.NoError(err)
+	var nodeConfig map[string]interface{}
+	assert.NoError(json.Unmarshal([]byte(configStr), &nodeConfig))
+	subnetIDs := testSubnetID1 + "," + testSubnetID2
+	if testSubnetID2 < testSubnetID1 {
+		subnetIDs = testSubnetID2 + "," + testSubnetID1
+	}
+	assert.Equal(map[string]interface{}{
+		"log-level":           "debug",
+		"http-host":           "0.0.0.0",
+		"whitelisted-subnets": subnetIDs,
+	}, nodeConfig)
+}
+
+func TestAddAPINodes(t *testing.T) {
+	assert := setupTest(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+	d := &LocalSubnetDeployer{app: app, healthCheckInterval: time.Millisecond, apiNodes: 2}
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		Healthy:          true,
+		CustomVmsHealthy: true,
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1"},
+			"api1":  {Name: "api1"},
+			"api3":  {Name: "api3"},
+		},
+		CustomVms: map[string]*rpcpb.CustomVmInfo{
+			testBlockChainID1: {BlockchainId: testBlockChainID1, SubnetId: testSubnetID1},
+		},
+	}
+	cli := &mocks.Client{}
+	cli.On("RemoveNode", mock.Anything, "api3").Return(&rpcpb.RemoveNodeResponse{}, nil).Once()
+	var addOpts []client.OpOption
+	cli.On("AddNode", mock.Anything, "api2", "/bin/avalanchego", mock.Anything).
+		Run(func(args mock.Arguments) {
+			for _, opt := range args[3:] {
+				addOpts = append(addOpts, opt.(client.OpOption))
+			}
+		}).
+		Return(&rpcpb.AddNodeResponse{}, nil).Once()
+	cli.On("Health", mock.Anything).Return(&rpcpb.HealthResponse{ClusterInfo: clusterInfo}, nil)
+
+	// the extra API node is removed, and the missing one added
+	_, err := d.AddAPINodes(context.Background(), cli, clusterInfo, "/bin/avalanchego")
+	assert.NoError(err)
+	cli.AssertExpectations(t)
+	assert.Len(addOpts, 1)
+
+	// nothing changes once the network has its API nodes
+	clusterInfo.NodeInfos = map[string]*rpcpb.NodeInfo{
+		"node1": {Name: "node1"},
+		"api1":  {Name: "api1"},
+		"api2":  {Name: "api2"},
+	}
+	got, err := d.AddAPINodes(context.Background(), &mocks.Client{}, clusterInfo, "/bin/avalanchego")
+	assert.NoError(err)
+	assert.Equal(clusterInfo, got)
+
+	// and all of them are removed before creating a blockchain
+	cli = &mocks.Client{}
+	cli.On("RemoveNode", mock.Anything, "api1").Return(&rpcpb.RemoveNodeResponse{}, nil).Once()
+	cli.On("RemoveNode", mock.Anything, "api2").Return(&rpcpb.RemoveNodeResponse{}, nil).Once()
+	cli.On("Health", mock.Anything).Return(&rpcpb.HealthResponse{ClusterInfo: clusterInfo}, nil)
+	_, err = d.RemoveAPINodes(context.Background(), cli, clusterInfo, 0)
+	assert.NoError(err)
+	cli.AssertExpectations(t)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// customAvalancheGoDir holds the plugin directory of the avalanchego binary
+// set with --avalanchego-path, which can't be next to the binary, e.g. in
+// /usr/local/bin
+const customAvalancheGoDir = "avalanchego-custom"
+
+// setupCustomAvalancheGo checks the avalanchego binary at avalancheGoBinPath
+// can run the VM plugins paired with avalanchego release version, and returns
+// its absolute path and the plugin directory to run it with
+func setupCustomAvalancheGo(log logging.Logger, avalancheGoBinPath string, version string, binDir string) (string, string, error) {
+	avalancheGoBinPath, err := filepath.Abs(avalancheGoBinPath)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := os.Stat(avalancheGoBinPath)
+	if err != nil {
+		return "", "", exitcodes.UserInput(fmt.Errorf("invalid --avalanchego-path: %w", err))
+	}
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		return "", "", exitcodes.UserInput(fmt.Errorf("invalid --avalanchego-path: %s is not an executable", avalancheGoBinPath))
+	}
+	binVersion, err := binutils.AvalancheGoBinaryVersion(avalancheGoBinPath)
+	if err != nil {
+		return "", "", exitcodes.UserInput(fmt.Errorf("invalid --avalanchego-path: %w", err))
+	}
+	if err := checkRPCChainVMProtocol(log, binVersion, version); err != nil {
+		return "", "", exitcodes.UserInput(fmt.Errorf("avalanchego at %s: %w", avalancheGoBinPath, err))
+	}
+
+	pluginDir := filepath.Join(binDir, customAvalancheGoDir, "plugins")
+	if err := os.MkdirAll(pluginDir, constants.DefaultPerms755); err != nil {
+		return "", "", err
+	}
+	ux.Logger.PrintToUser("Using avalanchego %s at %s", binVersion, avalancheGoBinPath)
+	return avalancheGoBinPath, pluginDir, nil
+}
+
+// checkRPCChainVMProtocol returns an error if avalanchego binVersion doesn't
+// speak the RPCChainVM protocol of release version, the one the VM plugins
+// are paired with. A version of unknown protocol is only warned about.
+func checkRPCChainVMProtocol(log logging.Logger, binVersion string, version string) error {
+	expected, ok := binutils.RPCChainVMProtocol(version)
+	if !ok {
+		log.Warn("the RPCChainVM protocol of avalanchego %s is unknown, not checking the one of avalanchego %s", version, binVersion)
+		return nil
+	}
+	protocol, ok := binutils.RPCChainVMProtocol(binVersion)
+	if !ok {
+		ux.Logger.PrintToUser("The RPCChainVM protocol of avalanchego %s is unknown, the VM plugins may fail to start if it isn't %d", binVersion, expected)
+		return nil
+	}
+	if protocol != expected {
+		return fmt.Errorf(
+			"avalanchego %s speaks RPCChainVM protocol %d, but the VM plugins are paired with avalanchego %s, speaking protocol %d, and would fail their handshake. "+
+				"Use a build of avalanchego based on a release speaking protocol %d (%s), or pin versions of avalanchego and subnet-evm speaking protocol %d in the project config",
+			binVersion, protocol, version, expected, expected, binutils.ProtocolReleases(expected), protocol,
+		)
+	}
+	return nil
+}
+
+// CheckPluginsProtocol returns an error if a VM plugin of pluginDir speaks
+// another RPCChainVM protocol than the avalanchego binary at
+// avalancheGoBinPath, which would make the plugin fail its handshake once
+// the network runs it. The plugins and versions of unknown protocol are
+// skipped.
+func CheckPluginsProtocol(log logging.Logger, avalancheGoBinPath string, pluginDir string) error {
+	version, err := binutils.AvalancheGoBinaryVersion(avalancheGoBinPath)
+	if err != nil {
+		log.Warn("not checking the RPCChainVM protocol of the VM plugins: %s", err)
+		return nil
+	}
+	expected, ok := binutils.RPCChainVMProtocol(version)
+	if !ok {
+		log.Warn("the RPCChainVM protocol of avalanchego %s is unknown, not checking the one of the VM plugins", version)
+		return nil
+	}
+	entries, err := os.ReadDir(pluginDir)
+	if err != nil {
+		return err
+	}
+	mismatches := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		pluginVersion, protocol, ok := binutils.PluginProtocol(filepath.Join(pluginDir, e.Name()))
+		if !ok {
+			log.Debug("the RPCChainVM protocol of VM plugin %s is unknown", e.Name())
+			continue
+		}
+		if protocol != expected {
+			mismatches = append(mismatches, fmt.Sprintf("  %s (%s): protocol %d", e.Name(), pluginVersion, protocol))
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf(
+		"the VM plugins below speak another RPCChainVM protocol than avalanchego %s, which speaks protocol %d, and would fail their handshake when the network starts them:",
+		version, expected,
+	)}
+	lines = append(lines, mismatches...)
+	lines = append(lines, "The RPCChainVM protocols of the releases are:")
+	for _, line := range binutils.CompatibilityMatrix() {
+		lines = append(lines, "  "+line)
+	}
+	lines = append(lines, "Pin versions of avalanchego and subnet-evm speaking the same protocol in the project config, or run a matching avalanchego with --avalanchego-path")
+	return exitcodes.UserInput(errors.New(strings.Join(lines, "\n")))
+}
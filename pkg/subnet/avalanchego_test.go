@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func writeFakeAvalancheGo(t *testing.T, version string) string {
+	binPath := filepath.Join(t.TempDir(), "avalanchego")
+	script := "#!/bin/sh\necho 'avalanche/" + version + " [database=v1.4.5]'\n"
+	if err := os.WriteFile(binPath, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return binPath
+}
+
+func TestSetupCustomAvalancheGo(t *testing.T) {
+	assert := setupTest(t)
+
+	binDir := t.TempDir()
+	binPath := writeFakeAvalancheGo(t, "1.7.15")
+	avalancheGoBinPath, pluginDir, err := setupCustomAvalancheGo(logging.NoLog{}, binPath, "v1.7.16", binDir)
+	assert.NoError(err)
+	assert.Equal(binPath, avalancheGoBinPath)
+	assert.Equal(filepath.Join(binDir, customAvalancheGoDir, "plugins"), pluginDir)
+	assert.DirExists(pluginDir)
+
+	// a binary speaking another RPCChainVM protocol is refused
+	_, _, err = setupCustomAvalancheGo(logging.NoLog{}, writeFakeAvalancheGo(t, "1.7.13"), "v1.7.16", binDir)
+	assert.ErrorContains(err, "RPCChainVM protocol 14")
+	assert.ErrorContains(err, "v1.7.14, v1.7.15, v1.7.16")
+
+	// the protocol of unknown versions can't be checked
+	_, _, err = setupCustomAvalancheGo(logging.NoLog{}, writeFakeAvalancheGo(t, "9.9.9"), "v1.7.16", binDir)
+	assert.NoError(err)
+
+	_, _, err = setupCustomAvalancheGo(logging.NoLog{}, filepath.Join(binDir, "missing"), "v1.7.16", binDir)
+	assert.Error(err)
+	_, _, err = setupCustomAvalancheGo(logging.NoLog{}, binDir, "v1.7.16", binDir)
+	assert.ErrorContains(err, "not an executable")
+}
+
+func TestCheckPluginsProtocol(t *testing.T) {
+	assert := setupTest(t)
+
+	pluginDir := t.TempDir()
+	writePlugin := func(name string, version string) {
+		script := "#!/bin/sh\necho '" + version + "'\n"
+		assert.NoError(os.WriteFile(filepath.Join(pluginDir, name), []byte(script), 0o755))
+	}
+	writePlugin("subnetEVM", "v0.2.4")
+	writePlugin("customVM", "not a version")
+
+	assert.NoError(CheckPluginsProtocol(logging.NoLog{}, writeFakeAvalancheGo(t, "1.7.13"), pluginDir))
+
+	err := CheckPluginsProtocol(logging.NoLog{}, writeFakeAvalancheGo(t, "1.7.16"), pluginDir)
+	assert.ErrorContains(err, "subnetEVM (v0.2.4): protocol 14")
+	assert.ErrorContains(err, "protocol 15: avalanchego v1.7.14")
+	assert.NotContains(err.Error(), "customVM")
+
+	// the protocols of unknown versions of avalanchego can't be checked
+	assert.NoError(CheckPluginsProtocol(logging.NoLog{}, writeFakeAvalancheGo(t, "9.9.9"), pluginDir))
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+)
+
+const (
+	// prefix of the directories of the snapshots saved by the network runner
+	snapshotPrefix = "anr-snapshot-"
+	// the snapshot is saved under this name before replacing the default one,
+	// which is kept if anything fails
+	rebuiltSnapshotName = constants.DefaultSnapshotName + "-rebuilt"
+)
+
+// SnapshotRecord records the versions which saved a snapshot, or rebuilt the
+// bootstrap snapshot, as older avalanchego releases may not read the
+// databases of newer ones
+type SnapshotRecord struct {
+	CLIVersion         string `json:"cliVersion,omitempty"`
+	AvalancheGoVersion string `json:"avalancheGoVersion,omitempty"`
+}
+
+// LoadSnapshotRecord returns the record of the bootstrap snapshot of app,
+// false if it wasn't rebuilt, as the downloaded one isn't recorded
+func LoadSnapshotRecord(app *application.Avalanche) (SnapshotRecord, bool, error) {
+	return loadSnapshotRecord(filepath.Join(app.GetSnapshotsDir(), constants.BootstrapSnapshotRecordName))
+}
+
+// LoadNamedSnapshotRecord returns the record of the snapshot snapshotName of
+// app, false if it wasn't recorded
+func LoadNamedSnapshotRecord(app *application.Avalanche, snapshotName string) (SnapshotRecord, bool, error) {
+	return loadSnapshotRecord(snapshotRecordPath(app.GetSnapshotsDir(), snapshotName))
+}
+
+func loadSnapshotRecord(path string) (SnapshotRecord, bool, error) {
+	recordBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SnapshotRecord{}, false, nil
+	}
+	if err != nil {
+		return SnapshotRecord{}, false, err
+	}
+	var record SnapshotRecord
+	if err := json.Unmarshal(recordBytes, &record); err != nil {
+		return SnapshotRecord{}, false, fmt.Errorf("failed reading the record of snapshot %s: %w", path, err)
+	}
+	return record, true, nil
+}
+
+func snapshotRecordPath(snapshotsDir string, snapshotName string) string {
+	return filepath.Join(snapshotsDir, snapshotName+constants.SnapshotRecordSuffix)
+}
+
+// LocalAvalancheGoVersion returns the avalanchego version the local network
+// of app runs, empty if the avalanchego binary given doesn't report it
+func LocalAvalancheGoVersion(app *application.Avalanche) string {
+	if path := app.Conf.AvalancheGoPath(); path != "" {
+		version, err := binutils.AvalancheGoBinaryVersion(path)
+		if err != nil {
+			app.Log.Warn("failed getting the version of %s: %s", path, err)
+		}
+		return version
+	}
+	version, _ := app.Conf.AvalancheGoVersion()
+	return version
+}
+
+// WriteSnapshotRecord records the versions of this CLI and of the local
+// avalanchego as the ones which saved the snapshot snapshotName
+func WriteSnapshotRecord(app *application.Avalanche, snapshotName string) error {
+	return writeSnapshotRecord(app, snapshotRecordPath(app.GetSnapshotsDir(), snapshotName))
+}
+
+func writeSnapshotRecord(app *application.Avalanche, path string) error {
+	recordBytes, err := json.MarshalIndent(SnapshotRecord{
+		CLIVersion:         app.GetVersion(),
+		AvalancheGoVersion: LocalAvalancheGoVersion(app),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, recordBytes, application.WriteReadReadPerms)
+}
+
+// RebuildDefaultSnapshot regenerates the bootstrap snapshot with the local
+// avalanchego instead of downloading it: it starts a clean network, creates
+// numSubnets validated subnets for the deploys to use, and saves it as the
+// bootstrap snapshot archive and the default snapshot. Unless networkID is 0,
+// the network is a standalone one with this network ID. The local network
+// must not be running.
+func (d *LocalSubnetDeployer) RebuildDefaultSnapshot(numSubnets uint32, networkID uint32) error {
+	if networkID != 0 {
+		if err := CheckLocalNetworkID(networkID); err != nil {
+			return exitcodes.UserInput(err)
+		}
+	}
+	if err := d.StartServer(); err != nil {
+		return err
+	}
+	avalancheGoBinPath, pluginDir, err := d.setupLocalEnv()
+	if err != nil {
+		return fmt.Errorf("failed setting up local environment: %w", err)
+	}
+
+	cli, err := d.getClientFunc()
+	if err != nil {
+		return fmt.Errorf("error creating gRPC Client: %w", err)
+	}
+	defer cli.Close()
+
+	ctx := binutils.GetAsyncContext()
+
+	_, err = cli.Status(ctx)
+	switch {
+	case err == nil:
+		return exitcodes.UserInput(errors.New("the local network is running, stop it with network stop or network clean first"))
+	// TODO: use error type not string comparison
+	case !strings.Contains(err.Error(), "not bootstrapped"):
+		return exitcodes.Backend(fmt.Errorf("failed querying the local network: %w", err))
+	}
+
+	snapshotsDir := d.app.GetSnapshotsDir()
+	if networkID == 0 {
+		ux.Logger.PrintToUser("Starting a clean network with %s...", avalancheGoBinPath)
+		if _, err := cli.Start(
+			ctx,
+			avalancheGoBinPath,
+			client.WithPluginDir(pluginDir),
+			client.WithRootDataDir(d.app.GetRunDir()),
+		); err != nil {
+			return fmt.Errorf("failed to start network: %w", err)
+		}
+	} else {
+		// the network runner only starts clean networks with its own network
+		// ID, so the network is loaded from a snapshot of the genesis instead
+		ux.Logger.PrintToUser("Starting a clean network with network ID %d with %s...", networkID, avalancheGoBinPath)
+		if err := writeGenesisSnapshot(snapshotsDir, avalancheGoBinPath, networkID); err != nil {
+			return fmt.Errorf("failed writing the genesis of network %d: %w", networkID, err)
+		}
+		defer os.RemoveAll(filepath.Join(snapshotsDir, snapshotPrefix+genesisSnapshotName))
+		if _, err := cli.LoadSnapshot(
+			ctx,
+			genesisSnapshotName,
+			client.WithPluginDir(pluginDir),
+			client.WithExecPath(avalancheGoBinPath),
+			client.WithRootDataDir(d.app.GetRunDir()),
+		); err != nil {
+			return fmt.Errorf("failed to start network: %w", err)
+		}
+	}
+	if _, err := d.WaitForHealthy(ctx, cli, d.healthCheckInterval); err != nil {
+		return fmt.Errorf("failed to query network health: %w", err)
+	}
+
+	ux.Logger.PrintToUser("Creating %d validated subnets...", numSubnets)
+	if _, err := cli.CreateSubnets(ctx, client.WithNumSubnets(numSubnets)); err != nil {
+		return fmt.Errorf("failed creating subnets: %w", err)
+	}
+	clusterInfo, err := d.WaitForHealthy(ctx, cli, d.healthCheckInterval)
+	if err != nil {
+		return fmt.Errorf("failed to query network health: %w", err)
+	}
+	if len(clusterInfo.Subnets) < int(numSubnets) {
+		return fmt.Errorf("expected %d subnets, the network has %d", numSubnets, len(clusterInfo.Subnets))
+	}
+
+	// saving the snapshot stops the network
+	_, _ = cli.RemoveSnapshot(ctx, rebuiltSnapshotName)
+	if _, err := cli.SaveSnapshot(ctx, rebuiltSnapshotName); err != nil {
+		return fmt.Errorf("failed saving snapshot: %w", err)
+	}
+
+	rebuiltDir := filepath.Join(snapshotsDir, snapshotPrefix+rebuiltSnapshotName)
+	defer os.RemoveAll(rebuiltDir)
+	archivePath := filepath.Join(snapshotsDir, constants.BootstrapSnapshotArchiveName)
+	tmpArchivePath := archivePath + ".tmp"
+	if err := binutils.CreateTarGzArchive(rebuiltDir, snapshotPrefix+constants.DefaultSnapshotName, tmpArchivePath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpArchivePath, archivePath); err != nil {
+		return fmt.Errorf("failed writing down bootstrap snapshot: %w", err)
+	}
+	if err := d.setDefaultSnapshot(snapshotsDir, true); err != nil {
+		return err
+	}
+	if err := writeSnapshotRecord(d.app, filepath.Join(snapshotsDir, constants.BootstrapSnapshotRecordName)); err != nil {
+		return fmt.Errorf("failed recording the versions of the bootstrap snapshot: %w", err)
+	}
+	if err := WriteSnapshotRecord(d.app, constants.DefaultSnapshotName); err != nil {
+		return fmt.Errorf("failed recording the versions of the default snapshot: %w", err)
+	}
+	if networkID == 0 {
+		networkID = constants.LocalNetworkID
+	}
+	ux.Logger.PrintToUser("Bootstrap snapshot of network %d rebuilt at %s, with %d subnets", networkID, archivePath, len(clusterInfo.Subnets))
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRebuildTestDeployer(t *testing.T, cli *mocks.Client, setDefaultSnapshot setDefaultSnapshotFunc) *LocalSubnetDeployer {
+	procChecker := &mocks.ProcessChecker{}
+	procChecker.On("IsServerProcessRunning", mock.Anything).Return(true, nil)
+	avagoDir := t.TempDir()
+	binChecker := &mocks.BinaryChecker{}
+	binChecker.On("ExistsWithLatestVersion", mock.Anything, mock.Anything).Return(true, avagoDir, nil)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+	return &LocalSubnetDeployer{
+		procChecker:         procChecker,
+		binChecker:          binChecker,
+		getClientFunc:       func() (client.Client, error) { return cli, nil },
+		healthCheckInterval: 0,
+		app:                 app,
+		setDefaultSnapshot:  setDefaultSnapshot,
+	}
+}
+
+func TestRebuildDefaultSnapshot(t *testing.T) {
+	assert := setupTest(t)
+
+	cli := &mocks.Client{}
+	var d *LocalSubnetDeployer
+	cli.On("Status", mock.Anything).Return(nil, errors.New("not bootstrapped"))
+	cli.On("Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&rpcpb.StartResponse{}, nil)
+	cli.On("CreateSubnets", mock.Anything, mock.Anything).Return(&rpcpb.CreateSubnetsResponse{}, nil)
+	cli.On("Health", mock.Anything).Return(fakeHealthResponse, nil)
+	cli.On("RemoveSnapshot", mock.Anything, rebuiltSnapshotName).Return(nil, errors.New("does not exist"))
+	cli.On("SaveSnapshot", mock.Anything, rebuiltSnapshotName).Run(func(args mock.Arguments) {
+		// the network runner writes the snapshot in the snapshots dir
+		dir := filepath.Join(d.app.GetSnapshotsDir(), snapshotPrefix+rebuiltSnapshotName)
+		assert.NoError(os.MkdirAll(dir, perms.ReadWriteExecute))
+		assert.NoError(os.WriteFile(filepath.Join(dir, "network.json"), []byte("{}"), perms.ReadWrite))
+	}).Return(&rpcpb.SaveSnapshotResponse{}, nil)
+	cli.On("Close").Return(nil)
+
+	forced := false
+	d = newRebuildTestDeployer(t, cli, func(snapshotsDir string, force bool) error {
+		forced = force
+		return binutils.InstallArchive(filepath.Join(snapshotsDir, constants.BootstrapSnapshotArchiveName), snapshotsDir)
+	})
+
+	assert.NoError(d.RebuildDefaultSnapshot(2, 0))
+	cli.AssertCalled(t, "CreateSubnets", mock.Anything, mock.Anything)
+	assert.True(forced)
+	snapshotsDir := d.app.GetSnapshotsDir()
+	_, err := os.Stat(filepath.Join(snapshotsDir, snapshotPrefix+constants.DefaultSnapshotName, "network.json"))
+	assert.NoError(err)
+	_, err = os.Stat(filepath.Join(snapshotsDir, snapshotPrefix+rebuiltSnapshotName))
+	assert.True(os.IsNotExist(err))
+	// the versions it was rebuilt with are recorded
+	record, rebuilt, err := LoadSnapshotRecord(d.app)
+	assert.NoError(err)
+	assert.True(rebuilt)
+	assert.Equal(constants.AvalancheGoReleaseVersion, record.AvalancheGoVersion)
+	_, recorded, err := LoadNamedSnapshotRecord(d.app, constants.DefaultSnapshotName)
+	assert.NoError(err)
+	assert.True(recorded)
+
+	// not enough subnets created
+	assert.Error(d.RebuildDefaultSnapshot(3, 0))
+}
+
+func TestRebuildDefaultSnapshotNetworkRunning(t *testing.T) {
+	assert := setupTest(t)
+
+	cli := &mocks.Client{}
+	cli.On("Status", mock.Anything).Return(&rpcpb.StatusResponse{}, nil)
+	cli.On("Close").Return(nil)
+	d := newRebuildTestDeployer(t, cli, fakeSetDefaultSnapshot)
+
+	err := d.RebuildDefaultSnapshot(2, 0)
+	assert.ErrorContains(err, "network is running")
+	cli.AssertNotCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
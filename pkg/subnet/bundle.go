@@ -0,0 +1,328 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p"
+)
+
+// kinds of transactions in a bundle
+const (
+	BundleCreateSubnetTx       = "CreateSubnetTx"
+	BundleCreateChainTx        = "CreateChainTx"
+	BundleAddSubnetValidatorTx = "AddSubnetValidatorTx"
+)
+
+var errBundleTxMismatch = errors.New("the bundle transaction does not match its kind")
+
+// TxBundle is a P-Chain transaction, along with everything needed to sign
+// it offline: the UTXOs it consumes and the transaction creating the subnet
+// it needs the authorization of. It is moved as a file between the machine
+// building it, the machines signing it and the machine broadcasting it.
+type TxBundle struct {
+	Kind string `json:"kind"`
+	// Network the transaction is for
+	Network string `json:"network"`
+	// SubnetName is the name of the subnet the transaction is for, whose
+	// sidecar is updated on broadcast
+	SubnetName string `json:"subnetName"`
+	// Tx is the transaction, with the signatures added so far
+	Tx string `json:"tx"`
+	// UTXOs are the UTXOs consumed by the transaction
+	UTXOs []string `json:"utxos"`
+	// SubnetTx is the transaction creating the subnet, if the transaction
+	// needs its authorization
+	SubnetTx string `json:"subnetTx,omitempty"`
+	// Governance of the subnet created by the transaction, recorded in the
+	// sidecar on broadcast
+	Governance *models.SubnetGovernance `json:"governance,omitempty"`
+}
+
+func encodeBytes(b []byte) (string, error) {
+	return formatting.Encode(formatting.Hex, b)
+}
+
+func decodeBytes(s string) ([]byte, error) {
+	return formatting.Decode(formatting.Hex, s)
+}
+
+// newTxBundle returns the bundle of the unsigned transaction utx, getting
+// the UTXOs it consumes and the subnet it needs the authorization of, if
+// any, from backend
+func newTxBundle(
+	ctx context.Context,
+	kind string,
+	network string,
+	subnetName string,
+	utx txs.UnsignedTx,
+	ins []*avax.TransferableInput,
+	subnetID ids.ID,
+	backend p.SignerBackend,
+) (*TxBundle, error) {
+	b := &TxBundle{
+		Kind:       kind,
+		Network:    network,
+		SubnetName: subnetName,
+	}
+	if err := b.setTx(&txs.Tx{Unsigned: utx}); err != nil {
+		return nil, err
+	}
+	for _, in := range ins {
+		utxo, err := backend.GetUTXO(ctx, avago_constants.PlatformChainID, in.InputID())
+		if err != nil {
+			return nil, fmt.Errorf("failed getting UTXO %s: %w", in.InputID(), err)
+		}
+		utxoBytes, err := txs.Codec.Marshal(txs.Version, utxo)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := encodeBytes(utxoBytes)
+		if err != nil {
+			return nil, err
+		}
+		b.UTXOs = append(b.UTXOs, encoded)
+	}
+	if subnetID != ids.Empty {
+		subnetTx, err := backend.GetTx(ctx, subnetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting subnet %s: %w", subnetID, err)
+		}
+		b.SubnetTx, err = encodeBytes(subnetTx.Bytes())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// LoadTxBundle reads the bundle at path
+func LoadTxBundle(path string) (*TxBundle, error) {
+	bundleBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &TxBundle{}
+	if err := json.Unmarshal(bundleBytes, b); err != nil {
+		return nil, fmt.Errorf("failed parsing transaction bundle %s: %w", path, err)
+	}
+	if _, err := b.GetTx(); err != nil {
+		return nil, fmt.Errorf("invalid transaction bundle %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Save writes the bundle to path
+func (b *TxBundle) Save(path string) error {
+	bundleBytes, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bundleBytes, application.WriteReadReadPerms)
+}
+
+// GetTx returns the transaction of the bundle
+func (b *TxBundle) GetTx() (*txs.Tx, error) {
+	txBytes, err := decodeBytes(b.Tx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txs.Parse(txs.Codec, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	var ok bool
+	switch b.Kind {
+	case BundleCreateSubnetTx:
+		_, ok = tx.Unsigned.(*txs.CreateSubnetTx)
+	case BundleCreateChainTx:
+		_, ok = tx.Unsigned.(*txs.CreateChainTx)
+	case BundleAddSubnetValidatorTx:
+		_, ok = tx.Unsigned.(*txs.AddSubnetValidatorTx)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errBundleTxMismatch, b.Kind)
+	}
+	return tx, nil
+}
+
+// ParseSignedTx parses a signed P-Chain transaction exported by a wallet or
+// a signing device, hex encoded with or without checksum, or raw
+func ParseSignedTx(txBytes []byte) (*txs.Tx, error) {
+	encoded := strings.TrimSpace(string(txBytes))
+	if strings.HasPrefix(encoded, "0x") {
+		var err error
+		txBytes, err = formatting.Decode(formatting.Hex, encoded)
+		if err != nil {
+			txBytes, err = formatting.Decode(formatting.HexNC, encoded)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex encoded transaction: %w", err)
+		}
+	}
+	return txs.Parse(txs.Codec, txBytes)
+}
+
+// TxNetworkID returns the ID of the network tx was built for, false for the
+// transactions only built by the validators
+func TxNetworkID(tx *txs.Tx) (uint32, bool) {
+	switch utx := tx.Unsigned.(type) {
+	case *txs.CreateSubnetTx:
+		return utx.NetworkID, true
+	case *txs.CreateChainTx:
+		return utx.NetworkID, true
+	case *txs.AddSubnetValidatorTx:
+		return utx.NetworkID, true
+	case *txs.AddValidatorTx:
+		return utx.NetworkID, true
+	case *txs.AddDelegatorTx:
+		return utx.NetworkID, true
+	case *txs.ImportTx:
+		return utx.NetworkID, true
+	case *txs.ExportTx:
+		return utx.NetworkID, true
+	}
+	return 0, false
+}
+
+func (b *TxBundle) setTx(tx *txs.Tx) error {
+	unsignedBytes, err := txs.Codec.Marshal(txs.Version, &tx.Unsigned)
+	if err != nil {
+		return err
+	}
+	txBytes, err := txs.Codec.Marshal(txs.Version, tx)
+	if err != nil {
+		return err
+	}
+	tx.Initialize(unsignedBytes, txBytes)
+	b.Tx, err = encodeBytes(txBytes)
+	return err
+}
+
+// Sign adds the signatures of signer to the transaction of the bundle,
+// without reaching the network
+func (b *TxBundle) Sign(ctx context.Context, signer p.Signer) error {
+	tx, err := b.GetTx()
+	if err != nil {
+		return err
+	}
+	if err := signer.Sign(ctx, tx); err != nil {
+		return err
+	}
+	return b.setTx(tx)
+}
+
+// Signatures returns the number of signatures the transaction has, and the
+// number it needs
+func (b *TxBundle) Signatures() (int, int, error) {
+	tx, err := b.GetTx()
+	if err != nil {
+		return 0, 0, err
+	}
+	var (
+		ins        []*avax.TransferableInput
+		subnetAuth verify.Verifiable
+	)
+	switch utx := tx.Unsigned.(type) {
+	case *txs.CreateSubnetTx:
+		ins = utx.Ins
+	case *txs.CreateChainTx:
+		ins, subnetAuth = utx.Ins, utx.SubnetAuth
+	case *txs.AddSubnetValidatorTx:
+		ins, subnetAuth = utx.Ins, utx.SubnetAuth
+	}
+	needed := 0
+	for _, in := range ins {
+		if input, ok := in.In.(*secp256k1fx.TransferInput); ok {
+			needed += len(input.SigIndices)
+		}
+	}
+	if input, ok := subnetAuth.(*secp256k1fx.Input); ok {
+		needed += len(input.SigIndices)
+	}
+	present := 0
+	empty := [crypto.SECP256K1RSigLen]byte{}
+	for _, credIntf := range tx.Creds {
+		cred, ok := credIntf.(*secp256k1fx.Credential)
+		if !ok {
+			continue
+		}
+		for _, sig := range cred.Sigs {
+			if sig != empty {
+				present++
+			}
+		}
+	}
+	return present, needed, nil
+}
+
+// Backend returns a signer backend serving the UTXOs and subnet of the
+// bundle
+func (b *TxBundle) Backend() (p.SignerBackend, error) {
+	backend := &bundleBackend{
+		utxos: map[ids.ID]*avax.UTXO{},
+		txs:   map[ids.ID]*txs.Tx{},
+	}
+	for _, encoded := range b.UTXOs {
+		utxoBytes, err := decodeBytes(encoded)
+		if err != nil {
+			return nil, err
+		}
+		utxo := &avax.UTXO{}
+		if _, err := txs.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+			return nil, fmt.Errorf("invalid UTXO in bundle: %w", err)
+		}
+		backend.utxos[utxo.InputID()] = utxo
+	}
+	if b.SubnetTx != "" {
+		subnetTxBytes, err := decodeBytes(b.SubnetTx)
+		if err != nil {
+			return nil, err
+		}
+		subnetTx, err := txs.Parse(txs.Codec, subnetTxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet transaction in bundle: %w", err)
+		}
+		backend.txs[subnetTx.ID()] = subnetTx
+	}
+	return backend, nil
+}
+
+// bundleBackend serves the UTXOs and transactions of a bundle
+type bundleBackend struct {
+	utxos map[ids.ID]*avax.UTXO
+	txs   map[ids.ID]*txs.Tx
+}
+
+func (b *bundleBackend) GetUTXO(_ context.Context, _, utxoID ids.ID) (*avax.UTXO, error) {
+	utxo, ok := b.utxos[utxoID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return utxo, nil
+}
+
+func (b *bundleBackend) GetTx(_ context.Context, txID ids.ID) (*txs.Tx, error) {
+	tx, ok := b.txs[txID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return tx, nil
+}
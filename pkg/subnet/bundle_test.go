@@ -0,0 +1,122 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxBundleSign(t *testing.T) {
+	assert := assert.New(t)
+
+	payer, err := key.NewSoft(avago_constants.FujiID)
+	assert.NoError(err)
+	controlKey, err := key.NewSoft(avago_constants.FujiID)
+	assert.NoError(err)
+	payerAddr := payer.Key().PublicKey().Address()
+	controlAddr := controlKey.Key().PublicKey().Address()
+
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: ids.GenerateTestID()},
+		Out:    &secp256k1fx.TransferOutput{Amt: 10, OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{payerAddr}}},
+	}
+	subnetTx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{NetworkID: avago_constants.FujiID, BlockchainID: avago_constants.PlatformChainID}},
+		Owner:  &secp256k1fx.OutputOwners{Threshold: 2, Addrs: []ids.ShortID{payerAddr, controlAddr}},
+	}}
+	assert.NoError(subnetTx.Sign(txs.Codec, nil))
+	subnetID := subnetTx.ID()
+	backend := &fakeSignerBackend{
+		utxos: map[ids.ID]*avax.UTXO{utxo.InputID(): utxo},
+		txs:   map[ids.ID]*txs.Tx{subnetID: subnetTx},
+	}
+
+	utx := &txs.CreateChainTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    avago_constants.FujiID,
+			BlockchainID: avago_constants.PlatformChainID,
+			Ins: []*avax.TransferableInput{{
+				UTXOID: utxo.UTXOID,
+				Asset:  utxo.Asset,
+				In:     &secp256k1fx.TransferInput{Amt: 10, Input: secp256k1fx.Input{SigIndices: []uint32{0}}},
+			}},
+		}},
+		SubnetID:   subnetID,
+		ChainName:  "test",
+		SubnetAuth: &secp256k1fx.Input{SigIndices: []uint32{0, 1}},
+	}
+	bundle, err := newTxBundle(context.Background(), BundleCreateChainTx, models.Fuji.String(), "test", utx, utx.Ins, subnetID, backend)
+	assert.NoError(err)
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+	assert.NoError(bundle.Save(bundlePath))
+	bundle, err = LoadTxBundle(bundlePath)
+	assert.NoError(err)
+	present, needed, err := bundle.Signatures()
+	assert.NoError(err)
+	assert.Equal(0, present)
+	assert.Equal(3, needed)
+
+	// each key holder signs in turn, with only the bundle at hand
+	for i, k := range []*key.SoftKey{payer, controlKey} {
+		bundleBackend, err := bundle.Backend()
+		assert.NoError(err)
+		assert.NoError(bundle.Sign(context.Background(), p.NewSigner(k.KeyChain(), bundleBackend)))
+		assert.NoError(bundle.Save(bundlePath))
+		bundle, err = LoadTxBundle(bundlePath)
+		assert.NoError(err)
+		present, _, err = bundle.Signatures()
+		assert.NoError(err)
+		assert.Equal([]int{2, 3}[i], present)
+	}
+
+	// the bundle is checked against its kind
+	bundle.Kind = BundleCreateSubnetTx
+	_, err = bundle.GetTx()
+	assert.ErrorIs(err, errBundleTxMismatch)
+}
+
+func TestParseSignedTx(t *testing.T) {
+	assert := assert.New(t)
+
+	tx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{NetworkID: avago_constants.FujiID, BlockchainID: avago_constants.PlatformChainID}},
+		Owner:  &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID()}},
+	}}
+	assert.NoError(tx.Sign(txs.Codec, nil))
+
+	withChecksum, err := formatting.Encode(formatting.Hex, tx.Bytes())
+	assert.NoError(err)
+	withoutChecksum, err := formatting.Encode(formatting.HexNC, tx.Bytes())
+	assert.NoError(err)
+	for _, txBytes := range [][]byte{tx.Bytes(), []byte(withChecksum + "\n"), []byte(withoutChecksum)} {
+		parsed, err := ParseSignedTx(txBytes)
+		assert.NoError(err)
+		assert.Equal(tx.ID(), parsed.ID())
+		networkID, ok := TxNetworkID(parsed)
+		assert.True(ok)
+		assert.Equal(avago_constants.FujiID, networkID)
+	}
+
+	_, err = ParseSignedTx([]byte("0xnothex"))
+	assert.ErrorContains(err, "invalid hex encoded transaction")
+	_, err = ParseSignedTx([]byte("not a transaction"))
+	assert.Error(err)
+
+	_, ok := TxNetworkID(&txs.Tx{Unsigned: &txs.AdvanceTimeTx{}})
+	assert.False(ok)
+}
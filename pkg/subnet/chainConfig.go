@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// LocalChainConfigs returns the chain configs of the subnets deployed to the
+// local network of the profile, by blockchain ID, for the nodes to pass them
+// to the VMs once the network starts. The nodes only read them on start, so
+// they don't apply to blockchains created on a running network.
+func LocalChainConfigs(app *application.Avalanche) (map[string]string, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	chainConfigs := map[string]string{}
+	for _, name := range names {
+		chainConfig, err := app.LoadChainConfig(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading the chain config of %s: %w", name, err)
+		}
+		if chainConfig == nil {
+			continue
+		}
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading subnet %s: %w", name, err)
+		}
+		network, ok := sc.Networks[app.GetLocalNetworkKey()]
+		if !ok || network.BlockchainID == ids.Empty {
+			continue
+		}
+		chainConfigs[network.BlockchainID.String()] = string(chainConfig)
+	}
+	return chainConfigs, nil
+}
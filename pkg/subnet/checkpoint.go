@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// DeployCheckpoint records the phases completed by a local deploy of a subnet
+// which failed, so that deploying the subnet again resumes after them instead
+// of running the whole deploy again.
+// The network start needs no checkpoint: a running network is not started
+// again anyway.
+type DeployCheckpoint struct {
+	Subnet string `json:"subnet"`
+	// Key identifies what is deployed, the checkpoint only applies to a
+	// deploy of the same genesis, VM and avalanchego
+	Key       string        `json:"key"`
+	Completed []DeployPhase `json:"completed"`
+	// AvalancheGoBinPath and PluginDir are the outcome of PhaseEnvSetup
+	AvalancheGoBinPath string `json:"avalancheGoBinPath,omitempty"`
+	PluginDir          string `json:"pluginDir,omitempty"`
+	// SubnetID is the subnet the blockchain was created in by PhaseChainCreate
+	SubnetID  string    `json:"subnetID,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DeployCheckpointKey returns the key of the checkpoints of the deploys of the
+// given genesis file and VM, with the avalanchego and VM source configured
+func DeployCheckpointKey(app *application.Avalanche, chainGenesis string, vmID string, vmSource string) (string, error) {
+	genesisBytes, err := os.ReadFile(chainGenesis)
+	if err != nil {
+		return "", err
+	}
+	version, _ := app.Conf.AvalancheGoVersion()
+	h := sha256.New()
+	h.Write(genesisBytes)
+	for _, s := range []string{vmID, vmSource, version, app.Conf.AvalancheGoPath()} {
+		h.Write([]byte{0})
+		h.Write([]byte(s))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LoadDeployCheckpoint returns the checkpoint of the last failed deploy of
+// subnetName with the given key, or a new empty one if there is none
+func LoadDeployCheckpoint(app *application.Avalanche, subnetName string, key string) (*DeployCheckpoint, error) {
+	c := &DeployCheckpoint{Subnet: subnetName, Key: key}
+	checkpointBytes, err := os.ReadFile(app.GetDeployCheckpointPath(subnetName))
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recorded DeployCheckpoint
+	if err := json.Unmarshal(checkpointBytes, &recorded); err != nil {
+		return nil, fmt.Errorf("failed reading the deploy checkpoint of %s: %w", subnetName, err)
+	}
+	// what is deployed changed since
+	if recorded.Key != key {
+		return c, nil
+	}
+	return &recorded, nil
+}
+
+// Done returns true if the phase was completed
+func (c *DeployCheckpoint) Done(phase DeployPhase) bool {
+	for _, completed := range c.Completed {
+		if completed == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete records that the phase was completed
+func (c *DeployCheckpoint) Complete(app *application.Avalanche, phase DeployPhase) error {
+	if !c.Done(phase) {
+		c.Completed = append(c.Completed, phase)
+	}
+	return c.save(app)
+}
+
+// Undo records that the phase has to run again, its outcome being gone
+func (c *DeployCheckpoint) Undo(app *application.Avalanche, phase DeployPhase) error {
+	completed := []DeployPhase{}
+	for _, p := range c.Completed {
+		if p != phase {
+			completed = append(completed, p)
+		}
+	}
+	c.Completed = completed
+	return c.save(app)
+}
+
+// String returns the completed phases, e.g. "env setup, plugin install"
+func (c *DeployCheckpoint) String() string {
+	labels := make([]string, 0, len(c.Completed))
+	for _, phase := range c.Completed {
+		labels = append(labels, phaseLabel(phase))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Remove deletes the checkpoint, once the deploy succeeded
+func (c *DeployCheckpoint) Remove(app *application.Avalanche) error {
+	c.Completed = nil
+	if err := os.Remove(app.GetDeployCheckpointPath(c.Subnet)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (c *DeployCheckpoint) save(app *application.Avalanche) error {
+	path := app.GetDeployCheckpointPath(c.Subnet)
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	c.UpdatedAt = time.Now()
+	checkpointBytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, checkpointBytes, WriteReadReadPerms)
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestDeployCheckpoint(t *testing.T) {
+	assert := setupTest(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+	genesisPath := filepath.Join(t.TempDir(), "genesis.json")
+	assert.NoError(os.WriteFile(genesisPath, []byte(`{"config":{"chainId":1}}`), constants.DefaultPerms755))
+	key, err := DeployCheckpointKey(app, genesisPath, testVMID, "")
+	assert.NoError(err)
+
+	c, err := LoadDeployCheckpoint(app, "mySubnet", key)
+	assert.NoError(err)
+	assert.Empty(c.Completed)
+
+	c.AvalancheGoBinPath = "/bin/avalanchego"
+	assert.NoError(c.Complete(app, PhaseEnvSetup))
+	assert.NoError(c.Complete(app, PhaseChainCreate))
+	assert.NoError(c.Undo(app, PhaseChainCreate))
+	c, err = LoadDeployCheckpoint(app, "mySubnet", key)
+	assert.NoError(err)
+	assert.True(c.Done(PhaseEnvSetup))
+	assert.False(c.Done(PhaseChainCreate))
+	assert.Equal("/bin/avalanchego", c.AvalancheGoBinPath)
+	assert.Equal("env setup", c.String())
+
+	// the checkpoint doesn't apply to another genesis or VM source
+	assert.NoError(os.WriteFile(genesisPath, []byte(`{"config":{"chainId":2}}`), constants.DefaultPerms755))
+	otherKey, err := DeployCheckpointKey(app, genesisPath, testVMID, "")
+	assert.NoError(err)
+	assert.NotEqual(key, otherKey)
+	sourceKey, err := DeployCheckpointKey(app, genesisPath, testVMID, "github.com/ava-labs/subnet-evm@main")
+	assert.NoError(err)
+	assert.NotEqual(otherKey, sourceKey)
+	c, err = LoadDeployCheckpoint(app, "mySubnet", otherKey)
+	assert.NoError(err)
+	assert.Empty(c.Completed)
+
+	assert.NoError(c.Remove(app))
+	assert.NoFileExists(app.GetDeployCheckpointPath("mySubnet"))
+	// removing it again is fine
+	assert.NoError(c.Remove(app))
+}
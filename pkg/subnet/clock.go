@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// NetworkClock tells the time of a network, which the start times of its
+// validators are checked against, from the local clock and how far it is
+// off
+type NetworkClock struct {
+	// Skew is how far the local clock is ahead of the network, negative if
+	// behind
+	Skew time.Duration
+}
+
+// Now returns the current time of the network
+func (c NetworkClock) Now() time.Time {
+	return time.Now().Add(-c.Skew)
+}
+
+// Skewed returns true if the local clock is off by more than
+// constants.ClockSkewTolerance
+func (c NetworkClock) Skewed() bool {
+	return c.Skew > constants.ClockSkewTolerance || c.Skew < -constants.ClockSkewTolerance
+}
+
+// Describe tells how far off the local clock is, e.g. "2m5s behind"
+func (c NetworkClock) Describe() string {
+	if c.Skew < 0 {
+		return (-c.Skew).Round(time.Second).String() + " behind"
+	}
+	return c.Skew.Round(time.Second).String() + " ahead of"
+}
+
+// MeasureClockSkew returns how far the local clock is ahead of the one of the
+// API endpoint api, from the Date header of its response, taken as sent
+// halfway through the request. The header has a resolution of a second.
+func MeasureClockSkew(ctx context.Context, api string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, constants.EndpointCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(api, "/")+"/ext/health", nil)
+	if err != nil {
+		return 0, err
+	}
+	sent := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("API endpoint %s is not reachable: %w", api, err)
+	}
+	received := time.Now()
+	resp.Body.Close()
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("API endpoint %s doesn't tell its time: %w", api, err)
+	}
+	local := sent.Add(received.Sub(sent) / 2)
+	// the date is truncated to the second
+	return local.Sub(date.Add(time.Second / 2)).Round(time.Second), nil
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newClockServer(offset time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestMeasureClockSkew(t *testing.T) {
+	assert := setupTest(t)
+
+	// the network is 2 minutes ahead, the local clock behind
+	ahead := newClockServer(2 * time.Minute)
+	defer ahead.Close()
+	skew, err := MeasureClockSkew(context.Background(), ahead.URL)
+	assert.NoError(err)
+	assert.InDelta((-2 * time.Minute).Seconds(), skew.Seconds(), 1)
+	clock := NetworkClock{Skew: skew}
+	assert.True(clock.Skewed())
+	assert.Contains(clock.Describe(), "behind")
+	assert.WithinDuration(time.Now().Add(2*time.Minute), clock.Now(), 2*time.Second)
+
+	synced := newClockServer(0)
+	defer synced.Close()
+	skew, err = MeasureClockSkew(context.Background(), synced.URL)
+	assert.NoError(err)
+	assert.False(NetworkClock{Skew: skew}.Skewed())
+
+	synced.Close()
+	_, err = MeasureClockSkew(context.Background(), synced.URL)
+	assert.Error(err)
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// CloneSidecar returns the sidecar of a local copy, named name, of the
+// blockchain blockchainID created by createChainTx on network. The original
+// deployment is recorded under network, so that the copy can be verified
+// against it.
+//
+// Local networks always derive the VM ID from the VM name, so the on-chain VM
+// ID is kept only if it was derived from the on-chain chain name, as for
+// blockchains deployed with the CLI. The returned bool tells if it was kept.
+func CloneSidecar(
+	name string,
+	vmType models.VMType,
+	network models.Network,
+	blockchainID ids.ID,
+	createChainTx *txs.CreateChainTx,
+) (*models.Sidecar, bool) {
+	sc := &models.Sidecar{
+		Name:   name,
+		VM:     vmType,
+		Subnet: name,
+		Networks: map[string]models.NetworkData{
+			network.String(): {
+				SubnetID:     createChainTx.SubnetID,
+				BlockchainID: blockchainID,
+			},
+		},
+	}
+	chainName := createChainTx.ChainName
+	derivedVMID, err := utils.VMID(chainName)
+	if err != nil || derivedVMID != createChainTx.VMID {
+		return sc, false
+	}
+	if chainName != name {
+		sc.VMAlias = chainName
+	}
+	return sc, true
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneSidecar(t *testing.T) {
+	assert := assert.New(t)
+
+	blockchainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	vmID, err := ids.FromString(testVMID)
+	assert.NoError(err)
+	tx := &txs.CreateChainTx{
+		SubnetID:  subnetID,
+		ChainName: testVMName,
+		VMID:      vmID,
+	}
+
+	sc, keptVMID := CloneSidecar("mychain", models.SubnetEvm, models.Fuji, blockchainID, tx)
+	assert.True(keptVMID)
+	assert.Equal("mychain", sc.Name)
+	assert.Equal(testVMName, sc.GetVMName())
+	scVMID, err := sc.GetVMID()
+	assert.NoError(err)
+	assert.Equal(vmID, scVMID)
+	assert.Equal(models.NetworkData{SubnetID: subnetID, BlockchainID: blockchainID}, sc.Networks[models.Fuji.String()])
+
+	sc, keptVMID = CloneSidecar(testVMName, models.SubnetEvm, models.Fuji, blockchainID, tx)
+	assert.True(keptVMID)
+	assert.Empty(sc.VMAlias)
+
+	// a VM ID not derived from the chain name can't be used locally
+	tx.VMID = ids.GenerateTestID()
+	sc, keptVMID = CloneSidecar("mychain", models.CustomVM, models.Fuji, blockchainID, tx)
+	assert.False(keptVMID)
+	assert.Equal("mychain", sc.GetVMName())
+	assert.Empty(sc.VMID)
+}
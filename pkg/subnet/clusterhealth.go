@@ -0,0 +1,266 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// Liveness is the liveness category of the local network, of one of its
+// nodes or of one of its blockchains, from the best to the worst
+type Liveness string
+
+const (
+	// Live is healthy
+	Live Liveness = "live"
+	// Starting is not healthy yet, such as a node bootstrapping or a
+	// blockchain being created
+	Starting Liveness = "starting"
+	// Unhealthy is failing health checks
+	Unhealthy Liveness = "unhealthy"
+	// Unreachable is a node whose health API doesn't answer
+	Unreachable Liveness = "unreachable"
+	// Down is a network which is not running
+	Down Liveness = "down"
+)
+
+// livenessRank orders the liveness categories, the worst being the highest
+var livenessRank = map[Liveness]int{
+	Live:        0,
+	Starting:    1,
+	Unhealthy:   2,
+	Unreachable: 3,
+	Down:        4,
+}
+
+// worse returns the worst of a and b
+func worse(a Liveness, b Liveness) Liveness {
+	if livenessRank[b] > livenessRank[a] {
+		return b
+	}
+	return a
+}
+
+// bootstrappedCheck is the health check of a node which passes once all its
+// chains are bootstrapped
+const bootstrappedCheck = "bootstrapped"
+
+// NodeHealthClient is the part of the health API needed to check the health
+// of a node, which health.Client implements
+type NodeHealthClient interface {
+	Health(ctx context.Context, options ...rpc.Option) (*health.APIHealthReply, error)
+}
+
+// NewNodeHealthClient returns the client of the health API of the node at uri
+func NewNodeHealthClient(uri string) NodeHealthClient {
+	return health.NewClient(uri)
+}
+
+// NodeHealth is the health of a node of the local network
+type NodeHealth struct {
+	Name     string
+	URI      string
+	Liveness Liveness
+	// Reason tells why the node is not live, empty if it is
+	Reason string
+}
+
+// VMHealth is the health of a blockchain of the local network, on all its
+// nodes
+type VMHealth struct {
+	BlockchainID string
+	VMID         string
+	VMName       string
+	SubnetID     string
+	Liveness     Liveness
+	// Reason tells why the blockchain is not live, empty if it is
+	Reason string
+}
+
+// ClusterHealth is the health of the local network, of its nodes and of its
+// blockchains, as reported by the network runner and, once checked with
+// CheckClusterHealth, by the health API of each node
+type ClusterHealth struct {
+	Liveness Liveness
+	// Reason tells why the network is not live, empty if it is
+	Reason string
+	// Nodes are sorted by name
+	Nodes []NodeHealth
+	// VMs are sorted by blockchain ID
+	VMs []VMHealth
+}
+
+// Live returns true if the network and all its blockchains are healthy
+func (h ClusterHealth) Live() bool {
+	return h.Liveness == Live
+}
+
+// NewClusterHealth interprets the health clusterInfo reports, for the whole
+// network: the nodes are live or starting along with the network, and the
+// blockchains along with the custom VMs. clusterInfo is nil if the network is
+// not running.
+func NewClusterHealth(clusterInfo *rpcpb.ClusterInfo) ClusterHealth {
+	if clusterInfo == nil {
+		return ClusterHealth{Liveness: Down, Reason: "the local network is not running"}
+	}
+	h := ClusterHealth{Liveness: Live}
+	nodesLiveness, nodesReason := Live, ""
+	if !clusterInfo.Healthy {
+		nodesLiveness, nodesReason = Starting, "the nodes are not healthy yet"
+		h.Liveness, h.Reason = nodesLiveness, nodesReason
+	}
+	vmsLiveness, vmsReason := Live, ""
+	if !clusterInfo.CustomVmsHealthy {
+		vmsLiveness, vmsReason = Starting, "the custom VMs are not healthy yet"
+		if h.Liveness == Live {
+			h.Liveness, h.Reason = vmsLiveness, vmsReason
+		}
+	}
+
+	for _, name := range sortedNodeNames(clusterInfo) {
+		h.Nodes = append(h.Nodes, NodeHealth{
+			Name:     name,
+			URI:      ux.HostURI(clusterInfo.NodeInfos[name].GetUri()),
+			Liveness: nodesLiveness,
+			Reason:   nodesReason,
+		})
+	}
+	for blockchainID, vmInfo := range clusterInfo.CustomVms {
+		h.VMs = append(h.VMs, VMHealth{
+			BlockchainID: blockchainID,
+			VMID:         vmInfo.VmId,
+			VMName:       vmInfo.VmName,
+			SubnetID:     vmInfo.SubnetId,
+			Liveness:     vmsLiveness,
+			Reason:       vmsReason,
+		})
+	}
+	sort.Slice(h.VMs, func(i, j int) bool {
+		return h.VMs[i].BlockchainID < h.VMs[j].BlockchainID
+	})
+	return h
+}
+
+// CheckClusterHealth returns the health of the network of clusterInfo, with
+// the health of each node and of each blockchain on it from the health API
+// of every node, queried with nodeHealth. A blockchain is live once it is on
+// all the reachable nodes and passes its health check there.
+func CheckClusterHealth(
+	ctx context.Context,
+	clusterInfo *rpcpb.ClusterInfo,
+	nodeHealth func(uri string) NodeHealthClient,
+) ClusterHealth {
+	h := NewClusterHealth(clusterInfo)
+	if clusterInfo == nil {
+		return h
+	}
+	replies := make([]*health.APIHealthReply, len(h.Nodes))
+	errs := make([]error, len(h.Nodes))
+	var wg sync.WaitGroup
+	for i := range h.Nodes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, constants.NodeInfoTimeout)
+			defer cancel()
+			replies[i], errs[i] = nodeHealth(h.Nodes[i].URI).Health(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	h.Liveness, h.Reason = Live, ""
+	for i := range h.Nodes {
+		node := &h.Nodes[i]
+		node.Liveness, node.Reason = nodeLiveness(replies[i], errs[i])
+		h.worsen(node.Liveness, fmt.Sprintf("%s is %s: %s", node.Name, node.Liveness, node.Reason))
+	}
+	for i := range h.VMs {
+		vm := &h.VMs[i]
+		vm.Liveness, vm.Reason = Live, ""
+		reasons := []string{}
+		for j, node := range h.Nodes {
+			if node.Liveness == Unreachable {
+				continue
+			}
+			liveness, reason := vmLiveness(replies[j], vm.BlockchainID)
+			if liveness == Live {
+				continue
+			}
+			vm.Liveness = worse(vm.Liveness, liveness)
+			reasons = append(reasons, fmt.Sprintf("%s on %s", reason, node.Name))
+		}
+		vm.Reason = strings.Join(reasons, ", ")
+		name := vm.VMName
+		if name == "" {
+			name = vm.BlockchainID
+		}
+		h.worsen(vm.Liveness, fmt.Sprintf("blockchain %s is %s: %s", name, vm.Liveness, vm.Reason))
+	}
+	return h
+}
+
+// worsen sets the liveness of the network to liveness, for reason, if it is
+// worse than the current one
+func (h *ClusterHealth) worsen(liveness Liveness, reason string) {
+	if livenessRank[liveness] > livenessRank[h.Liveness] {
+		h.Liveness, h.Reason = liveness, reason
+	}
+}
+
+// nodeLiveness interprets the health reply of a node, or the error querying
+// it
+func nodeLiveness(reply *health.APIHealthReply, err error) (Liveness, string) {
+	if err != nil {
+		return Unreachable, err.Error()
+	}
+	if reply.Healthy {
+		return Live, ""
+	}
+	if check, ok := reply.Checks[bootstrappedCheck]; ok && check.Error != nil {
+		return Starting, "bootstrapping"
+	}
+	failing := []string{}
+	for name, check := range reply.Checks {
+		if check.Error != nil {
+			failing = append(failing, fmt.Sprintf("%s (%s)", name, *check.Error))
+		}
+	}
+	sort.Strings(failing)
+	return Unhealthy, "failing " + strings.Join(failing, ", ")
+}
+
+// vmLiveness interprets the health check of the blockchain of blockchainID
+// in the health reply of a node, named after the ID of the blockchain
+func vmLiveness(reply *health.APIHealthReply, blockchainID string) (Liveness, string) {
+	check, ok := reply.Checks[blockchainID]
+	if !ok {
+		return Starting, "not created yet"
+	}
+	if check.Error != nil {
+		if reply.Checks[bootstrappedCheck].Error != nil {
+			return Starting, "bootstrapping"
+		}
+		return Unhealthy, *check.Error
+	}
+	return Live, ""
+}
+
+// sortedNodeNames returns the names of the nodes of clusterInfo, sorted
+func sortedNodeNames(clusterInfo *rpcpb.ClusterInfo) []string {
+	names := make([]string, 0, len(clusterInfo.GetNodeInfos()))
+	for name := range clusterInfo.GetNodeInfos() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeNodeHealth struct {
+	reply *health.APIHealthReply
+	err   error
+}
+
+func (f fakeNodeHealth) Health(context.Context, ...rpc.Option) (*health.APIHealthReply, error) {
+	return f.reply, f.err
+}
+
+func failing(msg string) health.Result {
+	return health.Result{Error: &msg}
+}
+
+func TestNewClusterHealth(t *testing.T) {
+	assert := setupTest(t)
+
+	h := NewClusterHealth(nil)
+	assert.Equal(Down, h.Liveness)
+	assert.False(h.Live())
+
+	clusterInfo := proto.Clone(fakeHealthResponse.ClusterInfo).(*rpcpb.ClusterInfo)
+	h = NewClusterHealth(clusterInfo)
+	assert.True(h.Live())
+	assert.Len(h.Nodes, 2)
+	assert.Equal("testNode1", h.Nodes[0].Name)
+	assert.Len(h.VMs, 2)
+
+	clusterInfo.CustomVmsHealthy = false
+	h = NewClusterHealth(clusterInfo)
+	assert.Equal(Starting, h.Liveness)
+	assert.Equal("the custom VMs are not healthy yet", h.Reason)
+	assert.Equal(Live, h.Nodes[0].Liveness)
+	assert.Equal(Starting, h.VMs[0].Liveness)
+
+	clusterInfo.Healthy = false
+	h = NewClusterHealth(clusterInfo)
+	assert.Equal("the nodes are not healthy yet", h.Reason)
+	assert.Equal(Starting, h.Nodes[1].Liveness)
+}
+
+func TestCheckClusterHealth(t *testing.T) {
+	assert := setupTest(t)
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		Healthy:          true,
+		CustomVmsHealthy: true,
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", Uri: "http://node1"},
+			"node2": {Name: "node2", Uri: "http://node2"},
+			"node3": {Name: "node3", Uri: "http://node3"},
+		},
+		CustomVms: map[string]*rpcpb.CustomVmInfo{
+			"chain1": {BlockchainId: "chain1", VmName: "first"},
+			"chain2": {BlockchainId: "chain2", VmName: "second"},
+		},
+	}
+	replies := map[string]NodeHealthClient{
+		"http://node1": fakeNodeHealth{reply: &health.APIHealthReply{
+			Healthy: true,
+			Checks:  map[string]health.Result{"bootstrapped": {}, "chain1": {}, "chain2": {}},
+		}},
+		"http://node2": fakeNodeHealth{reply: &health.APIHealthReply{
+			Checks: map[string]health.Result{"bootstrapped": {}, "chain1": {}, "chain2": failing("no blocks accepted")},
+		}},
+		"http://node3": fakeNodeHealth{err: errors.New("connection refused")},
+	}
+	h := CheckClusterHealth(context.Background(), clusterInfo, func(uri string) NodeHealthClient {
+		return replies[uri]
+	})
+
+	assert.Equal(Unreachable, h.Liveness)
+	assert.Equal("node3 is unreachable: connection refused", h.Reason)
+	assert.Equal(Live, h.Nodes[0].Liveness)
+	assert.Equal(Unhealthy, h.Nodes[1].Liveness)
+	assert.Equal("failing chain2 (no blocks accepted)", h.Nodes[1].Reason)
+	// the blockchains are not checked on the unreachable nodes
+	assert.Equal(VMHealth{BlockchainID: "chain1", VMName: "first", Liveness: Live}, h.VMs[0])
+	assert.Equal(Unhealthy, h.VMs[1].Liveness)
+	assert.Equal("no blocks accepted on node2", h.VMs[1].Reason)
+
+	// a node bootstrapping is starting, along with its blockchains
+	replies["http://node2"] = fakeNodeHealth{reply: &health.APIHealthReply{
+		Checks: map[string]health.Result{"bootstrapped": failing("not bootstrapped"), "chain2": failing("not bootstrapped")},
+	}}
+	delete(replies, "http://node3")
+	delete(clusterInfo.NodeInfos, "node3")
+	h = CheckClusterHealth(context.Background(), clusterInfo, func(uri string) NodeHealthClient {
+		return replies[uri]
+	})
+	assert.Equal(Starting, h.Liveness)
+	assert.Equal("node2 is starting: bootstrapping", h.Reason)
+	assert.Equal("not created yet on node2", h.VMs[0].Reason)
+	assert.Equal("bootstrapping on node2", h.VMs[1].Reason)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+)
+
+const (
+	// how many times a transaction is issued before giving up on UTXO
+	// conflicts
+	txIssueAttempts = 3
+	// how long to wait for the conflicting transaction to be accepted before
+	// refreshing the wallet
+	txIssueRetryBackoff = 2 * time.Second
+)
+
+var (
+	ErrUTXOConflict = errors.New("the funds of the key were spent by another transaction")
+
+	// errors returned by the P-Chain when a transaction spends UTXOs which
+	// are already consumed, or about to be by a transaction in the mempool
+	utxoConflictErrors = []string{
+		"failed to read consumed UTXO",
+		"conflicting transaction",
+		"conflicts with a transaction",
+	}
+)
+
+// isUTXOConflict returns true if err is the P-Chain refusing a transaction
+// because of UTXOs spent by another one. Errors only come back from the API
+// as text, so they are matched on their message.
+func isUTXOConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, conflict := range utxoConflictErrors {
+		if strings.Contains(msg, conflict) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryOnUTXOConflict calls issue up to attempts times, as long as it fails
+// on UTXO conflicts, calling refresh to get the current UTXOs in between
+func retryOnUTXOConflict(
+	attempts int,
+	backoff time.Duration,
+	issue func() (ids.ID, error),
+	refresh func() error,
+) (ids.ID, error) {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var id ids.ID
+		id, err = issue()
+		if !isUTXOConflict(err) {
+			return id, err
+		}
+		if attempt == attempts {
+			break
+		}
+		ux.Logger.PrintToUser("The funds of the key were spent by another transaction, refreshing them and retrying (%d/%d)...", attempt+1, attempts)
+		time.Sleep(backoff)
+		if err := refresh(); err != nil {
+			return ids.Empty, fmt.Errorf("failed refreshing the wallet: %w", err)
+		}
+	}
+	return ids.Empty, fmt.Errorf(
+		"%w, even after %d attempts. Is another command using the same key? Wait for it to complete and try again: %s",
+		ErrUTXOConflict, attempts, err,
+	)
+}
+
+// issueTx issues a transaction with wallet and waits for it to be committed,
+// refreshing the wallet and trying again if its UTXOs are spent meanwhile,
+// e.g. by another invocation sharing the key. preloadTxs are the
+// transactions the refreshed wallet needs to know. issue must not wait for
+// the transaction to be decided.
+func (d *PublicDeployer) issueTx(
+	wallet *primary.Wallet,
+	preloadTxs []ids.ID,
+	issue func(primary.Wallet) (ids.ID, error),
+) (ids.ID, error) {
+	if err := d.app.CheckWritable("issue transactions"); err != nil {
+		return ids.Empty, err
+	}
+	return retryOnUTXOConflict(
+		txIssueAttempts,
+		txIssueRetryBackoff,
+		func() (ids.ID, error) {
+			id, err := issue(*wallet)
+			if err != nil {
+				return ids.Empty, err
+			}
+			// a transaction dropped for a UTXO conflict is retried as well
+			if err := d.waitForTx(id); err != nil {
+				return id, err
+			}
+			return id, nil
+		},
+		func() error {
+			refreshed, _, err := d.loadWallet(preloadTxs...)
+			if err != nil {
+				return err
+			}
+			d.app.Log.Info("wallet refreshed after a UTXO conflict")
+			*wallet = refreshed
+			return nil
+		},
+	)
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsUTXOConflict(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isUTXOConflict(errors.New("failed to read consumed UTXO 2Q8... due to: not found")))
+	assert.True(isUTXOConflict(errors.New("couldn't issue tx: conflicting transaction")))
+	assert.False(isUTXOConflict(errors.New("insufficient funds")))
+	assert.False(isUTXOConflict(nil))
+}
+
+func TestRetryOnUTXOConflict(t *testing.T) {
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	conflict := errors.New("failed to read consumed UTXO")
+	txID := ids.GenerateTestID()
+
+	// succeeds once refreshed
+	issued, refreshed := 0, 0
+	id, err := retryOnUTXOConflict(3, 0, func() (ids.ID, error) {
+		issued++
+		if refreshed == 0 {
+			return ids.Empty, conflict
+		}
+		return txID, nil
+	}, func() error {
+		refreshed++
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(txID, id)
+	assert.Equal(2, issued)
+	assert.Equal(1, refreshed)
+
+	// other errors are not retried
+	issued = 0
+	_, err = retryOnUTXOConflict(3, 0, func() (ids.ID, error) {
+		issued++
+		return ids.Empty, errors.New("insufficient funds")
+	}, func() error { return nil })
+	assert.EqualError(err, "insufficient funds")
+	assert.Equal(1, issued)
+
+	// gives up after the last attempt
+	issued, refreshed = 0, 0
+	_, err = retryOnUTXOConflict(3, 0, func() (ids.ID, error) {
+		issued++
+		return ids.Empty, conflict
+	}, func() error {
+		refreshed++
+		return nil
+	})
+	assert.ErrorIs(err, ErrUTXOConflict)
+	assert.Equal(3, issued)
+	assert.Equal(2, refreshed)
+}
@@ -0,0 +1,404 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ava-labs/coreth/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// feeHistoryBlocks and feeHistoryPercentiles are asked for in eth_feeHistory
+const feeHistoryBlocks = 4
+
+var (
+	feeHistoryPercentiles = []float64{25, 75}
+
+	quantityRegex = regexp.MustCompile(`^0x(0|[1-9a-f][0-9a-f]*)$`)
+	dataRegex     = regexp.MustCompile(`^0x([0-9a-f]{2})*$`)
+)
+
+// ConformanceResult is the outcome of a JSON-RPC conformance check of a chain
+type ConformanceResult struct {
+	// Check names what is checked, usually the method called
+	Check string
+	// Deviation is how the chain deviates from the Ethereum JSON-RPC
+	// specification, nil if it conforms
+	Deviation error
+	// Skipped tells why the check couldn't run, if it didn't
+	Skipped string
+}
+
+// conformanceRun holds what the checks of a chain share
+type conformanceRun struct {
+	client  *rpc.Client
+	chainID *big.Int
+	// latest is the latest block, as returned by eth_getBlockByNumber
+	latest map[string]json.RawMessage
+}
+
+type conformanceCheck struct {
+	name string
+	run  func(ctx context.Context, r *conformanceRun) error
+}
+
+// errSkipped is returned by a check which can't run, wrapped with why
+var errSkipped = errors.New("skipped")
+
+var conformanceChecks = []conformanceCheck{
+	{"eth_chainId", checkChainID},
+	{"net_version", checkNetVersion},
+	{"web3_clientVersion", func(ctx context.Context, r *conformanceRun) error {
+		var version string
+		if err := r.client.CallContext(ctx, &version, "web3_clientVersion"); err != nil {
+			return err
+		}
+		if version == "" {
+			return errors.New("returned an empty version")
+		}
+		return nil
+	}},
+	{"eth_syncing", func(ctx context.Context, r *conformanceRun) error {
+		raw, err := call(ctx, r, "eth_syncing")
+		if err != nil {
+			return err
+		}
+		if string(raw) != "false" && !strings.HasPrefix(string(raw), "{") {
+			return fmt.Errorf("returned %s, expected false or a sync status", raw)
+		}
+		return nil
+	}},
+	{"eth_blockNumber", func(ctx context.Context, r *conformanceRun) error {
+		return callQuantity(ctx, r, "eth_blockNumber")
+	}},
+	{"eth_getBlockByNumber", checkLatestBlock},
+	{"eth_getBlockByNumber(0x0)", func(ctx context.Context, r *conformanceRun) error {
+		block, err := getBlock(ctx, r, "eth_getBlockByNumber", "0x0")
+		if err != nil {
+			return err
+		}
+		if number := unquote(block["number"]); number != "0x0" {
+			return fmt.Errorf("returned block %s for the genesis block", number)
+		}
+		return nil
+	}},
+	{"eth_getBlockByHash", func(ctx context.Context, r *conformanceRun) error {
+		if r.latest == nil {
+			return fmt.Errorf("%w: no latest block", errSkipped)
+		}
+		hash := unquote(r.latest["hash"])
+		block, err := getBlock(ctx, r, "eth_getBlockByHash", hash)
+		if err != nil {
+			return err
+		}
+		if got := unquote(block["hash"]); got != hash {
+			return fmt.Errorf("returned block %s for hash %s", got, hash)
+		}
+		return nil
+	}},
+	{"eth_gasPrice", func(ctx context.Context, r *conformanceRun) error {
+		return callQuantity(ctx, r, "eth_gasPrice")
+	}},
+	{"eth_maxPriorityFeePerGas", func(ctx context.Context, r *conformanceRun) error {
+		return callQuantity(ctx, r, "eth_maxPriorityFeePerGas")
+	}},
+	{"eth_feeHistory", checkFeeHistory},
+	{"eth_getBalance", func(ctx context.Context, r *conformanceRun) error {
+		return callQuantity(ctx, r, "eth_getBalance", common.Address{}, "latest")
+	}},
+	{"eth_getTransactionCount", func(ctx context.Context, r *conformanceRun) error {
+		return callQuantity(ctx, r, "eth_getTransactionCount", common.Address{}, "latest")
+	}},
+	{"eth_getCode", func(ctx context.Context, r *conformanceRun) error {
+		return callData(ctx, r, "eth_getCode", common.Address{}, "latest")
+	}},
+	{"eth_getStorageAt", func(ctx context.Context, r *conformanceRun) error {
+		raw, err := call(ctx, r, "eth_getStorageAt", common.Address{}, "0x0", "latest")
+		if err != nil {
+			return err
+		}
+		if s := unquote(raw); !dataRegex.MatchString(s) || len(s) != 2+2*common.HashLength {
+			return fmt.Errorf("returned %s, expected a 32 bytes slot", raw)
+		}
+		return nil
+	}},
+	{"eth_call", func(ctx context.Context, r *conformanceRun) error {
+		return callData(ctx, r, "eth_call", map[string]interface{}{"to": common.Address{}, "data": "0x"}, "latest")
+	}},
+	{"eth_estimateGas", func(ctx context.Context, r *conformanceRun) error {
+		raw, err := call(ctx, r, "eth_estimateGas", map[string]interface{}{"from": common.Address{}, "to": common.Address{}, "value": "0x0"})
+		if err != nil {
+			return err
+		}
+		gas, err := hexutil.DecodeUint64(unquote(raw))
+		if err != nil {
+			return fmt.Errorf("returned %s, expected a quantity", raw)
+		}
+		if gas != transferGas {
+			return fmt.Errorf("estimated %d gas for a plain transfer, expected %d", gas, transferGas)
+		}
+		return nil
+	}},
+	{"eth_getLogs", func(ctx context.Context, r *conformanceRun) error {
+		raw, err := call(ctx, r, "eth_getLogs", map[string]interface{}{"fromBlock": "latest", "toBlock": "latest"})
+		if err != nil {
+			return err
+		}
+		var logs []json.RawMessage
+		if err := json.Unmarshal(raw, &logs); err != nil {
+			return fmt.Errorf("returned %s, expected a list of logs", raw)
+		}
+		return nil
+	}},
+	{"eth_getTransactionByHash", func(ctx context.Context, r *conformanceRun) error {
+		return callNull(ctx, r, "eth_getTransactionByHash", common.Hash{})
+	}},
+	{"eth_getTransactionReceipt", func(ctx context.Context, r *conformanceRun) error {
+		return callNull(ctx, r, "eth_getTransactionReceipt", common.Hash{})
+	}},
+}
+
+// subscriptionKinds are the eth_subscribe subscriptions checked on the
+// WebSocket endpoint
+var subscriptionKinds = []struct {
+	name string
+	args []interface{}
+}{
+	{"newHeads", nil},
+	{"logs", []interface{}{map[string]interface{}{}}},
+	{"newPendingTransactions", nil},
+}
+
+// RunConformance checks the JSON-RPC API of an EVM chain, at rpcURL, against
+// the Ethereum JSON-RPC specification: the methods of the eth namespace
+// wallets and tools rely on, eth_feeHistory, and the subscriptions of the
+// WebSocket endpoint at wsURL. Calls only read the chain. The chain ID is
+// checked if chainID is not nil, the WebSocket endpoint if wsURL is set.
+func RunConformance(ctx context.Context, rpcURL string, wsURL string, chainID *big.Int) ([]ConformanceResult, error) {
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed connecting to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+
+	r := &conformanceRun{client: client, chainID: chainID}
+	results := make([]ConformanceResult, 0, len(conformanceChecks)+len(subscriptionKinds))
+	for _, check := range conformanceChecks {
+		results = append(results, newConformanceResult(check.name, check.run(ctx, r)))
+	}
+	for _, kind := range subscriptionKinds {
+		name := "eth_subscribe(" + kind.name + ")"
+		if wsURL == "" {
+			results = append(results, ConformanceResult{Check: name, Skipped: "no WebSocket endpoint"})
+			continue
+		}
+		results = append(results, newConformanceResult(name, checkSubscription(ctx, wsURL, kind.name, kind.args)))
+	}
+	return results, nil
+}
+
+func newConformanceResult(check string, err error) ConformanceResult {
+	if errors.Is(err, errSkipped) {
+		return ConformanceResult{Check: check, Skipped: strings.TrimPrefix(err.Error(), errSkipped.Error()+": ")}
+	}
+	return ConformanceResult{Check: check, Deviation: err}
+}
+
+// WSURLFromRPC returns the WebSocket endpoint of a chain given its RPC
+// endpoint on an avalanchego node, or an empty string if it isn't one
+func WSURLFromRPC(rpcURL string) string {
+	if !strings.HasSuffix(rpcURL, "/rpc") || !strings.HasPrefix(rpcURL, "http") {
+		return ""
+	}
+	return "ws" + strings.TrimPrefix(strings.TrimSuffix(rpcURL, "/rpc"), "http") + "/ws"
+}
+
+func call(ctx context.Context, r *conformanceRun, method string, args ...interface{}) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := r.client.CallContext(ctx, &raw, method, args...); err != nil {
+		return nil, err
+	}
+	// the client leaves the result of a null response unset
+	if len(raw) == 0 {
+		raw = json.RawMessage("null")
+	}
+	return raw, nil
+}
+
+func callQuantity(ctx context.Context, r *conformanceRun, method string, args ...interface{}) error {
+	raw, err := call(ctx, r, method, args...)
+	if err != nil {
+		return err
+	}
+	if !quantityRegex.MatchString(unquote(raw)) {
+		return fmt.Errorf("returned %s, expected a quantity", raw)
+	}
+	return nil
+}
+
+func callData(ctx context.Context, r *conformanceRun, method string, args ...interface{}) error {
+	raw, err := call(ctx, r, method, args...)
+	if err != nil {
+		return err
+	}
+	if !dataRegex.MatchString(unquote(raw)) {
+		return fmt.Errorf("returned %s, expected data", raw)
+	}
+	return nil
+}
+
+// callNull checks a lookup of something which doesn't exist returns null,
+// not an error
+func callNull(ctx context.Context, r *conformanceRun, method string, args ...interface{}) error {
+	raw, err := call(ctx, r, method, args...)
+	if err != nil {
+		return fmt.Errorf("failed instead of returning null for an unknown hash: %w", err)
+	}
+	if string(raw) != "null" {
+		return fmt.Errorf("returned %s for an unknown hash, expected null", raw)
+	}
+	return nil
+}
+
+// unquote returns the JSON string raw, or raw if it isn't a string
+func unquote(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return string(raw)
+	}
+	return s
+}
+
+func checkChainID(ctx context.Context, r *conformanceRun) error {
+	raw, err := call(ctx, r, "eth_chainId")
+	if err != nil {
+		return err
+	}
+	chainID, err := hexutil.DecodeBig(unquote(raw))
+	if err != nil {
+		return fmt.Errorf("returned %s, expected a quantity", raw)
+	}
+	if r.chainID != nil && chainID.Cmp(r.chainID) != 0 {
+		return fmt.Errorf("returned %s, expected the chain ID of the genesis %s", chainID, r.chainID)
+	}
+	return nil
+}
+
+func checkNetVersion(ctx context.Context, r *conformanceRun) error {
+	var version string
+	if err := r.client.CallContext(ctx, &version, "net_version"); err != nil {
+		return err
+	}
+	if _, ok := new(big.Int).SetString(version, 10); !ok {
+		return fmt.Errorf("returned %q, expected a decimal network ID", version)
+	}
+	return nil
+}
+
+// blockFields are the fields of a block object every client returns
+var blockFields = []string{
+	"number", "hash", "parentHash", "timestamp", "gasLimit", "gasUsed",
+	"miner", "stateRoot", "transactionsRoot", "receiptsRoot", "logsBloom", "transactions",
+}
+
+func getBlock(ctx context.Context, r *conformanceRun, method string, id string) (map[string]json.RawMessage, error) {
+	raw, err := call(ctx, r, method, id, false)
+	if err != nil {
+		return nil, err
+	}
+	var block map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &block); err != nil || block == nil {
+		return nil, fmt.Errorf("returned %s, expected a block", raw)
+	}
+	missing := []string{}
+	for _, field := range blockFields {
+		if _, ok := block[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("returned a block without %s", strings.Join(missing, ", "))
+	}
+	return block, nil
+}
+
+func checkLatestBlock(ctx context.Context, r *conformanceRun) error {
+	block, err := getBlock(ctx, r, "eth_getBlockByNumber", "latest")
+	if err != nil {
+		return err
+	}
+	r.latest = block
+	if _, ok := block["baseFeePerGas"]; !ok {
+		return errors.New("returned a block without baseFeePerGas, EIP-1559 wallets can't price their transactions")
+	}
+	return nil
+}
+
+func checkFeeHistory(ctx context.Context, r *conformanceRun) error {
+	raw, err := call(ctx, r, "eth_feeHistory", hexutil.Uint64(feeHistoryBlocks), "latest", feeHistoryPercentiles)
+	if err != nil {
+		return err
+	}
+	var history struct {
+		OldestBlock   *hexutil.Big     `json:"oldestBlock"`
+		BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio  []float64        `json:"gasUsedRatio"`
+		Reward        [][]*hexutil.Big `json:"reward"`
+	}
+	if err := json.Unmarshal(raw, &history); err != nil {
+		return fmt.Errorf("returned %s, expected a fee history: %w", raw, err)
+	}
+	if history.OldestBlock == nil {
+		return errors.New("returned a fee history without oldestBlock")
+	}
+	// the chain may have fewer blocks than asked for
+	blocks := len(history.GasUsedRatio)
+	if blocks == 0 || blocks > feeHistoryBlocks {
+		return fmt.Errorf("returned the gas used ratios of %d blocks, %d were asked for", blocks, feeHistoryBlocks)
+	}
+	if len(history.BaseFeePerGas) != blocks+1 {
+		return fmt.Errorf("returned %d base fees for %d blocks, expected one more, for the next block", len(history.BaseFeePerGas), blocks)
+	}
+	if len(history.Reward) != blocks {
+		return fmt.Errorf("returned the rewards of %d blocks for %d blocks", len(history.Reward), blocks)
+	}
+	for _, rewards := range history.Reward {
+		if len(rewards) != len(feeHistoryPercentiles) {
+			return fmt.Errorf("returned %d rewards per block for %d percentiles", len(rewards), len(feeHistoryPercentiles))
+		}
+	}
+	return nil
+}
+
+// checkSubscription subscribes to kind on the WebSocket endpoint at wsURL,
+// and unsubscribes. Blocks are only produced on transactions, so no
+// notification is waited for.
+func checkSubscription(ctx context.Context, wsURL string, kind string, args []interface{}) error {
+	client, err := rpc.DialContext(ctx, wsURL)
+	if err != nil {
+		return fmt.Errorf("failed connecting to %s: %w", wsURL, err)
+	}
+	defer client.Close()
+	ch := make(chan json.RawMessage, 16)
+	sub, err := client.EthSubscribe(ctx, ch, append([]interface{}{kind}, args...)...)
+	if err != nil {
+		return err
+	}
+	sub.Unsubscribe()
+	select {
+	case err := <-sub.Err():
+		if err != nil {
+			return fmt.Errorf("subscription failed: %w", err)
+		}
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/coreth/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeEthAPI serves the eth namespace of a chain with a single block, which
+// deviates from the specification when legacy is set: no EIP-1559 fields,
+// and errors for unknown transactions
+type fakeEthAPI struct {
+	legacy bool
+}
+
+func (f *fakeEthAPI) ChainId() *hexutil.Big              { return (*hexutil.Big)(big.NewInt(9999)) }
+func (f *fakeEthAPI) Syncing() bool                      { return false }
+func (f *fakeEthAPI) BlockNumber() hexutil.Uint64        { return 0 }
+func (f *fakeEthAPI) GasPrice() *hexutil.Big             { return (*hexutil.Big)(big.NewInt(25_000_000_000)) }
+func (f *fakeEthAPI) MaxPriorityFeePerGas() *hexutil.Big { return (*hexutil.Big)(big.NewInt(0)) }
+
+func (f *fakeEthAPI) GetBlockByNumber(number string, full bool) map[string]interface{} {
+	block := map[string]interface{}{
+		"number": "0x0", "hash": common.Hash{1}, "parentHash": common.Hash{}, "timestamp": "0x0",
+		"gasLimit": "0x7a1200", "gasUsed": "0x0", "miner": common.Address{}, "stateRoot": common.Hash{},
+		"transactionsRoot": common.Hash{}, "receiptsRoot": common.Hash{}, "logsBloom": "0x00",
+		"transactions": []interface{}{},
+	}
+	if !f.legacy {
+		block["baseFeePerGas"] = "0x5d21dba00"
+	}
+	return block
+}
+
+func (f *fakeEthAPI) GetBlockByHash(hash common.Hash, full bool) map[string]interface{} {
+	return f.GetBlockByNumber("0x0", full)
+}
+
+func (f *fakeEthAPI) FeeHistory(blocks hexutil.Uint64, last string, percentiles []float64) (map[string]interface{}, error) {
+	if f.legacy {
+		return nil, errors.New("the method eth_feeHistory does not exist/is not available")
+	}
+	return map[string]interface{}{
+		"oldestBlock":   "0x0",
+		"baseFeePerGas": []string{"0x5d21dba00", "0x5d21dba00"},
+		"gasUsedRatio":  []float64{0},
+		"reward":        [][]string{{"0x0", "0x0"}},
+	}, nil
+}
+
+func (f *fakeEthAPI) GetBalance(addr common.Address, block string) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(0))
+}
+
+func (f *fakeEthAPI) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 {
+	return 0
+}
+
+func (f *fakeEthAPI) GetCode(addr common.Address, block string) hexutil.Bytes { return nil }
+
+func (f *fakeEthAPI) GetStorageAt(addr common.Address, slot string, block string) hexutil.Bytes {
+	return make([]byte, common.HashLength)
+}
+
+func (f *fakeEthAPI) Call(args map[string]interface{}, block string) hexutil.Bytes { return nil }
+
+func (f *fakeEthAPI) EstimateGas(args map[string]interface{}) hexutil.Uint64 {
+	return transferGas
+}
+
+func (f *fakeEthAPI) GetLogs(filter map[string]interface{}) []interface{} { return []interface{}{} }
+
+func (f *fakeEthAPI) GetTransactionByHash(hash common.Hash) map[string]interface{} { return nil }
+
+func (f *fakeEthAPI) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	if f.legacy {
+		return nil, errors.New("not found")
+	}
+	return nil, nil
+}
+
+func (f *fakeEthAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	return subscribe(ctx)
+}
+
+func (f *fakeEthAPI) Logs(ctx context.Context, crit map[string]interface{}) (*rpc.Subscription, error) {
+	return subscribe(ctx)
+}
+
+func subscribe(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return notifier.CreateSubscription(), nil
+}
+
+type fakeNetAPI struct{}
+
+func (fakeNetAPI) Version() string { return "9999" }
+
+type fakeWeb3API struct{}
+
+func (fakeWeb3API) ClientVersion() string { return "fake/v0.0.1" }
+
+// newFakeEVMServer serves api on an RPC and a WebSocket endpoint, and returns
+// their URLs
+func newFakeEVMServer(t *testing.T, api *fakeEthAPI) (string, string) {
+	server := rpc.NewServer(0)
+	assert.NoError(t, server.RegisterName("eth", api))
+	assert.NoError(t, server.RegisterName("net", fakeNetAPI{}))
+	assert.NoError(t, server.RegisterName("web3", fakeWeb3API{}))
+	httpServer := httptest.NewServer(server)
+	wsServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		wsServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL, "ws" + strings.TrimPrefix(wsServer.URL, "http")
+}
+
+func deviations(results []ConformanceResult) map[string]string {
+	found := map[string]string{}
+	for _, r := range results {
+		if r.Deviation != nil {
+			found[r.Check] = r.Deviation.Error()
+		}
+	}
+	return found
+}
+
+func TestRunConformance(t *testing.T) {
+	assert := assert.New(t)
+
+	rpcURL, wsURL := newFakeEVMServer(t, &fakeEthAPI{})
+	results, err := RunConformance(context.Background(), rpcURL, wsURL, big.NewInt(9999))
+	assert.NoError(err)
+	assert.Len(results, len(conformanceChecks)+len(subscriptionKinds))
+	// only newPendingTransactions is not served
+	assert.Equal([]string{"eth_subscribe(newPendingTransactions)"}, keys(deviations(results)))
+
+	results, err = RunConformance(context.Background(), rpcURL, "", big.NewInt(1))
+	assert.NoError(err)
+	found := deviations(results)
+	assert.Len(found, 1)
+	assert.Contains(found["eth_chainId"], "returned 9999, expected the chain ID of the genesis 1")
+	assert.Equal("no WebSocket endpoint", results[len(results)-1].Skipped)
+
+	rpcURL, _ = newFakeEVMServer(t, &fakeEthAPI{legacy: true})
+	results, err = RunConformance(context.Background(), rpcURL, "", nil)
+	assert.NoError(err)
+	found = deviations(results)
+	assert.Equal([]string{"eth_feeHistory", "eth_getBlockByNumber", "eth_getTransactionReceipt"}, keys(found))
+	assert.Contains(found["eth_getBlockByNumber"], "without baseFeePerGas")
+	assert.Contains(found["eth_getTransactionReceipt"], "failed instead of returning null")
+}
+
+func keys(m map[string]string) []string {
+	names := []string{}
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWSURLFromRPC(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("ws://127.0.0.1:9650/ext/bc/abc/ws", WSURLFromRPC("http://127.0.0.1:9650/ext/bc/abc/rpc"))
+	assert.Equal("wss://node.example.com/ext/bc/abc/ws", WSURLFromRPC("https://node.example.com/ext/bc/abc/rpc"))
+	assert.Empty(WSURLFromRPC("https://provider.example.com/v1/my-key"))
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/lock"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/shirou/gopsutil/process"
+)
+
+// The local deploys of different subnets run concurrently, and take turns on
+// what they share:
+//   - the binaries, as a deploy installs avalanchego and the default snapshot,
+//     and installs its plugin and cleans up the ones not needed, under the
+//     install lock. The plugin of a deploy in progress is claimed, so that
+//     the others don't clean it up before its blockchain is created.
+//   - the network, which a deploy starts, adds its blockchain to on a
+//     preloaded subnet, and adds its API nodes to, under the network lock
+
+// waitLock takes the lock at path for the deploy of chain, telling the user
+// it waits for the concurrent deploy holding it to be done with what
+func waitLock(path string, chain string, what string) (*lock.Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	command := "avalanche subnet deploy " + chain
+	l, err := lock.Acquire(path, command)
+	if !errors.Is(err, lock.ErrLocked) {
+		return l, err
+	}
+	ux.Logger.PrintToUser("Another deploy is %s%s, waiting for it...", what, strings.TrimPrefix(err.Error(), lock.ErrLocked.Error()))
+	l, err = lock.AcquireWait(path, command, constants.DeployCoordinationWait)
+	if errors.Is(err, lock.ErrLocked) {
+		return nil, exitcodes.Locked(fmt.Errorf("%w, and still %s after %s", err, what, constants.DeployCoordinationWait))
+	}
+	return l, err
+}
+
+// lockNetwork takes the network lock of the local network of the profile
+func (d *LocalSubnetDeployer) lockNetwork(chain string) (*lock.Lock, error) {
+	return waitLock(filepath.Join(d.app.GetRunDir(), constants.NetworkLockFile), chain, "using the local network")
+}
+
+// lockInstalls takes the install lock
+func (d *LocalSubnetDeployer) lockInstalls(chain string) (*lock.Lock, error) {
+	return waitLock(filepath.Join(d.app.GetBaseDir(), constants.InstallLockFile), chain, "installing binaries")
+}
+
+// pluginClaimsDir returns the dir recording the plugins of pluginDir claimed
+// by the local deploys in progress, one file named after the pid of each
+func pluginClaimsDir(pluginDir string) string {
+	return filepath.Clean(pluginDir) + constants.PluginClaimsSuffix
+}
+
+// claimPlugin records that this process needs the plugin of vmID in
+// pluginDir until its deploy completes
+func claimPlugin(pluginDir string, vmID string) error {
+	dir := pluginClaimsDir(pluginDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, strconv.Itoa(os.Getpid())), []byte(vmID), application.WriteReadReadPerms)
+}
+
+// releasePluginClaim removes the claim of this process on a plugin of
+// pluginDir, if any
+func releasePluginClaim(pluginDir string) error {
+	err := os.Remove(filepath.Join(pluginClaimsDir(pluginDir), strconv.Itoa(os.Getpid())))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// claimedPlugins returns the IDs of the VMs whose plugins in pluginDir are
+// claimed by the deploys of other processes, removing the claims of the
+// processes gone
+func claimedPlugins(pluginDir string) (map[string]struct{}, error) {
+	dir := pluginClaimsDir(pluginDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	claimed := map[string]struct{}{}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if alive, err := process.PidExists(int32(pid)); err == nil && !alive {
+			_ = os.Remove(path)
+			continue
+		}
+		vmID, err := os.ReadFile(path)
+		if err != nil || len(vmID) == 0 {
+			continue
+		}
+		claimed[string(vmID)] = struct{}{}
+	}
+	return claimed, nil
+}
+
+// pickSubnetID returns the preloaded subnet of the network with the fewest
+// blockchains, the first one in order on a tie, so that each deploy gets its
+// own subnet until all of them are used
+func pickSubnetID(clusterInfo *rpcpb.ClusterInfo) (string, error) {
+	subnetIDs := append([]string{}, clusterInfo.GetSubnets()...)
+	if len(subnetIDs) == 0 {
+		return "", errors.New("the network has not preloaded subnet IDs")
+	}
+	sort.Strings(subnetIDs)
+	numBlockchains := map[string]int{}
+	for _, vmInfo := range clusterInfo.GetCustomVms() {
+		numBlockchains[vmInfo.SubnetId]++
+	}
+	picked := subnetIDs[0]
+	for _, subnetID := range subnetIDs[1:] {
+		if numBlockchains[subnetID] < numBlockchains[picked] {
+			picked = subnetID
+		}
+	}
+	return picked, nil
+}
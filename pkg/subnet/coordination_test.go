@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+func TestPickSubnetID(t *testing.T) {
+	assert := setupTest(t)
+
+	_, err := pickSubnetID(&rpcpb.ClusterInfo{})
+	assert.ErrorContains(err, "has not preloaded subnet IDs")
+
+	clusterInfo := &rpcpb.ClusterInfo{Subnets: []string{"subnetC", "subnetA", "subnetB"}}
+	subnetID, err := pickSubnetID(clusterInfo)
+	assert.NoError(err)
+	assert.Equal("subnetA", subnetID)
+
+	// the subnet of an undeployed blockchain may be used again
+	clusterInfo.CustomVms = map[string]*rpcpb.CustomVmInfo{
+		"chain1": {SubnetId: "subnetA"},
+		"chain3": {SubnetId: "subnetC"},
+	}
+	subnetID, err = pickSubnetID(clusterInfo)
+	assert.NoError(err)
+	assert.Equal("subnetB", subnetID)
+
+	// once all of them are used, the one with the fewest blockchains
+	clusterInfo.CustomVms["chain2"] = &rpcpb.CustomVmInfo{SubnetId: "subnetB"}
+	clusterInfo.CustomVms["chain4"] = &rpcpb.CustomVmInfo{SubnetId: "subnetA"}
+	subnetID, err = pickSubnetID(clusterInfo)
+	assert.NoError(err)
+	assert.Equal("subnetB", subnetID)
+	assert.Equal([]string{"subnetC", "subnetA", "subnetB"}, clusterInfo.Subnets)
+}
+
+func TestPluginClaims(t *testing.T) {
+	assert := setupTest(t)
+
+	pluginDir := filepath.Join(t.TempDir(), "plugins")
+	claimed, err := claimedPlugins(pluginDir)
+	assert.NoError(err)
+	assert.Empty(claimed)
+
+	// the claims of this process are its own
+	assert.NoError(claimPlugin(pluginDir, testVMID))
+	claimed, err = claimedPlugins(pluginDir)
+	assert.NoError(err)
+	assert.Empty(claimed)
+
+	// the ones of the processes gone are removed
+	claimsDir := pluginClaimsDir(pluginDir)
+	assert.NoError(os.WriteFile(filepath.Join(claimsDir, "1"), []byte("otherVM"), perms.ReadWrite))
+	assert.NoError(os.WriteFile(filepath.Join(claimsDir, "999999999"), []byte("goneVM"), perms.ReadWrite))
+	claimed, err = claimedPlugins(pluginDir)
+	assert.NoError(err)
+	assert.Equal(map[string]struct{}{"otherVM": {}}, claimed)
+	assert.NoFileExists(filepath.Join(claimsDir, "999999999"))
+
+	assert.NoError(releasePluginClaim(pluginDir))
+	assert.NoFileExists(filepath.Join(claimsDir, strconv.Itoa(os.Getpid())))
+	// releasing twice is harmless
+	assert.NoError(releasePluginClaim(pluginDir))
+}
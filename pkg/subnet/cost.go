@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// FeeParams are the fees and minimum stake of a network, in nAVAX
+type FeeParams struct {
+	TxFee                 uint64
+	CreateSubnetTxFee     uint64
+	CreateBlockchainTxFee uint64
+	MinValidatorStake     uint64
+}
+
+// GetFeeParams fetches the current fees and minimum stake of the public
+// network
+func GetFeeParams(app *application.Avalanche, network models.Network) (FeeParams, error) {
+	api, err := app.GetAPIEndpoint(network)
+	if err != nil {
+		return FeeParams{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	fees, err := info.NewClient(api).GetTxFee(ctx)
+	if err != nil {
+		return FeeParams{}, fmt.Errorf("failed getting the fees of %s: %w", network, err)
+	}
+	minValidatorStake, _, err := platformvm.NewClient(api).GetMinStake(ctx)
+	if err != nil {
+		return FeeParams{}, fmt.Errorf("failed getting the minimum stake of %s: %w", network, err)
+	}
+	return FeeParams{
+		TxFee:                 uint64(fees.TxFee),
+		CreateSubnetTxFee:     uint64(fees.CreateSubnetTxFee),
+		CreateBlockchainTxFee: uint64(fees.CreateBlockchainTxFee),
+		MinValidatorStake:     minValidatorStake,
+	}, nil
+}
+
+// Cost is what deploying a subnet and having it validated takes, in nAVAX
+type Cost struct {
+	CreateSubnet     uint64
+	CreateBlockchain uint64
+	// ValidatorTxs is the number of transactions adding the validators, one
+	// per validator and staking period
+	ValidatorTxs  uint64
+	AddValidators uint64
+	// PrimaryStake is the stake of the validators on the primary network,
+	// locked while validating and returned afterwards
+	PrimaryStake uint64
+}
+
+// Fees returns the fees spent by the deploy
+func (c Cost) Fees() uint64 {
+	return c.CreateSubnet + c.CreateBlockchain + c.AddValidators
+}
+
+// Total returns the amount needed to deploy, including the stake
+func (c Cost) Total() uint64 {
+	return c.Fees() + c.PrimaryStake
+}
+
+// EstimateCost returns the cost of deploying a subnet validated by
+// validators for duration. A validator can't be added for more than the
+// maximum staking period, so it is added again for every period started.
+// If primaryStake is set, the validators are not yet validating the primary
+// network and have to stake the minimum there.
+func EstimateCost(fees FeeParams, validators uint64, duration time.Duration, primaryStake bool) Cost {
+	periods := uint64((duration + constants.MaxStakeDuration - 1) / constants.MaxStakeDuration)
+	if periods == 0 {
+		periods = 1
+	}
+	cost := Cost{
+		CreateSubnet:     fees.CreateSubnetTxFee,
+		CreateBlockchain: fees.CreateBlockchainTxFee,
+		ValidatorTxs:     validators * periods,
+	}
+	cost.AddValidators = cost.ValidatorTxs * fees.TxFee
+	if primaryStake {
+		// the stake is returned at the end of every period, to be staked again
+		cost.PrimaryStake = validators * fees.MinValidatorStake
+	}
+	return cost
+}
+
+// Shortfall returns the amount missing from balance to pay for amount, in
+// nAVAX, or 0 if balance covers it
+func Shortfall(balance, amount uint64) uint64 {
+	if balance >= amount {
+		return 0
+	}
+	return amount - balance
+}
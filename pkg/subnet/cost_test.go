@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateCost(t *testing.T) {
+	assert := assert.New(t)
+
+	fees := FeeParams{
+		TxFee:                 units.MilliAvax,
+		CreateSubnetTxFee:     100 * units.MilliAvax,
+		CreateBlockchainTxFee: 100 * units.MilliAvax,
+		MinValidatorStake:     units.Avax,
+	}
+
+	cost := EstimateCost(fees, 5, constants.MaxStakeDuration/2, false)
+	assert.Equal(uint64(5), cost.ValidatorTxs)
+	assert.Equal(5*units.MilliAvax, cost.AddValidators)
+	assert.Zero(cost.PrimaryStake)
+	assert.Equal(205*units.MilliAvax, cost.Total())
+
+	// validators are added again for every staking period started
+	cost = EstimateCost(fees, 5, constants.MaxStakeDuration+1, true)
+	assert.Equal(uint64(10), cost.ValidatorTxs)
+	assert.Equal(5*units.Avax, cost.PrimaryStake)
+	assert.Equal(210*units.MilliAvax, cost.Fees())
+	assert.Equal(5*units.Avax+210*units.MilliAvax, cost.Total())
+}
+
+func TestShortfall(t *testing.T) {
+	assert := assert.New(t)
+	assert.Zero(Shortfall(units.Avax, units.Avax))
+	assert.Zero(Shortfall(2*units.Avax, units.Avax))
+	assert.Equal(200*units.MilliAvax, Shortfall(800*units.MilliAvax, units.Avax))
+	assert.Equal(units.Avax, Shortfall(0, units.Avax))
+}
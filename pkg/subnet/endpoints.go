@@ -0,0 +1,141 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// EndpointSelection is the API endpoint picked for a public network, the
+// fastest healthy one when it was probed
+type EndpointSelection struct {
+	Endpoint   string        `json:"endpoint"`
+	Latency    time.Duration `json:"latency"`
+	SelectedAt time.Time     `json:"selectedAt"`
+}
+
+// EndpointProbe is the outcome of checking an API endpoint
+type EndpointProbe struct {
+	Endpoint string
+	Latency  time.Duration
+	Err      error
+}
+
+// LoadEndpointSelections returns the API endpoints picked for the public
+// networks, by network name. A missing or broken file records none.
+func LoadEndpointSelections(app *application.Avalanche) map[string]EndpointSelection {
+	selections := map[string]EndpointSelection{}
+	selectionsBytes, err := os.ReadFile(app.GetEndpointSelectionsPath())
+	if err != nil {
+		return selections
+	}
+	if err := json.Unmarshal(selectionsBytes, &selections); err != nil {
+		app.Log.Warn("ignoring the broken endpoint selections at %s: %s", app.GetEndpointSelectionsPath(), err)
+		return map[string]EndpointSelection{}
+	}
+	return selections
+}
+
+func saveEndpointSelections(app *application.Avalanche, selections map[string]EndpointSelection) error {
+	selectionsBytes, err := json.MarshalIndent(selections, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetEndpointSelectionsPath(), selectionsBytes, WriteReadReadPerms)
+}
+
+// ProbeEndpoints checks the API endpoints concurrently with checkEndpoint,
+// and returns how long each took, the healthy ones first, fastest first
+func ProbeEndpoints(ctx context.Context, endpoints []string, networkID uint32) []EndpointProbe {
+	probes := make([]EndpointProbe, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			start := time.Now()
+			err := checkEndpoint(ctx, endpoint, networkID)
+			probes[i] = EndpointProbe{Endpoint: endpoint, Latency: time.Since(start), Err: err}
+		}(i, endpoint)
+	}
+	wg.Wait()
+	sort.SliceStable(probes, func(i, j int) bool {
+		if (probes[i].Err == nil) != (probes[j].Err == nil) {
+			return probes[i].Err == nil
+		}
+		return probes[i].Latency < probes[j].Latency
+	})
+	return probes
+}
+
+// SelectAPIEndpoint returns the API endpoint to use for network, checked
+// to be healthy. Of several endpoints, see GetAPIEndpoints, the one picked
+// before is kept for constants.EndpointSelectionTTL while it stays healthy,
+// else they are all probed and the fastest healthy one is picked, and
+// recorded for the next commands.
+func SelectAPIEndpoint(ctx context.Context, app *application.Avalanche, network models.Network, networkID uint32) (string, error) {
+	endpoints, err := app.GetAPIEndpoints(network)
+	if err != nil {
+		return "", err
+	}
+	if len(endpoints) == 1 {
+		return endpoints[0], checkEndpoint(ctx, endpoints[0], networkID)
+	}
+
+	selections := LoadEndpointSelections(app)
+	previous, picked := selections[network.String()]
+	if picked && time.Since(previous.SelectedAt) < constants.EndpointSelectionTTL && contains(endpoints, previous.Endpoint) {
+		err := checkEndpoint(ctx, previous.Endpoint, networkID)
+		if err == nil {
+			return previous.Endpoint, nil
+		}
+		app.Log.Warn("the API endpoint picked for %s is degraded, probing the others: %s", network, err)
+	}
+
+	probes := ProbeEndpoints(ctx, endpoints, networkID)
+	failures := []string{}
+	for _, probe := range probes {
+		if probe.Err == nil {
+			continue
+		}
+		app.Log.Debug("API endpoint %s failed its check: %s", probe.Endpoint, probe.Err)
+		failures = append(failures, probe.Err.Error())
+	}
+	best := probes[0]
+	if best.Err != nil {
+		return "", fmt.Errorf("none of the %d API endpoints of %s is healthy: %s", len(endpoints), network, strings.Join(failures, "; "))
+	}
+	if !picked || previous.Endpoint != best.Endpoint {
+		ux.Logger.PrintToUser("Using API endpoint %s for %s, the fastest healthy one (%s)", best.Endpoint, network, best.Latency.Round(time.Millisecond))
+	}
+	selections[network.String()] = EndpointSelection{
+		Endpoint:   best.Endpoint,
+		Latency:    best.Latency,
+		SelectedAt: time.Now().UTC(),
+	}
+	if err := saveEndpointSelections(app, selections); err != nil {
+		app.Log.Warn("failed recording the API endpoint picked for %s: %s", network, err)
+	}
+	return best.Endpoint, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/viper"
+)
+
+func TestSelectAPIEndpoint(t *testing.T) {
+	assert := setupTest(t)
+
+	healthy := newInfoServer(avago_constants.FujiID, true)
+	defer healthy.Close()
+	bootstrapping := newInfoServer(avago_constants.FujiID, false)
+	defer bootstrapping.Close()
+	mainnet := newInfoServer(avago_constants.MainnetID, true)
+	defer mainnet.Close()
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("endpoints.fuji", []interface{}{bootstrapping.URL, mainnet.URL, healthy.URL})
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+
+	api, err := SelectAPIEndpoint(context.Background(), app, models.Fuji, avago_constants.FujiID)
+	assert.NoError(err)
+	assert.Equal(healthy.URL, api)
+	selection := LoadEndpointSelections(app)[models.Fuji.String()]
+	assert.Equal(healthy.URL, selection.Endpoint)
+
+	// the pick sticks while it is healthy, even if another is faster
+	another := newInfoServer(avago_constants.FujiID, true)
+	defer another.Close()
+	viper.Set("endpoints.fuji", []interface{}{another.URL, healthy.URL})
+	api, err = SelectAPIEndpoint(context.Background(), app, models.Fuji, avago_constants.FujiID)
+	assert.NoError(err)
+	assert.Equal(healthy.URL, api)
+
+	// and is replaced once it is down
+	healthy.Close()
+	api, err = SelectAPIEndpoint(context.Background(), app, models.Fuji, avago_constants.FujiID)
+	assert.NoError(err)
+	assert.Equal(another.URL, api)
+	assert.Equal(another.URL, LoadEndpointSelections(app)[models.Fuji.String()].Endpoint)
+
+	// or once it expires
+	selections := LoadEndpointSelections(app)
+	selection = selections[models.Fuji.String()]
+	selection.Endpoint = bootstrapping.URL
+	selection.SelectedAt = time.Now().Add(-2 * time.Hour)
+	selections[models.Fuji.String()] = selection
+	assert.NoError(saveEndpointSelections(app, selections))
+	viper.Set("endpoints.fuji", []interface{}{bootstrapping.URL, another.URL})
+	api, err = SelectAPIEndpoint(context.Background(), app, models.Fuji, avago_constants.FujiID)
+	assert.NoError(err)
+	assert.Equal(another.URL, api)
+
+	another.Close()
+	_, err = SelectAPIEndpoint(context.Background(), app, models.Fuji, avago_constants.FujiID)
+	assert.ErrorContains(err, "none of the 2 API endpoints of Fuji is healthy")
+	assert.ErrorContains(err, "not finished bootstrapping")
+	assert.ErrorContains(err, "is not reachable")
+
+	// a single endpoint is only checked
+	app.Conf.SetEndpoint(mainnet.URL)
+	_, err = SelectAPIEndpoint(context.Background(), app, models.Fuji, avago_constants.FujiID)
+	assert.ErrorContains(err, "is on network mainnet, not on fuji")
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/shirou/gopsutil/mem"
+)
+
+const (
+	// assumedDownloadBandwidth is the bandwidth, in bytes per second, the
+	// download times are estimated with
+	assumedDownloadBandwidth = 5 * 1024 * 1024
+	gib                      = 1024 * 1024 * 1024
+)
+
+// DownloadConfirmFunc is called before downloading what, of size bytes or
+// -1 if unknown, and fails if the download must not happen
+type DownloadConfirmFunc func(what string, size int64) error
+
+// EstimateDownload returns how long downloading size bytes takes at the
+// assumed bandwidth
+func EstimateDownload(size int64) time.Duration {
+	return (time.Duration(size) * time.Second / assumedDownloadBandwidth).Round(time.Second)
+}
+
+// MachineClass is how fast a machine runs a local network, from its CPUs
+// and memory
+type MachineClass struct {
+	Name      string
+	CPUs      int
+	MemoryGiB float64
+	// Bootstrap is how long a local network typically takes to be healthy
+	// on a machine of the class
+	Bootstrap time.Duration
+}
+
+// machineClasses are the classes from the slowest to the fastest, a
+// machine being of the last class it has the CPUs and the memory of
+var machineClasses = []MachineClass{
+	{Name: "small", Bootstrap: 90 * time.Second},
+	{Name: "medium", CPUs: 4, MemoryGiB: 8, Bootstrap: 45 * time.Second},
+	{Name: "large", CPUs: 8, MemoryGiB: 16, Bootstrap: 20 * time.Second},
+}
+
+// ClassifyMachine returns the class of a machine of cpus and memoryGiB
+func ClassifyMachine(cpus int, memoryGiB float64) MachineClass {
+	class := machineClasses[0]
+	for _, c := range machineClasses[1:] {
+		if cpus >= c.CPUs && memoryGiB >= c.MemoryGiB {
+			class = c
+		}
+	}
+	class.CPUs, class.MemoryGiB = cpus, memoryGiB
+	return class
+}
+
+// DetectMachineClass returns the class of this machine. Its memory is
+// assumed to be the one of the smallest class if it can't be read.
+func DetectMachineClass() MachineClass {
+	memoryGiB := 0.0
+	if vm, err := mem.VirtualMemory(); err == nil {
+		memoryGiB = float64(vm.Total) / gib
+	}
+	return ClassifyMachine(runtime.NumCPU(), memoryGiB)
+}
+
+// ExpectedBootstrap returns how long the local network should take to start
+// and be healthy, and where the estimate comes from: the previous deploys on
+// this machine, or else its class
+func (d *LocalSubnetDeployer) ExpectedBootstrap() (time.Duration, string) {
+	if bootstrap, ok := d.timings.Estimate(PhaseBootstrap); ok {
+		snapshotLoad, _ := d.timings.Estimate(PhaseSnapshotLoad)
+		return (snapshotLoad + bootstrap).Round(time.Second), "as in the previous starts on this machine"
+	}
+	class := DetectMachineClass()
+	return class.Bootstrap, fmt.Sprintf("typical of a %s machine (%d CPUs, %.1f GiB)", class.Name, class.CPUs, class.MemoryGiB)
+}
+
+// PrintExpectedBootstrap tells the user how long the local network should
+// take to be healthy
+func (d *LocalSubnetDeployer) PrintExpectedBootstrap() {
+	expected, source := d.ExpectedBootstrap()
+	ux.Logger.PrintToUser("The network should be healthy in about %s, %s", ux.FormatDuration(expected), source)
+}
+
+// SetNoConfirm downloads without asking for confirmation on a metered
+// connection
+func (d *LocalSubnetDeployer) SetNoConfirm(noConfirm bool) {
+	d.noConfirm = noConfirm
+}
+
+// confirmDownload tells the user the size of what is downloaded and how long
+// it should take, and asks for confirmation first on a metered connection
+func (d *LocalSubnetDeployer) confirmDownload(what string, size int64) error {
+	if size < 0 {
+		ux.Logger.PrintToUser("Downloading the %s, of unknown size", what)
+	} else {
+		ux.Logger.PrintToUser("Downloading the %s: %s, about %s at %s/s",
+			what, ux.FormatSize(size), ux.FormatDuration(EstimateDownload(size)), ux.FormatSize(assumedDownloadBandwidth))
+	}
+	if d.noConfirm || !d.app.Conf.Metered() {
+		return nil
+	}
+	yes, err := d.app.Prompt.CaptureYesNo(fmt.Sprintf("The connection is metered, download the %s?", what))
+	if err != nil {
+		return err
+	}
+	if !yes {
+		return exitcodes.UserInput(errors.New("download declined, use --no-confirm to download without asking"))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClassifyMachine(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("small", ClassifyMachine(2, 4).Name)
+	assert.Equal("small", ClassifyMachine(8, 4).Name)
+	assert.Equal("medium", ClassifyMachine(4, 8).Name)
+	assert.Equal("medium", ClassifyMachine(16, 12).Name)
+	large := ClassifyMachine(8, 32)
+	assert.Equal("large", large.Name)
+	assert.Equal(8, large.CPUs)
+	assert.Equal(32.0, large.MemoryGiB)
+	assert.Less(large.Bootstrap, ClassifyMachine(1, 1).Bootstrap)
+}
+
+func TestEstimateDownload(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(time.Duration(0), EstimateDownload(0))
+	assert.Equal(10*time.Second, EstimateDownload(50*1024*1024))
+}
+
+func TestExpectedBootstrap(t *testing.T) {
+	assert := assert.New(t)
+
+	d := &LocalSubnetDeployer{timings: LoadPhaseTimings(filepath.Join(t.TempDir(), constants.PhaseTimingsFile))}
+	expected, source := d.ExpectedBootstrap()
+	assert.Equal(DetectMachineClass().Bootstrap, expected)
+	assert.Contains(source, "machine")
+
+	d.timings.Record(PhaseSnapshotLoad, 2*time.Second)
+	d.timings.Record(PhaseBootstrap, 10*time.Second)
+	expected, source = d.ExpectedBootstrap()
+	assert.Equal(12*time.Second, expected)
+	assert.Contains(source, "previous starts")
+}
+
+func TestConfirmDownload(t *testing.T) {
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+	d := &LocalSubnetDeployer{app: app}
+
+	// not metered, nothing is asked
+	t.Setenv(constants.EnvVarPrefix+"METERED", "false")
+	assert.NoError(d.confirmDownload("bootstrap snapshot", 1024))
+
+	t.Setenv(constants.EnvVarPrefix+"METERED", "true")
+	prompter := &mocks.Prompter{}
+	prompter.On("CaptureYesNo", mock.Anything).Return(false, nil).Once()
+	prompter.On("CaptureYesNo", mock.Anything).Return(true, nil).Once()
+	app.Prompt = prompter
+	err := d.confirmDownload("bootstrap snapshot", -1)
+	assert.ErrorContains(err, "--no-confirm")
+	assert.NoError(d.confirmDownload("bootstrap snapshot", 1024))
+
+	// the download goes on without asking
+	d.SetNoConfirm(true)
+	assert.NoError(d.confirmDownload("bootstrap snapshot", 1024))
+	prompter.AssertNumberOfCalls(t, "CaptureYesNo", 2)
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/subnet-evm/core"
+)
+
+// CheckEwoqGenesis refuses to deploy the genesis of sc if it funds the ewoq
+// address or makes the ewoq key an admin of a precompile, the way the
+// defaults of the creation wizard do, unless allowed. Only the subnet EVM
+// genesis are checked.
+func (d *PublicDeployer) CheckEwoqGenesis(sc models.Sidecar, genesis []byte) error {
+	if sc.VM != models.SubnetEvm {
+		return nil
+	}
+	var evmGenesis core.Genesis
+	if err := json.Unmarshal(genesis, &evmGenesis); err != nil {
+		// an invalid genesis is refused by the chain itself
+		return nil
+	}
+	for _, finding := range vm.LintEvmGenesis(evmGenesis, d.network) {
+		if strings.HasPrefix(finding.Rule, "ewoq-") {
+			if err := d.app.CheckEwoq(d.network, "deploy a genesis where "+finding.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkEwoqControlKeys refuses to make the ewoq key a control key of a
+// subnet, letting anyone add its validators, unless allowed
+func (d *PublicDeployer) checkEwoqControlKeys(controlKeys []string) error {
+	for _, controlKey := range controlKeys {
+		if key.IsEwoqAddress(controlKey) {
+			return d.app.CheckEwoq(d.network, "make the ewoq key "+controlKey+" a control key of the subnet")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckEwoq(t *testing.T) {
+	assert := assert.New(t)
+	app := &application.Avalanche{Log: logging.NoLog{}, Conf: config.New()}
+
+	chainConfig := *params.SubnetEVMDefaultChainConfig
+	genesis, err := json.Marshal(core.Genesis{
+		Config:     &chainConfig,
+		Difficulty: vm.Difficulty,
+		GasLimit:   vm.GasLimit,
+		Alloc:      core.GenesisAlloc{vm.PrefundedEwoqAddress: {Balance: big.NewInt(1)}},
+	})
+	assert.NoError(err)
+	sc := models.Sidecar{Name: "tokens", VM: models.SubnetEvm}
+
+	ewoqPath := filepath.Join(t.TempDir(), "ewoq.pk")
+	ewoq, err := key.NewSoft(avago_constants.FujiID, key.WithPrivateKeyEncoded(key.EwoqPrivateKey))
+	assert.NoError(err)
+	assert.True(key.IsEwoq(ewoq))
+	assert.NoError(ewoq.Save(ewoqPath))
+	ewoqControlKey := ewoq.P()[0]
+	assert.True(key.IsEwoqAddress(ewoqControlKey))
+
+	local := NewPublicDeployer(app, ewoqPath, models.Local)
+	assert.NoError(local.CheckEwoqGenesis(sc, genesis))
+
+	fuji := NewPublicDeployer(app, ewoqPath, models.Fuji)
+	err = fuji.CheckEwoqGenesis(sc, genesis)
+	assert.True(errors.Is(err, application.ErrEwoq))
+	assert.Equal(exitcodes.UserInputError, exitcodes.FromError(err))
+	assert.ErrorContains(err, "--i-know-what-im-doing")
+	// only the subnet EVM genesis are checked
+	assert.NoError(fuji.CheckEwoqGenesis(models.Sidecar{VM: models.CustomVM}, genesis))
+	assert.ErrorIs(fuji.checkEwoqControlKeys([]string{"P-fuji1x459sj0ssujguq723cljfty4jlae28evjzt7xz", ewoqControlKey}), application.ErrEwoq)
+	_, err = fuji.loadSoftKey(avago_constants.FujiID)
+	assert.ErrorIs(err, application.ErrEwoq)
+
+	app.Conf.SetAllowEwoq(true)
+	assert.NoError(fuji.CheckEwoqGenesis(sc, genesis))
+	assert.NoError(fuji.checkEwoqControlKeys([]string{ewoqControlKey}))
+	_, err = fuji.loadSoftKey(avago_constants.FujiID)
+	assert.NoError(err)
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// GovernanceHolder is a control key of a subnet in a governance document
+type GovernanceHolder struct {
+	Address string `json:"address"`
+	HeldBy  string `json:"heldBy"`
+}
+
+// GovernanceDocument states who controls a subnet, for the holders of its
+// control keys to agree on it. Each holder signs Message, e.g. with the
+// wallet holding their key, and the signatures are kept along with the
+// document.
+type GovernanceDocument struct {
+	Subnet      string             `json:"subnet"`
+	Network     string             `json:"network"`
+	SubnetID    string             `json:"subnetID"`
+	Threshold   uint32             `json:"threshold"`
+	ControlKeys []GovernanceHolder `json:"controlKeys"`
+	// Message is the statement of the governance to sign
+	Message string `json:"message"`
+	// Digest is the hex encoded SHA-256 of Message
+	Digest string `json:"digest"`
+}
+
+// NewGovernanceDocument returns the governance document of subnet subnetName
+// deployed to network with ID subnetID
+func NewGovernanceDocument(subnetName string, network models.Network, subnetID ids.ID, governance models.SubnetGovernance) *GovernanceDocument {
+	doc := &GovernanceDocument{
+		Subnet:    subnetName,
+		Network:   network.String(),
+		SubnetID:  subnetID.String(),
+		Threshold: governance.Threshold,
+	}
+	lines := []string{
+		fmt.Sprintf("Governance of subnet %s on %s", subnetName, network),
+		"Subnet ID: " + subnetID.String(),
+		fmt.Sprintf("Threshold: %d of %d control keys", governance.Threshold, len(governance.ControlKeys)),
+		"Control keys:",
+	}
+	for _, k := range governance.ControlKeys {
+		holder := GovernanceHolder{Address: k.Address, HeldBy: k.HeldBy()}
+		doc.ControlKeys = append(doc.ControlKeys, holder)
+		lines = append(lines, fmt.Sprintf("  %s (%s)", holder.Address, holder.HeldBy))
+	}
+	doc.Message = strings.Join(lines, "\n")
+	digest := sha256.Sum256([]byte(doc.Message))
+	doc.Digest = hex.EncodeToString(digest[:])
+	return doc
+}
+
+// Save writes the document to path
+func (d *GovernanceDocument) Save(path string) error {
+	docBytes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, docBytes, application.WriteReadReadPerms)
+}
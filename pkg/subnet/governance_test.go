@@ -0,0 +1,45 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestGovernanceDocument(t *testing.T) {
+	assert := setupTest(t)
+
+	subnetID := ids.GenerateTestID()
+	doc := NewGovernanceDocument("mySubnet", models.Fuji, subnetID, models.SubnetGovernance{
+		ControlKeys: []models.ControlKey{
+			{Address: "P-fuji1a", Key: "mykey"},
+			{Address: "P-fuji1b", Holder: "ops team"},
+		},
+		Threshold: 2,
+	})
+	assert.Equal(subnetID.String(), doc.SubnetID)
+	assert.Equal([]GovernanceHolder{
+		{Address: "P-fuji1a", HeldBy: "local key mykey"},
+		{Address: "P-fuji1b", HeldBy: "ops team"},
+	}, doc.ControlKeys)
+	assert.Contains(doc.Message, "Threshold: 2 of 2 control keys")
+	assert.Contains(doc.Message, "  P-fuji1b (ops team)")
+	digest := sha256.Sum256([]byte(doc.Message))
+	assert.Equal(hex.EncodeToString(digest[:]), doc.Digest)
+
+	path := filepath.Join(t.TempDir(), "governance.json")
+	assert.NoError(doc.Save(path))
+	docBytes, err := os.ReadFile(path)
+	assert.NoError(err)
+	loaded := &GovernanceDocument{}
+	assert.NoError(json.Unmarshal(docBytes, loaded))
+	assert.Equal(doc, loaded)
+}
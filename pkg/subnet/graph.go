@@ -0,0 +1,284 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/info"
+)
+
+const (
+	GraphFormatText    = "text"
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+// GraphFormats are the formats a graph of the local network is rendered in
+var GraphFormats = []string{GraphFormatText, GraphFormatDOT, GraphFormatMermaid}
+
+// NodeVersionGetter returns the versions run by the node with the info API
+// at uri
+type NodeVersionGetter func(ctx context.Context, uri string) (*info.GetNodeVersionReply, error)
+
+// GetNodeVersion returns the versions run by the node with the info API at
+// uri, with the info API
+func GetNodeVersion(ctx context.Context, uri string) (*info.GetNodeVersionReply, error) {
+	return info.NewClient(uri).GetNodeVersion(ctx)
+}
+
+// Graph is the topology of the local network: its nodes, the subnets they
+// track and the blockchains of these subnets
+type Graph struct {
+	Nodes   []GraphNode
+	Subnets []GraphSubnet
+}
+
+type GraphNode struct {
+	Name   string
+	NodeID string
+	URI    string
+	// Version is the avalanchego version of the node, empty if unknown
+	Version string
+	// Subnets are the IDs of the subnets the node tracks
+	Subnets []string
+}
+
+type GraphSubnet struct {
+	ID          string
+	Blockchains []GraphBlockchain
+}
+
+type GraphBlockchain struct {
+	ID   string
+	Name string
+	VMID string
+	// VMVersions are the versions of the VM the nodes run, by node name,
+	// missing if unknown
+	VMVersions map[string]string
+}
+
+// VMVersion returns the versions of the VM of the blockchain, one if all
+// the nodes run the same, otherwise each one with the nodes running it
+func (b GraphBlockchain) VMVersion() string {
+	nodes := map[string][]string{}
+	for node, version := range b.VMVersions {
+		nodes[version] = append(nodes[version], node)
+	}
+	if len(nodes) == 0 {
+		return "unknown version"
+	}
+	if len(nodes) == 1 {
+		for version := range nodes {
+			return version
+		}
+	}
+	versions := []string{}
+	for version, names := range nodes {
+		sort.Strings(names)
+		versions = append(versions, fmt.Sprintf("%s on %s", version, strings.Join(names, ", ")))
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, "; ")
+}
+
+// BuildGraph returns the graph of the local network of clusterInfo, with
+// the versions its nodes run, as returned by getVersion. The nodes not
+// answering in time are left without versions.
+func BuildGraph(ctx context.Context, clusterInfo *rpcpb.ClusterInfo, getVersion NodeVersionGetter) Graph {
+	graph := Graph{}
+	subnets := map[string]*GraphSubnet{}
+	addSubnet := func(id string) *GraphSubnet {
+		if subnet, ok := subnets[id]; ok {
+			return subnet
+		}
+		subnets[id] = &GraphSubnet{ID: id}
+		return subnets[id]
+	}
+	for _, id := range clusterInfo.Subnets {
+		addSubnet(id)
+	}
+
+	vmVersions := map[string]map[string]string{}
+	names := append([]string{}, clusterInfo.NodeNames...)
+	sort.Strings(names)
+	for _, name := range names {
+		nodeInfo, ok := clusterInfo.NodeInfos[name]
+		if !ok {
+			continue
+		}
+		node := GraphNode{Name: name, NodeID: nodeInfo.Id, URI: nodeInfo.Uri, Subnets: []string{}}
+		for _, id := range strings.Split(nodeInfo.WhitelistedSubnets, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				node.Subnets = append(node.Subnets, id)
+				addSubnet(id)
+			}
+		}
+		sort.Strings(node.Subnets)
+		versionCtx, cancel := context.WithTimeout(ctx, constants.NodeInfoTimeout)
+		reply, err := getVersion(versionCtx, nodeInfo.Uri)
+		cancel()
+		if err == nil {
+			node.Version = reply.Version
+			vmVersions[name] = reply.VMVersions
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for blockchainID, vmInfo := range clusterInfo.CustomVms {
+		blockchain := GraphBlockchain{
+			ID:         blockchainID,
+			Name:       vmInfo.VmName,
+			VMID:       vmInfo.VmId,
+			VMVersions: map[string]string{},
+		}
+		for node, versions := range vmVersions {
+			// the VMs are reported by their primary alias, their ID unless
+			// aliased
+			for _, alias := range []string{vmInfo.VmId, vmInfo.VmName} {
+				if version, ok := versions[alias]; ok {
+					blockchain.VMVersions[node] = version
+					break
+				}
+			}
+		}
+		subnet := addSubnet(vmInfo.SubnetId)
+		subnet.Blockchains = append(subnet.Blockchains, blockchain)
+	}
+	for _, subnet := range subnets {
+		sort.Slice(subnet.Blockchains, func(i, j int) bool {
+			return subnet.Blockchains[i].Name < subnet.Blockchains[j].Name
+		})
+		graph.Subnets = append(graph.Subnets, *subnet)
+	}
+	sort.Slice(graph.Subnets, func(i, j int) bool { return graph.Subnets[i].ID < graph.Subnets[j].ID })
+	return graph
+}
+
+// trackers returns the names of the nodes tracking subnetID
+func (g Graph) trackers(subnetID string) []string {
+	names := []string{}
+	for _, node := range g.Nodes {
+		for _, id := range node.Subnets {
+			if id == subnetID {
+				names = append(names, node.Name)
+			}
+		}
+	}
+	return names
+}
+
+// Render writes the graph to w in format, one of GraphFormats
+func (g Graph) Render(w io.Writer, format string) error {
+	switch format {
+	case GraphFormatText:
+		return g.renderText(w)
+	case GraphFormatDOT:
+		return g.renderDOT(w)
+	case GraphFormatMermaid:
+		return g.renderMermaid(w)
+	}
+	return fmt.Errorf("unknown graph format %q, must be one of %s", format, strings.Join(GraphFormats, ", "))
+}
+
+// renderText writes the graph as an ASCII tree, the nodes and then the
+// subnets with their blockchains
+func (g Graph) renderText(w io.Writer) error {
+	var sb strings.Builder
+	branch := func(last bool) (string, string) {
+		if last {
+			return "`-- ", "    "
+		}
+		return "|-- ", "|   "
+	}
+	fmt.Fprintf(&sb, "Local network: %d nodes, %d subnets\n", len(g.Nodes), len(g.Subnets))
+	fmt.Fprintln(&sb, "|-- nodes")
+	for i, node := range g.Nodes {
+		prefix, indent := branch(i == len(g.Nodes)-1)
+		version := node.Version
+		if version == "" {
+			version = "unknown version"
+		}
+		fmt.Fprintf(&sb, "|   %s%s %s (%s) %s\n", prefix, node.Name, node.NodeID, version, node.URI)
+		if len(node.Subnets) > 0 {
+			fmt.Fprintf(&sb, "|   %s`-- tracks %s\n", indent, strings.Join(node.Subnets, ", "))
+		}
+	}
+	fmt.Fprintln(&sb, "`-- subnets")
+	for i, subnet := range g.Subnets {
+		prefix, indent := branch(i == len(g.Subnets)-1)
+		trackers := "no node"
+		if names := g.trackers(subnet.ID); len(names) > 0 {
+			trackers = strings.Join(names, ", ")
+		}
+		fmt.Fprintf(&sb, "    %s%s, tracked by %s\n", prefix, subnet.ID, trackers)
+		for j, blockchain := range subnet.Blockchains {
+			chainPrefix, _ := branch(j == len(subnet.Blockchains)-1)
+			fmt.Fprintf(&sb, "    %s%s%s %s (VM %s, %s)\n", indent, chainPrefix, blockchain.Name, blockchain.ID, blockchain.VMID, blockchain.VMVersion())
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// graphID returns an identifier of the DOT and mermaid languages for id,
+// which can start with a digit
+func graphID(kind string, id string) string {
+	return kind + "_" + strings.NewReplacer("-", "_", ".", "_").Replace(id)
+}
+
+func (g Graph) renderDOT(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintln(&sb, "digraph \"local network\" {")
+	fmt.Fprintln(&sb, "  rankdir=LR;")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&sb, "  %s [shape=box, label=%q];\n", graphID("node", node.Name), fmt.Sprintf("%s\n%s\n%s", node.Name, node.NodeID, node.Version))
+	}
+	for _, subnet := range g.Subnets {
+		fmt.Fprintf(&sb, "  %s [shape=ellipse, label=%q];\n", graphID("subnet", subnet.ID), "subnet "+subnet.ID)
+		for _, blockchain := range subnet.Blockchains {
+			fmt.Fprintf(&sb, "  %s [shape=component, label=%q];\n", graphID("chain", blockchain.ID),
+				fmt.Sprintf("%s\n%s\n%s", blockchain.Name, blockchain.ID, blockchain.VMVersion()))
+			fmt.Fprintf(&sb, "  %s -> %s;\n", graphID("subnet", subnet.ID), graphID("chain", blockchain.ID))
+		}
+	}
+	for _, node := range g.Nodes {
+		for _, id := range node.Subnets {
+			fmt.Fprintf(&sb, "  %s -> %s [label=\"tracks\"];\n", graphID("node", node.Name), graphID("subnet", id))
+		}
+	}
+	fmt.Fprintln(&sb, "}")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (g Graph) renderMermaid(w io.Writer) error {
+	var sb strings.Builder
+	label := func(lines ...string) string {
+		return strings.ReplaceAll(strings.Join(lines, "<br/>"), "\"", "#quot;")
+	}
+	fmt.Fprintln(&sb, "graph LR")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", graphID("node", node.Name), label(node.Name, node.NodeID, node.Version))
+	}
+	for _, subnet := range g.Subnets {
+		fmt.Fprintf(&sb, "  %s((\"%s\"))\n", graphID("subnet", subnet.ID), label("subnet "+subnet.ID))
+		for _, blockchain := range subnet.Blockchains {
+			fmt.Fprintf(&sb, "  %s[[\"%s\"]]\n", graphID("chain", blockchain.ID), label(blockchain.Name, blockchain.ID, blockchain.VMVersion()))
+			fmt.Fprintf(&sb, "  %s --> %s\n", graphID("subnet", subnet.ID), graphID("chain", blockchain.ID))
+		}
+	}
+	for _, node := range g.Nodes {
+		for _, id := range node.Subnets {
+			fmt.Fprintf(&sb, "  %s -- tracks --> %s\n", graphID("node", node.Name), graphID("subnet", id))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeNames: []string{"node2", "node1", "node3"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", Id: "NodeID-1", Uri: "http://127.0.0.1:9650", WhitelistedSubnets: "subnetA,subnetB"},
+			"node2": {Name: "node2", Id: "NodeID-2", Uri: "http://127.0.0.1:9652", WhitelistedSubnets: "subnetA"},
+			"node3": {Name: "node3", Id: "NodeID-3", Uri: "http://127.0.0.1:9654"},
+		},
+		CustomVms: map[string]*rpcpb.CustomVmInfo{
+			"chainA": {VmName: "tokens", VmId: "vmA", SubnetId: "subnetA", BlockchainId: "chainA"},
+			"chainB": {VmName: "games", VmId: "vmB", SubnetId: "subnetB", BlockchainId: "chainB"},
+		},
+	}
+	getVersion := func(ctx context.Context, uri string) (*info.GetNodeVersionReply, error) {
+		switch uri {
+		case "http://127.0.0.1:9650":
+			return &info.GetNodeVersionReply{Version: "avalanche/1.7.16", VMVersions: map[string]string{"vmA": "v0.2.9", "games": "v0.1.0"}}, nil
+		case "http://127.0.0.1:9652":
+			return &info.GetNodeVersionReply{Version: "avalanche/1.7.16", VMVersions: map[string]string{"vmA": "v0.2.8"}}, nil
+		}
+		return nil, errors.New("unreachable")
+	}
+
+	graph := BuildGraph(context.Background(), clusterInfo, getVersion)
+	assert.Len(graph.Nodes, 3)
+	assert.Equal("node1", graph.Nodes[0].Name)
+	assert.Equal([]string{"subnetA", "subnetB"}, graph.Nodes[0].Subnets)
+	assert.Equal("", graph.Nodes[2].Version)
+	assert.Len(graph.Subnets, 2)
+	assert.Equal("v0.2.8 on node2; v0.2.9 on node1", graph.Subnets[0].Blockchains[0].VMVersion())
+	assert.Equal("v0.1.0", graph.Subnets[1].Blockchains[0].VMVersion())
+	assert.Equal([]string{"node1", "node2"}, graph.trackers("subnetA"))
+
+	var out bytes.Buffer
+	assert.NoError(graph.Render(&out, GraphFormatText))
+	assert.Contains(out.String(), "Local network: 3 nodes, 2 subnets")
+	assert.Contains(out.String(), "|   |-- node1 NodeID-1 (avalanche/1.7.16) http://127.0.0.1:9650\n|   |   `-- tracks subnetA, subnetB")
+	assert.Contains(out.String(), "|   `-- node3 NodeID-3 (unknown version)")
+	assert.Contains(out.String(), "    `-- subnetB, tracked by node1\n        `-- games chainB (VM vmB, v0.1.0)")
+
+	out.Reset()
+	assert.NoError(graph.Render(&out, GraphFormatDOT))
+	assert.Contains(out.String(), "node_node2 -> subnet_subnetA [label=\"tracks\"];")
+	assert.Contains(out.String(), "subnet_subnetB -> chain_chainB;")
+
+	out.Reset()
+	assert.NoError(graph.Render(&out, GraphFormatMermaid))
+	assert.Contains(out.String(), "node_node1 -- tracks --> subnet_subnetB")
+	assert.Contains(out.String(), "chain_chainA[[\"tokens<br/>chainA<br/>v0.2.8 on node2; v0.2.9 on node1\"]]")
+
+	assert.ErrorContains(graph.Render(&out, "svg"), "unknown graph format")
+}
@@ -4,15 +4,12 @@ package subnet
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
 	"sort"
 	"strings"
 	"time"
@@ -20,6 +17,10 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/lock"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/timings"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanche-network-runner/client"
@@ -28,11 +29,11 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/storage"
 	"github.com/ava-labs/coreth/core"
-	"github.com/ava-labs/coreth/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
-	zipExtension       = "zip"
 	WriteReadReadPerms = 0o644
 )
 
@@ -45,18 +46,45 @@ type LocalSubnetDeployer struct {
 	app                 *application.Avalanche
 	backendStartedHere  bool
 	setDefaultSnapshot  setDefaultSnapshotFunc
+	timings             *PhaseTimings
+	// phases are the durations of the phases of the current deploy
+	phases []PhaseDuration
+	// verbose prints the exact funded amounts, in wei
+	verbose bool
+	// vmSource, if set, is built to install the plugin of the deployed VM,
+	// instead of downloading a release
+	vmSource *binutils.VMSource
+	// apiNodes is the number of nodes of the network tracking the deployed
+	// subnets without validating them
+	apiNodes int
+	// followLogs streams the log lines of the nodes while the network starts
+	followLogs bool
+	// streamingLogs is true while the log lines of the nodes are streamed,
+	// which replace the progress shown
+	streamingLogs bool
+	// noConfirm downloads without asking on a metered connection
+	noConfirm bool
+	// vmBinaryPath is the plugin of the VM of the current deploy, and
+	// vmBinaryHash its SHA-256 once deployed
+	vmBinaryPath string
+	vmBinaryHash string
 }
 
 func NewLocalSubnetDeployer(app *application.Avalanche) *LocalSubnetDeployer {
-	return &LocalSubnetDeployer{
+	d := &LocalSubnetDeployer{
 		procChecker:         binutils.NewProcessChecker(),
 		binChecker:          binutils.NewBinaryChecker(),
-		getClientFunc:       binutils.NewGRPCClient,
-		binaryDownloader:    binutils.NewPluginBinaryDownloader(app.Log),
+		getClientFunc:       func() (client.Client, error) { return binutils.NewGRPCClient(app) },
+		binaryDownloader:    binutils.NewPluginBinaryDownloader(app.Log, app.Conf),
 		healthCheckInterval: 100 * time.Millisecond,
 		app:                 app,
-		setDefaultSnapshot:  SetDefaultSnapshot,
+		timings:             LoadPhaseTimings(app.GetPhaseTimingsPath()),
+		apiNodes:            app.Conf.APINodes(),
 	}
+	d.setDefaultSnapshot = func(snapshotsDir string, force bool) error {
+		return SetDefaultSnapshotConfirmed(snapshotsDir, force, d.confirmDownload)
+	}
+	return d
 }
 
 type getGRPCClientFunc func() (client.Client, error)
@@ -66,56 +94,111 @@ type setDefaultSnapshotFunc func(string, bool) error
 // DeployToLocalNetwork does the heavy lifting:
 // * it checks the gRPC is running, if not, it starts it
 // * kicks off the actual deployment
-func (d *LocalSubnetDeployer) DeployToLocalNetwork(chain string, chainGenesis string) (ids.ID, ids.ID, error) {
-	if err := d.StartServer(); err != nil {
+func (d *LocalSubnetDeployer) DeployToLocalNetwork(sc models.Sidecar, chainGenesis string) (ids.ID, ids.ID, error) {
+	// the concurrent deploys finding no backend don't start one each
+	var networkLock *lock.Lock
+	if isRunning, err := d.procChecker.IsServerProcessRunning(d.app); err == nil && !isRunning {
+		if networkLock, err = d.lockNetwork(sc.Name); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+	}
+	err := d.StartServer()
+	_ = networkLock.Release()
+	if err != nil {
 		return ids.Empty, ids.Empty, err
 	}
-	return d.doDeploy(chain, chainGenesis)
+	subnetID, blockchainID, err := d.doDeploy(sc, chainGenesis)
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	// the chain is deployed, a plugin which can't be hashed is only warned
+	// about
+	if d.vmBinaryHash, err = binutils.SHA256File(d.vmBinaryPath); err != nil {
+		d.app.Log.Warn("failed hashing the VM binary of %s: %s", sc.Name, err)
+	}
+	return subnetID, blockchainID, nil
 }
 
 func (d *LocalSubnetDeployer) StartServer() error {
 	isRunning, err := d.procChecker.IsServerProcessRunning(d.app)
 	if err != nil {
-		return fmt.Errorf("failed querying if server process is running: %w", err)
+		return exitcodes.Backend(fmt.Errorf("failed querying if server process is running: %w", err))
 	}
 	if !isRunning {
 		d.app.Log.Debug("gRPC server is not running")
 		if err := binutils.StartServerProcess(d.app); err != nil {
-			return fmt.Errorf("failed starting gRPC server process: %w", err)
+			return exitcodes.Backend(fmt.Errorf("failed starting gRPC server process: %w", err))
 		}
 		d.backendStartedHere = true
 	}
 	return nil
 }
 
+// SetVerbose prints the exact amounts of the funded addresses, in wei, in
+// the summary of the deploy
+func (d *LocalSubnetDeployer) SetVerbose(verbose bool) {
+	d.verbose = verbose
+}
+
+// SetVMSource builds the plugin of the deployed VM from source, instead of
+// downloading a release
+func (d *LocalSubnetDeployer) SetVMSource(source binutils.VMSource) {
+	d.vmSource = &source
+}
+
+// SetAPINodes sets the number of API nodes of the network, overriding the one
+// of the project config
+func (d *LocalSubnetDeployer) SetAPINodes(apiNodes int) {
+	d.apiNodes = apiNodes
+}
+
+// PhaseDurations returns how long each phase of the last deploy took, in
+// the order they ran
+func (d *LocalSubnetDeployer) PhaseDurations() []PhaseDuration {
+	return d.phases
+}
+
+// recordPhase records the duration of a phase of the current deploy, and
+// adds it to the history the ETAs are estimated from
+func (d *LocalSubnetDeployer) recordPhase(phase DeployPhase, duration time.Duration) {
+	d.timings.Record(phase, duration)
+	d.phases = append(d.phases, PhaseDuration{Phase: phase, Seconds: duration.Seconds()})
+}
+
+// VMBinaryHash returns the SHA-256 of the VM plugin binary of the last
+// deploy, empty if unknown
+func (d *LocalSubnetDeployer) VMBinaryHash() string {
+	return d.vmBinaryHash
+}
+
 // BackendStartedHere returns true if the backend was started by this run,
 // or false if it found it there already
 func (d *LocalSubnetDeployer) BackendStartedHere() bool {
 	return d.backendStartedHere
 }
 
-// doDeploy the actual deployment to the network runner
-// steps:
-// - checks if the network has been started
-// - install all needed plugin binaries, for the the new VM, and the already deployed VMs
-// - either starts a network from the default snapshot if not started,
-//   or restarts the already available network while preserving state
-// - waits completion of operation
-// - get from the network an available subnet ID to be used in blockchain creation
-// - deploy a new blockchain for the given VM ID, genesis, and available subnet ID
-// - waits completion of operation
-// - show status
-func (d *LocalSubnetDeployer) doDeploy(chain string, chainGenesis string) (ids.ID, ids.ID, error) {
-	avalancheGoBinPath, pluginDir, err := d.SetupLocalEnv()
-	if err != nil {
-		return ids.Empty, ids.Empty, err
-	}
-
-	cli, err := d.getClientFunc()
-	if err != nil {
-		return ids.Empty, ids.Empty, fmt.Errorf("error creating gRPC Client: %s", err)
-	}
-	defer cli.Close()
+// doDeploy the actual deployment to the network runner, in phases:
+//   - env setup: installs avalanchego and the default snapshot
+//   - plugins: installs all needed plugin binaries, for the new VM, and the
+//     already deployed VMs
+//   - network start: starts a network from the default snapshot if not
+//     started, and waits until it is healthy
+//   - chain create: deploys a new blockchain for the given VM ID and genesis,
+//     on one of the subnets preloaded in the snapshot
+//   - health wait: waits until the new blockchain is healthy, and shows the
+//     status
+//
+// The deploys of other subnets may run concurrently: the installs are done
+// under the install lock, and the phases from the network start on under the
+// network lock
+//
+// The completed phases are recorded in a checkpoint, so that deploying again
+// after a failure resumes after them, e.g. without installing the plugins
+// again when the blockchain creation failed
+func (d *LocalSubnetDeployer) doDeploy(sc models.Sidecar, chainGenesis string) (ids.ID, ids.ID, error) {
+	chain := sc.Name
+	d.phases = nil
+	d.vmBinaryHash = ""
 
 	exists, err := storage.FileExists(chainGenesis)
 	if !exists || err != nil {
@@ -129,12 +212,61 @@ func (d *LocalSubnetDeployer) doDeploy(chain string, chainGenesis string) (ids.I
 	if err != nil {
 		return ids.Empty, ids.Empty, fmt.Errorf("failed to unpack chain ID from genesis: %w", err)
 	}
-	chainID := genesis.Config.ChainID
+
+	vmName := sc.GetVMName()
+	chainVMID, err := sc.GetVMID()
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	// the network runner always derives the VM ID from the VM name
+	derivedVMID, err := utils.VMID(vmName)
+	if err != nil {
+		return ids.Empty, ids.Empty, fmt.Errorf("failed to create VM ID from %s: %w", vmName, err)
+	}
+	if chainVMID != derivedVMID {
+		return ids.Empty, ids.Empty, exitcodes.UserInput(fmt.Errorf(
+			"VM ID %s does not match VM alias %q, local networks require the VM ID derived from the alias (%s)",
+			chainVMID, vmName, derivedVMID))
+	}
+	d.app.Log.Debug("this VM will get ID: %s", chainVMID.String())
+
+	vmSource := ""
+	if d.vmSource != nil {
+		vmSource = d.vmSource.String()
+	}
+	checkpointKey, err := DeployCheckpointKey(d.app, chainGenesis, chainVMID.String(), vmSource)
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	checkpoint, err := LoadDeployCheckpoint(d.app, chain, checkpointKey)
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	if len(checkpoint.Completed) > 0 {
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgDeployResuming), chain, checkpoint)
+	}
+
+	avalancheGoBinPath, pluginDir, err := d.envSetupPhase(checkpoint, chain)
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	d.vmBinaryPath = filepath.Join(pluginDir, chainVMID.String())
+
+	cli, err := d.getClientFunc()
+	if err != nil {
+		return ids.Empty, ids.Empty, fmt.Errorf("error creating gRPC Client: %w", err)
+	}
+	defer cli.Close()
 
 	runDir := d.app.GetRunDir()
 
 	ctx := binutils.GetAsyncContext()
 
+	var networkLock *lock.Lock
+	defer func() {
+		_ = networkLock.Release()
+	}()
+
 	// check for network and get VM info
 	networkBooted := true
 	clusterInfo, err := d.WaitForHealthy(ctx, cli, d.healthCheckInterval)
@@ -143,113 +275,382 @@ func (d *LocalSubnetDeployer) doDeploy(chain string, chainGenesis string) (ids.I
 		if strings.Contains(err.Error(), "not bootstrapped") {
 			networkBooted = false
 		} else {
-			return ids.Empty, ids.Empty, fmt.Errorf("failed to query network health: %s", err)
+			return ids.Empty, ids.Empty, fmt.Errorf("failed to query network health: %w", err)
 		}
 	}
+	// the blockchains undeployed from the network are still reported by it
+	if clusterInfo, err = FilterUndeployed(d.app, clusterInfo); err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+
+	vmInfo := deployedChain(chainVMID, clusterInfo)
+	if checkpoint.Done(PhaseChainCreate) && vmInfo == nil {
+		// the network the blockchain was created on is gone
+		d.saveCheckpoint(checkpoint.Undo(d.app, PhaseChainCreate))
+	}
+	var subnetIDStr string
+	if checkpoint.Done(PhaseChainCreate) {
+		// resume waiting for the blockchain created by the failed deploy
+		if networkLock, err = d.lockNetwork(chain); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+		subnetIDStr = checkpoint.SubnetID
+	} else {
+		// deploying again is a no-op, which reports the existing deployment
+		if vmInfo != nil {
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgAlreadyDeployed), chain)
+			subnetID, blockchainID, err := deployedIDs(vmInfo, sc.Networks[d.app.GetLocalNetworkKey()])
+			if err != nil {
+				return ids.Empty, ids.Empty, err
+			}
+			d.saveCheckpoint(checkpoint.Remove(d.app))
+			if len(APINodeNames(clusterInfo)) != d.apiNodes {
+				if networkLock, err = d.lockNetwork(chain); err != nil {
+					return ids.Empty, ids.Empty, err
+				}
+				if clusterInfo, err = d.AddAPINodes(ctx, cli, clusterInfo, avalancheGoBinPath); err != nil {
+					return ids.Empty, ids.Empty, err
+				}
+			}
+			d.printSummary(sc, clusterInfo, blockchainID, genesis)
+			return subnetID, blockchainID, nil
+		}
 
-	chainVMID, err := utils.VMID(chain)
+		defer func() {
+			if err := releasePluginClaim(pluginDir); err != nil {
+				d.app.Log.Warn("failed releasing the claim on the plugin: %s", err)
+			}
+		}()
+		if err := d.pluginsPhase(ctx, cli, checkpoint, chain, chainVMID, networkBooted, clusterInfo, avalancheGoBinPath, pluginDir); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgVMsReady))
+
+		if networkLock, err = d.lockNetwork(chain); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+		if !networkBooted {
+			// a concurrent deploy may have started it meanwhile
+			if _, err := d.WaitForHealthy(ctx, cli, d.healthCheckInterval); err == nil {
+				networkBooted = true
+			}
+		}
+		if clusterInfo, err = d.networkStartPhase(ctx, cli, networkBooted, avalancheGoBinPath, pluginDir, runDir); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+		// they are added back once the blockchain is created
+		if clusterInfo, err = d.RemoveAPINodes(ctx, cli, clusterInfo, 0); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+
+		if subnetIDStr, err = d.chainCreatePhase(ctx, cli, checkpoint, vmName, chainGenesis, clusterInfo); err != nil {
+			return ids.Empty, ids.Empty, err
+		}
+	}
+
+	// we can safely ignore errors here as the subnets have already been generated
+	subnetID, _ := ids.FromString(subnetIDStr)
+	// the endpoints of the nodes are printed as soon as each is ready, while
+	// waiting for all of them
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	watchDone := make(chan struct{})
+	go func(clusterInfo *rpcpb.ClusterInfo) {
+		defer close(watchDone)
+		watchNodesReady(watchCtx, clusterInfo, newNodeReadiness(subnetID, chainVMID), nodeReadyPollInterval, printNodeReady)
+	}(clusterInfo)
+	clusterInfo, err = d.waitForHealthyPhase(ctx, cli, PhaseVMHealth)
+	stopWatching()
+	<-watchDone
 	if err != nil {
-		return ids.Empty, ids.Empty, fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
+		return ids.Empty, ids.Empty, fmt.Errorf("failed to query network health: %w", err)
+	}
+	if clusterInfo, err = FilterUndeployed(d.app, clusterInfo); err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	d.saveCheckpoint(checkpoint.Remove(d.app))
+	if clusterInfo, err = d.AddAPINodes(ctx, cli, clusterInfo, avalancheGoBinPath); err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	// the endpoints by blockchain ID keep working without the aliases
+	if err := AliasChains(ctx, clusterInfo); err != nil {
+		ux.Logger.PrintToUser("Warning: the endpoints are only available by blockchain ID: %s", err)
+	}
+	if err := d.timings.Save(); err != nil {
+		d.app.Log.Warn("failed saving deploy phase timings: %s", err)
+	}
+	// the concurrent deploys can use the network from here on
+	if err := networkLock.Release(); err != nil {
+		d.app.Log.Warn("failed releasing the network lock: %s", err)
 	}
-	d.app.Log.Debug("this VM will get ID: %s", chainVMID.String())
 
-	if alreadyDeployed(chainVMID, clusterInfo) {
-		ux.Logger.PrintToUser("Subnet %s has already been deployed", chain)
-		return ids.Empty, ids.Empty, nil
+	var blockchainID ids.ID
+	for _, info := range clusterInfo.CustomVms {
+		if info.VmId == chainVMID.String() {
+			blockchainID, _ = ids.FromString(info.BlockchainId)
+		}
 	}
 
-	if err := d.installNeededPlugins(chainVMID, clusterInfo, pluginDir); err != nil {
+	d.printSummary(sc, clusterInfo, blockchainID, genesis)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgDeployTimings), FormatPhaseDurations(d.phases))
+
+	// the nodes only read the chain configs when the network starts
+	if chainConfig, err := d.app.LoadChainConfig(chain); err != nil {
 		return ids.Empty, ids.Empty, err
+	} else if chainConfig != nil {
+		ux.Logger.PrintToUser("The chain config of %s applies once the local network is restarted with network stop and network start", chain)
 	}
 
-	ux.Logger.PrintToUser("VMs ready.")
+	// the RPC of custom VMs is unknown
+	if sc.VM == models.SubnetEvm {
+		d.smokeTest(clusterInfo, blockchainID, genesis)
+	}
+	return subnetID, blockchainID, nil
+}
 
-	if !networkBooted {
-		if err := d.startNetwork(ctx, cli, avalancheGoBinPath, pluginDir, runDir); err != nil {
-			return ids.Empty, ids.Empty, err
+// saveCheckpoint logs the failure to record the checkpoint of the deploy,
+// which only makes the next deploy start over
+func (d *LocalSubnetDeployer) saveCheckpoint(err error) {
+	if err != nil {
+		d.app.Log.Warn("failed saving the deploy checkpoint: %s", err)
+	}
+}
+
+// envSetupPhase returns the avalanchego binary and plugin dir of the local
+// network, set up by SetupLocalEnv unless the checkpoint has them
+func (d *LocalSubnetDeployer) envSetupPhase(checkpoint *DeployCheckpoint, chain string) (string, string, error) {
+	if checkpoint.Done(PhaseEnvSetup) {
+		binExists, _ := storage.FileExists(checkpoint.AvalancheGoBinPath)
+		pluginDirExists, _ := storage.FolderExists(checkpoint.PluginDir)
+		if binExists && pluginDirExists {
+			return checkpoint.AvalancheGoBinPath, checkpoint.PluginDir, nil
 		}
 	}
+	installLock, err := d.lockInstalls(chain)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		_ = installLock.Release()
+	}()
+	start := time.Now()
+	avalancheGoBinPath, pluginDir, err := d.SetupLocalEnv()
+	if err != nil {
+		return "", "", err
+	}
+	d.recordPhase(PhaseEnvSetup, time.Since(start))
+	checkpoint.AvalancheGoBinPath, checkpoint.PluginDir = avalancheGoBinPath, pluginDir
+	d.saveCheckpoint(checkpoint.Complete(d.app, PhaseEnvSetup))
+	return avalancheGoBinPath, pluginDir, nil
+}
 
-	clusterInfo, err = d.WaitForHealthy(ctx, cli, d.healthCheckInterval)
+// pluginsPhase installs the plugins of the VM of the deployed chain and of the
+// chains on the network, unless the checkpoint has them installed already, and
+// claims the plugin of the deployed chain until the deploy completes
+func (d *LocalSubnetDeployer) pluginsPhase(
+	ctx context.Context,
+	cli client.Client,
+	checkpoint *DeployCheckpoint,
+	chain string,
+	chainVMID ids.ID,
+	networkBooted bool,
+	clusterInfo *rpcpb.ClusterInfo,
+	avalancheGoBinPath string,
+	pluginDir string,
+) error {
+	installLock, err := d.lockInstalls(chain)
 	if err != nil {
-		return ids.Empty, ids.Empty, fmt.Errorf("failed to query network health: %s", err)
+		return err
+	}
+	defer func() {
+		_ = installLock.Release()
+	}()
+	if err := claimPlugin(pluginDir, chainVMID.String()); err != nil {
+		return fmt.Errorf("failed claiming the plugin of %s: %w", chain, err)
+	}
+	if networkBooted {
+		// the concurrent deploys may have added blockchains meanwhile
+		resp, err := cli.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query network status: %w", err)
+		}
+		if clusterInfo, err = FilterUndeployed(d.app, resp.GetClusterInfo()); err != nil {
+			return err
+		}
+	}
+	if checkpoint.Done(PhasePluginInstall) && pluginsInstalled(chainVMID, clusterInfo, pluginDir) {
+		return nil
+	}
+	start := time.Now()
+	if err := d.installNeededPlugins(chainVMID, clusterInfo, pluginDir); err != nil {
+		return err
+	}
+	if err := CheckPluginsProtocol(d.app.Log, avalancheGoBinPath, pluginDir); err != nil {
+		return err
+	}
+	d.recordPhase(PhasePluginInstall, time.Since(start))
+	d.saveCheckpoint(checkpoint.Complete(d.app, PhasePluginInstall))
+	return nil
+}
+
+// pluginsInstalled returns true if the plugins of the VM of the deployed
+// chain and of the chains on the network are in pluginDir
+func pluginsInstalled(chainVMID ids.ID, clusterInfo *rpcpb.ClusterInfo, pluginDir string) bool {
+	vmIDs := []string{chainVMID.String()}
+	if clusterInfo != nil {
+		for _, vmInfo := range clusterInfo.CustomVms {
+			vmIDs = append(vmIDs, vmInfo.VmId)
+		}
+	}
+	for _, vmID := range vmIDs {
+		if vmID == "" {
+			continue
+		}
+		if exists, err := storage.FileExists(filepath.Join(pluginDir, vmID)); !exists || err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// networkStartPhase starts the network from the default snapshot unless it
+// is booted already, and returns its info once it is healthy
+func (d *LocalSubnetDeployer) networkStartPhase(
+	ctx context.Context,
+	cli client.Client,
+	networkBooted bool,
+	avalancheGoBinPath string,
+	pluginDir string,
+	runDir string,
+) (*rpcpb.ClusterInfo, error) {
+	var (
+		clusterInfo *rpcpb.ClusterInfo
+		err         error
+	)
+	if !networkBooted {
+		d.PrintExpectedBootstrap()
+		err = d.StreamNodeLogs(runDir, func() error {
+			snapshotLoadStart := time.Now()
+			if err := d.startNetwork(ctx, cli, avalancheGoBinPath, pluginDir, runDir); err != nil {
+				return err
+			}
+			d.recordPhase(PhaseSnapshotLoad, time.Since(snapshotLoadStart))
+			var err error
+			if clusterInfo, err = d.waitForHealthyPhase(ctx, cli, PhaseBootstrap); err != nil {
+				return fmt.Errorf("failed to query network health: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if clusterInfo, err = d.WaitForHealthy(ctx, cli, d.healthCheckInterval); err != nil {
+		return nil, fmt.Errorf("failed to query network health: %w", err)
 	}
-	subnetIDs := clusterInfo.Subnets
-	numBlockchains := len(clusterInfo.CustomVms)
+	if clusterInfo, err = d.ApplyNodeVersions(ctx, cli, clusterInfo, avalancheGoBinPath, pluginDir); err != nil {
+		return nil, err
+	}
+	return FilterUndeployed(d.app, clusterInfo)
+}
 
+// chainCreatePhase creates the blockchain of the VM vmName with the given
+// genesis on the network, and returns the ID of the subnet it is created in
+func (d *LocalSubnetDeployer) chainCreatePhase(
+	ctx context.Context,
+	cli client.Client,
+	checkpoint *DeployCheckpoint,
+	vmName string,
+	chainGenesis string,
+	clusterInfo *rpcpb.ClusterInfo,
+) (string, error) {
 	// in order to make subnet deploy faster, a set of validated subnet IDs is preloaded
 	// in the bootstrap snapshot
-	// we select one to be used for creating the next blockchain, for that we use the
-	// number of currently created blockchains as the index to select the next subnet ID,
-	// so we get incremental selection
-	sort.Strings(subnetIDs)
-	if len(subnetIDs) == 0 {
-		return ids.Empty, ids.Empty, errors.New("the network has not preloaded subnet IDs")
+	// we select the one with the fewest blockchains to be used for creating the next
+	// blockchain, so we get incremental selection
+	subnetIDStr, err := pickSubnetID(clusterInfo)
+	if err != nil {
+		return "", err
 	}
-	subnetIDStr := subnetIDs[numBlockchains%len(subnetIDs)]
 
 	// create a new blockchain on the already started network, associated to
 	// the given VM ID, genesis, and available subnet ID
 	blockchainSpecs := []*rpcpb.BlockchainSpec{
 		{
-			VmName:   chain,
+			VmName:   vmName,
 			Genesis:  chainGenesis,
 			SubnetId: &subnetIDStr,
 		},
 	}
+	start := time.Now()
 	deployBlockchainsInfo, err := cli.CreateBlockchains(
 		ctx,
 		blockchainSpecs,
 	)
 	if err != nil {
-		return ids.Empty, ids.Empty, fmt.Errorf("failed to deploy blockchain :%s", err)
+		return "", fmt.Errorf("failed to deploy blockchain :%s", err)
 	}
+	d.recordPhase(PhaseChainCreate, time.Since(start))
+	checkpoint.SubnetID = subnetIDStr
+	d.saveCheckpoint(checkpoint.Complete(d.app, PhaseChainCreate))
 
 	d.app.Log.Debug(deployBlockchainsInfo.String())
 
 	fmt.Println()
-	ux.Logger.PrintToUser("Blockchain has been deployed. Wait until network acknowledges...")
-
-	clusterInfo, err = d.WaitForHealthy(ctx, cli, d.healthCheckInterval)
-	if err != nil {
-		return ids.Empty, ids.Empty, fmt.Errorf("failed to query network health: %s", err)
-	}
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgBlockchainDeployed))
+	return subnetIDStr, nil
+}
 
-	endpoints := GetEndpoints(clusterInfo)
+// printNodeReady prints the endpoints of a node as soon as the new blockchain
+// is ready on it
+func printNodeReady(node ReadyNode) {
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgNodeReady), node.Name,
+		ux.RPCEndpoint(node.URI, node.BlockchainID.String()), ux.WSEndpoint(node.URI, node.BlockchainID.String()))
+}
 
+// printSummary prints the endpoints of the local network, and the details of
+// the deployed chain to add it to a wallet
+func (d *LocalSubnetDeployer) printSummary(sc models.Sidecar, clusterInfo *rpcpb.ClusterInfo, blockchainID ids.ID, genesis core.Genesis) {
 	fmt.Println()
-	ux.Logger.PrintToUser("Network ready to use. Local network node endpoints:")
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgNetworkReady))
 	ux.PrintTableEndpoints(clusterInfo)
+	if apiNodes := APINodeNames(clusterInfo); len(apiNodes) > 0 {
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgAPINodes), strings.Join(apiNodes, ", "))
+	}
 	fmt.Println()
 
-	firstURL := endpoints[0]
-	tokenName := d.app.GetTokenName(chain)
+	nodeNames := make([]string, 0, len(clusterInfo.NodeInfos))
+	for name := range clusterInfo.NodeInfos {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	firstNodeURI := clusterInfo.NodeInfos[nodeNames[0]].GetUri()
+	tokenName := d.app.GetTokenName(sc.Name)
 
-	ux.Logger.PrintToUser("Metamask connection details (any node URL from above works):")
-	ux.Logger.PrintToUser("RPC URL:          %s", firstURL[strings.LastIndex(firstURL, "http"):])
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgMetamaskDetails))
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgRPCURL), ux.RPCEndpoint(ux.HostURI(firstNodeURI), blockchainID.String()))
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgWSURL), ux.WSEndpoint(ux.HostURI(firstNodeURI), blockchainID.String()))
+	addresses := make([]common.Address, 0, len(genesis.Alloc))
 	for address := range genesis.Alloc {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Hex() < addresses[j].Hex() })
+	for _, address := range addresses {
 		amount := genesis.Alloc[address].Balance
-		formattedAmount := new(big.Int).Div(amount, big.NewInt(params.Ether))
+		formattedAmount := ux.FormatAmount(amount, sc.GetTokenDecimals()) + " " + tokenName
+		if d.verbose {
+			formattedAmount += ux.Msgf(ux.MsgExactAmount, amount.String())
+		}
 		if address == vm.PrefundedEwoqAddress {
-			ux.Logger.PrintToUser("Funded address:   %s with %s (10^18) - private key: %s", address, formattedAmount.String(), vm.PrefundedEwoqPrivate)
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgFundedEwoqAddress), address, formattedAmount, vm.PrefundedEwoqPrivate)
+		} else if keyName, ok := sc.FundedKeys[address.Hex()]; ok {
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgFundedKeyAddress), address, formattedAmount, keyName)
 		} else {
-			ux.Logger.PrintToUser("Funded address:   %s with %s", address, formattedAmount.String())
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgFundedAddress), address, formattedAmount)
 		}
 	}
 
-	ux.Logger.PrintToUser("Network name:     %s", chain)
-	ux.Logger.PrintToUser("Chain ID:         %s", chainID)
-	ux.Logger.PrintToUser("Currency Symbol:  %s", tokenName)
-
-	// we can safely ignore errors here as the subnets have already been generated
-	subnetID, _ := ids.FromString(subnetIDStr)
-	var blockchainID ids.ID
-	for _, info := range clusterInfo.CustomVms {
-		if info.VmId == chainVMID.String() {
-			blockchainID, _ = ids.FromString(info.BlockchainId)
-		}
-	}
-	return subnetID, blockchainID, nil
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgNetworkName), sc.Name)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgChainID), genesis.Config.ChainID)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgCurrencySymbol), tokenName)
 }
 
 // SetupLocalEnv also does some heavy lifting:
@@ -262,15 +663,19 @@ func (d *LocalSubnetDeployer) SetupLocalEnv() (string, string, error) {
 	if err != nil {
 		return "", "", fmt.Errorf("failed setting up snapshots: %w", err)
 	}
+	// the nodes of the default profile listen on fixed ports, so
+	// networks of other profiles must use free ones to not collide
+	if !d.app.IsDefaultProfile() {
+		if err := ReleaseSnapshotNodePorts(d.app.GetSnapshotsDir()); err != nil {
+			return "", "", fmt.Errorf("failed setting up snapshots: %w", err)
+		}
+	}
 
-	avagoDir, err := d.setupLocalEnv()
+	avalancheGoBinPath, pluginDir, err := d.setupLocalEnv()
 	if err != nil {
 		return "", "", fmt.Errorf("failed setting up local environment: %w", err)
 	}
 
-	pluginDir := filepath.Join(avagoDir, "plugins")
-	avalancheGoBinPath := filepath.Join(avagoDir, "avalanchego")
-
 	exists, err := storage.FolderExists(pluginDir)
 	if !exists || err != nil {
 		return "", "", fmt.Errorf("evaluated pluginDir to be %s but it does not exist", pluginDir)
@@ -287,134 +692,137 @@ func (d *LocalSubnetDeployer) SetupLocalEnv() (string, string, error) {
 	return avalancheGoBinPath, pluginDir, nil
 }
 
-func (d *LocalSubnetDeployer) setupLocalEnv() (string, error) {
+// setupLocalEnv returns the avalanchego binary the local network runs and
+// the plugin directory of the VMs, installing the managed avalanchego release
+// if the binary isn't set with --avalanchego-path
+func (d *LocalSubnetDeployer) setupLocalEnv() (string, string, error) {
 	binDir := filepath.Join(d.app.GetBaseDir(), constants.AvalancheCliBinDir)
-	binPrefix := "avalanchego-v"
-
-	exists, avagoDir, err := d.binChecker.ExistsWithLatestVersion(binDir, binPrefix)
-	if err != nil {
-		return "", fmt.Errorf("failed trying to locate avalanchego binary: %s", binDir)
-	}
-	if exists {
-		d.app.Log.Debug("local avalanchego found. skipping installation")
-		return avagoDir, nil
-	}
-
-	ux.Logger.PrintToUser("Installing avalanchego...")
 
 	// TODO: we are hardcoding the release version
 	// until we have a better binary, dependency and version management
 	// as per https://github.com/ava-labs/avalanche-cli/pull/17#discussion_r887164924
-	version := constants.AvalancheGoReleaseVersion
+	version, pinned := d.app.Conf.AvalancheGoVersion()
 	/*
-		version, err := binutils.GetLatestReleaseVersion(constants.LatestAvagoReleaseURL)
+		version, err := binutils.GetLatestReleaseVersion(d.app, constants.LatestAvagoReleaseURL)
 		if err != nil {
 			return "", fmt.Errorf("failed to get latest avalanchego version: %s", err)
 		}
 	*/
 
-	d.app.Log.Info("Avalanchego version is: %s", version)
-
-	// TODO: would be nice if we could also here just use binutils.DownloadLatestReleaseVersion(),
-	// but unfortunately we don't have a consistent naming scheme between avalanchego and subnet-evm
-	// releases and names (and supported `goos`).
-	// Doing so therefore would require adding some questionable complexity.
-	// The goal MUST be to have some sort of mature binary management
-
-	// NOTE: if any of the underlying URLs change (github changes, release file names, etc.) this fails
-	arch := runtime.GOARCH
-	goos := runtime.GOOS
-	var avalanchegoURL string
-	var ext string
-
-	switch goos {
-	case "linux":
-		avalanchegoURL = fmt.Sprintf(
-			"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-linux-%s-%s.tar.gz",
-			version,
-			arch,
-			version,
-		)
-		ext = "tar.gz"
-	case "darwin":
-		avalanchegoURL = fmt.Sprintf(
-			"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-macos-%s.zip",
-			version,
-			version,
-		)
-		ext = zipExtension
-		// EXPERMENTAL WIN, no support
-	case "windows":
-		avalanchegoURL = fmt.Sprintf(
-			"https://github.com/ava-labs/avalanchego/releases/download/%s/avalanchego-win-%s-experimental.zip",
-			version,
-			version,
-		)
-		ext = zipExtension
-	default:
-		return "", fmt.Errorf("OS not supported: %s", goos)
+	if avalancheGoBinPath := d.app.Conf.AvalancheGoPath(); avalancheGoBinPath != "" {
+		return setupCustomAvalancheGo(d.app.Log, avalancheGoBinPath, version, binDir)
 	}
 
-	d.app.Log.Debug("starting download from %s...", avalanchegoURL)
-
-	resp, err := http.Get(avalanchegoURL)
+	avagoDir, err := d.installAvalancheGo(binDir, version, pinned)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
+	return filepath.Join(avagoDir, "avalanchego"), filepath.Join(avagoDir, "plugins"), nil
+}
+
+// installAvalancheGo returns the directory of the avalanchego release at
+// version, or of the latest one installed unless pinned, downloading it if
+// it isn't installed
+func (d *LocalSubnetDeployer) installAvalancheGo(binDir string, version string, pinned bool) (string, error) {
+	binPrefix := "avalanchego-v"
+
+	if pinned {
+		// a version pinned by the project must be used exactly
+		avagoDir := filepath.Join(binDir, "avalanchego-"+version)
+		exists, err := storage.FolderExists(avagoDir)
+		if err != nil {
+			return "", fmt.Errorf("failed trying to locate avalanchego binary: %s", avagoDir)
+		}
+		if exists {
+			d.app.Log.Debug("pinned avalanchego %s found. skipping installation", version)
+			return avagoDir, nil
+		}
+	} else {
+		exists, avagoDir, err := d.binChecker.ExistsWithLatestVersion(binDir, binPrefix)
+		if err != nil {
+			return "", fmt.Errorf("failed trying to locate avalanchego binary: %s", binDir)
+		}
+		if exists {
+			d.app.Log.Debug("local avalanchego found. skipping installation")
+			return avagoDir, nil
+		}
 	}
-	defer resp.Body.Close()
 
-	archive, err := io.ReadAll(resp.Body)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgInstallingAvalanchego))
+
+	d.app.Log.Info("Avalanchego version is: %s", version)
+
+	avagoDir, err := binutils.DownloadAvalancheGo(d.app.Log, version, binDir)
 	if err != nil {
 		return "", err
 	}
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgAvalanchegoInstalled))
+	return avagoDir, nil
+}
 
-	d.app.Log.Debug("download successful. installing archive...")
-	if err := binutils.InstallArchive(ext, archive, binDir); err != nil {
-		return "", err
+// WaitForHealthy polls continuously until the network is ready to be used
+func (d *LocalSubnetDeployer) WaitForHealthy(
+	ctx context.Context,
+	cli client.Client,
+	healthCheckInterval time.Duration,
+) (*rpcpb.ClusterInfo, error) {
+	return d.waitForHealthy(ctx, cli, healthCheckInterval, 0)
+}
+
+// waitForHealthyPhase waits for the network to be healthy, showing the progress
+// against the historical duration of the given phase, and records how long it took
+func (d *LocalSubnetDeployer) waitForHealthyPhase(
+	ctx context.Context,
+	cli client.Client,
+	phase DeployPhase,
+) (*rpcpb.ClusterInfo, error) {
+	eta, ok := d.timings.Estimate(phase)
+	if !ok && phase == PhaseBootstrap {
+		eta = DetectMachineClass().Bootstrap
 	}
-	avagoSubDir := "avalanchego-" + version
-	if ext == zipExtension {
-		// zip contains a build subdir instead of the avagoSubDir expected from tar.gz
-		if err := os.Rename(filepath.Join(binDir, "build"), filepath.Join(binDir, avagoSubDir)); err != nil {
-			return "", err
-		}
+	start := time.Now()
+	clusterInfo, err := d.waitForHealthy(ctx, cli, d.healthCheckInterval, eta)
+	if err != nil {
+		return nil, err
 	}
-	ux.Logger.PrintToUser("Avalanchego installation successful")
-	return filepath.Join(binDir, avagoSubDir), nil
+	d.recordPhase(phase, time.Since(start))
+	return clusterInfo, nil
 }
 
-// WaitForHealthy polls continuously until the network is ready to be used
-func (d *LocalSubnetDeployer) WaitForHealthy(
+// waitForHealthy polls until the network is healthy. If eta is known,
+// progress is shown against it, otherwise dots are printed.
+func (d *LocalSubnetDeployer) waitForHealthy(
 	ctx context.Context,
 	cli client.Client,
 	healthCheckInterval time.Duration,
+	eta time.Duration,
 ) (*rpcpb.ClusterInfo, error) {
+	defer timings.Track(timings.Health)()
 	cancel := make(chan struct{})
 	defer close(cancel)
-	go ux.PrintWait(cancel)
+	switch {
+	case d.streamingLogs:
+	case eta > 0:
+		go ux.PrintWaitWithETA(cancel, eta)
+	default:
+		go ux.PrintWait(cancel)
+	}
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, exitcodes.Unhealthy(ctx.Err())
 		case <-time.After(healthCheckInterval):
 			d.app.Log.Debug("polling for health...")
 			resp, err := cli.Health(ctx)
 			if err != nil {
-				return nil, fmt.Errorf("the health check failed to complete. The server might be down or have crashed, check the logs! %s", err)
+				return nil, exitcodes.Backend(fmt.Errorf("the health check failed to complete. The server might be down or have crashed, check the logs! %w", err))
 			}
 			if resp.ClusterInfo == nil {
 				d.app.Log.Debug("warning: ClusterInfo is nil. trying again...")
 				continue
 			}
-			if !resp.ClusterInfo.Healthy {
-				d.app.Log.Debug("network is not healthy. polling again...")
-				continue
-			}
-			if !resp.ClusterInfo.CustomVmsHealthy {
-				d.app.Log.Debug("network is up but custom VMs are not healthy. polling again...")
+			if health := NewClusterHealth(resp.ClusterInfo); !health.Live() {
+				d.app.Log.Debug("%s. polling again...", health.Reason)
 				continue
 			}
 			d.app.Log.Debug("network is up and custom VMs are up")
@@ -423,27 +831,59 @@ func (d *LocalSubnetDeployer) WaitForHealthy(
 	}
 }
 
-// GetEndpoints get a human readable list of endpoints from clusterinfo
+// GetEndpoints get a human readable list of endpoints from clusterinfo, both
+// by blockchain ID and by the alias set by AliasChains
 func GetEndpoints(clusterInfo *rpcpb.ClusterInfo) []string {
 	endpoints := []string{}
 	for _, nodeInfo := range clusterInfo.NodeInfos {
 		for blockchainID, vmInfo := range clusterInfo.CustomVms {
-			endpoints = append(endpoints, fmt.Sprintf("Endpoint at node %s for blockchain %q with VM ID %q: %s/ext/bc/%s/rpc", nodeInfo.Name, blockchainID, vmInfo.VmId, nodeInfo.GetUri(), blockchainID))
+			endpoints = append(endpoints, fmt.Sprintf("Endpoint at node %s for blockchain %q with VM ID %q: %s (WebSocket: %s), by name: %s (WebSocket: %s)",
+				nodeInfo.Name,
+				blockchainID,
+				vmInfo.VmId,
+				ux.RPCEndpoint(ux.HostURI(nodeInfo.GetUri()), blockchainID),
+				ux.WSEndpoint(ux.HostURI(nodeInfo.GetUri()), blockchainID),
+				ux.RPCEndpoint(ux.HostURI(nodeInfo.GetUri()), ChainAlias(vmInfo)),
+				ux.WSEndpoint(ux.HostURI(nodeInfo.GetUri()), ChainAlias(vmInfo)),
+			))
 		}
 	}
 	return endpoints
 }
 
-// return true if vm has already been deployed
-func alreadyDeployed(chainVMID ids.ID, clusterInfo *rpcpb.ClusterInfo) bool {
+// deployedChain returns the blockchain of the VM deployed on the network, or
+// nil if the VM has not been deployed yet
+func deployedChain(chainVMID ids.ID, clusterInfo *rpcpb.ClusterInfo) *rpcpb.CustomVmInfo {
 	if clusterInfo != nil {
 		for _, vmInfo := range clusterInfo.CustomVms {
 			if vmInfo.VmId == chainVMID.String() {
-				return true
+				return vmInfo
 			}
 		}
 	}
-	return false
+	return nil
+}
+
+// deployedIDs returns the subnet and blockchain IDs of a deployed blockchain,
+// as reported by the network, or else as recorded in the sidecar
+func deployedIDs(vmInfo *rpcpb.CustomVmInfo, recorded models.NetworkData) (ids.ID, ids.ID, error) {
+	subnetID, blockchainID := recorded.SubnetID, recorded.BlockchainID
+	if vmInfo.SubnetId != "" {
+		var err error
+		if subnetID, err = ids.FromString(vmInfo.SubnetId); err != nil {
+			return ids.Empty, ids.Empty, fmt.Errorf("invalid subnet ID %q reported by the network: %w", vmInfo.SubnetId, err)
+		}
+	}
+	if vmInfo.BlockchainId != "" {
+		var err error
+		if blockchainID, err = ids.FromString(vmInfo.BlockchainId); err != nil {
+			return ids.Empty, ids.Empty, fmt.Errorf("invalid blockchain ID %q reported by the network: %w", vmInfo.BlockchainId, err)
+		}
+	}
+	if subnetID == ids.Empty || blockchainID == ids.Empty {
+		return ids.Empty, ids.Empty, fmt.Errorf("the IDs of the blockchain of VM %s are unknown", vmInfo.VmId)
+	}
+	return subnetID, blockchainID, nil
 }
 
 // get list of all needed plugins and install them
@@ -455,13 +895,68 @@ func (d *LocalSubnetDeployer) installNeededPlugins(chainVMID ids.ID, clusterInfo
 			toInstallVMIDs[vmInfo.VmId] = struct{}{}
 		}
 	}
+	// the plugins claimed by the concurrent deploys are not cleaned up
+	claimed, err := claimedPlugins(pluginDir)
+	if err != nil {
+		return err
+	}
+	for vmID := range claimed {
+		if exists, _ := storage.FileExists(filepath.Join(pluginDir, vmID)); exists {
+			toInstallVMIDs[vmID] = struct{}{}
+		}
+	}
+	// the VM built from source is installed once the others are, as
+	// downloading them cleans up the plugins not downloaded
+	if d.vmSource != nil {
+		delete(toInstallVMIDs, chainVMID.String())
+	}
 	binDir := filepath.Join(d.app.GetBaseDir(), constants.AvalancheCliBinDir)
 	if err := d.binaryDownloader.Download(toInstallVMIDs, pluginDir, binDir); err != nil {
 		return err
 	}
+	if d.vmSource != nil {
+		return binutils.InstallVMFromSource(d.app.Log, *d.vmSource, chainVMID.String(), pluginDir, binDir)
+	}
 	return nil
 }
 
+// smokeTest checks the RPC of the deployed chain works on every node, and
+// reports the result for each of them. The funded ewoq account, if any, is
+// used to also send a transaction.
+func (d *LocalSubnetDeployer) smokeTest(clusterInfo *rpcpb.ClusterInfo, blockchainID ids.ID, genesis core.Genesis) {
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		d.app.Log.Warn("the genesis has no chain ID, skipping the RPC smoke test")
+		return
+	}
+	var sk *ecdsa.PrivateKey
+	if account, ok := genesis.Alloc[vm.PrefundedEwoqAddress]; ok && account.Balance != nil && account.Balance.Sign() > 0 {
+		var err error
+		sk, err = crypto.HexToECDSA(vm.PrefundedEwoqPrivate)
+		if err != nil {
+			d.app.Log.Warn("failed loading the ewoq key, skipping the smoke test transaction: %s", err)
+		}
+	}
+
+	fmt.Println()
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgSmokeTestRunning))
+	ctx, cancel := context.WithTimeout(context.Background(), constants.SmokeTestTimeout)
+	defer cancel()
+	results := RunSmokeTest(
+		ctx,
+		GetNodeRPCURLs(clusterInfo, blockchainID.String()),
+		genesis.Config.ChainID,
+		sk,
+		constants.SmokeTestRequestInterval,
+	)
+	for _, r := range results {
+		if r.Err != nil {
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgSmokeTestFailed), r.Node, r.Err)
+		} else {
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgSmokeTestPassed), r.Node)
+		}
+	}
+}
+
 // getGenesis extracts the chain genesis from the provided genesis file
 // we don't need to check the existence of the file as we already did before
 // TODO: We should probably store this in some global object when asking the user so we don't need
@@ -481,35 +976,105 @@ func getGenesis(genesisFile string) (core.Genesis, error) {
 // Initialize default snapshot with bootstrap snapshot archive
 // If force flag is set to true, overwrite the default snapshot if it exists
 func SetDefaultSnapshot(snapshotsDir string, force bool) error {
+	return SetDefaultSnapshotConfirmed(snapshotsDir, force, nil)
+}
+
+// SetDefaultSnapshotConfirmed is SetDefaultSnapshot calling confirm, if not
+// nil, with the size of the bootstrap snapshot before downloading it
+func SetDefaultSnapshotConfirmed(snapshotsDir string, force bool, confirm DownloadConfirmFunc) error {
 	bootstrapSnapshotArchivePath := filepath.Join(snapshotsDir, constants.BootstrapSnapshotArchiveName)
 	if _, err := os.Stat(bootstrapSnapshotArchivePath); os.IsNotExist(err) {
-		resp, err := http.Get(constants.BootstrapSnapshotURL)
+		if confirm != nil {
+			// the size is only informative, the download goes on without it
+			size, err := binutils.GithubContentLength(constants.BootstrapSnapshotURL)
+			if err != nil {
+				size = -1
+			}
+			if err := confirm("bootstrap snapshot", size); err != nil {
+				return err
+			}
+		}
+		resp, err := binutils.GithubGet(constants.BootstrapSnapshotURL, "")
 		if err != nil {
 			return fmt.Errorf("failed downloading bootstrap snapshot: %w", err)
 		}
+		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
 			return fmt.Errorf("failed downloading bootstrap snapshot: unexpected http status code: %d", resp.StatusCode)
 		}
-		defer resp.Body.Close()
-		bootstrapSnapshotBytes, err := io.ReadAll(resp.Body)
+		downloadPath, err := binutils.DownloadToTempFile(resp.Body, snapshotsDir)
 		if err != nil {
 			return fmt.Errorf("failed downloading bootstrap snapshot: %w", err)
 		}
-		if err := os.WriteFile(bootstrapSnapshotArchivePath, bootstrapSnapshotBytes, WriteReadReadPerms); err != nil {
+		if err := os.Rename(downloadPath, bootstrapSnapshotArchivePath); err != nil {
 			return fmt.Errorf("failed writing down bootstrap snapshot: %w", err)
 		}
 	}
-	defaultSnapshotPath := filepath.Join(snapshotsDir, "anr-snapshot-"+constants.DefaultSnapshotName)
+	defaultSnapshotPath := filepath.Join(snapshotsDir, snapshotPrefix+constants.DefaultSnapshotName)
 	if force {
 		os.RemoveAll(defaultSnapshotPath)
 	}
 	if _, err := os.Stat(defaultSnapshotPath); os.IsNotExist(err) {
-		bootstrapSnapshotBytes, err := os.ReadFile(bootstrapSnapshotArchivePath)
+		if err := binutils.InstallArchive(bootstrapSnapshotArchivePath, snapshotsDir); err != nil {
+			return fmt.Errorf("failed installing bootstrap snapshot: %w", err)
+		}
+		// the default snapshot is now the bootstrap one, recorded or not
+		defaultRecordPath := snapshotRecordPath(snapshotsDir, constants.DefaultSnapshotName)
+		recordBytes, err := os.ReadFile(filepath.Join(snapshotsDir, constants.BootstrapSnapshotRecordName))
+		switch {
+		case err == nil:
+			if err := os.WriteFile(defaultRecordPath, recordBytes, WriteReadReadPerms); err != nil {
+				return err
+			}
+		case os.IsNotExist(err):
+			if err := os.Remove(defaultRecordPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseSnapshotNodePorts removes the fixed API and staking ports of the nodes
+// of all snapshots in snapshotsDir, so that the network runner assigns free
+// ports when loading them
+func ReleaseSnapshotNodePorts(snapshotsDir string) error {
+	networkFiles, err := filepath.Glob(filepath.Join(snapshotsDir, "anr-snapshot-*", "network.json"))
+	if err != nil {
+		return err
+	}
+	for _, networkFile := range networkFiles {
+		networkBytes, err := os.ReadFile(networkFile)
 		if err != nil {
-			return fmt.Errorf("failed reading bootstrap snapshot: %w", err)
+			return fmt.Errorf("failed reading snapshot network file %s: %w", networkFile, err)
 		}
-		if err := binutils.InstallArchive("tar.gz", bootstrapSnapshotBytes, snapshotsDir); err != nil {
-			return fmt.Errorf("failed installing bootstrap snapshot: %w", err)
+		var network map[string]interface{}
+		if err := json.Unmarshal(networkBytes, &network); err != nil {
+			return fmt.Errorf("failed unmarshalling snapshot network file %s: %w", networkFile, err)
+		}
+		nodeConfigs, _ := network["nodeConfigs"].([]interface{})
+		changed := false
+		for _, nodeConfig := range nodeConfigs {
+			nodeConfigMap, _ := nodeConfig.(map[string]interface{})
+			flags, _ := nodeConfigMap["flags"].(map[string]interface{})
+			for _, portFlag := range []string{"http-port", "staking-port"} {
+				if _, ok := flags[portFlag]; ok {
+					delete(flags, portFlag)
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+		networkBytes, err = json.MarshalIndent(network, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(networkFile, networkBytes, WriteReadReadPerms); err != nil {
+			return fmt.Errorf("failed writing snapshot network file %s: %w", networkFile, err)
 		}
 	}
 	return nil
@@ -523,20 +1088,29 @@ func (d *LocalSubnetDeployer) startNetwork(
 	pluginDir string,
 	runDir string,
 ) error {
-	ux.Logger.PrintToUser("Starting network...")
+	// the nodes would crash loop on a snapshot they can't load
+	if err := d.RepairDefaultSnapshot(); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgStartingNetwork))
 	loadSnapshotOpts := []client.OpOption{
 		client.WithPluginDir(pluginDir),
 		client.WithExecPath(avalancheGoBinPath),
 		client.WithRootDataDir(runDir),
 	}
 
-	// load global node configs if they exist
-	configStr, err := d.app.Conf.LoadNodeConfig()
+	// load the node configs if they exist
+	nodeConfigOpts, err := LocalNodeConfigOptions(d.app, constants.DefaultSnapshotName)
+	if err != nil {
+		return err
+	}
+	loadSnapshotOpts = append(loadSnapshotOpts, nodeConfigOpts...)
+	chainConfigs, err := LocalChainConfigs(d.app)
 	if err != nil {
 		return err
 	}
-	if configStr != "" {
-		loadSnapshotOpts = append(loadSnapshotOpts, client.WithGlobalNodeConfig(configStr))
+	if len(chainConfigs) > 0 {
+		loadSnapshotOpts = append(loadSnapshotOpts, client.WithChainConfigs(chainConfigs))
 	}
 
 	_, err = cli.LoadSnapshot(
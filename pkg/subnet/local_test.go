@@ -3,6 +3,7 @@
 package subnet
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/client"
 	"github.com/ava-labs/avalanche-network-runner/rpcpb"
@@ -94,9 +96,8 @@ func TestDeployToLocal(t *testing.T) {
 	binDownloader := &mocks.PluginBinaryDownloader{}
 	binDownloader.On("Download", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
-	app := &application.Avalanche{
-		Log: logging.NoLog{},
-	}
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
 
 	testDeployer := &LocalSubnetDeployer{
 		procChecker:         procChecker,
@@ -117,10 +118,91 @@ func TestDeployToLocal(t *testing.T) {
 	err = os.WriteFile(testGenesis.Name(), []byte(genesis), constants.DefaultPerms755)
 	assert.NoError(err)
 	// test actual deploy
-	s, b, err := testDeployer.DeployToLocalNetwork(testVMName, testGenesis.Name())
+	s, b, err := testDeployer.DeployToLocalNetwork(models.Sidecar{Name: testVMName}, testGenesis.Name())
+	assert.NoError(err)
+	assert.Equal(testSubnetID2, s.String())
+	assert.Equal(testBlockChainID2, b.String())
+}
+
+func TestDeployResumesAfterFailedChainCreate(t *testing.T) {
+	assert := setupTest(t)
+
+	procChecker := &mocks.ProcessChecker{}
+	procChecker.On("IsServerProcessRunning", mock.Anything).Return(true, nil)
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "plugins")
+	assert.NoError(os.Mkdir(pluginDir, perms.ReadWriteExecute))
+	assert.NoError(os.WriteFile(filepath.Join(tmpDir, "avalanchego"), nil, perms.ReadWriteExecute))
+	// the plugin installed by the failed deploy
+	assert.NoError(os.WriteFile(filepath.Join(pluginDir, testVMID), nil, perms.ReadWriteExecute))
+
+	binChecker := &mocks.BinaryChecker{}
+	binChecker.On("ExistsWithLatestVersion", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(true, tmpDir, nil)
+	binDownloader := &mocks.PluginBinaryDownloader{}
+	binDownloader.On("Download", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+
+	failingClient := &mocks.Client{}
+	failingClient.On("Health", mock.Anything).Return(fakeHealthResponse, nil)
+	failingClient.On("Status", mock.Anything).Return(&rpcpb.StatusResponse{ClusterInfo: fakeHealthResponse.ClusterInfo}, nil)
+	failingClient.On("CreateBlockchains", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("creation failed"))
+	failingClient.On("Close").Return(nil)
+
+	testDeployer := &LocalSubnetDeployer{
+		procChecker:         procChecker,
+		binChecker:          binChecker,
+		getClientFunc:       func() (client.Client, error) { return failingClient, nil },
+		binaryDownloader:    binDownloader,
+		healthCheckInterval: 10 * time.Millisecond,
+		app:                 app,
+		setDefaultSnapshot:  fakeSetDefaultSnapshot,
+	}
+
+	genesisPath := filepath.Join(tmpDir, "test-genesis.json")
+	genesis := `{"config":{"chainId":9999},"gasLimit":"0x0","difficulty":"0x0","alloc":{}}`
+	assert.NoError(os.WriteFile(genesisPath, []byte(genesis), constants.DefaultPerms755))
+
+	_, _, err := testDeployer.DeployToLocalNetwork(models.Sidecar{Name: testVMName}, genesisPath)
+	assert.ErrorContains(err, "creation failed")
+	assert.FileExists(app.GetDeployCheckpointPath(testVMName))
+
+	// the env setup and the plugins are not redone
+	testDeployer.getClientFunc = getTestClientFunc
+	s, b, err := testDeployer.DeployToLocalNetwork(models.Sidecar{Name: testVMName}, genesisPath)
 	assert.NoError(err)
 	assert.Equal(testSubnetID2, s.String())
 	assert.Equal(testBlockChainID2, b.String())
+	binChecker.AssertNumberOfCalls(t, "ExistsWithLatestVersion", 1)
+	binDownloader.AssertNumberOfCalls(t, "Download", 1)
+	assert.NoFileExists(app.GetDeployCheckpointPath(testVMName))
+}
+
+func TestDeployedIDs(t *testing.T) {
+	assert := setupTest(t)
+
+	subnetID := ids.GenerateTestID()
+	blockchainID := ids.GenerateTestID()
+	recorded := models.NetworkData{SubnetID: ids.GenerateTestID(), BlockchainID: ids.GenerateTestID()}
+
+	// the IDs reported by the network win
+	s, b, err := deployedIDs(&rpcpb.CustomVmInfo{SubnetId: subnetID.String(), BlockchainId: blockchainID.String()}, recorded)
+	assert.NoError(err)
+	assert.Equal(subnetID, s)
+	assert.Equal(blockchainID, b)
+
+	// the ones recorded in the sidecar are used otherwise
+	s, b, err = deployedIDs(&rpcpb.CustomVmInfo{BlockchainId: blockchainID.String()}, recorded)
+	assert.NoError(err)
+	assert.Equal(recorded.SubnetID, s)
+	assert.Equal(blockchainID, b)
+
+	_, _, err = deployedIDs(&rpcpb.CustomVmInfo{}, models.NetworkData{})
+	assert.Error(err)
+	_, _, err = deployedIDs(&rpcpb.CustomVmInfo{SubnetId: "invalid"}, recorded)
+	assert.Error(err)
 }
 
 func TestExistsWithLatestVersion(t *testing.T) {
@@ -202,7 +284,9 @@ func TestGetLatestAvagoVersion(t *testing.T) {
 	s := httptest.NewServer(testHandler)
 	defer s.Close()
 
-	v, err := binutils.GetLatestReleaseVersion(s.URL)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+	v, err := binutils.GetLatestReleaseVersion(app, s.URL)
 	assert.NoError(err)
 	assert.Equal(v, testVersion)
 }
@@ -218,6 +302,7 @@ func getTestClientFunc() (client.Client, error) {
 	c.On("RemoveSnapshot", mock.Anything, mock.Anything).Return(fakeRemoveSnapshotResponse, nil)
 	c.On("CreateBlockchains", mock.Anything, mock.Anything, mock.Anything).Return(fakeCreateBlockchainsResponse, nil)
 	c.On("URIs", mock.Anything).Return([]string{"fakeUri"}, nil)
+	c.On("Status", mock.Anything).Return(&rpcpb.StatusResponse{ClusterInfo: fakeHealthResponse.ClusterInfo}, nil)
 	// When fake deploying, the first response needs to have a bogus subnet ID, because
 	// otherwise the doDeploy function "aborts" when checking if the subnet had already been deployed.
 	// Afterwards, we can set the actual VM ID so that the test returns an expected subnet ID...
@@ -0,0 +1,193 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/observability"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/coreth/ethclient"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// number of blocks the gas used per second is averaged over
+	gasRateBlocks = 10
+
+	peersMetric         = "avalanche_network_peers"
+	blockHeightMetric   = "last_accepted_height"
+	txPoolPendingMetric = "vm_txpool_pending"
+	txPoolQueuedMetric  = "vm_txpool_queued"
+)
+
+// ChainMetrics are the key runtime metrics of a chain on one node
+type ChainMetrics struct {
+	Node          string
+	BlockHeight   float64
+	GasPerSecond  float64
+	TxPoolPending float64
+	TxPoolQueued  float64
+	Peers         float64
+}
+
+// chainMetricName returns the name of a metric of the given blockchain, as
+// avalanchego namespaces chain metrics by blockchain ID
+func chainMetricName(blockchainID string, name string) string {
+	return fmt.Sprintf("avalanche_%s_%s", blockchainID, name)
+}
+
+// ScrapeNodeMetrics fetches the Prometheus metrics of the node at nodeURI
+func ScrapeNodeMetrics(ctx context.Context, nodeURI string) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nodeURI+observability.MetricsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed scraping metrics of %s: %w", nodeURI, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed scraping metrics of %s: unexpected http status code: %d", nodeURI, resp.StatusCode)
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing metrics of %s: %w", nodeURI, err)
+	}
+	return families, nil
+}
+
+// metricValue returns the value of the first sample of the gauge or counter
+// named name, or 0 if the node doesn't expose it
+func metricValue(families map[string]*dto.MetricFamily, name string) float64 {
+	family, ok := families[name]
+	if !ok || len(family.GetMetric()) == 0 {
+		return 0
+	}
+	metric := family.GetMetric()[0]
+	switch {
+	case metric.GetGauge() != nil:
+		return metric.GetGauge().GetValue()
+	case metric.GetCounter() != nil:
+		return metric.GetCounter().GetValue()
+	case metric.GetUntyped() != nil:
+		return metric.GetUntyped().GetValue()
+	}
+	return 0
+}
+
+// FilterChainMetrics extracts the metrics of the given blockchain from the
+// metrics scraped from a node
+func FilterChainMetrics(node string, families map[string]*dto.MetricFamily, blockchainID string) ChainMetrics {
+	return ChainMetrics{
+		Node:          node,
+		BlockHeight:   metricValue(families, chainMetricName(blockchainID, blockHeightMetric)),
+		TxPoolPending: metricValue(families, chainMetricName(blockchainID, txPoolPendingMetric)),
+		TxPoolQueued:  metricValue(families, chainMetricName(blockchainID, txPoolQueuedMetric)),
+		Peers:         metricValue(families, peersMetric),
+	}
+}
+
+// GetGasPerSecond returns the gas used per second by the EVM chain at rpcURL,
+// averaged over its last blocks. subnet-evm doesn't expose a gas metric.
+func GetGasPerSecond(ctx context.Context, rpcURL string) (float64, error) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	last, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed getting the last block: %w", err)
+	}
+	lastNumber := last.Number.Uint64()
+	if lastNumber == 0 {
+		return 0, nil
+	}
+	first := last
+	gasUsed := last.GasUsed
+	for n := lastNumber - 1; n > 0 && lastNumber-n < gasRateBlocks; n-- {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return 0, fmt.Errorf("failed getting block %d: %w", n, err)
+		}
+		gasUsed += header.GasUsed
+		first = header
+	}
+	// the time window starts at the parent of the first block counted
+	parent, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(first.Number.Uint64()-1))
+	if err != nil {
+		return 0, fmt.Errorf("failed getting block %d: %w", first.Number.Uint64()-1, err)
+	}
+	elapsed := last.Time - parent.Time
+	if elapsed == 0 {
+		return 0, nil
+	}
+	return float64(gasUsed) / float64(elapsed), nil
+}
+
+// GetChainMetrics scrapes the metrics of the given blockchain on every node of
+// the cluster. Nodes which can't be scraped are reported in the returned map
+// of errors by node name.
+func GetChainMetrics(ctx context.Context, clusterInfo *rpcpb.ClusterInfo, blockchainID string) ([]ChainMetrics, map[string]error) {
+	nodes := make([]string, 0, len(clusterInfo.NodeInfos))
+	for name := range clusterInfo.NodeInfos {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+
+	rpcURLs := GetNodeRPCURLs(clusterInfo, blockchainID)
+	metrics := []ChainMetrics{}
+	errs := map[string]error{}
+	for _, name := range nodes {
+		families, err := ScrapeNodeMetrics(ctx, clusterInfo.NodeInfos[name].GetUri())
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		m := FilterChainMetrics(name, families, blockchainID)
+		m.GasPerSecond, err = GetGasPerSecond(ctx, rpcURLs[name])
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, errs
+}
+
+// PrometheusScrapeConfig returns a Prometheus configuration scraping the
+// metrics of the given blockchain, and the peers count, from every node of
+// the cluster
+func PrometheusScrapeConfig(clusterInfo *rpcpb.ClusterInfo, chainName string, blockchainID string) ([]byte, error) {
+	nodeURIs := map[string]string{}
+	for name, nodeInfo := range clusterInfo.NodeInfos {
+		nodeURIs[name] = nodeInfo.GetUri()
+	}
+	staticConfigs, err := observability.NodeStaticConfigs(nodeURIs, "", map[string]string{"chain": chainName})
+	if err != nil {
+		return nil, err
+	}
+	conf := observability.PrometheusConfig{
+		ScrapeConfigs: []observability.PrometheusScrapeConfig{{
+			JobName:       "avalanchego-" + chainName,
+			MetricsPath:   observability.MetricsPath,
+			StaticConfigs: staticConfigs,
+			MetricRelabelConfigs: []observability.PrometheusRelabel{{
+				SourceLabels: []string{"__name__"},
+				Regex:        fmt.Sprintf("%s|avalanche_%s_.*", peersMetric, blockchainID),
+				Action:       "keep",
+			}},
+		}},
+	}
+	return yaml.Marshal(conf)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/observability"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestScrapeAndFilterChainMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	otherChain := "2Z36RnQuk1hvsnFeGWzfZUfXNr7w1SjzmDQ78YxfTVNAkDq3nZ"
+	body := fmt.Sprintf(`# TYPE avalanche_network_peers gauge
+avalanche_network_peers 4
+# TYPE avalanche_%[1]s_last_accepted_height gauge
+avalanche_%[1]s_last_accepted_height 42
+# TYPE avalanche_%[1]s_vm_txpool_pending gauge
+avalanche_%[1]s_vm_txpool_pending 3
+# TYPE avalanche_%[2]s_last_accepted_height gauge
+avalanche_%[2]s_last_accepted_height 1000
+`, testBlockChainID1, otherChain)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != observability.MetricsPath {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer s.Close()
+
+	families, err := ScrapeNodeMetrics(context.Background(), s.URL)
+	assert.NoError(err)
+
+	m := FilterChainMetrics("node1", families, testBlockChainID1)
+	assert.Equal(ChainMetrics{
+		Node:          "node1",
+		BlockHeight:   42,
+		TxPoolPending: 3,
+		Peers:         4,
+	}, m)
+
+	_, err = ScrapeNodeMetrics(context.Background(), s.URL+"/wrong")
+	assert.Error(err)
+}
+
+func TestPrometheusScrapeConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node2": {Name: "node2", Uri: "http://127.0.0.1:9652"},
+			"node1": {Name: "node1", Uri: "http://127.0.0.1:9650"},
+		},
+	}
+	confBytes, err := PrometheusScrapeConfig(clusterInfo, "test", testBlockChainID1)
+	assert.NoError(err)
+
+	var conf observability.PrometheusConfig
+	assert.NoError(yaml.Unmarshal(confBytes, &conf))
+	assert.Len(conf.ScrapeConfigs, 1)
+	scrape := conf.ScrapeConfigs[0]
+	assert.Equal(observability.MetricsPath, scrape.MetricsPath)
+	assert.Equal([]observability.PrometheusStaticConfig{
+		{Targets: []string{"127.0.0.1:9650"}, Labels: map[string]string{"node": "node1", "chain": "test"}},
+		{Targets: []string{"127.0.0.1:9652"}, Labels: map[string]string{"node": "node2", "chain": "test"}},
+	}, scrape.StaticConfigs)
+	assert.Equal("avalanche_network_peers|avalanche_"+testBlockChainID1+"_.*", scrape.MetricRelabelConfigs[0].Regex)
+}
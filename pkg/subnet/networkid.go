@@ -0,0 +1,94 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/local"
+	"github.com/ava-labs/avalanche-network-runner/network"
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+// genesisSnapshotName is the snapshot of a network with a custom network ID
+// and no state, which the bootstrap snapshot is rebuilt from
+const genesisSnapshotName = constants.DefaultSnapshotName + "-genesis"
+
+// CheckLocalNetworkID returns an error if the local network can't run with
+// networkID, which must not be the one of a network with a genesis built in
+// avalanchego
+func CheckLocalNetworkID(networkID uint32) error {
+	if networkID == 0 {
+		return errors.New("the network ID must be positive")
+	}
+	if name, ok := avago_constants.NetworkIDToNetworkName[networkID]; ok {
+		return fmt.Errorf("network ID %d is the one of %s, whose genesis can't be replaced", networkID, name)
+	}
+	return nil
+}
+
+// LocalNetworkID returns the network ID of the local network of app, as set
+// in the genesis of its default snapshot, or the one of the network runner if
+// the snapshot isn't installed yet
+func LocalNetworkID(app *application.Avalanche) (uint32, error) {
+	networkFile := filepath.Join(app.GetSnapshotsDir(), snapshotPrefix+constants.DefaultSnapshotName, "network.json")
+	networkBytes, err := os.ReadFile(networkFile)
+	if os.IsNotExist(err) {
+		return constants.LocalNetworkID, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var networkConfig network.Config
+	if err := json.Unmarshal(networkBytes, &networkConfig); err != nil {
+		return 0, fmt.Errorf("failed unmarshalling snapshot network file %s: %w", networkFile, err)
+	}
+	networkID, err := utils.NetworkIDFromGenesis([]byte(networkConfig.Genesis))
+	if err != nil {
+		return 0, fmt.Errorf("failed reading the network ID of snapshot network file %s: %w", networkFile, err)
+	}
+	return networkID, nil
+}
+
+// writeGenesisSnapshot writes the snapshot named genesisSnapshotName of the
+// default network of the network runner with networkID, whose nodes start
+// from an empty database, run with avalancheGoBinPath
+func writeGenesisSnapshot(snapshotsDir string, avalancheGoBinPath string, networkID uint32) error {
+	networkConfig := local.NewDefaultConfig(avalancheGoBinPath)
+	var genesis map[string]interface{}
+	if err := json.Unmarshal([]byte(networkConfig.Genesis), &genesis); err != nil {
+		return fmt.Errorf("failed unmarshalling the default genesis: %w", err)
+	}
+	genesis["networkID"] = networkID
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		return err
+	}
+	networkConfig.Genesis = string(genesisBytes)
+
+	snapshotDir := filepath.Join(snapshotsDir, snapshotPrefix+genesisSnapshotName)
+	if err := os.RemoveAll(snapshotDir); err != nil {
+		return err
+	}
+	// the network runner copies the database of each node from the snapshot,
+	// by node name, which it only assigns to the nodes of a clean network
+	for i := range networkConfig.NodeConfigs {
+		networkConfig.NodeConfigs[i].Name = fmt.Sprintf("node%d", i+1)
+		if err := os.MkdirAll(filepath.Join(snapshotDir, "db", networkConfig.NodeConfigs[i].Name), perms.ReadWriteExecute); err != nil {
+			return err
+		}
+	}
+	networkBytes, err := json.MarshalIndent(networkConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(snapshotDir, "network.json"), networkBytes, perms.ReadWrite)
+}
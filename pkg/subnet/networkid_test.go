@@ -0,0 +1,74 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckLocalNetworkID(t *testing.T) {
+	assert := setupTest(t)
+
+	assert.NoError(CheckLocalNetworkID(1337))
+	assert.NoError(CheckLocalNetworkID(4242))
+	assert.Error(CheckLocalNetworkID(0))
+	assert.Error(CheckLocalNetworkID(avago_constants.MainnetID))
+	assert.Error(CheckLocalNetworkID(avago_constants.FujiID))
+	assert.Error(CheckLocalNetworkID(avago_constants.LocalID))
+}
+
+func TestRebuildDefaultSnapshotNetworkID(t *testing.T) {
+	assert := setupTest(t)
+
+	cli := &mocks.Client{}
+	var d *LocalSubnetDeployer
+	cli.On("Status", mock.Anything).Return(nil, errors.New("not bootstrapped"))
+	cli.On("LoadSnapshot", mock.Anything, genesisSnapshotName, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// the genesis snapshot of the network ID has a database for every node
+		snapshotDir := filepath.Join(d.app.GetSnapshotsDir(), snapshotPrefix+genesisSnapshotName)
+		for _, node := range []string{"node1", "node5"} {
+			_, err := os.Stat(filepath.Join(snapshotDir, "db", node))
+			assert.NoError(err)
+		}
+	}).Return(&rpcpb.LoadSnapshotResponse{}, nil)
+	cli.On("CreateSubnets", mock.Anything, mock.Anything).Return(&rpcpb.CreateSubnetsResponse{}, nil)
+	cli.On("Health", mock.Anything).Return(fakeHealthResponse, nil)
+	cli.On("RemoveSnapshot", mock.Anything, rebuiltSnapshotName).Return(nil, errors.New("does not exist"))
+	cli.On("SaveSnapshot", mock.Anything, rebuiltSnapshotName).Run(func(args mock.Arguments) {
+		// the network runner saves the network config it loaded
+		snapshotsDir := d.app.GetSnapshotsDir()
+		networkBytes, err := os.ReadFile(filepath.Join(snapshotsDir, snapshotPrefix+genesisSnapshotName, "network.json"))
+		assert.NoError(err)
+		dir := filepath.Join(snapshotsDir, snapshotPrefix+rebuiltSnapshotName)
+		assert.NoError(os.MkdirAll(dir, perms.ReadWriteExecute))
+		assert.NoError(os.WriteFile(filepath.Join(dir, "network.json"), networkBytes, perms.ReadWrite))
+	}).Return(&rpcpb.SaveSnapshotResponse{}, nil)
+	cli.On("Close").Return(nil)
+	d = newRebuildTestDeployer(t, cli, SetDefaultSnapshot)
+
+	networkID, err := LocalNetworkID(d.app)
+	assert.NoError(err)
+	assert.Equal(uint32(constants.LocalNetworkID), networkID)
+
+	assert.ErrorContains(d.RebuildDefaultSnapshot(2, avago_constants.FujiID), "genesis can't be replaced")
+	cli.AssertNotCalled(t, "LoadSnapshot", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.NoError(d.RebuildDefaultSnapshot(2, 4242))
+	cli.AssertNotCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	networkID, err = LocalNetworkID(d.app)
+	assert.NoError(err)
+	assert.Equal(uint32(4242), networkID)
+	// the genesis snapshot is only needed to start the network
+	_, err = os.Stat(filepath.Join(d.app.GetSnapshotsDir(), snapshotPrefix+genesisSnapshotName))
+	assert.True(os.IsNotExist(err))
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-network-runner/client"
+)
+
+// nodeConfigsRecordPrefix prefixes the files recording the settings written
+// to the network file of a snapshot, kept next to the snapshots as the
+// directory of a snapshot is replaced whenever the network is saved
+const nodeConfigsRecordPrefix = "node-configs-"
+
+// recordedSetting is the value a setting of a node had in the network file
+// of a snapshot before the CLI overrode it
+type recordedSetting struct {
+	Value  interface{} `json:"value,omitempty"`
+	Absent bool        `json:"absent,omitempty"`
+}
+
+// nodeConfigsRecord holds the recorded settings by node name, then setting
+type nodeConfigsRecord map[string]map[string]recordedSetting
+
+// LocalNodeConfigOptions returns the options of the network runner setting
+// the node configs of the local network loaded from snapshotName. The network
+// runner sets the same config on all the nodes of a loaded snapshot, so when
+// settings are overridden per node with node-configs, they are written to the
+// network file of the snapshot along with the global ones instead. The
+// settings written by a previous run are reverted first.
+func LocalNodeConfigOptions(app *application.Avalanche, snapshotName string) ([]client.OpOption, error) {
+	if err := app.Conf.CheckNodeProfile(); err != nil {
+		return nil, exitcodes.UserInput(err)
+	}
+	nodeConfigs, err := app.Conf.NodeConfigs()
+	if err != nil {
+		return nil, exitcodes.UserInput(err)
+	}
+	globalConfig := app.Conf.GlobalNodeConfig()
+	if len(nodeConfigs) == 0 {
+		// settings written to the snapshot by a previous run are reverted
+		nodeConfigs = nil
+	}
+	snapshotsDir := app.GetSnapshotsDir()
+	if err := applySnapshotNodeConfigs(
+		filepath.Join(snapshotsDir, snapshotPrefix+snapshotName, "network.json"),
+		filepath.Join(snapshotsDir, nodeConfigsRecordPrefix+snapshotName+".json"),
+		globalConfig,
+		nodeConfigs,
+	); err != nil {
+		return nil, err
+	}
+	if nodeConfigs != nil || len(globalConfig) == 0 {
+		return nil, nil
+	}
+	configStr, err := app.Conf.LoadNodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	return []client.OpOption{client.WithGlobalNodeConfig(configStr)}, nil
+}
+
+// applySnapshotNodeConfigs reverts the settings recorded in recordFile in the
+// network file of a snapshot, then, unless nodeConfigs is nil, writes the
+// global settings merged with the ones of each node to it, and records them
+func applySnapshotNodeConfigs(
+	networkFile string,
+	recordFile string,
+	globalConfig map[string]interface{},
+	nodeConfigs map[string]map[string]interface{},
+) error {
+	record := nodeConfigsRecord{}
+	recordBytes, err := os.ReadFile(recordFile)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(recordBytes, &record); err != nil {
+			return fmt.Errorf("failed unmarshalling node configs record %s: %w", recordFile, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	if len(record) == 0 && nodeConfigs == nil {
+		return nil
+	}
+
+	networkBytes, err := os.ReadFile(networkFile)
+	if err != nil {
+		return fmt.Errorf("failed reading snapshot network file %s: %w", networkFile, err)
+	}
+	var network map[string]interface{}
+	if err := json.Unmarshal(networkBytes, &network); err != nil {
+		return fmt.Errorf("failed unmarshalling snapshot network file %s: %w", networkFile, err)
+	}
+	nodeFlags := map[string]map[string]interface{}{}
+	nodes, _ := network["nodeConfigs"].([]interface{})
+	for _, node := range nodes {
+		nodeMap, _ := node.(map[string]interface{})
+		name, _ := nodeMap["name"].(string)
+		if nodeMap == nil || name == "" {
+			continue
+		}
+		flags, _ := nodeMap["flags"].(map[string]interface{})
+		if flags == nil {
+			flags = map[string]interface{}{}
+			nodeMap["flags"] = flags
+		}
+		nodeFlags[name] = flags
+	}
+
+	for name, settings := range record {
+		flags, ok := nodeFlags[name]
+		if !ok {
+			continue
+		}
+		for key, original := range settings {
+			if original.Absent {
+				delete(flags, key)
+			} else {
+				flags[key] = original.Value
+			}
+		}
+	}
+
+	record = nodeConfigsRecord{}
+	if nodeConfigs != nil {
+		names := make([]string, 0, len(nodeFlags))
+		for name := range nodeFlags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for name := range nodeConfigs {
+			if _, ok := nodeFlags[name]; !ok {
+				return exitcodes.UserInput(fmt.Errorf("node-configs sets the config of node %s, which is not one of the nodes of the local network: %s",
+					name, strings.Join(names, ", ")))
+			}
+		}
+		for _, name := range names {
+			flags := nodeFlags[name]
+			record[name] = map[string]recordedSetting{}
+			settings := map[string]interface{}{}
+			for key, value := range globalConfig {
+				settings[key] = value
+			}
+			for key, value := range nodeConfigs[name] {
+				settings[key] = value
+			}
+			for key, value := range settings {
+				original, ok := flags[key]
+				record[name][key] = recordedSetting{Value: original, Absent: !ok}
+				flags[key] = value
+			}
+		}
+	}
+
+	networkBytes, err = json.MarshalIndent(network, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(networkFile, networkBytes, WriteReadReadPerms); err != nil {
+		return fmt.Errorf("failed writing snapshot network file %s: %w", networkFile, err)
+	}
+	if len(record) == 0 {
+		if err := os.Remove(recordFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	recordBytes, err = json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(recordFile, recordBytes, WriteReadReadPerms)
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSnapshotNetwork = `{
+  "nodeConfigs": [
+    {"name": "node1", "flags": {"log-level": "warn", "db-dir": "/db1"}},
+    {"name": "node2", "flags": {"db-dir": "/db2"}},
+    {"name": "node3"}
+  ]
+}`
+
+func readSnapshotFlags(assert *assert.Assertions, networkFile string) map[string]map[string]interface{} {
+	networkBytes, err := os.ReadFile(networkFile)
+	assert.NoError(err)
+	var network struct {
+		NodeConfigs []struct {
+			Name  string                 `json:"name"`
+			Flags map[string]interface{} `json:"flags"`
+		} `json:"nodeConfigs"`
+	}
+	assert.NoError(json.Unmarshal(networkBytes, &network))
+	flags := map[string]map[string]interface{}{}
+	for _, node := range network.NodeConfigs {
+		flags[node.Name] = node.Flags
+	}
+	return flags
+}
+
+func TestApplySnapshotNodeConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	networkFile := filepath.Join(dir, "network.json")
+	recordFile := filepath.Join(dir, "node-configs-test.json")
+	assert.NoError(os.WriteFile(networkFile, []byte(testSnapshotNetwork), 0o600))
+
+	// nothing to apply or revert leaves the snapshot untouched
+	assert.NoError(applySnapshotNodeConfigs(networkFile, recordFile, nil, nil))
+	networkBytes, err := os.ReadFile(networkFile)
+	assert.NoError(err)
+	assert.Equal(testSnapshotNetwork, string(networkBytes))
+
+	global := map[string]interface{}{"log-level": "info", "index-enabled": false}
+	nodeConfigs := map[string]map[string]interface{}{
+		"node1": {"log-level": "debug"},
+		"node3": {"index-enabled": true},
+	}
+	assert.NoError(applySnapshotNodeConfigs(networkFile, recordFile, global, nodeConfigs))
+	assert.Equal(map[string]map[string]interface{}{
+		"node1": {"log-level": "debug", "index-enabled": false, "db-dir": "/db1"},
+		"node2": {"log-level": "info", "index-enabled": false, "db-dir": "/db2"},
+		"node3": {"log-level": "info", "index-enabled": true},
+	}, readSnapshotFlags(assert, networkFile))
+	assert.FileExists(recordFile)
+
+	// a node override removed from the config is reverted
+	delete(nodeConfigs, "node1")
+	assert.NoError(applySnapshotNodeConfigs(networkFile, recordFile, global, nodeConfigs))
+	assert.Equal("info", readSnapshotFlags(assert, networkFile)["node1"]["log-level"])
+
+	// and so are all the settings, once no node is overridden
+	assert.NoError(applySnapshotNodeConfigs(networkFile, recordFile, global, nil))
+	assert.Equal(map[string]map[string]interface{}{
+		"node1": {"log-level": "warn", "db-dir": "/db1"},
+		"node2": {"db-dir": "/db2"},
+		"node3": {},
+	}, readSnapshotFlags(assert, networkFile))
+	assert.NoFileExists(recordFile)
+
+	err = applySnapshotNodeConfigs(networkFile, recordFile, nil, map[string]map[string]interface{}{"node9": {}})
+	assert.ErrorContains(err, "node9")
+}
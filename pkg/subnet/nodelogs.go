@@ -0,0 +1,158 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+const (
+	// networkRunnerDirPrefix prefixes the dir the network runner creates in
+	// the root data dir it is given, holding a dir for each node
+	networkRunnerDirPrefix = "network-runner-root-data_"
+	// nodeLogPollInterval is how often the logs of the nodes are polled for
+	// new lines when following them
+	nodeLogPollInterval = 200 * time.Millisecond
+)
+
+// nodeMainLog is the main log of a node, in its dir
+var nodeMainLog = filepath.Join("logs", "main"+constants.LogSuffix)
+
+// followedLog is a log being followed, read up to offset, with the start
+// of the line not complete yet
+type followedLog struct {
+	node    string
+	offset  int64
+	partial []byte
+}
+
+// readLines calls onLine with the lines completed in the log since the
+// last read
+func (l *followedLog) readLines(path string, onLine func(node string, line string)) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if _, err := f.Seek(l.offset, io.SeekStart); err != nil {
+		return
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return
+	}
+	l.offset += int64(len(content))
+	content = append(l.partial, content...)
+	for {
+		i := bytes.IndexByte(content, '\n')
+		if i < 0 {
+			break
+		}
+		onLine(l.node, string(bytes.TrimRight(content[:i], "\r")))
+		content = content[i+1:]
+	}
+	l.partial = append([]byte{}, content...)
+}
+
+// FollowNodeLogs calls onLine with each line of the main log of the nodes of
+// the network started in rootDataDir since start, as they are written,
+// polling them every interval until ctx is done
+func FollowNodeLogs(
+	ctx context.Context,
+	rootDataDir string,
+	since time.Time,
+	interval time.Duration,
+	onLine func(node string, line string),
+) {
+	logs := map[string]*followedLog{}
+	networkDir := ""
+	for {
+		if networkDir == "" {
+			networkDir = newNetworkDir(rootDataDir, since)
+		}
+		if networkDir != "" {
+			paths, _ := filepath.Glob(filepath.Join(networkDir, "*", nodeMainLog))
+			sort.Strings(paths)
+			for _, path := range paths {
+				l, ok := logs[path]
+				if !ok {
+					l = &followedLog{node: filepath.Base(filepath.Dir(filepath.Dir(path)))}
+					logs[path] = l
+				}
+				l.readLines(path, onLine)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// newNetworkDir returns the most recent dir of a network started in
+// rootDataDir since start, or an empty string if there is none yet
+func newNetworkDir(rootDataDir string, since time.Time) string {
+	matches, _ := filepath.Glob(filepath.Join(rootDataDir, networkRunnerDirPrefix+"*"))
+	newest, newestTime := "", since
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || !info.IsDir() || info.ModTime().Before(newestTime) {
+			continue
+		}
+		newest, newestTime = match, info.ModTime()
+	}
+	return newest
+}
+
+// nodeLogPrinter returns the onLine of FollowNodeLogs printing each line
+// prefixed with the name of its node, in a color of its own
+func nodeLogPrinter() func(node string, line string) {
+	colors := map[string]int{}
+	return func(node string, line string) {
+		color, ok := colors[node]
+		if !ok {
+			color = len(colors)
+			colors[node] = color
+		}
+		fmt.Println(ux.Colorize(color, "["+node+"]"), line)
+	}
+}
+
+// SetFollowLogs streams the log lines of the nodes to the terminal while
+// the network starts, instead of showing its progress
+func (d *LocalSubnetDeployer) SetFollowLogs(followLogs bool) {
+	d.followLogs = followLogs
+}
+
+// StreamNodeLogs runs start, which starts the nodes of a network in
+// rootDataDir and waits for them, printing the log lines of the nodes
+// meanwhile if enabled with SetFollowLogs
+func (d *LocalSubnetDeployer) StreamNodeLogs(rootDataDir string, start func() error) error {
+	if !d.followLogs {
+		return start()
+	}
+	since := time.Now()
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		FollowNodeLogs(ctx, rootDataDir, since, nodeLogPollInterval, nodeLogPrinter())
+	}()
+	d.streamingLogs = true
+	err := start()
+	d.streamingLogs = false
+	stop()
+	<-done
+	return err
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+func TestFollowNodeLogs(t *testing.T) {
+	assert := setupTest(t)
+
+	rootDataDir := t.TempDir()
+	since := time.Now()
+	// the network of a previous run is left out
+	oldLog := filepath.Join(rootDataDir, networkRunnerDirPrefix+"20220101_000000", "node1", nodeMainLog)
+	assert.NoError(os.MkdirAll(filepath.Dir(oldLog), perms.ReadWriteExecute))
+	assert.NoError(os.WriteFile(oldLog, []byte("old line\n"), perms.ReadWrite))
+	old := since.Add(-time.Hour)
+	assert.NoError(os.Chtimes(filepath.Dir(filepath.Dir(filepath.Dir(oldLog))), old, old))
+
+	networkDir := filepath.Join(rootDataDir, networkRunnerDirPrefix+"20220102_000000")
+	node1Log := filepath.Join(networkDir, "node1", nodeMainLog)
+	node2Log := filepath.Join(networkDir, "node2", nodeMainLog)
+	assert.NoError(os.MkdirAll(filepath.Dir(node1Log), perms.ReadWriteExecute))
+	assert.NoError(os.MkdirAll(filepath.Dir(node2Log), perms.ReadWriteExecute))
+	assert.NoError(os.WriteFile(node1Log, []byte("starting\nbootstr"), perms.ReadWrite))
+	assert.NoError(os.WriteFile(node2Log, []byte("starting\r\n"), perms.ReadWrite))
+
+	lines := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		FollowNodeLogs(ctx, rootDataDir, since, time.Millisecond, func(node string, line string) {
+			lines <- node + ": " + line
+		})
+	}()
+	assert.Equal("node1: starting", <-lines)
+	assert.Equal("node2: starting", <-lines)
+
+	// the lines are printed once complete
+	f, err := os.OpenFile(node1Log, os.O_APPEND|os.O_WRONLY, perms.ReadWrite)
+	assert.NoError(err)
+	_, err = f.WriteString("apping\nhealthy\n")
+	assert.NoError(err)
+	assert.NoError(f.Close())
+	assert.Equal("node1: bootstrapping", <-lines)
+	assert.Equal("node1: healthy", <-lines)
+
+	cancel()
+	<-done
+	assert.Empty(lines)
+}
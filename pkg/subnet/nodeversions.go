@@ -0,0 +1,123 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+)
+
+// ApplyNodeVersions runs each node of the local network with the avalanchego
+// binary of its version: the one the project pins for the node, see
+// config.ProjectVersions, or else avalancheGoBinPath. The network runner
+// starts all the nodes with the same binary, so the nodes running another one
+// are restarted with theirs one at a time, waiting for the network to be
+// healthy after each, as in a rolling upgrade of validators. Nothing is
+// restarted unless a version is pinned for some node. The releases pinned are
+// installed if needed, and must speak the RPCChainVM protocol of the VM
+// plugins of pluginDir. Returns the info of the network once healthy.
+func (d *LocalSubnetDeployer) ApplyNodeVersions(
+	ctx context.Context,
+	cli client.Client,
+	clusterInfo *rpcpb.ClusterInfo,
+	avalancheGoBinPath string,
+	pluginDir string,
+) (*rpcpb.ClusterInfo, error) {
+	versions := d.app.Conf.NodeAvalancheGoVersions()
+	if len(versions) == 0 {
+		return clusterInfo, nil
+	}
+	binaries, err := d.installNodeVersions(versions, pluginDir)
+	if err != nil {
+		return nil, err
+	}
+	restarts, err := nodeRestarts(clusterInfo, avalancheGoBinPath, binaries)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range restarts {
+		version, pinned := versions[name]
+		binary := avalancheGoBinPath
+		if pinned {
+			binary = binaries[name]
+		} else {
+			version, _ = d.app.Conf.AvalancheGoVersion()
+		}
+		ux.Logger.PrintToUser("Restarting %s with avalanchego %s...", name, version)
+		if _, err := cli.RestartNode(ctx, name, client.WithExecPath(binary)); err != nil {
+			return nil, fmt.Errorf("failed restarting %s with avalanchego %s: %w", name, version, err)
+		}
+		if clusterInfo, err = d.WaitForHealthy(ctx, cli, d.healthCheckInterval); err != nil {
+			return nil, fmt.Errorf("the network failed to become healthy once %s runs avalanchego %s: %w", name, version, err)
+		}
+	}
+	return clusterInfo, nil
+}
+
+// installNodeVersions installs the avalanchego releases of versions, by node
+// name, and returns their binaries by node name
+func (d *LocalSubnetDeployer) installNodeVersions(versions map[string]string, pluginDir string) (map[string]string, error) {
+	binDir := filepath.Join(d.app.GetBaseDir(), constants.AvalancheCliBinDir)
+	binaries := map[string]string{}
+	byVersion := map[string]string{}
+	for _, name := range sortedKeys(versions) {
+		version := versions[name]
+		binary, ok := byVersion[version]
+		if !ok {
+			avagoDir, err := d.installAvalancheGo(binDir, version, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed installing avalanchego %s for %s: %w", version, name, err)
+			}
+			binary = filepath.Join(avagoDir, "avalanchego")
+			if err := CheckPluginsProtocol(d.app.Log, binary, pluginDir); err != nil {
+				return nil, exitcodes.UserInput(fmt.Errorf("avalanchego %s pinned for %s: %w", version, name, err))
+			}
+			byVersion[version] = binary
+		}
+		binaries[name] = binary
+	}
+	return binaries, nil
+}
+
+// nodeRestarts returns the nodes of the network which don't run the binary
+// they must, theirs in binaries or else defaultBinary, in the order of their
+// names
+func nodeRestarts(clusterInfo *rpcpb.ClusterInfo, defaultBinary string, binaries map[string]string) ([]string, error) {
+	names := append([]string{}, clusterInfo.GetNodeNames()...)
+	sort.Strings(names)
+	for name := range binaries {
+		if _, ok := clusterInfo.GetNodeInfos()[name]; !ok {
+			return nil, exitcodes.UserInput(fmt.Errorf("the project pins the avalanchego version of node %s, which is not one of the nodes of the local network: %s",
+				name, strings.Join(names, ", ")))
+		}
+	}
+	restarts := []string{}
+	for _, name := range names {
+		expected, ok := binaries[name]
+		if !ok {
+			expected = defaultBinary
+		}
+		if info := clusterInfo.GetNodeInfos()[name]; info != nil && info.GetExecPath() != expected {
+			restarts = append(restarts, name)
+		}
+	}
+	return restarts, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+)
+
+func TestNodeRestarts(t *testing.T) {
+	assert := setupTest(t)
+
+	const (
+		oldBinary = "/bin/avalanchego-v1.7.13/avalanchego"
+		newBinary = "/bin/avalanchego-v1.7.14/avalanchego"
+	)
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeNames: []string{"node3", "node1", "node2"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", ExecPath: oldBinary},
+			"node2": {Name: "node2", ExecPath: oldBinary},
+			"node3": {Name: "node3", ExecPath: newBinary},
+		},
+	}
+
+	// the nodes pinned to another version, in order
+	restarts, err := nodeRestarts(clusterInfo, oldBinary, map[string]string{"node2": newBinary, "node3": newBinary})
+	assert.NoError(err)
+	assert.Equal([]string{"node2"}, restarts)
+
+	// and the ones not pinned anymore
+	restarts, err = nodeRestarts(clusterInfo, oldBinary, map[string]string{"node1": newBinary})
+	assert.NoError(err)
+	assert.Equal([]string{"node1", "node3"}, restarts)
+
+	_, err = nodeRestarts(clusterInfo, oldBinary, map[string]string{"node9": newBinary})
+	assert.ErrorContains(err, "node1, node2, node3")
+}
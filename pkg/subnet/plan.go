@@ -0,0 +1,216 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// PlanVersion is the version of the plan documents written by WritePlan
+const PlanVersion = 1
+
+// PlanAction is a change a plan makes to a network
+type PlanAction string
+
+const (
+	PlanCreateSubnet     PlanAction = "create-subnet"
+	PlanCreateBlockchain PlanAction = "create-blockchain"
+	PlanAddValidator     PlanAction = "add-validator"
+)
+
+// PlanStep is an action of a plan, along with the validator it adds, if any
+type PlanStep struct {
+	Action PlanAction `json:"action"`
+	NodeID string     `json:"nodeID,omitempty"`
+	Weight uint64     `json:"weight,omitempty"`
+	// Start is the time the validator starts validating, shortly after the
+	// plan is applied if nil
+	Start    *time.Time `json:"start,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+}
+
+// Plan is the reviewable list of actions bringing a subnet to its desired
+// state on a network, to be applied as is
+type Plan struct {
+	Version int    `json:"version"`
+	Subnet  string `json:"subnet"`
+	Network string `json:"network"`
+	// GenesisHash is the SHA-256 of the genesis the plan deploys, which must
+	// not change until it is applied
+	GenesisHash string `json:"genesisHash"`
+	// GenesisVars are the values of the variables of a genesis template
+	GenesisVars map[string]string `json:"genesisVars,omitempty"`
+	// Key is the signing key paying for the public deploys
+	Key         string   `json:"key,omitempty"`
+	ControlKeys []string `json:"controlKeys,omitempty"`
+	Threshold   uint32   `json:"threshold,omitempty"`
+	// SubnetID is the subnet the validators are added to, if it exists when
+	// planning
+	SubnetID string     `json:"subnetID,omitempty"`
+	Steps    []PlanStep `json:"steps"`
+	// Fees are the fees of the public deploys, in nAVAX
+	Fees      uint64    `json:"fees,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PlanState is the state of a subnet on a network that a plan starts from
+type PlanState struct {
+	SubnetID     ids.ID
+	BlockchainID ids.ID
+	// Validators are the nodes already validating the subnet or pending to
+	Validators map[ids.NodeID]bool
+}
+
+// PlanSteps returns the steps deploying a subnet in state, with the given
+// validators. defaultStart is the start of the validators starting as soon
+// as possible, recorded without a start in the steps.
+func PlanSteps(state PlanState, validators []ValidatorEntry, defaultStart time.Time) []PlanStep {
+	steps := []PlanStep{}
+	// the subnet and its blockchain are recorded together once both exist
+	if state.SubnetID == ids.Empty {
+		steps = append(steps, PlanStep{Action: PlanCreateSubnet})
+	}
+	if state.BlockchainID == ids.Empty {
+		steps = append(steps, PlanStep{Action: PlanCreateBlockchain})
+	}
+	for _, v := range validators {
+		if state.Validators[v.NodeID] {
+			continue
+		}
+		step := PlanStep{
+			Action:   PlanAddValidator,
+			NodeID:   v.NodeID.String(),
+			Weight:   v.Weight,
+			Duration: v.Duration.String(),
+		}
+		if !v.Start.Equal(defaultStart) {
+			start := v.Start
+			step.Start = &start
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// ValidatorEntries returns the validators added by the steps, the ones
+// without a start starting at defaultStart
+func ValidatorEntries(steps []PlanStep, defaultStart time.Time) ([]ValidatorEntry, error) {
+	entries := []ValidatorEntry{}
+	for i, step := range steps {
+		if step.Action != PlanAddValidator {
+			continue
+		}
+		nodeID, err := ids.NodeIDFromString(step.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: invalid nodeID %q: %w", i+1, step.NodeID, err)
+		}
+		duration, err := time.ParseDuration(step.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: invalid duration %q: %w", i+1, step.Duration, err)
+		}
+		start := defaultStart
+		if step.Start != nil {
+			start = *step.Start
+		}
+		entries = append(entries, ValidatorEntry{Line: i + 1, NodeID: nodeID, Weight: step.Weight, Start: start, Duration: duration})
+	}
+	return entries, nil
+}
+
+// HasAction returns true if the plan has a step with action
+func (p Plan) HasAction(action PlanAction) bool {
+	for _, step := range p.Steps {
+		if step.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUpToDate returns an error if applying the plan to the subnet in state
+// would not take the steps planned anymore, such as when the subnet was
+// deployed or a validator added since the plan was made
+func (p Plan) CheckUpToDate(state PlanState) error {
+	// the start of the validators doesn't matter to the steps taken
+	entries, err := ValidatorEntries(p.Steps, time.Time{})
+	if err != nil {
+		return err
+	}
+	steps := PlanSteps(state, entries, time.Time{})
+	upToDate := len(steps) == len(p.Steps)
+	for i := 0; upToDate && i < len(steps); i++ {
+		upToDate = reflect.DeepEqual(steps[i], p.Steps[i])
+	}
+	if !upToDate {
+		return fmt.Errorf("subnet %s changed on %s since the plan was made, make a new plan with subnet plan", p.Subnet, p.Network)
+	}
+	return nil
+}
+
+// GenesisHash returns the hash of the genesis file at path recorded in plans
+func GenesisHash(path string) (string, error) {
+	genesis, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(genesis)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// FetchValidators returns which of the nodes are validating subnetID on the
+// P-Chain of the public network, or pending to
+func (d *PublicDeployer) FetchValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID) (map[ids.NodeID]bool, error) {
+	api, _, err := d.endpoint()
+	if err != nil {
+		return nil, err
+	}
+	return fetchValidators(ctx, platformvm.NewClient(api), subnetID, nodeIDs)
+}
+
+func fetchValidators(ctx context.Context, client validatorsClient, subnetID ids.ID, nodeIDs []ids.NodeID) (map[ids.NodeID]bool, error) {
+	validators := map[ids.NodeID]bool{}
+	for _, nodeID := range nodeIDs {
+		status, err := getValidatorStatus(ctx, client, subnetID, nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting the status of validator %s: %w", nodeID, err)
+		}
+		validators[nodeID] = status != ValidatorUnknown
+	}
+	return validators, nil
+}
+
+// WritePlan writes the plan as JSON to path
+func WritePlan(path string, plan Plan) error {
+	planBytes, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(planBytes, '\n'), application.WriteReadReadPerms)
+}
+
+// LoadPlan reads the plan written at path by WritePlan
+func LoadPlan(path string) (Plan, error) {
+	planBytes, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, err
+	}
+	var plan Plan
+	if err := json.Unmarshal(planBytes, &plan); err != nil {
+		return Plan{}, fmt.Errorf("failed parsing plan %s: %w", path, err)
+	}
+	if plan.Version != PlanVersion {
+		return Plan{}, fmt.Errorf("plan %s has version %d, expected %d", path, plan.Version, PlanVersion)
+	}
+	return plan, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanSteps(t *testing.T) {
+	assert := assert.New(t)
+
+	node1, err := ids.NodeIDFromString(testNodeID1)
+	assert.NoError(err)
+	node2, err := ids.NodeIDFromString(testNodeID2)
+	assert.NoError(err)
+	defaultStart := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	start := defaultStart.Add(time.Hour)
+	validators := []ValidatorEntry{
+		{NodeID: node1, Weight: 20, Start: start, Duration: 720 * time.Hour},
+		{NodeID: node2, Weight: 30, Start: defaultStart, Duration: time.Hour},
+	}
+
+	steps := PlanSteps(PlanState{}, validators, defaultStart)
+	assert.Equal([]PlanStep{
+		{Action: PlanCreateSubnet},
+		{Action: PlanCreateBlockchain},
+		{Action: PlanAddValidator, NodeID: testNodeID1, Weight: 20, Start: &start, Duration: "720h0m0s"},
+		{Action: PlanAddValidator, NodeID: testNodeID2, Weight: 30, Duration: "1h0m0s"},
+	}, steps)
+
+	// the validators without a start start at the default start when applied
+	applyStart := defaultStart.Add(time.Minute)
+	entries, err := ValidatorEntries(steps, applyStart)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.Equal(start, entries[0].Start)
+	assert.Equal(applyStart, entries[1].Start)
+	assert.Equal(time.Hour, entries[1].Duration)
+
+	plan := Plan{Subnet: "test", Network: "Fuji", Steps: steps}
+	assert.True(plan.HasAction(PlanCreateSubnet))
+	assert.NoError(plan.CheckUpToDate(PlanState{}))
+
+	// a deployed subnet only gets the validators it doesn't have yet
+	deployed := PlanState{
+		SubnetID:     ids.GenerateTestID(),
+		BlockchainID: ids.GenerateTestID(),
+		Validators:   map[ids.NodeID]bool{node1: true},
+	}
+	assert.Equal([]PlanStep{
+		{Action: PlanAddValidator, NodeID: testNodeID2, Weight: 30, Duration: "1h0m0s"},
+	}, PlanSteps(deployed, validators, defaultStart))
+	assert.Error(plan.CheckUpToDate(deployed))
+}
+
+func TestWriteLoadPlan(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	start := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	plan := Plan{
+		Version:     PlanVersion,
+		Subnet:      "test",
+		Network:     "Fuji",
+		GenesisHash: "abcd",
+		Key:         "key",
+		ControlKeys: []string{"P-fuji1abc"},
+		Threshold:   1,
+		Steps: []PlanStep{
+			{Action: PlanCreateSubnet},
+			{Action: PlanCreateBlockchain},
+			{Action: PlanAddValidator, NodeID: testNodeID1, Weight: 20, Start: &start, Duration: "1h0m0s"},
+		},
+		Fees:      2_000_000,
+		CreatedAt: start,
+	}
+	assert.NoError(WritePlan(path, plan))
+	loaded, err := LoadPlan(path)
+	assert.NoError(err)
+	assert.Equal(plan, loaded)
+	assert.NoError(loaded.CheckUpToDate(PlanState{}))
+
+	plan.Version = PlanVersion + 1
+	assert.NoError(WritePlan(path, plan))
+	_, err = LoadPlan(path)
+	assert.Error(err)
+}
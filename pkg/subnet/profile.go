@@ -0,0 +1,222 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/coreth/core/types"
+	ethrpc "github.com/ava-labs/coreth/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// the files avalanchego writes its profiles to in its profile directory
+var nodeProfileFiles = struct {
+	cpu, mem, lock string
+}{"cpu.profile", "mem.profile", "lock.profile"}
+
+// adminClient is the part of the admin API of a node profiling it
+type adminClient interface {
+	StartCPUProfiler(context.Context, ...rpc.Option) error
+	StopCPUProfiler(context.Context, ...rpc.Option) error
+	MemoryProfile(context.Context, ...rpc.Option) error
+	LockProfile(context.Context, ...rpc.Option) error
+	GetConfig(context.Context, ...rpc.Option) (interface{}, error)
+}
+
+// NodeProfiles are the profiles of a node written to Dir, or the reason
+// they couldn't be collected
+type NodeProfiles struct {
+	Node  string
+	Dir   string
+	Files []string
+	Err   error
+}
+
+// CollectNodeProfiles writes the CPU profile over cpuDuration, the memory
+// profile and the lock profile of every node at the URIs by node name to
+// outDir/<node>. The admin API of the nodes must be enabled, as it is on the
+// local network.
+func CollectNodeProfiles(ctx context.Context, nodeURIs map[string]string, outDir string, cpuDuration time.Duration) []NodeProfiles {
+	clients := map[string]adminClient{}
+	for name, uri := range nodeURIs {
+		clients[name] = admin.NewClient(uri)
+	}
+	return collectNodeProfiles(ctx, clients, outDir, cpuDuration)
+}
+
+func collectNodeProfiles(ctx context.Context, clients map[string]adminClient, outDir string, cpuDuration time.Duration) []NodeProfiles {
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := make([]NodeProfiles, len(names))
+	for i, name := range names {
+		results[i] = NodeProfiles{Node: name, Dir: filepath.Join(outDir, name)}
+	}
+
+	// the nodes are profiled over the same period, and each profile is
+	// copied right after it is written, as the nodes of the local network
+	// share their profile directory
+	for i, name := range names {
+		if err := clients[name].StartCPUProfiler(ctx); err != nil {
+			results[i].Err = fmt.Errorf("failed starting the CPU profiler, is the admin API enabled? %w", err)
+		}
+	}
+	select {
+	case <-time.After(cpuDuration):
+	case <-ctx.Done():
+	}
+	for i, name := range names {
+		client := clients[name]
+		if results[i].Err != nil {
+			// stopping a profiler that may have started, best effort
+			_ = client.StopCPUProfiler(ctx)
+			continue
+		}
+		profileDir, err := nodeProfileDir(ctx, client)
+		if err != nil {
+			_ = client.StopCPUProfiler(ctx)
+			results[i].Err = err
+			continue
+		}
+		steps := []struct {
+			write func(context.Context, ...rpc.Option) error
+			file  string
+		}{
+			{client.StopCPUProfiler, nodeProfileFiles.cpu},
+			{client.MemoryProfile, nodeProfileFiles.mem},
+			{client.LockProfile, nodeProfileFiles.lock},
+		}
+		for _, step := range steps {
+			if err := step.write(ctx); err != nil {
+				results[i].Err = fmt.Errorf("failed writing %s: %w", step.file, err)
+				break
+			}
+			if err := copyFile(filepath.Join(profileDir, step.file), filepath.Join(results[i].Dir, step.file)); err != nil {
+				results[i].Err = fmt.Errorf("failed copying %s: %w", step.file, err)
+				break
+			}
+			results[i].Files = append(results[i].Files, step.file)
+		}
+	}
+	return results
+}
+
+// nodeProfileDir returns the directory the node of client writes its
+// profiles to
+func nodeProfileDir(ctx context.Context, client adminClient) (string, error) {
+	nodeConfig, err := client.GetConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed getting the node config: %w", err)
+	}
+	configBytes, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return "", err
+	}
+	var config struct {
+		ProfilerConfig struct {
+			Dir string `json:"dir"`
+		} `json:"profilerConfig"`
+	}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return "", fmt.Errorf("failed reading the profile directory of the node config: %w", err)
+	}
+	if config.ProfilerConfig.Dir == "" {
+		return "", fmt.Errorf("the node config has no profile directory")
+	}
+	return config.ProfilerConfig.Dir, nil
+}
+
+func copyFile(src string, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, application.WriteReadReadPerms)
+}
+
+// rpcCaller is the JSON-RPC client of an EVM chain
+type rpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// TxTrace is the trace of a failed transaction written to Path, or the
+// reason it couldn't be traced
+type TxTrace struct {
+	Hash common.Hash
+	Path string
+	Err  error
+}
+
+// TraceFailedTxs writes the call trace of every failed transaction of the
+// last blocks of the EVM chain at rpcURL to outDir/<hash>.json. Tracing
+// requires the debug-tracer API in the eth-apis of the chain config.
+func TraceFailedTxs(ctx context.Context, rpcURL string, blocks uint64, outDir string) ([]TxTrace, error) {
+	client, err := ethrpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return traceFailedTxs(ctx, client, blocks, outDir)
+}
+
+func traceFailedTxs(ctx context.Context, client rpcCaller, blocks uint64, outDir string) ([]TxTrace, error) {
+	var head hexutil.Uint64
+	if err := client.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+		return nil, fmt.Errorf("failed getting the last block: %w", err)
+	}
+	traces := []TxTrace{}
+	for n := uint64(head); n > 0 && uint64(head)-n < blocks; n-- {
+		var block struct {
+			Transactions []common.Hash `json:"transactions"`
+		}
+		if err := client.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(n), false); err != nil {
+			return nil, fmt.Errorf("failed getting block %d: %w", n, err)
+		}
+		for _, hash := range block.Transactions {
+			var receipt struct {
+				Status hexutil.Uint64 `json:"status"`
+			}
+			if err := client.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+				return nil, fmt.Errorf("failed getting the receipt of %s: %w", hash, err)
+			}
+			if uint64(receipt.Status) == types.ReceiptStatusSuccessful {
+				continue
+			}
+			traces = append(traces, traceTx(ctx, client, hash, outDir))
+		}
+	}
+	return traces, nil
+}
+
+func traceTx(ctx context.Context, client rpcCaller, hash common.Hash, outDir string) TxTrace {
+	trace := TxTrace{Hash: hash, Path: filepath.Join(outDir, hash.Hex()+".json")}
+	var result json.RawMessage
+	if err := client.CallContext(ctx, &result, "debug_traceTransaction", hash, map[string]string{"tracer": "callTracer"}); err != nil {
+		trace.Err = fmt.Errorf("failed tracing, is debug-tracer in the eth-apis of the chain config? %w", err)
+		return trace
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		trace.Err = err
+		return trace
+	}
+	if err := os.WriteFile(trace.Path, result, application.WriteReadReadPerms); err != nil {
+		trace.Err = err
+	}
+	return trace
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProfiler writes the profiles of a node to its profile directory
+type fakeProfiler struct {
+	profileDir string
+	disabled   bool
+	profiling  bool
+}
+
+func (f *fakeProfiler) write(file string) error {
+	if f.disabled {
+		return errors.New("admin API disabled")
+	}
+	return os.WriteFile(filepath.Join(f.profileDir, file), []byte(f.profileDir+"/"+file), 0o600)
+}
+
+func (f *fakeProfiler) StartCPUProfiler(context.Context, ...rpc.Option) error {
+	if f.disabled {
+		return errors.New("admin API disabled")
+	}
+	f.profiling = true
+	return nil
+}
+
+func (f *fakeProfiler) StopCPUProfiler(context.Context, ...rpc.Option) error {
+	f.profiling = false
+	return f.write(nodeProfileFiles.cpu)
+}
+
+func (f *fakeProfiler) MemoryProfile(context.Context, ...rpc.Option) error {
+	return f.write(nodeProfileFiles.mem)
+}
+
+func (f *fakeProfiler) LockProfile(context.Context, ...rpc.Option) error {
+	return f.write(nodeProfileFiles.lock)
+}
+
+func (f *fakeProfiler) GetConfig(context.Context, ...rpc.Option) (interface{}, error) {
+	return map[string]interface{}{"profilerConfig": map[string]interface{}{"dir": f.profileDir}}, nil
+}
+
+func TestCollectNodeProfiles(t *testing.T) {
+	assert := assert.New(t)
+
+	// the nodes share their profile directory, as on the local network
+	profileDir := t.TempDir()
+	outDir := t.TempDir()
+	node1 := &fakeProfiler{profileDir: profileDir}
+	node2 := &fakeProfiler{profileDir: profileDir}
+	clients := map[string]adminClient{
+		"node1": node1,
+		"node2": node2,
+		"node3": &fakeProfiler{profileDir: profileDir, disabled: true},
+	}
+	results := collectNodeProfiles(context.Background(), clients, outDir, 0)
+	assert.Len(results, 3)
+	for i, node := range []string{"node1", "node2"} {
+		assert.Equal(node, results[i].Node)
+		assert.NoError(results[i].Err)
+		assert.Equal([]string{"cpu.profile", "mem.profile", "lock.profile"}, results[i].Files)
+		for _, file := range results[i].Files {
+			content, err := os.ReadFile(filepath.Join(outDir, node, file))
+			assert.NoError(err)
+			assert.Equal(profileDir+"/"+file, string(content))
+		}
+	}
+	assert.False(node1.profiling)
+	assert.False(node2.profiling)
+	assert.Equal("node3", results[2].Node)
+	assert.ErrorContains(results[2].Err, "admin API")
+	assert.NoDirExists(filepath.Join(outDir, "node3"))
+}
+
+// fakeChain answers the JSON-RPC calls of an EVM chain with a block of a
+// successful and a failed transaction
+type fakeChain struct {
+	tracing bool
+	blocks  []uint64
+}
+
+var (
+	okTx     = common.HexToHash("0x01")
+	failedTx = common.HexToHash("0x02")
+)
+
+func (f *fakeChain) CallContext(_ context.Context, result interface{}, method string, args ...interface{}) error {
+	var response string
+	switch method {
+	case "eth_blockNumber":
+		response = `"0x3"`
+	case "eth_getBlockByNumber":
+		n, err := hexutil.DecodeUint64(args[0].(string))
+		if err != nil {
+			return err
+		}
+		f.blocks = append(f.blocks, n)
+		response = `{"transactions": []}`
+		if n == 2 {
+			response = `{"transactions": ["` + okTx.Hex() + `", "` + failedTx.Hex() + `"]}`
+		}
+	case "eth_getTransactionReceipt":
+		response = `{"status": "0x1"}`
+		if args[0].(common.Hash) == failedTx {
+			response = `{"status": "0x0"}`
+		}
+	case "debug_traceTransaction":
+		if !f.tracing {
+			return errors.New("the method debug_traceTransaction does not exist/is not available")
+		}
+		response = `{"type": "CALL", "error": "execution reverted"}`
+	}
+	return json.Unmarshal([]byte(response), result)
+}
+
+func TestTraceFailedTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	outDir := t.TempDir()
+	chain := &fakeChain{tracing: true}
+	traces, err := traceFailedTxs(context.Background(), chain, 2, outDir)
+	assert.NoError(err)
+	assert.Equal([]uint64{3, 2}, chain.blocks)
+	assert.Len(traces, 1)
+	assert.Equal(failedTx, traces[0].Hash)
+	assert.NoError(traces[0].Err)
+	content, err := os.ReadFile(filepath.Join(outDir, failedTx.Hex()+".json"))
+	assert.NoError(err)
+	assert.JSONEq(`{"type": "CALL", "error": "execution reverted"}`, string(content))
+
+	// without the debug API, the failed transactions are reported untraced
+	traces, err = traceFailedTxs(context.Background(), &fakeChain{}, 100, t.TempDir())
+	assert.NoError(err)
+	assert.Len(traces, 1)
+	assert.ErrorContains(traces[0].Err, "debug-tracer")
+}
@@ -5,19 +5,25 @@ package subnet
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
-	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
 	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/platformvm/validator"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p"
 	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
 	"github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
 )
@@ -43,7 +49,37 @@ func (d *PublicDeployer) AddValidator(subnet ids.ID, nodeID ids.NodeID, weight u
 	if err != nil {
 		return err
 	}
-	validator := &validator.SubnetValidator{
+	id, err := d.issueTx(&wallet, []ids.ID{subnet}, func(wallet primary.Wallet) (ids.ID, error) {
+		return issueAddSubnetValidatorTx(wallet, subnet, nodeID, weight, startTime, duration)
+	})
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgTxSuccessful), id)
+	return nil
+}
+
+func issueAddSubnetValidatorTx(
+	wallet primary.Wallet,
+	subnet ids.ID,
+	nodeID ids.NodeID,
+	weight uint64,
+	startTime time.Time,
+	duration time.Duration,
+) (ids.ID, error) {
+	return wallet.P().IssueAddSubnetValidatorTx(newSubnetValidator(subnet, nodeID, weight, startTime, duration), common.WithAssumeDecided())
+}
+
+// newSubnetValidator returns nodeID validating subnet with weight for
+// duration from startTime
+func newSubnetValidator(
+	subnet ids.ID,
+	nodeID ids.NodeID,
+	weight uint64,
+	startTime time.Time,
+	duration time.Duration,
+) *validator.SubnetValidator {
+	return &validator.SubnetValidator{
 		Validator: validator.Validator{
 			NodeID: nodeID,
 			Start:  uint64(startTime.Unix()),
@@ -52,58 +88,70 @@ func (d *PublicDeployer) AddValidator(subnet ids.ID, nodeID ids.NodeID, weight u
 		},
 		Subnet: subnet,
 	}
-	id, err := wallet.P().IssueAddSubnetValidatorTx(validator)
-	if err != nil {
-		return err
-	}
-	ux.Logger.PrintToUser("Transaction successful, transaction ID :%s", id)
-	return nil
 }
 
-func (d *PublicDeployer) Deploy(controlKeys []string, threshold uint32, chain, genesis string) (ids.ID, ids.ID, error) {
+func (d *PublicDeployer) Deploy(controlKeys []string, threshold uint32, sc models.Sidecar, chainGenesis string) (ids.ID, ids.ID, error) {
+	genesis, err := os.ReadFile(chainGenesis)
+	if err != nil {
+		return ids.Empty, ids.Empty, fmt.Errorf("failed reading chain genesis: %w", err)
+	}
+	if err := d.checkEwoqControlKeys(controlKeys); err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	if err := d.CheckEwoqGenesis(sc, genesis); err != nil {
+		return ids.Empty, ids.Empty, err
+	}
 	wallet, api, err := d.loadWallet()
 	if err != nil {
 		return ids.Empty, ids.Empty, err
 	}
-	vmID, err := utils.VMID(chain)
+	vmID, err := sc.GetVMID()
 	if err != nil {
-		return ids.Empty, ids.Empty, fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
+		return ids.Empty, ids.Empty, err
 	}
 
-	subnetID, err := d.createSubnetTx(controlKeys, threshold, wallet)
+	subnetID, err := d.issueTx(&wallet, nil, func(wallet primary.Wallet) (ids.ID, error) {
+		return d.createSubnetTx(controlKeys, threshold, wallet)
+	})
 	if err != nil {
 		return ids.Empty, ids.Empty, err
 	}
-	ux.Logger.PrintToUser("Subnet has been created with ID: %s. Now creating blockchain...", subnetID.String())
+	d.reportSubnet(subnetID)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgSubnetCreated), subnetID.String())
 
-	blockchainID, err := d.createBlockchainTx(chain, vmID, subnetID, []byte(genesis), wallet)
+	// a refreshed wallet has to know the subnet to authorize the blockchain
+	blockchainID, err := d.issueTx(&wallet, []ids.ID{subnetID}, func(wallet primary.Wallet) (ids.ID, error) {
+		return d.createBlockchainTx(sc.Name, vmID, subnetID, genesis, wallet)
+	})
 	if err != nil {
 		return ids.Empty, ids.Empty, err
 	}
-	ux.Logger.PrintToUser("Endpoint for blockchain %q with VM ID %q: %s/ext/bc/%s/rpc", blockchainID.String(), vmID.String(), api, blockchainID.String())
+	d.reportBlockchain(blockchainID, subnetID)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgPublicEndpoint), blockchainID.String(), vmID.String(), api, blockchainID.String())
 	return subnetID, blockchainID, nil
 }
 
 func (d *PublicDeployer) loadWallet(preloadTxs ...ids.ID) (primary.Wallet, string, error) {
 	ctx := context.Background()
 
-	var (
-		api       string
-		networkID uint32
-	)
+	api, networkID, err := d.healthyEndpoint(ctx)
+	if err != nil {
+		return nil, "", err
+	}
 
-	switch d.network {
-	case models.Fuji:
-		api = constants.FujiAPIEndpoint
-		networkID = avago_constants.FujiID
-	case models.Mainnet:
-		api = constants.MainnetAPIEndpoint
-		networkID = avago_constants.MainnetID
-	default:
-		return nil, "", fmt.Errorf("unsupported public network")
+	if d.usesRemoteKey() {
+		rk, err := key.LoadRemote(networkID, d.privKeyPath)
+		if err != nil {
+			return nil, "", err
+		}
+		wallet, err := newRemoteWallet(ctx, api, rk, preloadTxs...)
+		if err != nil {
+			return nil, "", err
+		}
+		return wallet, api, nil
 	}
 
-	sf, err := key.LoadSoft(networkID, d.privKeyPath)
+	sf, err := d.loadSoftKey(networkID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -117,15 +165,172 @@ func (d *PublicDeployer) loadWallet(preloadTxs ...ids.ID) (primary.Wallet, strin
 	return wallet, api, nil
 }
 
+// PayerAddress returns the P-Chain address of the key of the deployer
+func (d *PublicDeployer) PayerAddress() (ids.ShortID, error) {
+	_, networkID, err := d.endpoint()
+	if err != nil {
+		return ids.ShortEmpty, err
+	}
+	if d.usesRemoteKey() {
+		rk, err := key.LoadRemote(networkID, d.privKeyPath)
+		if err != nil {
+			return ids.ShortEmpty, err
+		}
+		return rk.Address(), nil
+	}
+	sf, err := d.loadSoftKey(networkID)
+	if err != nil {
+		return ids.ShortEmpty, err
+	}
+	return sf.Key().PublicKey().Address(), nil
+}
+
+// CheckFunding verifies that the key of the deployer has the fees, in nAVAX,
+// unlocked on the P-Chain. Otherwise the error names the address to fund and
+// the amount it is short of, which the wallet would only report as
+// insufficient funds once issuing the transactions.
+func (d *PublicDeployer) CheckFunding(fees uint64) error {
+	payer, err := d.PayerAddress()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	api, networkID, err := d.healthyEndpoint(ctx)
+	if err != nil {
+		return err
+	}
+	balance, err := platformvm.NewClient(api).GetBalance(ctx, []ids.ShortID{payer})
+	if err != nil {
+		return fmt.Errorf("failed getting the P-Chain balance of the key: %w", err)
+	}
+	missing := Shortfall(uint64(balance.Unlocked), fees)
+	if missing == 0 {
+		return nil
+	}
+	pAddr, err := address.Format("P", avago_constants.GetHRP(networkID), payer.Bytes())
+	if err != nil {
+		return err
+	}
+	return exitcodes.UserInput(fmt.Errorf(
+		"the key has %s AVAX unlocked on the P-Chain of %s, but the deploy takes %s AVAX of fees: fund %s with at least %s more AVAX, for instance from the C-Chain with key transfer",
+		FormatAVAX(uint64(balance.Unlocked)), d.network, FormatAVAX(fees), pAddr, FormatAVAX(missing),
+	))
+}
+
+// SignBundle adds the signatures of the key of the deployer to the
+// transaction of bundle. Only remote keys reach the network, to sign with
+// their remote signer.
+func (d *PublicDeployer) SignBundle(bundle *TxBundle) error {
+	_, networkID, err := d.endpoint()
+	if err != nil {
+		return err
+	}
+	backend, err := bundle.Backend()
+	if err != nil {
+		return err
+	}
+	var signer p.Signer
+	if d.usesRemoteKey() {
+		rk, err := key.LoadRemote(networkID, d.privKeyPath)
+		if err != nil {
+			return err
+		}
+		signer = &remoteSigner{key: rk, backend: backend}
+	} else {
+		sf, err := d.loadSoftKey(networkID)
+		if err != nil {
+			return err
+		}
+		signer = p.NewSigner(sf.KeyChain(), backend)
+	}
+	return bundle.Sign(context.Background(), signer)
+}
+
+// loadSoftKey loads the local private key of the deployer, refusing the
+// ewoq key unless allowed
+func (d *PublicDeployer) loadSoftKey(networkID uint32) (*key.SoftKey, error) {
+	sk, err := key.LoadSoft(networkID, d.privKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsEwoq(sk) {
+		if err := d.app.CheckEwoq(d.network, "sign transactions with the ewoq key"); err != nil {
+			return nil, err
+		}
+	}
+	return sk, nil
+}
+
+// usesRemoteKey returns true if the transactions are signed by a remote
+// signer, such as a cloud KMS, instead of a local private key
+func (d *PublicDeployer) usesRemoteKey() bool {
+	return strings.HasSuffix(d.privKeyPath, constants.RemoteKeySuffix)
+}
+
+// endpoint returns the API endpoint and the network ID of the public network
+func (d *PublicDeployer) endpoint() (string, uint32, error) {
+	api, err := d.app.GetAPIEndpoint(d.network)
+	if err != nil {
+		return "", 0, err
+	}
+	switch d.network {
+	case models.Fuji:
+		return api, avago_constants.FujiID, nil
+	default:
+		return api, avago_constants.MainnetID, nil
+	}
+}
+
+// healthyEndpoint returns the API endpoint picked by SelectAPIEndpoint for
+// the public network, and the network ID
+func (d *PublicDeployer) healthyEndpoint(ctx context.Context) (string, uint32, error) {
+	_, networkID, err := d.endpoint()
+	if err != nil {
+		return "", 0, err
+	}
+	api, err := SelectAPIEndpoint(ctx, d.app, d.network, networkID)
+	if err != nil {
+		return "", 0, err
+	}
+	return api, networkID, nil
+}
+
+// checkEndpoint verifies that the API endpoint is up, on the expected
+// network and done bootstrapping the P-Chain, before a wallet is created on it
+func checkEndpoint(ctx context.Context, api string, networkID uint32) error {
+	ctx, cancel := context.WithTimeout(ctx, constants.EndpointCheckTimeout)
+	defer cancel()
+	infoClient := info.NewClient(api)
+	endpointNetworkID, err := infoClient.GetNetworkID(ctx)
+	if err != nil {
+		return fmt.Errorf("API endpoint %s is not reachable: %w", api, err)
+	}
+	if endpointNetworkID != networkID {
+		return exitcodes.UserInput(fmt.Errorf(
+			"API endpoint %s is on network %s, not on %s",
+			api, avago_constants.NetworkName(endpointNetworkID), avago_constants.NetworkName(networkID),
+		))
+	}
+	bootstrapped, err := infoClient.IsBootstrapped(ctx, "P")
+	if err != nil {
+		return fmt.Errorf("failed checking API endpoint %s: %w", api, err)
+	}
+	if !bootstrapped {
+		return fmt.Errorf("API endpoint %s has not finished bootstrapping the P-Chain, try again later or use another endpoint", api)
+	}
+	return nil
+}
+
 func (d *PublicDeployer) createBlockchainTx(chainName string, vmID, subnetID ids.ID, genesis []byte, wallet primary.Wallet) (ids.ID, error) {
-	// TODO do we need any of these to be set?
-	options := []common.Option{}
+	// the transaction is waited for by the caller
+	options := []common.Option{common.WithAssumeDecided()}
 	fxIDs := make([]ids.ID, 0)
 	return wallet.P().IssueCreateChainTx(subnetID, genesis, vmID, fxIDs, chainName, options...)
 }
 
 func (d *PublicDeployer) createSubnetTx(controlKeys []string, threshold uint32, wallet primary.Wallet) (ids.ID, error) {
-	addrs, err := address.ParseToIDs(controlKeys)
+	addrs, err := parseControlKeys(controlKeys)
 	if err != nil {
 		return ids.Empty, err
 	}
@@ -134,6 +339,182 @@ func (d *PublicDeployer) createSubnetTx(controlKeys []string, threshold uint32,
 		Threshold: threshold,
 		Locktime:  0,
 	}
-	opts := []common.Option{}
+	opts := []common.Option{common.WithAssumeDecided()}
 	return wallet.P().IssueCreateSubnetTx(owners, opts...)
 }
+
+// parseControlKeys returns the IDs of the addresses of the control keys,
+// naming the malformed one if any
+func parseControlKeys(controlKeys []string) ([]ids.ShortID, error) {
+	addrs := make([]ids.ShortID, len(controlKeys))
+	for i, controlKey := range controlKeys {
+		addr, err := address.ParseToID(controlKey)
+		if err != nil {
+			return nil, exitcodes.UserInput(fmt.Errorf("invalid control key %q: %w", controlKey, err))
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
+// fetchPTxs returns the P-Chain transactions with the given IDs
+func fetchPTxs(ctx context.Context, pClient platformvm.Client, txIDs ...ids.ID) (map[ids.ID]*txs.Tx, error) {
+	pTXs := make(map[ids.ID]*txs.Tx)
+	for _, id := range txIDs {
+		txBytes, err := pClient.GetTx(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := txs.Parse(txs.Codec, txBytes)
+		if err != nil {
+			return nil, err
+		}
+		pTXs[id] = tx
+	}
+	return pTXs, nil
+}
+
+// unsignedBuilder returns a builder of transactions paid by payer, which
+// are left unsigned. If subnetID is set, the transactions are authorized by
+// the control keys of the subnet.
+func (d *PublicDeployer) unsignedBuilder(ctx context.Context, payer ids.ShortID, subnetID ids.ID) (p.Builder, p.Backend, []common.Option, error) {
+	api, _, err := d.healthyEndpoint(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	payerAddrs := ids.ShortSet{}
+	payerAddrs.Add(payer)
+	pCTX, _, utxos, err := primary.FetchState(ctx, api, payerAddrs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// only the UTXOs of the payer are fetched, but the builder has to know
+	// the control keys to authorize the subnet
+	builderAddrs := ids.ShortSet{}
+	builderAddrs.Add(payer)
+	pTXs := map[ids.ID]*txs.Tx{}
+	if subnetID != ids.Empty {
+		pTXs, err = fetchPTxs(ctx, platformvm.NewClient(api), subnetID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed fetching subnet %s: %w", subnetID, err)
+		}
+		if subnet, ok := pTXs[subnetID].Unsigned.(*txs.CreateSubnetTx); ok {
+			if owner, ok := subnet.Owner.(*secp256k1fx.OutputOwners); ok {
+				builderAddrs.Add(owner.Addrs...)
+			}
+		}
+	}
+	backend := p.NewBackend(pCTX, primary.NewChainUTXOs(avago_constants.PlatformChainID, utxos), pTXs)
+	// the change goes back to the payer, not to an arbitrary control key
+	opts := []common.Option{
+		common.WithContext(ctx),
+		common.WithChangeOwner(&secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{payer},
+		}),
+	}
+	return p.NewBuilder(builderAddrs, backend), backend, opts, nil
+}
+
+// BuildUnsignedCreateSubnet returns the bundle of the unsigned transaction
+// creating the subnet of sc, paid by payer
+func (d *PublicDeployer) BuildUnsignedCreateSubnet(payer ids.ShortID, controlKeys []string, threshold uint32, sc models.Sidecar) (*TxBundle, error) {
+	ctx := context.Background()
+	addrs, err := parseControlKeys(controlKeys)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkEwoqControlKeys(controlKeys); err != nil {
+		return nil, err
+	}
+	builder, backend, opts, err := d.unsignedBuilder(ctx, payer, ids.Empty)
+	if err != nil {
+		return nil, err
+	}
+	utx, err := builder.NewCreateSubnetTx(&secp256k1fx.OutputOwners{
+		Addrs:     addrs,
+		Threshold: threshold,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newTxBundle(ctx, BundleCreateSubnetTx, d.network.String(), sc.Name, utx, utx.Ins, ids.Empty, backend)
+}
+
+// BuildUnsignedCreateChain returns the bundle of the unsigned transaction
+// creating the blockchain of sc in subnetID, paid by payer
+func (d *PublicDeployer) BuildUnsignedCreateChain(payer ids.ShortID, subnetID ids.ID, sc models.Sidecar, chainGenesis string) (*TxBundle, error) {
+	ctx := context.Background()
+	genesis, err := os.ReadFile(chainGenesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading chain genesis: %w", err)
+	}
+	if err := d.CheckEwoqGenesis(sc, genesis); err != nil {
+		return nil, err
+	}
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return nil, err
+	}
+	builder, backend, opts, err := d.unsignedBuilder(ctx, payer, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	utx, err := builder.NewCreateChainTx(subnetID, genesis, vmID, []ids.ID{}, sc.Name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newTxBundle(ctx, BundleCreateChainTx, d.network.String(), sc.Name, utx, utx.Ins, subnetID, backend)
+}
+
+// BuildUnsignedAddSubnetValidator returns the bundle of the unsigned
+// transaction adding nodeID as a validator of subnetID, paid by payer
+func (d *PublicDeployer) BuildUnsignedAddSubnetValidator(
+	payer ids.ShortID,
+	subnetName string,
+	subnetID ids.ID,
+	nodeID ids.NodeID,
+	weight uint64,
+	startTime time.Time,
+	duration time.Duration,
+) (*TxBundle, error) {
+	ctx := context.Background()
+	builder, backend, opts, err := d.unsignedBuilder(ctx, payer, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	utx, err := builder.NewAddSubnetValidatorTx(newSubnetValidator(subnetID, nodeID, weight, startTime, duration), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newTxBundle(ctx, BundleAddSubnetValidatorTx, d.network.String(), subnetName, utx, utx.Ins, subnetID, backend)
+}
+
+// Broadcast issues the signed transaction of bundle to the network, and
+// waits for it to be committed
+func (d *PublicDeployer) Broadcast(bundle *TxBundle) (ids.ID, error) {
+	tx, err := bundle.GetTx()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return d.BroadcastTx(tx)
+}
+
+// BroadcastTx issues the signed transaction tx to the network, and waits for
+// it to be committed. The ID of an issued transaction is returned along with
+// the error if it isn't committed.
+func (d *PublicDeployer) BroadcastTx(tx *txs.Tx) (ids.ID, error) {
+	if err := d.app.CheckWritable("issue transactions"); err != nil {
+		return ids.Empty, err
+	}
+	api, _, err := d.endpoint()
+	if err != nil {
+		return ids.Empty, err
+	}
+	txID, err := platformvm.NewClient(api).IssueTx(context.Background(), tx.Bytes())
+	if err != nil {
+		return ids.Empty, err
+	}
+	ux.Logger.PrintToUser("Issued transaction %s", txID)
+	return txID, d.waitForTx(txID)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+// newInfoServer serves the info API of a node on networkID
+func newInfoServer(networkID uint32, bootstrapped bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			ID     int    `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result string
+		switch req.Method {
+		case "info.getNetworkID":
+			result = fmt.Sprintf(`{"networkID": "%d"}`, networkID)
+		case "info.isBootstrapped":
+			result = fmt.Sprintf(`{"isBootstrapped": %t}`, bootstrapped)
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": %s}`, req.ID, result)
+	}))
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newInfoServer(avago_constants.FujiID, true)
+	defer s.Close()
+	assert.NoError(checkEndpoint(context.Background(), s.URL, avago_constants.FujiID))
+	assert.ErrorContains(checkEndpoint(context.Background(), s.URL, avago_constants.MainnetID), "is on network fuji, not on mainnet")
+
+	bootstrapping := newInfoServer(avago_constants.FujiID, false)
+	defer bootstrapping.Close()
+	assert.ErrorContains(checkEndpoint(context.Background(), bootstrapping.URL, avago_constants.FujiID), "not finished bootstrapping")
+
+	s.Close()
+	assert.ErrorContains(checkEndpoint(context.Background(), s.URL, avago_constants.FujiID), "is not reachable")
+}
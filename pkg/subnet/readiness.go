@@ -0,0 +1,106 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// nodeReadyPollInterval is how often the nodes are polled for the readiness
+// of a new blockchain
+const nodeReadyPollInterval = time.Second
+
+// ReadyNode is a node of the local network on which a new blockchain is
+// bootstrapped, and so whose endpoints for it can be used
+type ReadyNode struct {
+	Name         string
+	URI          string
+	BlockchainID ids.ID
+}
+
+// nodeReadiness finds the blockchain of a VM on the node at uri, and tells if
+// it is bootstrapped there
+type nodeReadiness struct {
+	findChain    func(ctx context.Context, uri string) (ids.ID, error)
+	bootstrapped func(ctx context.Context, uri string, blockchainID ids.ID) (bool, error)
+}
+
+// newNodeReadiness checks the readiness of the blockchain of vmID in subnetID
+// with the P-Chain and info APIs of the nodes
+func newNodeReadiness(subnetID ids.ID, vmID ids.ID) nodeReadiness {
+	return nodeReadiness{
+		findChain: func(ctx context.Context, uri string) (ids.ID, error) {
+			blockchains, err := platformvm.NewClient(uri).GetBlockchains(ctx)
+			if err != nil {
+				return ids.Empty, err
+			}
+			// the last one is the newest, if the VM was deployed before
+			blockchainID := ids.Empty
+			for _, blockchain := range blockchains {
+				if blockchain.SubnetID == subnetID && blockchain.VMID == vmID {
+					blockchainID = blockchain.ID
+				}
+			}
+			return blockchainID, nil
+		},
+		bootstrapped: func(ctx context.Context, uri string, blockchainID ids.ID) (bool, error) {
+			return info.NewClient(uri).IsBootstrapped(ctx, blockchainID.String())
+		},
+	}
+}
+
+// watchNodesReady polls the nodes of clusterInfo every interval until ctx is
+// done, calling onReady for each node as soon as the blockchain is
+// bootstrapped on it, so that the first ready nodes can be used while the
+// others are still bootstrapping. The errors of a poll are retried at the
+// next one.
+func watchNodesReady(
+	ctx context.Context,
+	clusterInfo *rpcpb.ClusterInfo,
+	readiness nodeReadiness,
+	interval time.Duration,
+	onReady func(ReadyNode),
+) {
+	if clusterInfo == nil {
+		return
+	}
+	names := make([]string, 0, len(clusterInfo.NodeInfos))
+	for name := range clusterInfo.NodeInfos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ready := map[string]bool{}
+	blockchainID := ids.Empty
+	for len(ready) < len(names) {
+		for _, name := range names {
+			if ready[name] || ctx.Err() != nil {
+				continue
+			}
+			uri := ux.HostURI(clusterInfo.NodeInfos[name].GetUri())
+			if blockchainID == ids.Empty {
+				id, err := readiness.findChain(ctx, uri)
+				if err != nil || id == ids.Empty {
+					continue
+				}
+				blockchainID = id
+			}
+			if ok, err := readiness.bootstrapped(ctx, uri, blockchainID); err == nil && ok {
+				ready[name] = true
+				onReady(ReadyNode{Name: name, URI: uri, BlockchainID: blockchainID})
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchNodesReady(t *testing.T) {
+	assert := assert.New(t)
+
+	clusterInfo := &rpcpb.ClusterInfo{NodeInfos: map[string]*rpcpb.NodeInfo{
+		"node1": {Uri: "http://127.0.0.1:9650"},
+		"node2": {Uri: "http://127.0.0.1:9652"},
+		"node3": {Uri: "http://127.0.0.1:9654"},
+	}}
+	blockchainID := ids.GenerateTestID()
+	var (
+		lock  sync.Mutex
+		polls = map[string]int{}
+	)
+	readiness := nodeReadiness{
+		findChain: func(_ context.Context, uri string) (ids.ID, error) {
+			// the blockchain is not known by the first node yet
+			if uri == "http://127.0.0.1:9650" {
+				return ids.Empty, errors.New("not yet")
+			}
+			return blockchainID, nil
+		},
+		bootstrapped: func(_ context.Context, uri string, id ids.ID) (bool, error) {
+			assert.Equal(blockchainID, id)
+			lock.Lock()
+			defer lock.Unlock()
+			polls[uri]++
+			switch uri {
+			case "http://127.0.0.1:9652":
+				return true, nil
+			case "http://127.0.0.1:9650":
+				return polls[uri] >= 3, nil
+			}
+			return false, nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ready := []ReadyNode{}
+	watchNodesReady(ctx, clusterInfo, readiness, time.Millisecond, func(node ReadyNode) {
+		ready = append(ready, node)
+	})
+	// the nodes are reported once each, as soon as they are ready, and the
+	// watch goes on for the others until canceled
+	assert.Equal([]ReadyNode{
+		{Name: "node2", URI: "http://127.0.0.1:9652", BlockchainID: blockchainID},
+		{Name: "node1", URI: "http://127.0.0.1:9650", BlockchainID: blockchainID},
+	}, ready)
+	assert.Error(ctx.Err())
+
+	// nothing is watched without the nodes
+	watchNodesReady(context.Background(), nil, readiness, time.Millisecond, func(ReadyNode) {
+		t.Fail()
+	})
+}
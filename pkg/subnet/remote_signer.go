@@ -0,0 +1,225 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p"
+	"github.com/ava-labs/avalanchego/wallet/chain/x"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+)
+
+var (
+	errRemoteUnsupportedTx = errors.New("transaction type not supported by remote keys")
+	errRemoteUnknownInput  = errors.New("unknown input type")
+	errRemoteUnknownOutput = errors.New("unknown output type")
+	errRemoteUnknownAuth   = errors.New("unknown subnet auth type")
+	errRemoteInvalidIndex  = errors.New("invalid UTXO signature index")
+)
+
+var _ p.Signer = &remoteSigner{}
+
+// remoteSigner signs P-Chain transactions with a key held by a remote signer.
+// As avalanchego's signer needs the private keys, it mirrors its logic for
+// the transactions issued by the deployer, signing the inputs and subnet
+// auths owned by the remote key, and leaving the others unsigned.
+type remoteSigner struct {
+	key     *key.RemoteKey
+	backend p.SignerBackend
+}
+
+func (s *remoteSigner) SignUnsigned(ctx context.Context, utx txs.UnsignedTx) (*txs.Tx, error) {
+	tx := &txs.Tx{Unsigned: utx}
+	return tx, s.Sign(ctx, tx)
+}
+
+func (s *remoteSigner) Sign(ctx context.Context, tx *txs.Tx) error {
+	var (
+		ins        []*avax.TransferableInput
+		subnetID   ids.ID
+		subnetAuth verify.Verifiable
+	)
+	switch utx := tx.Unsigned.(type) {
+	case *txs.CreateSubnetTx:
+		ins = utx.Ins
+	case *txs.CreateChainTx:
+		ins, subnetID, subnetAuth = utx.Ins, utx.SubnetID, utx.SubnetAuth
+	case *txs.AddSubnetValidatorTx:
+		ins, subnetID, subnetAuth = utx.Ins, utx.Validator.Subnet, utx.SubnetAuth
+	case *txs.AddValidatorTx:
+		ins = utx.Ins
+	case *txs.AddDelegatorTx:
+		ins = utx.Ins
+	default:
+		return fmt.Errorf("%w: %T", errRemoteUnsupportedTx, tx.Unsigned)
+	}
+
+	// for every credential, whether each of its signatures is ours
+	ours, err := s.inputSigners(ctx, ins)
+	if err != nil {
+		return err
+	}
+	if subnetAuth != nil {
+		authOurs, err := s.subnetSigners(ctx, subnetID, subnetAuth)
+		if err != nil {
+			return err
+		}
+		ours = append(ours, authOurs)
+	}
+	return s.sign(ctx, tx, ours)
+}
+
+func (s *remoteSigner) inputSigners(ctx context.Context, ins []*avax.TransferableInput) ([][]bool, error) {
+	ours := make([][]bool, len(ins))
+	for i, transferInput := range ins {
+		input, ok := transferInput.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			return nil, errRemoteUnknownInput
+		}
+		ours[i] = make([]bool, len(input.SigIndices))
+
+		utxo, err := s.backend.GetUTXO(ctx, avago_constants.PlatformChainID, transferInput.InputID())
+		if err == database.ErrNotFound {
+			// not our UTXO, leave it to be signed by others
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		outIntf := utxo.Out
+		if stakeableOut, ok := outIntf.(*stakeable.LockOut); ok {
+			outIntf = stakeableOut.TransferableOut
+		}
+		out, ok := outIntf.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return nil, errRemoteUnknownOutput
+		}
+		for sigIndex, addrIndex := range input.SigIndices {
+			if addrIndex >= uint32(len(out.Addrs)) {
+				return nil, errRemoteInvalidIndex
+			}
+			ours[i][sigIndex] = out.Addrs[addrIndex] == s.key.Address()
+		}
+	}
+	return ours, nil
+}
+
+func (s *remoteSigner) subnetSigners(ctx context.Context, subnetID ids.ID, subnetAuth verify.Verifiable) ([]bool, error) {
+	input, ok := subnetAuth.(*secp256k1fx.Input)
+	if !ok {
+		return nil, errRemoteUnknownAuth
+	}
+	subnetTx, err := s.backend.GetTx(ctx, subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subnet %q: %w", subnetID, err)
+	}
+	subnet, ok := subnetTx.Unsigned.(*txs.CreateSubnetTx)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a subnet", subnetID)
+	}
+	owner, ok := subnet.Owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil, errRemoteUnknownAuth
+	}
+	ours := make([]bool, len(input.SigIndices))
+	for sigIndex, addrIndex := range input.SigIndices {
+		if addrIndex >= uint32(len(owner.Addrs)) {
+			return nil, errRemoteInvalidIndex
+		}
+		ours[sigIndex] = owner.Addrs[addrIndex] == s.key.Address()
+	}
+	return ours, nil
+}
+
+// sign fills the credentials of tx, with a single remote signature reused
+// wherever the key has to sign
+func (s *remoteSigner) sign(ctx context.Context, tx *txs.Tx, ours [][]bool) error {
+	unsignedBytes, err := txs.Codec.Marshal(txs.Version, &tx.Unsigned)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal unsigned tx: %w", err)
+	}
+	unsignedHash := hashing.ComputeHash256(unsignedBytes)
+
+	if len(tx.Creds) != len(ours) {
+		tx.Creds = make([]verify.Verifiable, len(ours))
+	}
+	var sig []byte
+	for credIndex, credOurs := range ours {
+		if tx.Creds[credIndex] == nil {
+			tx.Creds[credIndex] = &secp256k1fx.Credential{}
+		}
+		cred, ok := tx.Creds[credIndex].(*secp256k1fx.Credential)
+		if !ok {
+			return errors.New("unknown credential type")
+		}
+		if len(cred.Sigs) != len(credOurs) {
+			cred.Sigs = make([][crypto.SECP256K1RSigLen]byte, len(credOurs))
+		}
+		for sigIndex, isOurs := range credOurs {
+			if !isOurs {
+				continue
+			}
+			if sig == nil {
+				sig, err = s.key.SignHash(ctx, unsignedHash)
+				if err != nil {
+					return fmt.Errorf("problem signing tx: %w", err)
+				}
+			}
+			copy(cred.Sigs[sigIndex][:], sig)
+		}
+	}
+
+	signedBytes, err := txs.Codec.Marshal(txs.Version, tx)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal tx: %w", err)
+	}
+	tx.Initialize(unsignedBytes, signedBytes)
+	return nil
+}
+
+// newRemoteWallet returns a wallet issuing P-Chain transactions signed by
+// the remote key. X-Chain transactions can't be signed.
+func newRemoteWallet(ctx context.Context, uri string, k *key.RemoteKey, preloadTxs ...ids.ID) (primary.Wallet, error) {
+	addrs := ids.ShortSet{}
+	addrs.Add(k.Address())
+	pCTX, xCTX, utxos, err := primary.FetchState(ctx, uri, addrs)
+	if err != nil {
+		return nil, err
+	}
+	pClient := platformvm.NewClient(uri)
+	pTXs, err := fetchPTxs(ctx, pClient, preloadTxs...)
+	if err != nil {
+		return nil, err
+	}
+
+	pBackend := p.NewBackend(pCTX, primary.NewChainUTXOs(avago_constants.PlatformChainID, utxos), pTXs)
+	pBuilder := p.NewBuilder(addrs, pBackend)
+	pSigner := &remoteSigner{key: k, backend: pBackend}
+
+	xChainID := xCTX.BlockchainID()
+	xBackend := x.NewBackend(xCTX, xChainID, primary.NewChainUTXOs(xChainID, utxos))
+	xBuilder := x.NewBuilder(addrs, xBackend)
+	// no private key to sign with on the X-Chain
+	xSigner := x.NewSigner(secp256k1fx.NewKeychain(), xBackend)
+
+	return primary.NewWallet(
+		p.NewWallet(pBuilder, pSigner, pClient, pBackend),
+		x.NewWallet(xBuilder, xSigner, avm.NewClient(uri, "X"), xBackend),
+	), nil
+}
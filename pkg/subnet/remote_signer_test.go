@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	eth_crypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// localSigner is a key.RemoteSigner holding a local key
+type localSigner struct {
+	sk *ecdsa.PrivateKey
+}
+
+func (s *localSigner) PublicKey(context.Context) ([]byte, error) {
+	params, err := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: eth_crypto.FromECDSAPub(&s.sk.PublicKey), BitLength: 65 * 8},
+	})
+}
+
+func (s *localSigner) SignDigest(_ context.Context, digest []byte) ([]byte, error) {
+	rsv, err := eth_crypto.Sign(digest, s.sk)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(rsv[:32]), new(big.Int).SetBytes(rsv[32:64])})
+}
+
+type fakeSignerBackend struct {
+	utxos map[ids.ID]*avax.UTXO
+	txs   map[ids.ID]*txs.Tx
+}
+
+func (b *fakeSignerBackend) GetUTXO(_ context.Context, _, utxoID ids.ID) (*avax.UTXO, error) {
+	utxo, ok := b.utxos[utxoID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return utxo, nil
+}
+
+func (b *fakeSignerBackend) GetTx(_ context.Context, txID ids.ID) (*txs.Tx, error) {
+	tx, ok := b.txs[txID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return tx, nil
+}
+
+func TestRemoteSignerCreateChainTx(t *testing.T) {
+	assert := assert.New(t)
+
+	sk, err := eth_crypto.GenerateKey()
+	assert.NoError(err)
+	k, err := key.NewRemoteKey(context.Background(), avago_constants.FujiID, &localSigner{sk: sk})
+	assert.NoError(err)
+	otherAddr := ids.GenerateTestShortID()
+
+	ours := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Out:    &secp256k1fx.TransferOutput{Amt: 10, OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{k.Address()}}},
+	}
+	subnetID := ids.GenerateTestID()
+	backend := &fakeSignerBackend{
+		utxos: map[ids.ID]*avax.UTXO{ours.InputID(): ours},
+		txs: map[ids.ID]*txs.Tx{subnetID: {Unsigned: &txs.CreateSubnetTx{
+			Owner: &secp256k1fx.OutputOwners{Threshold: 2, Addrs: []ids.ShortID{otherAddr, k.Address()}},
+		}}},
+	}
+
+	utx := &txs.CreateChainTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    avago_constants.FujiID,
+			BlockchainID: avago_constants.PlatformChainID,
+			Ins: []*avax.TransferableInput{
+				{UTXOID: ours.UTXOID, In: &secp256k1fx.TransferInput{Amt: 10, Input: secp256k1fx.Input{SigIndices: []uint32{0}}}},
+				// not known to the backend, left unsigned
+				{UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()}, In: &secp256k1fx.TransferInput{Amt: 5, Input: secp256k1fx.Input{SigIndices: []uint32{0}}}},
+			},
+		}},
+		SubnetID:   subnetID,
+		ChainName:  "test",
+		SubnetAuth: &secp256k1fx.Input{SigIndices: []uint32{0, 1}},
+	}
+	s := &remoteSigner{key: k, backend: backend}
+	tx, err := s.SignUnsigned(context.Background(), utx)
+	assert.NoError(err)
+
+	assert.Len(tx.Creds, 3)
+	unsignedBytes, err := txs.Codec.Marshal(txs.Version, &tx.Unsigned)
+	assert.NoError(err)
+	hash := hashing.ComputeHash256(unsignedBytes)
+	factory := crypto.FactorySECP256K1R{}
+	recovered := func(sig [crypto.SECP256K1RSigLen]byte) ids.ShortID {
+		pubKey, err := factory.RecoverHashPublicKey(hash, sig[:])
+		assert.NoError(err)
+		return pubKey.Address()
+	}
+	empty := [crypto.SECP256K1RSigLen]byte{}
+
+	inputCred := tx.Creds[0].(*secp256k1fx.Credential)
+	assert.Equal(k.Address(), recovered(inputCred.Sigs[0]))
+	assert.Equal(empty, tx.Creds[1].(*secp256k1fx.Credential).Sigs[0])
+	authCred := tx.Creds[2].(*secp256k1fx.Credential)
+	assert.Equal(empty, authCred.Sigs[0])
+	assert.Equal(k.Address(), recovered(authCred.Sigs[1]))
+
+	_, err = s.SignUnsigned(context.Background(), &txs.ImportTx{})
+	assert.ErrorIs(err, errRemoteUnsupportedTx)
+}
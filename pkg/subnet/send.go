@@ -0,0 +1,101 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ava-labs/coreth/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const receiptPollInterval = time.Second
+
+// ParseTokenAmount converts an amount of tokens given in decimal notation,
+// with up to decimals decimals, to the units of the chain, e.g. wei
+func ParseTokenAmount(amount string, decimals int) (*big.Int, error) {
+	invalid := fmt.Errorf("invalid amount %q", amount)
+	whole, fraction := amount, ""
+	if i := strings.Index(amount, "."); i >= 0 {
+		whole, fraction = amount[:i], amount[i+1:]
+	}
+	if whole == "" && fraction == "" {
+		return nil, invalid
+	}
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("amount %q has more than %d decimals", amount, decimals)
+	}
+	digits := whole + fraction + strings.Repeat("0", decimals-len(fraction))
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return nil, invalid
+		}
+	}
+	units, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, invalid
+	}
+	return units, nil
+}
+
+// SendNative transfers amount units of the native token of the EVM chain at
+// rpcURL from the address of sk to to, and waits for the transaction to be
+// accepted. Returns the hash of the transaction.
+func SendNative(ctx context.Context, rpcURL string, sk *ecdsa.PrivateKey, to common.Address, amount *big.Int) (common.Hash, error) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer client.Close()
+
+	from := crypto.PubkeyToAddress(sk.PublicKey)
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed getting the chain ID: %w", err)
+	}
+	nonce, err := client.AcceptedNonceAt(ctx, from)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed getting the nonce of %s: %w", from, err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed getting the gas price: %w", err)
+	}
+	tx, err := types.SignTx(
+		types.NewTransaction(nonce, to, amount, transferGas, gasPrice, nil),
+		types.LatestSignerForChainID(chainID),
+		sk,
+	)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed sending the transaction: %w", err)
+	}
+	for {
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		switch {
+		case errors.Is(err, interfaces.NotFound):
+		case err != nil:
+			return tx.Hash(), fmt.Errorf("failed getting the receipt of transaction %s: %w", tx.Hash(), err)
+		case receipt.Status != types.ReceiptStatusSuccessful:
+			return tx.Hash(), fmt.Errorf("transaction %s failed", tx.Hash())
+		default:
+			return tx.Hash(), nil
+		}
+		select {
+		case <-ctx.Done():
+			return tx.Hash(), fmt.Errorf("transaction %s not accepted: %w", tx.Hash(), ctx.Err())
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
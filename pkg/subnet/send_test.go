@@ -0,0 +1,115 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenAmount(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		amount     string
+		decimals   int
+		expected   string
+		shouldFail bool
+	}{
+		{amount: "1", decimals: 18, expected: "1000000000000000000"},
+		{amount: "0.5", decimals: 18, expected: "500000000000000000"},
+		{amount: ".25", decimals: 2, expected: "25"},
+		{amount: "2.", decimals: 0, expected: "2"},
+		{amount: "1000000000000", decimals: 18, expected: "1000000000000000000000000000000"},
+		{amount: "0.001", decimals: 2, shouldFail: true},
+		{amount: "", decimals: 18, shouldFail: true},
+		{amount: ".", decimals: 18, shouldFail: true},
+		{amount: "-1", decimals: 18, shouldFail: true},
+		{amount: "1,5", decimals: 18, shouldFail: true},
+		{amount: "1e18", decimals: 18, shouldFail: true},
+	}
+	for _, tt := range tests {
+		units, err := ParseTokenAmount(tt.amount, tt.decimals)
+		if tt.shouldFail {
+			assert.Error(err, tt.amount)
+			continue
+		}
+		assert.NoError(err, tt.amount)
+		assert.Equal(tt.expected, units.String(), tt.amount)
+	}
+}
+
+func TestSendNative(t *testing.T) {
+	assert := assert.New(t)
+
+	sk, err := crypto.HexToECDSA(vm.PrefundedEwoqPrivate)
+	assert.NoError(err)
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	chainID := big.NewInt(9999)
+
+	var sent *types.Transaction
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var result interface{}
+		switch req.Method {
+		case "eth_chainId":
+			result = hexutil.EncodeBig(chainID)
+		case "eth_getTransactionCount":
+			result = "0x7"
+		case "eth_gasPrice":
+			result = "0x5d21dba00"
+		case "eth_sendRawTransaction":
+			var raw hexutil.Bytes
+			_ = json.Unmarshal(req.Params[0], &raw)
+			sent = new(types.Transaction)
+			_ = sent.UnmarshalBinary(raw)
+			result = sent.Hash()
+		case "eth_getTransactionReceipt":
+			result = map[string]interface{}{
+				"status":            "0x1",
+				"cumulativeGasUsed": "0x5208",
+				"gasUsed":           "0x5208",
+				"logsBloom":         hexutil.Bytes(make([]byte, 256)),
+				"logs":              []interface{}{},
+				"transactionHash":   sent.Hash(),
+			}
+		default:
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+			return
+		}
+		resultBytes, _ := json.Marshal(result)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, resultBytes)
+	}))
+	defer s.Close()
+
+	txHash, err := SendNative(context.Background(), s.URL, sk, to, big.NewInt(42))
+	assert.NoError(err)
+	assert.Equal(sent.Hash(), txHash)
+	assert.Equal(uint64(7), sent.Nonce())
+	assert.Equal(&to, sent.To())
+	assert.Equal(big.NewInt(42), sent.Value())
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), sent)
+	assert.NoError(err)
+	assert.Equal(vm.PrefundedEwoqAddress, from)
+}
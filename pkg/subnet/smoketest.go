@@ -0,0 +1,190 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ava-labs/coreth/interfaces"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// gas used by a plain value transfer
+const transferGas = 21000
+
+// SmokeTestResult is the outcome of the RPC smoke test of a chain on one node
+type SmokeTestResult struct {
+	Node string
+	URL  string
+	// Err is nil if the test passed
+	Err error
+}
+
+// GetNodeRPCURLs returns, for every node of the cluster, the RPC URL of the
+// given blockchain
+func GetNodeRPCURLs(clusterInfo *rpcpb.ClusterInfo, blockchainID string) map[string]string {
+	urls := map[string]string{}
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		urls[nodeInfo.Name] = ux.RPCEndpoint(nodeInfo.GetUri(), blockchainID)
+	}
+	return urls
+}
+
+// GetNodeWSURLs returns, for every node of the cluster, the WebSocket URL of
+// the given blockchain
+func GetNodeWSURLs(clusterInfo *rpcpb.ClusterInfo, blockchainID string) map[string]string {
+	urls := map[string]string{}
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		urls[nodeInfo.Name] = ux.WSEndpoint(nodeInfo.GetUri(), blockchainID)
+	}
+	return urls
+}
+
+// rateLimiter spaces the requests of the smoke test, not to overload nodes
+// which just started
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+func (r *rateLimiter) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.ticker.C:
+		return nil
+	}
+}
+
+// RunSmokeTest checks the RPC of an EVM chain works on every node, given by
+// name with its RPC URL: it queries eth_chainId and eth_blockNumber and, if sk
+// is not nil, sends a zero-value transaction from sk to itself and waits for
+// its receipt. A request is sent at most every interval.
+func RunSmokeTest(
+	ctx context.Context,
+	urls map[string]string,
+	chainID *big.Int,
+	sk *ecdsa.PrivateKey,
+	interval time.Duration,
+) []SmokeTestResult {
+	limiter := &rateLimiter{ticker: time.NewTicker(interval)}
+	defer limiter.ticker.Stop()
+
+	nodes := make([]string, 0, len(urls))
+	for node := range urls {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	results := make([]SmokeTestResult, 0, len(nodes))
+	for _, node := range nodes {
+		url := urls[node]
+		results = append(results, SmokeTestResult{
+			Node: node,
+			URL:  url,
+			Err:  smokeTestNode(ctx, limiter, url, chainID, sk),
+		})
+	}
+	return results
+}
+
+func smokeTestNode(
+	ctx context.Context,
+	limiter *rateLimiter,
+	url string,
+	chainID *big.Int,
+	sk *ecdsa.PrivateKey,
+) error {
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed connecting: %w", err)
+	}
+	defer client.Close()
+
+	if err := limiter.wait(ctx); err != nil {
+		return err
+	}
+	nodeChainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("eth_chainId failed: %w", err)
+	}
+	if nodeChainID.Cmp(chainID) != 0 {
+		return fmt.Errorf("eth_chainId returned %s, expected %s", nodeChainID, chainID)
+	}
+
+	if err := limiter.wait(ctx); err != nil {
+		return err
+	}
+	if _, err := client.BlockNumber(ctx); err != nil {
+		return fmt.Errorf("eth_blockNumber failed: %w", err)
+	}
+
+	if sk == nil {
+		return nil
+	}
+	return smokeTestTransfer(ctx, limiter, client, chainID, sk)
+}
+
+// smokeTestTransfer sends a zero-value transfer from sk to itself and waits
+// for its receipt
+func smokeTestTransfer(
+	ctx context.Context,
+	limiter *rateLimiter,
+	client ethclient.Client,
+	chainID *big.Int,
+	sk *ecdsa.PrivateKey,
+) error {
+	addr := crypto.PubkeyToAddress(sk.PublicKey)
+	if err := limiter.wait(ctx); err != nil {
+		return err
+	}
+	nonce, err := client.AcceptedNonceAt(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("failed getting the nonce of %s: %w", addr, err)
+	}
+	if err := limiter.wait(ctx); err != nil {
+		return err
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("eth_gasPrice failed: %w", err)
+	}
+	tx, err := types.SignTx(
+		types.NewTransaction(nonce, addr, big.NewInt(0), transferGas, gasPrice, nil),
+		types.LatestSignerForChainID(chainID),
+		sk,
+	)
+	if err != nil {
+		return err
+	}
+	if err := limiter.wait(ctx); err != nil {
+		return err
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("eth_sendRawTransaction failed: %w", err)
+	}
+	for {
+		if err := limiter.wait(ctx); err != nil {
+			return fmt.Errorf("transaction %s not accepted: %w", tx.Hash(), err)
+		}
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		switch {
+		case errors.Is(err, interfaces.NotFound):
+			continue
+		case err != nil:
+			return fmt.Errorf("eth_getTransactionReceipt failed: %w", err)
+		case receipt.Status != types.ReceiptStatusSuccessful:
+			return fmt.Errorf("transaction %s failed", tx.Hash())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeRPCServer answers eth_chainId and eth_blockNumber with the given chain ID
+func newFakeRPCServer(chainID int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var result string
+		switch req.Method {
+		case "eth_chainId":
+			result = fmt.Sprintf("0x%x", chainID)
+		case "eth_blockNumber":
+			result = "0x1"
+		default:
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%q}`, req.ID, result)
+	}))
+}
+
+func TestRunSmokeTest(t *testing.T) {
+	assert := assert.New(t)
+
+	good := newFakeRPCServer(9999)
+	defer good.Close()
+	wrongChain := newFakeRPCServer(1)
+	defer wrongChain.Close()
+	down := newFakeRPCServer(9999)
+	down.Close()
+
+	urls := map[string]string{
+		"node1": good.URL,
+		"node2": wrongChain.URL,
+		"node3": down.URL,
+	}
+	results := RunSmokeTest(context.Background(), urls, big.NewInt(9999), nil, time.Millisecond)
+	assert.Len(results, 3)
+	assert.Equal("node1", results[0].Node)
+	assert.NoError(results[0].Err)
+	assert.Equal("node2", results[1].Node)
+	assert.ErrorContains(results[1].Err, "eth_chainId returned 1, expected 9999")
+	assert.Equal("node3", results[2].Node)
+	assert.Error(results[2].Err)
+}
+
+func TestGetNodeRPCURLs(t *testing.T) {
+	assert := assert.New(t)
+
+	urls := GetNodeRPCURLs(fakeHealthResponse.ClusterInfo, testBlockChainID1)
+	assert.Equal(map[string]string{
+		"testNode1": "http://fake.localhost:12345/ext/bc/" + testBlockChainID1 + "/rpc",
+		"testNode2": "http://fake.localhost:12345/ext/bc/" + testBlockChainID1 + "/rpc",
+	}, urls)
+
+	assert.Empty(GetNodeRPCURLs(&rpcpb.ClusterInfo{}, testBlockChainID1))
+}
+
+func TestGetNodeWSURLs(t *testing.T) {
+	assert := assert.New(t)
+
+	urls := GetNodeWSURLs(fakeHealthResponse.ClusterInfo, testBlockChainID1)
+	assert.Equal(map[string]string{
+		"testNode1": "ws://fake.localhost:12345/ext/bc/" + testBlockChainID1 + "/ws",
+		"testNode2": "ws://fake.localhost:12345/ext/bc/" + testBlockChainID1 + "/ws",
+	}, urls)
+
+	secure := &rpcpb.ClusterInfo{NodeInfos: map[string]*rpcpb.NodeInfo{
+		"node1": {Name: "node1", Uri: "https://node.example.com"},
+	}}
+	assert.Equal("wss://node.example.com/ext/bc/"+testBlockChainID1+"/ws", GetNodeWSURLs(secure, testBlockChainID1)["node1"])
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// the subdir of a snapshot with the databases of its nodes, each one at
+// db/<node>/<network name>/<database version>
+const snapshotDBDir = "db"
+
+// ErrIncompatibleSnapshot is returned for a snapshot the nodes of the local
+// network would fail to load, crashing until the health checks time out
+var ErrIncompatibleSnapshot = errors.New("incompatible snapshot")
+
+// SnapshotDatabaseVersions returns the versions of the databases of the
+// nodes of the snapshot snapshotName, sorted
+func SnapshotDatabaseVersions(snapshotsDir string, snapshotName string) ([]string, error) {
+	dbDirs, err := filepath.Glob(filepath.Join(snapshotsDir, snapshotPrefix+snapshotName, snapshotDBDir, "*", "*", "v*"))
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, dbDir := range dbDirs {
+		if info, err := os.Stat(dbDir); err == nil && info.IsDir() {
+			found[filepath.Base(dbDir)] = true
+		}
+	}
+	versions := make([]string, 0, len(found))
+	for v := range found {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// CheckSnapshotCompatibility returns an ErrIncompatibleSnapshot if the nodes
+// of the local network of app may not load the snapshot snapshotName
+func CheckSnapshotCompatibility(app *application.Avalanche, snapshotName string) error {
+	reason, err := snapshotIncompatibility(app, snapshotName)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		return fmt.Errorf("%w: %s", ErrIncompatibleSnapshot, reason)
+	}
+	return nil
+}
+
+// snapshotIncompatibility returns why the nodes of the local network of app
+// may not load the snapshot snapshotName, if they may not: it has databases
+// of a version avalanchego neither uses nor migrates from, or it was saved
+// by a newer avalanchego than the local one
+func snapshotIncompatibility(app *application.Avalanche, snapshotName string) (string, error) {
+	dbVersions, err := SnapshotDatabaseVersions(app.GetSnapshotsDir(), snapshotName)
+	if err != nil {
+		return "", err
+	}
+	// the database version only changes with avalanchego major releases, so
+	// the one of the avalanchego this CLI is built with is the local one
+	supported := []string{version.CurrentDatabase.String(), version.PrevDatabase.String()}
+	for _, v := range dbVersions {
+		if v != supported[0] && v != supported[1] {
+			return fmt.Sprintf("snapshot %s has databases of version %s, while avalanchego uses %s",
+				snapshotName, v, strings.Join(supported, " or ")), nil
+		}
+	}
+
+	record, recorded, err := LoadNamedSnapshotRecord(app, snapshotName)
+	if err != nil || !recorded {
+		return "", err
+	}
+	localVersion := LocalAvalancheGoVersion(app)
+	saved, err := version.Parse(record.AvalancheGoVersion)
+	if err != nil {
+		return "", nil
+	}
+	local, err := version.Parse(localVersion)
+	if err != nil {
+		return "", nil
+	}
+	if saved.Compare(local) > 0 {
+		return fmt.Sprintf("snapshot %s was saved by avalanchego %s, newer than the local avalanchego %s",
+			snapshotName, record.AvalancheGoVersion, localVersion), nil
+	}
+	return "", nil
+}
+
+// RepairDefaultSnapshot rebuilds the default snapshot with the local
+// avalanchego, after confirmation, if its nodes may not load it. The local
+// network must not be running.
+func (d *LocalSubnetDeployer) RepairDefaultSnapshot() error {
+	reason, err := snapshotIncompatibility(d.app, constants.DefaultSnapshotName)
+	if err != nil || reason == "" {
+		return err
+	}
+	if err := d.app.Prompt.ConfirmDestructive(
+		fmt.Sprintf("The local network can't start: %s. Rebuilding the snapshot with the local avalanchego loses the state of the deployed subnets of profile %s.",
+			reason, d.app.GetProfile()),
+		d.app.GetProfile(),
+	); err != nil {
+		return fmt.Errorf("%s, rebuild it with avalanche network snapshot rebuild-default: %w", reason, err)
+	}
+	networkID, err := LocalNetworkID(d.app)
+	if err != nil {
+		return err
+	}
+	// a standalone network keeps its network ID
+	if networkID == constants.LocalNetworkID {
+		networkID = 0
+	}
+	if err := d.RebuildDefaultSnapshot(constants.BootstrapSnapshotSubnets, networkID); err != nil {
+		return err
+	}
+	return ClearUndeployed(d.app)
+}
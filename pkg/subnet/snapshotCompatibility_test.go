@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckSnapshotCompatibility(t *testing.T) {
+	assert := setupTest(t)
+
+	d := newRebuildTestDeployer(t, &mocks.Client{}, fakeSetDefaultSnapshot)
+	snapshotsDir := d.app.GetSnapshotsDir()
+	nodeDBDir := filepath.Join(snapshotsDir, snapshotPrefix+constants.DefaultSnapshotName, snapshotDBDir, "node1", "network-1337")
+
+	// not installed yet
+	assert.NoError(CheckSnapshotCompatibility(d.app, constants.DefaultSnapshotName))
+
+	assert.NoError(os.MkdirAll(filepath.Join(nodeDBDir, "v1.4.5"), perms.ReadWriteExecute))
+	assert.NoError(CheckSnapshotCompatibility(d.app, constants.DefaultSnapshotName))
+	versions, err := SnapshotDatabaseVersions(snapshotsDir, constants.DefaultSnapshotName)
+	assert.NoError(err)
+	assert.Equal([]string{"v1.4.5"}, versions)
+
+	// saved by an older avalanchego
+	assert.NoError(os.WriteFile(snapshotRecordPath(snapshotsDir, constants.DefaultSnapshotName),
+		[]byte(`{"avalancheGoVersion": "v1.0.0"}`), perms.ReadWrite))
+	assert.NoError(CheckSnapshotCompatibility(d.app, constants.DefaultSnapshotName))
+
+	// saved by a newer avalanchego
+	assert.NoError(os.WriteFile(snapshotRecordPath(snapshotsDir, constants.DefaultSnapshotName),
+		[]byte(`{"avalancheGoVersion": "v99.0.0"}`), perms.ReadWrite))
+	err = CheckSnapshotCompatibility(d.app, constants.DefaultSnapshotName)
+	assert.ErrorIs(err, ErrIncompatibleSnapshot)
+	assert.ErrorContains(err, "avalanchego v99.0.0")
+	assert.NoError(os.Remove(snapshotRecordPath(snapshotsDir, constants.DefaultSnapshotName)))
+
+	// databases of an unknown version
+	assert.NoError(os.MkdirAll(filepath.Join(nodeDBDir, "v2.0.0"), perms.ReadWriteExecute))
+	err = CheckSnapshotCompatibility(d.app, constants.DefaultSnapshotName)
+	assert.ErrorIs(err, ErrIncompatibleSnapshot)
+	assert.ErrorContains(err, "version v2.0.0")
+
+	// the rebuild is declined
+	prompter := &mocks.Prompter{}
+	prompter.On("ConfirmDestructive", mock.Anything, mock.Anything).Return(errors.New("not confirmed"))
+	d.app.Prompt = prompter
+	err = d.RepairDefaultSnapshot()
+	assert.ErrorContains(err, "network snapshot rebuild-default")
+	assert.ErrorContains(err, "not confirmed")
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// number of latest blocks the recent activity of a chain is computed over
+const statsRecentBlocks = 20
+
+// errNotIndexed is returned by an index API which doesn't know a chain
+var errNotIndexed = errors.New("not indexed")
+
+// ChainStats is a health summary of a chain of Fuji or mainnet, from the
+// public index APIs and the P-Chain
+type ChainStats struct {
+	BlockHeight   uint64
+	LastBlockTime time.Time
+	// TxCount is the number of transactions of the chain since its genesis,
+	// nil if the metrics API has not indexed it yet
+	TxCount *uint64
+	// RecentBlocks are the latest blocks RecentTxs was counted over, the
+	// first of which was built at RecentSince
+	RecentBlocks int
+	RecentTxs    uint64
+	RecentSince  time.Time
+	Validators   int
+}
+
+// glacierBlocks is the response of the Glacier API listing the latest blocks
+// of an EVM chain
+type glacierBlocks struct {
+	Blocks []struct {
+		BlockNumber    string `json:"blockNumber"`
+		BlockTimestamp int64  `json:"blockTimestamp"`
+		TxCount        uint64 `json:"txCount"`
+	} `json:"blocks"`
+}
+
+// metricsResults is the response of the metrics API for a metric of a chain
+type metricsResults struct {
+	Results []struct {
+		Value     uint64 `json:"value"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"results"`
+}
+
+// StatsClient queries the Glacier and metrics index APIs
+type StatsClient struct {
+	GlacierURL string
+	MetricsURL string
+}
+
+// getJSON decodes the JSON response of a GET of url into out. Returns
+// errNotIndexed if the API doesn't know the resource.
+func getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed querying %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotIndexed
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed querying %s: unexpected http status code: %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed decoding the response of %s: %w", url, err)
+	}
+	return nil
+}
+
+// GetChainStats summarizes the chain with EVM chain ID chainID, of subnetID.
+// The blocks come from Glacier, the transaction count from the metrics API,
+// and the validators from the P-Chain.
+func (c StatsClient) GetChainStats(ctx context.Context, validators validatorsClient, subnetID ids.ID, chainID uint64) (ChainStats, error) {
+	stats := ChainStats{}
+	var blocks glacierBlocks
+	url := fmt.Sprintf("%s/v1/chains/%d/blocks?pageSize=%d", c.GlacierURL, chainID, statsRecentBlocks)
+	if err := getJSON(ctx, url, &blocks); err != nil {
+		if errors.Is(err, errNotIndexed) {
+			return stats, exitcodes.UserInput(fmt.Errorf("chain %d is not indexed by Glacier at %s", chainID, c.GlacierURL))
+		}
+		return stats, err
+	}
+	// the blocks are listed from the latest one
+	for i, block := range blocks.Blocks {
+		if i == 0 {
+			height, err := strconv.ParseUint(block.BlockNumber, 10, 64)
+			if err != nil {
+				return stats, fmt.Errorf("invalid block number %q from Glacier: %w", block.BlockNumber, err)
+			}
+			stats.BlockHeight = height
+			stats.LastBlockTime = time.Unix(block.BlockTimestamp, 0)
+		}
+		stats.RecentBlocks++
+		stats.RecentTxs += block.TxCount
+		stats.RecentSince = time.Unix(block.BlockTimestamp, 0)
+	}
+
+	var txCount metricsResults
+	url = fmt.Sprintf("%s/v2/chains/%d/metrics/cumulativeTxCount?timeInterval=day&pageSize=1", c.MetricsURL, chainID)
+	switch err := getJSON(ctx, url, &txCount); {
+	case errors.Is(err, errNotIndexed):
+	case err != nil:
+		return stats, err
+	case len(txCount.Results) > 0:
+		stats.TxCount = &txCount.Results[0].Value
+	}
+
+	current, err := validators.GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return stats, fmt.Errorf("failed getting the validators of subnet %s: %w", subnetID, err)
+	}
+	stats.Validators = len(current)
+	return stats, nil
+}
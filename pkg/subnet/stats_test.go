@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChainStats(t *testing.T) {
+	assert := assert.New(t)
+
+	indexed := true
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chains/12345/blocks":
+			assert.Equal(fmt.Sprint(statsRecentBlocks), r.URL.Query().Get("pageSize"))
+			fmt.Fprint(w, `{"blocks": [
+				{"blockNumber": "42", "blockTimestamp": 1700000060, "txCount": 3},
+				{"blockNumber": "41", "blockTimestamp": 1700000030, "txCount": 0},
+				{"blockNumber": "40", "blockTimestamp": 1700000000, "txCount": 2}
+			]}`)
+		case "/v2/chains/12345/metrics/cumulativeTxCount":
+			if !indexed {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, `{"results": [{"value": 1234, "timestamp": 1700000000}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	client := StatsClient{GlacierURL: s.URL, MetricsURL: s.URL}
+	validators := &fakeValidatorsClient{statuses: []ValidatorStatus{ValidatorCurrent}}
+	stats, err := client.GetChainStats(context.Background(), validators, ids.Empty, 12345)
+	assert.NoError(err)
+	txCount := uint64(1234)
+	assert.Equal(ChainStats{
+		BlockHeight:   42,
+		LastBlockTime: time.Unix(1700000060, 0),
+		TxCount:       &txCount,
+		RecentBlocks:  3,
+		RecentTxs:     5,
+		RecentSince:   time.Unix(1700000000, 0),
+		Validators:    1,
+	}, stats)
+
+	// the transaction count is optional
+	indexed = false
+	stats, err = client.GetChainStats(context.Background(), validators, ids.Empty, 12345)
+	assert.NoError(err)
+	assert.Nil(stats.TxCount)
+	assert.Equal(uint64(42), stats.BlockHeight)
+
+	// the blocks are not
+	_, err = client.GetChainStats(context.Background(), validators, ids.Empty, 1)
+	assert.ErrorContains(err, "not indexed by Glacier")
+}
@@ -0,0 +1,343 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package subnettest runs throwaway local networks with a deployed subnet
+// for the integration tests of VMs and dapps. Each network lives in its own
+// temporary directory, with its own profile and ports, so that the tests of
+// several packages can run at the same time, next to the local network of
+// the user. The releases are downloaded once in a shared cache.
+//
+// In a TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		network, err := subnettest.Start(subnettest.Options{})
+//		if err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//		rpcURL = network.RPCEndpoints[0]
+//		code := m.Run()
+//		_ = network.Stop()
+//		os.Exit(code)
+//	}
+//
+// Or per test, with New, which stops the network at the end of the test.
+package subnettest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/lock"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+)
+
+const (
+	// DefaultSubnetName is the name of the subnet deployed by default
+	DefaultSubnetName = "testsubnet"
+	// DefaultChainID is the chain ID of DefaultGenesis
+	DefaultChainID = 99999
+
+	// profile isolates the test networks from the local network of the user
+	profile = "subnettest"
+	// binCacheLock serializes the downloads to the shared cache
+	binCacheLock = ".lock"
+	// lockRetryInterval is how often the lock of the cache is tried
+	lockRetryInterval = 500 * time.Millisecond
+	// defaultTimeout bounds the start of a network
+	defaultTimeout = 5 * time.Minute
+)
+
+// Options configures a test network. The zero value deploys DefaultGenesis
+// with the release versions of this module, and the fast-dev node profile.
+type Options struct {
+	// SubnetName is the name of the deployed subnet, DefaultSubnetName if
+	// empty
+	SubnetName string
+	// Genesis is the subnet-evm genesis of the deployed subnet,
+	// DefaultGenesis if nil
+	Genesis []byte
+	// AvalancheGoVersion pins the release of avalanchego, e.g. v1.7.16
+	AvalancheGoVersion string
+	// SubnetEVMVersion pins the release of subnet-evm, e.g. v0.2.9
+	SubnetEVMVersion string
+	// BinDir caches the releases across runs, avalanche-cli/subnettest in
+	// the user cache directory if empty
+	BinDir string
+	// NodeProfile is the node profile of the nodes, config.FastDevNodeProfile
+	// if empty
+	NodeProfile string
+	// Output receives the output of the deploy, discarded if nil
+	Output io.Writer
+	// Timeout bounds the start of the network, 5 minutes if 0
+	Timeout time.Duration
+}
+
+// Network is a running test network
+type Network struct {
+	// BaseDir is the temporary directory of the network, removed by Stop
+	BaseDir string
+	// SubnetName is the name of the deployed subnet
+	SubnetName string
+	// SubnetID is the ID of the deployed subnet
+	SubnetID ids.ID
+	// BlockchainID is the ID of the blockchain of the deployed subnet
+	BlockchainID ids.ID
+	// NodeURIs are the API endpoints of the nodes, by node name order
+	NodeURIs []string
+	// RPCEndpoints are the RPC endpoints of the blockchain on each node, in
+	// the order of NodeURIs
+	RPCEndpoints []string
+
+	app        *application.Avalanche
+	stopServer func()
+}
+
+// DefaultGenesis returns the subnet-evm genesis deployed by default: the
+// default chain config of subnet-evm, with DefaultChainID, and the ewoq
+// address funded
+func DefaultGenesis() ([]byte, error) {
+	config := *params.SubnetEVMDefaultChainConfig
+	config.ChainID = big.NewInt(DefaultChainID)
+	balance, ok := new(big.Int).SetString("1000000000000000000000000", 10)
+	if !ok {
+		return nil, errors.New("invalid default balance")
+	}
+	genesis := core.Genesis{
+		Config:     &config,
+		Difficulty: vm.Difficulty,
+		GasLimit:   vm.GasLimit,
+		Alloc: core.GenesisAlloc{
+			vm.PrefundedEwoqAddress: {Balance: balance},
+		},
+	}
+	return json.MarshalIndent(genesis, "", "    ")
+}
+
+// Start starts a local network in a temporary directory, and deploys a subnet
+// on it. Stop must be called once done, also on failures of the tests, so
+// that the nodes don't outlive them.
+func Start(opts Options) (*Network, error) {
+	if opts.SubnetName == "" {
+		opts.SubnetName = DefaultSubnetName
+	}
+	if opts.Genesis == nil {
+		genesis, err := DefaultGenesis()
+		if err != nil {
+			return nil, err
+		}
+		opts.Genesis = genesis
+	}
+	if opts.NodeProfile == "" {
+		opts.NodeProfile = config.FastDevNodeProfile
+	}
+	if opts.Output == nil {
+		opts.Output = io.Discard
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.BinDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		opts.BinDir = filepath.Join(cacheDir, "avalanche-cli", "subnettest")
+	}
+	var genesis core.Genesis
+	if err := json.Unmarshal(opts.Genesis, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis: %w", err)
+	}
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		return nil, errors.New("invalid genesis: the chain ID is missing")
+	}
+
+	baseDir, err := os.MkdirTemp("", "subnettest-*")
+	if err != nil {
+		return nil, err
+	}
+	n := &Network{BaseDir: baseDir, SubnetName: opts.SubnetName}
+	if err := n.start(opts, genesis); err != nil {
+		_ = n.Stop()
+		return nil, err
+	}
+	return n, nil
+}
+
+// New starts a test network like Start, and stops it at the end of the test.
+// The test fails if the network can't be started.
+func New(t testing.TB, opts Options) *Network {
+	t.Helper()
+	n, err := Start(opts)
+	if err != nil {
+		t.Fatalf("failed starting test network: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := n.Stop(); err != nil {
+			t.Errorf("failed stopping test network: %s", err)
+		}
+	})
+	return n
+}
+
+func (n *Network) start(opts Options, genesis core.Genesis) error {
+	log := logging.NoLog{}
+	ux.NewUserLog(log, opts.Output)
+
+	cf := config.New()
+	cf.SetNodeProfile(opts.NodeProfile)
+	if err := cf.CheckNodeProfile(); err != nil {
+		return err
+	}
+	cf.SetProject(&config.ProjectConfig{
+		Versions: config.ProjectVersions{
+			AvalancheGo: opts.AvalancheGoVersion,
+			SubnetEVM:   opts.SubnetEVMVersion,
+		},
+	})
+	n.app = application.New()
+	n.app.Setup(n.BaseDir, log, cf, prompts.NewHeadlessPrompter())
+	n.app.SetProfile(profile)
+	for _, dir := range []string{n.app.GetRunDir(), n.app.GetSnapshotsDir(), opts.BinDir} {
+		if err := os.MkdirAll(dir, constants.DefaultPerms755); err != nil {
+			return err
+		}
+	}
+	if err := os.Symlink(opts.BinDir, filepath.Join(n.BaseDir, constants.AvalancheCliBinDir)); err != nil {
+		return err
+	}
+
+	ports, err := freeGRPCPorts()
+	if err != nil {
+		return err
+	}
+	if err := binutils.SetGRPCPorts(n.app, ports); err != nil {
+		return err
+	}
+	n.stopServer, err = binutils.RunServerInProcess(n.app)
+	if err != nil {
+		return err
+	}
+
+	if err := n.app.WriteGenesisFile(n.SubnetName, opts.Genesis); err != nil {
+		return err
+	}
+	sc := models.Sidecar{
+		Name:    n.SubnetName,
+		VM:      models.SubnetEvm,
+		Subnet:  n.SubnetName,
+		ChainID: genesis.Config.ChainID.String(),
+	}
+	if err := n.app.CreateSidecar(&sc); err != nil {
+		return err
+	}
+
+	// the releases are downloaded to the shared cache during the deploy, so
+	// the networks of concurrent test processes are deployed one at a time
+	cacheLock, err := acquireCacheLock(opts.BinDir, opts.Timeout)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewLocalSubnetDeployer(n.app)
+	n.SubnetID, n.BlockchainID, err = deployer.DeployToLocalNetwork(sc, n.app.GetGenesisPath(n.SubnetName))
+	_ = cacheLock.Release()
+	if err != nil {
+		return fmt.Errorf("failed deploying subnet %s: %w", n.SubnetName, err)
+	}
+
+	cli, err := binutils.NewGRPCClient(n.app)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed getting the status of the network: %w", err)
+	}
+	nodeNames := []string{}
+	for name := range status.GetClusterInfo().GetNodeInfos() {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		uri := status.GetClusterInfo().GetNodeInfos()[name].GetUri()
+		n.NodeURIs = append(n.NodeURIs, uri)
+		n.RPCEndpoints = append(n.RPCEndpoints, ux.RPCEndpoint(uri, n.BlockchainID.String()))
+	}
+	return nil
+}
+
+// Stop stops the nodes and the backend of the network, and removes its
+// directory
+func (n *Network) Stop() error {
+	if n.stopServer != nil {
+		if cli, err := binutils.NewGRPCClient(n.app); err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+			// fails if the network was not started, which is fine
+			_, _ = cli.Stop(ctx)
+			cancel()
+		}
+		n.stopServer()
+		n.stopServer = nil
+	}
+	if err := os.RemoveAll(n.BaseDir); err != nil {
+		return fmt.Errorf("failed removing %s: %w", n.BaseDir, err)
+	}
+	return nil
+}
+
+// freeGRPCPorts returns ports free at the time of the call for the backend
+func freeGRPCPorts() (binutils.GRPCPorts, error) {
+	ports := make([]int, 2)
+	listeners := make([]net.Listener, 0, len(ports))
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+	for i := range ports {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return binutils.GRPCPorts{}, err
+		}
+		listeners = append(listeners, l)
+		ports[i] = l.Addr().(*net.TCPAddr).Port
+	}
+	return binutils.GRPCPorts{Server: ports[0], Gateway: ports[1]}, nil
+}
+
+// acquireCacheLock waits for the lock of the cache in binDir, held by the
+// networks of other test processes while they download releases
+func acquireCacheLock(binDir string, timeout time.Duration) (*lock.Lock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		l, err := lock.Acquire(filepath.Join(binDir, binCacheLock), "subnettest")
+		if !errors.Is(err, lock.ErrLocked) || time.Now().After(deadline) {
+			return l, err
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnettest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	genesisBytes, err := DefaultGenesis()
+	assert.NoError(err)
+	var genesis core.Genesis
+	assert.NoError(json.Unmarshal(genesisBytes, &genesis))
+	assert.EqualValues(DefaultChainID, genesis.Config.ChainID.Int64())
+	assert.Contains(genesis.Alloc, vm.PrefundedEwoqAddress)
+	assert.False(vm.HasLintErrors(vm.LintEvmGenesis(genesis, models.Local)))
+
+	// the fixture is deterministic
+	again, err := DefaultGenesis()
+	assert.NoError(err)
+	assert.Equal(genesisBytes, again)
+}
+
+func TestFreeGRPCPorts(t *testing.T) {
+	assert := assert.New(t)
+
+	ports, err := freeGRPCPorts()
+	assert.NoError(err)
+	assert.NotZero(ports.Server)
+	assert.NotZero(ports.Gateway)
+	assert.NotEqual(ports.Server, ports.Gateway)
+}
+
+func TestStartInvalidGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Start(Options{Genesis: []byte("{}"), BinDir: t.TempDir()})
+	assert.Error(err)
+}
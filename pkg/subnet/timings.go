@@ -0,0 +1,169 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// DeployPhase identifies a step of a local deployment whose duration is tracked
+type DeployPhase string
+
+const (
+	PhaseEnvSetup      DeployPhase = "env-setup"
+	PhasePluginInstall DeployPhase = "plugin-install"
+	PhaseSnapshotLoad  DeployPhase = "snapshot-load"
+	PhaseBootstrap     DeployPhase = "bootstrap"
+	PhaseChainCreate   DeployPhase = "chain-create"
+	PhaseVMHealth      DeployPhase = "vm-health"
+)
+
+// phaseLabels name the phases in the timing breakdown of a deploy
+var phaseLabels = map[DeployPhase]string{
+	PhaseEnvSetup:      "env setup",
+	PhasePluginInstall: "plugin install",
+	PhaseSnapshotLoad:  "snapshot load",
+	PhaseBootstrap:     "bootstrap",
+	PhaseChainCreate:   "chain create",
+	PhaseVMHealth:      "VM health",
+}
+
+// PhaseDuration is how long a phase of a deploy took
+type PhaseDuration struct {
+	Phase   DeployPhase `json:"phase"`
+	Seconds float64     `json:"seconds"`
+}
+
+// FormatPhaseDurations returns the compact timing breakdown of a deploy,
+// e.g. "env setup 1.2s, plugin install 0.3s, bootstrap 12.1s"
+func FormatPhaseDurations(phases []PhaseDuration) string {
+	parts := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		parts = append(parts, fmt.Sprintf("%s %.1fs", phaseLabel(phase.Phase), phase.Seconds))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func phaseLabel(phase DeployPhase) string {
+	if label, ok := phaseLabels[phase]; ok {
+		return label
+	}
+	return string(phase)
+}
+
+// DeployReport is the outcome of a local deploy, with how long its phases
+// took
+type DeployReport struct {
+	Subnet       string `json:"subnet"`
+	SubnetID     string `json:"subnetID"`
+	BlockchainID string `json:"blockchainID"`
+	// VMBinaryHash is the SHA-256 of the VM plugin binary deployed
+	VMBinaryHash string          `json:"vmBinarySHA256,omitempty"`
+	Phases       []PhaseDuration `json:"phases"`
+}
+
+// WriteDeployReport writes report to path as JSON
+func WriteDeployReport(path string, report DeployReport) error {
+	if report.Phases == nil {
+		report.Phases = []PhaseDuration{}
+	}
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(reportBytes, '\n'), WriteReadReadPerms); err != nil {
+		return fmt.Errorf("failed writing the deploy report: %w", err)
+	}
+	return nil
+}
+
+type phaseStats struct {
+	Samples int           `json:"samples"`
+	Average time.Duration `json:"average"`
+}
+
+// PhaseTimings keeps the average duration of each deploy phase across runs,
+// persisted to a file, so that an ETA can be given on the next run.
+// A nil *PhaseTimings is valid and records nothing.
+type PhaseTimings struct {
+	path   string
+	lock   sync.Mutex
+	Phases map[DeployPhase]*phaseStats `json:"phases"`
+}
+
+// LoadPhaseTimings reads the timings stored at path.
+// A missing or unreadable file just results in empty timings.
+func LoadPhaseTimings(path string) *PhaseTimings {
+	t := &PhaseTimings{
+		path:   path,
+		Phases: map[DeployPhase]*phaseStats{},
+	}
+	timingsBytes, err := os.ReadFile(path)
+	if err != nil {
+		return t
+	}
+	if err := json.Unmarshal(timingsBytes, t); err != nil || t.Phases == nil {
+		t.Phases = map[DeployPhase]*phaseStats{}
+	}
+	return t
+}
+
+// Estimate returns the average duration of the phase in previous runs,
+// and false if there is no history for it
+func (t *PhaseTimings) Estimate(phase DeployPhase) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	stats, ok := t.Phases[phase]
+	if !ok || stats.Samples == 0 {
+		return 0, false
+	}
+	return stats.Average, true
+}
+
+// Record adds a new duration for the phase to the average.
+// The average weights at most the last constants.MaxPhaseTimingSamples runs
+// so that it follows changes in the user's environment.
+func (t *PhaseTimings) Record(phase DeployPhase, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	stats, ok := t.Phases[phase]
+	if !ok {
+		stats = &phaseStats{}
+		t.Phases[phase] = stats
+	}
+	if stats.Samples < constants.MaxPhaseTimingSamples {
+		stats.Samples++
+	}
+	stats.Average += (d - stats.Average) / time.Duration(stats.Samples)
+}
+
+// Save persists the timings to the file they were loaded from
+func (t *PhaseTimings) Save() error {
+	if t == nil {
+		return nil
+	}
+	if t.path == "" {
+		return errors.New("no path for phase timings set")
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	timingsBytes, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, timingsBytes, WriteReadReadPerms)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseTimings(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), constants.PhaseTimingsFile)
+	timings := LoadPhaseTimings(path)
+	_, ok := timings.Estimate(PhaseBootstrap)
+	assert.False(ok)
+
+	timings.Record(PhaseBootstrap, 10*time.Second)
+	timings.Record(PhaseBootstrap, 20*time.Second)
+	eta, ok := timings.Estimate(PhaseBootstrap)
+	assert.True(ok)
+	assert.Equal(15*time.Second, eta)
+	assert.NoError(timings.Save())
+
+	loaded := LoadPhaseTimings(path)
+	eta, ok = loaded.Estimate(PhaseBootstrap)
+	assert.True(ok)
+	assert.Equal(15*time.Second, eta)
+	_, ok = loaded.Estimate(PhaseVMHealth)
+	assert.False(ok)
+
+	// the average only follows the last samples
+	for i := 0; i < 100; i++ {
+		loaded.Record(PhaseBootstrap, time.Second)
+	}
+	eta, _ = loaded.Estimate(PhaseBootstrap)
+	assert.Less(eta, 2*time.Second)
+
+	var nilTimings *PhaseTimings
+	nilTimings.Record(PhaseEnvSetup, time.Second)
+	_, ok = nilTimings.Estimate(PhaseEnvSetup)
+	assert.False(ok)
+	assert.NoError(nilTimings.Save())
+}
+
+func TestFormatPhaseDurations(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(FormatPhaseDurations(nil))
+	assert.Equal("env setup 1.2s, plugin install 0.3s, bootstrap 12.1s, VM health 4.0s", FormatPhaseDurations([]PhaseDuration{
+		{Phase: PhaseEnvSetup, Seconds: 1.23},
+		{Phase: PhasePluginInstall, Seconds: 0.31},
+		{Phase: PhaseBootstrap, Seconds: 12.08},
+		{Phase: PhaseVMHealth, Seconds: 4},
+	}))
+}
+
+func TestWriteDeployReport(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(WriteDeployReport(path, DeployReport{
+		Subnet: "test",
+		Phases: []PhaseDuration{{Phase: PhaseEnvSetup, Seconds: 1.5}},
+	}))
+	reportBytes, err := os.ReadFile(path)
+	assert.NoError(err)
+	var report map[string]interface{}
+	assert.NoError(json.Unmarshal(reportBytes, &report))
+	assert.Equal("test", report["subnet"])
+	assert.Equal([]interface{}{map[string]interface{}{"phase": "env-setup", "seconds": 1.5}}, report["phases"])
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/shirou/gopsutil/process"
+)
+
+// DeployStatus is the status of a deploy running in the background
+type DeployStatus string
+
+const (
+	// DeployPending is a deploy whose process has not started deploying yet
+	DeployPending   DeployStatus = "pending"
+	DeployRunning   DeployStatus = "running"
+	DeploySucceeded DeployStatus = "succeeded"
+	DeployFailed    DeployStatus = "failed"
+)
+
+var trackingIDRegex = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+// DeployTracking is the record of a local deploy running in the background,
+// started with subnet deploy --async, and updated by the process deploying
+type DeployTracking struct {
+	ID           string       `json:"id"`
+	Subnet       string       `json:"subnet"`
+	Status       DeployStatus `json:"status"`
+	Pid          int          `json:"pid,omitempty"`
+	LogFile      string       `json:"logFile"`
+	StartedAt    time.Time    `json:"startedAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+	SubnetID     string       `json:"subnetID,omitempty"`
+	BlockchainID string       `json:"blockchainID,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	ExitCode     int          `json:"exitCode,omitempty"`
+}
+
+// NewDeployTracking records a pending deploy of subnetName, under a new
+// tracking ID
+func NewDeployTracking(app *application.Avalanche, subnetName string) (*DeployTracking, error) {
+	idBytes := make([]byte, 6)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(idBytes)
+	if err := os.MkdirAll(app.GetDeploysDir(), constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	t := &DeployTracking{
+		ID:        id,
+		Subnet:    subnetName,
+		Status:    DeployPending,
+		LogFile:   filepath.Join(app.GetDeploysDir(), id+".log"),
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+	return t, t.Save(app)
+}
+
+// LoadDeployTracking loads the record of the deploy of tracking ID id
+func LoadDeployTracking(app *application.Avalanche, id string) (*DeployTracking, error) {
+	if !trackingIDRegex.MatchString(id) {
+		return nil, exitcodes.UserInput(fmt.Errorf("invalid tracking ID %q", id))
+	}
+	trackingBytes, err := os.ReadFile(trackingPath(app, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, exitcodes.UserInput(fmt.Errorf("no deploy of tracking ID %s in profile %s", id, app.GetProfile()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t DeployTracking
+	if err := json.Unmarshal(trackingBytes, &t); err != nil {
+		return nil, fmt.Errorf("failed reading the deploy of tracking ID %s: %w", id, err)
+	}
+	return &t, nil
+}
+
+// Save writes the record
+func (t *DeployTracking) Save(app *application.Avalanche) error {
+	t.UpdatedAt = time.Now()
+	trackingBytes, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(trackingPath(app, t.ID), trackingBytes, application.WriteReadReadPerms)
+}
+
+// Finish records the outcome of the deploy, failed if err is set
+func (t *DeployTracking) Finish(app *application.Avalanche, subnetID, blockchainID string, err error) error {
+	t.Status = DeploySucceeded
+	t.SubnetID, t.BlockchainID = subnetID, blockchainID
+	if err != nil {
+		t.Status = DeployFailed
+		t.Error = err.Error()
+		t.ExitCode = int(exitcodes.FromError(err))
+	}
+	return t.Save(app)
+}
+
+// CheckAlive marks the deploy as failed if it is not done while the process
+// deploying it is gone, e.g. killed or unable to take the state lock
+func (t *DeployTracking) CheckAlive() {
+	if t.Status != DeployPending && t.Status != DeployRunning || t.Pid == 0 {
+		return
+	}
+	if alive, err := process.PidExists(int32(t.Pid)); err != nil || alive {
+		return
+	}
+	t.Status = DeployFailed
+	t.Error = fmt.Sprintf("the deploy process (pid %d) exited before completing, see %s", t.Pid, t.LogFile)
+	t.ExitCode = int(exitcodes.GenericError)
+}
+
+// StartTrackedDeploy runs this binary with args, the ones of a deploy
+// reporting to t, in the background, with its output in the log file of t
+func StartTrackedDeploy(app *application.Avalanche, t *DeployTracking, args []string) error {
+	logFile, err := os.Create(t.LogFile)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(reexec.Self(), args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed starting the deploy in the background: %w", err)
+	}
+	t.Pid = cmd.Process.Pid
+	// the deploy updates the record once this command releases the state
+	// lock, so this write can't race with its own
+	return t.Save(app)
+}
+
+func trackingPath(app *application.Avalanche, id string) string {
+	return filepath.Join(app.GetDeploysDir(), id+".json")
+}
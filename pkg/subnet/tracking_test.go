@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestDeployTracking(t *testing.T) {
+	assert := setupTest(t)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+
+	tracking, err := NewDeployTracking(app, "mySubnet")
+	assert.NoError(err)
+	assert.Equal(DeployPending, tracking.Status)
+	assert.Regexp(trackingIDRegex, tracking.ID)
+
+	tracking.Status = DeployRunning
+	tracking.Pid = os.Getpid()
+	assert.NoError(tracking.Save(app))
+	loaded, err := LoadDeployTracking(app, tracking.ID)
+	assert.NoError(err)
+	loaded.CheckAlive()
+	assert.Equal(DeployRunning, loaded.Status)
+
+	assert.NoError(loaded.Finish(app, "", "", exitcodes.Unhealthy(errors.New("not healthy"))))
+	loaded, err = LoadDeployTracking(app, tracking.ID)
+	assert.NoError(err)
+	assert.Equal(DeployFailed, loaded.Status)
+	assert.Equal("not healthy", loaded.Error)
+	assert.Equal(int(exitcodes.NetworkUnhealthy), loaded.ExitCode)
+
+	// the process deploying is gone before completing
+	gone := exec.Command("true")
+	assert.NoError(gone.Run())
+	tracking.Pid = gone.Process.Pid
+	tracking.CheckAlive()
+	assert.Equal(DeployFailed, tracking.Status)
+
+	_, err = LoadDeployTracking(app, "../../etc/passwd")
+	assert.Equal(exitcodes.UserInputError, exitcodes.FromError(err))
+	_, err = LoadDeployTracking(app, "0123456789ab")
+	assert.Equal(exitcodes.UserInputError, exitcodes.FromError(err))
+}
@@ -0,0 +1,265 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ava-labs/coreth/plugin/evm"
+	"github.com/ava-labs/coreth/rpc"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	cChainAlias = "C"
+	// 1 nAVAX on the X and P chains is 1 gwei on the C-Chain
+	x2cRate = 1_000_000_000
+
+	atomicTxPollInterval = time.Second
+)
+
+// TransferCToP moves amount nAVAX owned by the key from the C-Chain to the
+// P-Chain, by exporting them from the C-Chain and importing them on the P-Chain.
+// The C-Chain export fee is paid on top of amount, the P-Chain import fee is
+// deducted from it.
+// Returns the IDs of the export and of the import transactions.
+func (d *PublicDeployer) TransferCToP(amount uint64) (ids.ID, ids.ID, error) {
+	if err := d.app.CheckWritable("issue transactions"); err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	api, networkID, err := d.endpoint()
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	if d.usesRemoteKey() {
+		// the C-Chain export is an atomic EVM transaction, not signed by the
+		// P-Chain signer
+		return ids.Empty, ids.Empty, exitcodes.UserInput(errors.New("transfers from the C-Chain are not supported with remote keys"))
+	}
+	sk, err := d.loadSoftKey(networkID)
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	kc := sk.KeyChain()
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+
+	cChainID, err := info.NewClient(api).GetBlockchainID(ctx, cChainAlias)
+	if err != nil {
+		return ids.Empty, ids.Empty, fmt.Errorf("failed getting the C-Chain ID: %w", err)
+	}
+	pCTX, xCTX, utxos, err := primary.FetchState(ctx, api, kc.Addrs)
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+
+	exportTxID, err := d.exportFromC(ctx, api, networkID, sk, amount, cChainID, pCTX.AVAXAssetID())
+	if err != nil {
+		return ids.Empty, ids.Empty, err
+	}
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgExportAccepted), exportTxID)
+
+	// the exported funds are atomic UTXOs which FetchState does not look for
+	err = primary.AddAllUTXOs(
+		ctx,
+		utxos,
+		platformvm.NewClient(api),
+		platformvm.Codec,
+		cChainID,
+		avago_constants.PlatformChainID,
+		kc.Addrs.List(),
+	)
+	if err != nil {
+		return exportTxID, ids.Empty, fmt.Errorf("failed fetching the exported funds: %w", err)
+	}
+	wallet := primary.NewWalletWithState(api, pCTX, xCTX, utxos, kc)
+	owner := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     sk.Addresses(),
+	}
+	importTxID, err := wallet.P().IssueImportTx(cChainID, owner)
+	if err != nil {
+		return exportTxID, ids.Empty, fmt.Errorf("failed importing the funds on the P-Chain: %w", err)
+	}
+	return exportTxID, importTxID, nil
+}
+
+// exportFromC issues a C-Chain export tx of amount nAVAX to the P-Chain
+// addresses of the key, and waits for it to be accepted
+func (d *PublicDeployer) exportFromC(
+	ctx context.Context,
+	api string,
+	networkID uint32,
+	sk *key.SoftKey,
+	amount uint64,
+	cChainID ids.ID,
+	avaxAssetID ids.ID,
+) (ids.ID, error) {
+	// through http.DefaultClient, whose requests to the public API
+	// endpoints are retried and rate limited
+	rpcClient, err := rpc.DialHTTPWithClient(api+"/ext/bc/C/rpc", http.DefaultClient)
+	if err != nil {
+		return ids.Empty, err
+	}
+	ethClient := ethclient.NewClient(rpcClient)
+	defer ethClient.Close()
+
+	ethAddr := common.HexToAddress(sk.C())
+	nonce, err := ethClient.NonceAt(ctx, ethAddr, nil)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed getting the C-Chain nonce: %w", err)
+	}
+	baseFee, err := ethClient.EstimateBaseFee(ctx)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed estimating the C-Chain base fee: %w", err)
+	}
+	balance, err := ethClient.BalanceAt(ctx, ethAddr, nil)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed getting the C-Chain balance: %w", err)
+	}
+
+	utx := &evm.UnsignedExportTx{
+		NetworkID:        networkID,
+		BlockchainID:     cChainID,
+		DestinationChain: avago_constants.PlatformChainID,
+		Ins: []evm.EVMInput{{
+			Address: ethAddr,
+			Amount:  amount,
+			AssetID: avaxAssetID,
+			Nonce:   nonce,
+		}},
+		ExportedOutputs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: avaxAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     sk.Addresses(),
+				},
+			},
+		}},
+	}
+	tx := &evm.Tx{UnsignedAtomicTx: utx}
+	signers := [][]*crypto.PrivateKeySECP256K1R{{sk.Key()}}
+	// the fee depends on the size of the signed tx
+	if err := tx.Sign(evm.Codec, signers); err != nil {
+		return ids.Empty, err
+	}
+	gasUsed, err := tx.GasUsed(true)
+	if err != nil {
+		return ids.Empty, err
+	}
+	fee := atomicTxFee(gasUsed, baseFee)
+	if amount+fee < amount {
+		return ids.Empty, exitcodes.UserInput(fmt.Errorf("amount %d is too large", amount))
+	}
+	utx.Ins[0].Amount = amount + fee
+
+	required := new(big.Int).Mul(new(big.Int).SetUint64(amount+fee), big.NewInt(x2cRate))
+	if balance.Cmp(required) < 0 {
+		return ids.Empty, exitcodes.Funds(fmt.Errorf(
+			"insufficient C-Chain funds: %s has %s nAVAX, %d nAVAX are needed including a fee of %d nAVAX",
+			ethAddr.Hex(), new(big.Int).Div(balance, big.NewInt(x2cRate)), amount+fee, fee))
+	}
+	if err := tx.Sign(evm.Codec, signers); err != nil {
+		return ids.Empty, err
+	}
+
+	cClient := evm.NewCChainClient(api)
+	txID, err := cClient.IssueTx(ctx, tx.SignedBytes())
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failed issuing the C-Chain export: %w", err)
+	}
+	for {
+		status, err := cClient.GetAtomicTxStatus(ctx, txID)
+		if err != nil {
+			return txID, fmt.Errorf("failed getting the status of the C-Chain export %s: %w", txID, err)
+		}
+		switch status {
+		case evm.Accepted:
+			return txID, nil
+		case evm.Dropped:
+			return txID, fmt.Errorf("C-Chain export %s was dropped", txID)
+		}
+		select {
+		case <-ctx.Done():
+			return txID, fmt.Errorf("timed out waiting for the C-Chain export %s to be accepted", txID)
+		case <-time.After(atomicTxPollInterval):
+		}
+	}
+}
+
+// atomicTxFee returns the fee in nAVAX of an atomic tx using gasUsed gas,
+// given the C-Chain base fee in wei
+func atomicTxFee(gasUsed uint64, baseFee *big.Int) uint64 {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), baseFee)
+	divisor := big.NewInt(x2cRate)
+	roundUp := new(big.Int).Sub(divisor, big.NewInt(1))
+	fee.Add(fee, roundUp)
+	return fee.Div(fee, divisor).Uint64()
+}
+
+// ParseAVAX converts an amount of AVAX given in decimal notation, with up to
+// 9 decimals, to nAVAX
+func ParseAVAX(amount string) (uint64, error) {
+	invalid := fmt.Errorf("invalid AVAX amount %q", amount)
+	whole, fraction := amount, ""
+	if i := strings.Index(amount, "."); i >= 0 {
+		whole, fraction = amount[:i], amount[i+1:]
+	}
+	if whole == "" && fraction == "" {
+		return 0, invalid
+	}
+	if len(fraction) > 9 {
+		return 0, fmt.Errorf("AVAX amount %q has more than 9 decimals", amount)
+	}
+	var wholeAVAX, fractionNAVAX uint64
+	var err error
+	if whole != "" {
+		if wholeAVAX, err = strconv.ParseUint(whole, 10, 64); err != nil {
+			return 0, invalid
+		}
+	}
+	if fraction != "" {
+		if fractionNAVAX, err = strconv.ParseUint(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64); err != nil {
+			return 0, invalid
+		}
+	}
+	nAVAX := wholeAVAX * units.Avax
+	if nAVAX/units.Avax != wholeAVAX || nAVAX+fractionNAVAX < nAVAX {
+		return 0, fmt.Errorf("AVAX amount %q is too large", amount)
+	}
+	return nAVAX + fractionNAVAX, nil
+}
+
+// FormatAVAX converts an amount of nAVAX to AVAX in decimal notation,
+// without trailing zeros
+func FormatAVAX(nAVAX uint64) string {
+	whole, fraction := nAVAX/units.Avax, nAVAX%units.Avax
+	if fraction == 0 {
+		return strconv.FormatUint(whole, 10)
+	}
+	return fmt.Sprintf("%d.%s", whole, strings.TrimRight(fmt.Sprintf("%09d", fraction), "0"))
+}
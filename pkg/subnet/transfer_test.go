@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAVAX(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		amount     string
+		expected   uint64
+		shouldFail bool
+	}{
+		{amount: "1", expected: 1_000_000_000},
+		{amount: "0.5", expected: 500_000_000},
+		{amount: ".25", expected: 250_000_000},
+		{amount: "2.", expected: 2_000_000_000},
+		{amount: "0.000000001", expected: 1},
+		{amount: "12.345678901", expected: 12_345_678_901},
+		{amount: "0.0000000001", shouldFail: true},
+		{amount: "", shouldFail: true},
+		{amount: ".", shouldFail: true},
+		{amount: "-1", shouldFail: true},
+		{amount: "1,5", shouldFail: true},
+		{amount: "1.-5", shouldFail: true},
+		{amount: "99999999999999999999", shouldFail: true},
+	}
+	for _, tt := range tests {
+		nAVAX, err := ParseAVAX(tt.amount)
+		if tt.shouldFail {
+			assert.Error(err, tt.amount)
+			continue
+		}
+		assert.NoError(err, tt.amount)
+		assert.Equal(tt.expected, nAVAX, tt.amount)
+	}
+}
+
+func TestFormatAVAX(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0", FormatAVAX(0))
+	assert.Equal("1", FormatAVAX(1_000_000_000))
+	assert.Equal("0.5", FormatAVAX(500_000_000))
+	assert.Equal("12.345678901", FormatAVAX(12_345_678_901))
+	assert.Equal("0.000000001", FormatAVAX(1))
+}
+
+func TestAtomicTxFee(t *testing.T) {
+	assert := assert.New(t)
+
+	// 25 gwei base fee
+	baseFee := big.NewInt(25_000_000_000)
+	assert.Equal(uint64(0), atomicTxFee(0, baseFee))
+	assert.Equal(uint64(250_000), atomicTxFee(10_000, baseFee))
+	// fractions of nAVAX are rounded up
+	assert.Equal(uint64(1), atomicTxFee(1, big.NewInt(1)))
+	assert.Equal(uint64(2), atomicTxFee(1, big.NewInt(1_000_000_001)))
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+const (
+	txPollInterval = time.Second
+	// how long a transaction may stay processing before giving up on it
+	txConfirmationTimeout = 2 * time.Minute
+)
+
+var (
+	ErrTxNotCommitted = errors.New("transaction not committed")
+	ErrTxNotConfirmed = errors.New("transaction not confirmed")
+)
+
+// waitForTx polls the P-Chain until it decides txID, reporting when it is
+// processing. Returns an error if the transaction is aborted or dropped, or
+// if it is still not decided after timeout.
+func waitForTx(
+	ctx context.Context,
+	api string,
+	pClient platformvm.Client,
+	txID ids.ID,
+	timeout time.Duration,
+	interval time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := status.Unknown
+	for {
+		resp, err := pClient.GetTxStatus(ctx, txID)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			return fmt.Errorf("failed getting the status of transaction %s: %w", txID, err)
+		case err == nil:
+			if resp.Status != last && resp.Status == status.Processing {
+				ux.Logger.PrintToUser("Transaction %s is processing, waiting for it to be committed...", txID)
+			}
+			last = resp.Status
+			switch resp.Status {
+			case status.Committed:
+				return nil
+			case status.Aborted, status.Dropped:
+				msg := fmt.Sprintf("transaction %s was %s", txID, strings.ToLower(resp.Status.String()))
+				if resp.Reason != "" {
+					msg += ": " + resp.Reason
+				}
+				return fmt.Errorf("%w: %s", ErrTxNotCommitted, msg)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"%w: transaction %s is still %s after %s. It may still be committed, check its status with platform.getTxStatus on %s before issuing it again",
+				ErrTxNotConfirmed, txID, strings.ToLower(last.String()), timeout, api,
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForTx waits until the P-Chain of the network of the deployer commits
+// txID
+func (d *PublicDeployer) waitForTx(txID ids.ID) error {
+	api, _, err := d.endpoint()
+	if err != nil {
+		return err
+	}
+	return waitForTx(context.Background(), api, platformvm.NewClient(api), txID, txConfirmationTimeout, txPollInterval)
+}
+
+// reportSubnet prints the control keys and threshold of the committed
+// subnet
+func (d *PublicDeployer) reportSubnet(subnetID ids.ID) {
+	api, networkID, err := d.endpoint()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), txConfirmationTimeout)
+	defer cancel()
+	subnets, err := platformvm.NewClient(api).GetSubnets(ctx, []ids.ID{subnetID})
+	if err != nil || len(subnets) == 0 {
+		d.app.Log.Warn("failed getting subnet %s: %s", subnetID, err)
+		return
+	}
+	controlKeys := make([]string, len(subnets[0].ControlKeys))
+	for i, addr := range subnets[0].ControlKeys {
+		controlKeys[i], err = address.Format("P", avago_constants.GetHRP(networkID), addr[:])
+		if err != nil {
+			controlKeys[i] = addr.String()
+		}
+	}
+	ux.Logger.PrintToUser("Subnet %s committed, with control keys %s and a threshold of %d", subnetID, strings.Join(controlKeys, ", "), subnets[0].Threshold)
+}
+
+// reportBlockchain prints the status of the committed blockchain
+func (d *PublicDeployer) reportBlockchain(blockchainID ids.ID, subnetID ids.ID) {
+	api, _, err := d.endpoint()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), txConfirmationTimeout)
+	defer cancel()
+	chainStatus, err := platformvm.NewClient(api).GetBlockchainStatus(ctx, blockchainID.String())
+	if err != nil {
+		d.app.Log.Warn("failed getting the status of blockchain %s: %s", blockchainID, err)
+		return
+	}
+	ux.Logger.PrintToUser("Blockchain %s committed in subnet %s, its status is %s", blockchainID, subnetID, chainStatus)
+}
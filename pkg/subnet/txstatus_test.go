@@ -0,0 +1,66 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTxStatusClient returns the given statuses in turn, the last one
+// forever
+type fakeTxStatusClient struct {
+	platformvm.Client
+	statuses []platformvm.GetTxStatusResponse
+	calls    int
+}
+
+func (c *fakeTxStatusClient) GetTxStatus(context.Context, ids.ID, ...rpc.Option) (*platformvm.GetTxStatusResponse, error) {
+	resp := c.statuses[len(c.statuses)-1]
+	if c.calls < len(c.statuses) {
+		resp = c.statuses[c.calls]
+	}
+	c.calls++
+	return &resp, nil
+}
+
+func TestWaitForTx(t *testing.T) {
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	txID := ids.GenerateTestID()
+	wait := func(timeout time.Duration, statuses ...platformvm.GetTxStatusResponse) (*fakeTxStatusClient, error) {
+		client := &fakeTxStatusClient{statuses: statuses}
+		return client, waitForTx(context.Background(), "http://api", client, txID, timeout, time.Millisecond)
+	}
+
+	client, err := wait(time.Minute,
+		platformvm.GetTxStatusResponse{Status: status.Processing},
+		platformvm.GetTxStatusResponse{Status: status.Processing},
+		platformvm.GetTxStatusResponse{Status: status.Committed},
+	)
+	assert.NoError(err)
+	assert.Equal(3, client.calls)
+
+	_, err = wait(time.Minute, platformvm.GetTxStatusResponse{Status: status.Dropped, Reason: "failed to read consumed UTXO"})
+	assert.ErrorIs(err, ErrTxNotCommitted)
+	// dropped transactions are retried on UTXO conflicts
+	assert.True(isUTXOConflict(err))
+
+	_, err = wait(time.Minute, platformvm.GetTxStatusResponse{Status: status.Aborted})
+	assert.ErrorIs(err, ErrTxNotCommitted)
+
+	_, err = wait(20*time.Millisecond, platformvm.GetTxStatusResponse{Status: status.Processing})
+	assert.ErrorIs(err, ErrTxNotConfirmed)
+	assert.ErrorContains(err, "still processing")
+}
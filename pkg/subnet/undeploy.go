@@ -0,0 +1,225 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNotDeployedLocally is returned when undeploying a subnet which is not
+// deployed to the local network
+var ErrNotDeployedLocally = errors.New("not deployed to the local network")
+
+// LoadUndeployed returns the blockchains removed from the local network with
+// UndeployFromLocalNetwork, by blockchain ID, along with their subnet ID.
+// They stay registered on the P-Chain of the network, so the network runner
+// keeps reporting them until the network is cleaned.
+func LoadUndeployed(app *application.Avalanche) (map[string]string, error) {
+	undeployed := map[string]string{}
+	undeployedBytes, err := os.ReadFile(app.GetUndeployedChainsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return undeployed, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(undeployedBytes, &undeployed); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling undeployed blockchains at %s: %w", app.GetUndeployedChainsPath(), err)
+	}
+	return undeployed, nil
+}
+
+func saveUndeployed(app *application.Avalanche, undeployed map[string]string) error {
+	undeployedBytes, err := json.MarshalIndent(undeployed, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(app.GetUndeployedChainsPath()), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetUndeployedChainsPath(), undeployedBytes, application.WriteReadReadPerms)
+}
+
+// ClearUndeployed forgets the undeployed blockchains, once the local network
+// they were registered on is gone
+func ClearUndeployed(app *application.Avalanche) error {
+	if err := os.Remove(app.GetUndeployedChainsPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// FilterUndeployed returns a copy of clusterInfo without the undeployed
+// blockchains and their subnets
+func FilterUndeployed(app *application.Avalanche, clusterInfo *rpcpb.ClusterInfo) (*rpcpb.ClusterInfo, error) {
+	if clusterInfo == nil {
+		return nil, nil
+	}
+	undeployed, err := LoadUndeployed(app)
+	if err != nil {
+		return nil, err
+	}
+	return filterUndeployed(clusterInfo, undeployed), nil
+}
+
+func filterUndeployed(clusterInfo *rpcpb.ClusterInfo, undeployed map[string]string) *rpcpb.ClusterInfo {
+	filtered, _ := proto.Clone(clusterInfo).(*rpcpb.ClusterInfo)
+	undeployedSubnets := map[string]bool{}
+	for blockchainID, subnetID := range undeployed {
+		delete(filtered.CustomVms, blockchainID)
+		undeployedSubnets[subnetID] = true
+	}
+	subnets := []string{}
+	for _, subnetID := range filtered.Subnets {
+		if !undeployedSubnets[subnetID] {
+			subnets = append(subnets, subnetID)
+		}
+	}
+	filtered.Subnets = subnets
+	return filtered
+}
+
+// UndeployFromLocalNetwork removes the blockchain of sc from the running
+// local network, keeping the other blockchains running: the nodes are
+// restarted without tracking its subnet, and its VM binary is removed unless
+// another blockchain uses it. As blockchains can't be removed from the
+// P-Chain, the blockchain is recorded as undeployed until the network is
+// cleaned. Returns the ID of the removed blockchain.
+func (d *LocalSubnetDeployer) UndeployFromLocalNetwork(sc models.Sidecar) (ids.ID, error) {
+	cli, err := d.getClientFunc()
+	if err != nil {
+		return ids.Empty, fmt.Errorf("error creating gRPC Client: %w", err)
+	}
+	defer cli.Close()
+
+	ctx := binutils.GetAsyncContext()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		// TODO: use error type not string comparison
+		if strings.Contains(err.Error(), "not bootstrapped") {
+			return ids.Empty, exitcodes.UserInput(errors.New("the local network is not running"))
+		}
+		return ids.Empty, err
+	}
+	undeployed, err := LoadUndeployed(d.app)
+	if err != nil {
+		return ids.Empty, err
+	}
+	clusterInfo := filterUndeployed(status.GetClusterInfo(), undeployed)
+
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return ids.Empty, err
+	}
+	var target *rpcpb.CustomVmInfo
+	for _, vmInfo := range clusterInfo.GetCustomVms() {
+		if vmInfo.GetVmId() == vmID.String() {
+			target = vmInfo
+		}
+	}
+	if target == nil {
+		return ids.Empty, exitcodes.UserInput(fmt.Errorf("%s is %w", sc.Name, ErrNotDeployedLocally))
+	}
+	blockchainID, err := ids.FromString(target.GetBlockchainId())
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	// the other blockchains of the subnet would stop as well
+	sharing := []string{}
+	vmShared := false
+	for _, vmInfo := range clusterInfo.GetCustomVms() {
+		if vmInfo.GetBlockchainId() == target.GetBlockchainId() {
+			continue
+		}
+		if vmInfo.GetSubnetId() == target.GetSubnetId() {
+			sharing = append(sharing, vmInfo.GetVmName())
+		}
+		vmShared = vmShared || vmInfo.GetVmId() == target.GetVmId()
+	}
+	if len(sharing) > 0 {
+		sort.Strings(sharing)
+		return ids.Empty, exitcodes.UserInput(fmt.Errorf(
+			"the subnet of %s also runs the blockchains of %s, which would stop too. Use network clean to remove them all",
+			sc.Name, strings.Join(sharing, ", ")))
+	}
+
+	nodeNames := make([]string, 0, len(clusterInfo.GetNodeInfos()))
+	for name := range clusterInfo.GetNodeInfos() {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		whitelist, err := remainingWhitelist(clusterInfo.NodeInfos[name].GetWhitelistedSubnets(), target.GetSubnetId(), clusterInfo.GetSubnets())
+		if err != nil {
+			return ids.Empty, err
+		}
+		ux.Logger.PrintToUser("Restarting %s without tracking the subnet of %s...", name, sc.Name)
+		if _, err := cli.RestartNode(ctx, name, client.WithWhitelistedSubnets(whitelist)); err != nil {
+			return ids.Empty, fmt.Errorf("failed restarting %s: %w", name, err)
+		}
+	}
+
+	undeployed[target.GetBlockchainId()] = target.GetSubnetId()
+	if err := saveUndeployed(d.app, undeployed); err != nil {
+		return ids.Empty, err
+	}
+
+	if !vmShared {
+		pluginDirs := map[string]bool{}
+		for _, nodeInfo := range clusterInfo.GetNodeInfos() {
+			pluginDirs[nodeInfo.GetPluginDir()] = true
+		}
+		for pluginDir := range pluginDirs {
+			if pluginDir == "" {
+				continue
+			}
+			if err := os.Remove(filepath.Join(pluginDir, vmID.String())); err != nil && !os.IsNotExist(err) {
+				d.app.Log.Warn("failed removing the VM binary of %s: %s", sc.Name, err)
+			}
+		}
+	}
+	return blockchainID, nil
+}
+
+// remainingWhitelist returns the subnets a node tracking whitelist, a comma
+// separated list, must keep tracking once subnetID is undeployed. As the
+// network runner keeps the whitelist of a node when given an empty one, one
+// of the other subnets of the network is tracked instead of none.
+func remainingWhitelist(whitelist string, subnetID string, networkSubnets []string) (string, error) {
+	remaining := []string{}
+	for _, s := range strings.Split(whitelist, ",") {
+		if s = strings.TrimSpace(s); s != "" && s != subnetID {
+			remaining = append(remaining, s)
+		}
+	}
+	if len(remaining) == 0 {
+		for _, s := range networkSubnets {
+			if s != subnetID {
+				remaining = append(remaining, s)
+				break
+			}
+		}
+	}
+	if len(remaining) == 0 {
+		return "", fmt.Errorf("the network has no other subnet than %s to track", subnetID)
+	}
+	return strings.Join(remaining, ","), nil
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterUndeployed(t *testing.T) {
+	assert := assert.New(t)
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		CustomVms: map[string]*rpcpb.CustomVmInfo{
+			"chain1": {VmName: "mySubnet", BlockchainId: "chain1", SubnetId: "subnet1"},
+			"chain2": {VmName: "other", BlockchainId: "chain2", SubnetId: "subnet2"},
+		},
+		Subnets: []string{"subnet1", "subnet2"},
+	}
+	filtered := filterUndeployed(clusterInfo, map[string]string{"chain1": "subnet1"})
+	assert.Len(filtered.CustomVms, 1)
+	assert.Contains(filtered.CustomVms, "chain2")
+	assert.Equal([]string{"subnet2"}, filtered.Subnets)
+	// the original cluster info is left untouched
+	assert.Len(clusterInfo.CustomVms, 2)
+	assert.Equal([]string{"subnet1", "subnet2"}, clusterInfo.Subnets)
+
+	filtered = filterUndeployed(clusterInfo, map[string]string{})
+	assert.Len(filtered.CustomVms, 2)
+	assert.Equal([]string{"subnet1", "subnet2"}, filtered.Subnets)
+}
+
+func TestRemainingWhitelist(t *testing.T) {
+	assert := assert.New(t)
+	networkSubnets := []string{"subnet1", "subnet2", "subnet3"}
+
+	whitelist, err := remainingWhitelist("subnet1,subnet2", "subnet1", networkSubnets)
+	assert.NoError(err)
+	assert.Equal("subnet2", whitelist)
+
+	whitelist, err = remainingWhitelist("subnet3, subnet1, subnet2", "subnet1", networkSubnets)
+	assert.NoError(err)
+	assert.Equal("subnet3,subnet2", whitelist)
+
+	// an empty whitelist would keep tracking the subnet, another one is tracked instead
+	whitelist, err = remainingWhitelist("subnet1", "subnet1", networkSubnets)
+	assert.NoError(err)
+	assert.Equal("subnet2", whitelist)
+
+	_, err = remainingWhitelist("subnet1", "subnet1", []string{"subnet1"})
+	assert.ErrorContains(err, "no other subnet than subnet1")
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/coreos/go-semver/semver"
+)
+
+// VersionSource tells where the version and uptime of a validator come from
+type VersionSource string
+
+const (
+	// FromInfoAPI is the info API of the validator itself
+	FromInfoAPI VersionSource = "info API"
+	// FromPeers is the view of the node of the API endpoint, which the
+	// validator is connected to
+	FromPeers VersionSource = "peers"
+	// FromNowhere means the validator is not connected to the node of the
+	// API endpoint, and doesn't expose its info API
+	FromNowhere VersionSource = ""
+)
+
+// NodeInfoClient is the part of the info API needed to check the health of
+// validators, which info.Client implements
+type NodeInfoClient interface {
+	GetNodeVersion(ctx context.Context, options ...rpc.Option) (*info.GetNodeVersionReply, error)
+	Peers(ctx context.Context, options ...rpc.Option) ([]info.Peer, error)
+	Uptime(ctx context.Context, options ...rpc.Option) (*info.UptimeResponse, error)
+}
+
+// ValidatorHealth is the version and uptime of a validator of a subnet
+type ValidatorHealth struct {
+	NodeID ids.NodeID
+	Weight uint64
+	End    time.Time
+	// Connected is true if the node of the API endpoint is connected to the
+	// validator
+	Connected bool
+	// Version is the avalanchego version of the validator, empty if unknown
+	Version string
+	// Uptime is the percentage of the time the validator was up, weighted
+	// by stake if from its info API, or as observed by the node of the API
+	// endpoint, nil if unknown
+	Uptime *float64
+	Source VersionSource
+	// Outdated is true if the validator runs an older version than the
+	// minimum version
+	Outdated bool
+}
+
+// ValidatorsHealth is the health of the validator set of a subnet
+type ValidatorsHealth struct {
+	// MinVersion is the version the validators are checked against
+	MinVersion string
+	Validators []ValidatorHealth
+}
+
+// Outdated returns the validators running an older version than the minimum
+func (h ValidatorsHealth) Outdated() []ValidatorHealth {
+	outdated := []ValidatorHealth{}
+	for _, v := range h.Validators {
+		if v.Outdated {
+			outdated = append(outdated, v)
+		}
+	}
+	return outdated
+}
+
+// ParseNodeVersion parses an avalanchego version, as reported by the info
+// API, e.g. avalanche/1.7.16, or as released, e.g. v1.7.16
+func ParseNodeVersion(version string) (*semver.Version, error) {
+	trimmed := version
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		trimmed = trimmed[i+1:]
+	}
+	parsed, err := semver.NewVersion(strings.TrimPrefix(trimmed, "v"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid avalanchego version %q: %w", version, err)
+	}
+	return parsed, nil
+}
+
+// NodeInfoURI returns the URI of the info API of the node at ip, on the
+// default API port
+func NodeInfoURI(ip string) string {
+	host, _, err := net.SplitHostPort(ip)
+	if err != nil {
+		host = ip
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(constants.NodeAPIPort))
+}
+
+// GetValidatorsHealth lists the current validators of subnetID, with their
+// version and uptime from their own info API where reachable with
+// nodeInfo, or else as observed by the node of the API endpoint, queried
+// with pClient and infoClient. The validators running an older version than
+// minVersion, or than the node of the API endpoint if empty, are flagged as
+// outdated.
+func GetValidatorsHealth(
+	ctx context.Context,
+	pClient validatorsClient,
+	infoClient NodeInfoClient,
+	nodeInfo func(ip string) NodeInfoClient,
+	subnetID ids.ID,
+	minVersion string,
+) (ValidatorsHealth, error) {
+	if minVersion == "" {
+		reply, err := infoClient.GetNodeVersion(ctx)
+		if err != nil {
+			return ValidatorsHealth{}, fmt.Errorf("failed getting the version of the API endpoint: %w", err)
+		}
+		minVersion = reply.Version
+	}
+	min, err := ParseNodeVersion(minVersion)
+	if err != nil {
+		return ValidatorsHealth{}, err
+	}
+
+	current, err := pClient.GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return ValidatorsHealth{}, fmt.Errorf("failed getting the validators of subnet %s: %w", subnetID, err)
+	}
+	nodeIDs := make([]ids.NodeID, len(current))
+	for i, v := range current {
+		nodeIDs[i] = v.NodeID
+	}
+	// the uptime of the subnet validators is tracked as primary network
+	// validators
+	primary := current
+	if subnetID != avago_constants.PrimaryNetworkID && len(nodeIDs) > 0 {
+		if primary, err = pClient.GetCurrentValidators(ctx, avago_constants.PrimaryNetworkID, nodeIDs); err != nil {
+			return ValidatorsHealth{}, fmt.Errorf("failed getting the uptime of the validators: %w", err)
+		}
+	}
+	uptimes := map[ids.NodeID]*float64{}
+	for _, v := range primary {
+		if v.Uptime != nil {
+			uptime := float64(*v.Uptime) * 100
+			uptimes[v.NodeID] = &uptime
+		}
+	}
+	peerList, err := infoClient.Peers(ctx)
+	if err != nil {
+		return ValidatorsHealth{}, fmt.Errorf("failed getting the peers of the API endpoint: %w", err)
+	}
+	peers := map[ids.NodeID]info.Peer{}
+	for _, peer := range peerList {
+		peers[peer.ID] = peer
+	}
+
+	validators := make([]ValidatorHealth, len(current))
+	var wg sync.WaitGroup
+	for i, v := range current {
+		validator := ValidatorHealth{
+			NodeID: v.NodeID,
+			End:    time.Unix(int64(v.EndTime), 0).UTC(),
+			Uptime: uptimes[v.NodeID],
+		}
+		if v.Weight != nil {
+			validator.Weight = *v.Weight
+		}
+		peer, connected := peers[v.NodeID]
+		if connected {
+			validator.Connected = true
+			validator.Version = peer.Version
+			validator.Source = FromPeers
+		}
+		validators[i] = validator
+		ip := peer.PublicIP
+		if ip == "" {
+			ip = peer.IP
+		}
+		if !connected || ip == "" || nodeInfo == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(validator *ValidatorHealth, client NodeInfoClient) {
+			defer wg.Done()
+			queryNodeInfo(ctx, validator, client)
+		}(&validators[i], nodeInfo(ip))
+	}
+	wg.Wait()
+
+	for i := range validators {
+		if validators[i].Version == "" {
+			continue
+		}
+		version, err := ParseNodeVersion(validators[i].Version)
+		validators[i].Outdated = err != nil || version.LessThan(*min)
+	}
+	sort.SliceStable(validators, func(i, j int) bool {
+		return validators[i].NodeID.String() < validators[j].NodeID.String()
+	})
+	return ValidatorsHealth{MinVersion: minVersion, Validators: validators}, nil
+}
+
+// queryNodeInfo sets the version and uptime of validator from its own info
+// API, if it answers in time
+func queryNodeInfo(ctx context.Context, validator *ValidatorHealth, client NodeInfoClient) {
+	ctx, cancel := context.WithTimeout(ctx, constants.NodeInfoTimeout)
+	defer cancel()
+	reply, err := client.GetNodeVersion(ctx)
+	if err != nil {
+		return
+	}
+	validator.Version = reply.Version
+	validator.Source = FromInfoAPI
+	if uptime, err := client.Uptime(ctx); err == nil {
+		percentage := float64(uptime.WeightedAveragePercentage)
+		validator.Uptime = &percentage
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHealthPChain struct {
+	validators map[ids.ID][]platformvm.ClientPrimaryValidator
+}
+
+func (c *fakeHealthPChain) GetCurrentValidators(_ context.Context, subnetID ids.ID, _ []ids.NodeID, _ ...rpc.Option) ([]platformvm.ClientPrimaryValidator, error) {
+	return c.validators[subnetID], nil
+}
+
+func (c *fakeHealthPChain) GetPendingValidators(context.Context, ids.ID, []ids.NodeID, ...rpc.Option) ([]interface{}, []interface{}, error) {
+	return nil, nil, nil
+}
+
+type fakeNodeInfo struct {
+	version string
+	peers   []info.Peer
+	uptime  float64
+	err     error
+}
+
+func (c *fakeNodeInfo) GetNodeVersion(context.Context, ...rpc.Option) (*info.GetNodeVersionReply, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &info.GetNodeVersionReply{Version: c.version}, nil
+}
+
+func (c *fakeNodeInfo) Peers(context.Context, ...rpc.Option) ([]info.Peer, error) {
+	return c.peers, nil
+}
+
+func (c *fakeNodeInfo) Uptime(context.Context, ...rpc.Option) (*info.UptimeResponse, error) {
+	return &info.UptimeResponse{WeightedAveragePercentage: json.Float64(c.uptime)}, nil
+}
+
+func TestGetValidatorsHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID1, _ := ids.NodeIDFromString(testNodeID1)
+	nodeID2, _ := ids.NodeIDFromString(testNodeID2)
+	nodeID3 := ids.GenerateTestNodeID()
+	subnetID := ids.GenerateTestID()
+	weight := uint64(20)
+	uptime := float32(0.9)
+	staker := func(nodeID ids.NodeID) platformvm.ClientPrimaryValidator {
+		return platformvm.ClientPrimaryValidator{ClientStaker: platformvm.ClientStaker{NodeID: nodeID, Weight: &weight, EndTime: 1700000000}}
+	}
+	pClient := &fakeHealthPChain{validators: map[ids.ID][]platformvm.ClientPrimaryValidator{
+		subnetID: {staker(nodeID1), staker(nodeID2), staker(nodeID3)},
+		avago_constants.PrimaryNetworkID: {
+			{ClientStaker: platformvm.ClientStaker{NodeID: nodeID2}, Uptime: &uptime},
+		},
+	}}
+	// the API node is connected to the first two validators, and only the
+	// first one exposes its info API
+	infoClient := &fakeNodeInfo{version: "avalanche/1.7.16", peers: []info.Peer{
+		{Info: peer.Info{ID: nodeID1, IP: "10.0.0.1:9651", Version: "avalanche/1.7.14"}},
+		{Info: peer.Info{ID: nodeID2, IP: "10.0.0.2:9651", Version: "avalanche/1.7.14"}},
+	}}
+	nodes := map[string]NodeInfoClient{
+		"10.0.0.1:9651": &fakeNodeInfo{version: "avalanche/1.7.16", uptime: 99.5},
+		"10.0.0.2:9651": &fakeNodeInfo{err: errors.New("connection refused")},
+	}
+	health, err := GetValidatorsHealth(context.Background(), pClient, infoClient, func(ip string) NodeInfoClient {
+		return nodes[ip]
+	}, subnetID, "")
+	assert.NoError(err)
+	assert.Equal("avalanche/1.7.16", health.MinVersion)
+	assert.Len(health.Validators, 3)
+
+	byID := map[ids.NodeID]ValidatorHealth{}
+	for _, v := range health.Validators {
+		byID[v.NodeID] = v
+	}
+	v1, v2, v3 := byID[nodeID1], byID[nodeID2], byID[nodeID3]
+	assert.Equal(FromInfoAPI, v1.Source)
+	assert.Equal("avalanche/1.7.16", v1.Version)
+	assert.Equal(99.5, *v1.Uptime)
+	assert.False(v1.Outdated)
+
+	assert.Equal(FromPeers, v2.Source)
+	assert.True(v2.Outdated)
+	assert.InDelta(90, *v2.Uptime, 0.001)
+	assert.Equal(uint64(20), v2.Weight)
+
+	assert.False(v3.Connected)
+	assert.Equal(FromNowhere, v3.Source)
+	assert.Nil(v3.Uptime)
+	assert.False(v3.Outdated)
+	assert.Equal([]ValidatorHealth{v2}, health.Outdated())
+
+	// the minimum version can be set ahead of a network upgrade
+	health, err = GetValidatorsHealth(context.Background(), pClient, infoClient, nil, subnetID, "v1.7.15")
+	assert.NoError(err)
+	assert.Len(health.Outdated(), 2)
+
+	_, err = GetValidatorsHealth(context.Background(), pClient, infoClient, nil, subnetID, "latest")
+	assert.ErrorContains(err, `invalid avalanchego version "latest"`)
+}
+
+func TestNodeInfoURI(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("http://10.0.0.1:9650", NodeInfoURI("10.0.0.1:9651"))
+	assert.Equal("http://[::1]:9650", NodeInfoURI("[::1]:9651"))
+	assert.Equal("http://10.0.0.1:9650", NodeInfoURI("10.0.0.1"))
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+// ValidatorStatus is the state of a subnet validator on the P-Chain
+type ValidatorStatus string
+
+const (
+	// ValidatorUnknown means the P-Chain doesn't know the validator (yet)
+	ValidatorUnknown ValidatorStatus = "unknown"
+	ValidatorPending ValidatorStatus = "pending"
+	ValidatorCurrent ValidatorStatus = "current"
+
+	validatorPollInterval = 5 * time.Second
+	// how long after its start time a validator may take to become current
+	validatorStartGrace = time.Minute
+)
+
+// validatorsClient is the part of the P-Chain API needed to follow validators
+type validatorsClient interface {
+	GetCurrentValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID, options ...rpc.Option) ([]platformvm.ClientPrimaryValidator, error)
+	GetPendingValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID, options ...rpc.Option) ([]interface{}, []interface{}, error)
+}
+
+func getValidatorStatus(ctx context.Context, client validatorsClient, subnet ids.ID, nodeID ids.NodeID) (ValidatorStatus, error) {
+	current, err := client.GetCurrentValidators(ctx, subnet, []ids.NodeID{nodeID})
+	if err != nil {
+		return ValidatorUnknown, err
+	}
+	if len(current) > 0 {
+		return ValidatorCurrent, nil
+	}
+	pending, _, err := client.GetPendingValidators(ctx, subnet, []ids.NodeID{nodeID})
+	if err != nil {
+		return ValidatorUnknown, err
+	}
+	if len(pending) > 0 {
+		return ValidatorPending, nil
+	}
+	return ValidatorUnknown, nil
+}
+
+// WaitForValidator watches the P-Chain until the validator of the subnet becomes
+// current. Fails if it isn't current shortly after its start time.
+// onChange is called with every new status of the validator.
+func (d *PublicDeployer) WaitForValidator(
+	subnet ids.ID,
+	nodeID ids.NodeID,
+	start time.Time,
+	onChange func(ValidatorStatus),
+) error {
+	api, _, err := d.endpoint()
+	if err != nil {
+		return err
+	}
+	deadline := start.Add(validatorStartGrace)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	return waitForValidator(ctx, platformvm.NewClient(api), subnet, nodeID, validatorPollInterval, onChange)
+}
+
+func waitForValidator(
+	ctx context.Context,
+	client validatorsClient,
+	subnet ids.ID,
+	nodeID ids.NodeID,
+	interval time.Duration,
+	onChange func(ValidatorStatus),
+) error {
+	last := ValidatorUnknown
+	for {
+		status, err := getValidatorStatus(ctx, client, subnet, nodeID)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			return fmt.Errorf("failed getting the status of validator %s: %w", nodeID, err)
+		case err == nil && status != last:
+			last = status
+			onChange(status)
+		}
+		if status == ValidatorCurrent {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return exitcodes.Unhealthy(fmt.Errorf("validator %s is still %s after its start time", nodeID, last))
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeValidatorsClient returns the statuses in order, one per poll, and then
+// keeps returning the last one
+type fakeValidatorsClient struct {
+	statuses []ValidatorStatus
+	polls    int
+	err      error
+}
+
+func (c *fakeValidatorsClient) status() ValidatorStatus {
+	i := c.polls
+	if i >= len(c.statuses) {
+		i = len(c.statuses) - 1
+	}
+	return c.statuses[i]
+}
+
+func (c *fakeValidatorsClient) GetCurrentValidators(context.Context, ids.ID, []ids.NodeID, ...rpc.Option) ([]platformvm.ClientPrimaryValidator, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.status() == ValidatorCurrent {
+		c.polls++
+		return []platformvm.ClientPrimaryValidator{{}}, nil
+	}
+	return nil, nil
+}
+
+func (c *fakeValidatorsClient) GetPendingValidators(context.Context, ids.ID, []ids.NodeID, ...rpc.Option) ([]interface{}, []interface{}, error) {
+	status := c.status()
+	c.polls++
+	if status == ValidatorPending {
+		return []interface{}{struct{}{}}, nil, nil
+	}
+	return nil, nil, nil
+}
+
+func TestWaitForValidator(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &fakeValidatorsClient{statuses: []ValidatorStatus{
+		ValidatorUnknown, ValidatorPending, ValidatorPending, ValidatorCurrent,
+	}}
+	changes := []ValidatorStatus{}
+	err := waitForValidator(context.Background(), client, ids.Empty, ids.EmptyNodeID, time.Millisecond, func(s ValidatorStatus) {
+		changes = append(changes, s)
+	})
+	assert.NoError(err)
+	assert.Equal([]ValidatorStatus{ValidatorPending, ValidatorCurrent}, changes)
+}
+
+func TestWaitForValidatorTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &fakeValidatorsClient{statuses: []ValidatorStatus{ValidatorPending}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := waitForValidator(ctx, client, ids.Empty, ids.EmptyNodeID, time.Millisecond, func(ValidatorStatus) {})
+	assert.Error(err)
+	assert.Contains(err.Error(), "still pending")
+}
+
+func TestWaitForValidatorError(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &fakeValidatorsClient{statuses: []ValidatorStatus{ValidatorUnknown}, err: errors.New("boom")}
+	err := waitForValidator(context.Background(), client, ids.Empty, ids.EmptyNodeID, time.Millisecond, func(ValidatorStatus) {})
+	assert.ErrorIs(err, client.err)
+}
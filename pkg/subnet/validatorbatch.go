@@ -0,0 +1,274 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+)
+
+// The validators of a batch are added without waiting for each transaction
+// to be committed before issuing the next one. The transactions are built
+// and signed one after the other by a single wallet, each of them spending
+// UTXOs the previous ones left, or their change. They are then issued
+// concurrently, a transaction spending the change of another one only once
+// that one is committed, as the P-Chain refuses to spend outputs it doesn't
+// have yet.
+
+// ValidatorTxStatus is the progress of the transaction adding a validator of
+// a batch
+type ValidatorTxStatus string
+
+const (
+	// ValidatorTxIssued is issued, waiting to be committed
+	ValidatorTxIssued ValidatorTxStatus = "issued"
+	// ValidatorTxCommitted is committed, the validator is added
+	ValidatorTxCommitted ValidatorTxStatus = "committed"
+	// ValidatorTxFailed failed to be built, issued or committed, the
+	// validator is not added
+	ValidatorTxFailed ValidatorTxStatus = "failed"
+)
+
+// ValidatorTxUpdate is a change of the status of the transaction adding a
+// validator of a batch
+type ValidatorTxUpdate struct {
+	Validator ValidatorEntry
+	TxID      ids.ID
+	Status    ValidatorTxStatus
+	// Err is why the transaction failed
+	Err error
+}
+
+// Final returns true if the status of the transaction won't change anymore
+func (u ValidatorTxUpdate) Final() bool {
+	return u.Status == ValidatorTxCommitted || u.Status == ValidatorTxFailed
+}
+
+// txAcceptor is the backend of a P-Chain wallet, which the wallet embeds,
+// updating the UTXOs of the wallet with a transaction
+type txAcceptor interface {
+	AcceptTx(ctx context.Context, tx *txs.Tx) error
+}
+
+// validatorTx is the transaction adding a validator of a batch
+type validatorTx struct {
+	entry ValidatorEntry
+	tx    *txs.Tx
+	id    ids.ID
+	// parents are the transactions of the batch whose change tx spends
+	parents []*validatorTx
+	// done is closed once err is set, nil if the transaction is committed
+	done chan struct{}
+	err  error
+}
+
+// buildValidatorTxs builds and signs the transactions adding validators to
+// subnet with wallet, each of them spending what the previous ones left.
+// The validators whose transaction fails to be built are returned apart,
+// with their error.
+func buildValidatorTxs(
+	ctx context.Context,
+	wallet primary.Wallet,
+	subnet ids.ID,
+	validators []ValidatorEntry,
+) ([]*validatorTx, map[int]error, error) {
+	acceptor, ok := wallet.P().(txAcceptor)
+	if !ok {
+		return nil, nil, errors.New("the wallet can't track the UTXOs spent by a batch")
+	}
+	batch := []*validatorTx{}
+	failed := map[int]error{}
+	for i, v := range validators {
+		utx, err := wallet.P().Builder().NewAddSubnetValidatorTx(newSubnetValidator(subnet, v.NodeID, v.Weight, v.Start, v.Duration))
+		if err != nil {
+			failed[i] = fmt.Errorf("failed building the transaction: %w", err)
+			continue
+		}
+		tx, err := wallet.P().Signer().SignUnsigned(ctx, utx)
+		if err != nil {
+			failed[i] = fmt.Errorf("failed signing the transaction: %w", err)
+			continue
+		}
+		// the next transactions don't spend the UTXOs of this one again, and
+		// may spend its change
+		if err := acceptor.AcceptTx(ctx, tx); err != nil {
+			return nil, nil, err
+		}
+		batch = append(batch, &validatorTx{entry: v, tx: tx, id: tx.ID()})
+	}
+	linkValidatorTxs(batch)
+	return batch, failed, nil
+}
+
+// linkValidatorTxs sets the parents of the transactions of batch, the ones
+// whose outputs they spend
+func linkValidatorTxs(batch []*validatorTx) {
+	byID := map[ids.ID]*validatorTx{}
+	for _, vtx := range batch {
+		byID[vtx.id] = vtx
+	}
+	for _, vtx := range batch {
+		utx, ok := vtx.tx.Unsigned.(*txs.AddSubnetValidatorTx)
+		if !ok {
+			continue
+		}
+		seen := map[ids.ID]struct{}{}
+		for _, in := range utx.Ins {
+			parent, ok := byID[in.TxID]
+			if _, dup := seen[in.TxID]; !ok || dup {
+				continue
+			}
+			seen[in.TxID] = struct{}{}
+			vtx.parents = append(vtx.parents, parent)
+		}
+	}
+}
+
+// runValidatorTxs issues the transactions of batch, parallelism of them at
+// most at once, and waits for them to be committed, a transaction only
+// once its parents are. report is called when a transaction is issued and
+// committed, one call at a time. The transactions failing are left to the
+// caller to report, with their err.
+func runValidatorTxs(
+	batch []*validatorTx,
+	parallelism int,
+	issue func(tx *txs.Tx) error,
+	await func(txID ids.ID) error,
+	report func(ValidatorTxUpdate),
+) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	var reportLock sync.Mutex
+	update := func(vtx *validatorTx, status ValidatorTxStatus, err error) {
+		reportLock.Lock()
+		defer reportLock.Unlock()
+		report(ValidatorTxUpdate{Validator: vtx.entry, TxID: vtx.id, Status: status, Err: err})
+	}
+	for _, vtx := range batch {
+		vtx.done = make(chan struct{})
+	}
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for _, vtx := range batch {
+		wg.Add(1)
+		go func(vtx *validatorTx) {
+			defer wg.Done()
+			defer close(vtx.done)
+			for _, parent := range vtx.parents {
+				<-parent.done
+				if parent.err != nil {
+					vtx.err = fmt.Errorf("it spends the change of the transaction adding %s, which failed", parent.entry.NodeID)
+					return
+				}
+			}
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if err := issue(vtx.tx); err != nil {
+				vtx.err = err
+				return
+			}
+			update(vtx, ValidatorTxIssued, nil)
+			if vtx.err = await(vtx.id); vtx.err == nil {
+				update(vtx, ValidatorTxCommitted, nil)
+			}
+		}(vtx)
+	}
+	wg.Wait()
+}
+
+// AddValidators adds all the validators to the subnet, issuing parallelism
+// transactions at most at once. A failing validator does not stop the
+// others from being added. The transactions failing on UTXOs spent by
+// another command meanwhile are built again with the current UTXOs, and
+// issued again. report is called with each change of the status of the
+// transaction of a validator. Returns the number of validators which failed
+// to be added.
+func (d *PublicDeployer) AddValidators(
+	subnet ids.ID,
+	validators []ValidatorEntry,
+	parallelism int,
+	report func(ValidatorTxUpdate),
+) (int, error) {
+	if err := d.app.CheckWritable("issue transactions"); err != nil {
+		return 0, err
+	}
+	api, _, err := d.endpoint()
+	if err != nil {
+		return 0, err
+	}
+	pClient := platformvm.NewClient(api)
+	ctx := context.Background()
+	issue := func(tx *txs.Tx) error {
+		_, err := pClient.IssueTx(ctx, tx.Bytes())
+		return err
+	}
+	await := func(txID ids.ID) error {
+		return waitForTx(ctx, api, pClient, txID, txConfirmationTimeout, txPollInterval)
+	}
+
+	failed := 0
+	fail := func(v ValidatorEntry, txID ids.ID, err error) {
+		d.app.Log.Error("failed adding validator %s from line %d: %s", v.NodeID, v.Line, err)
+		failed++
+		report(ValidatorTxUpdate{Validator: v, TxID: txID, Status: ValidatorTxFailed, Err: err})
+	}
+	pending := validators
+	for attempt := 1; len(pending) > 0; attempt++ {
+		wallet, _, err := d.loadWallet(subnet)
+		if err != nil {
+			return failed, err
+		}
+		batch, buildErrs, err := buildValidatorTxs(context.Background(), wallet, subnet, pending)
+		if err != nil {
+			return failed, err
+		}
+		for i, v := range pending {
+			if err, ok := buildErrs[i]; ok {
+				fail(v, ids.Empty, err)
+			}
+		}
+		runValidatorTxs(batch, parallelism, issue, await, report)
+
+		conflicted := []ValidatorEntry{}
+		for _, vtx := range batch {
+			switch {
+			case vtx.err == nil:
+			case attempt < txIssueAttempts && (isUTXOConflict(vtx.err) || spendsConflicted(vtx)):
+				conflicted = append(conflicted, vtx.entry)
+			default:
+				err := vtx.err
+				if isUTXOConflict(err) {
+					err = fmt.Errorf("%w, even after %d attempts. Is another command using the same key? Wait for it to complete and try again: %s", ErrUTXOConflict, txIssueAttempts, err)
+				}
+				fail(vtx.entry, vtx.id, err)
+			}
+		}
+		if len(conflicted) > 0 {
+			ux.Logger.PrintToUser("The funds of the key were spent by another transaction, building the transactions of %d validators again (%d/%d)...", len(conflicted), attempt+1, txIssueAttempts)
+			time.Sleep(txIssueRetryBackoff)
+		}
+		pending = conflicted
+	}
+	return failed, nil
+}
+
+// spendsConflicted returns true if vtx failed because a transaction whose
+// change it spends failed on a UTXO conflict, directly or not
+func spendsConflicted(vtx *validatorTx) bool {
+	for _, parent := range vtx.parents {
+		if parent.err != nil && (isUTXOConflict(parent.err) || spendsConflicted(parent)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/stretchr/testify/assert"
+)
+
+// testValidatorTx returns the transaction adding a validator, of ID id,
+// spending outputs of the transactions of spent
+func testValidatorTx(line int, id ids.ID, spent ...ids.ID) *validatorTx {
+	ins := []*avax.TransferableInput{}
+	for i, txID := range spent {
+		ins = append(ins, &avax.TransferableInput{UTXOID: avax.UTXOID{TxID: txID, OutputIndex: uint32(i)}})
+	}
+	utx := &txs.AddSubnetValidatorTx{BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{Ins: ins}}}
+	return &validatorTx{
+		entry: ValidatorEntry{Line: line, NodeID: ids.GenerateTestNodeID()},
+		tx:    &txs.Tx{Unsigned: utx},
+		id:    id,
+	}
+}
+
+func TestLinkValidatorTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	funds := ids.GenerateTestID()
+	a := testValidatorTx(1, ids.GenerateTestID(), funds)
+	b := testValidatorTx(2, ids.GenerateTestID(), a.id, a.id)
+	c := testValidatorTx(3, ids.GenerateTestID(), funds, b.id)
+	linkValidatorTxs([]*validatorTx{a, b, c})
+	assert.Empty(a.parents)
+	assert.Equal([]*validatorTx{a}, b.parents)
+	assert.Equal([]*validatorTx{b}, c.parents)
+}
+
+func TestRunValidatorTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	// a and b spend independent UTXOs, c spends the change of a, d the one
+	// of b, which fails
+	a := testValidatorTx(1, ids.GenerateTestID(), ids.GenerateTestID())
+	b := testValidatorTx(2, ids.GenerateTestID(), ids.GenerateTestID())
+	c := testValidatorTx(3, ids.GenerateTestID(), a.id)
+	d := testValidatorTx(4, ids.GenerateTestID(), b.id)
+	e := testValidatorTx(5, ids.GenerateTestID(), ids.GenerateTestID())
+	batch := []*validatorTx{a, b, c, d, e}
+	linkValidatorTxs(batch)
+
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	committed := map[ids.ID]bool{}
+	issued := []ids.ID{}
+	issue := func(tx *txs.Tx) error {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, vtx := range batch {
+			if vtx.tx != tx {
+				continue
+			}
+			// a transaction is only issued once its parents are committed
+			for _, parent := range vtx.parents {
+				assert.True(committed[parent.id])
+			}
+			issued = append(issued, vtx.id)
+		}
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		return nil
+	}
+	await := func(txID ids.ID) error {
+		lock.Lock()
+		defer lock.Unlock()
+		inFlight--
+		if txID == b.id {
+			return errors.New("failed to read consumed UTXO")
+		}
+		committed[txID] = true
+		return nil
+	}
+	updates := map[ids.ID][]ValidatorTxStatus{}
+	runValidatorTxs(batch, 2, issue, await, func(u ValidatorTxUpdate) {
+		updates[u.TxID] = append(updates[u.TxID], u.Status)
+	})
+
+	assert.LessOrEqual(maxInFlight, 2)
+	assert.ElementsMatch([]ids.ID{a.id, b.id, c.id, e.id}, issued)
+	assert.NoError(a.err)
+	assert.NoError(c.err)
+	assert.NoError(e.err)
+	assert.True(isUTXOConflict(b.err))
+	assert.ErrorContains(d.err, "it spends the change of the transaction adding "+b.entry.NodeID.String()+", which failed")
+	assert.Equal([]ValidatorTxStatus{ValidatorTxIssued, ValidatorTxCommitted}, updates[a.id])
+	assert.Equal([]ValidatorTxStatus{ValidatorTxIssued}, updates[b.id])
+	assert.Empty(updates[d.id])
+
+	// d is built again along with b, as it only failed because of b
+	assert.True(spendsConflicted(d))
+	assert.False(spendsConflicted(c))
+}
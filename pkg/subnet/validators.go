@@ -0,0 +1,162 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ValidatorEntry is a validator to add to a subnet, read from a row of a
+// validators file
+type ValidatorEntry struct {
+	// Line is the line of the entry in the file, for reporting
+	Line     int
+	NodeID   ids.NodeID
+	Weight   uint64
+	Start    time.Time
+	Duration time.Duration
+}
+
+// RowError is a problem found in a single row of a validators file
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// ValidatorsFileError lists all the invalid rows of a validators file
+type ValidatorsFileError struct {
+	Path string
+	Rows []RowError
+}
+
+func (e *ValidatorsFileError) Error() string {
+	rows := make([]string, len(e.Rows))
+	for i, row := range e.Rows {
+		rows[i] = row.Error()
+	}
+	return fmt.Sprintf("%d invalid rows in %s:\n%s", len(e.Rows), e.Path, strings.Join(rows, "\n"))
+}
+
+// LoadValidatorsFile reads validators from a CSV file with the columns
+// nodeID, weight, start and duration. The first row is skipped if it is a
+// header. An empty start defaults to now plus constants.StakingStartLeadTime,
+// an empty duration to constants.MaxStakeDuration.
+// All rows are validated, and if any is invalid, a *ValidatorsFileError
+// listing all of them is returned.
+func LoadValidatorsFile(path string, now time.Time) ([]ValidatorEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var (
+		entries   []ValidatorEntry
+		rowErrors []RowError
+		seen      = map[ids.NodeID]int{}
+	)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", path, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "nodeID") {
+			continue
+		}
+		entry, err := parseValidatorRecord(record, now)
+		if err == nil {
+			if prevLine, ok := seen[entry.NodeID]; ok {
+				err = fmt.Errorf("%s is already listed on line %d", entry.NodeID, prevLine)
+			}
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Err: err})
+			continue
+		}
+		seen[entry.NodeID] = line
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+	if len(rowErrors) > 0 {
+		return nil, &ValidatorsFileError{Path: path, Rows: rowErrors}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no validators found in %s", path)
+	}
+	return entries, nil
+}
+
+func parseValidatorRecord(record []string, now time.Time) (ValidatorEntry, error) {
+	if len(record) < 2 || len(record) > 4 {
+		return ValidatorEntry{}, fmt.Errorf("expected 2 to 4 columns (nodeID, weight, start, duration), got %d", len(record))
+	}
+	for len(record) < 4 {
+		record = append(record, "")
+	}
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	nodeID, err := ids.NodeIDFromString(record[0])
+	if err != nil {
+		return ValidatorEntry{}, fmt.Errorf("invalid nodeID %q: %w", record[0], err)
+	}
+	weight, err := strconv.ParseUint(record[1], 10, 64)
+	if err != nil || weight == 0 {
+		return ValidatorEntry{}, fmt.Errorf("invalid weight %q: must be a positive integer", record[1])
+	}
+
+	start := now.Add(constants.StakingStartLeadTime)
+	if record[2] != "" {
+		start, err = ux.ParseTime(record[2], now)
+		if err != nil {
+			return ValidatorEntry{}, fmt.Errorf("invalid start: %w", err)
+		}
+		if start.Before(now.Add(constants.StakingStartLeadTime)) {
+			return ValidatorEntry{}, fmt.Errorf("start %q should be at least %s in the future", record[2], constants.StakingStartLeadTime)
+		}
+	}
+
+	duration := constants.MaxStakeDuration
+	if record[3] != "" {
+		duration, err = time.ParseDuration(record[3])
+		if err != nil {
+			return ValidatorEntry{}, fmt.Errorf("invalid duration %q: %w", record[3], err)
+		}
+		if duration < constants.MinStakeDuration || duration > constants.MaxStakeDuration {
+			return ValidatorEntry{}, fmt.Errorf("duration %s is outside of the allowed range of %s to %s",
+				duration, constants.MinStakeDuration, constants.MaxStakeDuration)
+		}
+	}
+
+	return ValidatorEntry{
+		NodeID:   nodeID,
+		Weight:   weight,
+		Start:    start,
+		Duration: duration,
+	}, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testNodeID1 = "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"
+	testNodeID2 = "NodeID-MFrZFVCXPv5iCn6M9K6XduxGTYp891xXZ"
+)
+
+func writeValidatorsFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "validators.csv")
+	if err := os.WriteFile(path, []byte(content), WriteReadReadPerms); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadValidatorsFile(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	path := writeValidatorsFile(t, `nodeID,weight,start,duration
+# first batch
+`+testNodeID1+`, 20, 2022-07-02 12:00:00, 720h
+`+testNodeID2+`,30
+`)
+	validators, err := LoadValidatorsFile(path, now)
+	assert.NoError(err)
+	assert.Len(validators, 2)
+
+	assert.Equal(3, validators[0].Line)
+	assert.Equal(testNodeID1, validators[0].NodeID.String())
+	assert.Equal(uint64(20), validators[0].Weight)
+	assert.Equal(time.Date(2022, time.July, 2, 12, 0, 0, 0, time.Local), validators[0].Start)
+	assert.Equal(720*time.Hour, validators[0].Duration)
+
+	assert.Equal(4, validators[1].Line)
+	assert.Equal(uint64(30), validators[1].Weight)
+	assert.Equal(now.Add(constants.StakingStartLeadTime), validators[1].Start)
+	assert.Equal(constants.MaxStakeDuration, validators[1].Duration)
+}
+
+func TestLoadValidatorsFileInvalidRows(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	path := writeValidatorsFile(t, testNodeID1+`,20
+NodeID-invalid,20
+`+testNodeID2+`,0
+`+testNodeID2+`,10,2022-06-30 00:00:00
+`+testNodeID2+`,10,,1h
+`+testNodeID2+`,10,tomorrow
+`+testNodeID1+`,10
+`+testNodeID2+`
+`)
+	_, err := LoadValidatorsFile(path, now)
+	var fileErr *ValidatorsFileError
+	assert.True(errors.As(err, &fileErr))
+	lines := []int{}
+	for _, row := range fileErr.Rows {
+		lines = append(lines, row.Line)
+	}
+	// every row but the first one is invalid
+	assert.Equal([]int{2, 3, 4, 5, 6, 7, 8}, lines)
+	assert.Contains(fileErr.Rows[5].Error(), "already listed on line 1")
+}
+
+func TestLoadValidatorsFileEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	path := writeValidatorsFile(t, "nodeID,weight,start,duration\n")
+	_, err := LoadValidatorsFile(path, time.Now())
+	assert.Error(err)
+
+	_, err = LoadValidatorsFile(filepath.Join(t.TempDir(), "missing.csv"), time.Now())
+	assert.Error(err)
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// GenesisDiff describes how a deployed genesis differs from the local one
+type GenesisDiff struct {
+	// Identical is true if both genesis are equal byte by byte
+	Identical bool
+	// Differences lists the JSON paths whose values differ, empty if
+	// both genesis are semantically equal
+	Differences []string
+}
+
+// GetDeployedGenesis fetches the genesis a blockchain was created with from the
+// P-Chain of the given public network. The blockchain ID is the ID of the
+// transaction which created it.
+func GetDeployedGenesis(app *application.Avalanche, network models.Network, blockchainID ids.ID) ([]byte, error) {
+	createChainTx, err := GetDeployedChain(app, network, blockchainID)
+	if err != nil {
+		return nil, err
+	}
+	return createChainTx.GenesisData, nil
+}
+
+// GetDeployedChain fetches the transaction which created the given blockchain
+// from the P-Chain of the given public network
+func GetDeployedChain(app *application.Avalanche, network models.Network, blockchainID ids.ID) (*txs.CreateChainTx, error) {
+	api, err := app.GetAPIEndpoint(network)
+	if err != nil {
+		return nil, exitcodes.UserInput(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RequestTimeout)
+	defer cancel()
+	txBytes, err := platformvm.NewClient(api).GetTx(ctx, blockchainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching blockchain creation tx %s: %w", blockchainID, err)
+	}
+	tx, err := txs.Parse(txs.Codec, txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing blockchain creation tx %s: %w", blockchainID, err)
+	}
+	createChainTx, ok := tx.Unsigned.(*txs.CreateChainTx)
+	if !ok {
+		return nil, fmt.Errorf("tx %s is not a blockchain creation tx", blockchainID)
+	}
+	return createChainTx, nil
+}
+
+// CompareGenesis compares the local and the deployed genesis, first byte by
+// byte and then, if they differ, as JSON documents
+func CompareGenesis(local, deployed []byte) (GenesisDiff, error) {
+	if bytes.Equal(local, deployed) {
+		return GenesisDiff{Identical: true}, nil
+	}
+	var localJSON, deployedJSON interface{}
+	if err := json.Unmarshal(local, &localJSON); err != nil {
+		return GenesisDiff{}, fmt.Errorf("local genesis is not valid JSON: %w", err)
+	}
+	if err := json.Unmarshal(deployed, &deployedJSON); err != nil {
+		return GenesisDiff{}, fmt.Errorf("deployed genesis is not valid JSON: %w", err)
+	}
+	differences := []string{}
+	diffJSON("", localJSON, deployedJSON, &differences)
+	sort.Strings(differences)
+	return GenesisDiff{Differences: differences}, nil
+}
+
+// diffJSON walks both JSON values and records every path where they differ
+func diffJSON(path string, local, deployed interface{}, differences *[]string) {
+	localMap, localIsMap := local.(map[string]interface{})
+	deployedMap, deployedIsMap := deployed.(map[string]interface{})
+	if localIsMap && deployedIsMap {
+		for k, v := range localMap {
+			diffJSON(joinJSONPath(path, k), v, deployedMap[k], differences)
+		}
+		for k, v := range deployedMap {
+			if _, ok := localMap[k]; !ok {
+				diffJSON(joinJSONPath(path, k), nil, v, differences)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(local, deployed) {
+		*differences = append(*differences, fmt.Sprintf("%s: local %s, deployed %s", path, jsonString(local), jsonString(deployed)))
+	}
+}
+
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonString(v interface{}) string {
+	if v == nil {
+		return "<missing>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	local := []byte(`{"config":{"chainId":9999,"feeConfig":{"gasLimit":8000000}},"alloc":{}}`)
+
+	diff, err := CompareGenesis(local, local)
+	assert.NoError(err)
+	assert.True(diff.Identical)
+	assert.Empty(diff.Differences)
+
+	reformatted := []byte(`{"alloc":{}, "config":{"feeConfig":{"gasLimit":8000000}, "chainId":9999}}`)
+	diff, err = CompareGenesis(local, reformatted)
+	assert.NoError(err)
+	assert.False(diff.Identical)
+	assert.Empty(diff.Differences)
+
+	edited := []byte(`{"config":{"chainId":9999,"feeConfig":{"gasLimit":20000000}},"alloc":{},"timestamp":"0x0"}`)
+	diff, err = CompareGenesis(local, edited)
+	assert.NoError(err)
+	assert.False(diff.Identical)
+	assert.Equal([]string{
+		"config.feeConfig.gasLimit: local 8000000, deployed 20000000",
+		"timestamp: local <missing>, deployed \"0x0\"",
+	}, diff.Differences)
+
+	_, err = CompareGenesis(local, []byte("not json"))
+	assert.Error(err)
+}
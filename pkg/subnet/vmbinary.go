@@ -0,0 +1,72 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// ErrVMBinaryMismatch is returned when a VM plugin binary isn't the one
+// recorded for a deploy
+var ErrVMBinaryMismatch = errors.New("does not match the VM binary deployed")
+
+// newPluginBinaryDownloader locates the subnet-evm releases the plugins are
+// installed from
+var newPluginBinaryDownloader = func(app *application.Avalanche) binutils.PluginBinaryDownloader {
+	return binutils.NewPluginBinaryDownloader(app.Log, app.Conf)
+}
+
+// VMBinaryHash returns the SHA-256 of the VM plugin binary the validators of
+// the chain of sc run on a public network: the file at binaryPath if set,
+// otherwise, for a subnet EVM chain, the binary of the subnet-evm release
+// the CLI installs. It is empty for the other VMs, whose binary the CLI
+// doesn't know.
+func VMBinaryHash(app *application.Avalanche, sc models.Sidecar, binaryPath string) (string, error) {
+	if binaryPath == "" {
+		if sc.VM != models.SubnetEvm {
+			ux.Logger.PrintToUser("Not recording the VM binary of %s, which the CLI doesn't install, pass --vm-binary to record it", sc.Name)
+			return "", nil
+		}
+		var err error
+		binDir := filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)
+		if binaryPath, err = newPluginBinaryDownloader(app).SubnetEVMBinary(binDir); err != nil {
+			return "", err
+		}
+	}
+	hash, err := binutils.SHA256File(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed hashing the VM binary: %w", err)
+	}
+	return hash, nil
+}
+
+// PluginPath returns the path of the plugin of the VM of sc in pluginDir,
+// the plugin dir of an avalanchego node
+func PluginPath(pluginDir string, sc models.Sidecar) (string, error) {
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pluginDir, vmID.String()), nil
+}
+
+// VerifyVMBinary checks the VM plugin binary at path has the SHA-256
+// expected, recorded by the deploy, and returns its own
+func VerifyVMBinary(path string, expected string) (string, error) {
+	hash, err := binutils.SHA256File(path)
+	if err != nil {
+		return "", fmt.Errorf("failed hashing the VM binary: %w", err)
+	}
+	if hash != expected {
+		return hash, fmt.Errorf("%s %w", path, ErrVMBinaryMismatch)
+	}
+	return hash, nil
+}
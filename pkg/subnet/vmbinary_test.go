@@ -0,0 +1,90 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVMBinaryHash(t *testing.T) {
+	assert := setupTest(t)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+
+	dir := t.TempDir()
+	releaseBinary := filepath.Join(dir, "subnet-evm")
+	assert.NoError(os.WriteFile(releaseBinary, []byte("subnet-evm"), constants.DefaultPerms755))
+	customBinary := filepath.Join(dir, "custom")
+	assert.NoError(os.WriteFile(customBinary, []byte("custom"), constants.DefaultPerms755))
+	releaseHash, err := binutils.SHA256File(releaseBinary)
+	assert.NoError(err)
+	customHash, err := binutils.SHA256File(customBinary)
+	assert.NoError(err)
+	assert.NotEqual(releaseHash, customHash)
+
+	downloader := &mocks.PluginBinaryDownloader{}
+	downloader.On("SubnetEVMBinary", mock.Anything).Return(releaseBinary, nil)
+	defer func(orig func(*application.Avalanche) binutils.PluginBinaryDownloader) {
+		newPluginBinaryDownloader = orig
+	}(newPluginBinaryDownloader)
+	newPluginBinaryDownloader = func(*application.Avalanche) binutils.PluginBinaryDownloader { return downloader }
+
+	// subnet EVM chains run the release the CLI installs
+	hash, err := VMBinaryHash(app, models.Sidecar{Name: "evm", VM: models.SubnetEvm}, "")
+	assert.NoError(err)
+	assert.Equal(releaseHash, hash)
+	downloader.AssertCalled(t, "SubnetEVMBinary", filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir))
+
+	// the binary given takes precedence
+	hash, err = VMBinaryHash(app, models.Sidecar{Name: "evm", VM: models.SubnetEvm}, customBinary)
+	assert.NoError(err)
+	assert.Equal(customHash, hash)
+	hash, err = VMBinaryHash(app, models.Sidecar{Name: "custom", VM: models.CustomVM}, customBinary)
+	assert.NoError(err)
+	assert.Equal(customHash, hash)
+
+	// the binary of the other VMs is unknown
+	hash, err = VMBinaryHash(app, models.Sidecar{Name: "custom", VM: models.CustomVM}, "")
+	assert.NoError(err)
+	assert.Empty(hash)
+
+	_, err = VMBinaryHash(app, models.Sidecar{Name: "custom", VM: models.CustomVM}, filepath.Join(dir, "missing"))
+	assert.ErrorContains(err, "failed hashing the VM binary")
+}
+
+func TestVerifyVMBinary(t *testing.T) {
+	assert := setupTest(t)
+
+	sc := models.Sidecar{Name: "evm", VM: models.SubnetEvm}
+	pluginDir := t.TempDir()
+	plugin, err := PluginPath(pluginDir, sc)
+	assert.NoError(err)
+	vmID, err := sc.GetVMID()
+	assert.NoError(err)
+	assert.Equal(filepath.Join(pluginDir, vmID.String()), plugin)
+	assert.NoError(os.WriteFile(plugin, []byte("subnet-evm"), constants.DefaultPerms755))
+
+	expected, err := binutils.SHA256File(plugin)
+	assert.NoError(err)
+	hash, err := VerifyVMBinary(plugin, expected)
+	assert.NoError(err)
+	assert.Equal(expected, hash)
+
+	hash, err = VerifyVMBinary(plugin, strings.Repeat("0", 64))
+	assert.ErrorIs(err, ErrVMBinaryMismatch)
+	assert.Equal(expected, hash)
+
+	_, err = VerifyVMBinary(filepath.Join(pluginDir, "missing"), expected)
+	assert.ErrorIs(err, os.ErrNotExist)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// WaitForReady polls until the local network is healthy and, unless
+// chainVMID is empty, the blockchain of this VM is deployed on it. A network
+// still booting, e.g. started by a deploy running in the background, is
+// waited for as well. Fails with an unhealthy error once ctx is done.
+func (d *LocalSubnetDeployer) WaitForReady(ctx context.Context, cli client.Client, chainVMID ids.ID) (*rpcpb.ClusterInfo, error) {
+	for {
+		clusterInfo, err := d.WaitForHealthy(ctx, cli, d.healthCheckInterval)
+		switch {
+		case ctx.Err() != nil:
+			return nil, exitcodes.Unhealthy(ctx.Err())
+		case err != nil:
+			// TODO: use error type not string comparison
+			if !strings.Contains(err.Error(), "not bootstrapped") {
+				return nil, err
+			}
+			d.app.Log.Debug("network is not bootstrapped yet. polling again...")
+		default:
+			if clusterInfo, err = FilterUndeployed(d.app, clusterInfo); err != nil {
+				return nil, err
+			}
+			if chainVMID == ids.Empty || deployedChain(chainVMID, clusterInfo) != nil {
+				return clusterInfo, nil
+			}
+			d.app.Log.Debug("blockchain of VM %s is not deployed yet. polling again...", chainVMID)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, exitcodes.Unhealthy(ctx.Err())
+		case <-time.After(d.healthCheckInterval):
+		}
+	}
+}
+
+// WaitForSubnet polls until the local network is healthy, and the
+// blockchain of subnetName is deployed on it if subnetName is set, for at
+// most timeout
+func (d *LocalSubnetDeployer) WaitForSubnet(subnetName string, timeout time.Duration) (*rpcpb.ClusterInfo, error) {
+	chainVMID := ids.Empty
+	if subnetName != "" {
+		sc, err := d.app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, exitcodes.UserInput(fmt.Errorf("subnet %s does not exist: %w", subnetName, err))
+		}
+		if chainVMID, err = sc.GetVMID(); err != nil {
+			return nil, err
+		}
+	}
+	isRunning, err := d.procChecker.IsServerProcessRunning(d.app)
+	if err != nil {
+		return nil, exitcodes.Backend(fmt.Errorf("failed querying if server process is running: %w", err))
+	}
+	if !isRunning {
+		return nil, exitcodes.Backend(fmt.Errorf("the local network is not running, start it with network start or subnet deploy"))
+	}
+	cli, err := d.getClientFunc()
+	if err != nil {
+		return nil, exitcodes.Backend(err)
+	}
+	defer cli.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	clusterInfo, err := d.WaitForReady(ctx, cli, chainVMID)
+	if err != nil && ctx.Err() != nil {
+		what := "the local network"
+		if subnetName != "" {
+			what = subnetName
+		}
+		return nil, exitcodes.Unhealthy(fmt.Errorf("%s not ready after %s", what, timeout))
+	}
+	return clusterInfo, err
+}
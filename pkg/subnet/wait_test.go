@@ -0,0 +1,56 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/exitcodes"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWaitForReady(t *testing.T) {
+	assert := setupTest(t)
+
+	vmID, err := ids.FromString(testVMID)
+	assert.NoError(err)
+	deployed := &rpcpb.HealthResponse{
+		ClusterInfo: &rpcpb.ClusterInfo{
+			Healthy:          true,
+			CustomVmsHealthy: true,
+			CustomVms: map[string]*rpcpb.CustomVmInfo{
+				testBlockChainID1: {VmId: testVMID, BlockchainId: testBlockChainID1},
+			},
+		},
+	}
+
+	// the network is still booting, then the blockchain gets deployed
+	cli := &mocks.Client{}
+	cli.On("Health", mock.Anything).Return(nil, errors.New("not bootstrapped")).Once()
+	cli.On("Health", mock.Anything).Return(&rpcpb.HealthResponse{ClusterInfo: &rpcpb.ClusterInfo{Healthy: true, CustomVmsHealthy: true}}, nil).Once()
+	cli.On("Health", mock.Anything).Return(deployed, nil)
+	d := newRebuildTestDeployer(t, cli, nil)
+	clusterInfo, err := d.WaitForReady(context.Background(), cli, vmID)
+	assert.NoError(err)
+	assert.Equal(testBlockChainID1, clusterInfo.CustomVms[testBlockChainID1].BlockchainId)
+	cli.AssertNumberOfCalls(t, "Health", 3)
+
+	// the blockchain of another VM is never deployed
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = d.WaitForReady(ctx, cli, ids.GenerateTestID())
+	assert.Equal(exitcodes.NetworkUnhealthy, exitcodes.FromError(err))
+
+	// other failures of the backend are not waited out
+	cli = &mocks.Client{}
+	cli.On("Health", mock.Anything).Return(nil, errors.New("connection refused"))
+	d = newRebuildTestDeployer(t, cli, nil)
+	_, err = d.WaitForReady(context.Background(), cli, ids.Empty)
+	assert.Equal(exitcodes.BackendFailure, exitcodes.FromError(err))
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+var (
+	// restartDelay is the delay before the first restart of a service, doubled
+	// on each of the next ones
+	restartDelay = constants.ServiceRestartDelay
+	// maxRestartDelay bounds the delay before a restart
+	maxRestartDelay = 30 * time.Second
+)
+
+// Supervise runs the service name of the profile of app, restarting its
+// process per its policy until it is done, or this process is interrupted,
+// in which case it stops it. The processes started are recorded in the
+// registry.
+func Supervise(app *application.Avalanche, name string) error {
+	svc, err := Get(app, name)
+	if err != nil {
+		return err
+	}
+	if svc == nil {
+		return fmt.Errorf("service %s is not recorded", name)
+	}
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+
+	svc.SupervisorPid = os.Getpid()
+	for {
+		cmd, exited, err := startProcess(svc)
+		if err != nil {
+			svc.LastExit = err.Error()
+			_ = Register(app, svc)
+			return err
+		}
+		if err := Register(app, svc); err != nil {
+			app.Log.Warn("failed recording service %s: %s", name, err)
+		}
+
+		var exitErr error
+		select {
+		case sig := <-sigc:
+			app.Log.Info("signal received: %s; stopping service %s", sig, name)
+			stopProcess(cmd, exited)
+			return nil
+		case exitErr = <-exited:
+		}
+		svc.LastExit = exitDescription(exitErr)
+		if !shouldRestart(svc, exitErr) {
+			app.Log.Info("service %s exited: %s", name, svc.LastExit)
+			return Register(app, svc)
+		}
+		delay := restartBackoff(svc.Restarts)
+		svc.Restarts++
+		if err := Register(app, svc); err != nil {
+			app.Log.Warn("failed recording service %s: %s", name, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: service %s exited: %s, restarting it in %s\n", time.Now().Format(time.RFC3339), name, svc.LastExit, delay)
+		select {
+		case <-sigc:
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// startProcess starts the process of svc, with its output appended to the
+// one of the service. The channel returned gets how it exits.
+func startProcess(svc *Service) (*exec.Cmd, chan error, error) {
+	cmd := exec.Command(self(), svc.Args...)
+	if svc.OutputFile != "" {
+		outputFile, err := os.OpenFile(svc.OutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perms.ReadWrite)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer outputFile.Close()
+		cmd.Stdout = outputFile
+		cmd.Stderr = outputFile
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	svc.Pid = cmd.Process.Pid
+	svc.StartedAt = time.Now()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	return cmd, exited, nil
+}
+
+// stopProcess interrupts the process of cmd, and kills it if it doesn't
+// exit in time, or can't be interrupted, as on Windows
+func stopProcess(cmd *exec.Cmd, exited chan error) {
+	if err := cmd.Process.Signal(os.Interrupt); err == nil {
+		select {
+		case <-exited:
+			return
+		case <-time.After(constants.ServiceStopTimeout):
+		}
+	}
+	_ = cmd.Process.Kill()
+	<-exited
+}
+
+// shouldRestart returns true if svc is to be restarted per its policy, its
+// process having exited with exitErr
+func shouldRestart(svc *Service, exitErr error) bool {
+	if svc.MaxRestarts > 0 && svc.Restarts >= svc.MaxRestarts {
+		return false
+	}
+	switch svc.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return exitErr != nil
+	default:
+		return false
+	}
+}
+
+// restartBackoff returns the delay before the restart following restarts
+func restartBackoff(restarts int) time.Duration {
+	delay := restartDelay
+	for i := 0; i < restarts && delay < maxRestartDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRestartDelay {
+		return maxRestartDelay
+	}
+	return delay
+}
+
+func exitDescription(exitErr error) string {
+	if exitErr == nil {
+		return "exit status 0"
+	}
+	return exitErr.Error()
+}
@@ -0,0 +1,361 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package supervisor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/shirou/gopsutil/process"
+)
+
+// RestartPolicy is when a service is restarted once its process exits
+type RestartPolicy string
+
+const (
+	RestartNever     RestartPolicy = "never"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartAlways    RestartPolicy = "always"
+)
+
+// Status is the state of a service, as seen from its processes
+type Status string
+
+const (
+	StatusRunning Status = "running"
+	// StatusRestarting is a service whose process exited, waiting for its
+	// supervisor to restart it
+	StatusRestarting Status = "restarting"
+	StatusExited     Status = "exited"
+)
+
+var (
+	// ErrNotRunning is returned when stopping a service which is not running
+	ErrNotRunning = errors.New("the service is not running")
+
+	// self is the binary the services run, this one
+	self = reexec.Self
+
+	// legacyRunFiles are the run files of the services started before the
+	// registry, in the run dir of the profile
+	legacyRunFiles = map[string]string{
+		constants.BackendService: constants.ServerRunFile,
+		constants.ProxyService:   constants.ProxyRunFile,
+	}
+)
+
+// Spec is a background process of the CLI to start
+type Spec struct {
+	// Name identifies the service in the profile
+	Name string
+	// Args are the args of this binary running the service
+	Args    []string
+	Restart RestartPolicy
+	// MaxRestarts bounds the restarts of the service, 0 for no bound
+	MaxRestarts int
+	// OutputDir prefixes the timestamped dir of the run dir the output of
+	// the service goes to, in OutputFile
+	OutputDir  string
+	OutputFile string
+	// Port the service listens on, if any
+	Port          int
+	CorrelationID string
+	CLIVersion    string
+}
+
+// Service is the record of a background process of the CLI in the registry
+// of its profile
+type Service struct {
+	Name        string        `json:"name"`
+	Args        []string      `json:"args,omitempty"`
+	Restart     RestartPolicy `json:"restart,omitempty"`
+	MaxRestarts int           `json:"maxRestarts,omitempty"`
+	// Pid is the process of the service, the last one started
+	Pid int `json:"pid"`
+	// SupervisorPid is the process restarting the service, 0 if it isn't
+	// restarted
+	SupervisorPid int       `json:"supervisorPid,omitempty"`
+	Port          int       `json:"port,omitempty"`
+	OutputFile    string    `json:"outputFile,omitempty"`
+	StartedAt     time.Time `json:"startedAt"`
+	Restarts      int       `json:"restarts"`
+	// LastExit is how the last process of the service exited
+	LastExit string `json:"lastExit,omitempty"`
+	// CorrelationID identifies the invocation which started the service
+	CorrelationID string `json:"correlationID,omitempty"`
+	// CLIVersion is the version of the CLI running the service
+	CLIVersion string `json:"cliVersion,omitempty"`
+
+	// RecordFile is the file the service was read from
+	RecordFile string `json:"-"`
+}
+
+// Status returns the state of the processes of s
+func (s *Service) Status() Status {
+	if pidExists(s.Pid) {
+		return StatusRunning
+	}
+	if pidExists(s.SupervisorPid) {
+		return StatusRestarting
+	}
+	return StatusExited
+}
+
+// Running returns true if the service runs, or is being restarted
+func (s *Service) Running() bool {
+	return s.Status() != StatusExited
+}
+
+// Uptime returns for how long the current process of s has been running
+func (s *Service) Uptime() time.Duration {
+	if s.StartedAt.IsZero() || s.Status() != StatusRunning {
+		return 0
+	}
+	return time.Since(s.StartedAt).Truncate(time.Second)
+}
+
+// Start starts the service of spec in the profile of app, as a reentrant
+// process of this binary. Unless it is never restarted, it runs under a
+// supervisor process, `avalanche services supervise`, restarting it per
+// its policy.
+func Start(app *application.Avalanche, spec Spec) (*Service, error) {
+	outputDir, err := utils.MkDirWithTimestamp(filepath.Join(app.GetRunDir(), spec.OutputDir))
+	if err != nil {
+		return nil, err
+	}
+	outputFile, err := os.Create(filepath.Join(outputDir, spec.OutputFile))
+	if err != nil {
+		return nil, err
+	}
+	defer outputFile.Close()
+
+	svc := &Service{
+		Name:          spec.Name,
+		Args:          spec.Args,
+		Restart:       spec.Restart,
+		MaxRestarts:   spec.MaxRestarts,
+		Port:          spec.Port,
+		OutputFile:    outputFile.Name(),
+		CorrelationID: spec.CorrelationID,
+		CLIVersion:    spec.CLIVersion,
+	}
+	if svc.Restart == "" {
+		svc.Restart = RestartNever
+	}
+	if svc.Restart == RestartNever {
+		cmd := exec.Command(self(), svc.Args...)
+		cmd.Stdout = outputFile
+		cmd.Stderr = outputFile
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		svc.Pid = cmd.Process.Pid
+		svc.StartedAt = time.Now()
+		return svc, Register(app, svc)
+	}
+
+	// the supervisor reads the service from its record, and records the
+	// processes it starts
+	if err := Register(app, svc); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(self(), "services", "supervise", svc.Name, "--profile", app.GetProfile())
+	cmd.Stdout = outputFile
+	cmd.Stderr = outputFile
+	if err := cmd.Start(); err != nil {
+		_ = Remove(app, svc.Name)
+		return nil, err
+	}
+	go func() { _ = cmd.Wait() }()
+	deadline := time.Now().Add(constants.ServiceStopTimeout)
+	for time.Now().Before(deadline) {
+		if svc, err = Get(app, spec.Name); err != nil {
+			return nil, err
+		}
+		if svc != nil && svc.Pid != 0 {
+			return svc, nil
+		}
+		if !pidExists(cmd.Process.Pid) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("the supervisor of %s did not start it, see %s", spec.Name, outputFile.Name())
+}
+
+// Register records svc in the registry of the profile of app, e.g. for a
+// service running in this process
+func Register(app *application.Avalanche, svc *Service) error {
+	if err := os.MkdirAll(app.GetServicesDir(), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	svcBytes, err := json.MarshalIndent(svc, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := app.GetServiceRecordPath(svc.Name)
+	if err := os.WriteFile(path, svcBytes, perms.ReadWrite); err != nil {
+		return fmt.Errorf("could not write the record of service %s: %w", svc.Name, err)
+	}
+	svc.RecordFile = path
+	return nil
+}
+
+// Remove removes the record of the service name of the profile of app
+func Remove(app *application.Avalanche, name string) error {
+	paths := []string{app.GetServiceRecordPath(name)}
+	if legacy, ok := legacyRunFiles[name]; ok {
+		paths = append(paths, filepath.Join(app.GetRunDir(), legacy))
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed removing run file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Get returns the service name of the profile of app, nil if it isn't
+// recorded
+func Get(app *application.Avalanche, name string) (*Service, error) {
+	svc, err := readRecord(app.GetServiceRecordPath(name), name)
+	if svc != nil || err != nil {
+		return svc, err
+	}
+	if legacy, ok := legacyRunFiles[name]; ok {
+		return readRecord(filepath.Join(app.GetRunDir(), legacy), name)
+	}
+	return nil, nil
+}
+
+// List returns the services recorded in the profile of app, sorted by name
+func List(app *application.Avalanche) ([]*Service, error) {
+	names := map[string]bool{}
+	entries, err := os.ReadDir(app.GetServicesDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), constants.ServiceRecordSuffix) {
+			names[strings.TrimSuffix(entry.Name(), constants.ServiceRecordSuffix)] = true
+		}
+	}
+	for name := range legacyRunFiles {
+		names[name] = true
+	}
+	services := []*Service{}
+	for name := range names {
+		svc, err := Get(app, name)
+		if err != nil {
+			return nil, err
+		}
+		if svc != nil {
+			services = append(services, svc)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+// Stop stops the service name of the profile of app, its supervisor first
+// so that it isn't restarted, and removes its record. It returns
+// ErrNotRunning if none of its processes were running.
+func Stop(app *application.Avalanche, name string) error {
+	svc, err := Get(app, name)
+	if err != nil {
+		return err
+	}
+	if svc == nil {
+		return ErrNotRunning
+	}
+	supervised := pidExists(svc.SupervisorPid)
+	if supervised {
+		if err := terminate(svc.SupervisorPid); err != nil {
+			return err
+		}
+	}
+	running := pidExists(svc.Pid)
+	if running {
+		if err := terminate(svc.Pid); err != nil {
+			return err
+		}
+	}
+	if err := Remove(app, name); err != nil {
+		return err
+	}
+	if !supervised && !running {
+		return ErrNotRunning
+	}
+	return nil
+}
+
+// terminate interrupts the process pid, and kills it if it doesn't exit in
+// time, or can't be interrupted, as on Windows
+func terminate(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("could not find process with pid %d: %w", pid, err)
+	}
+	if err := proc.Signal(os.Interrupt); err == nil {
+		deadline := time.Now().Add(constants.ServiceStopTimeout)
+		for time.Now().Before(deadline) {
+			if !pidExists(pid) {
+				return nil
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	if err := proc.Kill(); err != nil && pidExists(pid) {
+		return fmt.Errorf("failed stopping process with pid %d: %w", pid, err)
+	}
+	return nil
+}
+
+// readRecord reads the record of the service name at path, nil if there is
+// none
+func readRecord(path string, name string) (*Service, error) {
+	svcBytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed reading process info file at %s: %w", path, err)
+	}
+	var record struct {
+		Service
+		// GRPCserverFileName is the output of the backends started before
+		// the registry
+		GRPCserverFileName string `json:"gRPCserverFileName"`
+	}
+	if err := json.Unmarshal(svcBytes, &record); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling run file at %s: %w", path, err)
+	}
+	svc := record.Service
+	svc.Name = name
+	svc.RecordFile = path
+	if svc.OutputFile == "" {
+		svc.OutputFile = record.GRPCserverFileName
+	}
+	return &svc, nil
+}
+
+// pidExists returns true if the process pid exists, false for 0
+func pidExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	exists, err := process.PidExists(int32(pid))
+	return err == nil && exists
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package supervisor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) *application.Avalanche {
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+	// the services run a shell rather than reentering the test binary
+	origSelf, origDelay := self, restartDelay
+	t.Cleanup(func() {
+		self = origSelf
+		restartDelay = origDelay
+	})
+	self = func() string { return "/bin/sh" }
+	restartDelay = time.Millisecond
+	return app
+}
+
+func TestSupervise(t *testing.T) {
+	assert := assert.New(t)
+	app := setupTest(t)
+
+	output := filepath.Join(t.TempDir(), "output")
+	assert.NoError(Register(app, &Service{
+		Name:        "failing",
+		Args:        []string{"-c", "echo attempt; exit 3"},
+		Restart:     RestartOnFailure,
+		MaxRestarts: 2,
+		OutputFile:  output,
+	}))
+	assert.NoError(Supervise(app, "failing"))
+
+	svc, err := Get(app, "failing")
+	assert.NoError(err)
+	assert.Equal(2, svc.Restarts)
+	assert.Equal("exit status 3", svc.LastExit)
+	assert.Equal(os.Getpid(), svc.SupervisorPid)
+	assert.NotZero(svc.Pid)
+	outputBytes, err := os.ReadFile(output)
+	assert.NoError(err)
+	assert.Equal("attempt\nattempt\nattempt\n", string(outputBytes))
+
+	// a clean exit is only restarted with the always policy
+	assert.NoError(Register(app, &Service{Name: "done", Args: []string{"-c", "exit 0"}, Restart: RestartOnFailure}))
+	assert.NoError(Supervise(app, "done"))
+	svc, err = Get(app, "done")
+	assert.NoError(err)
+	assert.Zero(svc.Restarts)
+	assert.Equal("exit status 0", svc.LastExit)
+
+	assert.NoError(Register(app, &Service{Name: "always", Args: []string{"-c", "exit 0"}, Restart: RestartAlways, MaxRestarts: 1}))
+	assert.NoError(Supervise(app, "always"))
+	svc, err = Get(app, "always")
+	assert.NoError(err)
+	assert.Equal(1, svc.Restarts)
+
+	assert.ErrorContains(Supervise(app, "missing"), "service missing is not recorded")
+}
+
+func TestStop(t *testing.T) {
+	assert := assert.New(t)
+	app := setupTest(t)
+
+	cmd := exec.Command("sleep", "30")
+	assert.NoError(cmd.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	assert.NoError(Register(app, &Service{Name: "sleeper", Pid: cmd.Process.Pid, StartedAt: time.Now()}))
+
+	svc, err := Get(app, "sleeper")
+	assert.NoError(err)
+	assert.Equal(StatusRunning, svc.Status())
+	assert.Equal(app.GetServiceRecordPath("sleeper"), svc.RecordFile)
+
+	assert.NoError(Stop(app, "sleeper"))
+	select {
+	case <-exited:
+	case <-time.After(constants.ServiceStopTimeout):
+		assert.Fail("the service was not stopped")
+	}
+	svc, err = Get(app, "sleeper")
+	assert.NoError(err)
+	assert.Nil(svc)
+	assert.ErrorIs(Stop(app, "sleeper"), ErrNotRunning)
+}
+
+func TestLegacyRunFiles(t *testing.T) {
+	assert := assert.New(t)
+	app := setupTest(t)
+
+	assert.NoError(os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755))
+	legacyBackend := filepath.Join(app.GetRunDir(), constants.ServerRunFile)
+	assert.NoError(os.WriteFile(legacyBackend, []byte(`{"pid": 0, "gRPCserverFileName": "/tmp/backend", "cliVersion": "v1.0.0"}`), 0o600))
+	assert.NoError(Register(app, &Service{Name: "other"}))
+
+	services, err := List(app)
+	assert.NoError(err)
+	assert.Len(services, 2)
+	assert.Equal(constants.BackendService, services[0].Name)
+	assert.Equal("/tmp/backend", services[0].OutputFile)
+	assert.Equal("v1.0.0", services[0].CLIVersion)
+	assert.Equal(StatusExited, services[0].Status())
+	assert.Equal("other", services[1].Name)
+
+	assert.ErrorIs(Stop(app, constants.BackendService), ErrNotRunning)
+	assert.NoFileExists(legacyBackend)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	assert := assert.New(t)
+	defer func(orig time.Duration) { restartDelay = orig }(restartDelay)
+	restartDelay = time.Second
+
+	assert.Equal(time.Second, restartBackoff(0))
+	assert.Equal(4*time.Second, restartBackoff(2))
+	assert.Equal(maxRestartDelay, restartBackoff(10))
+}
@@ -0,0 +1,323 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package support collects what is needed to investigate a bug report into
+// a single archive.
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/devenv"
+)
+
+const (
+	// maxLogBytes is how much of the end of a log file is kept, the node
+	// logs growing large on long running networks
+	maxLogBytes = 5 << 20
+
+	// the directories the backend and the network runner write their
+	// output to in the run directory, suffixed with a timestamp
+	backendDirPrefix    = "server_"
+	backendOutputFile   = "avalanche-cli-backend"
+	networkDirPrefix    = "network-runner-root-data_"
+	nodeLogsDir         = "logs"
+	redactedPlaceholder = "[REDACTED]"
+)
+
+var (
+	// privateKeyRegex matches private keys, in the format of avalanchego
+	// and as the 32 bytes in hex of key files. The 0x prefixed hashes of
+	// the EVM logs are kept.
+	privateKeyRegex = regexp.MustCompile(`PrivateKey-[1-9A-HJ-NP-Za-km-z]+|\b[0-9a-fA-F]{64}\b`)
+	// endpointRegex matches the endpoints of the config file, which may
+	// hold the API key of a provider
+	endpointRegex = regexp.MustCompile(`("endpoints"\s*:\s*\{)[^}]*(\})`)
+	// webhooksRegex matches the webhooks of the config file, whose URLs and
+	// secrets are credentials
+	webhooksRegex = regexp.MustCompile(`("webhooks"\s*:\s*\[)(?:[^\[\]]|\[[^\[\]]*\])*(\])`)
+)
+
+// Options sets what goes into a bundle
+type Options struct {
+	// Version of the CLI
+	Version string
+	// ConfigFile is the config file in use, if any
+	ConfigFile string
+	// CLILogs is how many of the most recent CLI logs are included
+	CLILogs int
+}
+
+// entry is a file of the bundle, read from path, or with content if path
+// is empty
+type entry struct {
+	name    string
+	path    string
+	content []byte
+	redact  bool
+}
+
+// Create writes the support bundle of app to archivePath, and returns the
+// names of the files it holds. The bundle holds a description of the
+// environment, the most recent CLI logs, the output of the backend and the
+// node logs of the last local network run, the sidecars of the subnets and
+// the config file. Private keys and API endpoints are redacted, and the key
+// files are never included.
+func Create(app *application.Avalanche, archivePath string, opts Options) ([]string, error) {
+	entries, err := collect(app, opts)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	err = writeArchive(f, entries)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return nil, err
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.name
+	}
+	return names, nil
+}
+
+func collect(app *application.Avalanche, opts Options) ([]entry, error) {
+	entries := []entry{{name: "environment.txt", content: []byte(describeEnvironment(app, opts))}}
+
+	cliLogs, err := app.GetCliLogFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(cliLogs) > opts.CLILogs {
+		cliLogs = cliLogs[:opts.CLILogs]
+	}
+	for _, path := range cliLogs {
+		entries = append(entries, entry{name: "logs/cli/" + filepath.Base(path), path: path, redact: true})
+	}
+
+	runDir := app.GetRunDir()
+	if dir, err := latestDir(runDir, backendDirPrefix); err != nil {
+		return nil, err
+	} else if dir != "" {
+		path := filepath.Join(dir, backendOutputFile)
+		if fileExists(path) {
+			entries = append(entries, entry{name: "logs/backend/" + backendOutputFile, path: path, redact: true})
+		}
+	}
+	if path := app.GetInvocationsLogPath(); fileExists(path) {
+		entries = append(entries, entry{name: "logs/" + constants.InvocationsLogFile, path: path, redact: true})
+	}
+	nodeLogs, err := latestNodeLogs(runDir)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, nodeLogs...)
+
+	sidecars, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(sidecars)
+	for _, name := range sidecars {
+		entries = append(entries, entry{name: "subnets/" + name + constants.SidecarSuffix, path: app.GetSidecarPath(name), redact: true})
+	}
+
+	if opts.ConfigFile != "" && fileExists(opts.ConfigFile) {
+		entries = append(entries, entry{name: "config/" + filepath.Base(opts.ConfigFile), path: opts.ConfigFile, redact: true})
+	}
+	return entries, nil
+}
+
+// describeEnvironment returns the versions and platform details of a bug
+// report
+func describeEnvironment(app *application.Avalanche, opts Options) string {
+	var sb strings.Builder
+	line := func(label, value string) {
+		sb.WriteString(fmt.Sprintf("%-20s %s\n", label+":", value))
+	}
+	version := opts.Version
+	if version == "" {
+		version = "unknown"
+	}
+	line("avalanche-cli", version)
+	avagoVersion, _ := app.Conf.AvalancheGoVersion()
+	line("avalanchego", avagoVersion)
+	subnetEVMVersion, _ := app.Conf.SubnetEVMVersion()
+	line("subnet-evm", subnetEVMVersion)
+	line("go", runtime.Version())
+	line("os", runtime.GOOS+"/"+runtime.GOARCH)
+	if kernel, err := os.ReadFile("/proc/version"); err == nil {
+		line("kernel", strings.TrimSpace(string(kernel)))
+	}
+	line("environment", environmentName(devenv.Detect().Kind))
+	line("profile", app.GetProfile())
+	if project := app.Conf.GetProject(); project != nil {
+		line("project config", project.Path)
+	}
+	line("created", time.Now().UTC().Format(time.RFC3339))
+	return sb.String()
+}
+
+func environmentName(kind devenv.Kind) string {
+	switch kind {
+	case devenv.Codespaces:
+		return "GitHub Codespaces"
+	case devenv.DevContainer:
+		return "devcontainer"
+	case devenv.Container:
+		return "container"
+	}
+	return "host"
+}
+
+// latestNodeLogs returns the log files of the nodes of the most recent
+// local network run, whose directory is either in runDir, or in the
+// directory of a network restart in runDir
+func latestNodeLogs(runDir string) ([]entry, error) {
+	networkDirs, err := filepath.Glob(filepath.Join(runDir, networkDirPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	nested, err := filepath.Glob(filepath.Join(runDir, "*", networkDirPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	networkDir := mostRecent(append(networkDirs, nested...))
+	if networkDir == "" {
+		return nil, nil
+	}
+	logs, err := filepath.Glob(filepath.Join(networkDir, "*", nodeLogsDir, "*"+constants.LogSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(logs)
+	entries := make([]entry, 0, len(logs))
+	for _, path := range logs {
+		rel, err := filepath.Rel(networkDir, path)
+		if err != nil {
+			return nil, err
+		}
+		node := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+		entries = append(entries, entry{name: "logs/nodes/" + node + "/" + filepath.Base(path), path: path, redact: true})
+	}
+	return entries, nil
+}
+
+// latestDir returns the most recent directory of dir named with prefix,
+// or an empty string if there is none
+func latestDir(dir string, prefix string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+"*"))
+	if err != nil {
+		return "", err
+	}
+	return mostRecent(matches), nil
+}
+
+// mostRecent returns the most recent of the directories paths, whose names
+// end with the timestamp of their creation
+func mostRecent(paths []string) string {
+	latest := ""
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		if latest == "" || filepath.Base(path) > filepath.Base(latest) {
+			latest = path
+		}
+	}
+	return latest
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// Redact replaces the private keys, API endpoints and webhooks of content
+func Redact(content []byte) []byte {
+	content = privateKeyRegex.ReplaceAll(content, []byte(redactedPlaceholder))
+	content = webhooksRegex.ReplaceAll(content, []byte(`${1}"`+redactedPlaceholder+`"${2}`))
+	return endpointRegex.ReplaceAll(content, []byte(`${1}"`+redactedPlaceholder+`"${2}`))
+}
+
+func writeArchive(w io.Writer, entries []entry) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		content := e.content
+		if e.path != "" {
+			var err error
+			content, err = readTail(e.path, maxLogBytes)
+			if err != nil {
+				return fmt.Errorf("failed reading %s: %w", e.path, err)
+			}
+		}
+		if e.redact {
+			content = Redact(content)
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    e.name,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readTail returns the last max bytes of the file at path, starting at
+// the beginning of a line if it is cut
+func readTail(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() <= max {
+		return io.ReadAll(f)
+	}
+	if _, err := f.Seek(info.Size()-max, io.SeekStart); err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		content = content[i+1:]
+	}
+	header := fmt.Sprintf("[truncated to the last %d bytes of %d]\n", len(content), info.Size())
+	return append([]byte(header), content...), nil
+}
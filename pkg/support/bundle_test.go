@@ -0,0 +1,113 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+
+func writeTestFile(t *testing.T, path string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func readArchive(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		files[hdr.Name] = string(content)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDir := t.TempDir()
+	app := application.New()
+	app.Setup(baseDir, logging.NoLog{}, config.New(), nil)
+
+	for _, name := range []string{"cli-1.log", "cli-2.log", "cli-3.log"} {
+		writeTestFile(t, filepath.Join(app.GetLogDir(), name), "log of "+name)
+	}
+	runDir := app.GetRunDir()
+	writeTestFile(t, filepath.Join(runDir, "server_20220101_100000", backendOutputFile), "old backend")
+	writeTestFile(t, filepath.Join(runDir, "server_20220102_100000", backendOutputFile), "backend")
+	writeTestFile(t, filepath.Join(runDir, networkDirPrefix+"20220101_100000", "node1", "logs", "main.log"), "old node")
+	writeTestFile(t, filepath.Join(runDir, "restart_20220102_100000", networkDirPrefix+"20220102_100001", "node1", "logs", "main.log"), "node with key "+testKey)
+	writeTestFile(t, filepath.Join(app.GetKeyDir(), "k"+constants.KeySuffix), testKey)
+	writeTestFile(t, app.GetSidecarPath("mySubnet"), `{"Name": "mySubnet"}`)
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, configFile, `{"endpoints": {"fuji": "https://provider.example.com/my-api-key"}, "read-only": true}`)
+
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	names, err := Create(app, archive, Options{Version: "v1.2.3", ConfigFile: configFile, CLILogs: 2})
+	assert.NoError(err)
+
+	files := readArchive(t, archive)
+	assert.Len(names, len(files))
+	assert.Contains(files["environment.txt"], "v1.2.3")
+	cliLogs := 0
+	for name := range files {
+		if strings.HasPrefix(name, "logs/cli/") {
+			cliLogs++
+		}
+	}
+	assert.Equal(2, cliLogs)
+	assert.Equal("backend", files["logs/backend/"+backendOutputFile])
+	assert.Equal("node with key "+redactedPlaceholder, files["logs/nodes/node1/main.log"])
+	assert.Equal(`{"Name": "mySubnet"}`, files["subnets/mySubnet"+constants.SidecarSuffix])
+	assert.Equal(`{"endpoints": {"`+redactedPlaceholder+`"}, "read-only": true}`, files["config/config.json"])
+	for name, content := range files {
+		assert.NotContains(name, constants.KeySuffix)
+		assert.NotContains(content, testKey)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("key "+redactedPlaceholder+" and "+redactedPlaceholder,
+		string(Redact([]byte("key PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN and "+testKey))))
+	// EVM hashes are kept
+	hash := "0x" + testKey
+	assert.Equal(hash, string(Redact([]byte(hash))))
+	// webhooks hold credentials, the settings after them are kept
+	assert.Equal(`{"webhooks": ["`+redactedPlaceholder+`"], "read-only": true}`,
+		string(Redact([]byte(`{"webhooks": [{"url": "https://hooks.example.com/T1/B2", "secret": "s3cret", "events": ["deploy.failed"]}], "read-only": true}`))))
+}
+
+func TestReadTail(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "main.log")
+	writeTestFile(t, path, "first line\nsecond line\nthird line\n")
+	content, err := readTail(path, 20)
+	assert.NoError(err)
+	assert.Equal("[truncated to the last 11 bytes of 34]\nthird line\n", string(content))
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/coreos/go-semver/semver"
+)
+
+// Incompatibility is state recorded by another version of the CLI, or of the
+// releases it runs, which this version may fail on
+type Incompatibility struct {
+	// Problem tells what is incompatible and why
+	Problem string
+	// Fix is the command fixing it, or what to do otherwise
+	Fix string
+}
+
+// CheckCompatibility compares the versions recorded in the state of app, in
+// the sidecars, the genesis, the snapshots and the run file of the
+// backend, against the versions of the CLI and of the releases it runs. The
+// files which can't be read are left to the commands using them.
+func CheckCompatibility(app *application.Avalanche) ([]Incompatibility, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	incompatibilities := []Incompatibility{}
+	for _, name := range names {
+		if incompatibility, ok := checkSubnet(app, name); ok {
+			incompatibilities = append(incompatibilities, incompatibility)
+		}
+	}
+
+	// rebuilding the bootstrap snapshot also fixes the default one
+	snapshotErr := subnet.CheckSnapshotCompatibility(app, constants.DefaultSnapshotName)
+	if errors.Is(snapshotErr, subnet.ErrIncompatibleSnapshot) {
+		incompatibilities = append(incompatibilities, Incompatibility{
+			Problem: snapshotErr.Error(),
+			Fix:     "avalanche network snapshot rebuild-default",
+		})
+	}
+	record, rebuilt, err := subnet.LoadSnapshotRecord(app)
+	if err != nil {
+		return nil, err
+	}
+	if rebuilt && !errors.Is(snapshotErr, subnet.ErrIncompatibleSnapshot) {
+		avalancheGoVersion := subnet.LocalAvalancheGoVersion(app)
+		switch {
+		case isNewer(record.AvalancheGoVersion, avalancheGoVersion):
+			incompatibilities = append(incompatibilities, Incompatibility{
+				Problem: fmt.Sprintf("the bootstrap snapshot was rebuilt with avalanchego %s, newer than the avalanchego %s of the local network", record.AvalancheGoVersion, avalancheGoVersion),
+				Fix:     "avalanche network snapshot rebuild-default",
+			})
+		case isNewer(record.CLIVersion, app.GetVersion()):
+			incompatibilities = append(incompatibilities, Incompatibility{
+				Problem: fmt.Sprintf("the bootstrap snapshot was rebuilt by CLI %s, newer than this CLI %s", record.CLIVersion, app.GetVersion()),
+				Fix:     "avalanche network snapshot rebuild-default",
+			})
+		}
+	}
+
+	backend, err := backendProcess(app)
+	if err != nil {
+		return nil, err
+	}
+	if backend.Running {
+		backendVersion, err := binutils.GetServerCLIVersion(app)
+		if err == nil && backendVersion != "" && app.GetVersion() != "" && backendVersion != app.GetVersion() {
+			incompatibilities = append(incompatibilities, Incompatibility{
+				Problem: fmt.Sprintf("the backend of profile %s was started by CLI %s, while this is CLI %s", app.GetProfile(), backendVersion, app.GetVersion()),
+				Fix:     "avalanche network stop, then avalanche network clean to restart the backend, which resets the local network",
+			})
+		}
+	}
+	return incompatibilities, nil
+}
+
+// checkSubnet returns the incompatibility of the subnet name, if any: a
+// sidecar written by a newer CLI, or a genesis in the format of an older
+// subnet-evm
+func checkSubnet(app *application.Avalanche, name string) (Incompatibility, bool) {
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		return Incompatibility{}, false
+	}
+	if isNewer(sc.Version, constants.SidecarVersion) {
+		return Incompatibility{
+			Problem: fmt.Sprintf("subnet %s was written by a newer CLI, in sidecar format %s while this CLI knows format %s", name, sc.Version, constants.SidecarVersion),
+			Fix:     "upgrade the CLI",
+		}, true
+	}
+	if sc.VM != models.SubnetEvm {
+		return Incompatibility{}, false
+	}
+	genesisBytes, err := os.ReadFile(app.GetGenesisPath(name))
+	if err != nil {
+		return Incompatibility{}, false
+	}
+	version, _ := app.Conf.SubnetEVMVersion()
+	migration, err := vm.MigrateGenesis(genesisBytes, version)
+	if err != nil || len(migration.Applied) == 0 {
+		return Incompatibility{}, false
+	}
+	return Incompatibility{
+		Problem: fmt.Sprintf("the genesis of subnet %s has the format of a subnet-evm older than %s", name, version),
+		Fix:     "avalanche subnet upgradeGenesis " + name,
+	}, true
+}
+
+// isNewer returns true if recorded is a newer version than current, false if
+// either is unknown, such as the empty version of development builds
+func isNewer(recorded, current string) bool {
+	recordedVersion, err := semver.NewVersion(strings.TrimPrefix(recorded, "v"))
+	if err != nil {
+		return false
+	}
+	currentVersion, err := semver.NewVersion(strings.TrimPrefix(current, "v"))
+	if err != nil {
+		return false
+	}
+	return currentVersion.LessThan(*recordedVersion)
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// genesis in the format of subnet-evm v0.1.x
+const legacyGenesis = `{
+	"config": {"chainId": 12345, "allowListConfig": {"blockTimestamp": 0}},
+	"alloc": {},
+	"gasLimit": "0x1312D00",
+	"difficulty": "0x0"
+}`
+
+func TestCheckCompatibility(t *testing.T) {
+	assert := assert.New(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+	app.SetVersion("v0.1.5")
+
+	incompatibilities, err := CheckCompatibility(app)
+	assert.NoError(err)
+	assert.Empty(incompatibilities)
+
+	assert.NoError(app.CreateSidecar(&models.Sidecar{Name: "current", VM: models.SubnetEvm, ChainID: "1"}))
+	assert.NoError(app.CreateSidecar(&models.Sidecar{Name: "legacy", VM: models.SubnetEvm, ChainID: "2"}))
+	assert.NoError(app.WriteGenesisFile("legacy", []byte(legacyGenesis)))
+	writeTestFile(t, app.GetSidecarPath("newer"), `{"Name": "newer", "VM": "SubnetEVM", "Version": "99.0.0"}`)
+	writeTestFile(t, filepath.Join(app.GetSnapshotsDir(), constants.BootstrapSnapshotRecordName),
+		`{"cliVersion": "v0.1.5", "avalancheGoVersion": "v99.0.0"}`)
+	writeTestFile(t, app.GetRunFile(), fmt.Sprintf(`{"pid": %d, "cliVersion": "v0.1.4"}`, os.Getpid()))
+
+	incompatibilities, err = CheckCompatibility(app)
+	assert.NoError(err)
+	assert.Len(incompatibilities, 4)
+	assert.Equal("avalanche subnet upgradeGenesis legacy", incompatibilities[0].Fix)
+	assert.Contains(incompatibilities[1].Problem, "subnet newer was written by a newer CLI")
+	assert.Equal("upgrade the CLI", incompatibilities[1].Fix)
+	assert.Contains(incompatibilities[2].Problem, "avalanchego v99.0.0")
+	assert.Equal("avalanche network snapshot rebuild-default", incompatibilities[2].Fix)
+	assert.Contains(incompatibilities[3].Problem, "started by CLI v0.1.4")
+
+	// development builds don't know their version
+	app.SetVersion("")
+	incompatibilities, err = CheckCompatibility(app)
+	assert.NoError(err)
+	assert.Len(incompatibilities, 3)
+}
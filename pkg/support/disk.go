@@ -0,0 +1,277 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// the categories of the disk usage of the CLI
+const (
+	DiskBinaries  = "binaries"
+	DiskSnapshots = "snapshots"
+	DiskRuns      = "runs"
+	DiskSubnets   = "subnets"
+	DiskLogs      = "logs"
+)
+
+// DiskEntry is something the CLI keeps on disk and the space it takes
+type DiskEntry struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	// Prune is the command removing the entry, if it is no longer in use
+	Prune string `json:"prune,omitempty"`
+}
+
+// GetDiskUsage returns the disk usage of app, by category. activeRootDataDir
+// is the data directory of the running local network, if any: the run
+// directory holding it is in use, as is the most recent one when the backend
+// runs a network which isn't known yet, e.g. one still starting.
+func GetDiskUsage(app *application.Avalanche, activeRootDataDir string) ([]DiskEntry, error) {
+	entries := []DiskEntry{}
+	for _, collect := range []func(*application.Avalanche, string) ([]DiskEntry, error){
+		binariesUsage,
+		snapshotsUsage,
+		runsUsage,
+		subnetsUsage,
+		logsUsage,
+	} {
+		categoryEntries, err := collect(app, activeRootDataDir)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, categoryEntries...)
+	}
+	return entries, nil
+}
+
+// binariesUsage returns the installed releases, the ones of the versions
+// not in use being prunable
+func binariesUsage(app *application.Avalanche, _ string) ([]DiskEntry, error) {
+	binaries, err := installedBinaries(filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir))
+	if err != nil {
+		return nil, err
+	}
+	avalancheGoVersion, _ := app.Conf.AvalancheGoVersion()
+	subnetEVMVersion, _ := app.Conf.SubnetEVMVersion()
+	inUse := map[string]string{
+		"avalanchego": avalancheGoVersion,
+		"subnet-evm":  subnetEVMVersion,
+	}
+	entries := []DiskEntry{}
+	for _, binary := range binaries {
+		entry, err := newDiskEntry(DiskBinaries, binary.Name+" "+binary.Version, binary.Path)
+		if err != nil {
+			return nil, err
+		}
+		if version, ok := inUse[binary.Name]; ok && version != binary.Version {
+			entry.Prune = removeCommand(binary.Path)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// snapshotsUsage returns the snapshots of the local network, those saved
+// by network stop being prunable, and the archive of the bootstrap snapshot
+func snapshotsUsage(app *application.Avalanche, _ string) ([]DiskEntry, error) {
+	names, err := snapshotNames(app.GetSnapshotsDir())
+	if err != nil {
+		return nil, err
+	}
+	entries := []DiskEntry{}
+	for _, name := range names {
+		path := filepath.Join(app.GetSnapshotsDir(), snapshotPrefix+name)
+		entry, err := newDiskEntry(DiskSnapshots, name, path)
+		if err != nil {
+			return nil, err
+		}
+		if name != constants.DefaultSnapshotName {
+			entry.Prune = removeCommand(path)
+		}
+		entries = append(entries, entry)
+	}
+	// network clean resets the default snapshot from the archive
+	archivePath := filepath.Join(app.GetSnapshotsDir(), constants.BootstrapSnapshotArchiveName)
+	if fileExists(archivePath) {
+		entry, err := newDiskEntry(DiskSnapshots, "bootstrap archive", archivePath)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// runsUsage returns the directories of the networks started by the profile,
+// all prunable but the one in use
+func runsUsage(app *application.Avalanche, activeRootDataDir string) ([]DiskEntry, error) {
+	dirEntries, err := readDir(app.GetRunDir())
+	if err != nil {
+		return nil, err
+	}
+	entries := []DiskEntry{}
+	active := -1
+	for _, e := range dirEntries {
+		if !e.IsDir() || e.Name() == constants.ServicesDir {
+			// run files of the background processes
+			continue
+		}
+		path := filepath.Join(app.GetRunDir(), e.Name())
+		entry, err := newDiskEntry(DiskRuns, e.Name(), path)
+		if err != nil {
+			return nil, err
+		}
+		if activeRootDataDir != "" && isWithin(path, activeRootDataDir) {
+			active = len(entries)
+		}
+		entries = append(entries, entry)
+	}
+	if active == -1 && activeRootDataDir == "" && len(entries) > 0 {
+		backend, err := backendProcess(app)
+		if err != nil {
+			return nil, err
+		}
+		if backend.Running {
+			active = lastModified(entries)
+		}
+	}
+	for i := range entries {
+		if i != active {
+			entries[i].Prune = removeCommand(entries[i].Path)
+		}
+	}
+	return entries, nil
+}
+
+// subnetsUsage returns the space taken by every subnet: its files and,
+// for the subnets deployed locally, the logs of their chain on the nodes
+func subnetsUsage(app *application.Avalanche, _ string) ([]DiskEntry, error) {
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	entries := []DiskEntry{}
+	for _, name := range names {
+		entry := DiskEntry{Category: DiskSubnets, Name: name, Path: app.GetSidecarPath(name)}
+		for _, path := range []string{
+			app.GetSidecarPath(name),
+			app.GetGenesisPath(name),
+			app.GetChainConfigPath(name),
+			app.GetHistoryDir(name),
+		} {
+			size, err := dirSize(path)
+			if err != nil {
+				return nil, err
+			}
+			entry.Size += size
+		}
+		sc, err := app.LoadSidecar(name)
+		if err == nil {
+			if blockchainID := sc.Networks[app.GetLocalNetworkKey()].BlockchainID; blockchainID != ids.Empty {
+				size, err := chainLogsSize(app.GetRunDir(), blockchainID.String())
+				if err != nil {
+					return nil, err
+				}
+				entry.Size += size
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// logsUsage returns the logs of the CLI, which it rotates itself
+func logsUsage(app *application.Avalanche, _ string) ([]DiskEntry, error) {
+	if _, err := os.Stat(app.GetLogDir()); os.IsNotExist(err) {
+		return []DiskEntry{}, nil
+	}
+	entry, err := newDiskEntry(DiskLogs, "cli", app.GetLogDir())
+	if err != nil {
+		return nil, err
+	}
+	return []DiskEntry{entry}, nil
+}
+
+func newDiskEntry(category string, name string, path string) (DiskEntry, error) {
+	size, err := dirSize(path)
+	if err != nil {
+		return DiskEntry{}, err
+	}
+	return DiskEntry{Category: category, Name: name, Path: path, Size: size}, nil
+}
+
+// dirSize returns the size of the files under path, 0 if it doesn't exist
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the files of a running network come and go
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// chainLogsSize returns the size of the logs of blockchainID written by the
+// nodes of the networks under runDir
+func chainLogsSize(runDir string, blockchainID string) (int64, error) {
+	var size int64
+	err := filepath.Walk(runDir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() && strings.HasPrefix(info.Name(), blockchainID+".log") {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// lastModified returns the index of the most recently modified of entries,
+// whose names don't all start with the same prefix
+func lastModified(entries []DiskEntry) int {
+	latest := -1
+	var latestInfo os.FileInfo
+	for i, entry := range entries {
+		info, err := os.Stat(entry.Path)
+		if err != nil {
+			continue
+		}
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latest, latestInfo = i, info
+		}
+	}
+	return latest
+}
+
+// isWithin returns true if path is dir or under it
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func removeCommand(path string) string {
+	return "rm -rf " + path
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDiskUsage(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDir := t.TempDir()
+	app := application.New()
+	app.Setup(baseDir, logging.NoLog{}, config.New(), nil)
+
+	entries, err := GetDiskUsage(app, "")
+	assert.NoError(err)
+	assert.Empty(entries)
+
+	avalancheGoVersion, _ := app.Conf.AvalancheGoVersion()
+	binDir := filepath.Join(baseDir, constants.AvalancheCliBinDir)
+	writeTestFile(t, filepath.Join(binDir, "avalanchego-"+avalancheGoVersion, "avalanchego"), "12345")
+	writeTestFile(t, filepath.Join(binDir, "avalanchego-v1.7.0", "avalanchego"), "123")
+	writeTestFile(t, filepath.Join(app.GetSnapshotsDir(), snapshotPrefix+constants.DefaultSnapshotName, "network.json"), "{}")
+	writeTestFile(t, filepath.Join(app.GetSnapshotsDir(), snapshotPrefix+"saved", "network.json"), "{}")
+	blockchainID := ids.GenerateTestID()
+	oldRun := filepath.Join(app.GetRunDir(), "network-runner-root-data_20220101_000000")
+	activeRun := filepath.Join(app.GetRunDir(), "restart_20220102_000000")
+	writeTestFile(t, filepath.Join(oldRun, "node1", "log", blockchainID.String()+".log"), "1234567")
+	writeTestFile(t, filepath.Join(oldRun, "node1", "log", "main.log"), "12")
+	writeTestFile(t, filepath.Join(activeRun, "network-runner-root-data_20220102_000001", "node1", "log", "main.log"), "1")
+	writeTestFile(t, app.GetRunFile(), `{"pid": 0}`)
+	assert.NoError(app.CreateSidecar(&models.Sidecar{
+		Name:     "mySubnet",
+		VM:       models.SubnetEvm,
+		Networks: map[string]models.NetworkData{app.GetLocalNetworkKey(): {BlockchainID: blockchainID}},
+	}))
+	assert.NoError(app.WriteGenesisFile("mySubnet", []byte("{}")))
+
+	entries, err = GetDiskUsage(app, filepath.Join(activeRun, "network-runner-root-data_20220102_000001"))
+	assert.NoError(err)
+	byName := map[string]DiskEntry{}
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+	assert.Len(byName, 7)
+
+	assert.Equal(int64(5), byName["avalanchego "+avalancheGoVersion].Size)
+	assert.Empty(byName["avalanchego "+avalancheGoVersion].Prune)
+	assert.Equal("rm -rf "+filepath.Join(binDir, "avalanchego-v1.7.0"), byName["avalanchego v1.7.0"].Prune)
+	assert.Empty(byName[constants.DefaultSnapshotName].Prune)
+	assert.NotEmpty(byName["saved"].Prune)
+	// the run directory of the running network is in use, the others not
+	assert.Equal(int64(9), byName[filepath.Base(oldRun)].Size)
+	assert.Equal("rm -rf "+oldRun, byName[filepath.Base(oldRun)].Prune)
+	assert.Equal(int64(1), byName[filepath.Base(activeRun)].Size)
+	assert.Empty(byName[filepath.Base(activeRun)].Prune)
+	assert.Equal(DiskRuns, byName[filepath.Base(activeRun)].Category)
+	// the subnet accounts for its files and the logs of its local chain
+	subnet := byName["mySubnet"]
+	assert.Equal(DiskSubnets, subnet.Category)
+	assert.Empty(subnet.Prune)
+	assert.Greater(subnet.Size, int64(2+7))
+
+	// without a running network, no run directory is in use
+	entries, err = GetDiskUsage(app, "")
+	assert.NoError(err)
+	for _, entry := range entries {
+		if entry.Category == DiskRuns {
+			assert.True(strings.HasPrefix(entry.Prune, "rm -rf "))
+		}
+	}
+}
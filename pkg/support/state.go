@@ -0,0 +1,225 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/proxy"
+	"github.com/ava-labs/avalanche-cli/pkg/supervisor"
+	"github.com/shirou/gopsutil/process"
+)
+
+// snapshotPrefix prefixes the directories of the snapshots of the network
+// runner in the snapshots directory
+const snapshotPrefix = "anr-snapshot-"
+
+// State is what the CLI thinks exists, as read from its files
+type State struct {
+	Profile   string        `json:"profile"`
+	BaseDir   string        `json:"baseDir"`
+	Subnets   []SubnetState `json:"subnets"`
+	Binaries  []Binary      `json:"binaries"`
+	Snapshots []string      `json:"snapshots"`
+	Keys      []string      `json:"keys"`
+	Backend   Process       `json:"backend"`
+	Proxy     Process       `json:"proxy"`
+}
+
+// SubnetState is a subnet registered with the CLI and its deployments
+type SubnetState struct {
+	Name    string `json:"name"`
+	VM      string `json:"vm,omitempty"`
+	ChainID string `json:"chainID,omitempty"`
+	// Genesis is true if the genesis of the subnet is stored
+	Genesis     bool         `json:"genesis"`
+	Deployments []Deployment `json:"deployments"`
+	// Error is why the sidecar of the subnet couldn't be read, if it couldn't
+	Error string `json:"error,omitempty"`
+}
+
+// Deployment is a deployment of a subnet recorded in its sidecar
+type Deployment struct {
+	Network      string `json:"network"`
+	SubnetID     string `json:"subnetID"`
+	BlockchainID string `json:"blockchainID"`
+}
+
+// Binary is a release installed in the bin directory of the CLI
+type Binary struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+}
+
+// Process is a background process of the CLI, from its run file
+type Process struct {
+	// RunFile is the path of the run file, empty if there is none
+	RunFile string `json:"runFile,omitempty"`
+	Pid     int    `json:"pid,omitempty"`
+	Running bool   `json:"running"`
+	// Port the process listens on, if known
+	Port int `json:"port,omitempty"`
+}
+
+// GetState reads the state of app. The files which can't be read are
+// reported in the state, so that it can be inspected when it is broken.
+func GetState(app *application.Avalanche) (State, error) {
+	state := State{
+		Profile: app.GetProfile(),
+		BaseDir: app.GetBaseDir(),
+	}
+	names, err := app.GetSidecarNames()
+	if err != nil {
+		return State{}, err
+	}
+	sort.Strings(names)
+	state.Subnets = make([]SubnetState, 0, len(names))
+	for _, name := range names {
+		state.Subnets = append(state.Subnets, subnetState(app, name))
+	}
+	if state.Binaries, err = installedBinaries(filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)); err != nil {
+		return State{}, err
+	}
+	if state.Snapshots, err = snapshotNames(app.GetSnapshotsDir()); err != nil {
+		return State{}, err
+	}
+	if state.Keys, err = keyNames(app.GetKeyDir()); err != nil {
+		return State{}, err
+	}
+	if state.Backend, err = backendProcess(app); err != nil {
+		return State{}, err
+	}
+	ri, running, err := proxy.GetRunInfo(app)
+	if err != nil {
+		return State{}, err
+	}
+	if ri.Pid != 0 {
+		state.Proxy = Process{RunFile: app.GetProxyRunFile(), Pid: ri.Pid, Running: running, Port: int(ri.Port)}
+	}
+	return state, nil
+}
+
+func subnetState(app *application.Avalanche, name string) SubnetState {
+	subnet := SubnetState{
+		Name:        name,
+		Genesis:     app.GenesisExists(name),
+		Deployments: []Deployment{},
+	}
+	sc, err := app.LoadSidecar(name)
+	if err != nil {
+		subnet.Error = err.Error()
+		return subnet
+	}
+	subnet.VM = string(sc.VM)
+	subnet.ChainID = sc.ChainID
+	networks := make([]string, 0, len(sc.Networks))
+	for network := range sc.Networks {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	for _, network := range networks {
+		data := sc.Networks[network]
+		subnet.Deployments = append(subnet.Deployments, Deployment{
+			Network:      network,
+			SubnetID:     data.SubnetID.String(),
+			BlockchainID: data.BlockchainID.String(),
+		})
+	}
+	return subnet
+}
+
+// installedBinaries returns the releases installed in binDir, in directories
+// named after the binary and its version, e.g. avalanchego-v1.7.13
+func installedBinaries(binDir string) ([]Binary, error) {
+	entries, err := readDir(binDir)
+	if err != nil {
+		return nil, err
+	}
+	binaries := []Binary{}
+	for _, e := range entries {
+		i := strings.LastIndex(e.Name(), "-v")
+		if !e.IsDir() || i <= 0 {
+			continue
+		}
+		binaries = append(binaries, Binary{
+			Name:    e.Name()[:i],
+			Version: e.Name()[i+1:],
+			Path:    filepath.Join(binDir, e.Name()),
+		})
+	}
+	return binaries, nil
+}
+
+// snapshotNames returns the names of the snapshots of the local network
+func snapshotNames(snapshotsDir string) ([]string, error) {
+	entries, err := readDir(snapshotsDir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), snapshotPrefix) {
+			names = append(names, strings.TrimPrefix(e.Name(), snapshotPrefix))
+		}
+	}
+	return names, nil
+}
+
+// keyNames returns the names of the local and remote keys
+func keyNames(keyDir string) ([]string, error) {
+	entries, err := readDir(keyDir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		switch {
+		case strings.HasSuffix(e.Name(), constants.RemoteKeySuffix):
+			names = append(names, strings.TrimSuffix(e.Name(), constants.RemoteKeySuffix))
+		case strings.HasSuffix(e.Name(), constants.KeySuffix):
+			names = append(names, strings.TrimSuffix(e.Name(), constants.KeySuffix))
+		}
+	}
+	return names, nil
+}
+
+func backendProcess(app *application.Avalanche) (Process, error) {
+	svc, err := supervisor.Get(app, constants.BackendService)
+	if err != nil {
+		// a broken run file is part of the state to inspect
+		return Process{RunFile: app.GetRunFile()}, nil
+	}
+	if svc == nil {
+		return Process{}, nil
+	}
+	backend := Process{RunFile: svc.RecordFile}
+	if svc.Pid == 0 {
+		// a broken run file is part of the state to inspect
+		return backend, nil
+	}
+	backend.Pid = svc.Pid
+	if backend.Running, err = process.PidExists(int32(svc.Pid)); err != nil {
+		return Process{}, err
+	}
+	// the ports of the profile are assigned before its backend first starts
+	if ports, err := binutils.GetGRPCPorts(app); err == nil {
+		backend.Port = ports.Server
+	}
+	return backend, nil
+}
+
+// readDir returns the entries of dir sorted by name, none if it doesn't exist
+func readDir(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return entries, err
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetState(t *testing.T) {
+	assert := assert.New(t)
+
+	baseDir := t.TempDir()
+	app := application.New()
+	app.Setup(baseDir, logging.NoLog{}, config.New(), nil)
+
+	state, err := GetState(app)
+	assert.NoError(err)
+	assert.Empty(state.Subnets)
+	assert.Empty(state.Binaries)
+	assert.Equal(Process{}, state.Backend)
+	assert.Equal(Process{}, state.Proxy)
+
+	subnetID, blockchainID := ids.GenerateTestID(), ids.GenerateTestID()
+	assert.NoError(app.CreateSidecar(&models.Sidecar{
+		Name:     "mySubnet",
+		VM:       models.SubnetEvm,
+		ChainID:  "1234",
+		Networks: map[string]models.NetworkData{"Fuji": {SubnetID: subnetID, BlockchainID: blockchainID}},
+	}))
+	writeTestFile(t, app.GetSidecarPath("broken"), `{`)
+	binDir := filepath.Join(baseDir, constants.AvalancheCliBinDir)
+	writeTestFile(t, filepath.Join(binDir, "avalanchego-v1.7.13", "avalanchego"), "")
+	writeTestFile(t, filepath.Join(binDir, "subnet-evm-v0.2.3", "subnet-evm"), "")
+	writeTestFile(t, filepath.Join(app.GetSnapshotsDir(), snapshotPrefix+"default", "network.json"), "{}")
+	writeTestFile(t, filepath.Join(app.GetKeyDir(), "k"+constants.KeySuffix), testKey)
+	writeTestFile(t, filepath.Join(app.GetKeyDir(), "ledger"+constants.RemoteKeySuffix), "{}")
+	writeTestFile(t, app.GetRunFile(), `{"pid": 0}`)
+
+	state, err = GetState(app)
+	assert.NoError(err)
+	assert.Len(state.Subnets, 2)
+	assert.Equal("broken", state.Subnets[0].Name)
+	assert.NotEmpty(state.Subnets[0].Error)
+	assert.Equal(SubnetState{
+		Name:    "mySubnet",
+		VM:      string(models.SubnetEvm),
+		ChainID: "1234",
+		Deployments: []Deployment{{
+			Network:      "Fuji",
+			SubnetID:     subnetID.String(),
+			BlockchainID: blockchainID.String(),
+		}},
+	}, state.Subnets[1])
+	assert.Equal([]Binary{
+		{Name: "avalanchego", Version: "v1.7.13", Path: filepath.Join(binDir, "avalanchego-v1.7.13")},
+		{Name: "subnet-evm", Version: "v0.2.3", Path: filepath.Join(binDir, "subnet-evm-v0.2.3")},
+	}, state.Binaries)
+	assert.Equal([]string{"default"}, state.Snapshots)
+	assert.Equal([]string{"k", "ledger"}, state.Keys)
+	// the run file is there, but it doesn't name a process
+	assert.Equal(Process{RunFile: app.GetRunFile()}, state.Backend)
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+)
+
+// maxEvidenceLen is how much of a log line is shown as the evidence of a
+// cause
+const maxEvidenceLen = 200
+
+// Cause is a likely reason for the local network not becoming healthy
+type Cause struct {
+	// Problem tells what went wrong
+	Problem string
+	// Fix is the command fixing it, or what to do otherwise
+	Fix string
+	// Evidence is where the cause was found, and the log line found if any
+	Evidence string
+	// Hits is how many log lines point to the cause
+	Hits int
+
+	rank int
+}
+
+// failureSignature is a known failure of the nodes of the local network,
+// recognized by the lines it logs. A higher rank is a more likely root
+// cause, the failures of low rank often following from others.
+type failureSignature struct {
+	regex *regexp.Regexp
+	rank  int
+	// problem and fix are formatted with the submatches of regex
+	problem func(match []string) string
+	fix     func(match []string) string
+}
+
+var failureSignatures = []failureSignature{
+	{
+		regex: regexp.MustCompile(`(?i)listen tcp [^ ]*?:(\d+): bind: address already in use`),
+		rank:  90,
+		problem: func(m []string) string {
+			return fmt.Sprintf("port %s is used by another process, so a node can't listen on it", m[1])
+		},
+		fix: func(m []string) string {
+			return fmt.Sprintf("stop the process listening on port %s, found with lsof -i :%s, then avalanche network start", m[1], m[1])
+		},
+	},
+	{
+		regex: regexp.MustCompile(`(?i)address already in use`),
+		rank:  85,
+		problem: func([]string) string {
+			return "a port of a node is used by another process"
+		},
+		fix: func([]string) string {
+			return "stop the process using the port, e.g. another local network, then avalanche network start"
+		},
+	},
+	{
+		regex: regexp.MustCompile(`(?i)no space left on device`),
+		rank:  85,
+		problem: func([]string) string {
+			return "the disk is full"
+		},
+		fix: func([]string) string {
+			return "free disk space, avalanche disk usage shows what the CLI uses"
+		},
+	},
+	{
+		regex: regexp.MustCompile(`(?i)incompatible api version with plugin|plugin.*handshake|handshake.*plugin|protocol version mismatch`),
+		rank:  80,
+		problem: func([]string) string {
+			return "a VM plugin failed its handshake with avalanchego, speaking another RPCChainVM protocol"
+		},
+		fix: func([]string) string {
+			return "pin versions of avalanchego and the VM speaking the same RPCChainVM protocol in the project config, or drop --avalanchego-path"
+		},
+	},
+	{
+		regex: regexp.MustCompile(`(?i)corrupt|checksum mismatch|invalid database version|failed to open database`),
+		rank:  75,
+		problem: func([]string) string {
+			return "the database of a node is corrupted or of another version"
+		},
+		fix: func([]string) string {
+			return "avalanche network clean, which deletes the state of the local network, then redeploy the subnets"
+		},
+	},
+}
+
+// nodeExitRegex matches the network runner reporting a node process which
+// exited
+var nodeExitRegex = regexp.MustCompile(`node "([^"]+)" (?:returned error on wait: exit status|exited with exit code:) (-?\d+)`)
+
+// TriageUnhealthy looks for why the local network of app doesn't become
+// healthy: the backend not running, nodes which exited, and known failures
+// in the output of the backend and the logs of the nodes of the last run.
+// The causes are returned the most likely first.
+func TriageUnhealthy(app *application.Avalanche) ([]Cause, error) {
+	causes := []Cause{}
+
+	backend, err := backendProcess(app)
+	if err != nil {
+		return nil, err
+	}
+	if !backend.Running {
+		evidence := app.GetRunFile() + ": missing"
+		if backend.RunFile != "" {
+			evidence = fmt.Sprintf("%s: process %d is gone", backend.RunFile, backend.Pid)
+		}
+		causes = append(causes, Cause{
+			Problem:  "the backend is not running, and the nodes with it",
+			Fix:      "avalanche network start",
+			Evidence: evidence,
+			rank:     100,
+		})
+	}
+
+	logs := map[string]string{}
+	backendDir, err := latestDir(app.GetRunDir(), backendDirPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if path := filepath.Join(backendDir, backendOutputFile); backendDir != "" && fileExists(path) {
+		logs[path] = "backend output"
+	}
+	nodeLogs, err := latestNodeLogs(app.GetRunDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range nodeLogs {
+		logs[e.path] = strings.TrimPrefix(e.name, "logs/nodes/")
+	}
+
+	found := map[string]*Cause{}
+	record := func(c Cause) {
+		if prev, ok := found[c.Problem]; ok {
+			prev.Hits++
+			return
+		}
+		c.Hits = 1
+		found[c.Problem] = &c
+	}
+	paths := make([]string, 0, len(logs))
+	for path := range logs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content, err := readTail(path, maxLogBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", path, err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if m := nodeExitRegex.FindStringSubmatch(line); m != nil && m[2] != "0" {
+				record(Cause{
+					Problem:  fmt.Sprintf("node %s exited with exit code %s", m[1], m[2]),
+					Fix:      fmt.Sprintf("look for the error at the end of the log of %s, in %s", m[1], app.GetRunDir()),
+					Evidence: evidence(logs[path], line),
+					rank:     50,
+				})
+			}
+			for _, sig := range failureSignatures {
+				if m := sig.regex.FindStringSubmatch(line); m != nil {
+					record(Cause{
+						Problem:  sig.problem(m),
+						Fix:      sig.fix(m),
+						Evidence: evidence(logs[path], line),
+						rank:     sig.rank,
+					})
+					// the first signature matching a line is the most precise
+					break
+				}
+			}
+		}
+	}
+	for _, c := range found {
+		causes = append(causes, *c)
+	}
+	sort.SliceStable(causes, func(i, j int) bool {
+		if causes[i].rank != causes[j].rank {
+			return causes[i].rank > causes[j].rank
+		}
+		if causes[i].Hits != causes[j].Hits {
+			return causes[i].Hits > causes[j].Hits
+		}
+		return causes[i].Problem < causes[j].Problem
+	})
+	return causes, nil
+}
+
+// evidence returns line, found in the log named source, redacted and cut
+func evidence(source string, line string) string {
+	line = strings.TrimSpace(string(Redact([]byte(line))))
+	if len(line) > maxEvidenceLen {
+		line = line[:maxEvidenceLen] + "..."
+	}
+	return source + ": " + line
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package support
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriageUnhealthy(t *testing.T) {
+	assert := assert.New(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+
+	// nothing ran yet
+	causes, err := TriageUnhealthy(app)
+	assert.NoError(err)
+	assert.Len(causes, 1)
+	assert.Contains(causes[0].Problem, "backend is not running")
+	assert.Equal("avalanche network start", causes[0].Fix)
+
+	runDir := app.GetRunDir()
+	writeTestFile(t, filepath.Join(runDir, "server_20220102_100000", backendOutputFile),
+		"[node1] started\n"+
+			`[12-01|10:00:05.000] DEBUG node "node1" returned error on wait: exit status 1`+"\n"+
+			`[12-01|10:00:05.000] DEBUG node "node2" returned error on wait: exit status 0`+"\n")
+	networkDir := filepath.Join(runDir, "network-runner-root-data_20220102_100000")
+	writeTestFile(t, filepath.Join(networkDir, "node1", "logs", "main.log"),
+		"INFO starting node\n"+
+			"FATAL failed to start: listen tcp 127.0.0.1:9651: bind: address already in use\n")
+	writeTestFile(t, filepath.Join(networkDir, "node2", "logs", "main.log"),
+		"INFO starting node\n"+
+			"ERROR failed to open database: leveldb: corrupted manifest "+testKey+"\n"+
+			"ERROR leveldb: corrupted journal\n")
+	writeTestFile(t, filepath.Join(networkDir, "node3", "logs", "main.log"), "INFO healthy\n")
+
+	causes, err = TriageUnhealthy(app)
+	assert.NoError(err)
+	problems := []string{}
+	for _, c := range causes {
+		problems = append(problems, c.Problem)
+	}
+	assert.Equal([]string{
+		"the backend is not running, and the nodes with it",
+		"port 9651 is used by another process, so a node can't listen on it",
+		"the database of a node is corrupted or of another version",
+		"node node1 exited with exit code 1",
+	}, problems)
+	assert.Contains(causes[1].Fix, "lsof -i :9651")
+	assert.Equal("node1/main.log: FATAL failed to start: listen tcp 127.0.0.1:9651: bind: address already in use", causes[1].Evidence)
+	assert.Equal(2, causes[2].Hits)
+	assert.NotContains(causes[2].Evidence, testKey)
+	assert.Contains(causes[3].Evidence, "backend output: ")
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package timings measures the wall-clock time a command spends in the
+// operations which usually make it slow, for --timings to report. Nothing
+// is recorded anywhere else.
+package timings
+
+import (
+	"sync"
+	"time"
+)
+
+// Category is a kind of operation whose time is measured
+type Category string
+
+const (
+	// Download is the time spent fetching releases, snapshots and their
+	// metadata from GitHub
+	Download Category = "download"
+	// GRPC is the time spent in the calls to the backend
+	GRPC Category = "grpc"
+	// Health is the time spent waiting for the local network or its chains
+	// to become healthy
+	Health Category = "health"
+	// FileIO is the time spent reading and writing the state of the CLI and
+	// installing binaries
+	FileIO Category = "file-io"
+)
+
+// Categories returns the categories, in the order they are reported
+func Categories() []Category {
+	return []Category{Download, GRPC, Health, FileIO}
+}
+
+// Entry is the time spent in a category of operations
+type Entry struct {
+	Category Category `json:"category"`
+	Seconds  float64  `json:"seconds"`
+	Count    int      `json:"count"`
+}
+
+// Report is the breakdown of the time a command took. The categories may
+// overlap, e.g. waiting for health is made of gRPC calls, and don't add up
+// to the total, the rest being spent in prompts, transactions and
+// computation.
+type Report struct {
+	Command      string  `json:"command"`
+	ExitCode     int     `json:"exitCode"`
+	TotalSeconds float64 `json:"totalSeconds"`
+	Categories   []Entry `json:"categories"`
+}
+
+// Recorder adds up the time spent in each category, safe for concurrent use
+type Recorder struct {
+	lock   sync.Mutex
+	totals map[Category]time.Duration
+	counts map[Category]int
+}
+
+// defaultRecorder records the time of the operations of the command
+var defaultRecorder = NewRecorder()
+
+// NewRecorder creates a recorder with nothing recorded
+func NewRecorder() *Recorder {
+	return &Recorder{
+		totals: map[Category]time.Duration{},
+		counts: map[Category]int{},
+	}
+}
+
+// Add records an operation of category which took d
+func (r *Recorder) Add(category Category, d time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.totals[category] += d
+	r.counts[category]++
+}
+
+// Track starts timing an operation of category, recorded once the returned
+// function is called, e.g. defer r.Track(timings.GRPC)()
+func (r *Recorder) Track(category Category) func() {
+	start := time.Now()
+	return func() {
+		r.Add(category, time.Since(start))
+	}
+}
+
+// Entries returns the time recorded in each category, including the ones
+// with none for the output to always have the same fields
+func (r *Recorder) Entries() []Entry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entries := make([]Entry, 0, len(Categories()))
+	for _, category := range Categories() {
+		entries = append(entries, Entry{
+			Category: category,
+			Seconds:  r.totals[category].Seconds(),
+			Count:    r.counts[category],
+		})
+	}
+	return entries
+}
+
+// Track starts timing an operation of category for the command
+func Track(category Category) func() {
+	return defaultRecorder.Track(category)
+}
+
+// NewReport returns the breakdown of command, which took total and exited
+// with exitCode
+func NewReport(command string, exitCode int, total time.Duration) Report {
+	return Report{
+		Command:      command,
+		ExitCode:     exitCode,
+		TotalSeconds: total.Seconds(),
+		Categories:   defaultRecorder.Entries(),
+	}
+}
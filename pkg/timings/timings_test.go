@@ -0,0 +1,30 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package timings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRecorder()
+	r.Add(GRPC, 2*time.Second)
+	r.Add(GRPC, 500*time.Millisecond)
+	r.Add(Download, time.Second)
+	done := r.Track(FileIO)
+	done()
+
+	entries := r.Entries()
+	// every category is reported, in order, for the output to be stable
+	assert.Len(entries, len(Categories()))
+	assert.Equal(Entry{Category: Download, Seconds: 1, Count: 1}, entries[0])
+	assert.Equal(Entry{Category: GRPC, Seconds: 2.5, Count: 2}, entries[1])
+	assert.Equal(Entry{Category: Health, Seconds: 0, Count: 0}, entries[2])
+	assert.Equal(FileIO, entries[3].Category)
+	assert.Equal(1, entries[3].Count)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package tlsconfig applies the TLS settings of the config file, custom
+// certificate authorities and client certificates, to the outbound HTTPS
+// connections of the CLI, for the enterprises reaching the API endpoints and
+// GitHub through a private gateway.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+)
+
+// Build returns the TLS config of settings, trusting the certificate
+// authorities of the system and the ones of the CA bundle
+func Build(settings config.TLSSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if settings.MinVersion == "1.3" {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+	if settings.CABundle != "" {
+		pemBytes, err := os.ReadFile(settings.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading the CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemBytes) {
+			return nil, fmt.Errorf("no PEM certificate found in the CA bundle %s", settings.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if settings.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(settings.ClientCert, settings.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// Install applies the TLS settings of the config file of app to
+// http.DefaultTransport, which http.DefaultClient, the avalanchego API
+// clients and the downloads of the releases use. It must be called before
+// the transports wrapping it are installed.
+func Install(app *application.Avalanche) error {
+	settings, err := app.Conf.TLSSettings()
+	if err != nil || !settings.IsSet() {
+		return err
+	}
+	tlsConfig, err := Build(settings)
+	if err != nil {
+		return err
+	}
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.New("the default HTTP transport is not customizable")
+	}
+	transport := base.Clone()
+	transport.TLSClientConfig = tlsConfig
+	http.DefaultTransport = transport
+	app.Log.Info("outbound TLS: CA bundle %q, client certificate %q, min version %q",
+		settings.CABundle, settings.ClientCert, settings.MinVersion)
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package topology
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+)
+
+// Action is what bringing a subnet to the state of its topology takes
+type Action string
+
+const (
+	// ActionNone leaves the subnet as it is
+	ActionNone Action = "unchanged"
+	// ActionCreate creates the configuration of the subnet, and deploys it
+	ActionCreate Action = "create"
+	// ActionUpdate updates the configuration of the subnet, and deploys it
+	ActionUpdate Action = "update"
+	// ActionRedeploy updates the configuration of the deployed subnet,
+	// undeploys it and deploys it again
+	ActionRedeploy Action = "redeploy"
+	// ActionDeploy deploys the subnet as configured
+	ActionDeploy Action = "deploy"
+)
+
+// SubnetState is the current state of a subnet
+type SubnetState struct {
+	// Exists is set if the subnet has a configuration
+	Exists  bool
+	VM      models.VMType
+	Genesis []byte
+	// BlockchainID of the subnet on the local network, empty if it is not
+	// deployed there
+	BlockchainID string
+}
+
+// Change is the action planned for a subnet of a topology
+type Change struct {
+	Subnet SubnetSpec
+	Action Action
+	// Reasons are the differences between the topology and the current state
+	Reasons []string
+	// GenesisDiff are the changes to the genesis of the subnet, see
+	// vm.DiffGenesis
+	GenesisDiff []string
+}
+
+// Plan returns the changes bringing the subnets to the state of the
+// topology, in deployment order, given their current state by name
+func Plan(t *Topology, states map[string]SubnetState) ([]Change, error) {
+	ordered, err := t.Order()
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]Change, 0, len(ordered))
+	for _, spec := range ordered {
+		state := states[spec.Name]
+		if !state.Exists {
+			if spec.Genesis == "" {
+				return nil, fmt.Errorf("subnet %s does not exist, and the topology gives no genesis to create it with", spec.Name)
+			}
+			changes = append(changes, Change{Subnet: spec, Action: ActionCreate, Reasons: []string{"no configuration"}})
+			continue
+		}
+		reasons, genesisDiff, err := configDiff(spec, state)
+		if err != nil {
+			return nil, err
+		}
+		change := Change{Subnet: spec, Action: ActionNone, Reasons: reasons, GenesisDiff: genesisDiff}
+		switch {
+		case len(reasons) > 0 && state.BlockchainID != "":
+			change.Action = ActionRedeploy
+		case len(reasons) > 0:
+			change.Action = ActionUpdate
+		case state.BlockchainID == "":
+			change.Action = ActionDeploy
+			change.Reasons = []string{"not deployed"}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// configDiff returns the differences between the configuration of the
+// subnet in the topology and its current one, along with the diff of their
+// genesis
+func configDiff(spec SubnetSpec, state SubnetState) ([]string, []string, error) {
+	reasons := []string{}
+	if spec.VM != "" && spec.VMType() != state.VM {
+		reasons = append(reasons, fmt.Sprintf("VM changes from %s to %s", state.VM, spec.VMType()))
+	}
+	if spec.Genesis == "" {
+		return reasons, nil, nil
+	}
+	genesisBytes, err := spec.ReadGenesis()
+	if err != nil {
+		return nil, nil, err
+	}
+	genesisDiff, err := vm.DiffGenesis(state.Genesis, genesisBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed comparing the genesis of %s: %w", spec.Name, err)
+	}
+	if len(genesisDiff) > 0 {
+		reasons = append(reasons, "genesis changes")
+	}
+	return reasons, genesisDiff, nil
+}
+
+// CheckValidators returns an error if a subnet of the topology requires more
+// validators than the local network has validating nodes. Every node of the
+// local network validates every subnet, except the API nodes.
+func CheckValidators(t *Topology, numNodes int) error {
+	short := []string{}
+	for _, s := range t.Subnets {
+		if s.Validators > numNodes {
+			short = append(short, fmt.Sprintf("%s requires %d", s.Name, s.Validators))
+		}
+	}
+	if len(short) > 0 {
+		return fmt.Errorf("the local network has %d validators, but %s", numNodes, strings.Join(short, ", "))
+	}
+	return nil
+}
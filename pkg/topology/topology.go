@@ -0,0 +1,177 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/spf13/viper"
+)
+
+const (
+	vmSubnetEVM = "subnet-evm"
+	vmCustom    = "custom"
+)
+
+// Topology describes a set of subnets deployed together to the local network
+type Topology struct {
+	// Path is the file the topology was loaded from
+	Path    string       `mapstructure:"-"`
+	Subnets []SubnetSpec `mapstructure:"subnets"`
+	// APINodes is the number of nodes of the local network tracking the
+	// subnets without validating them, overriding the one of the project
+	// config if set
+	APINodes int `mapstructure:"api-nodes"`
+}
+
+// SubnetSpec is the desired state of one subnet of a topology
+type SubnetSpec struct {
+	Name string `mapstructure:"name"`
+	// VM is either subnet-evm or custom, defaults to subnet-evm
+	VM string `mapstructure:"vm"`
+	// Genesis is the genesis file of the subnet, relative to the topology
+	// file. Without it, the subnet must already have been created.
+	Genesis string `mapstructure:"genesis"`
+	// Validators is the minimum number of validators the subnet requires
+	Validators int `mapstructure:"validators"`
+	// DependsOn are the subnets of the topology which must be deployed
+	// before this one
+	DependsOn []string `mapstructure:"depends-on"`
+}
+
+// VMType returns the VM of the subnet as recorded in its sidecar
+func (s SubnetSpec) VMType() models.VMType {
+	if s.VM == vmCustom {
+		return models.CustomVM
+	}
+	return models.SubnetEvm
+}
+
+// ReadGenesis returns the content of the genesis file of the subnet
+func (s SubnetSpec) ReadGenesis() ([]byte, error) {
+	genesisBytes, err := os.ReadFile(s.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading the genesis of %s: %w", s.Name, err)
+	}
+	return genesisBytes, nil
+}
+
+// Load reads the topology at path and validates it. The genesis files are
+// resolved relative to the directory of path.
+func Load(path string) (*Topology, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed reading topology %s: %w", path, err)
+	}
+	t := &Topology{}
+	if err := v.Unmarshal(t); err != nil {
+		return nil, fmt.Errorf("failed parsing topology %s: %w", path, err)
+	}
+	t.Path = path
+	for i := range t.Subnets {
+		if t.Subnets[i].Genesis != "" && !filepath.IsAbs(t.Subnets[i].Genesis) {
+			t.Subnets[i].Genesis = filepath.Join(filepath.Dir(path), t.Subnets[i].Genesis)
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid topology %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Validate checks the subnets of the topology are uniquely named, use a known
+// VM, and only depend on other subnets of the topology, without cycles
+func (t *Topology) Validate() error {
+	if len(t.Subnets) == 0 {
+		return errors.New("no subnets defined")
+	}
+	if t.APINodes < 0 {
+		return errors.New("api-nodes is negative")
+	}
+	names := map[string]bool{}
+	for _, s := range t.Subnets {
+		if err := checkName(s.Name); err != nil {
+			return err
+		}
+		if names[s.Name] {
+			return fmt.Errorf("subnet %s is defined more than once", s.Name)
+		}
+		names[s.Name] = true
+		if s.VM != "" && s.VM != vmSubnetEVM && s.VM != vmCustom {
+			return fmt.Errorf("subnet %s has unknown VM %q, expected %s or %s", s.Name, s.VM, vmSubnetEVM, vmCustom)
+		}
+		if s.Validators < 0 {
+			return fmt.Errorf("subnet %s has a negative number of validators", s.Name)
+		}
+	}
+	for _, s := range t.Subnets {
+		for _, dep := range s.DependsOn {
+			if !names[dep] {
+				return fmt.Errorf("subnet %s depends on %s, which is not part of the topology", s.Name, dep)
+			}
+			if dep == s.Name {
+				return fmt.Errorf("subnet %s depends on itself", s.Name)
+			}
+		}
+	}
+	_, err := t.Order()
+	return err
+}
+
+func checkName(name string) error {
+	if name == "" {
+		return errors.New("a subnet has no name")
+	}
+	for _, r := range name {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsNumber(r)) {
+			return fmt.Errorf("subnet name %q is invalid: only letters and numbers are allowed", name)
+		}
+	}
+	return nil
+}
+
+// Order returns the subnets in the order to deploy them, every subnet after
+// the ones it depends on. Subnets without dependencies between them keep the
+// order of the topology file.
+func (t *Topology) Order() ([]SubnetSpec, error) {
+	ordered := make([]SubnetSpec, 0, len(t.Subnets))
+	done := map[string]bool{}
+	for len(ordered) < len(t.Subnets) {
+		progress := false
+		for _, s := range t.Subnets {
+			if done[s.Name] || !allDone(s.DependsOn, done) {
+				continue
+			}
+			ordered = append(ordered, s)
+			done[s.Name] = true
+			progress = true
+		}
+		if !progress {
+			cycle := []string{}
+			for _, s := range t.Subnets {
+				if !done[s.Name] {
+					cycle = append(cycle, s.Name)
+				}
+			}
+			return nil, fmt.Errorf("the dependencies of %s form a cycle", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+func allDone(names []string, done map[string]bool) bool {
+	for _, name := range names {
+		if !done[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package topology
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTopology = `
+subnets:
+  - name: bridge
+    genesis: ./bridge.json
+    depends-on: [tokens, games]
+  - name: tokens
+    genesis: ./tokens.json
+    validators: 3
+  - name: games
+    vm: custom
+    depends-on: [tokens]
+`
+
+func writeTopology(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "topology.yaml")
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	path := writeTopology(t, testTopology)
+	topology, err := Load(path)
+	assert.NoError(err)
+	assert.Len(topology.Subnets, 3)
+	assert.Equal(filepath.Join(filepath.Dir(path), "bridge.json"), topology.Subnets[0].Genesis)
+	assert.Equal(3, topology.Subnets[1].Validators)
+	assert.Equal(models.VMType(models.CustomVM), topology.Subnets[2].VMType())
+	assert.Equal(models.VMType(models.SubnetEvm), topology.Subnets[1].VMType())
+
+	ordered, err := topology.Order()
+	assert.NoError(err)
+	names := []string{}
+	for _, s := range ordered {
+		names = append(names, s.Name)
+	}
+	assert.Equal([]string{"tokens", "games", "bridge"}, names)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]string{
+		"no subnets defined": `subnets: []`,
+		"is defined more than once": `
+subnets:
+  - name: tokens
+  - name: tokens`,
+		"unknown VM": `
+subnets:
+  - name: tokens
+    vm: wasm`,
+		"not part of the topology": `
+subnets:
+  - name: tokens
+    depends-on: [games]`,
+		"form a cycle": `
+subnets:
+  - name: tokens
+    depends-on: [games]
+  - name: games
+    depends-on: [tokens]`,
+		"only letters and numbers": `
+subnets:
+  - name: my-subnet`,
+		"api-nodes is negative": `
+api-nodes: -1
+subnets:
+  - name: tokens`,
+	}
+	for expected, content := range tests {
+		_, err := Load(writeTopology(t, content))
+		assert.ErrorContains(err, expected)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	genesisPath := filepath.Join(dir, "genesis.json")
+	err := os.WriteFile(genesisPath, []byte(`{"config": {"chainId": 2}, "gasLimit": "0x7A1200"}`), 0o600)
+	assert.NoError(err)
+	current := []byte(`{
+    "gasLimit": "0x7A1200",
+    "config": {"chainId": 1}
+}`)
+
+	topology := &Topology{Subnets: []SubnetSpec{
+		{Name: "created", Genesis: genesisPath},
+		{Name: "updated", Genesis: genesisPath},
+		{Name: "redeployed", Genesis: genesisPath, VM: vmCustom},
+		{Name: "deployed"},
+		{Name: "unchanged", Genesis: genesisPath},
+	}}
+	states := map[string]SubnetState{
+		"updated":    {Exists: true, VM: models.SubnetEvm, Genesis: current},
+		"redeployed": {Exists: true, VM: models.SubnetEvm, Genesis: current, BlockchainID: "chain1"},
+		"deployed":   {Exists: true, VM: models.SubnetEvm, Genesis: current},
+		"unchanged": {
+			Exists:       true,
+			VM:           models.SubnetEvm,
+			Genesis:      []byte(`{"gasLimit": "0x7A1200", "config": {"chainId": 2}}`),
+			BlockchainID: "chain2",
+		},
+	}
+	changes, err := Plan(topology, states)
+	assert.NoError(err)
+	assert.Len(changes, 5)
+	assert.Equal(ActionCreate, changes[0].Action)
+	assert.Equal(ActionUpdate, changes[1].Action)
+	assert.Equal([]string{"genesis changes"}, changes[1].Reasons)
+	assert.Equal([]string{"- config.chainId: 1", "+ config.chainId: 2"}, changes[1].GenesisDiff)
+	assert.Equal(ActionRedeploy, changes[2].Action)
+	assert.Equal([]string{"VM changes from SubnetEVM to Custom", "genesis changes"}, changes[2].Reasons)
+	assert.Equal(ActionDeploy, changes[3].Action)
+	assert.Equal(ActionNone, changes[4].Action)
+	assert.Empty(changes[4].GenesisDiff)
+
+	topology.Subnets = append(topology.Subnets, SubnetSpec{Name: "unknown"})
+	_, err = Plan(topology, states)
+	assert.ErrorContains(err, "subnet unknown does not exist")
+}
+
+func TestCheckValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	topology := &Topology{Subnets: []SubnetSpec{
+		{Name: "tokens", Validators: 5},
+		{Name: "games", Validators: 7},
+		{Name: "bridge"},
+	}}
+	assert.NoError(CheckValidators(topology, 7))
+	assert.EqualError(CheckValidators(topology, 5), "the local network has 5 validators, but games requires 7")
+}
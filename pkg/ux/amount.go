@@ -0,0 +1,84 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"math/big"
+	"strings"
+)
+
+// numberFormat holds the separators numbers are written with in a language
+type numberFormat struct {
+	group   string
+	decimal string
+}
+
+var (
+	englishNumbers = numberFormat{group: ",", decimal: "."}
+
+	// numberFormats of the languages not writing numbers the english way
+	numberFormats = map[string]numberFormat{
+		"de": {group: ".", decimal: ","},
+		"es": {group: ".", decimal: ","},
+		"id": {group: ".", decimal: ","},
+		"it": {group: ".", decimal: ","},
+		"nl": {group: ".", decimal: ","},
+		"pt": {group: ".", decimal: ","},
+		"tr": {group: ".", decimal: ","},
+		"cs": {group: " ", decimal: ","},
+		"fi": {group: " ", decimal: ","},
+		"fr": {group: " ", decimal: ","},
+		"nb": {group: " ", decimal: ","},
+		"pl": {group: " ", decimal: ","},
+		"ru": {group: " ", decimal: ","},
+		"sv": {group: " ", decimal: ","},
+		"uk": {group: " ", decimal: ","},
+	}
+
+	numbers = englishNumbers
+)
+
+// SetNumberLocale sets the language numbers are formatted for, e.g. `de`
+func SetNumberLocale(lang string) {
+	if format, ok := numberFormats[strings.ToLower(lang)]; ok {
+		numbers = format
+		return
+	}
+	numbers = englishNumbers
+}
+
+// FormatAmount returns amount, counted in units of 10^-decimals tokens, as
+// a number of tokens with thousands separators, e.g. `1,000,000.5`. The
+// fraction is exact, with its trailing zeros trimmed.
+func FormatAmount(amount *big.Int, decimals int) string {
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	digits := new(big.Int).Abs(amount).String()
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer := digits[:len(digits)-decimals]
+	fraction := strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	var sb strings.Builder
+	sb.WriteString(sign)
+	for i, d := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(numbers.group)
+		}
+		sb.WriteRune(d)
+	}
+	if fraction != "" {
+		sb.WriteString(numbers.decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
+}
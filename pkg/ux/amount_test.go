@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	assert := assert.New(t)
+	defer SetNumberLocale(DefaultLanguage)
+
+	million, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	half, _ := new(big.Int).SetString("1234567500000000000000000", 10)
+
+	tests := []struct {
+		amount   *big.Int
+		decimals int
+		expected string
+	}{
+		{million, 18, "1,000,000"},
+		{half, 18, "1,234,567.5"},
+		{big.NewInt(1), 18, "0.000000000000000001"},
+		{big.NewInt(0), 18, "0"},
+		{big.NewInt(-1234), 0, "-1,234"},
+		{big.NewInt(123456), 2, "1,234.56"},
+		{big.NewInt(999), 0, "999"},
+		{nil, 18, "0"},
+	}
+	for _, tt := range tests {
+		assert.Equal(tt.expected, FormatAmount(tt.amount, tt.decimals))
+	}
+
+	SetNumberLocale("de")
+	assert.Equal("1.234.567,5", FormatAmount(half, 18))
+	SetNumberLocale("fr")
+	assert.Equal("1 234 567,5", FormatAmount(half, 18))
+	SetNumberLocale("xx")
+	assert.Equal("1,234,567.5", FormatAmount(half, 18))
+}
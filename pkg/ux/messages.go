@@ -0,0 +1,449 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// MessageID identifies a user facing message of the message catalog
+type MessageID string
+
+// DefaultLanguage is the language of the built-in message catalog
+const DefaultLanguage = "en"
+
+const (
+	// pkg/vm
+	MsgCreatingSubnet          MessageID = "vm.creatingSubnet"
+	MsgCreatingCustomSubnet    MessageID = "vm.creatingCustomSubnet"
+	MsgCustomGenesisPath       MessageID = "vm.customGenesisPath"
+	MsgEnterChainID            MessageID = "vm.enterChainID"
+	MsgChainIDLabel            MessageID = "vm.chainIDLabel"
+	MsgChainIDExists           MessageID = "vm.chainIDExists"
+	MsgSelectTokenSymbol       MessageID = "vm.selectTokenSymbol"
+	MsgTokenSymbolLabel        MessageID = "vm.tokenSymbolLabel"
+	MsgGoBack                  MessageID = "vm.goBack"
+	MsgDefaultAirdrop          MessageID = "vm.defaultAirdrop"
+	MsgCustomAirdrop           MessageID = "vm.customAirdrop"
+	MsgExtendAirdrop           MessageID = "vm.extendAirdrop"
+	MsgDistributeFunds         MessageID = "vm.distributeFunds"
+	MsgAirdropAddress          MessageID = "vm.airdropAddress"
+	MsgAirdropAmount           MessageID = "vm.airdropAmount"
+	MsgLockAirdrop             MessageID = "vm.lockAirdrop"
+	MsgAirdropUnlockTime       MessageID = "vm.airdropUnlockTime"
+	MsgAirdropLocked           MessageID = "vm.airdropLocked"
+	MsgUnspendableAddress      MessageID = "vm.unspendableAddress"
+	MsgAllocateAnyway          MessageID = "vm.allocateAnyway"
+	MsgValidatorAirdrop        MessageID = "vm.validatorAirdrop"
+	MsgSharesFromFile          MessageID = "vm.sharesFromFile"
+	MsgSharesFromKeys          MessageID = "vm.sharesFromKeys"
+	MsgSharesSource            MessageID = "vm.sharesSource"
+	MsgSharesFilePath          MessageID = "vm.sharesFilePath"
+	MsgSharesPickKey           MessageID = "vm.sharesPickKey"
+	MsgSharesDone              MessageID = "vm.sharesDone"
+	MsgSharesEqualSplit        MessageID = "vm.sharesEqualSplit"
+	MsgSharesPercentage        MessageID = "vm.sharesPercentage"
+	MsgSharesTotal             MessageID = "vm.sharesTotal"
+	MsgSharesAllocated         MessageID = "vm.sharesAllocated"
+	MsgMnemonicAirdrop         MessageID = "vm.mnemonicAirdrop"
+	MsgMnemonic                MessageID = "vm.mnemonic"
+	MsgMnemonicCount           MessageID = "vm.mnemonicCount"
+	MsgMnemonicAmount          MessageID = "vm.mnemonicAmount"
+	MsgMnemonicAllocated       MessageID = "vm.mnemonicAllocated"
+	MsgNewKeyAirdrop           MessageID = "vm.newKeyAirdrop"
+	MsgNewKeyName              MessageID = "vm.newKeyName"
+	MsgNewKeyExists            MessageID = "vm.newKeyExists"
+	MsgNewKeyCreated           MessageID = "vm.newKeyCreated"
+	MsgPredeploysPrompt        MessageID = "vm.predeploysPrompt"
+	MsgNoPredeploys            MessageID = "vm.noPredeploys"
+	MsgPredeploysFromFile      MessageID = "vm.predeploysFromFile"
+	MsgPredeploysFilePath      MessageID = "vm.predeploysFilePath"
+	MsgPredeploysAdded         MessageID = "vm.predeploysAdded"
+	MsgInitialSupply           MessageID = "vm.initialSupply"
+	MsgTargetSupplyPrompt      MessageID = "vm.targetSupplyPrompt"
+	MsgCheckTargetSupply       MessageID = "vm.checkTargetSupply"
+	MsgSkipTargetSupply        MessageID = "vm.skipTargetSupply"
+	MsgTargetSupply            MessageID = "vm.targetSupply"
+	MsgSupplyMismatch          MessageID = "vm.supplyMismatch"
+	MsgSupplyDiffers           MessageID = "vm.supplyDiffers"
+	MsgFeeFast                 MessageID = "vm.feeFast"
+	MsgFeeMedium               MessageID = "vm.feeMedium"
+	MsgFeeSlow                 MessageID = "vm.feeSlow"
+	MsgFeeCustom               MessageID = "vm.feeCustom"
+	MsgFeeHardware             MessageID = "vm.feeHardware"
+	MsgSetFees                 MessageID = "vm.setFees"
+	MsgCustomizingFees         MessageID = "vm.customizingFees"
+	MsgSetGasLimit             MessageID = "vm.setGasLimit"
+	MsgSetBlockRate            MessageID = "vm.setBlockRate"
+	MsgSetMinBaseFee           MessageID = "vm.setMinBaseFee"
+	MsgSetTargetGas            MessageID = "vm.setTargetGas"
+	MsgSetBaseFeeDenominator   MessageID = "vm.setBaseFeeDenominator"
+	MsgSetMinBlockGas          MessageID = "vm.setMinBlockGas"
+	MsgSetMaxBlockGas          MessageID = "vm.setMaxBlockGas"
+	MsgSetGasStep              MessageID = "vm.setGasStep"
+	MsgForksPrompt             MessageID = "vm.forksPrompt"
+	MsgAllForks                MessageID = "vm.allForks"
+	MsgCustomForks             MessageID = "vm.customForks"
+	MsgEnableFork              MessageID = "vm.enableFork"
+	MsgSubnetEVMForkPrompt     MessageID = "vm.subnetEVMForkPrompt"
+	MsgForkAtGenesis           MessageID = "vm.forkAtGenesis"
+	MsgForkAtDate              MessageID = "vm.forkAtDate"
+	MsgSubnetEVMForkDate       MessageID = "vm.subnetEVMForkDate"
+	MsgAddAdmin                MessageID = "vm.addAdmin"
+	MsgRemoveAdmin             MessageID = "vm.removeAdmin"
+	MsgImportAdmins            MessageID = "vm.importAdmins"
+	MsgAdminsFilePath          MessageID = "vm.adminsFilePath"
+	MsgAdminsImported          MessageID = "vm.adminsImported"
+	MsgPreview                 MessageID = "vm.preview"
+	MsgMoreInfo                MessageID = "vm.moreInfo"
+	MsgDone                    MessageID = "vm.done"
+	MsgCancel                  MessageID = "vm.cancel"
+	MsgAdminAddress            MessageID = "vm.adminAddress"
+	MsgAlreadyAdmin            MessageID = "vm.alreadyAdmin"
+	MsgChooseAddressToRemove   MessageID = "vm.chooseAddressToRemove"
+	MsgAdmins                  MessageID = "vm.admins"
+	MsgContractAllowListPrompt MessageID = "vm.contractAllowListPrompt"
+	MsgContractAllowListInfo   MessageID = "vm.contractAllowListInfo"
+	MsgTxAllowListPrompt       MessageID = "vm.txAllowListPrompt"
+	MsgTxAllowListInfo         MessageID = "vm.txAllowListInfo"
+	MsgMinterListPrompt        MessageID = "vm.minterListPrompt"
+	MsgMinterListInfo          MessageID = "vm.minterListInfo"
+	MsgNativeMint              MessageID = "vm.nativeMint"
+	MsgContractAllowList       MessageID = "vm.contractAllowList"
+	MsgTxAllowList             MessageID = "vm.txAllowList"
+	MsgAddFirstPrecompile      MessageID = "vm.addFirstPrecompile"
+	MsgAddMorePrecompiles      MessageID = "vm.addMorePrecompiles"
+	MsgChoosePrecompile        MessageID = "vm.choosePrecompile"
+	MsgFeeRecipientPrompt      MessageID = "vm.feeRecipientPrompt"
+	MsgFeeRecipientInfo        MessageID = "vm.feeRecipientInfo"
+	MsgBurnFees                MessageID = "vm.burnFees"
+	MsgRewardAddressFees       MessageID = "vm.rewardAddressFees"
+	MsgRewardAddress           MessageID = "vm.rewardAddress"
+
+	MsgPrivateChainPrompt       MessageID = "vm.privateChainPrompt"
+	MsgPrivateChainAdminsPrompt MessageID = "vm.privateChainAdminsPrompt"
+	MsgPrivateChainInfo         MessageID = "vm.privateChainInfo"
+	MsgNotTxAllowListed         MessageID = "vm.notTxAllowListed"
+
+	// pkg/subnet
+	MsgInstallingAvalanchego MessageID = "subnet.installingAvalanchego"
+	MsgAvalanchegoInstalled  MessageID = "subnet.avalanchegoInstalled"
+	MsgAlreadyDeployed       MessageID = "subnet.alreadyDeployed"
+	MsgVMsReady              MessageID = "subnet.vmsReady"
+	MsgDeployTimings         MessageID = "subnet.deployTimings"
+	MsgStartingNetwork       MessageID = "subnet.startingNetwork"
+	MsgBlockchainDeployed    MessageID = "subnet.blockchainDeployed"
+	MsgNodeReady             MessageID = "subnet.nodeReady"
+	MsgNetworkReady          MessageID = "subnet.networkReady"
+	MsgAPINodes              MessageID = "subnet.apiNodes"
+	MsgMetamaskDetails       MessageID = "subnet.metamaskDetails"
+	MsgRPCURL                MessageID = "subnet.rpcURL"
+	MsgWSURL                 MessageID = "subnet.wsURL"
+	MsgFundedEwoqAddress     MessageID = "subnet.fundedEwoqAddress"
+	MsgFundedAddress         MessageID = "subnet.fundedAddress"
+	MsgFundedKeyAddress      MessageID = "subnet.fundedKeyAddress"
+	MsgExactAmount           MessageID = "subnet.exactAmount"
+	MsgNetworkName           MessageID = "subnet.networkName"
+	MsgChainID               MessageID = "subnet.chainID"
+	MsgCurrencySymbol        MessageID = "subnet.currencySymbol"
+	MsgTxSuccessful          MessageID = "subnet.txSuccessful"
+	MsgSubnetCreated         MessageID = "subnet.subnetCreated"
+	MsgPublicEndpoint        MessageID = "subnet.publicEndpoint"
+	MsgExportAccepted        MessageID = "subnet.exportAccepted"
+	MsgSmokeTestRunning      MessageID = "subnet.smokeTestRunning"
+	MsgSmokeTestPassed       MessageID = "subnet.smokeTestPassed"
+	MsgSmokeTestFailed       MessageID = "subnet.smokeTestFailed"
+	MsgPortsCodespaces       MessageID = "subnet.portsCodespaces"
+	MsgPortsDevContainer     MessageID = "subnet.portsDevContainer"
+	MsgPortsContainer        MessageID = "subnet.portsContainer"
+	MsgDeployResuming        MessageID = "subnet.deployResuming"
+
+	// pkg/ux
+	MsgProgressETA           MessageID = "ux.progressETA"
+	MsgTakingLongerThanUsual MessageID = "ux.takingLongerThanUsual"
+	MsgStillWaiting          MessageID = "ux.stillWaiting"
+	MsgWatching              MessageID = "ux.watching"
+	MsgRefreshFailed         MessageID = "ux.refreshFailed"
+)
+
+// defaultCatalog holds the built-in, english messages. It is the fallback
+// for any message missing in a loaded translation.
+var defaultCatalog = map[MessageID]string{
+	MsgCreatingSubnet:          "creating subnet %s",
+	MsgCreatingCustomSubnet:    "creating custom VM subnet %s",
+	MsgCustomGenesisPath:       "Enter path to custom genesis",
+	MsgEnterChainID:            "Enter your subnet's ChainId. It can be any positive integer.",
+	MsgChainIDLabel:            "ChainId",
+	MsgChainIDExists:           "The provided chain ID %q already exists! Try a different one:",
+	MsgSelectTokenSymbol:       "Select a symbol for your subnet's native token",
+	MsgTokenSymbolLabel:        "Token symbol",
+	MsgGoBack:                  "Go back to previous step",
+	MsgDefaultAirdrop:          "Airdrop 1 million tokens to the default address (do not use in production)",
+	MsgCustomAirdrop:           "Customize your airdrop",
+	MsgExtendAirdrop:           "Would you like to airdrop more tokens?",
+	MsgDistributeFunds:         "How would you like to distribute funds",
+	MsgAirdropAddress:          "Address to airdrop to",
+	MsgAirdropAmount:           "Amount to airdrop (in AVAX units)",
+	MsgLockAirdrop:             "Would you like to lock this airdrop until a given date?",
+	MsgAirdropUnlockTime:       "When should the airdrop unlock? Enter a date in 'YYYY-MM-DD HH:MM:SS' format",
+	MsgPredeploysPrompt:        "Would you like to deploy contracts in the genesis?",
+	MsgNoPredeploys:            "No predeployed contracts",
+	MsgPredeploysFromFile:      "Predeploy contracts listed in a predeploys.json file",
+	MsgPredeploysFilePath:      "Path to the predeploys file",
+	MsgPredeploysAdded:         "Added %d predeployed contracts to the genesis",
+	MsgInitialSupply:           "The genesis allocates an initial supply of %s AVAX units to %d addresses",
+	MsgTargetSupplyPrompt:      "Would you like to check the initial supply against the supply you expect?",
+	MsgCheckTargetSupply:       "Check against a target supply",
+	MsgSkipTargetSupply:        "Continue without checking",
+	MsgTargetSupply:            "Target initial supply (in AVAX units)",
+	MsgSupplyMismatch:          "Error: %s. Check the target, or go back to fix the allocations",
+	MsgSupplyDiffers:           "Warning: the initial supply differs by %s AVAX units from the target of %s",
+	MsgAirdropLocked:           "The airdrop to %s is held by the lock contract at %s until %s, any call to the lock contract from then on releases it",
+	MsgUnspendableAddress:      "Address %s is %s, nobody can ever spend an airdrop to it",
+	MsgAllocateAnyway:          "Airdrop to it anyway?",
+	MsgValidatorAirdrop:        "Split an airdrop among the validator reward addresses by percentage",
+	MsgSharesFromFile:          "Load the addresses and their percentages from a file",
+	MsgSharesFromKeys:          "Pick the addresses from the stored keys",
+	MsgSharesSource:            "Where are the validator reward addresses",
+	MsgSharesFilePath:          "Path to the file of addresses and percentages",
+	MsgSharesPickKey:           "Key to allocate tokens to",
+	MsgSharesDone:              "Done",
+	MsgSharesEqualSplit:        "Split the tokens equally among the addresses?",
+	MsgSharesPercentage:        "Percentage of the tokens for %s",
+	MsgSharesTotal:             "Total amount to split (in AVAX units)",
+	MsgSharesAllocated:         "%s gets %s%% of the tokens: %s",
+	MsgMnemonicAirdrop:         "Airdrop to the first addresses derived from a mnemonic",
+	MsgMnemonic:                "Mnemonic of the wallet",
+	MsgMnemonicCount:           "How many of its addresses get an airdrop",
+	MsgMnemonicAmount:          "Amount to airdrop to each address (in AVAX units)",
+	MsgMnemonicAllocated:       "Address #%d %s gets %s tokens",
+	MsgNewKeyAirdrop:           "Airdrop to new keys, generated and stored with the other keys",
+	MsgNewKeyName:              "Name of the new key",
+	MsgNewKeyExists:            "Key %s already exists, choose another name",
+	MsgNewKeyCreated:           "Created key %s, with C-Chain address %s",
+	MsgFeeFast:                 "High disk use   / High Throughput   5 mil   gas/s",
+	MsgFeeMedium:               "Medium disk use / Medium Throughput 2 mil   gas/s",
+	MsgFeeSlow:                 "Low disk use    / Low Throughput    1.5 mil gas/s (C-Chain's setting)",
+	MsgFeeCustom:               "Customize fee config",
+	MsgFeeHardware:             "Sized for %s validators (%s hardware profile)",
+	MsgSetFees:                 "How would you like to set fees",
+	MsgCustomizingFees:         "Customizing fee config",
+	MsgSetGasLimit:             "Set gas limit",
+	MsgSetBlockRate:            "Set target block rate",
+	MsgSetMinBaseFee:           "Set min base fee",
+	MsgSetTargetGas:            "Set target gas",
+	MsgSetBaseFeeDenominator:   "Set base fee change denominator",
+	MsgSetMinBlockGas:          "Set min block gas cost",
+	MsgSetMaxBlockGas:          "Set max block gas cost",
+	MsgSetGasStep:              "Set block gas cost step",
+	MsgForksPrompt:             "Which Ethereum hard forks should be active at genesis?",
+	MsgAllForks:                "All of them (recommended)",
+	MsgCustomForks:             "Customize fork activations",
+	MsgEnableFork:              "Enable %s (%s)? Disabling it also disables the following forks",
+	MsgSubnetEVMForkPrompt:     "When should the subnet EVM fork, with dynamic fees and precompiles, activate?",
+	MsgForkAtGenesis:           "At genesis",
+	MsgForkAtDate:              "At a later date",
+	MsgSubnetEVMForkDate:       "Subnet EVM fork activation date",
+	MsgAddAdmin:                "Add admin",
+	MsgRemoveAdmin:             "Remove admin",
+	MsgImportAdmins:            "Import admins from file",
+	MsgAdminsFilePath:          "Path to a file with one address per line, or a JSON array of addresses",
+	MsgAdminsImported:          "Imported %d addresses, skipped %d duplicates",
+	MsgPreview:                 "Preview",
+	MsgMoreInfo:                "More info",
+	MsgDone:                    "Done",
+	MsgCancel:                  "Cancel",
+	MsgAdminAddress:            "Admin Address",
+	MsgAlreadyAdmin:            "Address already an admin",
+	MsgChooseAddressToRemove:   "Choose address to remove:",
+	MsgAdmins:                  "Admins:",
+	MsgContractAllowListPrompt: "Configure contract deployment allow list",
+	MsgContractAllowListInfo: "\nThis precompile restricts who has the ability to deploy contracts " +
+		"on your subnet.\nFor more information visit " +
+		"https://docs.avax.network/subnets/customize-a-subnet/#restricting-smart-contract-deployers\n\n",
+	MsgTxAllowListPrompt: "Configure transaction allow list",
+	MsgTxAllowListInfo: "\nThis precompile restricts who has the ability to issue transactions " +
+		"on your subnet.\nFor more information visit " +
+		"https://docs.avax.network/subnets/customize-a-subnet/#restricting-who-can-submit-transactions\n\n",
+	MsgMinterListPrompt: "Configure native minting allow list",
+	MsgMinterListInfo: "\nThis precompile allows admins to permit designated contracts to mint the native token " +
+		"on your subnet.\nFor more information visit " +
+		"https://docs.avax.network/subnets/customize-a-subnet#minting-native-coins\n\n",
+	MsgNativeMint:         "Native Minting",
+	MsgContractAllowList:  "Contract deployment whitelist",
+	MsgTxAllowList:        "Transaction allow list",
+	MsgAddFirstPrecompile: "Advanced: Would you like to add a custom precompile to modify the EVM?",
+	MsgAddMorePrecompiles: "Would you like to add additional precompiles?",
+	MsgChoosePrecompile:   "Choose precompile",
+	MsgFeeRecipientPrompt: "Configure where the transaction fees go",
+	MsgFeeRecipientInfo: "\nThe fees are either burned, sent to the blackhole address 0x0100000000000000000000000000000000000000, " +
+		"or the genesis allows fee recipients, and the nodes send the fees of the blocks they build to the reward " +
+		"address set in their chain config, which subnet configure --fee-recipient changes later. Nodes without a " +
+		"reward address still burn the fees.\nFor more information visit " +
+		"https://docs.avax.network/subnets/customize-a-subnet#setting-a-custom-fee-recipient\n\n",
+	MsgBurnFees:          "Burn the fees",
+	MsgRewardAddressFees: "Send the fees to a reward address",
+	MsgRewardAddress:     "Reward address",
+
+	MsgPrivateChainPrompt:       "Make this a private chain, where only allow-listed addresses can deploy contracts and issue transactions?",
+	MsgPrivateChainAdminsPrompt: "Configure the admins of the private chain",
+	MsgPrivateChainInfo: "\nA private chain enables both the contract deployment and the transaction allow lists, " +
+		"with the same admins. Only the admins, and the addresses they allow, can deploy contracts and issue " +
+		"transactions.\nFor more information visit " +
+		"https://docs.avax.network/subnets/customize-a-subnet/#restricting-who-can-submit-transactions\n\n",
+	MsgNotTxAllowListed: "Warning: the funded address %s is not on the transaction allow list, it can't issue transactions until an admin allows it",
+
+	MsgInstallingAvalanchego: "Installing avalanchego...",
+	MsgAvalanchegoInstalled:  "Avalanchego installation successful",
+	MsgAlreadyDeployed:       "Subnet %s has already been deployed",
+	MsgVMsReady:              "VMs ready.",
+	MsgDeployTimings:         "Deploy timings: %s",
+	MsgStartingNetwork:       "Starting network...",
+	MsgBlockchainDeployed:    "Blockchain has been deployed. Wait until network acknowledges...",
+	MsgNodeReady:             "\nNode %s is ready, its endpoint can be used while the other nodes catch up: %s (WebSocket: %s)",
+	MsgNetworkReady:          "Network ready to use. Local network node endpoints:",
+	MsgAPINodes:              "API nodes, tracking the subnets without validating them: %s",
+	MsgMetamaskDetails:       "Metamask connection details (any node URL from above works):",
+	MsgRPCURL:                "RPC URL:          %s",
+	MsgWSURL:                 "WS URL:           %s",
+	MsgFundedEwoqAddress:     "Funded address:   %s with %s - private key: %s",
+	MsgFundedAddress:         "Funded address:   %s with %s",
+	MsgFundedKeyAddress:      "Funded address:   %s with %s - key: %s",
+	MsgExactAmount:           " (%s wei)",
+	MsgNetworkName:           "Network name:     %s",
+	MsgChainID:               "Chain ID:         %s",
+	MsgCurrencySymbol:        "Currency Symbol:  %s",
+	MsgTxSuccessful:          "Transaction successful, transaction ID :%s",
+	MsgSubnetCreated:         "Subnet has been created with ID: %s. Now creating blockchain...",
+	MsgPublicEndpoint:        "Endpoint for blockchain %q with VM ID %q: %s/ext/bc/%s/rpc",
+	MsgExportAccepted:        "C-Chain export accepted, transaction ID: %s. Now importing on the P-Chain...",
+	MsgSmokeTestRunning:      "Running RPC smoke test...",
+	MsgSmokeTestPassed:       "✔ RPC smoke test passed on %s",
+	MsgSmokeTestFailed:       "✗ RPC smoke test failed on %s: %s",
+	MsgPortsCodespaces:       "Running in GitHub Codespaces: the node ports (%s) are forwarded to the URLs above, which are private to your GitHub account unless made public in the Ports view",
+	MsgPortsDevContainer:     "Running in a devcontainer: forward the node ports (%s), e.g. with forwardPorts in devcontainer.json, to reach the URLs above from the host",
+	MsgPortsContainer:        "Running in a container: publish the node ports (%s), e.g. with docker run -p, to reach the URLs above from the host",
+	MsgDeployResuming:        "Resuming the failed deploy of %s, skipping the completed phases: %s",
+
+	MsgProgressETA:           "%3d%% done, estimated time left: %s",
+	MsgTakingLongerThanUsual: "Taking longer than usual, still waiting...",
+	MsgStillWaiting:          "Still waiting, %s elapsed",
+	MsgWatching:              "Every %s, last refreshed at %s. Press Ctrl+C to stop.",
+	MsgRefreshFailed:         "Refresh failed: %s",
+}
+
+var (
+	catalogLock sync.RWMutex
+	catalog     = map[MessageID]string{}
+	asciiMode   bool
+
+	// typographic characters which have a sensible ASCII replacement
+	asciiReplacer = strings.NewReplacer(
+		"’", "'", "‘", "'", "“", `"`, "”", `"`, "…", "...", "–", "-", "—", "-",
+		"✔", "v", "✗", "x", "⚠", "!", "▸", ">",
+	)
+
+	errUnknownLanguage = errors.New("no message catalog found for language")
+)
+
+// Msg returns the localized message for id, falling back to the built-in
+// english message if the loaded catalog has no translation for it
+func Msg(id MessageID) string {
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+	if msg, ok := catalog[id]; ok {
+		return msg
+	}
+	if msg, ok := defaultCatalog[id]; ok {
+		return msg
+	}
+	return string(id)
+}
+
+// Msgf returns the localized message for id formatted with args
+func Msgf(id MessageID, args ...interface{}) string {
+	return fmt.Sprintf(Msg(id), args...)
+}
+
+// LanguageFromEnv derives the language from the usual locale environment
+// variables, e.g. `de_DE.UTF-8` results in `de`
+func LanguageFromEnv() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		val := os.Getenv(env)
+		if val == "" || val == "C" || val == "POSIX" {
+			continue
+		}
+		if i := strings.IndexAny(val, "_.@"); i > 0 {
+			val = val[:i]
+		}
+		return strings.ToLower(val)
+	}
+	return DefaultLanguage
+}
+
+// LoadCatalog loads the translations for lang from `<catalogDir>/<lang>.json`,
+// a flat JSON object mapping message IDs to translated messages.
+// Loading DefaultLanguage resets the catalog to the built-in messages.
+func LoadCatalog(catalogDir string, lang string) error {
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+
+	if lang == "" || lang == DefaultLanguage {
+		catalog = map[MessageID]string{}
+		return nil
+	}
+	catalogPath := filepath.Join(catalogDir, lang+".json")
+	catalogBytes, err := os.ReadFile(catalogPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w %q: expected it at %s", errUnknownLanguage, lang, catalogPath)
+		}
+		return err
+	}
+	loaded := map[MessageID]string{}
+	if err := json.Unmarshal(catalogBytes, &loaded); err != nil {
+		return fmt.Errorf("failed parsing message catalog %s: %w", catalogPath, err)
+	}
+	catalog = loaded
+	return nil
+}
+
+// SetASCIIMode enables or disables the accessible output mode, in which
+// output is restricted to ASCII characters and animations are disabled,
+// making the output friendlier for screen readers and limited terminals
+func SetASCIIMode(enabled bool) {
+	asciiMode = enabled
+}
+
+// ASCIIMode returns true if the accessible output mode is enabled
+func ASCIIMode() bool {
+	return asciiMode
+}
+
+// ToOutput applies the accessibility settings to a message
+// before it gets printed to the user
+func ToOutput(msg string) string {
+	if !asciiMode {
+		return msg
+	}
+	return ToASCII(msg)
+}
+
+// ToASCII replaces typographic characters with their ASCII equivalent
+// and drops any remaining non-ASCII character, e.g. emojis
+func ToASCII(msg string) string {
+	msg = asciiReplacer.Replace(msg)
+	var sb strings.Builder
+	for _, r := range msg {
+		if r < 128 {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
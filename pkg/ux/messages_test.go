@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	assert := assert.New(t)
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "de.json"), []byte(`{"vm.tokenSymbolLabel": "Token-Symbol"}`), 0o600)
+	assert.NoError(err)
+
+	err = LoadCatalog(tmpDir, "de")
+	assert.NoError(err)
+	assert.Equal("Token-Symbol", Msg(MsgTokenSymbolLabel))
+	// missing translations fall back to english
+	assert.Equal("ChainId", Msg(MsgChainIDLabel))
+
+	err = LoadCatalog(tmpDir, "fr")
+	assert.Error(err)
+
+	err = LoadCatalog(tmpDir, DefaultLanguage)
+	assert.NoError(err)
+	assert.Equal("Token symbol", Msg(MsgTokenSymbolLabel))
+}
+
+func TestToASCII(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("If you'd like... go!", ToASCII("If you’d like… go!🚀"))
+	assert.Equal("plain text", ToASCII("plain text"))
+}
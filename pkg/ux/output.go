@@ -5,9 +5,13 @@ package ux
 import (
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/devenv"
 	"github.com/ava-labs/avalanche-network-runner/rpcpb"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/olekukonko/tablewriter"
@@ -15,6 +19,13 @@ import (
 
 var Logger *UserLog
 
+// progressLineInterval is how often progress is reported in line mode
+var progressLineInterval = 10 * time.Second
+
+// lineMode reports progress with a full line from time to time instead of
+// animations, see SetLineMode
+var lineMode bool
+
 type UserLog struct {
 	log    logging.Logger
 	writer io.Writer
@@ -31,12 +42,46 @@ func NewUserLog(log logging.Logger, userwriter io.Writer) {
 
 // PrintToUser prints msg directly on the screen, but also to log file
 func (ul *UserLog) PrintToUser(msg string, args ...interface{}) {
-	fmt.Fprintln(ul.writer, fmt.Sprintf(msg, args...))
+	fmt.Fprintln(ul.writer, ToOutput(fmt.Sprintf(msg, args...)))
 	ul.log.Info(msg, args...)
 }
 
-// PrintWait does some dot printing to entertain the user
+// SetLineMode enables or disables the line mode, in which long operations
+// report their progress with a full line every now and then instead of
+// animations, so that they show up in the logs of CI runs and other non
+// interactive outputs
+func SetLineMode(enabled bool) {
+	lineMode = enabled
+}
+
+// printProgressLines prints the line returned by progress for the elapsed
+// time every progressLineInterval, until cancel is closed
+func printProgressLines(cancel chan struct{}, progress func(elapsed time.Duration) string) {
+	start := time.Now()
+	for {
+		select {
+		case <-time.After(progressLineInterval):
+			Logger.PrintToUser("%s", progress(time.Since(start)))
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// PrintWait does some dot printing to entertain the user.
+// In ASCII mode nothing is printed, as screen readers would read every dot.
+// In line mode, the time elapsed is printed from time to time.
 func PrintWait(cancel chan struct{}) {
+	if lineMode {
+		printProgressLines(cancel, func(elapsed time.Duration) string {
+			return Msgf(MsgStillWaiting, strings.TrimSpace(FormatDuration(elapsed)))
+		})
+		return
+	}
+	if asciiMode {
+		<-cancel
+		return
+	}
 	for {
 		select {
 		case <-time.After(1 * time.Second):
@@ -47,17 +92,129 @@ func PrintWait(cancel chan struct{}) {
 	}
 }
 
+// PrintWaitWithETA prints the progress of an operation which is expected to
+// take eta, based on how long it took in previous runs.
+// In ASCII mode nothing is printed, as screen readers would read every update.
+// In line mode, the progress is printed from time to time.
+func PrintWaitWithETA(cancel chan struct{}, eta time.Duration) {
+	if lineMode {
+		printProgressLines(cancel, func(elapsed time.Duration) string {
+			if elapsed >= eta {
+				return Msg(MsgTakingLongerThanUsual)
+			}
+			remaining := (eta - elapsed).Round(time.Second)
+			return Msgf(MsgProgressETA, int(elapsed*100/eta), strings.TrimSpace(FormatDuration(remaining)))
+		})
+		return
+	}
+	if asciiMode {
+		<-cancel
+		return
+	}
+	start := time.Now()
+	for {
+		select {
+		case <-time.After(1 * time.Second):
+			elapsed := time.Since(start)
+			if elapsed >= eta {
+				fmt.Printf("\r%s", Msg(MsgTakingLongerThanUsual))
+				continue
+			}
+			percentage := int(elapsed * 100 / eta)
+			remaining := (eta - elapsed).Round(time.Second)
+			fmt.Printf("\r"+Msg(MsgProgressETA)+"   ", percentage, FormatDuration(remaining))
+		case <-cancel:
+			fmt.Println()
+			return
+		}
+	}
+}
+
+// prefixColors are the ANSI colors Colorize cycles through
+var prefixColors = []string{"36", "33", "35", "32", "34", "31"}
+
+// Colorize returns s in the i-th of a set of colors, for the lines of
+// different sources to be told apart, or as is in line and ASCII modes
+func Colorize(i int, s string) string {
+	if lineMode || asciiMode {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", prefixColors[i%len(prefixColors)], s)
+}
+
+// RPCEndpoint returns the HTTP RPC endpoint of blockchainID at the node
+// with the given URI
+func RPCEndpoint(nodeURI string, blockchainID string) string {
+	return fmt.Sprintf("%s/ext/bc/%s/rpc", nodeURI, blockchainID)
+}
+
+// WSEndpoint returns the WebSocket endpoint of blockchainID at the node
+// with the given URI
+func WSEndpoint(nodeURI string, blockchainID string) string {
+	// http -> ws, https -> wss
+	if strings.HasPrefix(nodeURI, "http") {
+		nodeURI = "ws" + strings.TrimPrefix(nodeURI, "http")
+	}
+	return fmt.Sprintf("%s/ext/bc/%s/ws", nodeURI, blockchainID)
+}
+
 // PrintTableEndpoints prints the endpoints coming from the healthy call
 func PrintTableEndpoints(clusterInfo *rpcpb.ClusterInfo) {
 	table := tablewriter.NewWriter(os.Stdout)
-	header := []string{"node", "VM", "URL"}
+	header := []string{"node", "VM", "RPC URL", "WS URL"}
 	table.SetHeader(header)
 	table.SetRowLine(true)
 
 	for _, nodeInfo := range clusterInfo.NodeInfos {
 		for blockchainID, vmInfo := range clusterInfo.CustomVms {
-			table.Append([]string{nodeInfo.Name, vmInfo.VmName, fmt.Sprintf("%s/ext/bc/%s/rpc", nodeInfo.GetUri(), blockchainID)})
+			table.Append([]string{
+				nodeInfo.Name,
+				vmInfo.VmName,
+				// both by blockchain ID and by the VM name the blockchain is
+				// aliased with by subnet.AliasChains
+				RPCEndpoint(HostURI(nodeInfo.GetUri()), blockchainID) + "\n" + RPCEndpoint(HostURI(nodeInfo.GetUri()), vmInfo.VmName),
+				WSEndpoint(HostURI(nodeInfo.GetUri()), blockchainID) + "\n" + WSEndpoint(HostURI(nodeInfo.GetUri()), vmInfo.VmName),
+			})
 		}
 	}
 	table.Render()
+	PrintPortsHint(clusterInfo)
+}
+
+// HostURI returns the URI the host reaches nodeURI at, which differs from
+// nodeURI when running in a container, such as a codespace
+func HostURI(nodeURI string) string {
+	return devenv.Detect().HostURL(nodeURI)
+}
+
+// PrintPortsHint tells how to reach the nodes of clusterInfo from the host
+// when running in a container
+func PrintPortsHint(clusterInfo *rpcpb.ClusterInfo) {
+	var msg MessageID
+	switch devenv.Detect().Kind {
+	case devenv.Codespaces:
+		msg = MsgPortsCodespaces
+	case devenv.DevContainer:
+		msg = MsgPortsDevContainer
+	case devenv.Container:
+		msg = MsgPortsContainer
+	default:
+		return
+	}
+	ports := []string{}
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		if u, err := url.Parse(nodeInfo.GetUri()); err == nil && u.Port() != "" {
+			ports = append(ports, u.Port())
+		}
+	}
+	if len(ports) == 0 {
+		return
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if len(ports[i]) != len(ports[j]) {
+			return len(ports[i]) < len(ports[j])
+		}
+		return ports[i] < ports[j]
+	})
+	Logger.PrintToUser(Msg(msg), strings.Join(ports, ", "))
 }
@@ -0,0 +1,75 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintWaitLineMode(t *testing.T) {
+	assert := assert.New(t)
+
+	var out bytes.Buffer
+	defer func(l *UserLog, interval time.Duration) {
+		Logger = l
+		progressLineInterval = interval
+		SetLineMode(false)
+	}(Logger, progressLineInterval)
+	Logger = &UserLog{log: logging.NoLog{}, writer: &out}
+	progressLineInterval = 10 * time.Millisecond
+	SetLineMode(true)
+
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		PrintWaitWithETA(cancel, time.Hour)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(cancel)
+	<-done
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.NotEmpty(lines)
+	for _, line := range lines {
+		// one complete line per update, without carriage returns
+		assert.NotContains(line, "\r")
+		assert.Contains(line, "0% done, estimated time left: ")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	assert := assert.New(t)
+
+	var out bytes.Buffer
+	defer func(l *UserLog) {
+		Logger = l
+		SetLineMode(false)
+	}(Logger)
+	Logger = &UserLog{log: logging.NoLog{}, writer: &out}
+	SetLineMode(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	refreshes := 0
+	err := Watch(ctx, time.Millisecond, func() error {
+		refreshes++
+		if refreshes == 3 {
+			cancel()
+			return errors.New("unreachable")
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(3, refreshes)
+	assert.Equal(3, strings.Count(out.String(), "Press Ctrl+C to stop"))
+	// a failed refresh doesn't stop watching
+	assert.Contains(out.String(), "Refresh failed: unreachable")
+}
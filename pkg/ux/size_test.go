@@ -0,0 +1,26 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSize(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[int64]string{
+		0:                  "0 B",
+		1023:               "1023 B",
+		1024:               "1.0 KiB",
+		1536:               "1.5 KiB",
+		5 * 1024 * 1024:    "5.0 MiB",
+		3 << 30:            "3.0 GiB",
+		1<<40 + 1<<39 + 12: "1.5 TiB",
+	}
+	for bytes, expected := range tests {
+		assert.Equal(expected, FormatSize(bytes))
+	}
+}
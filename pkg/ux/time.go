@@ -0,0 +1,93 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// timeDisplayLayout shows the timezone of the times, which the P-Chain
+// handles as unix times
+const timeDisplayLayout = "2006-01-02 15:04:05 MST"
+
+var (
+	relativeTimeRegex = regexp.MustCompile(`^in\s+(.+)$`)
+	timeAmountRegex   = regexp.MustCompile(`(\d+)\s*([a-z]+)`)
+	timeUnits         = map[string]time.Duration{
+		"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+		"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+		"h": time.Hour, "hour": time.Hour, "hours": time.Hour,
+		"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	}
+)
+
+// ParseTime parses a time entered by the user, either relative to now, as in
+// "in 10 minutes" or "in 1h30m", in RFC3339 format, e.g.
+// 2022-10-15T14:00:00+02:00, or in constants.TimeParseLayout in the local
+// timezone
+func ParseTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if match := relativeTimeRegex.FindStringSubmatch(strings.ToLower(input)); match != nil {
+		d, err := parseRelativeDuration(match[1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(constants.TimeParseLayout, input, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: must be relative to now, e.g. 'in 10 minutes', in RFC3339 format, or in 'YYYY-MM-DD HH:MM:SS' format in the local timezone (%s)",
+			input, now.Local().Format("MST"))
+	}
+	return t, nil
+}
+
+// parseRelativeDuration parses a duration as in "10 minutes", "1 day 2 hours"
+// or "1h30m"
+func parseRelativeDuration(input string) (time.Duration, error) {
+	if d, err := time.ParseDuration(strings.ReplaceAll(input, " ", "")); err == nil {
+		return d, nil
+	}
+	matches := timeAmountRegex.FindAllStringSubmatch(input, -1)
+	rest := strings.TrimSpace(timeAmountRegex.ReplaceAllString(input, ""))
+	if len(matches) == 0 || strings.Trim(rest, ", and") != "" {
+		return 0, fmt.Errorf("invalid relative time 'in %s', e.g. 'in 10 minutes' or 'in 1h30m'", input)
+	}
+	var d time.Duration
+	for _, match := range matches {
+		unit, ok := timeUnits[match[2]]
+		if !ok {
+			return 0, fmt.Errorf("invalid time unit %q in 'in %s'", match[2], input)
+		}
+		amount, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(amount) * unit
+	}
+	return d, nil
+}
+
+// FormatTime returns t in the local timezone, with the timezone and how far
+// it is from now
+func FormatTime(t time.Time, now time.Time) string {
+	formatted := t.Local().Format(timeDisplayLayout)
+	d := t.Sub(now).Round(time.Second)
+	switch {
+	case d > 0:
+		return fmt.Sprintf("%s (in %s)", formatted, strings.TrimSpace(FormatDuration(d)))
+	case d < 0:
+		return fmt.Sprintf("%s (%s ago)", formatted, strings.TrimSpace(FormatDuration(-d)))
+	}
+	return formatted + " (now)"
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTime(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+	for input, expected := range map[string]time.Duration{
+		"in 10 minutes":       10 * time.Minute,
+		"In 1h30m":            90 * time.Minute,
+		"in 1 day, 2 hours":   26 * time.Hour,
+		"in 2 weeks":          14 * 24 * time.Hour,
+		"in 1 hour and 5 min": 65 * time.Minute,
+	} {
+		parsed, err := ParseTime(input, now)
+		assert.NoError(err, input)
+		assert.Equal(now.Add(expected), parsed, input)
+	}
+
+	parsed, err := ParseTime("2022-10-15T14:00:00+02:00", now)
+	assert.NoError(err)
+	assert.True(now.Equal(parsed))
+
+	// times without timezone are in the local one
+	parsed, err = ParseTime("2022-10-15 14:00:00", now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2022, 10, 15, 14, 0, 0, 0, time.Local), parsed)
+
+	_, err = ParseTime("in 10 fortnights", now)
+	assert.ErrorContains(err, `invalid time unit "fortnights"`)
+	_, err = ParseTime("in a while", now)
+	assert.ErrorContains(err, "invalid relative time")
+	_, err = ParseTime("tomorrow", now)
+	assert.ErrorContains(err, "in the local timezone")
+}
+
+func TestFormatTime(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+	local := now.Local().Format("2006-01-02 15:04:05 MST")
+	assert.Equal(local+" (now)", FormatTime(now, now))
+	assert.Contains(FormatTime(now, now.Add(-90*time.Minute)), "(in 1 hours 30 minutes)")
+	assert.Contains(FormatTime(now, now.Add(2*24*time.Hour)), "(2 days ago)")
+}
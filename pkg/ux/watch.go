@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// DefaultWatchInterval is the refresh interval of --watch without a value
+	DefaultWatchInterval = 2 * time.Second
+
+	// clearScreen moves the cursor home and clears the terminal
+	clearScreen = "\033[H\033[2J"
+)
+
+// AddWatchFlag adds the --watch flag of the commands which can refresh their
+// output until interrupted, setting interval. Given without a value, the
+// interval is DefaultWatchInterval, e.g. `--watch` or `--watch=10s`.
+func AddWatchFlag(flags *pflag.FlagSet, interval *time.Duration) {
+	flags.DurationVar(interval, "watch", 0, fmt.Sprintf("refresh the output every interval until interrupted, e.g. --watch=10s (default interval %s)", DefaultWatchInterval))
+	flags.Lookup("watch").NoOptDefVal = DefaultWatchInterval.String()
+}
+
+// Watch calls refresh every interval until ctx is done, each output
+// replacing the previous one on the screen. In line mode, the outputs follow
+// each other instead. A failed refresh is reported and tried again at the
+// next interval.
+func Watch(ctx context.Context, interval time.Duration, refresh func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		if lineMode {
+			fmt.Println()
+		} else {
+			fmt.Print(clearScreen)
+		}
+		Logger.PrintToUser(Msg(MsgWatching), interval, time.Now().Format("15:04:05"))
+		Logger.PrintToUser("")
+		if err := refresh(); err != nil {
+			Logger.PrintToUser(Msg(MsgRefreshFailed), err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LoadAddressList reads a list of addresses from a file. The file either
+// holds a JSON array of addresses, or one address per line, where empty
+// lines and lines starting with # are ignored.
+// Every address is validated, and duplicates are removed.
+func LoadAddressList(path string) ([]common.Address, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading address list %s: %w", path, err)
+	}
+
+	var entries []string
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("failed parsing address list %s as JSON array: %w", path, err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			entries = append(entries, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed reading address list %s: %w", path, err)
+		}
+	}
+
+	addresses := []common.Address{}
+	for i, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if !common.IsHexAddress(entry) {
+			return nil, fmt.Errorf("invalid address %q in entry %d of %s", entry, i+1, path)
+		}
+		addr := common.HexToAddress(entry)
+		if !contains(addresses, addr) {
+			addresses = append(addresses, addr)
+		}
+	}
+	return addresses, nil
+}
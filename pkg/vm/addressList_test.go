@@ -0,0 +1,69 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAddressList(t *testing.T) {
+	addr1 := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	addr2 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	type test struct {
+		name        string
+		content     string
+		expected    []common.Address
+		expectedErr bool
+	}
+	tests := []test{
+		{
+			name:     "lines",
+			content:  "# admins\n0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC\n\n  0x1111111111111111111111111111111111111111  \n",
+			expected: []common.Address{addr1, addr2},
+		},
+		{
+			name:     "json",
+			content:  `["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", "0x1111111111111111111111111111111111111111"]`,
+			expected: []common.Address{addr1, addr2},
+		},
+		{
+			name:     "duplicates in different case",
+			content:  "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC\n0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc\n",
+			expected: []common.Address{addr1},
+		},
+		{
+			name:        "invalid address",
+			content:     "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC\nnot-an-address\n",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid json",
+			content:     `["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			path := filepath.Join(t.TempDir(), "addresses")
+			err := os.WriteFile(path, []byte(tt.content), 0o600)
+			assert.NoError(err)
+
+			addresses, err := LoadAddressList(path)
+			if tt.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.expected, addresses)
+		})
+	}
+}
@@ -4,14 +4,28 @@
 package vm
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
 )
 
-func getDefaultAllocation() (core.GenesisAlloc, error) {
+// DefaultAllocation funds the prefunded ewoq address of the local networks
+// with 1 million tokens, as the default airdrop of the wizard
+func DefaultAllocation() (core.GenesisAlloc, error) {
 	allocation := core.GenesisAlloc{}
 	defaultAmount, ok := new(big.Int).SetString(defaultAirdropAmount, 10)
 	if !ok {
@@ -27,20 +41,24 @@ func getDefaultAllocation() (core.GenesisAlloc, error) {
 func getAllocation(app *application.Avalanche) (core.GenesisAlloc, stateDirection, error) {
 	allocation := core.GenesisAlloc{}
 
-	defaultAirdrop := "Airdrop 1 million tokens to the default address (do not use in production)"
-	customAirdrop := "Customize your airdrop"
-	extendAirdrop := "Would you like to airdrop more tokens?"
+	defaultAirdrop := ux.Msg(ux.MsgDefaultAirdrop)
+	customAirdrop := ux.Msg(ux.MsgCustomAirdrop)
+	validatorAirdrop := ux.Msg(ux.MsgValidatorAirdrop)
+	mnemonicAirdrop := ux.Msg(ux.MsgMnemonicAirdrop)
+	newKeyAirdrop := ux.Msg(ux.MsgNewKeyAirdrop)
+	extendAirdrop := ux.Msg(ux.MsgExtendAirdrop)
+	goBackMsg := ux.Msg(ux.MsgGoBack)
 
 	airdropType, err := app.Prompt.CaptureList(
-		"How would you like to distribute funds",
-		[]string{defaultAirdrop, customAirdrop, goBackMsg},
+		ux.Msg(ux.MsgDistributeFunds),
+		[]string{defaultAirdrop, customAirdrop, validatorAirdrop, mnemonicAirdrop, newKeyAirdrop, goBackMsg},
 	)
 	if err != nil {
 		return allocation, stop, err
 	}
 
 	if airdropType == defaultAirdrop {
-		alloc, err := getDefaultAllocation()
+		alloc, err := DefaultAllocation()
 		return alloc, forward, err
 	}
 
@@ -48,24 +66,73 @@ func getAllocation(app *application.Avalanche) (core.GenesisAlloc, stateDirectio
 		return allocation, backward, nil
 	}
 
+	if airdropType == validatorAirdrop {
+		alloc, err := getValidatorAllocation(app)
+		if err != nil {
+			return nil, stop, err
+		}
+		return alloc, forward, nil
+	}
+
+	if airdropType == mnemonicAirdrop {
+		alloc, err := getMnemonicAllocation(app)
+		if err != nil {
+			return nil, stop, err
+		}
+		return alloc, forward, nil
+	}
+
+	if airdropType == newKeyAirdrop {
+		alloc, err := getNewKeysAllocation(app)
+		if err != nil {
+			return nil, stop, err
+		}
+		return alloc, forward, nil
+	}
+
 	for {
-		addressHex, err := app.Prompt.CaptureAddress("Address to airdrop to")
+		addressHex, err := app.Prompt.CaptureAddress(ux.Msg(ux.MsgAirdropAddress))
 		if err != nil {
 			return nil, stop, err
 		}
+		if name, ok := unspendableAddress(addressHex); ok {
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgUnspendableAddress), addressHex.Hex(), name)
+			allocate, err := app.Prompt.CaptureNoYes(ux.Msg(ux.MsgAllocateAnyway), prompts.WithDefault(prompts.No))
+			if err != nil {
+				return nil, stop, err
+			}
+			if !allocate {
+				continue
+			}
+		}
 
-		amount, err := app.Prompt.CapturePositiveBigInt("Amount to airdrop (in AVAX units)")
+		amount, err := app.Prompt.CapturePositiveBigInt(ux.Msg(ux.MsgAirdropAmount))
 		if err != nil {
 			return nil, stop, err
 		}
 
 		amount = amount.Mul(amount, oneAvax)
 
-		account := core.GenesisAccount{
-			Balance: amount,
+		lock, err := app.Prompt.CaptureNoYes(ux.Msg(ux.MsgLockAirdrop))
+		if err != nil {
+			return nil, stop, err
 		}
+		if lock {
+			unlockTime, err := app.Prompt.CaptureDate(ux.Msg(ux.MsgAirdropUnlockTime))
+			if err != nil {
+				return nil, stop, err
+			}
+			lockAddress, account := NewTimelockAccount(addressHex, amount, unlockTime)
+			allocation[lockAddress] = account
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgAirdropLocked),
+				addressHex.Hex(), lockAddress.Hex(), unlockTime.Format(constants.TimeParseLayout))
+		} else {
+			account := core.GenesisAccount{
+				Balance: amount,
+			}
 
-		allocation[addressHex] = account
+			allocation[addressHex] = account
+		}
 
 		continueAirdrop, err := app.Prompt.CaptureNoYes(extendAirdrop)
 		if err != nil {
@@ -76,3 +143,259 @@ func getAllocation(app *application.Avalanche) (core.GenesisAlloc, stateDirectio
 		}
 	}
 }
+
+// getValidatorAllocation splits an airdrop by percentage among the validator
+// reward addresses, listed in a file or picked from the stored keys
+func getValidatorAllocation(app *application.Avalanche) (core.GenesisAlloc, error) {
+	fromFile := ux.Msg(ux.MsgSharesFromFile)
+	source, err := app.Prompt.CaptureList(
+		ux.Msg(ux.MsgSharesSource),
+		[]string{fromFile, ux.Msg(ux.MsgSharesFromKeys)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []AllocationShare
+	if source == fromFile {
+		path, err := app.Prompt.CaptureExistingFilepath(ux.Msg(ux.MsgSharesFilePath))
+		if err != nil {
+			return nil, err
+		}
+		if shares, err = LoadAllocationShares(path); err != nil {
+			return nil, err
+		}
+	} else {
+		addrs, err := pickKeyAddresses(app)
+		if err != nil {
+			return nil, err
+		}
+		if shares, err = captureShares(app, addrs); err != nil {
+			return nil, err
+		}
+	}
+
+	total, err := app.Prompt.CapturePositiveBigInt(ux.Msg(ux.MsgSharesTotal))
+	if err != nil {
+		return nil, err
+	}
+	total = total.Mul(total, oneAvax)
+
+	allocation, err := SplitAllocation(total, shares)
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range shares {
+		amount := new(big.Rat).SetFrac(allocation[share.Address].Balance, oneAvax)
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgSharesAllocated),
+			share.Address.Hex(), formatBasisPoints(share.BasisPoints), amount.FloatString(9))
+	}
+	return allocation, nil
+}
+
+// getMnemonicAllocation airdrops the same amount to each of the first
+// addresses derived from a mnemonic, as test wallets are usually set up
+func getMnemonicAllocation(app *application.Avalanche) (core.GenesisAlloc, error) {
+	var addrs []common.Address
+	for {
+		mnemonic, err := app.Prompt.CapturePassword(ux.Msg(ux.MsgMnemonic))
+		if err != nil {
+			return nil, err
+		}
+		count, err := app.Prompt.CaptureUint64(ux.Msg(ux.MsgMnemonicCount))
+		if err != nil {
+			return nil, err
+		}
+		if addrs, err = DeriveMnemonicAddresses(mnemonic, count); err != nil {
+			ux.Logger.PrintToUser(err.Error())
+			continue
+		}
+		break
+	}
+
+	amount, err := app.Prompt.CapturePositiveBigInt(ux.Msg(ux.MsgMnemonicAmount))
+	if err != nil {
+		return nil, err
+	}
+	displayAmount := amount.String()
+	amount = amount.Mul(amount, oneAvax)
+
+	allocation := core.GenesisAlloc{}
+	for i, addr := range addrs {
+		allocation[addr] = core.GenesisAccount{
+			Balance: new(big.Int).Set(amount),
+		}
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgMnemonicAllocated), i, addr.Hex(), displayAmount)
+	}
+	return allocation, nil
+}
+
+// getNewKeysAllocation airdrops to keys it generates, stored with the other
+// keys, so that the funded accounts can be used right away
+func getNewKeysAllocation(app *application.Avalanche) (core.GenesisAlloc, error) {
+	if err := os.MkdirAll(app.GetKeyDir(), constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	allocation := core.GenesisAlloc{}
+	for {
+		keyName, err := app.Prompt.CaptureString(ux.Msg(ux.MsgNewKeyName))
+		if err != nil {
+			return nil, err
+		}
+		if app.KeyExists(keyName) {
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgNewKeyExists), keyName)
+			continue
+		}
+		k, err := key.NewSoft(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := k.Save(app.GetKeyPath(keyName)); err != nil {
+			return nil, err
+		}
+		address := common.HexToAddress(k.C())
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgNewKeyCreated), keyName, address.Hex())
+
+		amount, err := app.Prompt.CapturePositiveBigInt(ux.Msg(ux.MsgAirdropAmount))
+		if err != nil {
+			return nil, err
+		}
+		allocation[address] = core.GenesisAccount{
+			Balance: amount.Mul(amount, oneAvax),
+		}
+
+		continueAirdrop, err := app.Prompt.CaptureNoYes(ux.Msg(ux.MsgExtendAirdrop))
+		if err != nil {
+			return nil, err
+		}
+		if !continueAirdrop {
+			return allocation, nil
+		}
+	}
+}
+
+// pickKeyAddresses lets the user pick, one after the other, the stored keys
+// whose C-Chain addresses get the tokens
+func pickKeyAddresses(app *application.Avalanche) ([]common.Address, error) {
+	keyAddrs, err := storedKeyAddresses(app)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyAddrs) == 0 {
+		return nil, fmt.Errorf("no stored keys in %s, create or import some with `avalanche key`", app.GetKeyDir())
+	}
+	names := make([]string, 0, len(keyAddrs))
+	for name := range keyAddrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	done := ux.Msg(ux.MsgSharesDone)
+	addrs := []common.Address{}
+	for len(names) > 0 {
+		options := names
+		if len(addrs) > 0 {
+			options = append(append([]string{}, names...), done)
+		}
+		picked, err := app.Prompt.CaptureList(ux.Msg(ux.MsgSharesPickKey), options)
+		if err != nil {
+			return nil, err
+		}
+		if picked == done {
+			break
+		}
+		addrs = append(addrs, keyAddrs[picked])
+		for i, name := range names {
+			if name == picked {
+				names = append(names[:i], names[i+1:]...)
+				break
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// storedKeyAddresses returns the C-Chain addresses of the stored keys, by key
+// name
+func storedKeyAddresses(app *application.Avalanche) (map[string]common.Address, error) {
+	files, err := os.ReadDir(app.GetKeyDir())
+	if err != nil {
+		return nil, err
+	}
+	addrs := map[string]common.Address{}
+	for _, f := range files {
+		keyPath := filepath.Join(app.GetKeyDir(), f.Name())
+		var cAddr string
+		switch {
+		case strings.HasSuffix(f.Name(), constants.RemoteKeySuffix):
+			k, err := key.LoadRemote(avago_constants.FujiID, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			cAddr = k.C()
+		case strings.HasSuffix(f.Name(), constants.KeySuffix):
+			k, err := key.LoadSoftAddresses(avago_constants.FujiID, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			cAddr = k.C()
+		default:
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(f.Name(), constants.KeySuffix), constants.RemoteKeySuffix)
+		addrs[name] = common.HexToAddress(cAddr)
+	}
+	return addrs, nil
+}
+
+// FundedKeys returns the stored keys funded by the genesis, by the hex of
+// their C-Chain address
+func FundedKeys(app *application.Avalanche, genesisBytes []byte) (map[string]string, error) {
+	var genesis core.Genesis
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, fmt.Errorf("failed reading the genesis: %w", err)
+	}
+	keyAddrs, err := storedKeyAddresses(app)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	funded := map[string]string{}
+	for name, addr := range keyAddrs {
+		if _, ok := genesis.Alloc[addr]; ok {
+			funded[addr.Hex()] = name
+		}
+	}
+	return funded, nil
+}
+
+// captureShares asks for the percentage of each address, unless the tokens
+// are split equally
+func captureShares(app *application.Avalanche, addrs []common.Address) ([]AllocationShare, error) {
+	equal, err := app.Prompt.CaptureYesNo(ux.Msg(ux.MsgSharesEqualSplit))
+	if err != nil {
+		return nil, err
+	}
+	if equal {
+		return EqualShares(addrs), nil
+	}
+	shares := make([]AllocationShare, len(addrs))
+	for i, addr := range addrs {
+		for {
+			percentage, err := app.Prompt.CaptureString(ux.Msgf(ux.MsgSharesPercentage, addr.Hex()))
+			if err != nil {
+				return nil, err
+			}
+			basisPoints, err := ParsePercentage(percentage)
+			if err != nil {
+				ux.Logger.PrintToUser(err.Error())
+				continue
+			}
+			shares[i] = AllocationShare{Address: addr, BasisPoints: basisPoints}
+			break
+		}
+	}
+	return shares, nil
+}
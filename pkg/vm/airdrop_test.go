@@ -0,0 +1,41 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFundedKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+	genesis := fmt.Sprintf(`{"config": {}, "gasLimit": "0x7a1200", "difficulty": "0x0", "alloc": {"%s": {"balance": "0x1"}, "%s": {"balance": "0x1"}}}`,
+		PrefundedEwoqAddress.Hex(), "0x1111111111111111111111111111111111111111")
+
+	// no stored keys
+	funded, err := FundedKeys(app, []byte(genesis))
+	assert.NoError(err)
+	assert.Empty(funded)
+
+	assert.NoError(os.MkdirAll(app.GetKeyDir(), constants.DefaultPerms755))
+	assert.NoError(os.WriteFile(app.GetKeyPath("deployer"), []byte(PrefundedEwoqPrivate), 0o600))
+	other, err := key.NewSoft(0)
+	assert.NoError(err)
+	assert.NoError(other.Save(app.GetKeyPath("other")))
+
+	funded, err = FundedKeys(app, []byte(genesis))
+	assert.NoError(err)
+	assert.Equal(map[string]string{PrefundedEwoqAddress.Hex(): "deployer"}, funded)
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/constants"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrAddressChecksum is returned for a mixed case address not matching its
+// EIP-55 checksum, most likely mistyped
+var ErrAddressChecksum = errors.New("address does not match its checksum")
+
+// burnAddresses hold funds nobody can ever spend
+var burnAddresses = map[common.Address]string{
+	{}:                      "the zero address",
+	constants.BlackholeAddr: "the blackhole address, where the fees are burnt",
+	common.HexToAddress("0x000000000000000000000000000000000000dEaD"): "the dead address",
+}
+
+// precompileAddresses are the addresses of the stateful precompiles of
+// subnet EVM, which have no key
+var precompileAddresses = map[common.Address]string{
+	precompile.ContractDeployerAllowListAddress: "the contract deployer allow list precompile",
+	precompile.ContractNativeMinterAddress:      "the native minter precompile",
+	precompile.TxAllowListAddress:               "the transaction allow list precompile",
+}
+
+// unspendableAddress returns a description of addr if an allocation to it
+// can never be spent
+func unspendableAddress(addr common.Address) (string, bool) {
+	if name, ok := burnAddresses[addr]; ok {
+		return name, true
+	}
+	if name, ok := precompileAddresses[addr]; ok {
+		return name, true
+	}
+	// the lowest addresses are reserved by the EVM for its precompiles, and
+	// the ones starting with 0x02 or 0x03 by subnet EVM and its forks
+	reserved := true
+	for _, b := range addr[1 : common.AddressLength-1] {
+		if b != 0 {
+			reserved = false
+			break
+		}
+	}
+	if reserved && (addr[0] == 0 || addr[0] == 2 || addr[0] == 3) {
+		return "an address reserved for precompiles", true
+	}
+	return "", false
+}
+
+// CheckAddressChecksum checks that a mixed case address matches its
+// checksum. Addresses all in lower or upper case have no checksum.
+func CheckAddressChecksum(address string) error {
+	hex := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return nil
+	}
+	if expected := common.HexToAddress(hex).Hex(); expected[2:] != hex {
+		return fmt.Errorf("%w %s: %s", ErrAddressChecksum, expected, address)
+	}
+	return nil
+}
+
+// lintAllocations checks the allocations of a genesis for funds that can
+// never be spent
+func lintAllocations(alloc core.GenesisAlloc, target models.Network) []LintFinding {
+	findings := []LintFinding{}
+	for addr, account := range alloc {
+		if account.Balance == nil || account.Balance.Sign() == 0 {
+			continue
+		}
+		if name, ok := unspendableAddress(addr); ok {
+			findings = append(findings, LintFinding{
+				Severity: escalate(target),
+				Rule:     "unspendable-allocation",
+				Message:  fmt.Sprintf("address %s, %s, has an allocation nobody can spend", addr.Hex(), name),
+			})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Message < findings[j].Message })
+	return findings
+}
+
+// LintAllocationChecksums checks the addresses of the allocations of a
+// genesis, as written in it, against their checksum. Once parsed the case of
+// the addresses, and so their checksum, is lost.
+func LintAllocationChecksums(genesis []byte, target models.Network) ([]LintFinding, error) {
+	var raw struct {
+		Alloc map[string]json.RawMessage `json:"alloc"`
+	}
+	if err := json.Unmarshal(genesis, &raw); err != nil {
+		return nil, fmt.Errorf("failed parsing genesis: %w", err)
+	}
+	addresses := make([]string, 0, len(raw.Alloc))
+	for address := range raw.Alloc {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	findings := []LintFinding{}
+	for _, address := range addresses {
+		if err := CheckAddressChecksum(address); err != nil {
+			findings = append(findings, LintFinding{
+				Severity: escalate(target),
+				Rule:     "address-checksum",
+				Message:  fmt.Sprintf("allocation to %s, which does not match its checksum and is most likely mistyped", address),
+			})
+		}
+	}
+	return findings, nil
+}
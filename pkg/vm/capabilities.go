@@ -0,0 +1,131 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/coreos/go-semver/semver"
+)
+
+// subnetEVMCapability is a feature of the chain config, supported by the
+// subnet-evm versions from version on. Older versions ignore the fields
+// they don't know, so the chain would run without the feature.
+type subnetEVMCapability struct {
+	feature     string
+	description string
+	version     string
+	// used returns whether the chain config or the sidecar use the feature
+	used func(config map[string]interface{}, sc models.Sidecar) bool
+}
+
+// subnetEVMCapabilities are the features of the chain config, in the order
+// they were introduced
+var subnetEVMCapabilities = []subnetEVMCapability{
+	{
+		feature:     "contractDeployerAllowListConfig",
+		description: "the contract deployer allow list precompile",
+		version:     "v0.1.2",
+		used:        configField("contractDeployerAllowListConfig"),
+	},
+	{
+		feature:     "contractNativeMinterConfig",
+		description: "the native minter precompile",
+		version:     "v0.2.0",
+		used:        configField("contractNativeMinterConfig"),
+	},
+	{
+		feature:     "txAllowListConfig",
+		description: "the transaction allow list precompile",
+		version:     "v0.2.0",
+		used:        configField("txAllowListConfig"),
+	},
+	{
+		feature:     "allowFeeRecipients",
+		description: "the fees paid to the block producers",
+		version:     "v0.2.0",
+		used: func(config map[string]interface{}, _ models.Sidecar) bool {
+			allow, _ := config["allowFeeRecipients"].(bool)
+			return allow
+		},
+	},
+	{
+		feature:     "feeManagerConfig",
+		description: "the fee manager precompile, changing the fee config at runtime",
+		version:     "v0.2.5",
+		used:        configField("feeManagerConfig"),
+	},
+	{
+		feature:     "upgrades",
+		description: "the precompile upgrades scheduled with subnet upgrade",
+		version:     constants.PrecompileUpgradesSubnetEVMVersion,
+		used: func(_ map[string]interface{}, sc models.Sidecar) bool {
+			return len(sc.Upgrades) > 0
+		},
+	},
+}
+
+// configField returns whether the chain config sets field
+func configField(field string) func(map[string]interface{}, models.Sidecar) bool {
+	return func(config map[string]interface{}, _ models.Sidecar) bool {
+		value, ok := config[field]
+		return ok && value != nil
+	}
+}
+
+// UnsupportedFeature is a feature used by a subnet which the subnet-evm
+// version it is deployed with doesn't support
+type UnsupportedFeature struct {
+	Feature     string
+	Description string
+	// MinVersion is the first subnet-evm version supporting the feature
+	MinVersion string
+}
+
+func (f UnsupportedFeature) String() string {
+	return fmt.Sprintf("%s (%s) needs subnet-evm %s or later", f.Feature, f.Description, f.MinVersion)
+}
+
+// CheckSubnetEVMCapabilities returns the features used by the genesis and
+// the upgrades of sc which subnetEVMVersion doesn't support
+func CheckSubnetEVMCapabilities(genesisBytes []byte, sc models.Sidecar, subnetEVMVersion string) ([]UnsupportedFeature, error) {
+	version, err := semver.NewVersion(strings.TrimPrefix(subnetEVMVersion, "v"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet-evm version %q: %w", subnetEVMVersion, err)
+	}
+	genesis, err := decodeGenesis(genesisBytes)
+	if err != nil {
+		return nil, err
+	}
+	config, _ := genesis["config"].(map[string]interface{})
+	unsupported := []UnsupportedFeature{}
+	for _, c := range subnetEVMCapabilities {
+		if !c.used(config, sc) {
+			continue
+		}
+		if version.LessThan(*semver.New(strings.TrimPrefix(c.version, "v"))) {
+			unsupported = append(unsupported, UnsupportedFeature{
+				Feature:     c.feature,
+				Description: c.description,
+				MinVersion:  c.version,
+			})
+		}
+	}
+	return unsupported, nil
+}
+
+// MinSubnetEVMVersion returns the first subnet-evm version supporting all of
+// features
+func MinSubnetEVMVersion(features []UnsupportedFeature) string {
+	minVersion := ""
+	for _, f := range features {
+		if minVersion == "" || semver.New(strings.TrimPrefix(minVersion, "v")).LessThan(*semver.New(strings.TrimPrefix(f.MinVersion, "v"))) {
+			minVersion = f.MinVersion
+		}
+	}
+	return minVersion
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSubnetEVMCapabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	genesis := []byte(`{
+		"config": {
+			"chainId": 12345,
+			"feeConfig": {"gasLimit": 8000000},
+			"allowFeeRecipients": false,
+			"txAllowListConfig": {"blockTimestamp": 0},
+			"feeManagerConfig": {"blockTimestamp": 0, "adminAddresses": ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]}
+		},
+		"alloc": {}
+	}`)
+	sc := models.Sidecar{VM: models.SubnetEvm}
+
+	unsupported, err := CheckSubnetEVMCapabilities(genesis, sc, "v0.1.4")
+	assert.NoError(err)
+	assert.Equal([]UnsupportedFeature{
+		{Feature: "txAllowListConfig", Description: "the transaction allow list precompile", MinVersion: "v0.2.0"},
+		{Feature: "feeManagerConfig", Description: "the fee manager precompile, changing the fee config at runtime", MinVersion: "v0.2.5"},
+	}, unsupported)
+	assert.Equal("v0.2.5", MinSubnetEVMVersion(unsupported))
+	assert.Equal("feeManagerConfig (the fee manager precompile, changing the fee config at runtime) needs subnet-evm v0.2.5 or later", unsupported[1].String())
+
+	unsupported, err = CheckSubnetEVMCapabilities(genesis, sc, "v0.2.5")
+	assert.NoError(err)
+	assert.Empty(unsupported)
+
+	// scheduled upgrades need a recent enough version too
+	sc.Upgrades = []models.NetworkUpgrade{{Precompile: TxAllowList, Timestamp: 1, Disable: true}}
+	unsupported, err = CheckSubnetEVMCapabilities(genesis, sc, "v0.2.5")
+	assert.NoError(err)
+	assert.Len(unsupported, 1)
+	assert.Equal("upgrades", unsupported[0].Feature)
+
+	_, err = CheckSubnetEVMCapabilities(genesis, sc, "latest")
+	assert.ErrorContains(err, `invalid subnet-evm version "latest"`)
+	_, err = CheckSubnetEVMCapabilities([]byte("[]"), sc, "v0.2.5")
+	assert.Error(err)
+}
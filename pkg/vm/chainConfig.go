@@ -0,0 +1,139 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// node-level settings of subnet-evm chains, set in the chain config the nodes
+// pass to the VM
+const (
+	RPCGasCapKey       = "rpc-gas-cap"
+	RPCTxFeeCapKey     = "rpc-tx-fee-cap"
+	LocalTxsEnabledKey = "local-txs-enabled"
+	FeeRecipientKey    = "feeRecipient"
+
+	// the defaults of subnet-evm, applied without a chain config
+	defaultRPCGasCap   = 50_000_000
+	defaultRPCTxFeeCap = 100
+
+	// gas used by the cheapest transaction, a transfer
+	intrinsicTxGas = 21_000
+)
+
+// EvmChainSettings are the node-level settings of a subnet-evm chain exposed
+// by this tool. Unset fields are left as they are.
+type EvmChainSettings struct {
+	// RPCGasCap caps the gas of eth_call and eth_estimateGas, 0 for no cap
+	RPCGasCap *uint64
+	// RPCTxFeeCap caps the fee of the transactions sent through the RPC, in
+	// AVAX, 0 for no cap
+	RPCTxFeeCap *float64
+	// LocalTxsEnabled exempts the transactions sent through the RPC of a node
+	// from the pricing rules of its transaction pool
+	LocalTxsEnabled *bool
+	// FeeRecipient receives the fees of the blocks the node builds, when the
+	// genesis sets allowFeeRecipients
+	FeeRecipient *common.Address
+}
+
+// Validate checks the settings make sense for a chain with the given block
+// gas limit. Caps lower than what blocks can hold are not an error, but are
+// returned as warnings, as they make heavy transactions fail at the RPC.
+func (s EvmChainSettings) Validate(blockGasLimit *big.Int) ([]string, error) {
+	warnings := []string{}
+	if s.RPCGasCap != nil && *s.RPCGasCap != 0 {
+		if *s.RPCGasCap < intrinsicTxGas {
+			return nil, fmt.Errorf("%s of %d is below the %d gas of a transfer, use 0 for no cap", RPCGasCapKey, *s.RPCGasCap, intrinsicTxGas)
+		}
+		if blockGasLimit != nil && new(big.Int).SetUint64(*s.RPCGasCap).Cmp(blockGasLimit) < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s of %d is below the block gas limit of %s, calls and gas estimates of larger transactions will fail",
+				RPCGasCapKey, *s.RPCGasCap, blockGasLimit))
+		}
+	}
+	if s.RPCTxFeeCap != nil && *s.RPCTxFeeCap < 0 {
+		return nil, fmt.Errorf("%s of %g is negative, use 0 for no cap", RPCTxFeeCapKey, *s.RPCTxFeeCap)
+	}
+	return warnings, nil
+}
+
+// UpdateChainConfig sets the settings in the chain config, keeping anything
+// else in it. An empty chain config is created from scratch.
+func UpdateChainConfig(chainConfigBytes []byte, s EvmChainSettings) ([]byte, error) {
+	chainConfig, err := decodeChainConfig(chainConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+	if s.RPCGasCap != nil {
+		chainConfig[RPCGasCapKey] = *s.RPCGasCap
+	}
+	if s.RPCTxFeeCap != nil {
+		chainConfig[RPCTxFeeCapKey] = *s.RPCTxFeeCap
+	}
+	if s.LocalTxsEnabled != nil {
+		chainConfig[LocalTxsEnabledKey] = *s.LocalTxsEnabled
+	}
+	if s.FeeRecipient != nil {
+		chainConfig[FeeRecipientKey] = s.FeeRecipient.Hex()
+	}
+	return json.MarshalIndent(chainConfig, "", "    ")
+}
+
+// DescribeChainConfig returns the settings of EvmChainSettings in the chain
+// config, or their subnet-evm default, along with the other settings it
+// holds, sorted by key
+func DescribeChainConfig(chainConfigBytes []byte) ([][2]string, error) {
+	chainConfig, err := decodeChainConfig(chainConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[string]interface{}{
+		RPCGasCapKey:       json.Number(fmt.Sprint(defaultRPCGasCap)),
+		RPCTxFeeCapKey:     json.Number(fmt.Sprint(defaultRPCTxFeeCap)),
+		LocalTxsEnabledKey: false,
+	}
+	keys := []string{}
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	for k := range chainConfig {
+		if _, ok := defaults[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	settings := make([][2]string, 0, len(keys))
+	for _, k := range keys {
+		v, ok := chainConfig[k]
+		if !ok {
+			settings = append(settings, [2]string{k, fmt.Sprintf("%v (default)", defaults[k])})
+			continue
+		}
+		settings = append(settings, [2]string{k, compactJSON(v)})
+	}
+	return settings, nil
+}
+
+func decodeChainConfig(chainConfigBytes []byte) (map[string]interface{}, error) {
+	chainConfig := map[string]interface{}{}
+	if len(bytes.TrimSpace(chainConfigBytes)) == 0 {
+		return chainConfig, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(chainConfigBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&chainConfig); err != nil {
+		return nil, fmt.Errorf("chain config is not a valid JSON object: %w", err)
+	}
+	if chainConfig == nil {
+		return nil, errors.New("chain config is not a valid JSON object")
+	}
+	return chainConfig, nil
+}
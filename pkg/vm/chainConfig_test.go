@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateChainSettings(t *testing.T) {
+	assert := assert.New(t)
+
+	blockGasLimit := big.NewInt(8_000_000)
+	noCap, lowCap, highCap := uint64(0), uint64(1_000_000), uint64(100_000_000)
+	tooLowCap := uint64(20_000)
+	negativeFeeCap := -1.0
+
+	warnings, err := EvmChainSettings{RPCGasCap: &noCap}.Validate(blockGasLimit)
+	assert.NoError(err)
+	assert.Empty(warnings)
+
+	warnings, err = EvmChainSettings{RPCGasCap: &highCap}.Validate(blockGasLimit)
+	assert.NoError(err)
+	assert.Empty(warnings)
+
+	warnings, err = EvmChainSettings{RPCGasCap: &lowCap}.Validate(blockGasLimit)
+	assert.NoError(err)
+	assert.Len(warnings, 1)
+
+	_, err = EvmChainSettings{RPCGasCap: &tooLowCap}.Validate(blockGasLimit)
+	assert.Error(err)
+
+	_, err = EvmChainSettings{RPCTxFeeCap: &negativeFeeCap}.Validate(blockGasLimit)
+	assert.Error(err)
+}
+
+func TestUpdateChainConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	gasCap := uint64(100_000_000)
+	enabled := true
+	updated, err := UpdateChainConfig([]byte(`{"eth-apis": ["eth", "debug"], "rpc-tx-fee-cap": 1000}`),
+		EvmChainSettings{RPCGasCap: &gasCap, LocalTxsEnabled: &enabled})
+	assert.NoError(err)
+
+	chainConfig := map[string]interface{}{}
+	assert.NoError(json.Unmarshal(updated, &chainConfig))
+	assert.Equal(float64(gasCap), chainConfig[RPCGasCapKey])
+	assert.Equal(true, chainConfig[LocalTxsEnabledKey])
+	// the settings not given and the other keys are kept
+	assert.Equal(float64(1000), chainConfig[RPCTxFeeCapKey])
+	assert.Equal([]interface{}{"eth", "debug"}, chainConfig["eth-apis"])
+
+	recipient := PrefundedEwoqAddress
+	updated, err = UpdateChainConfig(nil, EvmChainSettings{FeeRecipient: &recipient})
+	assert.NoError(err)
+	chainConfig = map[string]interface{}{}
+	assert.NoError(json.Unmarshal(updated, &chainConfig))
+	assert.Equal(map[string]interface{}{FeeRecipientKey: recipient.Hex()}, chainConfig)
+
+	_, err = UpdateChainConfig([]byte(`[]`), EvmChainSettings{RPCGasCap: &gasCap})
+	assert.Error(err)
+}
+
+func TestDescribeChainConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	settings, err := DescribeChainConfig(nil)
+	assert.NoError(err)
+	assert.Equal([][2]string{
+		{LocalTxsEnabledKey, "false (default)"},
+		{RPCGasCapKey, "50000000 (default)"},
+		{RPCTxFeeCapKey, "100 (default)"},
+	}, settings)
+
+	settings, err = DescribeChainConfig([]byte(`{"rpc-gas-cap": 0, "pruning-enabled": false}`))
+	assert.NoError(err)
+	assert.Equal([][2]string{
+		{LocalTxsEnabledKey, "false (default)"},
+		{"pruning-enabled", "false"},
+		{RPCGasCapKey, "0"},
+		{RPCTxFeeCapKey, "100 (default)"},
+	}, settings)
+}
@@ -11,9 +11,9 @@ import (
 )
 
 func CreateCustomGenesis(name string, app *application.Avalanche) ([]byte, *models.Sidecar, error) {
-	ux.Logger.PrintToUser("creating custom VM subnet %s", name)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgCreatingCustomSubnet), name)
 
-	genesisPath, err := app.Prompt.CaptureExistingFilepath("Enter path to custom genesis")
+	genesisPath, err := app.Prompt.CaptureExistingFilepath(ux.Msg(ux.MsgCustomGenesisPath))
 	if err != nil {
 		return []byte{}, nil, err
 	}
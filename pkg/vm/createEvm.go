@@ -3,8 +3,6 @@
 package vm
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"math/big"
 
@@ -21,7 +19,11 @@ const (
 	startStage wizardState = iota
 	descriptorStage
 	feeStage
+	feeRecipientStage
+	forkStage
 	airdropStage
+	predeployStage
+	supplyStage
 	precompileStage
 	doneStage
 	errored
@@ -47,11 +49,28 @@ func nextStage(currentState wizardState, direction stateDirection) wizardState {
 	return currentState
 }
 
-func CreateEvmGenesis(name string, app *application.Avalanche) ([]byte, *models.Sidecar, error) {
-	ux.Logger.PrintToUser("creating subnet %s", name)
-
-	genesis := core.Genesis{}
-	conf := params.SubnetEVMDefaultChainConfig
+// CreateEvmGenesis runs the wizard creating the genesis of a subnet EVM. The
+// Ethereum hard forks are asked for unless forks sets them, where the fees
+// go unless fees is set, and the fees unless they are sized for the hardware
+// profile hardwareProfile. It also returns the settings of the chain
+// config chosen in the wizard. BuildEvmGenesis creates the same genesis
+// without prompts.
+func CreateEvmGenesis(
+	name string,
+	app *application.Avalanche,
+	forks ForkActivations,
+	fees *FeeDestination,
+	hardwareProfile string,
+) ([]byte, *models.Sidecar, EvmChainSettings, error) {
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgCreatingSubnet), name)
+
+	defaultConf := *params.SubnetEVMDefaultChainConfig
+	conf := &defaultConf
+	if len(forks) > 0 {
+		if err := ApplyForkActivations(conf, forks); err != nil {
+			return []byte{}, nil, EvmChainSettings{}, err
+		}
+	}
 
 	stage := startStage
 
@@ -59,8 +78,11 @@ func CreateEvmGenesis(name string, app *application.Avalanche) ([]byte, *models.
 		chainID    *big.Int
 		tokenName  string
 		allocation core.GenesisAlloc
+		settings   EvmChainSettings
 		direction  stateDirection
 		err        error
+		// chosenProfile is the hardware profile the fees are sized for
+		chosenProfile = hardwareProfile
 	)
 
 	for stage != doneStage {
@@ -70,44 +92,53 @@ func CreateEvmGenesis(name string, app *application.Avalanche) ([]byte, *models.
 		case descriptorStage:
 			chainID, tokenName, direction, err = getDescriptors(app)
 		case feeStage:
-			*conf, direction, err = getFeeConfig(*conf, app)
+			// passed through, in the direction of the wizard, when set by flags
+			if hardwareProfile != "" {
+				var profile HardwareProfile
+				if profile, err = GetHardwareProfile(hardwareProfile); err == nil {
+					conf.FeeConfig = profile.FeeConfig()
+				}
+			} else {
+				*conf, chosenProfile, direction, err = getFeeConfig(*conf, app)
+			}
+		case feeRecipientStage:
+			// passed through, in the direction of the wizard, when set by flags
+			if fees == nil {
+				*conf, settings.FeeRecipient, direction, err = getFeeRecipient(*conf, app)
+			} else {
+				*conf = fees.apply(*conf)
+				settings.FeeRecipient = fees.Recipient
+			}
+		case forkStage:
+			// passed through, in the direction of the wizard, when set by flags
+			if len(forks) == 0 {
+				*conf, direction, err = getForks(*conf, app)
+			}
 		case airdropStage:
 			allocation, direction, err = getAllocation(app)
+		case predeployStage:
+			allocation, direction, err = getPredeploys(allocation, app)
+		case supplyStage:
+			direction, err = getSupply(allocation, app)
 		case precompileStage:
 			*conf, direction, err = getPrecompiles(*conf, app)
 		default:
 			err = errors.New("invalid creation stage")
 		}
 		if err != nil {
-			return []byte{}, nil, err
+			return []byte{}, nil, EvmChainSettings{}, err
 		}
 		stage = nextStage(stage, direction)
 	}
 
-	conf.ChainID = chainID
-
-	genesis.Alloc = allocation
-	genesis.Config = conf
-	genesis.Difficulty = Difficulty
-	genesis.GasLimit = GasLimit
-
-	jsonBytes, err := genesis.MarshalJSON()
-	if err != nil {
-		return []byte{}, nil, err
+	for _, address := range notTxAllowListed(*conf, allocation) {
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgNotTxAllowListed), address.Hex())
 	}
 
-	var prettyJSON bytes.Buffer
-	err = json.Indent(&prettyJSON, jsonBytes, "", "    ")
+	genesisBytes, sc, err := evmGenesis(name, conf, chainID, tokenName, allocation, settings.FeeRecipient)
 	if err != nil {
-		return []byte{}, nil, err
-	}
-
-	sc := &models.Sidecar{
-		Name:      name,
-		VM:        models.SubnetEvm,
-		Subnet:    name,
-		TokenName: tokenName,
+		return []byte{}, nil, EvmChainSettings{}, err
 	}
-
-	return prettyJSON.Bytes(), sc, nil
+	sc.HardwareProfile = chosenProfile
+	return genesisBytes, sc, settings, nil
 }
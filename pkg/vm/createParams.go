@@ -0,0 +1,149 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CreateParams fully specifies a subnet EVM genesis, as chosen in the wizard
+// of subnet create, for tools generating the same genesis without prompts
+type CreateParams struct {
+	Name    string
+	ChainID *big.Int
+	// TokenName defaults to constants.DefaultTokenName
+	TokenName string
+	// FeeConfig defaults to the fees of HardwareProfile, or else to
+	// StarterFeeConfig, the fees of the C-Chain
+	FeeConfig *params.FeeConfig
+	// HardwareProfile is the hardware profile the validators run on,
+	// recorded in the sidecar
+	HardwareProfile string
+	// Forks sets the forks which aren't active at genesis, all are by default
+	Forks      ForkActivations
+	Allocation core.GenesisAlloc
+	// TargetSupply, when set, is the initial supply the allocations are
+	// expected to total. They must be within supplyMismatchFactor of it.
+	TargetSupply *big.Int
+	// the precompiles, disabled when nil
+	ContractDeployerAllowList *precompile.ContractDeployerAllowListConfig
+	TxAllowList               *precompile.TxAllowListConfig
+	NativeMinter              *precompile.ContractNativeMinterConfig
+	// FeeRecipient is the reward address the fees are sent to, instead of
+	// being burned, when set. It goes in the chain config of the nodes, see
+	// EvmChainSettings.
+	FeeRecipient *common.Address
+}
+
+// BuildEvmGenesis returns the genesis of a subnet EVM specified by p, and
+// its sidecar, without any prompt. The genesis is the one the wizard of
+// CreateEvmGenesis creates for the same choices.
+func BuildEvmGenesis(p CreateParams) ([]byte, *models.Sidecar, error) {
+	if p.Name == "" {
+		return nil, nil, errors.New("the subnet name is required")
+	}
+	if p.ChainID == nil || p.ChainID.Sign() <= 0 {
+		return nil, nil, errors.New("the chain ID must be a positive integer")
+	}
+	defaultConf := *params.SubnetEVMDefaultChainConfig
+	conf := &defaultConf
+	if err := ApplyForkActivations(conf, p.Forks); err != nil {
+		return nil, nil, err
+	}
+	conf.FeeConfig = StarterFeeConfig
+	if p.HardwareProfile != "" {
+		profile, err := GetHardwareProfile(p.HardwareProfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		conf.FeeConfig = profile.FeeConfig()
+	}
+	if p.FeeConfig != nil {
+		conf.FeeConfig = *p.FeeConfig
+	}
+	if p.ContractDeployerAllowList != nil {
+		conf.ContractDeployerAllowListConfig = *p.ContractDeployerAllowList
+	}
+	if p.TxAllowList != nil {
+		conf.TxAllowListConfig = *p.TxAllowList
+	}
+	if p.NativeMinter != nil {
+		conf.ContractNativeMinterConfig = *p.NativeMinter
+	}
+	*conf = FeeDestination{Recipient: p.FeeRecipient}.apply(*conf)
+
+	tokenName := p.TokenName
+	if tokenName == "" {
+		tokenName = constants.DefaultTokenName
+	}
+	allocation := p.Allocation
+	if allocation == nil {
+		allocation = core.GenesisAlloc{}
+	}
+	if p.TargetSupply != nil {
+		if err := CheckSupply(TotalSupply(allocation), p.TargetSupply); err != nil {
+			return nil, nil, err
+		}
+	}
+	genesisBytes, sc, err := evmGenesis(p.Name, conf, p.ChainID, tokenName, allocation, p.FeeRecipient)
+	if err != nil {
+		return nil, nil, err
+	}
+	sc.HardwareProfile = p.HardwareProfile
+	return genesisBytes, sc, nil
+}
+
+// evmGenesis returns the genesis with the chain config conf, and its sidecar
+func evmGenesis(
+	name string,
+	conf *params.ChainConfig,
+	chainID *big.Int,
+	tokenName string,
+	allocation core.GenesisAlloc,
+	feeRecipient *common.Address,
+) ([]byte, *models.Sidecar, error) {
+	// the fee config may have changed after the reward address was chosen,
+	// going back in the wizard
+	if feeRecipient != nil {
+		if err := CheckFeeRecipient(*conf, *feeRecipient); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	conf.ChainID = chainID
+
+	genesis := core.Genesis{}
+	genesis.Alloc = allocation
+	genesis.Config = conf
+	genesis.Difficulty = Difficulty
+	genesis.GasLimit = GasLimit
+
+	jsonBytes, err := genesis.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var prettyJSON bytes.Buffer
+	err = json.Indent(&prettyJSON, jsonBytes, "", "    ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc := &models.Sidecar{
+		Name:      name,
+		VM:        models.SubnetEvm,
+		Subnet:    name,
+		TokenName: tokenName,
+	}
+	return prettyJSON.Bytes(), sc, nil
+}
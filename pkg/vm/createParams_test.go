@@ -0,0 +1,90 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildEvmGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	admin := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	p := CreateParams{
+		Name:       "mySubnet",
+		ChainID:    big.NewInt(12345),
+		Forks:      ForkActivations{"muirglacier": nil},
+		Allocation: core.GenesisAlloc{admin: {Balance: big.NewInt(1)}},
+		TxAllowList: &precompile.TxAllowListConfig{
+			AllowListConfig: precompile.AllowListConfig{BlockTimestamp: big.NewInt(0), AllowListAdmins: []common.Address{admin}},
+		},
+		FeeRecipient: &recipient,
+	}
+	genesisBytes, sc, err := BuildEvmGenesis(p)
+	assert.NoError(err)
+	assert.Equal(&models.Sidecar{
+		Name:      "mySubnet",
+		VM:        models.SubnetEvm,
+		Subnet:    "mySubnet",
+		TokenName: constants.DefaultTokenName,
+	}, sc)
+
+	var genesis core.Genesis
+	assert.NoError(json.Unmarshal(genesisBytes, &genesis))
+	assert.Equal(big.NewInt(12345), genesis.Config.ChainID)
+	assert.Equal(StarterFeeConfig, genesis.Config.FeeConfig)
+	assert.Nil(genesis.Config.MuirGlacierBlock)
+	assert.Equal([]common.Address{admin}, genesis.Config.TxAllowListConfig.AllowListAdmins)
+	assert.Nil(genesis.Config.ContractNativeMinterConfig.BlockTimestamp)
+	assert.True(genesis.Config.AllowFeeRecipients)
+	assert.Equal(big.NewInt(1), genesis.Alloc[admin].Balance)
+	assert.Equal(uint64(GasLimit), genesis.GasLimit)
+
+	// the same parameters give the same genesis
+	again, _, err := BuildEvmGenesis(p)
+	assert.NoError(err)
+	assert.Equal(genesisBytes, again)
+
+	// the fees sized for a hardware profile, recorded in the sidecar
+	p.HardwareProfile = "performance"
+	genesisBytes, sc, err = BuildEvmGenesis(p)
+	assert.NoError(err)
+	assert.Equal("performance", sc.HardwareProfile)
+	assert.NoError(json.Unmarshal(genesisBytes, &genesis))
+	assert.Equal(big.NewInt(20_000_000), genesis.Config.FeeConfig.GasLimit)
+	p.HardwareProfile = "huge"
+	_, _, err = BuildEvmGenesis(p)
+	assert.ErrorContains(err, "unknown hardware profile")
+	p.HardwareProfile = ""
+
+	_, _, err = BuildEvmGenesis(CreateParams{Name: "mySubnet"})
+	assert.ErrorContains(err, "chain ID")
+	_, _, err = BuildEvmGenesis(CreateParams{ChainID: big.NewInt(1)})
+	assert.ErrorContains(err, "name")
+	// the fees sent to a precompile are lost
+	minter := precompile.ContractNativeMinterAddress
+	_, _, err = BuildEvmGenesis(CreateParams{
+		Name:         "mySubnet",
+		ChainID:      big.NewInt(1),
+		FeeRecipient: &minter,
+	})
+	assert.Error(err)
+
+	// an airdrop with a zero too many
+	p.TargetSupply = big.NewInt(1)
+	_, _, err = BuildEvmGenesis(p)
+	assert.NoError(err)
+	p.Allocation = core.GenesisAlloc{admin: {Balance: big.NewInt(10)}}
+	_, _, err = BuildEvmGenesis(p)
+	assert.ErrorIs(err, ErrSupplyMismatch)
+}
@@ -12,9 +12,9 @@ import (
 
 func getChainID(app *application.Avalanche) (*big.Int, error) {
 	// TODO check against known chain ids and provide warning
-	ux.Logger.PrintToUser("Enter your subnet's ChainId. It can be any positive integer.")
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgEnterChainID))
 
-	chainID, err := app.Prompt.CapturePositiveBigInt("ChainId")
+	chainID, err := app.Prompt.CapturePositiveBigInt(ux.Msg(ux.MsgChainIDLabel))
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +24,7 @@ func getChainID(app *application.Avalanche) (*big.Int, error) {
 		return nil, err
 	}
 	if exists {
-		ux.Logger.PrintToUser("The provided chain ID %q already exists! Try a different one:", chainID.String())
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgChainIDExists), chainID.String())
 		return getChainID(app)
 	}
 
@@ -32,8 +32,8 @@ func getChainID(app *application.Avalanche) (*big.Int, error) {
 }
 
 func getTokenName(app *application.Avalanche) (string, error) {
-	ux.Logger.PrintToUser("Select a symbol for your subnet's native token")
-	tokenName, err := app.Prompt.CaptureString("Token symbol")
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgSelectTokenSymbol))
+	tokenName, err := app.Prompt.CaptureString(ux.Msg(ux.MsgTokenSymbolLabel))
 	if err != nil {
 		return "", err
 	}
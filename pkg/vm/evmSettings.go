@@ -15,7 +15,6 @@ const (
 	GasLimit = 8_000_000
 
 	defaultAirdropAmount = "1000000000000000000000000"
-	goBackMsg            = "Go back to previous step"
 )
 
 var (
@@ -0,0 +1,141 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/constants"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CheckFeeRecipient checks the fees of a chain with config can be sent to
+// recipient, set as the fee recipient in its chain config
+func CheckFeeRecipient(config params.ChainConfig, recipient common.Address) error {
+	if !config.AllowFeeRecipients {
+		return fmt.Errorf("the genesis burns the fees, it must set allowFeeRecipients for the fees to be sent to %s", recipient.Hex())
+	}
+	if recipient == (common.Address{}) || recipient == constants.BlackholeAddr {
+		return fmt.Errorf("%s is not a reward address, the fees sent to it are burned", recipient.Hex())
+	}
+	if contains(precompile.UsedAddresses, recipient) {
+		return fmt.Errorf("%s is the address of a precompile, the fees sent to it are lost", recipient.Hex())
+	}
+	feeConfig := config.GetFeeConfig()
+	if (feeConfig.MinBaseFee == nil || feeConfig.MinBaseFee.Sign() == 0) &&
+		(feeConfig.MaxBlockGasCost == nil || feeConfig.MaxBlockGasCost.Sign() == 0) {
+		return errors.New("the fee config charges no base fee nor block gas cost, so there are no fees to send to a reward address")
+	}
+	return nil
+}
+
+// DescribeFeeDestination tells where the genesis config sends the fees
+func DescribeFeeDestination(config params.ChainConfig) string {
+	if config.AllowFeeRecipients {
+		return "sent to the fee recipient of the node building the block"
+	}
+	return "burned"
+}
+
+// LintFeeRecipient checks where the genesis config sends the fees is
+// consistent with the fee recipient of the chain config of the nodes, which
+// subnet-evm otherwise ignores or replaces with the blackhole address
+func LintFeeRecipient(config params.ChainConfig, chainConfigBytes []byte) ([]LintFinding, error) {
+	chainConfig, err := decodeChainConfig(chainConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+	configured, set := chainConfig[FeeRecipientKey].(string)
+	switch {
+	case set && !config.AllowFeeRecipients:
+		return []LintFinding{{
+			Severity: LintWarning,
+			Rule:     "fee-recipient-ignored",
+			Message:  fmt.Sprintf("the chain config sends the fees to %s, but the genesis burns them as it doesn't set allowFeeRecipients", configured),
+		}}, nil
+	case !set && config.AllowFeeRecipients:
+		return []LintFinding{{
+			Severity: LintWarning,
+			Rule:     "fees-burned",
+			Message:  "the genesis allows fee recipients, but the chain config sets no feeRecipient, so the nodes burn the fees",
+		}}, nil
+	case set:
+		if !common.IsHexAddress(configured) {
+			return []LintFinding{{
+				Severity: LintError,
+				Rule:     "invalid-fee-recipient",
+				Message:  fmt.Sprintf("the fee recipient %q of the chain config is not an address", configured),
+			}}, nil
+		}
+		if err := CheckFeeRecipient(config, common.HexToAddress(configured)); err != nil {
+			return []LintFinding{{
+				Severity: LintError,
+				Rule:     "invalid-fee-recipient",
+				Message:  err.Error(),
+			}}, nil
+		}
+	}
+	return []LintFinding{}, nil
+}
+
+// FeeDestination is where the fees of a chain go, decided without the wizard:
+// burned, or sent to the reward address Recipient if set
+type FeeDestination struct {
+	Recipient *common.Address
+}
+
+// apply sets in config whether the fees go to the fee recipients of the nodes
+func (d FeeDestination) apply(config params.ChainConfig) params.ChainConfig {
+	config.AllowFeeRecipients = d.Recipient != nil
+	return config
+}
+
+// getFeeRecipient asks whether the fees of the chain with config are burned
+// or sent to a reward address, which it returns. The nodes send the fees of
+// the blocks they build to the reward address set in their chain config.
+func getFeeRecipient(config params.ChainConfig, app *application.Avalanche) (params.ChainConfig, *common.Address, stateDirection, error) {
+	var (
+		burnFees   = ux.Msg(ux.MsgBurnFees)
+		rewardFees = ux.Msg(ux.MsgRewardAddressFees)
+		moreInfo   = ux.Msg(ux.MsgMoreInfo)
+		goBackMsg  = ux.Msg(ux.MsgGoBack)
+	)
+
+	for {
+		decision, err := app.Prompt.CaptureList(
+			ux.Msg(ux.MsgFeeRecipientPrompt),
+			[]string{burnFees, rewardFees, moreInfo, goBackMsg},
+		)
+		if err != nil {
+			return config, nil, stop, err
+		}
+
+		switch decision {
+		case burnFees:
+			return FeeDestination{}.apply(config), nil, forward, nil
+		case rewardFees:
+			recipient, err := app.Prompt.CaptureAddress(ux.Msg(ux.MsgRewardAddress))
+			if err != nil {
+				return config, nil, stop, err
+			}
+			rewardConfig := FeeDestination{Recipient: &recipient}.apply(config)
+			if err := CheckFeeRecipient(rewardConfig, recipient); err != nil {
+				ux.Logger.PrintToUser("%s", err)
+				continue
+			}
+			return rewardConfig, &recipient, forward, nil
+		case moreInfo:
+			fmt.Print(ux.ToOutput(ux.Msg(ux.MsgFeeRecipientInfo)))
+		case goBackMsg:
+			return config, nil, backward, nil
+		default:
+			return config, nil, stop, errors.New("unexpected option")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/constants"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFeeRecipient(t *testing.T) {
+	assert := assert.New(t)
+
+	config := *params.SubnetEVMDefaultChainConfig
+	config.FeeConfig = StarterFeeConfig
+	assert.Error(CheckFeeRecipient(config, PrefundedEwoqAddress))
+
+	config.AllowFeeRecipients = true
+	assert.NoError(CheckFeeRecipient(config, PrefundedEwoqAddress))
+	assert.Error(CheckFeeRecipient(config, common.Address{}))
+	assert.Error(CheckFeeRecipient(config, constants.BlackholeAddr))
+	assert.Error(CheckFeeRecipient(config, precompile.ContractNativeMinterAddress))
+
+	// a chain charging no fees has nothing to reward
+	config.FeeConfig.MinBaseFee = big.NewInt(0)
+	config.FeeConfig.MaxBlockGasCost = big.NewInt(0)
+	assert.Error(CheckFeeRecipient(config, PrefundedEwoqAddress))
+	config.FeeConfig.MaxBlockGasCost = big.NewInt(1_000_000)
+	assert.NoError(CheckFeeRecipient(config, PrefundedEwoqAddress))
+}
+
+func TestLintFeeRecipient(t *testing.T) {
+	assert := assert.New(t)
+
+	config := *params.SubnetEVMDefaultChainConfig
+	config.FeeConfig = StarterFeeConfig
+	recipient := []byte(`{"feeRecipient": "` + PrefundedEwoqAddress.Hex() + `"}`)
+
+	findings, err := LintFeeRecipient(config, nil)
+	assert.NoError(err)
+	assert.Empty(findings)
+
+	findings, err = LintFeeRecipient(config, recipient)
+	assert.NoError(err)
+	assert.Equal(map[string]LintSeverity{"fee-recipient-ignored": LintWarning}, lintRules(findings))
+
+	config = FeeDestination{Recipient: &PrefundedEwoqAddress}.apply(config)
+	assert.True(config.AllowFeeRecipients)
+	findings, err = LintFeeRecipient(config, recipient)
+	assert.NoError(err)
+	assert.Empty(findings)
+
+	findings, err = LintFeeRecipient(config, []byte(`{}`))
+	assert.NoError(err)
+	assert.Equal(map[string]LintSeverity{"fees-burned": LintWarning}, lintRules(findings))
+
+	findings, err = LintFeeRecipient(config, []byte(`{"feeRecipient": "`+constants.BlackholeAddr.Hex()+`"}`))
+	assert.NoError(err)
+	assert.Equal(map[string]LintSeverity{"invalid-fee-recipient": LintError}, lintRules(findings))
+
+	_, err = LintFeeRecipient(config, []byte(`[]`))
+	assert.Error(err)
+}
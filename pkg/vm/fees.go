@@ -9,89 +9,103 @@ import (
 	"github.com/ava-labs/subnet-evm/params"
 )
 
-func getFeeConfig(config params.ChainConfig, app *application.Avalanche) (params.ChainConfig, stateDirection, error) {
-	const (
-		useFast   = "High disk use   / High Throughput   5 mil   gas/s"
-		useMedium = "Medium disk use / Medium Throughput 2 mil   gas/s"
-		useSlow   = "Low disk use    / Low Throughput    1.5 mil gas/s (C-Chain's setting)"
-		customFee = "Customize fee config"
-
-		setGasLimit                 = "Set gas limit"
-		setBlockRate                = "Set target block rate"
-		setMinBaseFee               = "Set min base fee"
-		setTargetGas                = "Set target gas"
-		setBaseFeeChangeDenominator = "Set base fee change denominator"
-		setMinBlockGas              = "Set min block gas cost"
-		setMaxBlockGas              = "Set max block gas cost"
-		setGasStep                  = "Set block gas cost step"
+// getFeeConfig asks for the fee config of the chain. It also returns the
+// hardware profile the fees are sized for, if one was chosen.
+func getFeeConfig(config params.ChainConfig, app *application.Avalanche) (params.ChainConfig, string, stateDirection, error) {
+	var (
+		useFast   = ux.Msg(ux.MsgFeeFast)
+		useMedium = ux.Msg(ux.MsgFeeMedium)
+		useSlow   = ux.Msg(ux.MsgFeeSlow)
+		customFee = ux.Msg(ux.MsgFeeCustom)
+		goBackMsg = ux.Msg(ux.MsgGoBack)
+
+		setGasLimit                 = ux.Msg(ux.MsgSetGasLimit)
+		setBlockRate                = ux.Msg(ux.MsgSetBlockRate)
+		setMinBaseFee               = ux.Msg(ux.MsgSetMinBaseFee)
+		setTargetGas                = ux.Msg(ux.MsgSetTargetGas)
+		setBaseFeeChangeDenominator = ux.Msg(ux.MsgSetBaseFeeDenominator)
+		setMinBlockGas              = ux.Msg(ux.MsgSetMinBlockGas)
+		setMaxBlockGas              = ux.Msg(ux.MsgSetMaxBlockGas)
+		setGasStep                  = ux.Msg(ux.MsgSetGasStep)
 	)
 
-	feeConfigOptions := []string{useSlow, useMedium, useFast, customFee, goBackMsg}
+	feeConfigOptions := []string{useSlow, useMedium, useFast}
+	profileOptions := map[string]HardwareProfile{}
+	for _, profile := range hardwareProfiles {
+		option := ux.Msgf(ux.MsgFeeHardware, profile.Description(), profile.Name)
+		profileOptions[option] = profile
+		feeConfigOptions = append(feeConfigOptions, option)
+	}
+	feeConfigOptions = append(feeConfigOptions, customFee, goBackMsg)
 
 	feeDefault, err := app.Prompt.CaptureList(
-		"How would you like to set fees",
+		ux.Msg(ux.MsgSetFees),
 		feeConfigOptions,
 	)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	config.FeeConfig = StarterFeeConfig
+	if profile, ok := profileOptions[feeDefault]; ok {
+		config.FeeConfig = profile.FeeConfig()
+		return config, profile.Name, forward, nil
+	}
 
 	switch feeDefault {
 	case useFast:
 		config.FeeConfig.TargetGas = fastTarget
-		return config, forward, nil
+		return config, "", forward, nil
 	case useMedium:
 		config.FeeConfig.TargetGas = mediumTarget
-		return config, forward, nil
+		return config, "", forward, nil
 	case useSlow:
 		config.FeeConfig.TargetGas = slowTarget
-		return config, forward, nil
+		return config, "", forward, nil
 	case goBackMsg:
-		return config, backward, nil
+		return config, "", backward, nil
 	default:
-		ux.Logger.PrintToUser("Customizing fee config")
+		ux.Logger.PrintToUser(ux.Msg(ux.MsgCustomizingFees))
 	}
 
 	gasLimit, err := app.Prompt.CapturePositiveBigInt(setGasLimit)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	blockRate, err := app.Prompt.CapturePositiveBigInt(setBlockRate)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	minBaseFee, err := app.Prompt.CapturePositiveBigInt(setMinBaseFee)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	targetGas, err := app.Prompt.CapturePositiveBigInt(setTargetGas)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	baseDenominator, err := app.Prompt.CapturePositiveBigInt(setBaseFeeChangeDenominator)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	minBlockGas, err := app.Prompt.CapturePositiveBigInt(setMinBlockGas)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	maxBlockGas, err := app.Prompt.CapturePositiveBigInt(setMaxBlockGas)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	gasStep, err := app.Prompt.CapturePositiveBigInt(setGasStep)
 	if err != nil {
-		return config, stop, err
+		return config, "", stop, err
 	}
 
 	feeConf := params.FeeConfig{
@@ -107,5 +121,5 @@ func getFeeConfig(config params.ChainConfig, app *application.Avalanche) (params
 
 	config.FeeConfig = feeConf
 
-	return config, forward, nil
+	return config, "", forward, nil
 }
@@ -0,0 +1,202 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// ForkEnabled is the value of a fork active at genesis
+	ForkEnabled = "on"
+	// ForkDisabled is the value of a fork which is never activated
+	ForkDisabled = "off"
+
+	// subnetEVMFork is the fork of subnet EVM itself, activated by timestamp
+	subnetEVMFork = "subnetevm"
+)
+
+// ethereumFork is an Ethereum hard fork subnet EVM can activate
+type ethereumFork struct {
+	name  string
+	eips  string
+	block func(*params.ChainConfig) **big.Int
+}
+
+// ethereumForks are in their activation order. Subnet EVM only activates them
+// at genesis, and a fork can't be active without the previous ones.
+var ethereumForks = []ethereumFork{
+	{"homestead", "EIP-2, EIP-7", func(c *params.ChainConfig) **big.Int { return &c.HomesteadBlock }},
+	{"eip150", "EIP-150 gas repricing", func(c *params.ChainConfig) **big.Int { return &c.EIP150Block }},
+	{"eip155", "EIP-155 replay protection", func(c *params.ChainConfig) **big.Int { return &c.EIP155Block }},
+	{"eip158", "EIP-158 state clearing", func(c *params.ChainConfig) **big.Int { return &c.EIP158Block }},
+	{"byzantium", "EIP-140, EIP-196, EIP-197, EIP-198, EIP-211, EIP-214, EIP-658", func(c *params.ChainConfig) **big.Int { return &c.ByzantiumBlock }},
+	{"constantinople", "EIP-145, EIP-1014, EIP-1052", func(c *params.ChainConfig) **big.Int { return &c.ConstantinopleBlock }},
+	{"petersburg", "removal of EIP-1283", func(c *params.ChainConfig) **big.Int { return &c.PetersburgBlock }},
+	{"istanbul", "EIP-152, EIP-1108, EIP-1344, EIP-1884, EIP-2028, EIP-2200", func(c *params.ChainConfig) **big.Int { return &c.IstanbulBlock }},
+	{"muirglacier", "EIP-2384", func(c *params.ChainConfig) **big.Int { return &c.MuirGlacierBlock }},
+}
+
+// ForkNames returns the names of the forks which can be set, the Ethereum
+// hard forks in their activation order, then the fork of subnet EVM
+func ForkNames() []string {
+	names := make([]string, 0, len(ethereumForks)+1)
+	for _, fork := range ethereumForks {
+		names = append(names, fork.name)
+	}
+	return append(names, subnetEVMFork)
+}
+
+// ForkActivations sets the activation of forks by name: 0 for the Ethereum
+// hard forks active at genesis, the timestamp of the subnet EVM fork, or nil
+// for a disabled fork
+type ForkActivations map[string]*big.Int
+
+// ParseForkActivations parses the activation of forks by name, as ForkEnabled
+// or ForkDisabled, or a unix timestamp for the subnet EVM fork, and checks the
+// Ethereum hard forks are enabled in order
+func ParseForkActivations(values map[string]string) (ForkActivations, error) {
+	known := map[string]bool{}
+	for _, name := range ForkNames() {
+		known[name] = true
+	}
+	activations := ForkActivations{}
+	for name, value := range values {
+		name = strings.ToLower(name)
+		if !known[name] {
+			return nil, fmt.Errorf("unknown fork %q, must be one of %s", name, strings.Join(ForkNames(), ", "))
+		}
+		switch {
+		case strings.EqualFold(value, ForkEnabled):
+			activations[name] = big.NewInt(0)
+		case strings.EqualFold(value, ForkDisabled):
+			activations[name] = nil
+		case name == subnetEVMFork:
+			timestamp, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid activation of fork %s %q, must be a unix timestamp, %s or %s", name, value, ForkEnabled, ForkDisabled)
+			}
+			activations[name] = new(big.Int).SetUint64(timestamp)
+		default:
+			return nil, fmt.Errorf("invalid activation of fork %s %q, must be %s or %s as subnet EVM only activates Ethereum hard forks at genesis", name, value, ForkEnabled, ForkDisabled)
+		}
+	}
+	config := *params.SubnetEVMDefaultChainConfig
+	if err := ApplyForkActivations(&config, activations); err != nil {
+		return nil, err
+	}
+	return activations, nil
+}
+
+// ApplyForkActivations sets the forks of config. Disabling an Ethereum hard
+// fork also disables the following ones, unless they are enabled explicitly,
+// which is an error.
+func ApplyForkActivations(config *params.ChainConfig, activations ForkActivations) error {
+	disabledBy := ""
+	for _, fork := range ethereumForks {
+		block := fork.block(config)
+		activation, explicit := activations[fork.name]
+		switch {
+		case explicit && activation != nil && disabledBy != "":
+			return fmt.Errorf("fork %s can't be enabled, as the previous fork %s is disabled", fork.name, disabledBy)
+		case explicit:
+			*block = activation
+		case disabledBy != "":
+			*block = nil
+		}
+		if *block == nil && disabledBy == "" {
+			disabledBy = fork.name
+		}
+	}
+	if config.EIP150Block == nil {
+		config.EIP150Hash = common.Hash{}
+	}
+	if timestamp, ok := activations[subnetEVMFork]; ok {
+		config.SubnetEVMTimestamp = timestamp
+	}
+	return nil
+}
+
+// inactiveForks returns the names of the forks config doesn't activate at
+// genesis
+func inactiveForks(config *params.ChainConfig) []string {
+	names := []string{}
+	for _, fork := range ethereumForks {
+		if *fork.block(config) == nil {
+			names = append(names, fork.name)
+		}
+	}
+	if config.SubnetEVMTimestamp == nil || config.SubnetEVMTimestamp.Sign() > 0 {
+		names = append(names, subnetEVMFork)
+	}
+	return names
+}
+
+// getForks asks for the forks to activate, all of them at genesis by default
+func getForks(config params.ChainConfig, app *application.Avalanche) (params.ChainConfig, stateDirection, error) {
+	allForks := ux.Msg(ux.MsgAllForks)
+	customForks := ux.Msg(ux.MsgCustomForks)
+	goBackMsg := ux.Msg(ux.MsgGoBack)
+
+	choice, err := app.Prompt.CaptureList(
+		ux.Msg(ux.MsgForksPrompt),
+		[]string{allForks, customForks, goBackMsg},
+		prompts.WithDefault(allForks),
+	)
+	if err != nil {
+		return config, stop, err
+	}
+	switch choice {
+	case goBackMsg:
+		return config, backward, nil
+	case allForks:
+		return config, forward, nil
+	}
+
+	activations := ForkActivations{}
+	for _, fork := range ethereumForks {
+		enable, err := app.Prompt.CaptureYesNo(ux.Msgf(ux.MsgEnableFork, fork.name, fork.eips), prompts.WithDefault(prompts.Yes))
+		if err != nil {
+			return config, stop, err
+		}
+		if !enable {
+			// the following forks are disabled with it
+			activations[fork.name] = nil
+			break
+		}
+		activations[fork.name] = big.NewInt(0)
+	}
+
+	atGenesis := ux.Msg(ux.MsgForkAtGenesis)
+	atDate := ux.Msg(ux.MsgForkAtDate)
+	when, err := app.Prompt.CaptureList(
+		ux.Msg(ux.MsgSubnetEVMForkPrompt),
+		[]string{atGenesis, atDate},
+		prompts.WithDefault(atGenesis),
+	)
+	if err != nil {
+		return config, stop, err
+	}
+	activations[subnetEVMFork] = big.NewInt(0)
+	if when == atDate {
+		date, err := app.Prompt.CaptureDate(ux.Msg(ux.MsgSubnetEVMForkDate))
+		if err != nil {
+			return config, stop, err
+		}
+		activations[subnetEVMFork] = big.NewInt(date.Unix())
+	}
+
+	if err := ApplyForkActivations(&config, activations); err != nil {
+		return config, stop, err
+	}
+	return config, forward, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseForkActivations(t *testing.T) {
+	assert := assert.New(t)
+
+	forks, err := ParseForkActivations(map[string]string{
+		"Istanbul":    "ON",
+		"muirglacier": "off",
+		"subnetevm":   "1672531200",
+	})
+	assert.NoError(err)
+	assert.Equal(ForkActivations{
+		"istanbul":    big.NewInt(0),
+		"muirglacier": nil,
+		"subnetevm":   big.NewInt(1672531200),
+	}, forks)
+
+	_, err = ParseForkActivations(map[string]string{"london": "on"})
+	assert.Error(err)
+	// Ethereum hard forks only activate at genesis
+	_, err = ParseForkActivations(map[string]string{"istanbul": "100"})
+	assert.Error(err)
+	_, err = ParseForkActivations(map[string]string{"subnetevm": "-1"})
+	assert.Error(err)
+	// enabled after a disabled fork
+	_, err = ParseForkActivations(map[string]string{"byzantium": "off", "istanbul": "on"})
+	assert.Error(err)
+}
+
+func TestApplyForkActivations(t *testing.T) {
+	type test struct {
+		name     string
+		forks    ForkActivations
+		inactive []string
+	}
+	tests := []test{
+		{
+			name:     "defaults",
+			forks:    ForkActivations{},
+			inactive: []string{},
+		},
+		{
+			name:     "disabled fork disables the following ones",
+			forks:    ForkActivations{"byzantium": nil},
+			inactive: []string{"byzantium", "constantinople", "petersburg", "istanbul", "muirglacier"},
+		},
+		{
+			name:     "delayed subnet EVM fork",
+			forks:    ForkActivations{"muirglacier": nil, "subnetevm": big.NewInt(1672531200)},
+			inactive: []string{"muirglacier", "subnetevm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			config := *params.SubnetEVMDefaultChainConfig
+			assert.NoError(ApplyForkActivations(&config, tt.forks))
+			assert.Equal(tt.inactive, inactiveForks(&config))
+			assert.NoError(config.CheckConfigForkOrder())
+		})
+	}
+
+	// the default config is left untouched
+	assert.Equal(t, big.NewInt(0), params.SubnetEVMDefaultChainConfig.ByzantiumBlock)
+}
@@ -0,0 +1,548 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/storage"
+)
+
+// The genesis of a custom VM is opaque to the CLI, unless its authors
+// register a JSON schema describing it. The schemas are checked with the
+// subset of JSON Schema genesis formats need: type, enum, properties,
+// required, additionalProperties, items, minimum, maximum, minLength,
+// maxLength, pattern, and the title, description and default of the values
+// the user is prompted for. The keywords combining or referencing schemas
+// are not supported, and are refused when the schema is registered rather
+// than silently ignored.
+
+// GenesisSchema is a JSON schema of the genesis of a custom VM, or of a value
+// in it
+type GenesisSchema struct {
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	// Type is either a type name or a list of them
+	Type                 interface{}               `json:"type,omitempty"`
+	Enum                 []interface{}             `json:"enum,omitempty"`
+	Properties           map[string]*GenesisSchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *bool                     `json:"additionalProperties,omitempty"`
+	Items                *GenesisSchema            `json:"items,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+
+	types   []string
+	pattern *regexp.Regexp
+}
+
+// schemaTypes are the JSON schema types
+var schemaTypes = map[string]struct{}{
+	"object":  {},
+	"array":   {},
+	"string":  {},
+	"number":  {},
+	"integer": {},
+	"boolean": {},
+	"null":    {},
+}
+
+// unsupportedKeywords are the JSON schema keywords GenesisSchema doesn't
+// check
+var unsupportedKeywords = []string{
+	"$ref", "allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+	"patternProperties", "dependencies", "dependentRequired", "dependentSchemas",
+	"prefixItems", "contains", "uniqueItems", "minItems", "maxItems",
+	"exclusiveMinimum", "exclusiveMaximum", "multipleOf", "const", "format",
+}
+
+// ParseGenesisSchema parses a JSON schema of a genesis, failing if it uses
+// keywords which are not supported
+func ParseGenesisSchema(schemaBytes []byte) (*GenesisSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal(schemaBytes, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := checkKeywords(raw, ""); err != nil {
+		return nil, err
+	}
+	var schema GenesisSchema
+	if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if err := schema.compile(""); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// checkKeywords fails if the schema at path uses an unsupported keyword
+func checkKeywords(raw interface{}, path string) error {
+	schema, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: a schema must be an object", schemaPath(path))
+	}
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := schema[keyword]; ok {
+			return fmt.Errorf("%s: keyword %s is not supported", schemaPath(path), keyword)
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			if err := checkKeywords(property, path+"/properties/"+name); err != nil {
+				return err
+			}
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		return checkKeywords(items, path+"/items")
+	}
+	return nil
+}
+
+// compile checks the types and pattern of the schema at path and of its
+// subschemas
+func (s *GenesisSchema) compile(path string) error {
+	switch t := s.Type.(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, name := range t {
+			name, ok := name.(string)
+			if !ok {
+				return fmt.Errorf("%s: type must be a name or a list of names", schemaPath(path))
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return fmt.Errorf("%s: type must be a name or a list of names", schemaPath(path))
+	}
+	for _, name := range s.types {
+		if _, ok := schemaTypes[name]; !ok {
+			return fmt.Errorf("%s: unknown type %q", schemaPath(path), name)
+		}
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", schemaPath(path), err)
+		}
+		s.pattern = pattern
+	}
+	for _, name := range s.Required {
+		if s.Properties[name] == nil && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			return fmt.Errorf("%s: property %s is required but not allowed", schemaPath(path), name)
+		}
+	}
+	for name, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("%s/properties/%s: a schema must be an object", schemaPath(path), name)
+		}
+		if err := property.compile(path + "/properties/" + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile(path + "/items")
+	}
+	return nil
+}
+
+// schemaPath returns the JSON pointer path of a value, / for the root
+func schemaPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// decodeJSONValue decodes a JSON value keeping its numbers as they are written
+func decodeJSONValue(valueBytes []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(valueBytes))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: data after the value")
+	}
+	return value, nil
+}
+
+// Validate returns the violations of the schema by the genesis, as messages
+// prefixed with the JSON pointer path of the value at fault. The genesis is
+// valid if there is none.
+func (s *GenesisSchema) Validate(genesisBytes []byte) ([]string, error) {
+	genesis, err := decodeJSONValue(genesisBytes)
+	if err != nil {
+		return nil, err
+	}
+	return s.validate(genesis, ""), nil
+}
+
+func (s *GenesisSchema) validate(value interface{}, path string) []string {
+	violation := func(format string, args ...interface{}) []string {
+		return []string{schemaPath(path) + ": " + fmt.Sprintf(format, args...)}
+	}
+	if len(s.types) > 0 && !hasType(value, s.types) {
+		return violation("must be %s", typeNames(s.types))
+	}
+	if len(s.Enum) > 0 && !inEnum(value, s.Enum) {
+		return violation("must be one of %s", enumValues(s.Enum))
+	}
+
+	violations := []string{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				violations = append(violations, violation("missing required property %s", name)...)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					violations = append(violations, violation("property %s is not allowed", name)...)
+				}
+				continue
+			}
+			violations = append(violations, property.validate(v[name], path+"/"+name)...)
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				violations = append(violations, s.Items.validate(item, path+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return violation("invalid number %s", v)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			violations = append(violations, violation("must be at least %v", *s.Minimum)...)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			violations = append(violations, violation("must be at most %v", *s.Maximum)...)
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			violations = append(violations, violation("must be at least %d characters long", *s.MinLength)...)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			violations = append(violations, violation("must be at most %d characters long", *s.MaxLength)...)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			violations = append(violations, violation("must match %s", s.Pattern)...)
+		}
+	}
+	return violations
+}
+
+// typeOf returns the JSON schema type of a decoded value, integer for the
+// numbers without a fractional part
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if n, err := v.Float64(); err == nil && n == math.Trunc(n) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "null"
+}
+
+func hasType(value interface{}, types []string) bool {
+	valueType := typeOf(value)
+	for _, t := range types {
+		if t == valueType || (t == "number" && valueType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeNames(types []string) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "object", "array", "integer":
+			names[i] = "an " + t
+		case "null":
+			names[i] = t
+		default:
+			names[i] = "a " + t
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// inEnum returns true if value is one of enum, comparing their encodings
+func inEnum(value interface{}, enum []interface{}) bool {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, option := range enum {
+		if optionEncoded, err := json.Marshal(option); err == nil && bytes.Equal(encoded, optionEncoded) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumValues(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, option := range enum {
+		encoded, _ := json.Marshal(option)
+		values[i] = string(encoded)
+	}
+	return strings.Join(values, ", ")
+}
+
+// FillRequired prompts for the properties the schema requires which are
+// missing in the genesis, objects included, and returns the genesis with
+// them. The genesis is returned as it is if none is missing.
+func (s *GenesisSchema) FillRequired(prompt prompts.Prompter, genesisBytes []byte) ([]byte, error) {
+	genesis, err := decodeJSONValue(genesisBytes)
+	if err != nil {
+		return nil, err
+	}
+	filled, err := s.fillRequired(prompt, genesis, "")
+	if err != nil || !filled {
+		return genesisBytes, err
+	}
+	return json.MarshalIndent(genesis, "", "  ")
+}
+
+func (s *GenesisSchema) fillRequired(prompt prompts.Prompter, value interface{}, path string) (bool, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	filled := false
+	for _, name := range s.Required {
+		if _, ok := object[name]; ok {
+			continue
+		}
+		property := s.Properties[name]
+		if property == nil {
+			property = &GenesisSchema{}
+		}
+		if property.isObject() {
+			object[name] = map[string]interface{}{}
+		} else {
+			v, err := property.promptValue(prompt, path+"/"+name)
+			if err != nil {
+				return false, err
+			}
+			object[name] = v
+		}
+		filled = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, ok := object[name]
+		if !ok {
+			continue
+		}
+		propertyFilled, err := s.Properties[name].fillRequired(prompt, v, path+"/"+name)
+		if err != nil {
+			return false, err
+		}
+		filled = filled || propertyFilled
+	}
+	return filled, nil
+}
+
+// isObject returns true if the values of the schema are objects only, whose
+// required properties can be prompted for
+func (s *GenesisSchema) isObject() bool {
+	return len(s.types) == 1 && s.types[0] == "object" && len(s.Enum) == 0
+}
+
+// promptValue prompts for the value of the schema at path, from its title,
+// description and default
+func (s *GenesisSchema) promptValue(prompt prompts.Prompter, path string) (interface{}, error) {
+	promptStr := "Enter " + path
+	if s.Title != "" {
+		promptStr = "Enter the " + s.Title + " (" + path + ")"
+	}
+	if s.Description != "" {
+		ux.Logger.PrintToUser("%s: %s", path, s.Description)
+	}
+	opts := []prompts.Option{}
+	if s.Default != nil {
+		opts = append(opts, prompts.WithDefault(defaultAnswer(s.Default)))
+	}
+
+	if len(s.Enum) > 0 {
+		options := make([]string, len(s.Enum))
+		for i, option := range s.Enum {
+			options[i] = defaultAnswer(option)
+		}
+		if s.Default != nil && !inEnum(s.Default, s.Enum) {
+			opts = nil
+		}
+		answer, err := prompt.CaptureList(promptStr, options, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for i, option := range options {
+			if option == answer {
+				return s.Enum[i], nil
+			}
+		}
+		return nil, fmt.Errorf("%s: %q is not an option", path, answer)
+	}
+	if len(s.types) == 1 && s.types[0] == "boolean" {
+		if s.Default == false {
+			return prompt.CaptureNoYes(promptStr)
+		}
+		return prompt.CaptureYesNo(promptStr)
+	}
+
+	answer, err := prompt.CaptureString(promptStr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.types) == 1 && s.types[0] == "string" {
+		return answer, nil
+	}
+	// the answers of the other types are JSON, or plain strings
+	value, err := decodeJSONValue([]byte(answer))
+	if err != nil {
+		if hasType(answer, s.types) || len(s.types) == 0 {
+			return answer, nil
+		}
+		return nil, fmt.Errorf("%s: %q is not %s", path, answer, typeNames(s.types))
+	}
+	if len(s.types) > 0 && !hasType(value, s.types) {
+		return nil, fmt.Errorf("%s: %s is not %s", path, answer, typeNames(s.types))
+	}
+	return value, nil
+}
+
+// defaultAnswer returns a value as typed in a prompt: strings as they are,
+// other values as JSON
+func defaultAnswer(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// CheckSchemaVMName fails if vmName can't name the schema of a VM
+func CheckSchemaVMName(vmName string) error {
+	if vmName == "" || filepath.Base(vmName) != vmName || strings.HasPrefix(vmName, ".") {
+		return fmt.Errorf("invalid VM name %q", vmName)
+	}
+	return nil
+}
+
+// LoadGenesisSchema returns the schema registered for the genesis of the
+// custom VM of vmName, or nil if there is none
+func LoadGenesisSchema(app *application.Avalanche, vmName string) (*GenesisSchema, error) {
+	if err := CheckSchemaVMName(vmName); err != nil {
+		return nil, err
+	}
+	path := app.GetGenesisSchemaPath(vmName)
+	if exists, err := storage.FileExists(path); err != nil || !exists {
+		return nil, err
+	}
+	schemaBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := ParseGenesisSchema(schemaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("the genesis schema of %s at %s is invalid: %w", vmName, path, err)
+	}
+	return schema, nil
+}
+
+// SaveGenesisSchema registers schemaBytes as the schema of the genesis of the
+// custom VM of vmName
+func SaveGenesisSchema(app *application.Avalanche, vmName string, schemaBytes []byte) error {
+	if err := CheckSchemaVMName(vmName); err != nil {
+		return err
+	}
+	if _, err := ParseGenesisSchema(schemaBytes); err != nil {
+		return err
+	}
+	path := app.GetGenesisSchemaPath(vmName)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, schemaBytes, application.WriteReadReadPerms)
+}
+
+// ErrGenesisViolatesSchema is returned when a genesis doesn't follow the
+// schema registered for its VM
+var ErrGenesisViolatesSchema = errors.New("the genesis doesn't follow the schema of its VM")
+
+// CheckCustomGenesis checks the genesis of the custom VM of vmName against
+// the schema registered for it, if any, prompting for the required
+// properties it misses first when prompt is given. It returns the genesis
+// with the properties prompted for.
+func CheckCustomGenesis(app *application.Avalanche, vmName string, genesisBytes []byte, prompt prompts.Prompter) ([]byte, error) {
+	schema, err := LoadGenesisSchema(app, vmName)
+	if err != nil || schema == nil {
+		return genesisBytes, err
+	}
+	if prompt != nil {
+		genesisBytes, err = schema.FillRequired(prompt, genesisBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	violations, err := schema.Validate(genesisBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrGenesisViolatesSchema, err)
+	}
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("%w:\n  %s", ErrGenesisViolatesSchema, strings.Join(violations, "\n  "))
+	}
+	return genesisBytes, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/mock"
+)
+
+const testGenesisSchema = `{
+  "type": "object",
+  "required": ["networkID", "owner", "consensus"],
+  "additionalProperties": false,
+  "properties": {
+    "networkID": {"type": "integer", "minimum": 1},
+    "owner": {"type": "string", "title": "owner address", "pattern": "^0x[0-9a-fA-F]{40}$"},
+    "mode": {"enum": ["fast", "safe"]},
+    "consensus": {
+      "type": "object",
+      "required": ["k"],
+      "properties": {"k": {"type": "integer", "default": 20}}
+    },
+    "balances": {"type": "array", "items": {"type": "number", "maximum": 100}}
+  }
+}`
+
+func TestGenesisSchemaValidate(t *testing.T) {
+	assert := setupTest(t)
+
+	schema, err := ParseGenesisSchema([]byte(testGenesisSchema))
+	assert.NoError(err)
+
+	violations, err := schema.Validate([]byte(`{
+		"networkID": 12345,
+		"owner": "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+		"mode": "safe",
+		"consensus": {"k": 20},
+		"balances": [1, 2.5]
+	}`))
+	assert.NoError(err)
+	assert.Empty(violations)
+
+	violations, err = schema.Validate([]byte(`{
+		"networkID": 1.5,
+		"owner": "ewoq",
+		"mode": "fastest",
+		"consensus": {},
+		"balances": [1, 200],
+		"extra": true
+	}`))
+	assert.NoError(err)
+	assert.Equal([]string{
+		"/balances/1: must be at most 100",
+		"/consensus: missing required property k",
+		"/: property extra is not allowed",
+		"/mode: must be one of \"fast\", \"safe\"",
+		"/networkID: must be an integer",
+		"/owner: must match ^0x[0-9a-fA-F]{40}$",
+	}, violations)
+
+	_, err = schema.Validate([]byte(`{`))
+	assert.ErrorContains(err, "invalid JSON")
+}
+
+func TestParseGenesisSchema(t *testing.T) {
+	assert := setupTest(t)
+
+	_, err := ParseGenesisSchema([]byte(`{"properties": {"a": {"oneOf": []}}}`))
+	assert.ErrorContains(err, "/properties/a: keyword oneOf is not supported")
+	_, err = ParseGenesisSchema([]byte(`{"type": "map"}`))
+	assert.ErrorContains(err, `/: unknown type "map"`)
+	_, err = ParseGenesisSchema([]byte(`{"items": {"pattern": "("}}`))
+	assert.ErrorContains(err, "/items: invalid pattern")
+	_, err = ParseGenesisSchema([]byte(`{"required": ["a"], "additionalProperties": false}`))
+	assert.ErrorContains(err, "property a is required but not allowed")
+	_, err = ParseGenesisSchema([]byte(`{"type": ["string", "null"]}`))
+	assert.NoError(err)
+}
+
+func TestGenesisSchemaFillRequired(t *testing.T) {
+	assert := setupTest(t)
+
+	schema, err := ParseGenesisSchema([]byte(testGenesisSchema))
+	assert.NoError(err)
+
+	mockPrompt := &mocks.Prompter{}
+	mockPrompt.On("CaptureString", "Enter /networkID").Return("12345", nil)
+	mockPrompt.On("CaptureString", "Enter the owner address (/owner)").Return("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", nil)
+	mockPrompt.On("CaptureString", "Enter /consensus/k", mock.Anything).Return("20", nil)
+
+	genesisBytes, err := schema.FillRequired(mockPrompt, []byte(`{"mode": "fast"}`))
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"networkID": 12345,
+		"owner": "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+		"mode": "fast",
+		"consensus": {"k": 20}
+	}`, string(genesisBytes))
+	violations, err := schema.Validate(genesisBytes)
+	assert.NoError(err)
+	assert.Empty(violations)
+
+	// a complete genesis is left as it is
+	complete := []byte(`{"networkID": 1, "owner": "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", "consensus": {"k": 1}}`)
+	genesisBytes, err = schema.FillRequired(mockPrompt, complete)
+	assert.NoError(err)
+	assert.Equal(complete, genesisBytes)
+
+	mockPrompt = &mocks.Prompter{}
+	mockPrompt.On("CaptureString", "Enter /networkID").Return("many", nil)
+	_, err = schema.FillRequired(mockPrompt, []byte(`{}`))
+	assert.ErrorContains(err, `/networkID: "many" is not an integer`)
+}
+
+func TestCheckCustomGenesis(t *testing.T) {
+	assert := setupTest(t)
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil)
+
+	genesisBytes := []byte(`{"networkID": 0}`)
+	checked, err := CheckCustomGenesis(app, "myVM", genesisBytes, nil)
+	assert.NoError(err)
+	assert.Equal(genesisBytes, checked)
+
+	assert.ErrorContains(SaveGenesisSchema(app, "myVM", []byte(`{"type": "map"}`)), "unknown type")
+	assert.ErrorContains(SaveGenesisSchema(app, "../myVM", []byte(testGenesisSchema)), "invalid VM name")
+	assert.NoError(SaveGenesisSchema(app, "myVM", []byte(testGenesisSchema)))
+
+	_, err = CheckCustomGenesis(app, "myVM", genesisBytes, nil)
+	assert.ErrorIs(err, ErrGenesisViolatesSchema)
+	assert.ErrorContains(err, "/networkID: must be at least 1")
+	assert.ErrorContains(err, "/: missing required property owner")
+}
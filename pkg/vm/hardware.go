@@ -0,0 +1,111 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/params"
+)
+
+// HardwareProfile is a class of machines the validators of a chain run on,
+// with the gas limit and target gas they keep up with
+type HardwareProfile struct {
+	Name      string
+	CPUs      int
+	MemoryGB  int
+	GasLimit  *big.Int
+	TargetGas *big.Int
+}
+
+// Description returns the machines of the profile, as a user friendly string
+func (p HardwareProfile) Description() string {
+	return fmt.Sprintf("%d vCPU / %d GB", p.CPUs, p.MemoryGB)
+}
+
+// hardwareProfiles are the profiles from the smallest machines to the largest
+var hardwareProfiles = []HardwareProfile{
+	// the machines the C-Chain fees are sized for
+	{
+		Name:      "standard",
+		CPUs:      8,
+		MemoryGB:  16,
+		GasLimit:  big.NewInt(8_000_000),
+		TargetGas: big.NewInt(15_000_000),
+	},
+	{
+		Name:      "performance",
+		CPUs:      16,
+		MemoryGB:  32,
+		GasLimit:  big.NewInt(20_000_000),
+		TargetGas: big.NewInt(50_000_000),
+	},
+}
+
+// HardwareProfiles returns the known hardware profiles, from the smallest
+// machines to the largest
+func HardwareProfiles() []HardwareProfile {
+	return append([]HardwareProfile{}, hardwareProfiles...)
+}
+
+// HardwareProfileNames returns the names of the known hardware profiles
+func HardwareProfileNames() []string {
+	names := []string{}
+	for _, p := range hardwareProfiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// GetHardwareProfile returns the hardware profile of name
+func GetHardwareProfile(name string) (HardwareProfile, error) {
+	for _, p := range hardwareProfiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return HardwareProfile{}, fmt.Errorf("unknown hardware profile %q, must be one of %s", name, strings.Join(HardwareProfileNames(), ", "))
+}
+
+// FeeConfig returns the fee config of the C-Chain with the gas limit and the
+// target gas recommended for the profile
+func (p HardwareProfile) FeeConfig() params.FeeConfig {
+	feeConfig := StarterFeeConfig
+	feeConfig.GasLimit = new(big.Int).Set(p.GasLimit)
+	feeConfig.TargetGas = new(big.Int).Set(p.TargetGas)
+	return feeConfig
+}
+
+// LintHardwareProfile checks the fee config of a chain doesn't let blocks
+// use more gas than the machines of the hardware profile recorded for it,
+// profileName, keep up with. A chain without a profile isn't checked.
+func LintHardwareProfile(config params.ChainConfig, profileName string) []LintFinding {
+	if profileName == "" {
+		return nil
+	}
+	profile, err := GetHardwareProfile(profileName)
+	if err != nil {
+		return []LintFinding{{
+			Severity: LintWarning,
+			Rule:     "hardware-profile",
+			Message:  err.Error(),
+		}}
+	}
+	findings := []LintFinding{}
+	exceeds := func(setting string, value *big.Int, recommended *big.Int) {
+		if value == nil || value.Cmp(recommended) <= 0 {
+			return
+		}
+		findings = append(findings, LintFinding{
+			Severity: LintWarning,
+			Rule:     "hardware-profile",
+			Message: fmt.Sprintf("%s %s exceeds the %s the %s hardware profile (%s) keeps up with",
+				setting, value, recommended, profile.Name, profile.Description()),
+		})
+	}
+	exceeds("gas limit", config.FeeConfig.GasLimit, profile.GasLimit)
+	exceeds("target gas", config.FeeConfig.TargetGas, profile.TargetGas)
+	return findings
+}
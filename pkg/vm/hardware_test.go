@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/mocks"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetFeeConfigHardwareProfile(t *testing.T) {
+	assert := setupTest(t)
+	app := application.New()
+	mockPrompt := &mocks.Prompter{}
+	app.Prompt = mockPrompt
+
+	option := ux.Msgf(ux.MsgFeeHardware, "16 vCPU / 32 GB", "performance")
+	mockPrompt.On("CaptureList", mock.Anything, mock.MatchedBy(func(options []string) bool {
+		for _, o := range options {
+			if o == option {
+				return true
+			}
+		}
+		return false
+	})).Return(option, nil)
+
+	config, profile, direction, err := getFeeConfig(*params.SubnetEVMDefaultChainConfig, app)
+	assert.NoError(err)
+	assert.Equal(forward, direction)
+	assert.Equal("performance", profile)
+	assert.Equal(big.NewInt(20_000_000), config.FeeConfig.GasLimit)
+	assert.Equal(big.NewInt(50_000_000), config.FeeConfig.TargetGas)
+	assert.Equal(StarterFeeConfig.MinBaseFee, config.FeeConfig.MinBaseFee)
+}
+
+func TestLintHardwareProfile(t *testing.T) {
+	assert := setupTest(t)
+
+	standard, err := GetHardwareProfile("standard")
+	assert.NoError(err)
+	config := *params.SubnetEVMDefaultChainConfig
+	config.FeeConfig = standard.FeeConfig()
+	assert.Empty(LintHardwareProfile(config, "standard"))
+	// the fees of a chain without a profile aren't checked
+	config.FeeConfig.GasLimit = big.NewInt(30_000_000)
+	assert.Empty(LintHardwareProfile(config, ""))
+
+	findings := LintHardwareProfile(config, "standard")
+	assert.Len(findings, 1)
+	assert.Equal(LintWarning, findings[0].Severity)
+	assert.Contains(findings[0].Message, "gas limit 30000000 exceeds the 8000000 the standard hardware profile (8 vCPU / 16 GB)")
+
+	config.FeeConfig.TargetGas = big.NewInt(60_000_000)
+	assert.Len(LintHardwareProfile(config, "performance"), 2)
+	assert.Contains(LintHardwareProfile(config, "huge")[0].Message, "unknown hardware profile")
+
+	_, err = GetHardwareProfile("huge")
+	assert.ErrorContains(err, "standard, performance")
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/precompile"
+)
+
+type LintSeverity int
+
+const (
+	LintInfo LintSeverity = iota
+	LintWarning
+	LintError
+)
+
+func (s LintSeverity) String() string {
+	switch s {
+	case LintInfo:
+		return "INFO"
+	case LintWarning:
+		return "WARNING"
+	case LintError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// LintFinding is a single problem found in a subnet configuration
+type LintFinding struct {
+	Severity LintSeverity
+	Rule     string
+	Message  string
+}
+
+const (
+	// gas limits outside of this range are most likely a mistake
+	minSaneGasLimit = 1_000_000
+	maxSaneGasLimit = 100_000_000
+)
+
+// chain IDs used by well known public chains, test tooling or examples.
+// Reusing them exposes users to transaction replay and wallet confusion.
+var wellKnownChainIDs = map[int64]string{
+	1:        "Ethereum Mainnet",
+	3:        "Ropsten",
+	4:        "Rinkeby",
+	5:        "Goerli",
+	1337:     "local development chains",
+	12345:    "examples",
+	31337:    "Hardhat",
+	43112:    "the Avalanche local C-Chain",
+	43113:    "the Avalanche Fuji C-Chain",
+	43114:    "the Avalanche Mainnet C-Chain",
+	11155111: "Sepolia",
+}
+
+// escalate returns the severity on target of a problem which is only
+// acceptable on a local network, and tolerable on fuji
+func escalate(target models.Network) LintSeverity {
+	switch target {
+	case models.Mainnet:
+		return LintError
+	case models.Fuji:
+		return LintWarning
+	}
+	return LintInfo
+}
+
+// LintEvmGenesis checks a subnet EVM genesis for its readiness to be deployed
+// to the target network. Checks are stricter the closer target is to production.
+func LintEvmGenesis(genesis core.Genesis, target models.Network) []LintFinding {
+	findings := []LintFinding{}
+
+	if _, ok := genesis.Alloc[PrefundedEwoqAddress]; ok {
+		findings = append(findings, LintFinding{
+			Severity: escalate(target),
+			Rule:     "ewoq-allocation",
+			Message:  fmt.Sprintf("address %s of the publicly known ewoq key has an airdrop allocation", PrefundedEwoqAddress.Hex()),
+		})
+	}
+	findings = append(findings, lintAllocations(genesis.Alloc, target)...)
+
+	if genesis.Config == nil {
+		return append(findings, LintFinding{
+			Severity: LintError,
+			Rule:     "missing-config",
+			Message:  "genesis has no chain config",
+		})
+	}
+	config := genesis.Config
+
+	if config.ChainID != nil && config.ChainID.IsInt64() {
+		if name, ok := wellKnownChainIDs[config.ChainID.Int64()]; ok {
+			findings = append(findings, LintFinding{
+				Severity: escalate(target),
+				Rule:     "test-chain-id",
+				Message:  fmt.Sprintf("chain ID %s is already used by %s", config.ChainID, name),
+			})
+		}
+	}
+
+	if forks := inactiveForks(config); len(forks) > 0 {
+		severity := LintWarning
+		if target == models.Local {
+			severity = LintInfo
+		}
+		findings = append(findings, LintFinding{
+			Severity: severity,
+			Rule:     "inactive-fork",
+			Message:  fmt.Sprintf("forks %s are not active at genesis, which wallets and tooling may not expect", strings.Join(forks, ", ")),
+		})
+	}
+
+	allowLists := []struct {
+		name   string
+		config precompile.AllowListConfig
+	}{
+		{"Contract deployer allow list", config.ContractDeployerAllowListConfig.AllowListConfig},
+		{"Transaction allow list", config.TxAllowListConfig.AllowListConfig},
+		{"Native minter", config.ContractNativeMinterConfig.AllowListConfig},
+	}
+	for _, allowList := range allowLists {
+		if allowList.config.BlockTimestamp == nil {
+			continue
+		}
+		if len(allowList.config.AllowListAdmins) == 0 {
+			findings = append(findings, LintFinding{
+				Severity: LintWarning,
+				Rule:     "allow-list-no-admin",
+				Message:  fmt.Sprintf("%s is enabled without any admin, it can never be changed", allowList.name),
+			})
+		}
+		if contains(allowList.config.AllowListAdmins, PrefundedEwoqAddress) {
+			findings = append(findings, LintFinding{
+				Severity: escalate(target),
+				Rule:     "ewoq-admin",
+				Message:  fmt.Sprintf("the publicly known ewoq key is an admin of the %s", allowList.name),
+			})
+		}
+	}
+	if config.ContractDeployerAllowListConfig.BlockTimestamp == nil && target == models.Mainnet {
+		findings = append(findings, LintFinding{
+			Severity: LintInfo,
+			Rule:     "permissive-deployment",
+			Message:  "no contract deployer allow list is set, anybody can deploy contracts",
+		})
+	}
+
+	gasLimit := config.FeeConfig.GasLimit
+	if gasLimit != nil &&
+		(gasLimit.Cmp(big.NewInt(minSaneGasLimit)) < 0 || gasLimit.Cmp(big.NewInt(maxSaneGasLimit)) > 0) {
+		severity := LintWarning
+		if target == models.Mainnet {
+			severity = LintError
+		}
+		findings = append(findings, LintFinding{
+			Severity: severity,
+			Rule:     "gas-limit",
+			Message: fmt.Sprintf("gas limit %s is outside the sane range of %d to %d",
+				gasLimit, minSaneGasLimit, maxSaneGasLimit),
+		})
+	}
+	minBaseFee := config.FeeConfig.MinBaseFee
+	if minBaseFee != nil && minBaseFee.Sign() == 0 {
+		findings = append(findings, LintFinding{
+			Severity: escalate(target),
+			Rule:     "zero-base-fee",
+			Message:  "min base fee is zero, the chain can be spammed for free",
+		})
+	}
+
+	return findings
+}
+
+// HasLintErrors returns true if any of the findings is an error
+func HasLintErrors(findings []LintFinding) bool {
+	for _, f := range findings {
+		if f.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
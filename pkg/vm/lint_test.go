@@ -0,0 +1,160 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func lintRules(findings []LintFinding) map[string]LintSeverity {
+	rules := map[string]LintSeverity{}
+	for _, f := range findings {
+		rules[f.Rule] = f.Severity
+	}
+	return rules
+}
+
+func TestLintEvmGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	someAddress := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	clean := func() core.Genesis {
+		config := *params.SubnetEVMDefaultChainConfig
+		config.ChainID = big.NewInt(888777)
+		config.FeeConfig = StarterFeeConfig
+		config.ContractDeployerAllowListConfig = precompile.ContractDeployerAllowListConfig{
+			AllowListConfig: precompile.AllowListConfig{
+				BlockTimestamp:  big.NewInt(0),
+				AllowListAdmins: []common.Address{someAddress},
+			},
+		}
+		return core.Genesis{
+			Config: &config,
+			Alloc:  core.GenesisAlloc{someAddress: {Balance: big.NewInt(1)}},
+		}
+	}
+
+	type test struct {
+		name     string
+		modify   func(*core.Genesis)
+		target   models.Network
+		expected map[string]LintSeverity
+	}
+	tests := []test{
+		{
+			name:     "clean",
+			modify:   func(*core.Genesis) {},
+			target:   models.Mainnet,
+			expected: map[string]LintSeverity{},
+		},
+		{
+			name: "ewoq allocation on mainnet",
+			modify: func(g *core.Genesis) {
+				g.Alloc[PrefundedEwoqAddress] = core.GenesisAccount{Balance: big.NewInt(1)}
+			},
+			target:   models.Mainnet,
+			expected: map[string]LintSeverity{"ewoq-allocation": LintError},
+		},
+		{
+			name: "ewoq allocation on fuji",
+			modify: func(g *core.Genesis) {
+				g.Alloc[PrefundedEwoqAddress] = core.GenesisAccount{Balance: big.NewInt(1)}
+			},
+			target:   models.Fuji,
+			expected: map[string]LintSeverity{"ewoq-allocation": LintWarning},
+		},
+		{
+			name: "burnt and precompile allocations",
+			modify: func(g *core.Genesis) {
+				g.Alloc[common.Address{}] = core.GenesisAccount{Balance: big.NewInt(1)}
+				g.Alloc[common.HexToAddress("0x0000000000000000000000000000000000000005")] = core.GenesisAccount{Balance: big.NewInt(1)}
+			},
+			target:   models.Mainnet,
+			expected: map[string]LintSeverity{"unspendable-allocation": LintError},
+		},
+		{
+			name: "ewoq admin and admin-less allow list",
+			modify: func(g *core.Genesis) {
+				g.Config.ContractNativeMinterConfig.BlockTimestamp = big.NewInt(0)
+				g.Config.ContractNativeMinterConfig.AllowListAdmins = []common.Address{PrefundedEwoqAddress}
+				g.Config.TxAllowListConfig.BlockTimestamp = big.NewInt(0)
+			},
+			target: models.Mainnet,
+			expected: map[string]LintSeverity{
+				"ewoq-admin":          LintError,
+				"allow-list-no-admin": LintWarning,
+			},
+		},
+		{
+			name: "test chain id and absurd gas limit",
+			modify: func(g *core.Genesis) {
+				g.Config.ChainID = big.NewInt(43114)
+				g.Config.FeeConfig.GasLimit = big.NewInt(1_000_000_000)
+			},
+			target: models.Mainnet,
+			expected: map[string]LintSeverity{
+				"test-chain-id": LintError,
+				"gas-limit":     LintError,
+			},
+		},
+		{
+			name: "permissive deployment",
+			modify: func(g *core.Genesis) {
+				g.Config.ContractDeployerAllowListConfig = precompile.ContractDeployerAllowListConfig{}
+			},
+			target:   models.Mainnet,
+			expected: map[string]LintSeverity{"permissive-deployment": LintInfo},
+		},
+		{
+			name: "inactive fork",
+			modify: func(g *core.Genesis) {
+				g.Config.MuirGlacierBlock = nil
+			},
+			target:   models.Fuji,
+			expected: map[string]LintSeverity{"inactive-fork": LintWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			genesis := clean()
+			tt.modify(&genesis)
+			findings := LintEvmGenesis(genesis, tt.target)
+			assert.Equal(tt.expected, lintRules(findings))
+			hasError := false
+			for _, s := range tt.expected {
+				if s == LintError {
+					hasError = true
+				}
+			}
+			assert.Equal(hasError, HasLintErrors(findings))
+		})
+	}
+}
+
+func TestLintAllocationChecksums(t *testing.T) {
+	assert := assert.New(t)
+
+	genesis := []byte(`{"alloc": {
+		"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC": {"balance": "0x1"},
+		"8db97c7cece249c2b98bdc0226cc4c2a57bf52fc": {"balance": "0x1"},
+		"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52Fc": {"balance": "0x1"}
+	}}`)
+	findings, err := LintAllocationChecksums(genesis, models.Mainnet)
+	assert.NoError(err)
+	assert.Len(findings, 1)
+	assert.Equal("address-checksum", findings[0].Rule)
+	assert.Equal(LintError, findings[0].Severity)
+	assert.Contains(findings[0].Message, "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52Fc")
+
+	assert.ErrorIs(CheckAddressChecksum("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52Fc"), ErrAddressChecksum)
+	assert.NoError(CheckAddressChecksum("0x8DB97C7CECE249C2B98BDC0226CC4C2A57BF52FC"))
+}
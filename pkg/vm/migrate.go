@@ -0,0 +1,301 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/coreos/go-semver/semver"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// genesisMigration is a change of the genesis format, expected by the
+// subnet-evm versions from version on
+type genesisMigration struct {
+	version     string
+	description string
+	// apply migrates the genesis in place and returns whether it changed it
+	apply func(genesis map[string]interface{}) (bool, error)
+}
+
+// genesisMigrations are the changes of the genesis format, in the order they
+// were introduced
+var genesisMigrations = []genesisMigration{
+	{
+		version:     "v0.1.2",
+		description: "the contract deployer allow list is configured as contractDeployerAllowListConfig instead of allowListConfig",
+		apply:       renameConfigField("allowListConfig", "contractDeployerAllowListConfig"),
+	},
+	{
+		version:     "v0.2.0",
+		description: "the fee config is a required section of the chain config",
+		apply:       addFeeConfig,
+	},
+	{
+		version:     "v0.2.0",
+		description: "the gas limit of the genesis block must match the one of the fee config",
+		apply:       alignGasLimit,
+	},
+}
+
+// GenesisMigration is the result of upgrading a genesis to the format
+// expected by a subnet-evm version
+type GenesisMigration struct {
+	// Genesis is the upgraded genesis
+	Genesis []byte
+	// Applied describes the format changes applied to the genesis, empty if
+	// it already had the expected format
+	Applied []string
+	// Diff lists the removed (-) and added (+) values by JSON path
+	Diff []string
+}
+
+// MigrateGenesis upgrades a subnet-evm genesis to the format expected by
+// subnetEVMVersion, renaming fields and adding the required sections it
+// lacks
+func MigrateGenesis(genesisBytes []byte, subnetEVMVersion string) (GenesisMigration, error) {
+	version, err := semver.NewVersion(strings.TrimPrefix(subnetEVMVersion, "v"))
+	if err != nil {
+		return GenesisMigration{}, fmt.Errorf("invalid subnet-evm version %q: %w", subnetEVMVersion, err)
+	}
+	original, err := decodeGenesis(genesisBytes)
+	if err != nil {
+		return GenesisMigration{}, err
+	}
+	genesis, err := decodeGenesis(genesisBytes)
+	if err != nil {
+		return GenesisMigration{}, err
+	}
+
+	migration := GenesisMigration{Genesis: genesisBytes, Applied: []string{}, Diff: []string{}}
+	for _, m := range genesisMigrations {
+		if version.LessThan(*semver.New(strings.TrimPrefix(m.version, "v"))) {
+			continue
+		}
+		changed, err := m.apply(genesis)
+		if err != nil {
+			return GenesisMigration{}, fmt.Errorf("failed upgrading the genesis to the format of subnet-evm %s: %w", m.version, err)
+		}
+		if changed {
+			migration.Applied = append(migration.Applied, fmt.Sprintf("%s: %s", m.version, m.description))
+		}
+	}
+	if len(migration.Applied) == 0 {
+		return migration, nil
+	}
+
+	migrated, err := json.MarshalIndent(genesis, "", "    ")
+	if err != nil {
+		return GenesisMigration{}, err
+	}
+	// the upgraded genesis must be understood by the subnet-evm version this
+	// tool is built with
+	var evmGenesis core.Genesis
+	if err := json.Unmarshal(migrated, &evmGenesis); err != nil {
+		return GenesisMigration{}, fmt.Errorf("upgraded genesis is invalid: %w", err)
+	}
+	migration.Genesis = migrated
+	diffGenesis("", original, genesis, &migration.Diff)
+	return migration, nil
+}
+
+// DiffGenesis returns the differences between two genesis, by path, as the
+// lines of the values removed from old prefixed with "-" and the ones added
+// to it prefixed with "+". The formatting and key order of both is ignored.
+func DiffGenesis(old, updated []byte) ([]string, error) {
+	oldGenesis, err := decodeGenesis(old)
+	if err != nil {
+		return nil, err
+	}
+	updatedGenesis, err := decodeGenesis(updated)
+	if err != nil {
+		return nil, err
+	}
+	diff := []string{}
+	diffGenesis("", oldGenesis, updatedGenesis, &diff)
+	return diff, nil
+}
+
+func decodeGenesis(genesisBytes []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(genesisBytes))
+	// keep big numbers like balances and fees exact
+	decoder.UseNumber()
+	var genesis map[string]interface{}
+	if err := decoder.Decode(&genesis); err != nil {
+		return nil, fmt.Errorf("genesis is not a valid JSON object: %w", err)
+	}
+	return genesis, nil
+}
+
+// chainConfig returns the chain config of genesis, creating it if missing
+func chainConfig(genesis map[string]interface{}) (map[string]interface{}, error) {
+	config, ok := genesis["config"]
+	if !ok {
+		config = map[string]interface{}{}
+		genesis["config"] = config
+	}
+	configMap, ok := config.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("config is not a JSON object")
+	}
+	return configMap, nil
+}
+
+func renameConfigField(from, to string) func(map[string]interface{}) (bool, error) {
+	return func(genesis map[string]interface{}) (bool, error) {
+		config, err := chainConfig(genesis)
+		if err != nil {
+			return false, err
+		}
+		value, ok := config[from]
+		if !ok {
+			return false, nil
+		}
+		if _, ok := config[to]; ok {
+			return false, fmt.Errorf("config has both %s and %s", from, to)
+		}
+		config[to] = value
+		delete(config, from)
+		return true, nil
+	}
+}
+
+// addFeeConfig adds the C-Chain fee config, with the gas limit of the genesis
+// block if it has one
+func addFeeConfig(genesis map[string]interface{}) (bool, error) {
+	config, err := chainConfig(genesis)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := config["feeConfig"]; ok {
+		return false, nil
+	}
+	feeConfig := StarterFeeConfig
+	if gasLimit, ok := genesis["gasLimit"]; ok {
+		limit, err := jsonBigInt(gasLimit)
+		if err != nil {
+			return false, fmt.Errorf("invalid gasLimit: %w", err)
+		}
+		feeConfig.GasLimit = limit
+	}
+	feeConfigBytes, err := json.Marshal(feeConfig)
+	if err != nil {
+		return false, err
+	}
+	feeConfigMap, err := decodeGenesis(feeConfigBytes)
+	if err != nil {
+		return false, err
+	}
+	config["feeConfig"] = feeConfigMap
+	return true, nil
+}
+
+// alignGasLimit sets the gas limit of the genesis block to the one of the fee
+// config
+func alignGasLimit(genesis map[string]interface{}) (bool, error) {
+	config, err := chainConfig(genesis)
+	if err != nil {
+		return false, err
+	}
+	feeConfig, ok := config["feeConfig"].(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	feeGasLimit, ok := feeConfig["gasLimit"]
+	if !ok {
+		return false, nil
+	}
+	expected, err := jsonBigInt(feeGasLimit)
+	if err != nil {
+		return false, fmt.Errorf("invalid feeConfig.gasLimit: %w", err)
+	}
+	if gasLimit, ok := genesis["gasLimit"]; ok {
+		current, err := jsonBigInt(gasLimit)
+		if err != nil {
+			return false, fmt.Errorf("invalid gasLimit: %w", err)
+		}
+		if current.Cmp(expected) == 0 {
+			return false, nil
+		}
+	}
+	genesis["gasLimit"] = hexutil.EncodeBig(expected)
+	return true, nil
+}
+
+// jsonBigInt parses a JSON number, or a string holding a hex or decimal number
+func jsonBigInt(v interface{}) (*big.Int, error) {
+	var s string
+	switch value := v.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = value
+	default:
+		return nil, fmt.Errorf("expected a number, got %v", v)
+	}
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+	n, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return nil, fmt.Errorf("expected a number, got %q", s)
+	}
+	return n, nil
+}
+
+// diffGenesis walks both JSON values and records, by path, the values removed
+// from and added to the old one
+func diffGenesis(path string, old, updated interface{}, diff *[]string) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	updatedMap, updatedIsMap := updated.(map[string]interface{})
+	if oldIsMap && updatedIsMap {
+		keys := []string{}
+		for k := range oldMap {
+			keys = append(keys, k)
+		}
+		for k := range updatedMap {
+			if _, ok := oldMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffGenesis(joinPath(path, k), oldMap[k], updatedMap[k], diff)
+		}
+		return
+	}
+	if reflect.DeepEqual(old, updated) {
+		return
+	}
+	if old != nil {
+		*diff = append(*diff, fmt.Sprintf("- %s: %s", path, compactJSON(old)))
+	}
+	if updated != nil {
+		*diff = append(*diff, fmt.Sprintf("+ %s: %s", path, compactJSON(updated)))
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
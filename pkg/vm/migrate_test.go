@@ -0,0 +1,73 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// genesis in the format of subnet-evm v0.1.x
+const legacyGenesis = `{
+	"config": {
+		"chainId": 12345,
+		"homesteadBlock": 0,
+		"allowListConfig": {"blockTimestamp": 0, "adminAddresses": ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]}
+	},
+	"alloc": {"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC": {"balance": "0x52B7D2DCC80CD2E4000000"}},
+	"gasLimit": "0x1312D00",
+	"difficulty": "0x0"
+}`
+
+func TestMigrateGenesis(t *testing.T) {
+	assert := assert.New(t)
+
+	migration, err := MigrateGenesis([]byte(legacyGenesis), "v0.2.3")
+	assert.NoError(err)
+	assert.Len(migration.Applied, 2)
+	assert.Contains(migration.Diff, `- config.allowListConfig: {"adminAddresses":["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"],"blockTimestamp":0}`)
+	assert.Contains(migration.Diff, `+ config.contractDeployerAllowListConfig: {"adminAddresses":["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"],"blockTimestamp":0}`)
+
+	var genesis core.Genesis
+	assert.NoError(json.Unmarshal(migration.Genesis, &genesis))
+	assert.Equal(big.NewInt(12345), genesis.Config.ChainID)
+	assert.Equal(big.NewInt(0), genesis.Config.ContractDeployerAllowListConfig.BlockTimestamp)
+	// the fee config keeps the gas limit of the genesis block
+	assert.Equal(big.NewInt(20_000_000), genesis.Config.FeeConfig.GasLimit)
+	assert.Equal(StarterFeeConfig.MinBaseFee, genesis.Config.FeeConfig.MinBaseFee)
+	assert.Equal(uint64(20_000_000), genesis.GasLimit)
+	// big balances are kept exact
+	for _, account := range genesis.Alloc {
+		assert.Equal("100000000000000000000000000", account.Balance.String())
+	}
+
+	// upgrading again changes nothing
+	again, err := MigrateGenesis(migration.Genesis, "v0.2.3")
+	assert.NoError(err)
+	assert.Empty(again.Applied)
+	assert.Equal(migration.Genesis, again.Genesis)
+
+	// older versions only get the changes they expect
+	old, err := MigrateGenesis([]byte(legacyGenesis), "v0.1.2")
+	assert.NoError(err)
+	assert.Len(old.Applied, 1)
+}
+
+func TestMigrateGenesisGasLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	migration, err := MigrateGenesis([]byte(`{"config": {"chainId": 1, "feeConfig": {"gasLimit": 8000000}}, "alloc": {}, "gasLimit": "0x1312D00", "difficulty": "0x0"}`), "v0.2.3")
+	assert.NoError(err)
+	assert.Equal([]string{`- gasLimit: "0x1312D00"`, `+ gasLimit: "0x7a1200"`}, migration.Diff)
+
+	_, err = MigrateGenesis([]byte(`{"config": {"allowListConfig": {}, "contractDeployerAllowListConfig": {}}}`), "v0.2.3")
+	assert.ErrorContains(err, "both allowListConfig and contractDeployerAllowListConfig")
+
+	_, err = MigrateGenesis([]byte(`{}`), "latest")
+	assert.ErrorContains(err, "invalid subnet-evm version")
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// MaxMnemonicAddresses bounds how many addresses of a mnemonic get an airdrop
+const MaxMnemonicAddresses = 1000
+
+// ethereumCoinType is the BIP44 coin type of the C-Chain addresses derived by
+// wallets such as Metamask
+const ethereumCoinType = 60
+
+// DeriveMnemonicAddresses returns the first count C-Chain addresses of the
+// BIP39 mnemonic, along the BIP44 path m/44'/60'/0'/0/i wallets use for
+// their accounts
+func DeriveMnemonicAddresses(mnemonic string, count uint64) ([]common.Address, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("invalid mnemonic: it should be 12 to 24 words of the BIP39 english word list")
+	}
+	if count == 0 || count > MaxMnemonicAddresses {
+		return nil, fmt.Errorf("invalid number of addresses %d, expected 1 to %d", count, MaxMnemonicAddresses)
+	}
+	master, err := hdkeychain.NewMaster(bip39.NewSeed(mnemonic, ""), &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, err
+	}
+	account := master
+	for _, i := range []uint32{
+		hdkeychain.HardenedKeyStart + 44,
+		hdkeychain.HardenedKeyStart + ethereumCoinType,
+		hdkeychain.HardenedKeyStart,
+		0,
+	} {
+		if account, err = account.Derive(i); err != nil {
+			return nil, err
+		}
+	}
+	addrs := make([]common.Address, count)
+	for i := range addrs {
+		child, err := account.Derive(uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		privKey, err := child.ECPrivKey()
+		if err != nil {
+			return nil, err
+		}
+		ecdsaKey, err := crypto.ToECDSA(privKey.Serialize())
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = crypto.PubkeyToAddress(ecdsaKey.PublicKey)
+	}
+	return addrs, nil
+}
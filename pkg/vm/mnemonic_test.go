@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// the well known mnemonic of the hardhat and anvil test accounts
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveMnemonicAddresses(t *testing.T) {
+	assert := assert.New(t)
+
+	addrs, err := DeriveMnemonicAddresses(testMnemonic, 3)
+	assert.NoError(err)
+	assert.Equal([]common.Address{
+		common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+	}, addrs)
+
+	// extra whitespace is ignored
+	addrs, err = DeriveMnemonicAddresses("  test test test test test test\ntest test test test test junk ", 1)
+	assert.NoError(err)
+	assert.Equal(common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), addrs[0])
+
+	_, err = DeriveMnemonicAddresses("test test test test test test test test test test test avalanche", 1)
+	assert.Error(err)
+	_, err = DeriveMnemonicAddresses(testMnemonic, 0)
+	assert.Error(err)
+	_, err = DeriveMnemonicAddresses(testMnemonic, MaxMnemonicAddresses+1)
+	assert.Error(err)
+}
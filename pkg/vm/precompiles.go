@@ -4,12 +4,16 @@
 package vm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile"
 	"github.com/ethereum/go-ethereum/common"
@@ -25,13 +29,14 @@ func contains(list []common.Address, element common.Address) bool {
 }
 
 func getAdminList(initialPrompt string, info string, app *application.Avalanche) ([]common.Address, bool, error) {
-	const (
-		addAdmin    = "Add admin"
-		removeAdmin = "Remove admin"
-		preview     = "Preview"
-		moreInfo    = "More info"
-		doneMsg     = "Done"
-		cancelMsg   = "Cancel"
+	var (
+		addAdmin     = ux.Msg(ux.MsgAddAdmin)
+		importAdmins = ux.Msg(ux.MsgImportAdmins)
+		removeAdmin  = ux.Msg(ux.MsgRemoveAdmin)
+		preview      = ux.Msg(ux.MsgPreview)
+		moreInfo     = ux.Msg(ux.MsgMoreInfo)
+		doneMsg      = ux.Msg(ux.MsgDone)
+		cancelMsg    = ux.Msg(ux.MsgCancel)
 	)
 
 	admins := []common.Address{}
@@ -39,7 +44,7 @@ func getAdminList(initialPrompt string, info string, app *application.Avalanche)
 	for {
 		listDecision, err := app.Prompt.CaptureList(
 			initialPrompt,
-			[]string{addAdmin, removeAdmin, preview, moreInfo, doneMsg, cancelMsg},
+			[]string{addAdmin, importAdmins, removeAdmin, preview, moreInfo, doneMsg, cancelMsg},
 		)
 		if err != nil {
 			return []common.Address{}, false, err
@@ -47,31 +52,50 @@ func getAdminList(initialPrompt string, info string, app *application.Avalanche)
 
 		switch listDecision {
 		case addAdmin:
-			adminAddr, err := app.Prompt.CaptureAddress("Admin Address")
+			adminAddr, err := app.Prompt.CaptureAddress(ux.Msg(ux.MsgAdminAddress))
 			if err != nil {
 				return []common.Address{}, false, err
 			}
 			if contains(admins, adminAddr) {
-				fmt.Println("Address already an admin")
+				ux.Logger.PrintToUser(ux.Msg(ux.MsgAlreadyAdmin))
 				continue
 			}
 			admins = append(admins, adminAddr)
+		case importAdmins:
+			path, err := app.Prompt.CaptureExistingFilepath(ux.Msg(ux.MsgAdminsFilePath))
+			if err != nil {
+				return []common.Address{}, false, err
+			}
+			imported, err := LoadAddressList(path)
+			if err != nil {
+				ux.Logger.PrintToUser("%s", err)
+				continue
+			}
+			duplicates := 0
+			for _, addr := range imported {
+				if contains(admins, addr) {
+					duplicates++
+					continue
+				}
+				admins = append(admins, addr)
+			}
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgAdminsImported), len(imported)-duplicates, duplicates)
 		case removeAdmin:
-			index, err := app.Prompt.CaptureIndex("Choose address to remove:", admins)
+			index, err := app.Prompt.CaptureIndex(ux.Msg(ux.MsgChooseAddressToRemove), admins)
 			if err != nil {
 				return []common.Address{}, false, err
 			}
 			admins = append(admins[:index], admins[index+1:]...)
 		case preview:
-			fmt.Println("Admins:")
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgAdmins))
 			for i, addr := range admins {
-				fmt.Printf("%d. %s\n", i, addr.Hex())
+				ux.Logger.PrintToUser("%d. %s", i, addr.Hex())
 			}
 		case doneMsg:
 			cancelled := len(admins) == 0
 			return admins, cancelled, nil
 		case moreInfo:
-			fmt.Print(info)
+			fmt.Print(ux.ToOutput(info))
 		case cancelMsg:
 			return []common.Address{}, true, nil
 		default:
@@ -82,10 +106,8 @@ func getAdminList(initialPrompt string, info string, app *application.Avalanche)
 
 func configureContractAllowList(app *application.Avalanche) (precompile.ContractDeployerAllowListConfig, bool, error) {
 	config := precompile.ContractDeployerAllowListConfig{}
-	prompt := "Configure contract deployment allow list"
-	info := "\nThis precompile restricts who has the ability to deploy contracts " +
-		"on your subnet.\nFor more information visit " +
-		"https://docs.avax.network/subnets/customize-a-subnet/#restricting-smart-contract-deployers\n\n"
+	prompt := ux.Msg(ux.MsgContractAllowListPrompt)
+	info := ux.Msg(ux.MsgContractAllowListInfo)
 
 	admins, cancelled, err := getAdminList(prompt, info, app)
 	if err != nil {
@@ -102,10 +124,8 @@ func configureContractAllowList(app *application.Avalanche) (precompile.Contract
 
 func configureTransactionAllowList(app *application.Avalanche) (precompile.TxAllowListConfig, bool, error) {
 	config := precompile.TxAllowListConfig{}
-	prompt := "Configure transaction allow list"
-	info := "\nThis precompile restricts who has the ability to issue transactions " +
-		"on your subnet.\nFor more information visit " +
-		"https://docs.avax.network/subnets/customize-a-subnet/#restricting-who-can-submit-transactions\n\n"
+	prompt := ux.Msg(ux.MsgTxAllowListPrompt)
+	info := ux.Msg(ux.MsgTxAllowListInfo)
 
 	admins, cancelled, err := getAdminList(prompt, info, app)
 	if err != nil {
@@ -122,10 +142,8 @@ func configureTransactionAllowList(app *application.Avalanche) (precompile.TxAll
 
 func configureMinterList(app *application.Avalanche) (precompile.ContractNativeMinterConfig, bool, error) {
 	config := precompile.ContractNativeMinterConfig{}
-	prompt := "Configure native minting allow list"
-	info := "\nThis precompile allows admins to permit designated contracts to mint the native token " +
-		"on your subnet.\nFor more information visit " +
-		"https://docs.avax.network/subnets/customize-a-subnet#minting-native-coins\n\n"
+	prompt := ux.Msg(ux.MsgMinterListPrompt)
+	info := ux.Msg(ux.MsgMinterListInfo)
 
 	admins, cancelled, err := getAdminList(prompt, info, app)
 	if err != nil {
@@ -140,6 +158,47 @@ func configureMinterList(app *application.Avalanche) (precompile.ContractNativeM
 	return config, cancelled, nil
 }
 
+// configurePrivateChain configures both the contract deployment and the
+// transaction allow lists with the same admins, so that only allow-listed
+// addresses deploy contracts and issue transactions on the chain
+func configurePrivateChain(app *application.Avalanche) (precompile.ContractDeployerAllowListConfig, precompile.TxAllowListConfig, bool, error) {
+	prompt := ux.Msg(ux.MsgPrivateChainAdminsPrompt)
+	info := ux.Msg(ux.MsgPrivateChainInfo)
+
+	admins, cancelled, err := getAdminList(prompt, info, app)
+	if err != nil || cancelled {
+		return precompile.ContractDeployerAllowListConfig{}, precompile.TxAllowListConfig{}, cancelled, err
+	}
+
+	allowList := precompile.AllowListConfig{
+		BlockTimestamp:  big.NewInt(0),
+		AllowListAdmins: admins,
+	}
+	return precompile.ContractDeployerAllowListConfig{AllowListConfig: allowList},
+		precompile.TxAllowListConfig{AllowListConfig: allowList},
+		false, nil
+}
+
+// notTxAllowListed returns the funded accounts of allocation which the
+// transaction allow list of config, if enabled, doesn't start with
+func notTxAllowListed(config params.ChainConfig, allocation core.GenesisAlloc) []common.Address {
+	if config.TxAllowListConfig.BlockTimestamp == nil {
+		return nil
+	}
+	addresses := []common.Address{}
+	for address, account := range allocation {
+		// predeployed contracts don't issue transactions
+		if len(account.Code) > 0 || contains(config.TxAllowListConfig.AllowListAdmins, address) {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+	return addresses
+}
+
 func removePrecompile(arr []string, s string) ([]string, error) {
 	for i, val := range arr {
 		if val == s {
@@ -149,21 +208,47 @@ func removePrecompile(arr []string, s string) ([]string, error) {
 	return arr, errors.New("string not in array")
 }
 
+// getPrecompiles asks for the precompiles of the chain
 func getPrecompiles(config params.ChainConfig, app *application.Avalanche) (params.ChainConfig, stateDirection, error) {
-	const (
-		nativeMint        = "Native Minting"
-		contractAllowList = "Contract deployment whitelist"
-		txAllowList       = "Transaction allow list"
-		cancel            = "Cancel"
+	var (
+		nativeMint        = ux.Msg(ux.MsgNativeMint)
+		contractAllowList = ux.Msg(ux.MsgContractAllowList)
+		txAllowList       = ux.Msg(ux.MsgTxAllowList)
+		cancel            = ux.Msg(ux.MsgCancel)
+		goBackMsg         = ux.Msg(ux.MsgGoBack)
 	)
 
 	first := true
 
 	remainingPrecompiles := []string{nativeMint, contractAllowList, txAllowList, cancel}
 
+	privateChain, err := app.Prompt.CaptureList(ux.Msg(ux.MsgPrivateChainPrompt), []string{prompts.No, prompts.Yes, goBackMsg})
+	if err != nil {
+		return config, stop, err
+	}
+	switch privateChain {
+	case goBackMsg:
+		return config, backward, nil
+	case prompts.Yes:
+		contractConfig, txConfig, cancelled, err := configurePrivateChain(app)
+		if err != nil {
+			return config, stop, err
+		}
+		if !cancelled {
+			config.ContractDeployerAllowListConfig = contractConfig
+			config.TxAllowListConfig = txConfig
+			for _, p := range []string{contractAllowList, txAllowList} {
+				remainingPrecompiles, err = removePrecompile(remainingPrecompiles, p)
+				if err != nil {
+					return config, stop, err
+				}
+			}
+		}
+	}
+
 	for {
-		firstStr := "Advanced: Would you like to add a custom precompile to modify the EVM?"
-		secondStr := "Would you like to add additional precompiles?"
+		firstStr := ux.Msg(ux.MsgAddFirstPrecompile)
+		secondStr := ux.Msg(ux.MsgAddMorePrecompiles)
 
 		var promptStr string
 		if promptStr = secondStr; first {
@@ -184,7 +269,7 @@ func getPrecompiles(config params.ChainConfig, app *application.Avalanche) (para
 		}
 
 		precompileDecision, err := app.Prompt.CaptureList(
-			"Choose precompile",
+			ux.Msg(ux.MsgChoosePrecompile),
 			remainingPrecompiles,
 		)
 		if err != nil {
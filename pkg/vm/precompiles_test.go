@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotTxAllowListed(t *testing.T) {
+	assert := assert.New(t)
+
+	admin := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	user := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	contract := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	allocation := core.GenesisAlloc{
+		admin:    {Balance: big.NewInt(1)},
+		user:     {Balance: big.NewInt(1)},
+		contract: {Balance: big.NewInt(0), Code: []byte{0x00}},
+	}
+
+	config := *params.SubnetEVMDefaultChainConfig
+	assert.Empty(notTxAllowListed(config, allocation))
+
+	config.TxAllowListConfig = precompile.TxAllowListConfig{
+		AllowListConfig: precompile.AllowListConfig{
+			BlockTimestamp:  big.NewInt(0),
+			AllowListAdmins: []common.Address{admin},
+		},
+	}
+	assert.Equal([]common.Address{user}, notTxAllowListed(config, allocation))
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// predeploy is a contract to deploy in the genesis, as listed in a predeploys file
+type predeploy struct {
+	Address string            `json:"address"`
+	Code    string            `json:"code"`
+	Storage map[string]string `json:"storage,omitempty"`
+	Balance string            `json:"balance,omitempty"`
+}
+
+// precompiled contracts of subnet-evm live at addresses with this prefix
+var precompileAddressPrefix = []byte{0x02}
+
+// LoadPredeploys reads a predeploys file, a JSON array of contracts with their
+// address, runtime bytecode, storage slots and optional balance, e.g.
+//
+//	[{"address": "0x...", "code": "0x6080...", "storage": {"0x00": "0x01"}, "balance": "0x0"}]
+//
+// and returns them as genesis accounts
+func LoadPredeploys(path string) (core.GenesisAlloc, error) {
+	predeploysBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var predeploys []predeploy
+	if err := json.Unmarshal(predeploysBytes, &predeploys); err != nil {
+		return nil, fmt.Errorf("invalid predeploys file %s: %w", path, err)
+	}
+	alloc := core.GenesisAlloc{}
+	for i, p := range predeploys {
+		address, account, err := parsePredeploy(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid predeploy #%d in %s: %w", i+1, path, err)
+		}
+		if _, ok := alloc[address]; ok {
+			return nil, fmt.Errorf("invalid predeploy #%d in %s: address %s is listed twice", i+1, path, address.Hex())
+		}
+		alloc[address] = account
+	}
+	return alloc, nil
+}
+
+func parsePredeploy(p predeploy) (common.Address, core.GenesisAccount, error) {
+	if !common.IsHexAddress(p.Address) {
+		return common.Address{}, core.GenesisAccount{}, fmt.Errorf("invalid address %q", p.Address)
+	}
+	address := common.HexToAddress(p.Address)
+	if bytes.HasPrefix(address.Bytes(), precompileAddressPrefix) {
+		return common.Address{}, core.GenesisAccount{}, fmt.Errorf("address %s is reserved for precompiles", address.Hex())
+	}
+
+	code, err := hexutil.Decode(p.Code)
+	if err != nil {
+		return common.Address{}, core.GenesisAccount{}, fmt.Errorf("invalid code of %s: %w", address.Hex(), err)
+	}
+	switch {
+	case len(code) == 0:
+		return common.Address{}, core.GenesisAccount{}, fmt.Errorf("empty code for %s", address.Hex())
+	case len(code) > params.MaxCodeSize:
+		return common.Address{}, core.GenesisAccount{}, fmt.Errorf("code of %s is %d bytes, more than the limit of %d",
+			address.Hex(), len(code), params.MaxCodeSize)
+	case code[0] == 0xEF:
+		// EIP-3541
+		return common.Address{}, core.GenesisAccount{}, fmt.Errorf("code of %s starts with the reserved 0xEF byte", address.Hex())
+	}
+
+	storage := map[common.Hash]common.Hash{}
+	for k, v := range p.Storage {
+		slot, err := parseStorageWord(k)
+		if err != nil {
+			return common.Address{}, core.GenesisAccount{}, fmt.Errorf("invalid storage slot %q of %s: %w", k, address.Hex(), err)
+		}
+		value, err := parseStorageWord(v)
+		if err != nil {
+			return common.Address{}, core.GenesisAccount{}, fmt.Errorf("invalid storage value %q of %s: %w", v, address.Hex(), err)
+		}
+		storage[slot] = value
+	}
+
+	balance := new(big.Int)
+	if p.Balance != "" {
+		balance, err = hexutil.DecodeBig(p.Balance)
+		if err != nil {
+			return common.Address{}, core.GenesisAccount{}, fmt.Errorf("invalid balance of %s: %w", address.Hex(), err)
+		}
+	}
+
+	account := core.GenesisAccount{
+		Code:    code,
+		Balance: balance,
+	}
+	if len(storage) > 0 {
+		account.Storage = storage
+	}
+	return address, account, nil
+}
+
+// parseStorageWord decodes a hex encoded storage slot or value of at most 32
+// bytes, left padding it with zeros
+func parseStorageWord(word string) (common.Hash, error) {
+	if !strings.HasPrefix(word, "0x") && !strings.HasPrefix(word, "0X") {
+		return common.Hash{}, fmt.Errorf("missing 0x prefix")
+	}
+	digits := word[2:]
+	if len(digits) == 0 || len(digits) > 2*common.HashLength {
+		return common.Hash{}, fmt.Errorf("must be 1 to %d hex digits", 2*common.HashLength)
+	}
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	b, err := hexutil.Decode("0x" + digits)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(b), nil
+}
+
+func getPredeploys(allocation core.GenesisAlloc, app *application.Avalanche) (core.GenesisAlloc, stateDirection, error) {
+	noPredeploys := ux.Msg(ux.MsgNoPredeploys)
+	fromFile := ux.Msg(ux.MsgPredeploysFromFile)
+	goBackMsg := ux.Msg(ux.MsgGoBack)
+
+	choice, err := app.Prompt.CaptureList(
+		ux.Msg(ux.MsgPredeploysPrompt),
+		[]string{noPredeploys, fromFile, goBackMsg},
+	)
+	if err != nil {
+		return allocation, stop, err
+	}
+	switch choice {
+	case noPredeploys:
+		return allocation, forward, nil
+	case goBackMsg:
+		return allocation, backward, nil
+	}
+
+	path, err := app.Prompt.CaptureExistingFilepath(ux.Msg(ux.MsgPredeploysFilePath))
+	if err != nil {
+		return allocation, stop, err
+	}
+	predeploys, err := LoadPredeploys(path)
+	if err != nil {
+		return allocation, stop, err
+	}
+	merged, err := addPredeploys(allocation, predeploys)
+	if err != nil {
+		return allocation, stop, err
+	}
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgPredeploysAdded), len(predeploys))
+	return merged, forward, nil
+}
+
+// addPredeploys returns a copy of the allocation holding the predeploys too.
+// Predeploys can't be set on addresses which already have an airdrop.
+func addPredeploys(allocation, predeploys core.GenesisAlloc) (core.GenesisAlloc, error) {
+	merged := core.GenesisAlloc{}
+	for address, account := range allocation {
+		merged[address] = account
+	}
+	for address, account := range predeploys {
+		if _, ok := merged[address]; ok {
+			return nil, fmt.Errorf("predeploy address %s already has an airdrop", address.Hex())
+		}
+		merged[address] = account
+	}
+	return merged, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPredeploys(t *testing.T) {
+	assert := assert.New(t)
+
+	multicall := common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+	weth := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name          string
+		content       string
+		expected      core.GenesisAlloc
+		errorContains string
+	}{
+		{
+			name: "valid",
+			content: `[
+				{"address": "0xcA11bde05977b3631167028862bE2a173976CA11", "code": "0x6080"},
+				{"address": "0x1000000000000000000000000000000000000001", "code": "0x60016000",
+				 "storage": {"0x0": "0x1", "0x02": "0x` + strings.Repeat("ff", 32) + `"}, "balance": "0x64"}
+			]`,
+			expected: core.GenesisAlloc{
+				multicall: {Code: []byte{0x60, 0x80}, Balance: new(big.Int)},
+				weth: {
+					Code: []byte{0x60, 0x01, 0x60, 0x00},
+					Storage: map[common.Hash]common.Hash{
+						common.BigToHash(big.NewInt(0)): common.BigToHash(big.NewInt(1)),
+						common.BigToHash(big.NewInt(2)): common.HexToHash("0x" + strings.Repeat("ff", 32)),
+					},
+					Balance: big.NewInt(100),
+				},
+			},
+		},
+		{
+			name:          "not json",
+			content:       "0xcA11bde05977b3631167028862bE2a173976CA11",
+			errorContains: "invalid predeploys file",
+		},
+		{
+			name:          "invalid address",
+			content:       `[{"address": "0x1234", "code": "0x6080"}]`,
+			errorContains: "invalid address",
+		},
+		{
+			name:          "precompile address",
+			content:       `[{"address": "0x0200000000000000000000000000000000000000", "code": "0x6080"}]`,
+			errorContains: "reserved for precompiles",
+		},
+		{
+			name:          "invalid code",
+			content:       `[{"address": "0x1000000000000000000000000000000000000001", "code": "0x60zz"}]`,
+			errorContains: "invalid code",
+		},
+		{
+			name:          "empty code",
+			content:       `[{"address": "0x1000000000000000000000000000000000000001", "code": "0x"}]`,
+			errorContains: "empty code",
+		},
+		{
+			name:          "reserved first byte",
+			content:       `[{"address": "0x1000000000000000000000000000000000000001", "code": "0xef00"}]`,
+			errorContains: "0xEF",
+		},
+		{
+			name:          "code too large",
+			content:       `[{"address": "0x1000000000000000000000000000000000000001", "code": "0x` + strings.Repeat("00", 24577) + `"}]`,
+			errorContains: "more than the limit",
+		},
+		{
+			name:          "storage slot too long",
+			content:       `[{"address": "0x1000000000000000000000000000000000000001", "code": "0x60", "storage": {"0x` + strings.Repeat("01", 33) + `": "0x1"}}]`,
+			errorContains: "invalid storage slot",
+		},
+		{
+			name:          "storage value not hex",
+			content:       `[{"address": "0x1000000000000000000000000000000000000001", "code": "0x60", "storage": {"0x1": "1"}}]`,
+			errorContains: "invalid storage value",
+		},
+		{
+			name: "duplicate",
+			content: `[{"address": "0x1000000000000000000000000000000000000001", "code": "0x60"},
+				{"address": "0x1000000000000000000000000000000000000001", "code": "0x61"}]`,
+			errorContains: "listed twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "predeploys.json")
+			assert.NoError(os.WriteFile(path, []byte(tt.content), 0o600))
+			alloc, err := LoadPredeploys(path)
+			if tt.errorContains != "" {
+				assert.ErrorContains(err, tt.errorContains)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.expected, alloc)
+		})
+	}
+}
+
+func TestAddPredeploys(t *testing.T) {
+	assert := assert.New(t)
+
+	airdrop := core.GenesisAlloc{PrefundedEwoqAddress: {Balance: big.NewInt(1)}}
+	predeploys := core.GenesisAlloc{common.HexToAddress("0x01"): {Code: []byte{0x60}}}
+
+	merged, err := addPredeploys(airdrop, predeploys)
+	assert.NoError(err)
+	assert.Len(merged, 2)
+	// the airdrop is left untouched, to go back in the wizard
+	assert.Len(airdrop, 1)
+
+	_, err = addPredeploys(airdrop, core.GenesisAlloc{PrefundedEwoqAddress: {Code: []byte{0x60}}})
+	assert.ErrorContains(err, "already has an airdrop")
+}
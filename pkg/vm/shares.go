@@ -0,0 +1,191 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// allocation shares are kept in basis points, so that percentages with up to
+// two decimals are split exactly
+const fullShare = 10000
+
+// AllocationShare is the part of an allocation going to an address, in basis
+// points of the total
+type AllocationShare struct {
+	Address     common.Address
+	BasisPoints uint64
+}
+
+// ParsePercentage parses a percentage with up to two decimals, with or
+// without a trailing %, e.g. "12.5%", and returns it in basis points
+func ParsePercentage(s string) (uint64, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+	parts := strings.SplitN(s, ".", 2)
+	whole, frac := parts[0], ""
+	if len(parts) == 2 {
+		frac = parts[1]
+	}
+	if whole == "" || (len(parts) == 2 && (frac == "" || len(frac) > 2)) {
+		return 0, fmt.Errorf("invalid percentage %q, expected a number with up to two decimals", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	wholeValue, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q: %w", s, err)
+	}
+	fracValue, err := strconv.ParseUint(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q: %w", s, err)
+	}
+	if wholeValue > 100 || wholeValue*100+fracValue > fullShare {
+		return 0, fmt.Errorf("invalid percentage %q, it can't be more than 100", s)
+	}
+	return wholeValue*100 + fracValue, nil
+}
+
+// EqualShares splits the allocation equally among addrs
+func EqualShares(addrs []common.Address) []AllocationShare {
+	shares := make([]AllocationShare, len(addrs))
+	for i, addr := range addrs {
+		shares[i] = AllocationShare{Address: addr, BasisPoints: fullShare / uint64(len(addrs))}
+	}
+	if len(shares) > 0 {
+		shares[0].BasisPoints += fullShare % uint64(len(addrs))
+	}
+	return shares
+}
+
+// LoadAllocationShares reads the shares of an allocation from a file. The
+// file either holds a JSON object of addresses to percentages, e.g.
+//
+//	{"0x...": "60", "0x...": "40"}
+//
+// or one address per line, optionally followed by its percentage, where
+// empty lines and lines starting with # are ignored.
+// If no address has a percentage, the allocation is split equally.
+func LoadAllocationShares(path string) ([]AllocationShare, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading allocation shares %s: %w", path, err)
+	}
+
+	type entry struct {
+		address    string
+		percentage string
+	}
+	var entries []entry
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		var object map[string]json.Number
+		if err := json.Unmarshal(trimmed, &object); err != nil {
+			return nil, fmt.Errorf("failed parsing allocation shares %s as JSON object: %w", path, err)
+		}
+		for address, percentage := range object {
+			entries = append(entries, entry{address: address, percentage: percentage.String()})
+		}
+		// keep the rounding deterministic regardless of the map order
+		sort.Slice(entries, func(i, j int) bool { return entries[i].address < entries[j].address })
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+			if len(fields) > 2 {
+				return nil, fmt.Errorf("invalid line %q in %s, expected an address and an optional percentage", line, path)
+			}
+			e := entry{address: fields[0]}
+			if len(fields) == 2 {
+				e.percentage = fields[1]
+			}
+			entries = append(entries, e)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed reading allocation shares %s: %w", path, err)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no addresses in %s", path)
+	}
+
+	withPercentage := 0
+	addrs := make([]common.Address, len(entries))
+	for i, e := range entries {
+		if !common.IsHexAddress(e.address) {
+			return nil, fmt.Errorf("invalid address %q in %s", e.address, path)
+		}
+		if err := CheckAddressChecksum(e.address); err != nil {
+			return nil, fmt.Errorf("invalid address in %s: %w", path, err)
+		}
+		addrs[i] = common.HexToAddress(e.address)
+		if e.percentage != "" {
+			withPercentage++
+		}
+	}
+	switch withPercentage {
+	case 0:
+		return EqualShares(addrs), nil
+	case len(entries):
+	default:
+		return nil, fmt.Errorf("either all or none of the addresses in %s must have a percentage", path)
+	}
+
+	shares := make([]AllocationShare, len(entries))
+	for i, e := range entries {
+		basisPoints, err := ParsePercentage(e.percentage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid share of %s in %s: %w", e.address, path, err)
+		}
+		shares[i] = AllocationShare{Address: addrs[i], BasisPoints: basisPoints}
+	}
+	return shares, nil
+}
+
+// SplitAllocation splits total among the shares, which must add up to 100%.
+// The remainder of the rounding goes to the first share.
+func SplitAllocation(total *big.Int, shares []AllocationShare) (core.GenesisAlloc, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no addresses to allocate to")
+	}
+	sum := uint64(0)
+	for _, share := range shares {
+		sum += share.BasisPoints
+	}
+	if sum != fullShare {
+		return nil, fmt.Errorf("the shares add up to %s%%, they must add up to 100%%", formatBasisPoints(sum))
+	}
+
+	allocation := core.GenesisAlloc{}
+	allocated := new(big.Int)
+	for _, share := range shares {
+		if _, ok := allocation[share.Address]; ok {
+			return nil, fmt.Errorf("address %s is listed twice", share.Address.Hex())
+		}
+		amount := new(big.Int).Mul(total, new(big.Int).SetUint64(share.BasisPoints))
+		amount.Div(amount, big.NewInt(fullShare))
+		allocation[share.Address] = core.GenesisAccount{Balance: amount}
+		allocated.Add(allocated, amount)
+	}
+	first := allocation[shares[0].Address]
+	first.Balance.Add(first.Balance, new(big.Int).Sub(total, allocated))
+	return allocation, nil
+}
+
+func formatBasisPoints(basisPoints uint64) string {
+	return strconv.FormatFloat(float64(basisPoints)/100, 'f', -1, 64)
+}
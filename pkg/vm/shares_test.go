@@ -0,0 +1,133 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePercentage(t *testing.T) {
+	assert := assert.New(t)
+
+	for input, expected := range map[string]uint64{
+		"100":    10000,
+		"12.5%":  1250,
+		"0.01":   1,
+		" 33.33": 3333,
+		"0":      0,
+	} {
+		basisPoints, err := ParsePercentage(input)
+		assert.NoError(err, input)
+		assert.Equal(expected, basisPoints, input)
+	}
+	for _, input := range []string{"", "abc", "12.345", "100.01", "101", "-5", "5.", ".5"} {
+		_, err := ParsePercentage(input)
+		assert.Error(err, input)
+	}
+}
+
+func TestLoadAllocationShares(t *testing.T) {
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	addr3 := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	type test struct {
+		name        string
+		content     string
+		expected    []AllocationShare
+		expectedErr bool
+	}
+	tests := []test{
+		{
+			name:    "lines with percentages",
+			content: "# validators\n0x1111111111111111111111111111111111111111 60%\n\n0x2222222222222222222222222222222222222222, 40\n",
+			expected: []AllocationShare{
+				{Address: addr1, BasisPoints: 6000},
+				{Address: addr2, BasisPoints: 4000},
+			},
+		},
+		{
+			name:    "lines without percentages",
+			content: "0x1111111111111111111111111111111111111111\n0x2222222222222222222222222222222222222222\n0x3333333333333333333333333333333333333333\n",
+			expected: []AllocationShare{
+				{Address: addr1, BasisPoints: 3334},
+				{Address: addr2, BasisPoints: 3333},
+				{Address: addr3, BasisPoints: 3333},
+			},
+		},
+		{
+			name:    "json",
+			content: `{"0x2222222222222222222222222222222222222222": "25.5", "0x1111111111111111111111111111111111111111": 74.5}`,
+			expected: []AllocationShare{
+				{Address: addr1, BasisPoints: 7450},
+				{Address: addr2, BasisPoints: 2550},
+			},
+		},
+		{
+			name:        "some percentages missing",
+			content:     "0x1111111111111111111111111111111111111111 60\n0x2222222222222222222222222222222222222222\n",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid address",
+			content:     "not-an-address 100\n",
+			expectedErr: true,
+		},
+		{
+			name:        "empty",
+			content:     "# nothing\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			path := filepath.Join(t.TempDir(), "shares")
+			err := os.WriteFile(path, []byte(tt.content), 0o600)
+			assert.NoError(err)
+
+			shares, err := LoadAllocationShares(path)
+			if tt.expectedErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.expected, shares)
+		})
+	}
+}
+
+func TestSplitAllocation(t *testing.T) {
+	assert := assert.New(t)
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	addr3 := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	shares := EqualShares([]common.Address{addr1, addr2, addr3})
+	allocation, err := SplitAllocation(big.NewInt(1000), shares)
+	assert.NoError(err)
+	// the rounding remainder goes to the first address
+	assert.Equal(big.NewInt(334), allocation[addr1].Balance)
+	assert.Equal(big.NewInt(333), allocation[addr2].Balance)
+	assert.Equal(big.NewInt(333), allocation[addr3].Balance)
+
+	_, err = SplitAllocation(big.NewInt(1000), []AllocationShare{
+		{Address: addr1, BasisPoints: 5000},
+		{Address: addr2, BasisPoints: 4000},
+	})
+	assert.ErrorContains(err, "add up to 90%")
+
+	_, err = SplitAllocation(big.NewInt(1000), []AllocationShare{
+		{Address: addr1, BasisPoints: 5000},
+		{Address: addr1, BasisPoints: 5000},
+	})
+	assert.ErrorContains(err, "listed twice")
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core"
+)
+
+// the decimals of the AVAX units the amounts of the wizard are entered in,
+// see oneAvax
+const avaxUnitDecimals = 9
+
+// supplyMismatchFactor is how far off the allocations of a genesis must be
+// from the target supply to be taken for a mistyped amount, e.g. a digit
+// too many or too few
+var supplyMismatchFactor = big.NewInt(10)
+
+// ErrSupplyMismatch is returned when the allocations of a genesis total a
+// supply at least supplyMismatchFactor times over or under the target supply
+var ErrSupplyMismatch = errors.New("initial supply does not match the target supply")
+
+// TotalSupply returns the sum of the balances allocated by a genesis, its
+// initial supply
+func TotalSupply(alloc core.GenesisAlloc) *big.Int {
+	total := new(big.Int)
+	for _, account := range alloc {
+		if account.Balance != nil {
+			total.Add(total, account.Balance)
+		}
+	}
+	return total
+}
+
+// CheckSupply returns an ErrSupplyMismatch if the supply total is off from
+// target by supplyMismatchFactor or more
+func CheckSupply(total, target *big.Int) error {
+	if target == nil || target.Sign() <= 0 {
+		return errors.New("the target supply must be positive")
+	}
+	switch {
+	case total.Cmp(new(big.Int).Mul(target, supplyMismatchFactor)) >= 0:
+		return fmt.Errorf("%w: the allocations total %s AVAX units, %s times the target of %s",
+			ErrSupplyMismatch, formatAvaxUnits(total), supplyRatio(total, target), formatAvaxUnits(target))
+	case new(big.Int).Mul(total, supplyMismatchFactor).Cmp(target) <= 0:
+		return fmt.Errorf("%w: the allocations total %s AVAX units, %s of the target of %s",
+			ErrSupplyMismatch, formatAvaxUnits(total), supplyRatio(total, target), formatAvaxUnits(target))
+	}
+	return nil
+}
+
+func formatAvaxUnits(amount *big.Int) string {
+	return ux.FormatAmount(amount, avaxUnitDecimals)
+}
+
+// supplyRatio formats total/target, with 4 significant digits at most
+func supplyRatio(total, target *big.Int) string {
+	ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(total), new(big.Float).SetInt(target)).Float64()
+	return fmt.Sprintf("%.4g", ratio)
+}
+
+// getSupply shows the initial supply allocated by the genesis and, when the
+// user gives the supply they expect, blocks allocations off by an order of
+// magnitude or more
+func getSupply(allocation core.GenesisAlloc, app *application.Avalanche) (stateDirection, error) {
+	total := TotalSupply(allocation)
+	ux.Logger.PrintToUser(ux.Msg(ux.MsgInitialSupply), formatAvaxUnits(total), len(allocation))
+
+	checkTarget := ux.Msg(ux.MsgCheckTargetSupply)
+	skipTarget := ux.Msg(ux.MsgSkipTargetSupply)
+	goBackMsg := ux.Msg(ux.MsgGoBack)
+	for {
+		choice, err := app.Prompt.CaptureList(
+			ux.Msg(ux.MsgTargetSupplyPrompt),
+			[]string{checkTarget, skipTarget, goBackMsg},
+		)
+		if err != nil {
+			return stop, err
+		}
+		switch choice {
+		case skipTarget:
+			return forward, nil
+		case goBackMsg:
+			return backward, nil
+		}
+
+		target, err := app.Prompt.CapturePositiveBigInt(ux.Msg(ux.MsgTargetSupply))
+		if err != nil {
+			return stop, err
+		}
+		target = target.Mul(target, oneAvax)
+		if err := CheckSupply(total, target); err != nil {
+			if !errors.Is(err, ErrSupplyMismatch) {
+				return stop, err
+			}
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgSupplyMismatch), err)
+			continue
+		}
+		if total.Cmp(target) != 0 {
+			diff := new(big.Int).Sub(total, target)
+			ux.Logger.PrintToUser(ux.Msg(ux.MsgSupplyDiffers), formatAvaxUnits(diff), formatAvaxUnits(target))
+		}
+		return forward, nil
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotalSupply(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0", TotalSupply(core.GenesisAlloc{}).String())
+	alloc := core.GenesisAlloc{
+		common.HexToAddress("0x1"): {Balance: big.NewInt(1000)},
+		common.HexToAddress("0x2"): {Balance: big.NewInt(234)},
+		// a predeploy without balance
+		common.HexToAddress("0x3"): {Code: []byte{0x60}},
+	}
+	assert.Equal("1234", TotalSupply(alloc).String())
+}
+
+func TestCheckSupply(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens := func(n int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(n), oneAvax)
+	}
+	tests := []struct {
+		total    *big.Int
+		target   *big.Int
+		mismatch bool
+	}{
+		{total: tokens(1_000_000), target: tokens(1_000_000)},
+		{total: tokens(1_200_000), target: tokens(1_000_000)},
+		{total: tokens(9_999_999), target: tokens(1_000_000)},
+		{total: tokens(10_000_000), target: tokens(1_000_000), mismatch: true},
+		{total: tokens(100_001), target: tokens(1_000_000)},
+		{total: tokens(100_000), target: tokens(1_000_000), mismatch: true},
+		{total: tokens(0), target: tokens(1_000_000), mismatch: true},
+	}
+	for _, tt := range tests {
+		err := CheckSupply(tt.total, tt.target)
+		if tt.mismatch {
+			assert.ErrorIs(err, ErrSupplyMismatch, tt.total.String())
+			continue
+		}
+		assert.NoError(err, tt.total.String())
+	}
+
+	err := CheckSupply(tokens(10_000_000), tokens(1_000_000))
+	assert.ErrorContains(err, "10,000,000 AVAX units, 10 times the target of 1,000,000")
+	err = CheckSupply(tokens(100_000), tokens(1_000_000))
+	assert.ErrorContains(err, "0.1 of the target")
+	assert.Error(CheckSupply(tokens(1), big.NewInt(0)))
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// timelockCode is the runtime bytecode of a contract holding its balance until
+// an unlock time. Any call made once the unlock time is reached sends the whole
+// balance to the beneficiary; any call made before reverts.
+// The beneficiary is read from storage slot 0, the unlock time, as a unix
+// timestamp, from storage slot 1.
+//
+//	00 PUSH1 0x01    SLOAD                  unlock time
+//	03 TIMESTAMP     LT                     now < unlock time
+//	05 PUSH1 0x18    JUMPI                  still locked: revert
+//	08 PUSH1 0x00    DUP1 DUP1 DUP1         out and in memory areas, all empty
+//	0d SELFBALANCE                          value
+//	0e PUSH1 0x00    SLOAD                  beneficiary
+//	11 GAS           CALL
+//	13 ISZERO        PUSH1 0x18 JUMPI       transfer failed: revert
+//	17 STOP
+//	18 JUMPDEST      PUSH1 0x00 DUP1 REVERT
+var timelockCode = hexutil.MustDecode("0x60015442106018576000808080476000545af115601857005b600080fd")
+
+var (
+	timelockBeneficiarySlot = common.BigToHash(big.NewInt(0))
+	timelockUnlockTimeSlot  = common.BigToHash(big.NewInt(1))
+)
+
+// NewTimelockAccount returns a genesis account locking amount for beneficiary
+// until unlockTime, and the address to allocate it to. The address is derived
+// from the beneficiary and the unlock time, no key controls it.
+func NewTimelockAccount(beneficiary common.Address, amount *big.Int, unlockTime time.Time) (common.Address, core.GenesisAccount) {
+	unlock := common.BigToHash(big.NewInt(unlockTime.Unix()))
+	address := common.BytesToAddress(crypto.Keccak256(beneficiary.Bytes(), unlock.Bytes())[12:])
+	return address, core.GenesisAccount{
+		Code: timelockCode,
+		Storage: map[common.Hash]common.Hash{
+			timelockBeneficiarySlot: common.BytesToHash(beneficiary.Bytes()),
+			timelockUnlockTimeSlot:  unlock,
+		},
+		Balance: amount,
+	}
+}
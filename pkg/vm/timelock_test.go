@@ -0,0 +1,58 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/rawdb"
+	"github.com/ava-labs/subnet-evm/core/state"
+	"github.com/ava-labs/subnet-evm/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelockAccount(t *testing.T) {
+	assert := assert.New(t)
+
+	beneficiary := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	amount := big.NewInt(1_000_000)
+	unlockTime := time.Unix(1_700_000_000, 0)
+
+	address, account := NewTimelockAccount(beneficiary, amount, unlockTime)
+	otherAddress, _ := NewTimelockAccount(beneficiary, amount, unlockTime.Add(time.Second))
+	assert.NotEqual(address, otherAddress)
+
+	newState := func() *state.StateDB {
+		statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		assert.NoError(err)
+		statedb.SetCode(address, account.Code)
+		for k, v := range account.Storage {
+			statedb.SetState(address, k, v)
+		}
+		statedb.SetBalance(address, account.Balance)
+		return statedb
+	}
+
+	// before the unlock time, calls revert and the funds stay locked
+	statedb := newState()
+	_, _, err := runtime.Call(address, nil, &runtime.Config{
+		State: statedb,
+		Time:  big.NewInt(unlockTime.Unix() - 1),
+	})
+	assert.Error(err)
+	assert.Equal(amount, statedb.GetBalance(address))
+	assert.Equal(0, statedb.GetBalance(beneficiary).Sign())
+
+	// from the unlock time on, any call releases the funds to the beneficiary
+	statedb = newState()
+	_, _, err = runtime.Call(address, nil, &runtime.Config{
+		State: statedb,
+		Time:  big.NewInt(unlockTime.Unix()),
+	})
+	assert.NoError(err)
+	assert.Equal(0, statedb.GetBalance(address).Sign())
+	assert.Equal(amount, statedb.GetBalance(beneficiary))
+}
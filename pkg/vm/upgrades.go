@@ -0,0 +1,148 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/coreos/go-semver/semver"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+)
+
+// stateful precompiles of subnet-evm, named after their config in the chain
+// config
+const (
+	ContractDeployerAllowList = "contractDeployerAllowList"
+	ContractNativeMinter      = "contractNativeMinter"
+	TxAllowList               = "txAllowList"
+)
+
+var (
+	Precompiles = []string{ContractDeployerAllowList, ContractNativeMinter, TxAllowList}
+
+	errUpgradeInPast = errors.New("the activation time of an upgrade must be in the future")
+)
+
+// precompilesEnabledAt returns which precompiles are enabled once the
+// genesis and the upgrades activated before t are applied
+func precompilesEnabledAt(genesis core.Genesis, upgrades []models.NetworkUpgrade, t time.Time) map[string]bool {
+	enabled := map[string]bool{}
+	if genesis.Config != nil {
+		enabled[ContractDeployerAllowList] = genesis.Config.ContractDeployerAllowListConfig.BlockTimestamp != nil
+		enabled[ContractNativeMinter] = genesis.Config.ContractNativeMinterConfig.BlockTimestamp != nil
+		enabled[TxAllowList] = genesis.Config.TxAllowListConfig.BlockTimestamp != nil
+	}
+	for _, u := range upgrades {
+		if !u.ActivationTime().Before(t) {
+			break
+		}
+		enabled[u.Precompile] = !u.Disable
+	}
+	return enabled
+}
+
+// ScheduleUpgrade adds upgrade to the upgrades of the chain of sc, once
+// checked it applies to the precompiles enabled at that time
+func ScheduleUpgrade(sc *models.Sidecar, genesis core.Genesis, upgrade models.NetworkUpgrade, now time.Time) error {
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("precompile upgrades are only supported by %s chains", models.SubnetEvm)
+	}
+	known := false
+	for _, p := range Precompiles {
+		known = known || p == upgrade.Precompile
+	}
+	if !known {
+		return fmt.Errorf("unknown precompile %q, expected one of %s", upgrade.Precompile, strings.Join(Precompiles, ", "))
+	}
+	if !upgrade.ActivationTime().After(now) {
+		return errUpgradeInPast
+	}
+	for _, u := range sc.Upgrades {
+		if u.Timestamp == upgrade.Timestamp {
+			return fmt.Errorf("an upgrade of %s is already scheduled at %s", u.Precompile, u.ActivationTime().UTC())
+		}
+	}
+	for _, addr := range upgrade.AdminAddresses {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid admin address %q", addr)
+		}
+	}
+	if upgrade.Disable && len(upgrade.AdminAddresses) > 0 {
+		return errors.New("admin addresses only apply to enabling a precompile")
+	}
+
+	upgrades := append(append([]models.NetworkUpgrade{}, sc.Upgrades...), upgrade)
+	sort.SliceStable(upgrades, func(i, j int) bool { return upgrades[i].Timestamp < upgrades[j].Timestamp })
+	// each upgrade must change the state of its precompile, including the
+	// upgrades scheduled after the new one
+	for _, u := range upgrades {
+		wasEnabled := precompilesEnabledAt(genesis, upgrades, u.ActivationTime())[u.Precompile]
+		switch {
+		case u.Disable && !wasEnabled:
+			return fmt.Errorf("%s is not enabled at %s, it can't be disabled", u.Precompile, u.ActivationTime().UTC())
+		case !u.Disable && wasEnabled:
+			return fmt.Errorf("%s is already enabled at %s", u.Precompile, u.ActivationTime().UTC())
+		}
+	}
+	sc.Upgrades = upgrades
+	return nil
+}
+
+// SupportsUpgrades returns an error if the subnet-evm version can't honor
+// precompile upgrades
+func SupportsUpgrades(subnetEVMVersion string) error {
+	version, err := semver.NewVersion(strings.TrimPrefix(subnetEVMVersion, "v"))
+	if err != nil {
+		return fmt.Errorf("invalid subnet-evm version %q: %w", subnetEVMVersion, err)
+	}
+	minVersion := semver.New(strings.TrimPrefix(constants.PrecompileUpgradesSubnetEVMVersion, "v"))
+	if version.LessThan(*minVersion) {
+		return fmt.Errorf("subnet-evm %s is too old to honor precompile upgrades, they need %s or later",
+			subnetEVMVersion, constants.PrecompileUpgradesSubnetEVMVersion)
+	}
+	return nil
+}
+
+// PendingUpgrades returns the upgrades of sc not active yet at now
+func PendingUpgrades(sc models.Sidecar, now time.Time) []models.NetworkUpgrade {
+	pending := []models.NetworkUpgrade{}
+	for _, u := range sc.Upgrades {
+		if u.ActivationTime().After(now) {
+			pending = append(pending, u)
+		}
+	}
+	return pending
+}
+
+// PrintUpgrades writes a table of the upgrades of sc, and when they activate
+// relative to now
+func PrintUpgrades(w io.Writer, sc models.Sidecar, now time.Time) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Precompile", "Upgrade", "Activation Time", "Status"})
+	table.SetRowLine(true)
+	for _, u := range sc.Upgrades {
+		action := "enable"
+		if u.Disable {
+			action = "disable"
+		}
+		if len(u.AdminAddresses) > 0 {
+			action += " (admins " + strings.Join(u.AdminAddresses, ", ") + ")"
+		}
+		status := "active"
+		if activation := u.ActivationTime(); activation.After(now) {
+			status = "in " + activation.Sub(now).Round(time.Minute).String()
+		}
+		table.Append([]string{u.Precompile, action, u.ActivationTime().UTC().Format(time.RFC3339), status})
+	}
+	table.Render()
+}
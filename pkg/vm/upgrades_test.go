@@ -0,0 +1,66 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleUpgrade(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1_000_000, 0)
+	genesis := core.Genesis{Config: &params.ChainConfig{
+		TxAllowListConfig: precompile.TxAllowListConfig{
+			AllowListConfig: precompile.AllowListConfig{BlockTimestamp: big.NewInt(0)},
+		},
+	}}
+	sc := models.Sidecar{VM: models.SubnetEvm}
+	at := func(d time.Duration) int64 { return now.Add(d).Unix() }
+
+	// disable the tx allow list enabled in genesis
+	assert.NoError(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: TxAllowList, Timestamp: at(2 * time.Hour), Disable: true}, now))
+	// it can't be disabled twice
+	assert.Error(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: TxAllowList, Timestamp: at(3 * time.Hour), Disable: true}, now))
+	// it is still enabled before
+	assert.Error(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: TxAllowList, Timestamp: at(time.Hour)}, now))
+	// re-enabling it later works
+	assert.NoError(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{
+		Precompile:     TxAllowList,
+		Timestamp:      at(3 * time.Hour),
+		AdminAddresses: []string{"0x1111111111111111111111111111111111111111"},
+	}, now))
+	// enabling a precompile not in genesis, scheduled before the others
+	assert.NoError(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: ContractNativeMinter, Timestamp: at(time.Hour)}, now))
+	assert.Equal([]int64{at(time.Hour), at(2 * time.Hour), at(3 * time.Hour)}, []int64{sc.Upgrades[0].Timestamp, sc.Upgrades[1].Timestamp, sc.Upgrades[2].Timestamp})
+
+	assert.ErrorIs(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: ContractDeployerAllowList, Timestamp: at(-time.Hour)}, now), errUpgradeInPast)
+	assert.Error(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: "unknown", Timestamp: at(4 * time.Hour)}, now))
+	assert.Error(ScheduleUpgrade(&sc, genesis, models.NetworkUpgrade{Precompile: ContractDeployerAllowList, Timestamp: at(4 * time.Hour), AdminAddresses: []string{"nope"}}, now))
+	assert.Error(ScheduleUpgrade(&models.Sidecar{VM: models.CustomVM}, genesis, models.NetworkUpgrade{Precompile: TxAllowList, Timestamp: at(time.Hour)}, now))
+	assert.Len(sc.Upgrades, 3)
+
+	assert.Len(PendingUpgrades(sc, now.Add(90*time.Minute)), 2)
+
+	var out bytes.Buffer
+	PrintUpgrades(&out, sc, now.Add(90*time.Minute))
+	assert.Contains(out.String(), "active")
+	assert.Contains(out.String(), "in 30m0s")
+}
+
+func TestSupportsUpgrades(t *testing.T) {
+	assert := assert.New(t)
+	assert.Error(SupportsUpgrades("v0.2.3"))
+	assert.NoError(SupportsUpgrades("v0.2.8"))
+	assert.NoError(SupportsUpgrades("v0.3.0"))
+	assert.Error(SupportsUpgrades("latest"))
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// The types of the events posted to the webhooks
+const (
+	DeployStarted   = "deploy.started"
+	DeploySucceeded = "deploy.succeeded"
+	DeployFailed    = "deploy.failed"
+	ValidatorAdded  = "validator.added"
+	NetworkStopped  = "network.stopped"
+)
+
+const (
+	// EventHeader holds the type of the event posted
+	EventHeader = "X-Avalanche-Event"
+	// TimestampHeader holds the unix time the event was signed at
+	TimestampHeader = "X-Avalanche-Timestamp"
+	// SignatureHeader holds "sha256=" and the hex HMAC-SHA256, keyed with the
+	// secret of the webhook, of the timestamp, a '.' and the body
+	SignatureHeader = "X-Avalanche-Signature"
+)
+
+// Event is the JSON body posted to the webhooks
+type Event struct {
+	Type          string            `json:"type"`
+	Time          time.Time         `json:"time"`
+	Subnet        string            `json:"subnet,omitempty"`
+	Network       string            `json:"network,omitempty"`
+	Details       map[string]string `json:"details,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	CorrelationID string            `json:"correlationID,omitempty"`
+	CLIVersion    string            `json:"cliVersion,omitempty"`
+}
+
+// EventTypes returns the types of the events posted to the webhooks
+func EventTypes() []string {
+	return []string{DeployStarted, DeploySucceeded, DeployFailed, ValidatorAdded, NetworkStopped}
+}
+
+// Sign returns the value of the SignatureHeader of body, signed at timestamp
+// with secret
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify posts event to the webhooks of the config file subscribed to its
+// type. A webhook failing is only warned about: the events are notifications,
+// which must not fail the command they come from.
+func Notify(app *application.Avalanche, event Event) {
+	hooks, err := app.Conf.Webhooks()
+	if err != nil {
+		ux.Logger.PrintToUser("Warning: not posting the %s event: %s", event.Type, err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	event.CorrelationID = app.GetCorrelationID()
+	event.CLIVersion = app.GetVersion()
+	body, err := json.Marshal(event)
+	if err != nil {
+		app.Log.Warn("failed marshaling the %s event: %s", event.Type, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.WebhookTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, hook := range hooks {
+		if !subscribed(hook, event.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(hook config.Webhook) {
+			defer wg.Done()
+			if err := post(ctx, hook, event.Type, body); err != nil {
+				ux.Logger.PrintToUser("Warning: failed posting the %s event to webhook %s: %s", event.Type, hook.URL, err)
+				return
+			}
+			app.Log.Debug("posted the %s event to webhook %s", event.Type, hook.URL)
+		}(hook)
+	}
+	wg.Wait()
+}
+
+func subscribed(hook config.Webhook, eventType string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, t := range hook.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func post(ctx context.Context, hook config.Webhook, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
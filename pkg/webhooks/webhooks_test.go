@@ -0,0 +1,101 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type received struct {
+	headers http.Header
+	body    []byte
+}
+
+func newReceiver(status int) (*httptest.Server, func() []received) {
+	var (
+		lock sync.Mutex
+		got  []received
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		got = append(got, received{headers: r.Header, body: body})
+		lock.Unlock()
+		w.WriteHeader(status)
+	}))
+	return server, func() []received {
+		lock.Lock()
+		defer lock.Unlock()
+		return got
+	}
+}
+
+func TestNotify(t *testing.T) {
+	assert := assert.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	all, allReceived := newReceiver(http.StatusOK)
+	defer all.Close()
+	failures, failuresReceived := newReceiver(http.StatusNoContent)
+	defer failures.Close()
+	broken, brokenReceived := newReceiver(http.StatusInternalServerError)
+	defer broken.Close()
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("webhooks", []interface{}{
+		map[string]interface{}{"url": all.URL, "secret": "s3cret"},
+		map[string]interface{}{"url": failures.URL, "secret": "0ther", "events": []interface{}{DeployFailed}},
+		map[string]interface{}{"url": broken.URL, "secret": "s3cret"},
+	})
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil)
+	app.SetCorrelationID("abc123")
+
+	Notify(app, Event{Type: DeployStarted, Subnet: "mysubnet", Network: "Fuji"})
+	Notify(app, Event{Type: DeployFailed, Subnet: "mysubnet", Network: "Fuji", Error: "out of funds"})
+
+	got := allReceived()
+	assert.Len(got, 2)
+	assert.Len(brokenReceived(), 2)
+	assert.Len(failuresReceived(), 1)
+
+	started := got[0]
+	assert.Equal(DeployStarted, started.headers.Get(EventHeader))
+	assert.Equal("application/json", started.headers.Get("Content-Type"))
+	assert.Equal(Sign("s3cret", started.headers.Get(TimestampHeader), started.body), started.headers.Get(SignatureHeader))
+	var event Event
+	assert.NoError(json.Unmarshal(started.body, &event))
+	assert.Equal(DeployStarted, event.Type)
+	assert.Equal("mysubnet", event.Subnet)
+	assert.Equal("Fuji", event.Network)
+	assert.Equal("abc123", event.CorrelationID)
+	assert.False(event.Time.IsZero())
+
+	failed := failuresReceived()[0]
+	assert.Equal(Sign("0ther", failed.headers.Get(TimestampHeader), failed.body), failed.headers.Get(SignatureHeader))
+	assert.NoError(json.Unmarshal(failed.body, &event))
+	assert.Equal("out of funds", event.Error)
+}
+
+func TestSign(t *testing.T) {
+	assert := assert.New(t)
+
+	signature := Sign("s3cret", "1660000000", []byte(`{"type":"deploy.started"}`))
+	assert.Regexp(`^sha256=[0-9a-f]{64}$`, signature)
+	assert.Equal(signature, Sign("s3cret", "1660000000", []byte(`{"type":"deploy.started"}`)))
+	assert.NotEqual(signature, Sign("0ther", "1660000000", []byte(`{"type":"deploy.started"}`)))
+	assert.NotEqual(signature, Sign("s3cret", "1660000001", []byte(`{"type":"deploy.started"}`)))
+}
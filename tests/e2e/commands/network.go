@@ -15,6 +15,7 @@ func CleanNetwork() {
 		CLIBinary,
 		NetworkCmd,
 		"clean",
+		"--yes",
 	)
 	_, err := cmd.Output()
 	gomega.Expect(err).Should(gomega.BeNil())
@@ -45,7 +45,7 @@ func DeleteSubnetConfig(subnetName string) {
 	gomega.Expect(exists).Should(gomega.BeTrue())
 
 	// Now delete config
-	cmd := exec.Command(CLIBinary, SubnetCmd, "delete", subnetName)
+	cmd := exec.Command(CLIBinary, SubnetCmd, "delete", subnetName, "--yes")
 	_, err = cmd.Output()
 	gomega.Expect(err).Should(gomega.BeNil())
 
@@ -4,13 +4,19 @@ import "@nomiclabs/hardhat-waffle"
 import "@typechain/hardhat"
 import { existsSync } from "fs"
 
-// Import the dynamic rpc url if the file exists
+// Import the dynamic rpc and websocket urls if the file exists
 let rpcUrl = ""
+let wsUrl = ""
 if (existsSync("./dynamic_rpc.json")) {
   const importedRpc = require("./dynamic_rpc.json")
   rpcUrl = importedRpc.rpc
+  wsUrl = importedRpc.ws
 }
 
+// websocket url of the subnet, for scripts needing subscriptions
+// "ws://{ip}:{port}/ext/bc/{chainID}/ws
+export const subnetWsUrl = wsUrl
+
 // You need to export an object to set up your config
 // Go to https://hardhat.org/config/ to learn more
 
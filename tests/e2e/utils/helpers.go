@@ -133,8 +133,12 @@ func ParseRPCsFromOutput(output string) ([]string, error) {
 		if startIndex == -1 {
 			return nil, errors.New("no url in RPC URL line")
 		}
-		endIndex := strings.LastIndex(line, "rpc")
-		rpc := line[startIndex : endIndex+3]
+		// the line may also list the WebSocket endpoint after the RPC one
+		endIndex := strings.Index(line[startIndex:], "/rpc")
+		if endIndex == -1 {
+			return nil, errors.New("no RPC path in RPC URL line")
+		}
+		rpc := line[startIndex : startIndex+endIndex+len("/rpc")]
 		rpcComponents := strings.Split(rpc, "/")
 		if len(rpcComponents) != expectedRPCComponentsLen {
 			return nil, fmt.Errorf("unexpected number of components in url %q: expected %d got %d",
@@ -176,11 +180,19 @@ func ParseGreeterAddress(output string) error {
 
 type rpcFile struct {
 	RPC string `json:"rpc"`
+	WS  string `json:"ws"`
 }
 
+// SetHardhatRPC writes the RPC URL, and the WebSocket URL of the same chain,
+// for the hardhat config to import
 func SetHardhatRPC(rpc string) error {
+	ws := strings.TrimSuffix(rpc, "/rpc") + "/ws"
+	if strings.HasPrefix(ws, "http") {
+		ws = "ws" + strings.TrimPrefix(ws, "http")
+	}
 	rpcFileData := rpcFile{
 		RPC: rpc,
+		WS:  ws,
 	}
 
 	file, err := json.MarshalIndent(rpcFileData, "", " ")